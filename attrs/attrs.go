@@ -0,0 +1,52 @@
+// Package attrs builds semantic convention attributes for the domains instrumented most often:
+// HTTP, databases, messaging, and RPC. Fill in a struct such as HTTPServer or DB and call
+// Attributes to get the attribute.KeyValue slice with the semconv keys and value types, instead of
+// spelling keys by hand. Zero-valued fields are left out.
+//
+//	span.SetAttributes(attrs.DB{System: attrs.DBSystemPostgreSQL, Operation: "SELECT", Collection: "users"}.Attributes()...)
+package attrs
+
+import (
+	"fmt"
+	"strconv"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.28.0"
+)
+
+// builder appends attributes, skipping empty values.
+type builder []attribute.KeyValue
+
+func (b *builder) str(key attribute.Key, value string) {
+	if value != "" {
+		*b = append(*b, key.String(value))
+	}
+}
+
+func (b *builder) int(key attribute.Key, value int) {
+	if value > 0 {
+		*b = append(*b, key.Int(value))
+	}
+}
+
+// server adds server.address and server.port.
+func (b *builder) server(address string, port int) {
+	b.str(semconv.ServerAddressKey, address)
+	b.int(semconv.ServerPortKey, port)
+}
+
+// errorType adds error.type from err, or from the status code when it counts as a failure.
+func (b *builder) errorType(err error, status int, failed bool) {
+	switch {
+	case err != nil:
+		*b = append(*b, ErrorType(err))
+	case failed:
+		*b = append(*b, semconv.ErrorTypeKey.String(strconv.Itoa(status)))
+	}
+}
+
+// ErrorType returns error.type for err: the Go type name of the error, as the instrumentations in
+// this module record it.
+func ErrorType(err error) attribute.KeyValue {
+	return semconv.ErrorTypeKey.String(fmt.Sprintf("%T", err))
+}
@@ -0,0 +1,115 @@
+package attrs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc/codes"
+)
+
+func attrMap(kvs []attribute.KeyValue) map[string]any {
+	out := make(map[string]any, len(kvs))
+	for _, kv := range kvs {
+		out[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	return out
+}
+
+func TestHTTPServerAttributes(t *testing.T) {
+	got := attrMap(HTTPServer{
+		Scheme:        "https",
+		Route:         "/users/{id}",
+		ServerAddress: "api.example.com",
+		ServerPort:    443,
+		StatusCode:    503,
+	}.Attributes())
+	want := map[string]any{
+		"http.request.method":       "GET",
+		"url.scheme":                "https",
+		"http.route":                "/users/{id}",
+		"server.address":            "api.example.com",
+		"server.port":               int64(443),
+		"http.response.status_code": int64(503),
+		"error.type":                "503",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestHTTPMethodNormalization(t *testing.T) {
+	got := attrMap(HTTPClient{Method: "PURGE", StatusCode: 200}.Attributes())
+	if got["http.request.method"] != "_OTHER" || got["http.request.method_original"] != "PURGE" {
+		t.Fatalf("expected an _OTHER method, got %v", got)
+	}
+	if _, ok := got["error.type"]; ok {
+		t.Fatalf("expected no error.type for a 200, got %v", got)
+	}
+
+	got = attrMap(HTTPClient{Method: "DELETE", StatusCode: 404}.Attributes())
+	if got["http.request.method"] != "DELETE" || got["error.type"] != "404" {
+		t.Fatalf("unexpected client attributes: %v", got)
+	}
+	if _, ok := got["http.request.method_original"]; ok {
+		t.Fatalf("expected no original method for a known one, got %v", got)
+	}
+}
+
+func TestDBAttributes(t *testing.T) {
+	got := attrMap(DB{
+		System:     DBSystemPostgreSQL,
+		Namespace:  "shop",
+		Collection: "orders",
+		Operation:  "INSERT",
+		BatchSize:  3,
+		Err:        errors.New("duplicate key"),
+	}.Attributes())
+	want := map[string]any{
+		"db.system":               "postgresql",
+		"db.namespace":            "shop",
+		"db.collection.name":      "orders",
+		"db.operation.name":       "INSERT",
+		"db.operation.batch.size": int64(3),
+		"error.type":              "*errors.errorString",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestMessagingAttributes(t *testing.T) {
+	got := attrMap(Messaging{
+		System:        MessagingSystemKafka,
+		Operation:     MessagingOperationProcess,
+		Destination:   "orders",
+		ConsumerGroup: "billing",
+		BodySize:      128,
+		BatchCount:    1,
+	}.Attributes())
+	want := map[string]any{
+		"messaging.system":              "kafka",
+		"messaging.operation.type":      "process",
+		"messaging.destination.name":    "orders",
+		"messaging.consumer.group.name": "billing",
+		"messaging.message.body.size":   int64(128),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestRPCAttributes(t *testing.T) {
+	kvs := append(RPC{System: RPCSystemGRPC, Service: "shop.v1.Orders", Method: "Place"}.Attributes(), GRPCStatus(codes.OK))
+	got := attrMap(kvs)
+	want := map[string]any{
+		"rpc.system":           "grpc",
+		"rpc.service":          "shop.v1.Orders",
+		"rpc.method":           "Place",
+		"rpc.grpc.status_code": int64(0),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
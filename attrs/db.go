@@ -0,0 +1,60 @@
+package attrs
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.28.0"
+)
+
+// DBSystem is a db.system value.
+type DBSystem string
+
+// Well-known db.system values.
+const (
+	DBSystemPostgreSQL    DBSystem = "postgresql"
+	DBSystemMySQL         DBSystem = "mysql"
+	DBSystemMariaDB       DBSystem = "mariadb"
+	DBSystemMSSQL         DBSystem = "mssql"
+	DBSystemSQLite        DBSystem = "sqlite"
+	DBSystemOracle        DBSystem = "oracle"
+	DBSystemRedis         DBSystem = "redis"
+	DBSystemMongoDB       DBSystem = "mongodb"
+	DBSystemCassandra     DBSystem = "cassandra"
+	DBSystemElasticsearch DBSystem = "elasticsearch"
+	DBSystemDynamoDB      DBSystem = "dynamodb"
+	DBSystemOtherSQL      DBSystem = "other_sql"
+)
+
+// DB describes a database client call.
+type DB struct {
+	System DBSystem
+	// Namespace is the database, or schema-qualified database, the call runs against.
+	Namespace string
+	// Collection is the table, collection, or key prefix the call targets.
+	Collection string
+	// Operation is the command, such as SELECT, findAndModify, or HGET.
+	Operation string
+	// QueryText is the statement; sanitize literal values out of it before passing it.
+	QueryText string
+	// BatchSize is the number of operations of a batch call, left out for single operations.
+	BatchSize     int
+	ServerAddress string
+	ServerPort    int
+	// Err is the error the call failed with, recorded as error.type.
+	Err error
+}
+
+// Attributes returns the semconv attributes of the call.
+func (d DB) Attributes() []attribute.KeyValue {
+	var b builder
+	b.str(semconv.DBSystemKey, string(d.System))
+	b.str(semconv.DBNamespaceKey, d.Namespace)
+	b.str(semconv.DBCollectionNameKey, d.Collection)
+	b.str(semconv.DBOperationNameKey, d.Operation)
+	b.str(semconv.DBQueryTextKey, d.QueryText)
+	if d.BatchSize > 1 {
+		b = append(b, semconv.DBOperationBatchSize(d.BatchSize))
+	}
+	b.server(d.ServerAddress, d.ServerPort)
+	b.errorType(d.Err, 0, false)
+	return b
+}
@@ -0,0 +1,84 @@
+package attrs
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.28.0"
+)
+
+// HTTPServer describes an incoming HTTP request and, once handled, its response.
+type HTTPServer struct {
+	// Method is normalized: methods outside RFC 9110 and PATCH are recorded as _OTHER with
+	// http.request.method_original. Empty means GET.
+	Method string
+	// Scheme is the URL scheme the request arrived on, http or https.
+	Scheme string
+	// Route is the matched route template, such as /users/{id}, never the raw path.
+	Route         string
+	Path          string
+	ServerAddress string
+	ServerPort    int
+	ClientAddress string
+	UserAgent     string
+	// StatusCode is the response status; 5xx responses also set error.type.
+	StatusCode int
+	// Err is the error the handler failed with, recorded as error.type.
+	Err error
+}
+
+// Attributes returns the semconv attributes of the request.
+func (h HTTPServer) Attributes() []attribute.KeyValue {
+	var b builder
+	b.method(h.Method)
+	b.str(semconv.URLSchemeKey, h.Scheme)
+	b.str(semconv.HTTPRouteKey, h.Route)
+	b.str(semconv.URLPathKey, h.Path)
+	b.server(h.ServerAddress, h.ServerPort)
+	b.str(semconv.ClientAddressKey, h.ClientAddress)
+	b.str(semconv.UserAgentOriginalKey, h.UserAgent)
+	b.int(semconv.HTTPResponseStatusCodeKey, h.StatusCode)
+	b.errorType(h.Err, h.StatusCode, h.StatusCode >= http.StatusInternalServerError)
+	return b
+}
+
+// HTTPClient describes an outgoing HTTP request and, once answered, its response.
+type HTTPClient struct {
+	// Method is normalized as in HTTPServer.
+	Method string
+	// URL is recorded as url.full; scrub credentials and sensitive query values before passing it.
+	URL           string
+	ServerAddress string
+	ServerPort    int
+	// ResendCount is the number of the retry or redirect this request is, zero for the first.
+	ResendCount int
+	// StatusCode is the response status; 4xx and 5xx responses also set error.type.
+	StatusCode int
+	// Err is the transport error, recorded as error.type.
+	Err error
+}
+
+// Attributes returns the semconv attributes of the request.
+func (h HTTPClient) Attributes() []attribute.KeyValue {
+	var b builder
+	b.method(h.Method)
+	b.str(semconv.URLFullKey, h.URL)
+	b.server(h.ServerAddress, h.ServerPort)
+	b.int(semconv.HTTPRequestResendCountKey, h.ResendCount)
+	b.int(semconv.HTTPResponseStatusCodeKey, h.StatusCode)
+	b.errorType(h.Err, h.StatusCode, h.StatusCode >= http.StatusBadRequest)
+	return b
+}
+
+// method adds http.request.method, and http.request.method_original for unknown methods.
+func (b *builder) method(method string) {
+	switch method {
+	case "":
+		*b = append(*b, semconv.HTTPRequestMethodGet)
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+		http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace:
+		*b = append(*b, semconv.HTTPRequestMethodKey.String(method))
+	default:
+		*b = append(*b, semconv.HTTPRequestMethodOther, semconv.HTTPRequestMethodOriginal(method))
+	}
+}
@@ -0,0 +1,69 @@
+package attrs
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.28.0"
+)
+
+// MessagingSystem is a messaging.system value.
+type MessagingSystem string
+
+// Well-known messaging.system values.
+const (
+	MessagingSystemKafka      MessagingSystem = "kafka"
+	MessagingSystemRabbitMQ   MessagingSystem = "rabbitmq"
+	MessagingSystemNATS       MessagingSystem = "nats"
+	MessagingSystemPulsar     MessagingSystem = "pulsar"
+	MessagingSystemAWSSQS     MessagingSystem = "aws_sqs"
+	MessagingSystemGCPPubsub  MessagingSystem = "gcp_pubsub"
+	MessagingSystemServicebus MessagingSystem = "servicebus"
+)
+
+// MessagingOperation is a messaging.operation.type value.
+type MessagingOperation string
+
+// messaging.operation.type values.
+const (
+	MessagingOperationCreate  MessagingOperation = "create"
+	MessagingOperationSend    MessagingOperation = "send"
+	MessagingOperationReceive MessagingOperation = "receive"
+	MessagingOperationProcess MessagingOperation = "process"
+	MessagingOperationSettle  MessagingOperation = "settle"
+)
+
+// Messaging describes a producer or consumer operation on a broker.
+type Messaging struct {
+	System MessagingSystem
+	// Operation is the kind of operation; OperationName is the system-specific name, such as ack.
+	Operation     MessagingOperation
+	OperationName string
+	// Destination is the topic, queue, or subject.
+	Destination   string
+	MessageID     string
+	ConsumerGroup string
+	BodySize      int
+	// BatchCount is the number of messages of a batch operation, left out for single messages.
+	BatchCount    int
+	ServerAddress string
+	ServerPort    int
+	// Err is the error the operation failed with, recorded as error.type.
+	Err error
+}
+
+// Attributes returns the semconv attributes of the operation.
+func (m Messaging) Attributes() []attribute.KeyValue {
+	var b builder
+	b.str(semconv.MessagingSystemKey, string(m.System))
+	b.str(semconv.MessagingOperationTypeKey, string(m.Operation))
+	b.str(semconv.MessagingOperationNameKey, m.OperationName)
+	b.str(semconv.MessagingDestinationNameKey, m.Destination)
+	b.str(semconv.MessagingMessageIDKey, m.MessageID)
+	b.str(semconv.MessagingConsumerGroupNameKey, m.ConsumerGroup)
+	b.int(semconv.MessagingMessageBodySizeKey, m.BodySize)
+	if m.BatchCount > 1 {
+		b = append(b, semconv.MessagingBatchMessageCount(m.BatchCount))
+	}
+	b.server(m.ServerAddress, m.ServerPort)
+	b.errorType(m.Err, 0, false)
+	return b
+}
@@ -0,0 +1,47 @@
+package attrs
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.28.0"
+	"google.golang.org/grpc/codes"
+)
+
+// RPCSystem is an rpc.system value.
+type RPCSystem string
+
+// Well-known rpc.system values.
+const (
+	RPCSystemGRPC       RPCSystem = "grpc"
+	RPCSystemConnectRPC RPCSystem = "connect_rpc"
+	RPCSystemDubbo      RPCSystem = "apache_dubbo"
+)
+
+// RPC describes a client or server remote procedure call. Add GRPCStatus once a gRPC call
+// completes.
+type RPC struct {
+	System RPCSystem
+	// Service is the full service name, such as myapp.v1.UserService.
+	Service string
+	// Method is the method name alone, such as GetUser.
+	Method        string
+	ServerAddress string
+	ServerPort    int
+	// Err is the error the call failed with, recorded as error.type.
+	Err error
+}
+
+// Attributes returns the semconv attributes of the call.
+func (r RPC) Attributes() []attribute.KeyValue {
+	var b builder
+	b.str(semconv.RPCSystemKey, string(r.System))
+	b.str(semconv.RPCServiceKey, r.Service)
+	b.str(semconv.RPCMethodKey, r.Method)
+	b.server(r.ServerAddress, r.ServerPort)
+	b.errorType(r.Err, 0, false)
+	return b
+}
+
+// GRPCStatus returns rpc.grpc.status_code for code.
+func GRPCStatus(code codes.Code) attribute.KeyValue {
+	return semconv.RPCGRPCStatusCodeKey.Int(int(code))
+}
@@ -2,10 +2,12 @@ package goo11y
 
 import (
 	"context"
+	"time"
 
 	"github.com/creasty/defaults"
 	"github.com/go-playground/validator/v10"
 	"github.com/mfahmialkautsar/goo11y/constant"
+	"github.com/mfahmialkautsar/goo11y/internal/resourceutil"
 	"github.com/mfahmialkautsar/goo11y/logger"
 	"github.com/mfahmialkautsar/goo11y/meter"
 	"github.com/mfahmialkautsar/goo11y/profiler"
@@ -21,8 +23,51 @@ type Config struct {
 	Meter       meter.Config
 	Profiler    profiler.Config
 	Customizers []ResourceCustomizer
+	// Serverless prepares the pipeline for AWS Lambda, Cloud Functions, and similar runtimes that
+	// freeze between invocations: spans and OTLP log records are exported synchronously and metrics
+	// only when flushed. Wrap handlers with Telemetry.FlushAfter so every invocation ends flushed.
+	// The logger and meter spools are bypassed, since a flush would only hand requests to a spool
+	// that cannot deliver them while the runtime is frozen. Trace batches a collector refuses still
+	// go to the failover journal and are replayed during a later invocation.
+	Serverless bool
+	// VendorPreset tunes every signal for a vendor backend. "datadog" propagates x-datadog-* headers
+	// next to W3C trace context, adds the decimal dd.trace_id and dd.span_id to log entries, and exports
+	// delta metrics every 10s, matching the Datadog Agent's flush interval. Explicit settings win.
+	VendorPreset string `validate:"omitempty,oneof=datadog"`
+	// ExportFailureLogInterval limits export failure logs to the first failure per component and
+	// transport in each interval; an interval that suppressed failures ends with a log of the last
+	// one and their count. Zero keeps the 30s default and a negative value logs every failure. The
+	// setting is process-wide.
+	ExportFailureLogInterval time.Duration
+	// OTelErrors routes errors the OpenTelemetry SDK reports through otel.Handle, such as dropped
+	// spans or conflicting instruments, to the Logger's local sinks instead of stderr.
+	OTelErrors OTelErrorConfig
+	// AsyncInit builds the OTLP exporters of the logger, tracer, and meter in the background, so New
+	// returns without waiting for the collector; telemetry queues in memory until they are ready. It
+	// sets AsyncInit on each component.
+	AsyncInit bool
+	// TraceInit records New itself: once the tracer is ready, a telemetry.init span with a child per
+	// stage (resource, logger, tracer, meter, profiler, integrations) carries each stage's duration
+	// and error, and the Logger writes one summary entry with the durations and failed stages. It
+	// helps diagnose slow startups, such as resource detectors or exporters blocked on the network.
+	TraceInit bool
 }
 
+// OTelErrorConfig controls the process-wide otel.ErrorHandler installed by New.
+type OTelErrorConfig struct {
+	// Disabled leaves the current otel.ErrorHandler in place.
+	Disabled bool
+	// LogInterval limits errors with the same message to one per interval; an interval that
+	// suppressed errors ends with a log of the last one and their count. Zero keeps the 30s default
+	// and a negative value logs every error.
+	LogInterval time.Duration
+}
+
+// VendorDatadog selects the Datadog preset for Config.VendorPreset.
+const VendorDatadog = "datadog"
+
+const datadogExportInterval = 10 * time.Second
+
 // ResourceConfig describes service identity attributes propagated to telemetry backends.
 type ResourceConfig struct {
 	ServiceName    string `default:"unknown-service"`
@@ -32,8 +77,31 @@ type ResourceConfig struct {
 	Detectors      []resource.Detector
 	Options        []resource.Option
 	Override       ResourceFactory
+	// Presets enables the built-in detectors by name: ec2, ecs, eks, gce, cloudrun and k8s.
+	// A preset contributes nothing when the process is not running on its platform.
+	Presets []string `validate:"dive,oneof=ec2 ecs eks gce cloudrun k8s"`
+	// EnvAttributes maps resource attribute keys to the environment variables holding their
+	// values, typically populated through the Kubernetes downward API. Unset variables are skipped.
+	EnvAttributes map[string]string
+	// InstanceID sets service.instance.id, shared by logs, traces, metrics and profiles. It defaults to
+	// the SERVICE_INSTANCE_ID environment variable (e.g. the pod name), then to a UUID generated per process.
+	InstanceID string
+	// RunID sets process.run_id. It defaults to a UUID generated per process, so it changes on restart.
+	RunID string
+	// SchemaConflict decides what happens when detectors, options, or the override carry a schema
+	// URL other than the SDK's, usually because they were built against another semconv version:
+	// SchemaConflictError fails setup, SchemaConflictPreferLatest keeps the newest schema URL, and
+	// SchemaConflictStrip keeps the attributes without a schema URL.
+	SchemaConflict string `default:"error" validate:"oneof=error prefer-latest strip"`
 }
 
+// Resource schema URL conflict strategies for ResourceConfig.SchemaConflict.
+const (
+	SchemaConflictError        = resourceutil.ConflictError
+	SchemaConflictPreferLatest = resourceutil.ConflictPreferLatest
+	SchemaConflictStrip        = resourceutil.ConflictStrip
+)
+
 // ResourceFactory is an optional hook to build a base resource overriding default behavior.
 type ResourceFactory func(context.Context) (*resource.Resource, error)
 
@@ -52,6 +120,7 @@ func (f ResourceCustomizerFunc) Customize(ctx context.Context, res *resource.Res
 
 func (c *Config) applyDefaults() {
 	_ = defaults.Set(&c.Resource)
+	c.Resource.resolveIdentity()
 
 	propagateServiceName := func(target *string) {
 		if *target == "" || *target == constant.DefaultServiceName {
@@ -71,10 +140,61 @@ func (c *Config) applyDefaults() {
 
 	propageteEnvironment(&c.Logger.Environment)
 
+	if c.Logger.ServiceInstanceID == "" {
+		c.Logger.ServiceInstanceID = c.Resource.InstanceID
+	}
+	if c.Logger.RunID == "" {
+		c.Logger.RunID = c.Resource.RunID
+	}
+	if c.Meter.Info.Version == "" {
+		c.Meter.Info.Version = c.Resource.ServiceVersion
+	}
+	if c.Logger.ResourceSchemaConflict == "" {
+		c.Logger.ResourceSchemaConflict = c.Resource.SchemaConflict
+	}
+
+	if c.Serverless {
+		c.Tracer.SyncExport = true
+		c.Meter.ManualExport = true
+		c.Logger.OTLP.SyncExport = true
+		c.Logger.Audit.OTLP.SyncExport = true
+		c.Logger.OTLP.UseSpool = false
+		c.Logger.Audit.OTLP.UseSpool = false
+		c.Meter.UseSpool = false
+	}
+
+	if c.AsyncInit {
+		c.Tracer.AsyncInit = true
+		c.Meter.AsyncInit = true
+		c.Logger.OTLP.AsyncInit = true
+		c.Logger.Audit.OTLP.AsyncInit = true
+	}
+
+	if c.VendorPreset == VendorDatadog {
+		if c.Tracer.Interop == "" {
+			c.Tracer.Interop = tracer.InteropDatadog
+		}
+		if c.Logger.Profile == "" {
+			c.Logger.Profile = logger.ProfileDatadog
+		}
+		if c.Meter.Temporality == "" {
+			c.Meter.Temporality = meter.TemporalityDelta
+		}
+		if c.Meter.ExportInterval == 0 {
+			c.Meter.ExportInterval = datadogExportInterval
+		}
+	}
+
 	c.Logger = c.Logger.ApplyDefaults()
 	c.Tracer = c.Tracer.ApplyDefaults()
 	c.Meter = c.Meter.ApplyDefaults()
 	c.Profiler = c.Profiler.ApplyDefaults()
+	if _, ok := c.Profiler.Tags[instanceIDTag]; !ok {
+		c.Profiler.Tags[instanceIDTag] = c.Resource.InstanceID
+	}
+	if _, ok := c.Profiler.Tags[runIDTag]; !ok {
+		c.Profiler.Tags[runIDTag] = c.Resource.RunID
+	}
 }
 
 func (c Config) validate() error {
@@ -0,0 +1,143 @@
+package goo11y
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// minMeterExportInterval is the shortest meter export interval ValidateConfig accepts without a warning.
+const minMeterExportInterval = time.Second
+
+// ConfigIssue is one finding of ValidateConfig. Field is the setting's path, such as
+// "Tracer.Export.Backend.Endpoint", or empty when the issue spans the whole Config.
+type ConfigIssue struct {
+	Field   string
+	Message string
+}
+
+// String renders the issue as "Field: Message".
+func (i ConfigIssue) String() string {
+	if i.Field == "" {
+		return i.Message
+	}
+	return i.Field + ": " + i.Message
+}
+
+// ValidationReport separates the Config errors that make New fail from warnings about settings
+// that are accepted but probably not what was meant.
+type ValidationReport struct {
+	Errors   []ConfigIssue
+	Warnings []ConfigIssue
+}
+
+// OK reports whether New would accept the Config.
+func (r ValidationReport) OK() bool {
+	return len(r.Errors) == 0
+}
+
+// Err joins the errors into one error, or returns nil when there are none.
+func (r ValidationReport) Err() error {
+	errs := make([]error, 0, len(r.Errors))
+	for _, issue := range r.Errors {
+		errs = append(errs, errors.New(issue.String()))
+	}
+	return errors.Join(errs...)
+}
+
+// ValidateConfig applies defaults to a copy of cfg and checks it the way New does, without building
+// anything, so CI can lint configuration files. Errors include the checks each enabled component
+// runs on its own Config, such as histogram bucket order or an audit file in drop mode, and a tracer
+// without any export destination, which New only accepts with span exporters passed as Options.
+// Warnings cover spools without a QueueDir (their temp directory does not survive container
+// restarts), spools that Serverless bypasses, a SampleRatio of 0 (replaced by the default 1.0), and
+// meter exports more often than every second.
+func ValidateConfig(cfg Config) ValidationReport {
+	raw := cfg
+	cfg.applyDefaults()
+
+	var report ValidationReport
+	addErrors := func(component string, err error) {
+		for _, issue := range configIssues(component, err) {
+			if !slices.Contains(report.Errors, issue) {
+				report.Errors = append(report.Errors, issue)
+			}
+		}
+	}
+	addErrors("", cfg.validate())
+	if cfg.Logger.Enabled {
+		addErrors("Logger", cfg.Logger.Validate())
+	}
+	if cfg.Tracer.Enabled {
+		addErrors("Tracer", cfg.Tracer.Validate())
+	}
+	if cfg.Meter.Enabled {
+		addErrors("Meter", cfg.Meter.Validate())
+	}
+	if cfg.Profiler.Enabled {
+		addErrors("Profiler", cfg.Profiler.Validate())
+	}
+	report.Warnings = configWarnings(raw, cfg)
+	return report
+}
+
+// configIssues converts err, returned by validating the Config of component (empty for the
+// top-level Config), into issues naming the failing fields.
+func configIssues(component string, err error) []ConfigIssue {
+	if err == nil {
+		return nil
+	}
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return []ConfigIssue{{Field: component, Message: err.Error()}}
+	}
+	issues := make([]ConfigIssue, 0, len(fieldErrs))
+	for _, fieldErr := range fieldErrs {
+		field := strings.TrimPrefix(fieldErr.Namespace(), "Config.")
+		if component != "" {
+			field = component + "." + field
+		}
+		issues = append(issues, ConfigIssue{Field: field, Message: "fails the " + fieldErr.Tag() + " rule"})
+	}
+	return issues
+}
+
+// configWarnings inspects raw, the Config as given, for settings that defaults would silently
+// change, and cfg, the Config with defaults applied, for settings that take effect as written.
+func configWarnings(raw, cfg Config) []ConfigIssue {
+	var warnings []ConfigIssue
+	warn := func(field, message string) {
+		warnings = append(warnings, ConfigIssue{Field: field, Message: message})
+	}
+
+	if cfg.Logger.Enabled && cfg.Logger.OTLP.Enabled && cfg.Logger.OTLP.UseSpool && raw.Logger.OTLP.QueueDir == "" {
+		warn("Logger.OTLP.QueueDir", "spool is enabled without a directory; the default under the temp directory is lost when the container restarts")
+	}
+	if cfg.Meter.Enabled && cfg.Meter.UseSpool && raw.Meter.QueueDir == "" {
+		warn("Meter.QueueDir", "spool is enabled without a directory; the default under the temp directory is lost when the container restarts")
+	}
+	if raw.Serverless {
+		const bypassed = "spool is bypassed in serverless mode, where requests are exported before each invocation returns"
+		if cfg.Logger.Enabled && cfg.Logger.OTLP.Enabled && raw.Logger.OTLP.UseSpool {
+			warn("Logger.OTLP.UseSpool", bypassed)
+		}
+		if cfg.Logger.Enabled && cfg.Logger.Audit.Enabled && cfg.Logger.Audit.OTLP.Enabled && raw.Logger.Audit.OTLP.UseSpool {
+			warn("Logger.Audit.OTLP.UseSpool", bypassed)
+		}
+		if cfg.Meter.Enabled && raw.Meter.UseSpool {
+			warn("Meter.UseSpool", bypassed)
+		}
+	}
+	if cfg.Tracer.Enabled {
+		if raw.Tracer.SampleRatio == 0 && cfg.Tracer.RemoteSampling.URL == "" {
+			warn("Tracer.SampleRatio", "0 is replaced by the default 1.0, sampling every trace; use a small ratio to sample almost none")
+		}
+	}
+	if cfg.Meter.Enabled && !cfg.Meter.ManualExport && cfg.Meter.ExportInterval < minMeterExportInterval {
+		warn("Meter.ExportInterval", "exports more often than every "+minMeterExportInterval.String()+", which loads the collector without adding detail")
+	}
+	return warnings
+}
@@ -0,0 +1,81 @@
+package goo11y
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mfahmialkautsar/goo11y/logger"
+)
+
+func TestValidateConfigReportsErrorsAndWarnings(t *testing.T) {
+	t.Parallel()
+
+	cfg := Config{}
+	cfg.Resource.ServiceName = "orders"
+	cfg.Tracer.Enabled = true
+	cfg.Tracer.Export.Backend.Enabled = true
+	cfg.Meter.Enabled = true
+	cfg.Meter.Endpoint = "http://collector:4318"
+	cfg.Meter.UseSpool = true
+	cfg.Meter.ExportInterval = 200 * time.Millisecond
+
+	report := ValidateConfig(cfg)
+	if report.OK() || report.Err() == nil {
+		t.Fatal("expected the missing tracer endpoint to be an error")
+	}
+	if len(report.Errors) != 1 || report.Errors[0].Field != "Tracer.Export.Backend.Endpoint" {
+		t.Fatalf("unexpected errors %v", report.Errors)
+	}
+
+	fields := make([]string, 0, len(report.Warnings))
+	for _, warning := range report.Warnings {
+		fields = append(fields, warning.Field)
+	}
+	got := strings.Join(fields, ",")
+	if got != "Meter.QueueDir,Tracer.SampleRatio,Meter.ExportInterval" {
+		t.Fatalf("unexpected warnings %v", report.Warnings)
+	}
+}
+
+func TestValidateConfigAcceptsPresets(t *testing.T) {
+	t.Parallel()
+
+	report := ValidateConfig(PresetDev())
+	if !report.OK() || len(report.Warnings) != 0 {
+		t.Fatalf("expected a clean report, got %+v", report)
+	}
+	if report.Err() != nil {
+		t.Fatalf("unexpected error %v", report.Err())
+	}
+}
+
+func TestValidateConfigRunsComponentChecks(t *testing.T) {
+	t.Parallel()
+
+	cfg := Config{}
+	cfg.Resource.ServiceName = "orders"
+	cfg.Logger.Enabled = true
+	cfg.Logger.Audit.Enabled = true
+	cfg.Logger.Audit.File = logger.FileConfig{Enabled: true, Directory: t.TempDir(), Mode: logger.FileModeDrop}
+	cfg.Tracer.Enabled = true
+	cfg.Tracer.SampleRatio = 0.5
+	cfg.Meter.Enabled = true
+	cfg.Meter.Endpoint = "http://collector:4318"
+	cfg.Meter.DefaultHistogramBuckets = []float64{1, 0.5}
+
+	report := ValidateConfig(cfg)
+	fields := make([]string, 0, len(report.Errors))
+	for _, issue := range report.Errors {
+		fields = append(fields, issue.Field)
+	}
+	if got := strings.Join(fields, ","); got != "Logger,Tracer,Meter" {
+		t.Fatalf("unexpected errors %v", report.Errors)
+	}
+	if !strings.Contains(report.Errors[0].Message, "audit file mode") {
+		t.Fatalf("expected the audit drop mode to be rejected, got %v", report.Errors[0])
+	}
+	if !strings.Contains(report.Errors[1].Message, "export target") {
+		t.Fatalf("expected a tracer without export to be an error, got %v", report.Errors[1])
+	}
+}
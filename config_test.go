@@ -125,3 +125,26 @@ func TestResourceCustomizerFuncInvokes(t *testing.T) {
 		t.Fatalf("expected custom attribute, got %v", out.Attributes())
 	}
 }
+
+func TestConfigApplyDefaultsDatadogPreset(t *testing.T) {
+	t.Parallel()
+
+	cfg := Config{VendorPreset: VendorDatadog, Tracer: tracer.Config{Interop: tracer.InteropXRay}}
+	cfg.applyDefaults()
+
+	if cfg.Tracer.Interop != tracer.InteropXRay {
+		t.Fatalf("explicit interop overwritten: %q", cfg.Tracer.Interop)
+	}
+	if cfg.Logger.Profile != logger.ProfileDatadog {
+		t.Fatalf("expected datadog logger profile, got %q", cfg.Logger.Profile)
+	}
+	if cfg.Meter.Temporality != meter.TemporalityDelta || cfg.Meter.ExportInterval != datadogExportInterval {
+		t.Fatalf("unexpected meter settings %q %v", cfg.Meter.Temporality, cfg.Meter.ExportInterval)
+	}
+
+	plain := Config{VendorPreset: VendorDatadog}
+	plain.applyDefaults()
+	if plain.Tracer.Interop != tracer.InteropDatadog {
+		t.Fatalf("expected datadog interop, got %q", plain.Tracer.Interop)
+	}
+}
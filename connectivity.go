@@ -0,0 +1,260 @@
+package goo11y
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mfahmialkautsar/goo11y/auth"
+	"github.com/mfahmialkautsar/goo11y/constant"
+	"github.com/mfahmialkautsar/goo11y/internal/otlputil"
+	collog "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetric "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// Signals reported by CheckConnectivity.
+const (
+	SignalLogs     = "logs"
+	SignalTraces   = "traces"
+	SignalMetrics  = "metrics"
+	SignalProfiles = "profiles"
+)
+
+const defaultConnectivityTimeout = 10 * time.Second
+
+// ConnectivityResult is the outcome of one CheckConnectivity probe. Err is nil when the endpoint
+// accepted the request, and Latency is the round trip of the request, or of the attempt that failed.
+type ConnectivityResult struct {
+	Signal   string
+	Endpoint string
+	Latency  time.Duration
+	Err      error
+}
+
+// otlpProbe describes one OTLP export CheckConnectivity sends.
+type otlpProbe struct {
+	signal      string
+	endpoint    string
+	insecure    bool
+	protocol    string
+	timeout     time.Duration
+	certificate string
+	headers     map[string]string
+	path        string
+	isURL       bool
+	method      string
+	request     proto.Message
+	response    proto.Message
+}
+
+// CheckConnectivity sends an empty export request to the OTLP endpoint of every enabled log,
+// trace, and metric exporter in cfg, and a request to the profiler's ServerURL, concurrently, and
+// reports one result per signal in that order. The empty requests carry no data, so collectors
+// accept them without storing anything; a rejected credential, a wrong path, or an unreachable
+// host shows up as Err. Environment defaults apply as in New. Use it at startup or in a health
+// check to catch misconfigured endpoints before traffic arrives.
+func CheckConnectivity(ctx context.Context, cfg Config) []ConnectivityResult {
+	cfg.applyDefaults()
+
+	var probes []func(context.Context) ConnectivityResult
+	if cfg.Logger.Enabled && cfg.Logger.OTLP.Enabled {
+		otlp := cfg.Logger.OTLP
+		probes = append(probes, otlpProbe{
+			signal:      SignalLogs,
+			endpoint:    otlp.Endpoint,
+			insecure:    otlp.Insecure,
+			protocol:    otlp.Protocol,
+			timeout:     otlp.Timeout,
+			certificate: otlp.Certificate,
+			headers:     otlputil.MergeHeaders(otlp.Credentials.HeaderMap(), otlp.Headers),
+			path:        "/v1/logs",
+			isURL:       otlp.EndpointIsURL,
+			method:      "/opentelemetry.proto.collector.logs.v1.LogsService/Export",
+			request:     new(collog.ExportLogsServiceRequest),
+			response:    new(collog.ExportLogsServiceResponse),
+		}.run)
+	}
+	if cfg.Tracer.Enabled && cfg.Tracer.Export.Backend.Enabled {
+		backend := cfg.Tracer.Export.Backend
+		probes = append(probes, otlpProbe{
+			signal:      SignalTraces,
+			endpoint:    backend.Endpoint,
+			insecure:    backend.Insecure,
+			protocol:    backend.Protocol,
+			timeout:     backend.Timeout,
+			certificate: backend.Certificate,
+			headers:     otlputil.MergeHeaders(backend.Credentials.HeaderMap(), backend.Headers),
+			path:        "/v1/traces",
+			isURL:       backend.EndpointIsURL,
+			method:      "/opentelemetry.proto.collector.trace.v1.TraceService/Export",
+			request:     new(coltrace.ExportTraceServiceRequest),
+			response:    new(coltrace.ExportTraceServiceResponse),
+		}.run)
+	}
+	if cfg.Meter.Enabled {
+		m := cfg.Meter
+		probes = append(probes, otlpProbe{
+			signal:      SignalMetrics,
+			endpoint:    m.Endpoint,
+			insecure:    m.Insecure,
+			protocol:    m.Protocol,
+			timeout:     m.Timeout,
+			certificate: m.Certificate,
+			headers:     otlputil.MergeHeaders(m.Credentials.HeaderMap(), m.Headers),
+			path:        "/v1/metrics",
+			isURL:       m.EndpointIsURL,
+			method:      "/opentelemetry.proto.collector.metrics.v1.MetricsService/Export",
+			request:     new(colmetric.ExportMetricsServiceRequest),
+			response:    new(colmetric.ExportMetricsServiceResponse),
+		}.run)
+	}
+	if cfg.Profiler.Enabled {
+		profiler := cfg.Profiler
+		probes = append(probes, func(ctx context.Context) ConnectivityResult {
+			return checkProfiler(ctx, profiler.ServerURL, profiler.TenantID, profiler.Credentials)
+		})
+	}
+
+	results := make([]ConnectivityResult, len(probes))
+	var wg sync.WaitGroup
+	for idx, probe := range probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[idx] = probe(ctx)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+func (p otlpProbe) run(ctx context.Context) ConnectivityResult {
+	result := ConnectivityResult{Signal: p.signal, Endpoint: p.endpoint}
+	endpoint, err := otlputil.ParseEndpoint(p.endpoint, p.insecure)
+	if err != nil {
+		result.Err = fmt.Errorf("%s endpoint: %w", p.signal, err)
+		return result
+	}
+	tlsConfig, err := otlputil.TLSConfig(p.certificate)
+	if err != nil {
+		result.Err = fmt.Errorf("%s: %w", p.signal, err)
+		return result
+	}
+	timeout := p.timeout
+	if timeout <= 0 {
+		timeout = defaultConnectivityTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	if p.protocol == constant.ProtocolGRPC {
+		err = p.exportGRPC(ctx, endpoint, credentialsFor(endpoint.Insecure, tlsConfig))
+	} else {
+		client := otlputil.NewHTTPClient(timeout, tlsConfig)
+		err = p.exportHTTP(ctx, endpoint.ExportURL(p.path, p.isURL), client)
+		client.CloseIdleConnections()
+	}
+	result.Latency = time.Since(start)
+	if err != nil {
+		result.Err = fmt.Errorf("%s export to %s: %w", p.signal, p.endpoint, err)
+	}
+	return result
+}
+
+func (p otlpProbe) exportHTTP(ctx context.Context, url string, client *http.Client) error {
+	body, err := proto.Marshal(p.request)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	for key, value := range p.headers {
+		req.Header.Set(key, value)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+func (p otlpProbe) exportGRPC(ctx context.Context, endpoint otlputil.Endpoint, creds credentials.TransportCredentials) error {
+	if endpoint.HasPath() {
+		return fmt.Errorf("grpc endpoint must not include a path")
+	}
+	conn, err := grpc.NewClient(endpoint.HostWithPath(), grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	md := metadata.MD{}
+	for key, value := range p.headers {
+		md.Append(strings.ToLower(key), value)
+	}
+	return conn.Invoke(metadata.NewOutgoingContext(ctx, md), p.method, p.request, p.response)
+}
+
+func credentialsFor(insecureTransport bool, tlsConfig *tls.Config) credentials.TransportCredentials {
+	switch {
+	case insecureTransport:
+		return insecure.NewCredentials()
+	case tlsConfig != nil:
+		return credentials.NewTLS(tlsConfig)
+	default:
+		return credentials.NewClientTLSFromCert(nil, "")
+	}
+}
+
+// checkProfiler requests serverURL with the profiler's credentials. Pyroscope has no empty upload,
+// so any answer other than a 401, 403, or 5xx counts as reachable.
+func checkProfiler(ctx context.Context, serverURL, tenantID string, creds auth.Credentials) ConnectivityResult {
+	result := ConnectivityResult{Signal: SignalProfiles, Endpoint: serverURL}
+	ctx, cancel := context.WithTimeout(ctx, defaultConnectivityTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, serverURL, nil)
+	if err != nil {
+		result.Err = fmt.Errorf("profiles endpoint: %w", err)
+		return result
+	}
+	for key, value := range creds.HeaderMap() {
+		req.Header.Set(key, value)
+	}
+	if tenantID != "" {
+		req.Header.Set("X-Scope-OrgID", tenantID)
+	}
+
+	start := time.Now()
+	resp, err := http.DefaultClient.Do(req)
+	result.Latency = time.Since(start)
+	if err != nil {
+		result.Err = fmt.Errorf("profiles request to %s: %w", serverURL, err)
+		return result
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden || resp.StatusCode >= http.StatusInternalServerError {
+		result.Err = fmt.Errorf("profiles request to %s: unexpected status %s", serverURL, resp.Status)
+	}
+	return result
+}
@@ -0,0 +1,116 @@
+package goo11y
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mfahmialkautsar/goo11y/auth"
+	"github.com/mfahmialkautsar/goo11y/constant"
+)
+
+func TestCheckConnectivityReportsEachSignal(t *testing.T) {
+	var mu sync.Mutex
+	authorization := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		authorization[r.URL.Path] = r.Header.Get("Authorization")
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/v1/logs", "/v1/metrics":
+			w.WriteHeader(http.StatusOK)
+		case "/v1/traces":
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	creds := auth.Credentials{BearerToken: "secret"}
+	cfg := Config{}
+	cfg.Logger.Enabled = true
+	cfg.Logger.OTLP.Enabled = true
+	cfg.Logger.OTLP.Endpoint = server.URL
+	cfg.Logger.OTLP.Credentials = creds
+	cfg.Tracer.Enabled = true
+	cfg.Tracer.Export.Backend.Enabled = true
+	cfg.Tracer.Export.Backend.Endpoint = server.URL
+	cfg.Meter.Enabled = true
+	cfg.Meter.Endpoint = server.URL
+	cfg.Profiler.Enabled = true
+	cfg.Profiler.ServerURL = server.URL
+
+	results := CheckConnectivity(context.Background(), cfg)
+	if len(results) != 4 {
+		t.Fatalf("expected 4 results, got %d", len(results))
+	}
+	want := []struct {
+		signal string
+		ok     bool
+	}{
+		{SignalLogs, true},
+		{SignalTraces, false},
+		{SignalMetrics, true},
+		{SignalProfiles, true},
+	}
+	for idx, result := range results {
+		if result.Signal != want[idx].signal || (result.Err == nil) != want[idx].ok {
+			t.Fatalf("result %d = %s err=%v, want %s ok=%v", idx, result.Signal, result.Err, want[idx].signal, want[idx].ok)
+		}
+		if result.Latency <= 0 {
+			t.Fatalf("%s latency not measured", result.Signal)
+		}
+	}
+	if !strings.Contains(results[1].Err.Error(), "401") {
+		t.Fatalf("expected the status in the trace error, got %v", results[1].Err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if authorization["/v1/logs"] != "Bearer secret" {
+		t.Fatalf("expected credentials on the log export, got %q", authorization["/v1/logs"])
+	}
+}
+
+func TestCheckConnectivityReportsUnreachableEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	endpoint := server.URL
+	server.Close()
+
+	cfg := Config{}
+	cfg.Meter.Enabled = true
+	cfg.Meter.Endpoint = endpoint
+
+	results := CheckConnectivity(context.Background(), cfg)
+	if len(results) != 1 || results[0].Err == nil {
+		t.Fatalf("expected one failed result, got %+v", results)
+	}
+}
+
+func TestCheckConnectivityFailsFastOnUnreachableGRPCEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	endpoint := strings.TrimPrefix(server.URL, "http://")
+	server.Close()
+
+	cfg := Config{}
+	cfg.Meter.Enabled = true
+	cfg.Meter.Endpoint = endpoint
+	cfg.Meter.Insecure = true
+	cfg.Meter.Protocol = constant.ProtocolGRPC
+
+	start := time.Now()
+	results := CheckConnectivity(context.Background(), cfg)
+	if len(results) != 1 || results[0].Err == nil {
+		t.Fatalf("expected one failed result, got %+v", results)
+	}
+	if elapsed := time.Since(start); elapsed >= defaultConnectivityTimeout/2 {
+		t.Fatalf("expected the probe to fail fast, took %v", elapsed)
+	}
+	if !strings.Contains(results[0].Err.Error(), "Unavailable") {
+		t.Fatalf("expected the transport error, got %v", results[0].Err)
+	}
+}
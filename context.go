@@ -0,0 +1,62 @@
+package goo11y
+
+import (
+	"context"
+
+	"github.com/mfahmialkautsar/goo11y/logger"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+type telemetryContextKey struct{}
+
+// NewContext returns a copy of ctx carrying tele so that code deep in a call
+// stack can reach the configured components without relying on globals.
+func NewContext(ctx context.Context, tele *Telemetry) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, telemetryContextKey{}, tele)
+}
+
+// FromContext returns the Telemetry stored in ctx by NewContext, or nil when
+// none is present.
+func FromContext(ctx context.Context) *Telemetry {
+	if ctx == nil {
+		return nil
+	}
+	tele, _ := ctx.Value(telemetryContextKey{}).(*Telemetry)
+	return tele
+}
+
+// LoggerFromContext returns the logger of the Telemetry stored in ctx. It
+// never returns nil; a logger that discards every event is returned when ctx
+// carries no Telemetry or the logger is disabled.
+func LoggerFromContext(ctx context.Context) *logger.Logger {
+	if tele := FromContext(ctx); tele != nil && tele.Logger() != nil {
+		return tele.Logger()
+	}
+	return logger.Nop()
+}
+
+// TracerFromContext returns a named tracer from the Telemetry stored in ctx.
+// A no-op tracer is returned when ctx carries no Telemetry or tracing is
+// disabled.
+func TracerFromContext(ctx context.Context, name string, opts ...trace.TracerOption) trace.Tracer {
+	if tele := FromContext(ctx); tele != nil && tele.Tracer() != nil {
+		return tele.Tracer().Tracer(name, opts...)
+	}
+	return tracenoop.NewTracerProvider().Tracer(name, opts...)
+}
+
+// MeterFromContext returns a named meter from the Telemetry stored in ctx. A
+// no-op meter is returned when ctx carries no Telemetry or metrics are
+// disabled.
+func MeterFromContext(ctx context.Context, name string, opts ...metric.MeterOption) metric.Meter {
+	if tele := FromContext(ctx); tele != nil && tele.Meter() != nil {
+		return tele.Meter().Meter(name, opts...)
+	}
+	return metricnoop.NewMeterProvider().Meter(name, opts...)
+}
@@ -0,0 +1,62 @@
+package goo11y
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestContextRoundTrip(t *testing.T) {
+	tele, buf, _ := newRecoverTelemetry(t)
+	ctx := NewContext(context.Background(), tele)
+
+	if got := FromContext(ctx); got != tele {
+		t.Fatalf("FromContext returned %p, want %p", got, tele)
+	}
+
+	LoggerFromContext(ctx).Info().Msg("from context")
+	if !strings.Contains(buf.String(), "from context") {
+		t.Fatalf("expected log written through context logger, got %q", buf.String())
+	}
+}
+
+func TestContextAccessorsWithoutTelemetry(t *testing.T) {
+	ctx := context.Background()
+	if FromContext(ctx) != nil {
+		t.Fatal("expected nil telemetry on bare context")
+	}
+
+	log := LoggerFromContext(ctx)
+	if log == nil {
+		t.Fatal("expected non-nil fallback logger")
+	}
+	log.Info().Msg("discarded")
+
+	_, span := TracerFromContext(ctx, "test").Start(ctx, "op")
+	if span.SpanContext().IsValid() {
+		t.Fatal("expected no-op span from fallback tracer")
+	}
+	span.End()
+
+	counter, err := MeterFromContext(ctx, "test").Int64Counter("calls")
+	if err != nil {
+		t.Fatalf("fallback counter: %v", err)
+	}
+	counter.Add(ctx, 1)
+}
+
+func TestContextAccessorsWithDisabledComponents(t *testing.T) {
+	ctx := NewContext(context.Background(), &Telemetry{})
+
+	if LoggerFromContext(ctx) == nil {
+		t.Fatal("expected fallback logger for disabled component")
+	}
+	_, span := TracerFromContext(ctx, "test").Start(ctx, "op")
+	defer span.End()
+	if span.SpanContext().IsValid() {
+		t.Fatal("expected no-op span for disabled tracer")
+	}
+	if MeterFromContext(ctx, "test") == nil {
+		t.Fatal("expected fallback meter for disabled component")
+	}
+}
@@ -0,0 +1,75 @@
+package goo11y
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mfahmialkautsar/goo11y/logger"
+	"github.com/mfahmialkautsar/goo11y/meter"
+	"github.com/mfahmialkautsar/goo11y/profiler"
+	"github.com/mfahmialkautsar/goo11y/tracer"
+)
+
+var (
+	globalTelemetry atomic.Pointer[Telemetry]
+	globalMu        sync.Mutex
+)
+
+// Init constructs Telemetry using New and installs it with Use.
+func Init(ctx context.Context, cfg Config, opts ...Option) error {
+	tele, err := New(ctx, cfg, opts...)
+	if err != nil {
+		return err
+	}
+	Use(tele)
+	return nil
+}
+
+// Use installs tele as the global Telemetry and each of its components as the
+// logger, tracer, meter, and profiler globals. Disabled components install the
+// disabled noop globals. Concurrent calls never leave the globals mixed
+// between two Telemetry instances. Passing nil resets every global.
+func Use(tele *Telemetry) {
+	globalMu.Lock()
+	defer globalMu.Unlock()
+
+	if tele == nil {
+		logger.Use(nil)
+		tracer.Use(nil)
+		meter.Use(nil)
+		profiler.Use(nil)
+	} else {
+		logger.Use(tele.Logger())
+		tracer.Use(tele.Tracer())
+		meter.Use(tele.Meter())
+		profiler.Use(tele.Profiler())
+	}
+	globalTelemetry.Store(tele)
+}
+
+// Global returns the Telemetry installed by Init or Use.
+// Returns nil if not initialized; Telemetry methods are nil-safe.
+func Global() *Telemetry {
+	return globalTelemetry.Load()
+}
+
+// L returns the global logger.
+func L() *logger.Logger {
+	return logger.Global()
+}
+
+// T returns the global tracer provider.
+func T() *tracer.Provider {
+	return tracer.Global()
+}
+
+// M returns the global meter provider.
+func M() *meter.Provider {
+	return meter.Global()
+}
+
+// P returns the global profiler controller.
+func P() *profiler.Controller {
+	return profiler.Global()
+}
@@ -35,7 +35,7 @@ func TestNewUsesGlobalLogger(t *testing.T) {
 	if err != nil {
 		t.Fatalf("New with global logger: %v", err)
 	}
-	if tele.Logger == nil {
+	if tele.Logger() == nil {
 		t.Fatal("expected logger instance")
 	}
 	global := logger.Global()
@@ -106,15 +106,15 @@ func TestTelemetryLinksTracesToProfilesWithGlobalProviders(t *testing.T) {
 		tracer.Use(nil)
 	})
 
-	if tele.Tracer == nil {
+	if tele.Tracer() == nil {
 		t.Fatal("expected tracer provider")
 	}
-	if tele.Profiler == nil {
+	if tele.Profiler() == nil {
 		t.Fatal("expected profiler controller")
 	}
 
 	recorder := tracetest.NewSpanRecorder()
-	tele.Tracer.RegisterSpanProcessor(recorder)
+	tele.Tracer().RegisterSpanProcessor(recorder)
 
 	profileID := "global-profile-id"
 	pyroscope.TagWrapper(context.Background(), pyroscope.Labels(profiler.TraceProfileAttributeKey, profileID), func(ctx context.Context) {
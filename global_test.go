@@ -0,0 +1,114 @@
+package goo11y
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mfahmialkautsar/goo11y/logger"
+	"github.com/mfahmialkautsar/goo11y/meter"
+	"github.com/mfahmialkautsar/goo11y/profiler"
+	"github.com/mfahmialkautsar/goo11y/tracer"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestInitInstallsGlobalTelemetry(t *testing.T) {
+	var buf bytes.Buffer
+	err := Init(context.Background(), reloadTestConfig(&buf),
+		WithTracerOption(tracer.WithSpanExporter(tracetest.NewInMemoryExporter())),
+		WithMeterOption(meter.WithMetricReader(sdkmetric.NewManualReader())),
+	)
+	if err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	tele := Global()
+	t.Cleanup(func() {
+		Use(nil)
+		_ = tele.Shutdown(context.Background())
+	})
+
+	if tele == nil {
+		t.Fatal("expected global telemetry")
+	}
+	if L() != tele.Logger() || logger.Global() != tele.Logger() {
+		t.Fatal("expected global logger to match telemetry logger")
+	}
+	if T() != tele.Tracer() || tracer.Global() != tele.Tracer() {
+		t.Fatal("expected global tracer to match telemetry tracer")
+	}
+	if M() != tele.Meter() || meter.Global() != tele.Meter() {
+		t.Fatal("expected global meter to match telemetry meter")
+	}
+	if P() == nil {
+		t.Fatal("expected disabled profiler controller, got nil")
+	}
+
+	L().Info().Msg("through global")
+	if !strings.Contains(buf.String(), "through global") {
+		t.Fatalf("expected entry written through global logger, got %q", buf.String())
+	}
+}
+
+func TestUseNilResetsGlobals(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := logger.New(context.Background(), logger.Config{
+		Enabled: true,
+		Console: false,
+		Writers: []io.Writer{&buf},
+	})
+	if err != nil {
+		t.Fatalf("logger.New: %v", err)
+	}
+	t.Cleanup(func() { _ = log.Close() })
+
+	Use(FromComponents(log, nil, nil, nil))
+	if L() != log {
+		t.Fatal("expected Use to install the logger globally")
+	}
+
+	Use(nil)
+	if Global() != nil {
+		t.Fatal("expected nil global telemetry after reset")
+	}
+	if L() == log || L() == nil {
+		t.Fatal("expected disabled global logger after reset")
+	}
+	if T() == nil || M() == nil || P() == nil {
+		t.Fatal("expected disabled globals after reset, got nil")
+	}
+	if P() != profiler.Global() {
+		t.Fatal("expected P to mirror profiler.Global")
+	}
+}
+
+func TestReloadRefreshesGlobals(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := reloadTestConfig(&buf)
+	err := Init(context.Background(), cfg,
+		WithTracerOption(tracer.WithSpanExporter(tracetest.NewInMemoryExporter())),
+		WithMeterOption(meter.WithMetricReader(sdkmetric.NewManualReader())),
+	)
+	if err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	tele := Global()
+	t.Cleanup(func() {
+		Use(nil)
+		_ = tele.Shutdown(context.Background())
+	})
+
+	before := L()
+	cfg.Logger.Level = "debug"
+	if err := tele.Reload(context.Background(), cfg); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if L() == before {
+		t.Fatal("expected reload to replace the global logger")
+	}
+	if L() != tele.Logger() {
+		t.Fatal("expected global logger to follow the reloaded telemetry")
+	}
+}
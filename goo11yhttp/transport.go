@@ -0,0 +1,254 @@
+// Package goo11yhttp instruments outbound HTTP calls. NewTransport wraps an http.RoundTripper with
+// client spans, trace context injection, request duration and attempt metrics, and trace-correlated
+// logs for failed calls.
+package goo11yhttp
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/mfahmialkautsar/goo11y"
+	"github.com/mfahmialkautsar/goo11y/logger"
+	"github.com/mfahmialkautsar/goo11y/meter"
+	"github.com/mfahmialkautsar/goo11y/tracer"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.28.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/mfahmialkautsar/goo11y/goo11yhttp"
+
+// AttemptsMetricName counts every request sent through the transport, with retry=true for resends
+// of a request tracked by ContextWithRetries.
+const AttemptsMetricName = "http.client.request.attempts"
+
+// Option configures a Transport.
+type Option func(*Transport)
+
+// WithTracerProvider records spans through provider instead of the global tracer provider.
+func WithTracerProvider(provider *tracer.Provider) Option {
+	return func(t *Transport) {
+		t.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider records metrics through provider instead of the global meter provider.
+func WithMeterProvider(provider *meter.Provider) Option {
+	return func(t *Transport) {
+		t.meterProvider = provider
+	}
+}
+
+// WithLogger logs failures through log instead of the global logger.
+func WithLogger(log *logger.Logger) Option {
+	return func(t *Transport) {
+		t.logger = log
+	}
+}
+
+// WithScrubber records URLs and headers through scrubber. The zero HTTPScrubber is the default, so
+// query values and credentials are redacted and no header is recorded.
+func WithScrubber(scrubber goo11y.HTTPScrubber) Option {
+	return func(t *Transport) {
+		t.scrubber = scrubber
+	}
+}
+
+// WithPropagator injects the trace context through propagator instead of the global propagator.
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(t *Transport) {
+		t.propagator = propagator
+	}
+}
+
+// Transport is an instrumented http.RoundTripper.
+type Transport struct {
+	base           http.RoundTripper
+	tracerProvider *tracer.Provider
+	meterProvider  *meter.Provider
+	logger         *logger.Logger
+	scrubber       goo11y.HTTPScrubber
+	propagator     propagation.TextMapPropagator
+
+	tracer   trace.Tracer
+	duration metric.Float64Histogram
+	attempts metric.Int64Counter
+}
+
+// NewTransport wraps base, http.DefaultTransport when nil. Every request gets a client span named
+// after its method, carries the span's context and the request ID of its context in its headers,
+// and is recorded in http.client.request.duration and http.client.request.attempts. Transport
+// errors and responses with a 5xx status are logged at error level. Components not supplied
+// through options fall back to the globals.
+func NewTransport(base http.RoundTripper, opts ...Option) (*Transport, error) {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	t := &Transport{base: base}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(t)
+		}
+	}
+	if t.tracerProvider == nil {
+		t.tracerProvider = tracer.Global()
+	}
+	if t.meterProvider == nil {
+		t.meterProvider = meter.Global()
+	}
+
+	t.tracer = t.tracerProvider.Tracer(instrumentationName)
+	m := t.meterProvider.Meter(instrumentationName)
+	var err error
+	t.duration, err = m.Float64Histogram(
+		semconv.HTTPClientRequestDurationName,
+		metric.WithDescription(semconv.HTTPClientRequestDurationDescription),
+		metric.WithUnit(semconv.HTTPClientRequestDurationUnit),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("goo11yhttp: create duration histogram: %w", err)
+	}
+	t.attempts, err = m.Int64Counter(
+		AttemptsMetricName,
+		metric.WithDescription("Number of outbound HTTP request attempts"),
+		metric.WithUnit("{attempt}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("goo11yhttp: create attempts counter: %w", err)
+	}
+	return t, nil
+}
+
+type retriesKey struct{}
+
+// ContextWithRetries marks ctx as one logical request that a retry loop may send several times.
+// Each RoundTrip with the returned context, or a context derived from it, counts as an attempt:
+// resends carry http.request.resend_count on their span and retry=true on the attempts counter.
+func ContextWithRetries(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retriesKey{}, new(atomic.Int64))
+}
+
+// RoundTrip sends req through the wrapped transport.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	resend := 0
+	if counter, ok := ctx.Value(retriesKey{}).(*atomic.Int64); ok {
+		resend = int(counter.Add(1)) - 1
+	}
+
+	attrs := []attribute.KeyValue{semconv.HTTPRequestMethodKey.String(method(req))}
+	if host, port := serverAddress(req); host != "" {
+		attrs = append(attrs, semconv.ServerAddress(host))
+		if port > 0 {
+			attrs = append(attrs, semconv.ServerPort(port))
+		}
+	}
+	spanAttrs := append([]attribute.KeyValue{semconv.URLFull(t.scrubber.URL(req.URL))}, attrs...)
+	spanAttrs = append(spanAttrs, t.scrubber.HeaderAttributes("http.request.header.", req.Header)...)
+	if resend > 0 {
+		spanAttrs = append(spanAttrs, semconv.HTTPRequestResendCount(resend))
+	}
+	ctx, span := t.tracer.Start(ctx, method(req),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(spanAttrs...),
+	)
+	defer span.End()
+
+	out := req.Clone(ctx)
+	t.propagatorFor().Inject(ctx, propagation.HeaderCarrier(out.Header))
+	if id := logger.RequestIDFromContext(ctx); id != "" && out.Header.Get(goo11y.RequestIDHeader) == "" {
+		out.Header.Set(goo11y.RequestIDHeader, id)
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(out)
+	elapsed := time.Since(start)
+
+	metricAttrs := append([]attribute.KeyValue(nil), attrs...)
+	switch {
+	case err != nil:
+		errorType := semconv.ErrorTypeKey.String(fmt.Sprintf("%T", err))
+		metricAttrs = append(metricAttrs, errorType)
+		span.SetAttributes(errorType)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		t.logFailure(ctx, req, 0, elapsed, err)
+	default:
+		status := semconv.HTTPResponseStatusCode(resp.StatusCode)
+		metricAttrs = append(metricAttrs, status)
+		span.SetAttributes(status)
+		span.SetAttributes(t.scrubber.HeaderAttributes("http.response.header.", resp.Header)...)
+		if resp.StatusCode >= http.StatusBadRequest {
+			errorType := semconv.ErrorTypeKey.String(strconv.Itoa(resp.StatusCode))
+			metricAttrs = append(metricAttrs, errorType)
+			span.SetAttributes(errorType)
+			span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+		}
+		if resp.StatusCode >= http.StatusInternalServerError {
+			t.logFailure(ctx, req, resp.StatusCode, elapsed, nil)
+		}
+	}
+	t.duration.Record(ctx, elapsed.Seconds(), metric.WithAttributes(metricAttrs...))
+	t.attempts.Add(ctx, 1, metric.WithAttributes(append(attrs, attribute.Bool("retry", resend > 0))...))
+	return resp, err
+}
+
+func (t *Transport) propagatorFor() propagation.TextMapPropagator {
+	if t.propagator != nil {
+		return t.propagator
+	}
+	return otel.GetTextMapPropagator()
+}
+
+func (t *Transport) logFailure(ctx context.Context, req *http.Request, status int, elapsed time.Duration, err error) {
+	log := t.logger
+	if log == nil {
+		log = logger.Global()
+	}
+	event := log.Error().Ctx(ctx).
+		Str("http_request_method", method(req)).
+		Str("url_full", t.scrubber.URL(req.URL)).
+		Dur("duration", elapsed)
+	if status > 0 {
+		event = event.Int("http_response_status_code", status)
+	}
+	if err != nil {
+		event = event.Err(err)
+	}
+	event.Msg("outbound http request failed")
+}
+
+func method(req *http.Request) string {
+	if req.Method == "" {
+		return http.MethodGet
+	}
+	return req.Method
+}
+
+func serverAddress(req *http.Request) (string, int) {
+	if req.URL == nil {
+		return "", 0
+	}
+	host, portText, err := net.SplitHostPort(req.URL.Host)
+	if err != nil {
+		host = req.URL.Hostname()
+		switch req.URL.Scheme {
+		case "https":
+			return host, 443
+		case "http":
+			return host, 80
+		}
+		return host, 0
+	}
+	port, _ := strconv.Atoi(portText)
+	return host, port
+}
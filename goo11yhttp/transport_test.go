@@ -0,0 +1,184 @@
+package goo11yhttp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mfahmialkautsar/goo11y"
+	"github.com/mfahmialkautsar/goo11y/logger"
+	"github.com/mfahmialkautsar/goo11y/meter"
+	"github.com/mfahmialkautsar/goo11y/tracer"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.28.0"
+)
+
+type fixture struct {
+	client   *http.Client
+	recorder *tracetest.SpanRecorder
+	reader   *sdkmetric.ManualReader
+	logs     *bytes.Buffer
+}
+
+func newFixture(t *testing.T, base http.RoundTripper) fixture {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	var logs bytes.Buffer
+	log, err := logger.New(context.Background(), logger.Config{
+		Enabled: true,
+		Console: false,
+		Writers: []io.Writer{&logs},
+	})
+	if err != nil {
+		t.Fatalf("logger.New: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = tp.Shutdown(context.Background())
+		_ = mp.Shutdown(context.Background())
+		_ = log.Close()
+	})
+
+	transport, err := NewTransport(base,
+		WithTracerProvider(tracer.NewProvider(tp)),
+		WithMeterProvider(meter.NewProvider(mp)),
+		WithLogger(log),
+		WithPropagator(propagation.TraceContext{}),
+	)
+	if err != nil {
+		t.Fatalf("NewTransport: %v", err)
+	}
+	return fixture{client: &http.Client{Transport: transport}, recorder: recorder, reader: reader, logs: &logs}
+}
+
+func TestTransportInstrumentsRequests(t *testing.T) {
+	var traceparent, requestID string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		requestID = r.Header.Get(goo11y.RequestIDHeader)
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+	f := newFixture(t, nil)
+
+	ctx := logger.ContextWithRequestID(context.Background(), "req-42")
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/users?token=abc", nil)
+	resp, err := f.client.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	spans := f.recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != http.MethodGet || span.Status().Code == codes.Error {
+		t.Fatalf("unexpected span %q with status %v", span.Name(), span.Status())
+	}
+	if !strings.Contains(traceparent, span.SpanContext().TraceID().String()) {
+		t.Fatalf("traceparent %q does not carry trace %s", traceparent, span.SpanContext().TraceID())
+	}
+	if requestID != "req-42" {
+		t.Fatalf("request ID header = %q", requestID)
+	}
+	if got := spanAttr(span, semconv.URLFullKey); !strings.HasSuffix(got, "/users?token=REDACTED") {
+		t.Fatalf("url.full = %q", got)
+	}
+
+	req, _ = http.NewRequest(http.MethodPost, server.URL+"/fail", nil)
+	resp, err = f.client.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	_ = resp.Body.Close()
+	if failed := f.recorder.Ended()[1]; failed.Status().Code != codes.Error || spanAttr(failed, semconv.ErrorTypeKey) != "502" {
+		t.Fatalf("expected an error span for 502, got %v", failed.Status())
+	}
+	if logs := f.logs.String(); !strings.Contains(logs, "outbound http request failed") || !strings.Contains(logs, `"http_response_status_code":502`) {
+		t.Fatalf("expected a failure log, got %s", logs)
+	}
+}
+
+type flakyTransport struct {
+	calls int
+}
+
+func (f *flakyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.calls++
+	if f.calls == 1 {
+		return nil, errors.New("connection reset")
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+}
+
+func TestTransportCountsRetries(t *testing.T) {
+	f := newFixture(t, &flakyTransport{})
+	ctx := ContextWithRetries(context.Background())
+
+	for attempt := 0; attempt < 2; attempt++ {
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.test/orders", nil)
+		resp, err := f.client.Do(req)
+		if err == nil {
+			_ = resp.Body.Close()
+		}
+	}
+
+	spans := f.recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	if got := spanAttr(spans[1], semconv.HTTPRequestResendCountKey); got != "1" {
+		t.Fatalf("resend count = %q", got)
+	}
+	if !strings.Contains(f.logs.String(), "connection reset") {
+		t.Fatalf("expected the transport error to be logged, got %s", f.logs.String())
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := f.reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	counts := make(map[bool]int64)
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name != AttemptsMetricName {
+				continue
+			}
+			for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				retry, _ := dp.Attributes.Value("retry")
+				counts[retry.AsBool()] += dp.Value
+			}
+		}
+	}
+	if counts[false] != 1 || counts[true] != 1 {
+		t.Fatalf("attempt counts = %v, want one first attempt and one retry", counts)
+	}
+}
+
+func spanAttr(span sdktrace.ReadOnlySpan, key attribute.Key) string {
+	for _, attr := range span.Attributes() {
+		if attr.Key == key {
+			return attr.Value.Emit()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,230 @@
+// Package goo11yredis instruments Redis commands independently of the client library. Process and
+// ProcessPipeline take a command as its argument list, the shape go-redis's Cmder.Args returns, and
+// record a client span with a sanitized statement, the db.client.operation.duration histogram, and
+// a trace-correlated log entry on failure.
+//
+// Package goo11yredis/goredis adapts them to go-redis v9 as a redis.Hook. With other clients, pass
+// the client's cache-miss error to WithIgnoredErrors so misses are not reported as failures.
+package goo11yredis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mfahmialkautsar/goo11y/logger"
+	"github.com/mfahmialkautsar/goo11y/meter"
+	"github.com/mfahmialkautsar/goo11y/tracer"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	semconv "go.opentelemetry.io/otel/semconv/v1.28.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/mfahmialkautsar/goo11y/goo11yredis"
+
+const (
+	defaultMaxStatementLength = 256
+	pipelineOperation         = "PIPELINE"
+)
+
+// secretCommands carry credentials in every argument, so none of them is recorded.
+var secretCommands = map[string]struct{}{"AUTH": {}, "HELLO": {}, "MIGRATE": {}}
+
+// Option configures an Instrumenter.
+type Option func(*Instrumenter)
+
+// WithTracerProvider records spans through provider instead of the global tracer provider.
+func WithTracerProvider(provider *tracer.Provider) Option {
+	return func(i *Instrumenter) {
+		i.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider records latency through provider instead of the global meter provider.
+func WithMeterProvider(provider *meter.Provider) Option {
+	return func(i *Instrumenter) {
+		i.meterProvider = provider
+	}
+}
+
+// WithLogger logs failures through log instead of the global logger.
+func WithLogger(log *logger.Logger) Option {
+	return func(i *Instrumenter) {
+		i.logger = log
+	}
+}
+
+// WithMaxStatementLength truncates the recorded statement to n bytes, 256 by default.
+// Zero or less leaves the statement out.
+func WithMaxStatementLength(n int) Option {
+	return func(i *Instrumenter) {
+		i.maxStatement = n
+	}
+}
+
+// WithIgnoredErrors treats errors matching any of errs, such as redis.Nil, as successful replies.
+func WithIgnoredErrors(errs ...error) Option {
+	return func(i *Instrumenter) {
+		i.ignored = append(i.ignored, errs...)
+	}
+}
+
+// Instrumenter wraps Redis commands.
+type Instrumenter struct {
+	tracerProvider *tracer.Provider
+	meterProvider  *meter.Provider
+	logger         *logger.Logger
+	maxStatement   int
+	ignored        []error
+
+	tracer   trace.Tracer
+	duration metric.Float64Histogram
+}
+
+// New creates an Instrumenter. Components not supplied through options fall back to the globals.
+func New(opts ...Option) (*Instrumenter, error) {
+	i := &Instrumenter{maxStatement: defaultMaxStatementLength}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(i)
+		}
+	}
+	if i.tracerProvider == nil {
+		i.tracerProvider = tracer.Global()
+	}
+	if i.meterProvider == nil {
+		i.meterProvider = meter.Global()
+	}
+
+	i.tracer = i.tracerProvider.Tracer(instrumentationName)
+	var err error
+	i.duration, err = i.meterProvider.Meter(instrumentationName).Float64Histogram(
+		semconv.DBClientOperationDurationName,
+		metric.WithDescription(semconv.DBClientOperationDurationDescription),
+		metric.WithUnit(semconv.DBClientOperationDurationUnit),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("goo11yredis: create duration histogram: %w", err)
+	}
+	return i, nil
+}
+
+// Process starts a client span for the command args, such as ["set", "user:1", "…"], and calls
+// call. The error returned by call is recorded and passed through.
+func (i *Instrumenter) Process(ctx context.Context, args []any, call func(context.Context) error) error {
+	operation := commandName(args)
+	attrs := []attribute.KeyValue{semconv.DBSystemRedis, semconv.DBOperationName(operation)}
+	return i.run(ctx, operation, attrs, []string{statement(args)}, call)
+}
+
+// ProcessPipeline starts one client span for a pipeline of commands and calls call, which sends
+// them. The error returned by call is recorded and passed through.
+func (i *Instrumenter) ProcessPipeline(ctx context.Context, cmds [][]any, call func(context.Context) error) error {
+	attrs := []attribute.KeyValue{semconv.DBSystemRedis, semconv.DBOperationName(pipelineOperation)}
+	statements := make([]string, len(cmds))
+	for idx, args := range cmds {
+		statements[idx] = statement(args)
+	}
+	return i.run(ctx, pipelineOperation, attrs, statements, call, semconv.DBOperationBatchSize(len(cmds)))
+}
+
+func (i *Instrumenter) run(ctx context.Context, operation string, attrs []attribute.KeyValue, statements []string, call func(context.Context) error, extra ...attribute.KeyValue) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	spanAttrs := append(append([]attribute.KeyValue(nil), attrs...), extra...)
+	if i.maxStatement > 0 {
+		spanAttrs = append(spanAttrs, semconv.DBQueryText(truncate(strings.Join(statements, "\n"), i.maxStatement)))
+	}
+	ctx, span := i.tracer.Start(ctx, operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(spanAttrs...),
+	)
+	defer span.End()
+
+	start := time.Now()
+	err := call(ctx)
+	metricAttrs := attrs
+	if err != nil && !i.isIgnored(err) {
+		errorType := semconv.ErrorTypeKey.String(fmt.Sprintf("%T", err))
+		metricAttrs = append(append([]attribute.KeyValue(nil), attrs...), errorType)
+		span.SetAttributes(errorType)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		log := i.logger
+		if log == nil {
+			log = logger.Global()
+		}
+		log.Error().Ctx(ctx).
+			Str("db_system", "redis").
+			Str("db_operation_name", operation).
+			Err(err).
+			Msg("redis command failed")
+	}
+	i.duration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(metricAttrs...))
+	return err
+}
+
+func (i *Instrumenter) isIgnored(err error) bool {
+	for _, ignored := range i.ignored {
+		if errors.Is(err, ignored) {
+			return true
+		}
+	}
+	return false
+}
+
+func commandName(args []any) string {
+	if len(args) == 0 {
+		return "UNKNOWN"
+	}
+	return strings.ToUpper(fmt.Sprint(args[0]))
+}
+
+// statement renders args as the command name and its first argument, usually the key, with every
+// other argument replaced by "?" so values never reach the span. Commands carrying credentials keep
+// only their name.
+func statement(args []any) string {
+	if len(args) == 0 {
+		return ""
+	}
+	name := commandName(args)
+	parts := make([]string, 0, len(args))
+	parts = append(parts, name)
+	_, secret := secretCommands[name]
+	for idx, arg := range args[1:] {
+		if idx == 0 && !secret {
+			parts = append(parts, argString(arg))
+			continue
+		}
+		parts = append(parts, "?")
+	}
+	return strings.Join(parts, " ")
+}
+
+func argString(arg any) string {
+	switch v := arg.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
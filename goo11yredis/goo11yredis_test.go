@@ -0,0 +1,169 @@
+package goo11yredis
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mfahmialkautsar/goo11y/logger"
+	"github.com/mfahmialkautsar/goo11y/meter"
+	"github.com/mfahmialkautsar/goo11y/tracer"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.28.0"
+)
+
+var errNil = errors.New("redis: nil")
+
+type fixture struct {
+	inst     *Instrumenter
+	recorder *tracetest.SpanRecorder
+	reader   *sdkmetric.ManualReader
+	logs     *bytes.Buffer
+}
+
+func newFixture(t *testing.T, opts ...Option) fixture {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	var logs bytes.Buffer
+	log, err := logger.New(context.Background(), logger.Config{
+		Enabled: true,
+		Console: false,
+		Writers: []io.Writer{&logs},
+	})
+	if err != nil {
+		t.Fatalf("logger.New: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = tp.Shutdown(context.Background())
+		_ = mp.Shutdown(context.Background())
+		_ = log.Close()
+	})
+
+	inst, err := New(append([]Option{
+		WithTracerProvider(tracer.NewProvider(tp)),
+		WithMeterProvider(meter.NewProvider(mp)),
+		WithLogger(log),
+		WithIgnoredErrors(errNil),
+	}, opts...)...)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return fixture{inst: inst, recorder: recorder, reader: reader, logs: &logs}
+}
+
+func TestProcessRecordsSanitizedCommands(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	if err := f.inst.Process(ctx, []any{"set", "user:1", "secret-value", "EX", 60}, func(context.Context) error { return nil }); err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if err := f.inst.Process(ctx, []any{"auth", "admin", "hunter2"}, func(context.Context) error { return nil }); err != nil {
+		t.Fatalf("Process: %v", err)
+	}
+	if err := f.inst.Process(ctx, []any{"get", "user:2"}, func(context.Context) error { return errNil }); !errors.Is(err, errNil) {
+		t.Fatalf("Process error = %v, want %v", err, errNil)
+	}
+
+	spans := f.recorder.Ended()
+	if len(spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(spans))
+	}
+	want := []struct{ name, statement string }{
+		{"SET", "SET user:1 ? ? ?"},
+		{"AUTH", "AUTH ? ?"},
+		{"GET", "GET user:2"},
+	}
+	for idx, span := range spans {
+		if span.Name() != want[idx].name {
+			t.Fatalf("span %d name = %q, want %q", idx, span.Name(), want[idx].name)
+		}
+		var statement string
+		for _, attr := range span.Attributes() {
+			if attr.Key == semconv.DBQueryTextKey {
+				statement = attr.Value.AsString()
+			}
+		}
+		if statement != want[idx].statement {
+			t.Fatalf("span %d statement = %q, want %q", idx, statement, want[idx].statement)
+		}
+		if span.Status().Code == codes.Error {
+			t.Fatalf("span %d marked as failed", idx)
+		}
+	}
+	if f.logs.Len() != 0 {
+		t.Fatalf("expected no failure logs, got %s", f.logs.String())
+	}
+}
+
+func TestProcessPipelineRecordsFailure(t *testing.T) {
+	f := newFixture(t, WithMaxStatementLength(12))
+	ctx, parent := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "parent")
+	defer parent.End()
+
+	failure := errors.New("connection reset")
+	cmds := [][]any{{"incr", "hits"}, {"expire", "hits", 60}}
+	if err := f.inst.ProcessPipeline(ctx, cmds, func(context.Context) error { return failure }); !errors.Is(err, failure) {
+		t.Fatalf("ProcessPipeline error = %v, want %v", err, failure)
+	}
+
+	spans := f.recorder.Ended()
+	if len(spans) != 1 || spans[0].Name() != "PIPELINE" {
+		t.Fatalf("expected one PIPELINE span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Status().Code != codes.Error {
+		t.Fatalf("expected error status, got %v", span.Status())
+	}
+	attrs := make(map[string]string)
+	for _, attr := range span.Attributes() {
+		attrs[string(attr.Key)] = attr.Value.Emit()
+	}
+	if attrs[string(semconv.DBOperationBatchSizeKey)] != "2" {
+		t.Fatalf("batch size = %q", attrs[string(semconv.DBOperationBatchSizeKey)])
+	}
+	if got := attrs[string(semconv.DBQueryTextKey)]; got != "INCR hits\nEX" {
+		t.Fatalf("statement = %q", got)
+	}
+
+	logs := f.logs.String()
+	if !strings.Contains(logs, "redis command failed") || !strings.Contains(logs, parent.SpanContext().TraceID().String()) {
+		t.Fatalf("expected a trace-correlated failure log, got %s", logs)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := f.reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	var found bool
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name != semconv.DBClientOperationDurationName {
+				continue
+			}
+			hist, ok := m.Data.(metricdata.Histogram[float64])
+			if !ok || len(hist.DataPoints) != 1 {
+				t.Fatalf("unexpected duration data: %#v", m.Data)
+			}
+			if _, ok := hist.DataPoints[0].Attributes.Value(semconv.ErrorTypeKey); !ok {
+				t.Fatal("expected error.type on the failed pipeline")
+			}
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("duration histogram not recorded")
+	}
+}
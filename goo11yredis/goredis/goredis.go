@@ -0,0 +1,67 @@
+// Package goredis plugs goo11yredis into go-redis v9 as a redis.Hook, so every command and
+// pipeline a client sends gets a client span, the db.client.operation.duration histogram, and a
+// trace-correlated log entry on failure:
+//
+//	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+//	if err := goredis.Instrument(client); err != nil {
+//		return err
+//	}
+package goredis
+
+import (
+	"context"
+
+	"github.com/mfahmialkautsar/goo11y/goo11yredis"
+	"github.com/redis/go-redis/v9"
+)
+
+// Hook forwards go-redis commands and pipelines to an Instrumenter.
+type Hook struct {
+	inst *goo11yredis.Instrumenter
+}
+
+var _ redis.Hook = (*Hook)(nil)
+
+// NewHook returns a redis.Hook recording through inst. Build inst with
+// goo11yredis.WithIgnoredErrors(redis.Nil) so cache misses are not reported as failures;
+// Instrument does that for you.
+func NewHook(inst *goo11yredis.Instrumenter) *Hook {
+	return &Hook{inst: inst}
+}
+
+// Instrument creates an Instrumenter from opts, ignoring redis.Nil, and adds its Hook to client.
+func Instrument(client redis.UniversalClient, opts ...goo11yredis.Option) error {
+	inst, err := goo11yredis.New(append([]goo11yredis.Option{goo11yredis.WithIgnoredErrors(redis.Nil)}, opts...)...)
+	if err != nil {
+		return err
+	}
+	client.AddHook(NewHook(inst))
+	return nil
+}
+
+// DialHook leaves dials uninstrumented.
+func (h *Hook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// ProcessHook records each command.
+func (h *Hook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		return h.inst.Process(ctx, cmd.Args(), func(ctx context.Context) error {
+			return next(ctx, cmd)
+		})
+	}
+}
+
+// ProcessPipelineHook records each pipeline, including MULTI/EXEC transactions, as one span.
+func (h *Hook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		args := make([][]any, len(cmds))
+		for idx, cmd := range cmds {
+			args[idx] = cmd.Args()
+		}
+		return h.inst.ProcessPipeline(ctx, args, func(ctx context.Context) error {
+			return next(ctx, cmds)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package goredis
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/mfahmialkautsar/goo11y/goo11yredis"
+	"github.com/mfahmialkautsar/goo11y/logger"
+	"github.com/mfahmialkautsar/goo11y/meter"
+	"github.com/mfahmialkautsar/goo11y/tracer"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.28.0"
+)
+
+// stubHook answers every command in place of a server, failing the ones named in failures.
+type stubHook struct {
+	failures map[string]error
+}
+
+func (h stubHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(context.Context, string, string) (net.Conn, error) {
+		return nil, errors.New("unexpected dial")
+	}
+}
+
+func (h stubHook) ProcessHook(redis.ProcessHook) redis.ProcessHook {
+	return func(_ context.Context, cmd redis.Cmder) error {
+		err := h.failures[cmd.Name()]
+		cmd.SetErr(err)
+		return err
+	}
+}
+
+func (h stubHook) ProcessPipelineHook(redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(_ context.Context, cmds []redis.Cmder) error {
+		for _, cmd := range cmds {
+			if err := h.failures[cmd.Name()]; err != nil {
+				cmd.SetErr(err)
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func TestInstrumentRecordsClientCommands(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	mp := sdkmetric.NewMeterProvider()
+	var logs bytes.Buffer
+	log, err := logger.New(context.Background(), logger.Config{
+		Enabled: true,
+		Console: false,
+		Writers: []io.Writer{&logs},
+	})
+	if err != nil {
+		t.Fatalf("logger.New: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = tp.Shutdown(context.Background())
+		_ = mp.Shutdown(context.Background())
+		_ = log.Close()
+	})
+
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})
+	t.Cleanup(func() { _ = client.Close() })
+	if err := Instrument(client,
+		goo11yredis.WithTracerProvider(tracer.NewProvider(tp)),
+		goo11yredis.WithMeterProvider(meter.NewProvider(mp)),
+		goo11yredis.WithLogger(log),
+	); err != nil {
+		t.Fatalf("Instrument: %v", err)
+	}
+	failure := errors.New("connection reset")
+	client.AddHook(stubHook{failures: map[string]error{"get": redis.Nil, "incr": failure}})
+
+	ctx := context.Background()
+	if err := client.Set(ctx, "user:1", "secret-value", 0).Err(); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := client.Get(ctx, "user:2").Err(); !errors.Is(err, redis.Nil) {
+		t.Fatalf("Get error = %v, want redis.Nil", err)
+	}
+	_, err = client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Incr(ctx, "hits")
+		pipe.Expire(ctx, "hits", 0)
+		return nil
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("Pipelined error = %v, want %v", err, failure)
+	}
+
+	spans := recorder.Ended()
+	want := []struct {
+		name      string
+		statement string
+		failed    bool
+	}{
+		{"SET", "SET user:1 ?", false},
+		{"GET", "GET user:2", false},
+		{"PIPELINE", "INCR hits\nEXPIRE hits ?", true},
+	}
+	if len(spans) != len(want) {
+		t.Fatalf("expected %d spans, got %d", len(want), len(spans))
+	}
+	for idx, span := range spans {
+		if span.Name() != want[idx].name {
+			t.Fatalf("span %d name = %q, want %q", idx, span.Name(), want[idx].name)
+		}
+		var statement string
+		for _, attr := range span.Attributes() {
+			if attr.Key == semconv.DBQueryTextKey {
+				statement = attr.Value.AsString()
+			}
+		}
+		if statement != want[idx].statement {
+			t.Fatalf("span %d statement = %q, want %q", idx, statement, want[idx].statement)
+		}
+		if failed := span.Status().Code == codes.Error; failed != want[idx].failed {
+			t.Fatalf("span %d failed = %v, want %v", idx, failed, want[idx].failed)
+		}
+	}
+	if got := strings.Count(logs.String(), "redis command failed"); got != 1 {
+		t.Fatalf("expected one failure log for the pipeline, got %d: %s", got, logs.String())
+	}
+}
@@ -0,0 +1,150 @@
+package goo11ytest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mfahmialkautsar/goo11y/internal/testutil/inmemory"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// AssertSpan fails the test unless an ended span with the given name carries every supplied attribute.
+// Returns the first matching span.
+func (tt *Telemetry) AssertSpan(name string, attrs ...attribute.KeyValue) sdktrace.ReadOnlySpan {
+	tt.t.Helper()
+
+	found := false
+	for _, span := range tt.Spans.Ended() {
+		if span.Name() != name {
+			continue
+		}
+		found = true
+		if hasAttributes(attribute.NewSet(span.Attributes()...), attrs) {
+			return span
+		}
+	}
+	if !found {
+		tt.t.Fatalf("goo11ytest: span %q not recorded", name)
+	} else {
+		tt.t.Fatalf("goo11ytest: span %q recorded without attributes %v", name, attrs)
+	}
+	return nil
+}
+
+// AssertMetricValue fails the test unless the named metric has a data point with the supplied
+// attributes whose value equals want. Sums and gauges compare their value; histograms compare their sum.
+func (tt *Telemetry) AssertMetricValue(name string, want float64, attrs ...attribute.KeyValue) {
+	tt.t.Helper()
+
+	rm, err := inmemory.GetMetrics(context.Background(), tt.Reader)
+	if err != nil {
+		tt.t.Fatalf("goo11ytest: %v", err)
+		return
+	}
+	m, ok := inmemory.FindMetricByName(rm, name)
+	if !ok {
+		tt.t.Fatalf("goo11ytest: metric %q not recorded", name)
+		return
+	}
+
+	values := metricValues(m.Data, attrs)
+	if len(values) == 0 {
+		tt.t.Fatalf("goo11ytest: metric %q has no data point with attributes %v", name, attrs)
+		return
+	}
+	for _, got := range values {
+		if got == want {
+			return
+		}
+	}
+	tt.t.Fatalf("goo11ytest: metric %q value mismatch: got %v, want %v", name, values, want)
+}
+
+// AssertLogged fails the test unless a log entry with the given message contains every supplied field.
+// Field values are compared by their formatted representation. Returns the first matching entry.
+func (tt *Telemetry) AssertLogged(message string, fields map[string]any) map[string]any {
+	tt.t.Helper()
+
+	found := false
+	for _, entry := range tt.Logs.Entries() {
+		if entry[zerolog.MessageFieldName] != message {
+			continue
+		}
+		found = true
+		if hasFields(entry, fields) {
+			return entry
+		}
+	}
+	if !found {
+		tt.t.Fatalf("goo11ytest: log %q not written", message)
+	} else {
+		tt.t.Fatalf("goo11ytest: log %q written without fields %v", message, fields)
+	}
+	return nil
+}
+
+func hasAttributes(set attribute.Set, attrs []attribute.KeyValue) bool {
+	for _, attr := range attrs {
+		value, ok := set.Value(attr.Key)
+		if !ok || value != attr.Value {
+			return false
+		}
+	}
+	return true
+}
+
+func hasFields(entry map[string]any, fields map[string]any) bool {
+	for key, want := range fields {
+		got, ok := entry[key]
+		if !ok || fmt.Sprint(got) != fmt.Sprint(want) {
+			return false
+		}
+	}
+	return true
+}
+
+func metricValues(data metricdata.Aggregation, attrs []attribute.KeyValue) []float64 {
+	var values []float64
+	switch d := data.(type) {
+	case metricdata.Sum[int64]:
+		for _, dp := range d.DataPoints {
+			if hasAttributes(dp.Attributes, attrs) {
+				values = append(values, float64(dp.Value))
+			}
+		}
+	case metricdata.Sum[float64]:
+		for _, dp := range d.DataPoints {
+			if hasAttributes(dp.Attributes, attrs) {
+				values = append(values, dp.Value)
+			}
+		}
+	case metricdata.Gauge[int64]:
+		for _, dp := range d.DataPoints {
+			if hasAttributes(dp.Attributes, attrs) {
+				values = append(values, float64(dp.Value))
+			}
+		}
+	case metricdata.Gauge[float64]:
+		for _, dp := range d.DataPoints {
+			if hasAttributes(dp.Attributes, attrs) {
+				values = append(values, dp.Value)
+			}
+		}
+	case metricdata.Histogram[int64]:
+		for _, dp := range d.DataPoints {
+			if hasAttributes(dp.Attributes, attrs) {
+				values = append(values, float64(dp.Sum))
+			}
+		}
+	case metricdata.Histogram[float64]:
+		for _, dp := range d.DataPoints {
+			if hasAttributes(dp.Attributes, attrs) {
+				values = append(values, dp.Sum)
+			}
+		}
+	}
+	return values
+}
@@ -0,0 +1,148 @@
+package goo11ytest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/mfahmialkautsar/goo11y/clock"
+)
+
+// FakeClock is a clock.Clock that only moves when Advance or Set is called. Timers and tickers fire
+// synchronously inside those calls, so code waiting on them resumes without real delays.
+type FakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter
+}
+
+var _ clock.Clock = (*FakeClock)(nil)
+
+// NewFakeClock returns a FakeClock reading start.
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start}
+}
+
+// Now returns the fake time.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTimer returns a timer firing once the fake time reaches Now plus d.
+func (c *FakeClock) NewTimer(d time.Duration) clock.Timer {
+	return c.schedule(d, 0)
+}
+
+// NewTicker returns a ticker firing every d of fake time.
+func (c *FakeClock) NewTicker(d time.Duration) clock.Ticker {
+	if d <= 0 {
+		panic("goo11ytest: non-positive interval for NewTicker")
+	}
+	return fakeTicker{c.schedule(d, d)}
+}
+
+// Advance moves the fake time forward by d, firing every timer and ticker due by then.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.Set(c.Now().Add(d))
+}
+
+// Set moves the fake time to t, firing every timer and ticker due by then. Moving backwards fires nothing.
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if t.After(c.now) {
+		c.now = t
+	}
+	remaining := c.waiters[:0]
+	for _, w := range c.waiters {
+		for !w.when.After(c.now) {
+			select {
+			case w.ch <- w.when:
+			default:
+			}
+			if w.period <= 0 {
+				break
+			}
+			w.when = w.when.Add(w.period)
+		}
+		if w.when.After(c.now) {
+			remaining = append(remaining, w)
+		}
+	}
+	clear(c.waiters[len(remaining):])
+	c.waiters = remaining
+}
+
+// Waiters reports how many timers and tickers are pending, so a test can wait until the code under
+// test has started waiting before calling Advance.
+func (c *FakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+func (c *FakeClock) schedule(d, period time.Duration) *fakeWaiter {
+	w := &fakeWaiter{clock: c, ch: make(chan time.Time, 1)}
+	w.reset(d, period)
+	return w
+}
+
+func (c *FakeClock) remove(w *fakeWaiter) bool {
+	for i, pending := range c.waiters {
+		if pending == w {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+type fakeWaiter struct {
+	clock  *FakeClock
+	ch     chan time.Time
+	when   time.Time
+	period time.Duration
+}
+
+func (w *fakeWaiter) C() <-chan time.Time { return w.ch }
+
+func (w *fakeWaiter) Stop() bool {
+	w.clock.mu.Lock()
+	defer w.clock.mu.Unlock()
+	return w.clock.remove(w)
+}
+
+func (w *fakeWaiter) Reset(d time.Duration) bool {
+	return w.reset(d, 0)
+}
+
+// reset reschedules w to fire d from now, then every period when period is positive.
+func (w *fakeWaiter) reset(d, period time.Duration) bool {
+	c := w.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	active := c.remove(w)
+	w.when = c.now.Add(d)
+	w.period = period
+	if d <= 0 && period <= 0 {
+		select {
+		case w.ch <- c.now:
+		default:
+		}
+		return active
+	}
+	c.waiters = append(c.waiters, w)
+	return active
+}
+
+type fakeTicker struct{ *fakeWaiter }
+
+func (t fakeTicker) Stop() { t.fakeWaiter.Stop() }
+
+func (t fakeTicker) Reset(d time.Duration) {
+	if d <= 0 {
+		panic("goo11ytest: non-positive interval for Ticker.Reset")
+	}
+	t.fakeWaiter.reset(d, d)
+}
@@ -0,0 +1,87 @@
+package goo11ytest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/mfahmialkautsar/goo11y/logger"
+)
+
+func TestFakeClockFiresTimersOnAdvance(t *testing.T) {
+	start := time.Date(2024, 6, 2, 12, 0, 0, 0, time.UTC)
+	clk := NewFakeClock(start)
+
+	timer := clk.NewTimer(time.Second)
+	ticker := clk.NewTicker(400 * time.Millisecond)
+	if clk.Waiters() != 2 {
+		t.Fatalf("expected 2 waiters, got %d", clk.Waiters())
+	}
+
+	clk.Advance(500 * time.Millisecond)
+	select {
+	case <-timer.C():
+		t.Fatal("timer fired early")
+	default:
+	}
+	if got := <-ticker.C(); !got.Equal(start.Add(400 * time.Millisecond)) {
+		t.Fatalf("unexpected tick %v", got)
+	}
+
+	clk.Advance(500 * time.Millisecond)
+	if got := <-timer.C(); !got.Equal(start.Add(time.Second)) {
+		t.Fatalf("unexpected timer fire %v", got)
+	}
+	if timer.Stop() {
+		t.Fatal("expected fired timer to be inactive")
+	}
+	ticker.Stop()
+	if clk.Waiters() != 0 {
+		t.Fatalf("expected no waiters, got %d", clk.Waiters())
+	}
+	if !clk.Now().Equal(start.Add(time.Second)) {
+		t.Fatalf("unexpected now %v", clk.Now())
+	}
+}
+
+func TestFakeClockDrivesFileRollover(t *testing.T) {
+	dir := t.TempDir()
+	clk := NewFakeClock(time.Date(2024, 6, 2, 23, 59, 0, 0, time.Local))
+	log, err := logger.New(context.Background(), logger.Config{
+		Enabled:     true,
+		ServiceName: "clock-test",
+		Console:     false,
+		File:        logger.FileConfig{Enabled: true, Directory: dir, Buffer: 4},
+		Clock:       clk,
+	})
+	if err != nil {
+		t.Fatalf("logger.New: %v", err)
+	}
+
+	log.Info().Msg("before midnight")
+	// The file writer stamps entries when it drains its queue, so wait for it before moving the clock.
+	first := filepath.Join(dir, "2024-06-02.log")
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if info, err := os.Stat(first); err == nil && info.Size() > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", first)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	clk.Advance(2 * time.Minute)
+	log.Info().Msg("after midnight")
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	for _, name := range []string{"2024-06-02.log", "2024-06-03.log"} {
+		if info, err := os.Stat(filepath.Join(dir, name)); err != nil || info.Size() == 0 {
+			t.Fatalf("expected %s to hold an entry: %v", name, err)
+		}
+	}
+}
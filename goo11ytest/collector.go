@@ -0,0 +1,306 @@
+package goo11ytest
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	collog "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetric "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// Collector is an in-process OTLP receiver serving both HTTP and gRPC.
+// It captures every decoded export request so tests can assert on delivered telemetry.
+type Collector struct {
+	httpServer *httptest.Server
+	grpcServer *grpc.Server
+	listener   net.Listener
+
+	failing atomic.Bool
+
+	mu      sync.Mutex
+	traces  []*coltrace.ExportTraceServiceRequest
+	metrics []*colmetric.ExportMetricsServiceRequest
+	logs    []*collog.ExportLogsServiceRequest
+}
+
+// NewCollector starts a collector that is stopped when the test ends.
+func NewCollector(t testing.TB) *Collector {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("goo11ytest: listen: %v", err)
+	}
+
+	c := &Collector{listener: listener}
+
+	c.grpcServer = grpc.NewServer()
+	coltrace.RegisterTraceServiceServer(c.grpcServer, collectorTraceService{c: c})
+	colmetric.RegisterMetricsServiceServer(c.grpcServer, collectorMetricsService{c: c})
+	collog.RegisterLogsServiceServer(c.grpcServer, collectorLogsService{c: c})
+	go func() {
+		_ = c.grpcServer.Serve(listener)
+	}()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/traces", c.handleHTTP(
+		func() proto.Message { return new(coltrace.ExportTraceServiceRequest) },
+		func() proto.Message { return new(coltrace.ExportTraceServiceResponse) },
+	))
+	mux.HandleFunc("/v1/metrics", c.handleHTTP(
+		func() proto.Message { return new(colmetric.ExportMetricsServiceRequest) },
+		func() proto.Message { return new(colmetric.ExportMetricsServiceResponse) },
+	))
+	mux.HandleFunc("/v1/logs", c.handleHTTP(
+		func() proto.Message { return new(collog.ExportLogsServiceRequest) },
+		func() proto.Message { return new(collog.ExportLogsServiceResponse) },
+	))
+	c.httpServer = httptest.NewServer(mux)
+
+	t.Cleanup(c.Close)
+	return c
+}
+
+// HTTPEndpoint returns the base URL of the OTLP/HTTP receiver.
+func (c *Collector) HTTPEndpoint() string {
+	return c.httpServer.URL
+}
+
+// GRPCEndpoint returns the host:port of the OTLP/gRPC receiver.
+func (c *Collector) GRPCEndpoint() string {
+	return c.listener.Addr().String()
+}
+
+// SetFailing makes the collector reject exports (HTTP 503, gRPC Unavailable) while still recording them.
+func (c *Collector) SetFailing(failing bool) {
+	c.failing.Store(failing)
+}
+
+// Close stops both receivers.
+func (c *Collector) Close() {
+	c.httpServer.Close()
+	c.grpcServer.Stop()
+}
+
+// Reset discards every captured request.
+func (c *Collector) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.traces = nil
+	c.metrics = nil
+	c.logs = nil
+}
+
+// TraceRequests returns the captured trace export requests.
+func (c *Collector) TraceRequests() []*coltrace.ExportTraceServiceRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*coltrace.ExportTraceServiceRequest(nil), c.traces...)
+}
+
+// MetricRequests returns the captured metric export requests.
+func (c *Collector) MetricRequests() []*colmetric.ExportMetricsServiceRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*colmetric.ExportMetricsServiceRequest(nil), c.metrics...)
+}
+
+// LogRequests returns the captured log export requests.
+func (c *Collector) LogRequests() []*collog.ExportLogsServiceRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*collog.ExportLogsServiceRequest(nil), c.logs...)
+}
+
+// SpanNames lists the names of every captured span in arrival order.
+func (c *Collector) SpanNames() []string {
+	var names []string
+	for _, req := range c.TraceRequests() {
+		for _, rs := range req.GetResourceSpans() {
+			for _, ss := range rs.GetScopeSpans() {
+				for _, span := range ss.GetSpans() {
+					names = append(names, span.GetName())
+				}
+			}
+		}
+	}
+	return names
+}
+
+// MetricNames lists the names of every captured metric in arrival order.
+func (c *Collector) MetricNames() []string {
+	var names []string
+	for _, req := range c.MetricRequests() {
+		for _, rm := range req.GetResourceMetrics() {
+			for _, sm := range rm.GetScopeMetrics() {
+				for _, m := range sm.GetMetrics() {
+					names = append(names, m.GetName())
+				}
+			}
+		}
+	}
+	return names
+}
+
+// LogBodies lists the string bodies of every captured log record in arrival order.
+func (c *Collector) LogBodies() []string {
+	var bodies []string
+	for _, req := range c.LogRequests() {
+		for _, rl := range req.GetResourceLogs() {
+			for _, sl := range rl.GetScopeLogs() {
+				for _, record := range sl.GetLogRecords() {
+					bodies = append(bodies, record.GetBody().GetStringValue())
+				}
+			}
+		}
+	}
+	return bodies
+}
+
+// WaitFor polls cond until it returns true or timeout elapses, reporting whether cond was satisfied.
+func (c *Collector) WaitFor(timeout time.Duration, cond func(*Collector) bool) bool {
+	deadline := time.Now().Add(timeout)
+	for {
+		if cond(c) {
+			return true
+		}
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func (c *Collector) record(msg proto.Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	switch req := proto.Clone(msg).(type) {
+	case *coltrace.ExportTraceServiceRequest:
+		c.traces = append(c.traces, req)
+	case *colmetric.ExportMetricsServiceRequest:
+		c.metrics = append(c.metrics, req)
+	case *collog.ExportLogsServiceRequest:
+		c.logs = append(c.logs, req)
+	}
+}
+
+func (c *Collector) handleHTTP(newRequest, newResponse func() proto.Message) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var body io.Reader = r.Body
+		if strings.EqualFold(r.Header.Get("Content-Encoding"), "gzip") {
+			gz, err := gzip.NewReader(r.Body)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			defer func() {
+				_ = gz.Close()
+			}()
+			body = gz
+		}
+		payload, err := io.ReadAll(body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		isJSON := strings.HasPrefix(r.Header.Get("Content-Type"), "application/json")
+		req := newRequest()
+		if isJSON {
+			err = protojson.Unmarshal(payload, req)
+		} else {
+			err = proto.Unmarshal(payload, req)
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		c.record(req)
+
+		if c.failing.Load() {
+			http.Error(w, "collector unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		var out []byte
+		if isJSON {
+			w.Header().Set("Content-Type", "application/json")
+			out, err = protojson.Marshal(newResponse())
+		} else {
+			w.Header().Set("Content-Type", "application/x-protobuf")
+			out, err = proto.Marshal(newResponse())
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(out)
+	}
+}
+
+func (c *Collector) grpcResult() error {
+	if c.failing.Load() {
+		return status.Error(codes.Unavailable, "collector unavailable")
+	}
+	return nil
+}
+
+type collectorTraceService struct {
+	coltrace.UnimplementedTraceServiceServer
+	c *Collector
+}
+
+func (s collectorTraceService) Export(_ context.Context, req *coltrace.ExportTraceServiceRequest) (*coltrace.ExportTraceServiceResponse, error) {
+	s.c.record(req)
+	if err := s.c.grpcResult(); err != nil {
+		return nil, err
+	}
+	return &coltrace.ExportTraceServiceResponse{}, nil
+}
+
+type collectorMetricsService struct {
+	colmetric.UnimplementedMetricsServiceServer
+	c *Collector
+}
+
+func (s collectorMetricsService) Export(_ context.Context, req *colmetric.ExportMetricsServiceRequest) (*colmetric.ExportMetricsServiceResponse, error) {
+	s.c.record(req)
+	if err := s.c.grpcResult(); err != nil {
+		return nil, err
+	}
+	return &colmetric.ExportMetricsServiceResponse{}, nil
+}
+
+type collectorLogsService struct {
+	collog.UnimplementedLogsServiceServer
+	c *Collector
+}
+
+func (s collectorLogsService) Export(_ context.Context, req *collog.ExportLogsServiceRequest) (*collog.ExportLogsServiceResponse, error) {
+	s.c.record(req)
+	if err := s.c.grpcResult(); err != nil {
+		return nil, err
+	}
+	return &collog.ExportLogsServiceResponse{}, nil
+}
@@ -0,0 +1,119 @@
+package goo11ytest
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/mfahmialkautsar/goo11y"
+	"github.com/mfahmialkautsar/goo11y/logger"
+	"github.com/mfahmialkautsar/goo11y/meter"
+	"github.com/mfahmialkautsar/goo11y/tracer"
+)
+
+func TestCollectorReceivesAllSignals(t *testing.T) {
+	collector := NewCollector(t)
+	ctx := context.Background()
+
+	tele, err := goo11y.New(ctx, goo11y.Config{
+		Resource: goo11y.ResourceConfig{ServiceName: "collector-test"},
+		Logger: logger.Config{
+			Enabled: true,
+			Console: false,
+			OTLP: logger.OTLPConfig{
+				Enabled:  true,
+				Endpoint: collector.HTTPEndpoint(),
+				Protocol: "http",
+				QueueDir: t.TempDir(),
+			},
+		},
+		Tracer: tracer.Config{
+			Enabled: true,
+			Export: tracer.ExportConfig{
+				Backend: tracer.BackendConfig{
+					Enabled:  true,
+					Endpoint: collector.GRPCEndpoint(),
+					Insecure: true,
+					Protocol: "grpc",
+					Failover: tracer.FailoverConfig{Directory: t.TempDir()},
+				},
+			},
+		},
+		Meter: meter.Config{
+			Enabled:  true,
+			Endpoint: collector.HTTPEndpoint(),
+			Protocol: "http",
+			QueueDir: t.TempDir(),
+		},
+	})
+	if err != nil {
+		t.Fatalf("goo11y.New: %v", err)
+	}
+
+	ctx, span := tele.Tracer().Tracer("collector-test").Start(ctx, "collector-span")
+	tele.Logger().Info().Ctx(ctx).Msg("collector-log")
+	span.End()
+
+	counter, err := tele.Meter().Meter("collector-test").Int64Counter("collector.requests")
+	if err != nil {
+		t.Fatalf("Int64Counter: %v", err)
+	}
+	counter.Add(ctx, 1)
+
+	if err := tele.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+	if err := tele.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	if !collector.WaitFor(2*time.Second, func(c *Collector) bool {
+		return slices.Contains(c.SpanNames(), "collector-span") &&
+			slices.Contains(c.MetricNames(), "collector.requests") &&
+			slices.Contains(c.LogBodies(), "collector-log")
+	}) {
+		t.Fatalf("collector missing telemetry: spans=%v metrics=%v logs=%v",
+			collector.SpanNames(), collector.MetricNames(), collector.LogBodies())
+	}
+}
+
+func TestCollectorFailingRejectsExports(t *testing.T) {
+	collector := NewCollector(t)
+	collector.SetFailing(true)
+
+	provider, err := tracer.Setup(context.Background(), tracer.Config{
+		Enabled:     true,
+		ServiceName: "collector-failing",
+		Export: tracer.ExportConfig{
+			Backend: tracer.BackendConfig{
+				Enabled:  true,
+				Endpoint: collector.HTTPEndpoint(),
+				Protocol: "http",
+				Failover: tracer.FailoverConfig{Directory: t.TempDir()},
+			},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatalf("tracer.Setup: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = provider.Shutdown(context.Background())
+	})
+
+	_, span := provider.Tracer("collector-failing").Start(context.Background(), "rejected-span")
+	span.End()
+
+	if err := provider.ForceFlush(context.Background()); err == nil {
+		t.Fatal("expected ForceFlush to surface collector failure")
+	}
+
+	if !slices.Contains(collector.SpanNames(), "rejected-span") {
+		t.Fatalf("expected rejected span to be recorded, got %v", collector.SpanNames())
+	}
+
+	collector.Reset()
+	if len(collector.TraceRequests()) != 0 {
+		t.Fatal("expected Reset to discard captured requests")
+	}
+}
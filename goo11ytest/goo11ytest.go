@@ -0,0 +1,118 @@
+// Package goo11ytest provides in-memory telemetry backends and assertion helpers
+// for unit testing code instrumented with goo11y.
+package goo11ytest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/mfahmialkautsar/goo11y"
+	"github.com/mfahmialkautsar/goo11y/logger"
+	"github.com/mfahmialkautsar/goo11y/meter"
+	"github.com/mfahmialkautsar/goo11y/tracer"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// Telemetry wraps a goo11y.Telemetry whose logger, tracer, and meter are backed by in-memory sinks.
+type Telemetry struct {
+	*goo11y.Telemetry
+
+	Spans  *tracetest.SpanRecorder
+	Reader *sdkmetric.ManualReader
+	Logs   *LogBuffer
+
+	t testing.TB
+}
+
+// New builds an in-memory Telemetry whose resources are released when the test ends.
+func New(t testing.TB) *Telemetry {
+	t.Helper()
+
+	logs := &LogBuffer{}
+	log, err := logger.New(context.Background(), logger.Config{
+		Enabled:     true,
+		Level:       "trace",
+		ServiceName: "goo11ytest",
+		Environment: "test",
+		Console:     false,
+		Writers:     []io.Writer{logs},
+	})
+	if err != nil {
+		t.Fatalf("goo11ytest: logger: %v", err)
+	}
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	t.Cleanup(func() {
+		ctx := context.Background()
+		_ = tp.Shutdown(ctx)
+		_ = mp.Shutdown(ctx)
+		_ = log.Close()
+	})
+
+	return &Telemetry{
+		Telemetry: goo11y.FromComponents(log, tracer.NewProvider(tp), meter.NewProvider(mp), nil),
+		Spans:     recorder,
+		Reader:    reader,
+		Logs:      logs,
+		t:         t,
+	}
+}
+
+// LogBuffer is a concurrency-safe writer that retains every JSON log line written to it.
+type LogBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+// Write appends p to the buffer.
+func (b *LogBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+// String returns the raw buffered output.
+func (b *LogBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// Reset discards all buffered output.
+func (b *LogBuffer) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf.Reset()
+}
+
+// Entries decodes every buffered JSON log line. Lines that are not valid JSON are skipped.
+func (b *LogBuffer) Entries() []map[string]any {
+	b.mu.Lock()
+	data := append([]byte(nil), b.buf.Bytes()...)
+	b.mu.Unlock()
+
+	var entries []map[string]any
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal(line, &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
@@ -0,0 +1,71 @@
+package goo11ytest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+type recordingTB struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Fatalf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestTelemetryAssertions(t *testing.T) {
+	tt := New(t)
+	ctx := context.Background()
+
+	ctx, span := tt.Tracer().Tracer("goo11ytest").Start(ctx, "checkout")
+	span.SetAttributes(attribute.String("tenant", "acme"))
+	tt.Logger().Info().Ctx(ctx).Str("order", "42").Msg("order placed")
+	span.End()
+
+	counter, err := tt.Meter().Meter("goo11ytest").Int64Counter("orders")
+	if err != nil {
+		t.Fatalf("Int64Counter: %v", err)
+	}
+	counter.Add(ctx, 2, metric.WithAttributes(attribute.String("tenant", "acme")))
+	counter.Add(ctx, 1, metric.WithAttributes(attribute.String("tenant", "other")))
+
+	tt.AssertSpan("checkout", attribute.String("tenant", "acme"))
+	tt.AssertMetricValue("orders", 2, attribute.String("tenant", "acme"))
+	entry := tt.AssertLogged("order placed", map[string]any{"order": "42", "level": "info"})
+	if entry["trace_id"] != span.SpanContext().TraceID().String() {
+		t.Fatalf("expected log entry to carry trace id, got %v", entry["trace_id"])
+	}
+}
+
+func TestTelemetryAssertionsReportFailures(t *testing.T) {
+	tt := New(t)
+	rec := &recordingTB{TB: t}
+	tt.t = rec
+
+	tt.AssertSpan("missing")
+	tt.AssertMetricValue("missing", 1)
+	tt.AssertLogged("missing", nil)
+
+	if len(rec.failures) != 3 {
+		t.Fatalf("expected 3 failures, got %v", rec.failures)
+	}
+}
+
+func TestLogBufferReset(t *testing.T) {
+	tt := New(t)
+	tt.Logger().Info().Msg("first")
+	if len(tt.Logs.Entries()) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(tt.Logs.Entries()))
+	}
+	tt.Logs.Reset()
+	if tt.Logs.String() != "" {
+		t.Fatalf("expected empty buffer, got %q", tt.Logs.String())
+	}
+}
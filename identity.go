@@ -0,0 +1,59 @@
+package goo11y
+
+import (
+	"crypto/rand"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ProcessRunIDKey identifies a single run of the process; unlike service.instance.id it changes on every restart.
+const ProcessRunIDKey = attribute.Key("process.run_id")
+
+// InstanceIDEnv is read for service.instance.id when ResourceConfig.InstanceID is empty.
+const InstanceIDEnv = "SERVICE_INSTANCE_ID"
+
+const (
+	instanceIDTag = "service_instance_id"
+	runIDTag      = "process_run_id"
+)
+
+var (
+	processIDsOnce  sync.Once
+	processInstance string
+	processRun      string
+)
+
+// processIDs returns the IDs generated once per process, so every Telemetry built in it agrees.
+func processIDs() (instanceID, runID string) {
+	processIDsOnce.Do(func() {
+		processInstance = newUUID()
+		processRun = newUUID()
+	})
+	return processInstance, processRun
+}
+
+func (c *ResourceConfig) resolveIdentity() {
+	instanceID, runID := processIDs()
+	if c.InstanceID == "" {
+		c.InstanceID = strings.TrimSpace(os.Getenv(InstanceIDEnv))
+	}
+	if c.InstanceID == "" {
+		c.InstanceID = instanceID
+	}
+	if c.RunID == "" {
+		c.RunID = runID
+	}
+}
+
+// newUUID returns a random RFC 4122 version 4 UUID.
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
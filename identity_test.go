@@ -0,0 +1,72 @@
+package goo11y
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/mfahmialkautsar/goo11y/internal/testutil"
+	semconv "go.opentelemetry.io/otel/semconv/v1.28.0"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestApplyDefaultsGeneratesStableProcessIDs(t *testing.T) {
+	t.Setenv(InstanceIDEnv, "")
+
+	first := Config{}
+	first.applyDefaults()
+	second := Config{}
+	second.applyDefaults()
+
+	if !uuidPattern.MatchString(first.Resource.InstanceID) {
+		t.Fatalf("instance id %q is not a UUID", first.Resource.InstanceID)
+	}
+	if !uuidPattern.MatchString(first.Resource.RunID) {
+		t.Fatalf("run id %q is not a UUID", first.Resource.RunID)
+	}
+	if first.Resource.InstanceID == first.Resource.RunID {
+		t.Fatal("instance and run ids should be generated independently")
+	}
+	if second.Resource.InstanceID != first.Resource.InstanceID || second.Resource.RunID != first.Resource.RunID {
+		t.Fatal("ids should be stable within the process")
+	}
+	if first.Logger.ServiceInstanceID != first.Resource.InstanceID || first.Logger.RunID != first.Resource.RunID {
+		t.Fatalf("logger ids not propagated: %q %q", first.Logger.ServiceInstanceID, first.Logger.RunID)
+	}
+	if first.Profiler.Tags[instanceIDTag] != first.Resource.InstanceID || first.Profiler.Tags[runIDTag] != first.Resource.RunID {
+		t.Fatalf("profiler tags not propagated: %v", first.Profiler.Tags)
+	}
+}
+
+func TestApplyDefaultsInstanceIDOverrides(t *testing.T) {
+	t.Setenv(InstanceIDEnv, "orders-7d9f")
+
+	fromEnv := Config{}
+	fromEnv.applyDefaults()
+	if fromEnv.Resource.InstanceID != "orders-7d9f" {
+		t.Fatalf("expected instance id from env, got %q", fromEnv.Resource.InstanceID)
+	}
+
+	explicit := Config{Resource: ResourceConfig{InstanceID: "replica-2", RunID: "run-9"}}
+	explicit.applyDefaults()
+	if explicit.Resource.InstanceID != "replica-2" || explicit.Resource.RunID != "run-9" {
+		t.Fatalf("explicit ids overridden: %q %q", explicit.Resource.InstanceID, explicit.Resource.RunID)
+	}
+}
+
+func TestBuildResourceIncludesProcessIDs(t *testing.T) {
+	cfg := Config{Resource: ResourceConfig{ServiceName: "svc", InstanceID: "replica-2", RunID: "run-9"}}
+
+	res, err := buildResource(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("buildResource: %v", err)
+	}
+	attrs := testutil.AttrsToMap(res.Attributes())
+	if got := attrs[string(semconv.ServiceInstanceIDKey)]; got != "replica-2" {
+		t.Fatalf("service.instance.id = %v", got)
+	}
+	if got := attrs[string(ProcessRunIDKey)]; got != "run-9" {
+		t.Fatalf("process.run_id = %v", got)
+	}
+}
@@ -0,0 +1,97 @@
+package goo11y
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	initInstrumentation = "github.com/mfahmialkautsar/goo11y/init"
+	initSpanName        = "telemetry.init"
+	initFailedKey       = attribute.Key("telemetry.init.failed")
+)
+
+// initStep is one timed stage of New, such as building the resource or setting up the tracer.
+type initStep struct {
+	name       string
+	start, end time.Time
+	err        error
+}
+
+// initRecorder times the stages of New for Config.TraceInit.
+type initRecorder struct {
+	now   func() time.Time
+	start time.Time
+	steps []initStep
+}
+
+func newInitRecorder(now func() time.Time) *initRecorder {
+	return &initRecorder{now: now, start: now()}
+}
+
+// step runs fn as the stage name and returns its error.
+func (r *initRecorder) step(name string, fn func() error) error {
+	step := initStep{name: name, start: r.now()}
+	step.err = fn()
+	step.end = r.now()
+	r.steps = append(r.steps, step)
+	return step.err
+}
+
+// finishInit reports the stages recorded by rec when Config.TraceInit is set and returns err
+// unchanged. The telemetry.init span needs the tracer, so stages before it are recorded with their
+// measured timestamps once it is ready; without a tracer only the summary line is logged.
+func (t *Telemetry) finishInit(ctx context.Context, rec *initRecorder, err error) error {
+	if !t.cfg.TraceInit {
+		return err
+	}
+	end := rec.now()
+	var failed []string
+	for _, step := range rec.steps {
+		if step.err != nil {
+			failed = append(failed, step.name)
+		}
+	}
+
+	if t.Tracer() != nil {
+		tr := t.Tracer().Tracer(initInstrumentation)
+		spanCtx, span := tr.Start(ctx, initSpanName, trace.WithTimestamp(rec.start))
+		for _, step := range rec.steps {
+			_, child := tr.Start(spanCtx, initSpanName+"."+step.name, trace.WithTimestamp(step.start))
+			if step.err != nil {
+				child.RecordError(step.err)
+				child.SetStatus(codes.Error, step.err.Error())
+			}
+			child.End(trace.WithTimestamp(step.end))
+		}
+		if len(failed) > 0 {
+			span.SetAttributes(initFailedKey.StringSlice(failed))
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End(trace.WithTimestamp(end))
+		if err != nil {
+			// New discards the components on failure, so the span must leave now.
+			_ = t.Tracer().ForceFlush(ctx)
+		}
+	}
+
+	if t.Logger() != nil {
+		durations := zerolog.Dict()
+		for _, step := range rec.steps {
+			durations = durations.Dur(step.name, step.end.Sub(step.start))
+		}
+		event := t.Logger().Info()
+		msg := "telemetry initialized"
+		if err != nil {
+			event = t.Logger().Error().Err(err).Strs("failed", failed)
+			msg = "telemetry initialization failed"
+		}
+		event.Ctx(ctx).Dur("duration", end.Sub(rec.start)).Dict("components", durations).Msg(msg)
+	}
+	return err
+}
@@ -0,0 +1,88 @@
+package goo11y
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mfahmialkautsar/goo11y/meter"
+	"github.com/mfahmialkautsar/goo11y/tracer"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestNewTracesInitialization(t *testing.T) {
+	var buf bytes.Buffer
+	exporter := tracetest.NewInMemoryExporter()
+	cfg := reloadTestConfig(&buf)
+	cfg.TraceInit = true
+	tele, err := New(context.Background(), cfg,
+		WithTracerOption(tracer.WithSpanExporter(exporter)),
+		WithMeterOption(meter.WithMetricReader(sdkmetric.NewManualReader())),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = tele.Shutdown(context.Background()) })
+	if err := tele.Tracer().ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	var root *tracetest.SpanStub
+	children := make(map[string]bool)
+	for idx := range spans {
+		if spans[idx].Name == initSpanName {
+			root = &spans[idx]
+			continue
+		}
+		children[spans[idx].Name] = true
+	}
+	if root == nil {
+		t.Fatalf("expected a %s span, got %d spans", initSpanName, len(spans))
+	}
+	for _, stage := range []string{"resource", "logger", "tracer", "meter", "profiler", "integrations"} {
+		if !children[initSpanName+"."+stage] {
+			t.Fatalf("missing span for stage %s", stage)
+		}
+	}
+	for _, span := range spans {
+		if span.Name != initSpanName && span.Parent.SpanID() != root.SpanContext.SpanID() {
+			t.Fatalf("span %s is not a child of %s", span.Name, initSpanName)
+		}
+	}
+
+	entry := buf.String()
+	if !strings.Contains(entry, `"message":"telemetry initialized"`) || !strings.Contains(entry, `"components":{"resource":`) {
+		t.Fatalf("expected an initialization summary, got %s", entry)
+	}
+}
+
+func TestNewTracesFailedInitialization(t *testing.T) {
+	var buf bytes.Buffer
+	exporter := tracetest.NewInMemoryExporter()
+	cfg := reloadTestConfig(&buf)
+	cfg.TraceInit = true
+	cfg.Meter.Endpoint = "collector 4318"
+	if _, err := New(context.Background(), cfg, WithTracerOption(tracer.WithSpanExporter(exporter))); err == nil {
+		t.Fatal("expected the meter setup to fail")
+	}
+
+	var found bool
+	for _, span := range exporter.GetSpans() {
+		if span.Name == initSpanName+".meter" {
+			found = true
+			if span.Status.Code != codes.Error {
+				t.Fatalf("expected the meter stage to be failed, got %v", span.Status)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("expected a span for the failed meter stage")
+	}
+	if entry := buf.String(); !strings.Contains(entry, "telemetry initialization failed") || !strings.Contains(entry, `"failed":["meter"]`) {
+		t.Fatalf("expected a failure summary, got %s", entry)
+	}
+}
@@ -0,0 +1,364 @@
+// Package cloudresource detects cloud and Kubernetes resource attributes from instance metadata
+// services and well-known environment variables. Detectors return an empty resource when
+// the process does not run on their platform, so presets can be enabled unconditionally.
+package cloudresource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.28.0"
+)
+
+// Cloud preset names accepted by Detector.
+const (
+	PresetEC2      = "ec2"
+	PresetECS      = "ecs"
+	PresetEKS      = "eks"
+	PresetGCE      = "gce"
+	PresetCloudRun = "cloudrun"
+)
+
+const (
+	defaultEC2Endpoint = "http://169.254.169.254"
+	defaultGCEEndpoint = "http://metadata.google.internal"
+	metadataTimeout    = time.Second
+)
+
+// Detector returns the detector registered for preset.
+func Detector(preset string) (resource.Detector, bool) {
+	switch strings.ToLower(strings.TrimSpace(preset)) {
+	case PresetEC2:
+		return EC2{}, true
+	case PresetECS:
+		return ECS{}, true
+	case PresetEKS:
+		return EKS{}, true
+	case PresetGCE:
+		return GCE{}, true
+	case PresetCloudRun:
+		return CloudRun{}, true
+	case PresetK8s:
+		return K8s{}, true
+	default:
+		return nil, false
+	}
+}
+
+// EC2 reads the instance identity document through IMDSv2.
+type EC2 struct {
+	// Endpoint overrides the instance metadata service address.
+	Endpoint string
+	Client   *http.Client
+}
+
+type ec2Identity struct {
+	AccountID        string `json:"accountId"`
+	AvailabilityZone string `json:"availabilityZone"`
+	ImageID          string `json:"imageId"`
+	InstanceID       string `json:"instanceId"`
+	InstanceType     string `json:"instanceType"`
+	Region           string `json:"region"`
+}
+
+// Detect implements resource.Detector.
+func (d EC2) Detect(ctx context.Context) (*resource.Resource, error) {
+	attrs, ok := d.attributes(ctx)
+	if !ok {
+		return resource.Empty(), nil
+	}
+	attrs = append(attrs, semconv.CloudPlatformAWSEC2)
+	return resource.NewWithAttributes(semconv.SchemaURL, attrs...), nil
+}
+
+func (d EC2) attributes(ctx context.Context) ([]attribute.KeyValue, bool) {
+	endpoint := orDefault(d.Endpoint, defaultEC2Endpoint)
+	client := httpClient(d.Client)
+
+	ctx, cancel := context.WithTimeout(ctx, metadataTimeout)
+	defer cancel()
+
+	tokenReq, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint+"/latest/api/token", nil)
+	if err != nil {
+		return nil, false
+	}
+	tokenReq.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", "60")
+	token, err := fetch(client, tokenReq)
+	if err != nil {
+		return nil, false
+	}
+
+	docReq, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"/latest/dynamic/instance-identity/document", nil)
+	if err != nil {
+		return nil, false
+	}
+	docReq.Header.Set("X-aws-ec2-metadata-token", string(token))
+	body, err := fetch(client, docReq)
+	if err != nil {
+		return nil, false
+	}
+	var doc ec2Identity
+	if err := json.Unmarshal(body, &doc); err != nil || doc.InstanceID == "" {
+		return nil, false
+	}
+
+	attrs := []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		semconv.HostID(doc.InstanceID),
+	}
+	attrs = appendNonEmpty(attrs, semconv.CloudRegion, doc.Region)
+	attrs = appendNonEmpty(attrs, semconv.CloudAvailabilityZone, doc.AvailabilityZone)
+	attrs = appendNonEmpty(attrs, semconv.CloudAccountID, doc.AccountID)
+	attrs = appendNonEmpty(attrs, semconv.HostType, doc.InstanceType)
+	attrs = appendNonEmpty(attrs, semconv.HostImageID, doc.ImageID)
+	return attrs, true
+}
+
+// EKS combines the EC2 instance identity with the Kubernetes cluster name. It only reports
+// when running inside a Kubernetes pod on EC2; the cluster name is read from CLUSTER_NAME.
+type EKS struct {
+	EC2 EC2
+}
+
+// Detect implements resource.Detector.
+func (d EKS) Detect(ctx context.Context) (*resource.Resource, error) {
+	if os.Getenv("KUBERNETES_SERVICE_HOST") == "" {
+		return resource.Empty(), nil
+	}
+	attrs, ok := d.EC2.attributes(ctx)
+	if !ok {
+		return resource.Empty(), nil
+	}
+	attrs = append(attrs, semconv.CloudPlatformAWSEKS)
+	attrs = appendNonEmpty(attrs, semconv.K8SClusterName, os.Getenv("CLUSTER_NAME"))
+	return resource.NewWithAttributes(semconv.SchemaURL, attrs...), nil
+}
+
+// ECS reads the task metadata endpoint advertised through ECS_CONTAINER_METADATA_URI_V4.
+type ECS struct {
+	Client *http.Client
+}
+
+type ecsTask struct {
+	Cluster          string `json:"Cluster"`
+	TaskARN          string `json:"TaskARN"`
+	Family           string `json:"Family"`
+	Revision         string `json:"Revision"`
+	AvailabilityZone string `json:"AvailabilityZone"`
+	LaunchType       string `json:"LaunchType"`
+}
+
+type ecsContainer struct {
+	DockerID     string `json:"DockerId"`
+	Name         string `json:"Name"`
+	ContainerARN string `json:"ContainerARN"`
+}
+
+// Detect implements resource.Detector.
+func (d ECS) Detect(ctx context.Context) (*resource.Resource, error) {
+	endpoint := strings.TrimRight(os.Getenv("ECS_CONTAINER_METADATA_URI_V4"), "/")
+	if endpoint == "" {
+		return resource.Empty(), nil
+	}
+	client := httpClient(d.Client)
+
+	ctx, cancel := context.WithTimeout(ctx, metadataTimeout)
+	defer cancel()
+
+	// The endpoint variable says the process runs on ECS, so an unreachable endpoint is reported
+	// through otel.Handle; like the other detectors, it still yields an empty resource.
+	var container ecsContainer
+	if err := getJSON(ctx, client, endpoint, &container); err != nil {
+		otel.Handle(fmt.Errorf("ecs container metadata: %w", err))
+		return resource.Empty(), nil
+	}
+	var task ecsTask
+	if err := getJSON(ctx, client, endpoint+"/task", &task); err != nil {
+		otel.Handle(fmt.Errorf("ecs task metadata: %w", err))
+		return resource.Empty(), nil
+	}
+
+	attrs := []attribute.KeyValue{
+		semconv.CloudProviderAWS,
+		semconv.CloudPlatformAWSECS,
+	}
+	attrs = appendNonEmpty(attrs, semconv.AWSECSTaskARN, task.TaskARN)
+	attrs = appendNonEmpty(attrs, semconv.AWSECSTaskFamily, task.Family)
+	attrs = appendNonEmpty(attrs, semconv.AWSECSTaskRevision, task.Revision)
+	attrs = appendNonEmpty(attrs, semconv.AWSECSClusterARN, task.Cluster)
+	attrs = appendNonEmpty(attrs, semconv.CloudAvailabilityZone, task.AvailabilityZone)
+	attrs = appendNonEmpty(attrs, semconv.AWSECSContainerARN, container.ContainerARN)
+	attrs = appendNonEmpty(attrs, semconv.ContainerID, container.DockerID)
+	attrs = appendNonEmpty(attrs, semconv.ContainerName, container.Name)
+	if region := arnRegion(task.TaskARN); region != "" {
+		attrs = append(attrs, semconv.CloudRegion(region))
+	}
+	if strings.EqualFold(task.LaunchType, "fargate") {
+		attrs = append(attrs, semconv.AWSECSLaunchtypeFargate)
+	} else if strings.EqualFold(task.LaunchType, "ec2") {
+		attrs = append(attrs, semconv.AWSECSLaunchtypeEC2)
+	}
+	return resource.NewWithAttributes(semconv.SchemaURL, attrs...), nil
+}
+
+// GCE reads the Compute Engine metadata server.
+type GCE struct {
+	// Endpoint overrides the metadata server address.
+	Endpoint string
+	Client   *http.Client
+}
+
+// Detect implements resource.Detector.
+func (d GCE) Detect(ctx context.Context) (*resource.Resource, error) {
+	endpoint := orDefault(d.Endpoint, defaultGCEEndpoint)
+	client := httpClient(d.Client)
+
+	ctx, cancel := context.WithTimeout(ctx, metadataTimeout)
+	defer cancel()
+
+	instanceID, err := gceMetadata(ctx, client, endpoint, "instance/id")
+	if err != nil || instanceID == "" {
+		return resource.Empty(), nil
+	}
+	attrs := []attribute.KeyValue{
+		semconv.CloudProviderGCP,
+		semconv.CloudPlatformGCPComputeEngine,
+		semconv.HostID(instanceID),
+	}
+	if project, err := gceMetadata(ctx, client, endpoint, "project/project-id"); err == nil {
+		attrs = appendNonEmpty(attrs, semconv.CloudAccountID, project)
+	}
+	if zone, err := gceMetadata(ctx, client, endpoint, "instance/zone"); err == nil && zone != "" {
+		zone = zone[strings.LastIndex(zone, "/")+1:]
+		attrs = append(attrs, semconv.CloudAvailabilityZone(zone))
+		if i := strings.LastIndex(zone, "-"); i > 0 {
+			attrs = append(attrs, semconv.CloudRegion(zone[:i]))
+		}
+	}
+	if name, err := gceMetadata(ctx, client, endpoint, "instance/name"); err == nil {
+		attrs = appendNonEmpty(attrs, semconv.HostName, name)
+		attrs = appendNonEmpty(attrs, semconv.GCPGceInstanceName, name)
+	}
+	if machineType, err := gceMetadata(ctx, client, endpoint, "instance/machine-type"); err == nil && machineType != "" {
+		attrs = append(attrs, semconv.HostType(machineType[strings.LastIndex(machineType, "/")+1:]))
+	}
+	return resource.NewWithAttributes(semconv.SchemaURL, attrs...), nil
+}
+
+// CloudRun reads the K_SERVICE and K_REVISION variables set by Cloud Run, plus the
+// project and region from the metadata server when it is reachable.
+type CloudRun struct {
+	// Endpoint overrides the metadata server address.
+	Endpoint string
+	Client   *http.Client
+}
+
+// Detect implements resource.Detector.
+func (d CloudRun) Detect(ctx context.Context) (*resource.Resource, error) {
+	service := os.Getenv("K_SERVICE")
+	if service == "" {
+		return resource.Empty(), nil
+	}
+	attrs := []attribute.KeyValue{
+		semconv.CloudProviderGCP,
+		semconv.CloudPlatformGCPCloudRun,
+		semconv.FaaSName(service),
+	}
+	attrs = appendNonEmpty(attrs, semconv.FaaSVersion, os.Getenv("K_REVISION"))
+
+	endpoint := orDefault(d.Endpoint, defaultGCEEndpoint)
+	client := httpClient(d.Client)
+	ctx, cancel := context.WithTimeout(ctx, metadataTimeout)
+	defer cancel()
+
+	if instanceID, err := gceMetadata(ctx, client, endpoint, "instance/id"); err == nil {
+		attrs = appendNonEmpty(attrs, semconv.FaaSInstance, instanceID)
+	}
+	if project, err := gceMetadata(ctx, client, endpoint, "project/project-id"); err == nil {
+		attrs = appendNonEmpty(attrs, semconv.CloudAccountID, project)
+	}
+	if region, err := gceMetadata(ctx, client, endpoint, "instance/region"); err == nil && region != "" {
+		attrs = append(attrs, semconv.CloudRegion(region[strings.LastIndex(region, "/")+1:]))
+	}
+	return resource.NewWithAttributes(semconv.SchemaURL, attrs...), nil
+}
+
+func gceMetadata(ctx context.Context, client *http.Client, endpoint, path string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"/computeMetadata/v1/"+path, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+	body, err := fetch(client, req)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, target any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	body, err := fetch(client, req)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, target)
+}
+
+func fetch(client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s %s: status %d", req.Method, req.URL.Path, resp.StatusCode)
+	}
+	return body, nil
+}
+
+func httpClient(client *http.Client) *http.Client {
+	if client != nil {
+		return client
+	}
+	return &http.Client{Timeout: metadataTimeout}
+}
+
+func orDefault(value, fallback string) string {
+	if value = strings.TrimRight(value, "/"); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func appendNonEmpty(attrs []attribute.KeyValue, build func(string) attribute.KeyValue, value string) []attribute.KeyValue {
+	if value == "" {
+		return attrs
+	}
+	return append(attrs, build(value))
+}
+
+// arnRegion extracts the region from arn:partition:service:region:account:resource.
+func arnRegion(arn string) string {
+	parts := strings.SplitN(arn, ":", 5)
+	if len(parts) < 5 {
+		return ""
+	}
+	return parts[3]
+}
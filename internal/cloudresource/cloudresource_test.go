@@ -0,0 +1,196 @@
+package cloudresource
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+func attrValue(res *resource.Resource, key string) string {
+	value, _ := res.Set().Value(attribute.Key(key))
+	return value.Emit()
+}
+
+func TestEC2DetectReadsIdentityDocument(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/latest/api/token":
+			if r.Method != http.MethodPut {
+				t.Errorf("token method = %s", r.Method)
+			}
+			_, _ = w.Write([]byte("token-1"))
+		case "/latest/dynamic/instance-identity/document":
+			if got := r.Header.Get("X-aws-ec2-metadata-token"); got != "token-1" {
+				t.Errorf("token header = %q", got)
+			}
+			_, _ = w.Write([]byte(`{"accountId":"123","availabilityZone":"us-east-1a","instanceId":"i-abc","instanceType":"t3.micro","region":"us-east-1"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	res, err := EC2{Endpoint: server.URL}.Detect(context.Background())
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	for key, want := range map[string]string{
+		"cloud.provider":          "aws",
+		"cloud.platform":          "aws_ec2",
+		"cloud.region":            "us-east-1",
+		"cloud.availability_zone": "us-east-1a",
+		"cloud.account.id":        "123",
+		"host.id":                 "i-abc",
+		"host.type":               "t3.micro",
+	} {
+		if got := attrValue(res, key); got != want {
+			t.Fatalf("%s = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestEC2DetectOffPlatformIsEmpty(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	res, err := EC2{Endpoint: server.URL}.Detect(context.Background())
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if res.Len() != 0 {
+		t.Fatalf("expected empty resource, got %v", res)
+	}
+}
+
+func TestECSDetectReadsTaskMetadata(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v4":
+			_, _ = w.Write([]byte(`{"DockerId":"abc123","Name":"app","ContainerARN":"arn:aws:ecs:eu-west-1:123:container/x"}`))
+		case "/v4/task":
+			_, _ = w.Write([]byte(`{"Cluster":"arn:aws:ecs:eu-west-1:123:cluster/main","TaskARN":"arn:aws:ecs:eu-west-1:123:task/main/t1","Family":"api","Revision":"7","LaunchType":"FARGATE"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	t.Setenv("ECS_CONTAINER_METADATA_URI_V4", server.URL+"/v4")
+
+	res, err := ECS{}.Detect(context.Background())
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	for key, want := range map[string]string{
+		"cloud.platform":        "aws_ecs",
+		"cloud.region":          "eu-west-1",
+		"aws.ecs.task.family":   "api",
+		"aws.ecs.task.revision": "7",
+		"aws.ecs.launchtype":    "fargate",
+		"container.id":          "abc123",
+		"container.name":        "app",
+	} {
+		if got := attrValue(res, key); got != want {
+			t.Fatalf("%s = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestGCEDetectRequiresMetadataFlavor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			http.Error(w, "missing flavor", http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/computeMetadata/v1/instance/id":
+			_, _ = w.Write([]byte("42"))
+		case "/computeMetadata/v1/project/project-id":
+			_, _ = w.Write([]byte("proj"))
+		case "/computeMetadata/v1/instance/zone":
+			_, _ = w.Write([]byte("projects/1/zones/europe-west4-b"))
+		case "/computeMetadata/v1/instance/name":
+			_, _ = w.Write([]byte("vm-1"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	res, err := GCE{Endpoint: server.URL}.Detect(context.Background())
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	for key, want := range map[string]string{
+		"cloud.provider":          "gcp",
+		"cloud.platform":          "gcp_compute_engine",
+		"cloud.account.id":        "proj",
+		"cloud.availability_zone": "europe-west4-b",
+		"cloud.region":            "europe-west4",
+		"host.id":                 "42",
+		"host.name":               "vm-1",
+	} {
+		if got := attrValue(res, key); got != want {
+			t.Fatalf("%s = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestECSDetectReportsUnreachableEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	t.Setenv("ECS_CONTAINER_METADATA_URI_V4", server.URL+"/v4")
+
+	var reported []error
+	prev := otel.GetErrorHandler()
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) { reported = append(reported, err) }))
+	t.Cleanup(func() { otel.SetErrorHandler(prev) })
+
+	res, err := ECS{}.Detect(context.Background())
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if res.Len() != 0 {
+		t.Fatalf("expected empty resource, got %v", res)
+	}
+	if len(reported) != 1 || !strings.Contains(reported[0].Error(), "ecs container metadata") {
+		t.Fatalf("expected the fetch failure to be reported, got %v", reported)
+	}
+}
+
+func TestCloudRunDetectUsesEnvironment(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	t.Setenv("K_SERVICE", "checkout")
+	t.Setenv("K_REVISION", "checkout-00003")
+
+	res, err := CloudRun{Endpoint: server.URL}.Detect(context.Background())
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if got := attrValue(res, "cloud.platform"); got != "gcp_cloud_run" {
+		t.Fatalf("cloud.platform = %q", got)
+	}
+	if got := attrValue(res, "faas.name"); got != "checkout" {
+		t.Fatalf("faas.name = %q", got)
+	}
+	if got := attrValue(res, "faas.version"); got != "checkout-00003" {
+		t.Fatalf("faas.version = %q", got)
+	}
+}
+
+func TestDetectorPresets(t *testing.T) {
+	for _, preset := range []string{PresetEC2, PresetECS, PresetEKS, PresetGCE, PresetCloudRun, PresetK8s} {
+		if _, ok := Detector(preset); !ok {
+			t.Fatalf("preset %q not registered", preset)
+		}
+	}
+	if _, ok := Detector("azure"); ok {
+		t.Fatal("unexpected detector for unknown preset")
+	}
+}
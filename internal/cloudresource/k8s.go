@@ -0,0 +1,108 @@
+package cloudresource
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.28.0"
+)
+
+// PresetK8s selects the Kubernetes detector.
+const PresetK8s = "k8s"
+
+const (
+	defaultNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+	defaultCgroupFile    = "/proc/self/cgroup"
+	defaultMountinfoFile = "/proc/self/mountinfo"
+)
+
+// Downward API variables read by K8s, checked in order.
+var (
+	podNameEnv      = []string{"K8S_POD_NAME", "POD_NAME"}
+	podUIDEnv       = []string{"K8S_POD_UID", "POD_UID"}
+	namespaceEnv    = []string{"K8S_NAMESPACE_NAME", "POD_NAMESPACE"}
+	nodeNameEnv     = []string{"K8S_NODE_NAME", "NODE_NAME"}
+	containerEnv    = []string{"K8S_CONTAINER_NAME", "CONTAINER_NAME"}
+	containerIDExpr = regexp.MustCompile(`[0-9a-f]{64}`)
+)
+
+// K8s reports pod, namespace, node and container attributes when running inside a pod.
+// Values come from the downward API variables above; the pod name falls back to the
+// hostname, the namespace to the service account mount and the container ID to cgroups.
+type K8s struct {
+	// NamespaceFile, CgroupFile and MountinfoFile override the default paths.
+	NamespaceFile string
+	CgroupFile    string
+	MountinfoFile string
+}
+
+// Detect implements resource.Detector.
+func (d K8s) Detect(context.Context) (*resource.Resource, error) {
+	if os.Getenv("KUBERNETES_SERVICE_HOST") == "" {
+		return resource.Empty(), nil
+	}
+
+	podName := firstEnv(podNameEnv)
+	if podName == "" {
+		podName, _ = os.Hostname()
+	}
+	namespace := firstEnv(namespaceEnv)
+	if namespace == "" {
+		if data, err := os.ReadFile(orDefault(d.NamespaceFile, defaultNamespaceFile)); err == nil {
+			namespace = strings.TrimSpace(string(data))
+		}
+	}
+
+	var attrs []attribute.KeyValue
+	attrs = appendNonEmpty(attrs, semconv.K8SPodName, podName)
+	attrs = appendNonEmpty(attrs, semconv.K8SPodUID, firstEnv(podUIDEnv))
+	attrs = appendNonEmpty(attrs, semconv.K8SNamespaceName, namespace)
+	attrs = appendNonEmpty(attrs, semconv.K8SNodeName, firstEnv(nodeNameEnv))
+	attrs = appendNonEmpty(attrs, semconv.K8SContainerName, firstEnv(containerEnv))
+	attrs = appendNonEmpty(attrs, semconv.ContainerID, d.containerID())
+	return resource.NewWithAttributes(semconv.SchemaURL, attrs...), nil
+}
+
+// containerID reads the container ID from the cgroup v1 hierarchy, falling back to the
+// cgroup v2 mount table where containerd and CRI-O expose it in the sandbox paths.
+func (d K8s) containerID() string {
+	if id := scanContainerID(orDefault(d.CgroupFile, defaultCgroupFile)); id != "" {
+		return id
+	}
+	return scanContainerID(orDefault(d.MountinfoFile, defaultMountinfoFile))
+}
+
+func scanContainerID(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.Contains(line, "/sandboxes/") {
+			// The pause container's sandbox ID is not ours.
+			continue
+		}
+		if id := containerIDExpr.FindString(line); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+func firstEnv(names []string) string {
+	for _, name := range names {
+		if value := strings.TrimSpace(os.Getenv(name)); value != "" {
+			return value
+		}
+	}
+	return ""
+}
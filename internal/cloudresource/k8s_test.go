@@ -0,0 +1,83 @@
+package cloudresource
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testContainerID = "3f2c1a9b8e7d6c5b4a39281706f5e4d3c2b1a09f8e7d6c5b4a3928170615e4d3"
+
+func TestK8sDetectReadsDownwardAPI(t *testing.T) {
+	dir := t.TempDir()
+	cgroup := filepath.Join(dir, "cgroup")
+	if err := os.WriteFile(cgroup, []byte("0::/kubepods/besteffort/pod1234/"+testContainerID+"\n"), 0o600); err != nil {
+		t.Fatalf("write cgroup: %v", err)
+	}
+	t.Setenv("KUBERNETES_SERVICE_HOST", "10.0.0.1")
+	t.Setenv("POD_NAME", "api-7d9f")
+	t.Setenv("POD_NAMESPACE", "payments")
+	t.Setenv("NODE_NAME", "node-a")
+
+	res, err := K8s{CgroupFile: cgroup, MountinfoFile: filepath.Join(dir, "missing")}.Detect(context.Background())
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	for key, want := range map[string]string{
+		"k8s.pod.name":       "api-7d9f",
+		"k8s.namespace.name": "payments",
+		"k8s.node.name":      "node-a",
+		"container.id":       testContainerID,
+	} {
+		if got := attrValue(res, key); got != want {
+			t.Fatalf("%s = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestK8sDetectFallsBackToServiceAccountAndMountinfo(t *testing.T) {
+	dir := t.TempDir()
+	namespace := filepath.Join(dir, "namespace")
+	mountinfo := filepath.Join(dir, "mountinfo")
+	if err := os.WriteFile(namespace, []byte("billing\n"), 0o600); err != nil {
+		t.Fatalf("write namespace: %v", err)
+	}
+	sandbox := "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	lines := "100 90 0:50 /var/lib/containerd/io.containerd.grpc.v1.cri/sandboxes/" + sandbox + "/hostname /etc/hostname rw\n" +
+		"101 90 0:50 /var/lib/containerd/io.containerd.grpc.v1.cri/containers/" + testContainerID + "/resolv.conf /etc/resolv.conf rw\n"
+	if err := os.WriteFile(mountinfo, []byte(lines), 0o600); err != nil {
+		t.Fatalf("write mountinfo: %v", err)
+	}
+	t.Setenv("KUBERNETES_SERVICE_HOST", "10.0.0.1")
+
+	res, err := K8s{
+		NamespaceFile: namespace,
+		CgroupFile:    filepath.Join(dir, "missing"),
+		MountinfoFile: mountinfo,
+	}.Detect(context.Background())
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if got := attrValue(res, "k8s.namespace.name"); got != "billing" {
+		t.Fatalf("k8s.namespace.name = %q", got)
+	}
+	if got := attrValue(res, "container.id"); got != testContainerID {
+		t.Fatalf("container.id = %q", got)
+	}
+	if got := attrValue(res, "k8s.pod.name"); got == "" {
+		t.Fatal("expected pod name to fall back to the hostname")
+	}
+}
+
+func TestK8sDetectOutsideClusterIsEmpty(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "")
+
+	res, err := K8s{}.Detect(context.Background())
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if res.Len() != 0 {
+		t.Fatalf("expected empty resource, got %v", res)
+	}
+}
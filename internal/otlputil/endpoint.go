@@ -135,6 +135,15 @@ func (e Endpoint) Resolve(suffix string) string {
 	return host + "/" + combined
 }
 
+// URL returns the absolute HTTP URL for the endpoint and suffix, using http when the endpoint is insecure.
+func (e Endpoint) URL(suffix string) string {
+	scheme := "https"
+	if e.Insecure {
+		scheme = "http"
+	}
+	return scheme + "://" + strings.TrimRight(e.Host, "/") + e.PathWithSuffix(suffix)
+}
+
 // PathWithSuffix returns the normalized URL path consisting of the base path and suffix.
 // A leading slash is added when the combined path is non-empty.
 func (e Endpoint) PathWithSuffix(suffix string) string {
@@ -145,6 +154,30 @@ func (e Endpoint) PathWithSuffix(suffix string) string {
 	return "/" + combined
 }
 
+// ExportPath returns the URL path exports are sent to: the base path plus suffix, or the path as
+// given when isURL is set, as for an endpoint holding the full URL.
+func (e Endpoint) ExportPath(suffix string, isURL bool) string {
+	if !isURL {
+		return e.PathWithSuffix(suffix)
+	}
+	if e.Path == "" {
+		return "/"
+	}
+	return e.Path
+}
+
+// ExportURL is URL with the path chosen by ExportPath.
+func (e Endpoint) ExportURL(suffix string, isURL bool) string {
+	if !isURL {
+		return e.URL(suffix)
+	}
+	scheme := "https"
+	if e.Insecure {
+		scheme = "http"
+	}
+	return scheme + "://" + strings.TrimRight(e.Host, "/") + e.ExportPath(suffix, true)
+}
+
 func combinePath(base, suffix string) string {
 	trimmedBase := strings.Trim(base, "/")
 	trimmedSuffix := strings.Trim(suffix, "/")
@@ -189,3 +189,35 @@ func TestPathWithSuffix(t *testing.T) {
 		})
 	}
 }
+
+func TestURL(t *testing.T) {
+	secure := Endpoint{Host: "collector:4318", Path: "/base"}
+	if got := secure.URL("/v1/logs"); got != "https://collector:4318/base/v1/logs" {
+		t.Fatalf("unexpected secure URL: %q", got)
+	}
+	plain := Endpoint{Host: "collector:4318", Insecure: true}
+	if got := plain.URL("/v1/traces"); got != "http://collector:4318/v1/traces" {
+		t.Fatalf("unexpected insecure URL: %q", got)
+	}
+}
+
+func TestEndpointExportPathKeepsFullURLs(t *testing.T) {
+	endpoint, err := ParseEndpoint("https://collector:4318/custom/ingest", false)
+	if err != nil {
+		t.Fatalf("ParseEndpoint: %v", err)
+	}
+	if got := endpoint.ExportPath("/v1/logs", false); got != "/custom/ingest/v1/logs" {
+		t.Fatalf("expected the signal path appended to a base URL, got %q", got)
+	}
+	if got := endpoint.ExportURL("/v1/logs", true); got != "https://collector:4318/custom/ingest" {
+		t.Fatalf("expected a full URL used as-is, got %q", got)
+	}
+
+	root, err := ParseEndpoint("http://collector:4318", false)
+	if err != nil {
+		t.Fatalf("ParseEndpoint: %v", err)
+	}
+	if got := root.ExportPath("/v1/logs", true); got != "/" {
+		t.Fatalf("expected the root path for a full URL without one, got %q", got)
+	}
+}
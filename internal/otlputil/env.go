@@ -0,0 +1,131 @@
+package otlputil
+
+import (
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mfahmialkautsar/goo11y/constant"
+)
+
+// Signals naming the OTEL_EXPORTER_OTLP_{SIGNAL}_* variables.
+const (
+	SignalTraces  = "TRACES"
+	SignalMetrics = "METRICS"
+	SignalLogs    = "LOGS"
+)
+
+const envPrefix = "OTEL_EXPORTER_OTLP_"
+
+// EnvExporter holds the exporter settings found in the standard OTLP environment variables for one
+// signal. A signal-specific variable wins over its generic OTEL_EXPORTER_OTLP_* counterpart; fields
+// whose variables are unset or unparsable are left zero.
+type EnvExporter struct {
+	Endpoint string
+	// EndpointIsURL reports that Endpoint came from OTEL_EXPORTER_OTLP_{SIGNAL}_ENDPOINT, which the
+	// specification defines as the full URL to export to, so no /v1/<signal> path is appended.
+	EndpointIsURL bool
+	Insecure      bool
+	Headers       map[string]string
+	Timeout       time.Duration
+	Protocol      string
+	Certificate   string
+}
+
+// ReadEnv reads the OTLP exporter variables for signal.
+func ReadEnv(signal string) EnvExporter {
+	var env EnvExporter
+
+	if value, ok := lookupSignalEnv(signal, "ENDPOINT"); ok {
+		env.Endpoint = value
+		env.EndpointIsURL = true
+	} else if value, ok := lookupEnv(envPrefix + "ENDPOINT"); ok {
+		env.Endpoint = value
+	}
+	if value, ok := envValue(signal, "INSECURE"); ok {
+		env.Insecure, _ = strconv.ParseBool(value)
+	}
+	if value, ok := envValue(signal, "HEADERS"); ok {
+		env.Headers = parseEnvHeaders(value)
+	}
+	if value, ok := envValue(signal, "TIMEOUT"); ok {
+		if ms, err := strconv.ParseInt(value, 10, 64); err == nil && ms > 0 {
+			env.Timeout = time.Duration(ms) * time.Millisecond
+		}
+	}
+	if value, ok := envValue(signal, "PROTOCOL"); ok {
+		switch value {
+		case "grpc":
+			env.Protocol = constant.ProtocolGRPC
+		case "http/protobuf", "http/json":
+			env.Protocol = constant.ProtocolHTTP
+		}
+	}
+	if value, ok := envValue(signal, "CERTIFICATE"); ok {
+		env.Certificate = value
+	}
+	return env
+}
+
+// MergeHeaders returns primary plus the fallback headers whose names, compared case-insensitively,
+// primary does not set.
+func MergeHeaders(primary, fallback map[string]string) map[string]string {
+	if len(fallback) == 0 {
+		return primary
+	}
+	merged := make(map[string]string, len(primary)+len(fallback))
+	for key, value := range fallback {
+		merged[key] = value
+	}
+	for key, value := range primary {
+		for existing := range merged {
+			if strings.EqualFold(existing, key) {
+				delete(merged, existing)
+			}
+		}
+		merged[key] = value
+	}
+	return merged
+}
+
+func envValue(signal, name string) (string, bool) {
+	if value, ok := lookupSignalEnv(signal, name); ok {
+		return value, true
+	}
+	return lookupEnv(envPrefix + name)
+}
+
+func lookupSignalEnv(signal, name string) (string, bool) {
+	if signal == "" {
+		return "", false
+	}
+	return lookupEnv(envPrefix + signal + "_" + name)
+}
+
+func lookupEnv(name string) (string, bool) {
+	value := strings.TrimSpace(os.Getenv(name))
+	return value, value != ""
+}
+
+// parseEnvHeaders decodes the W3C baggage-like key=value,key=value list the specification uses.
+func parseEnvHeaders(raw string) map[string]string {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(raw, ",") {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		decoded, err := url.PathUnescape(strings.TrimSpace(value))
+		if err != nil || key == "" || decoded == "" {
+			continue
+		}
+		headers[key] = decoded
+	}
+	if len(headers) == 0 {
+		return nil
+	}
+	return headers
+}
@@ -0,0 +1,61 @@
+package otlputil
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReadEnvPrefersSignalVariables(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "https://generic:4318")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "https://traces:4318/otlp/v1/traces")
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "Authorization=Basic%20abc,x-tenant=generic")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_HEADERS", "x-tenant=traces")
+	t.Setenv("OTEL_EXPORTER_OTLP_TIMEOUT", "2500")
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "http/protobuf")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_PROTOCOL", "grpc")
+	t.Setenv("OTEL_EXPORTER_OTLP_INSECURE", "true")
+	t.Setenv("OTEL_EXPORTER_OTLP_CERTIFICATE", "/etc/ssl/collector.pem")
+
+	traces := ReadEnv(SignalTraces)
+	if traces.Endpoint != "https://traces:4318/otlp/v1/traces" || !traces.EndpointIsURL {
+		t.Fatalf("expected the traces endpoint as a full URL, got %q", traces.Endpoint)
+	}
+	if traces.Protocol != "grpc" || traces.Timeout != 2500*time.Millisecond || !traces.Insecure {
+		t.Fatalf("unexpected traces settings %+v", traces)
+	}
+	if len(traces.Headers) != 1 || traces.Headers["x-tenant"] != "traces" {
+		t.Fatalf("expected signal headers to replace generic ones, got %v", traces.Headers)
+	}
+	if traces.Certificate != "/etc/ssl/collector.pem" {
+		t.Fatalf("unexpected certificate %q", traces.Certificate)
+	}
+
+	logs := ReadEnv(SignalLogs)
+	if logs.Endpoint != "https://generic:4318" || logs.EndpointIsURL || logs.Protocol != "http" {
+		t.Fatalf("unexpected logs settings %+v", logs)
+	}
+	if logs.Headers["Authorization"] != "Basic abc" {
+		t.Fatalf("expected decoded authorization header, got %v", logs.Headers)
+	}
+}
+
+func TestReadEnvIgnoresInvalidValues(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_TIMEOUT", "soon")
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "thrift")
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "novalue,=empty")
+
+	env := ReadEnv(SignalMetrics)
+	if env.Timeout != 0 || env.Protocol != "" || env.Headers != nil {
+		t.Fatalf("expected invalid values to be ignored, got %+v", env)
+	}
+}
+
+func TestMergeHeadersKeepsPrimary(t *testing.T) {
+	merged := MergeHeaders(
+		map[string]string{"authorization": "Bearer primary"},
+		map[string]string{"Authorization": "Basic fallback", "x-tenant": "acme"},
+	)
+	if len(merged) != 2 || merged["authorization"] != "Bearer primary" || merged["x-tenant"] != "acme" {
+		t.Fatalf("unexpected merge result %v", merged)
+	}
+}
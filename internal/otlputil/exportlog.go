@@ -3,30 +3,129 @@ package otlputil
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
+// DefaultExportFailureInterval is how long repeated failures of one component and transport are
+// counted instead of logged after the first one.
+const DefaultExportFailureInterval = 30 * time.Second
+
 type failureHandler func(component, transport string, err error)
 
 var (
 	exportLogMu    sync.Mutex
 	exportHandlers atomic.Value // failureHandler
 	exportInFlight sync.Map
+
+	exportInterval atomic.Int64 // time.Duration
+	exportLimits   sync.Map     // component|transport -> *failureLimit
+	exportNow      = time.Now
+	// exportAfterFunc schedules the summary of an interval's suppressed failures.
+	exportAfterFunc = func(d time.Duration, f func()) (stop func() bool) {
+		return time.AfterFunc(d, f).Stop
+	}
 )
 
+// ExportFailureStats counts the failures reported for one component and transport.
+type ExportFailureStats struct {
+	// Reported is every failure passed to LogExportFailure.
+	Reported uint64
+	// Suppressed is the failures counted instead of logged because one was logged within the interval.
+	Suppressed uint64
+}
+
+// failureLimit lets the first failure of an interval through and counts the rest. When an interval
+// ends with suppressed failures, the last of them is summarized with their count, so a failure
+// that stops repeating is still accounted for without waiting for the next one.
+type failureLimit struct {
+	mu         sync.Mutex
+	logged     time.Time
+	pending    uint64
+	last       error
+	stop       func() bool
+	reported   uint64
+	suppressed uint64
+}
+
+// allow reports whether err may be logged now, with the failures suppressed since the last one
+// logged. Suppressing a failure schedules summarize for the end of the interval unless it is
+// already scheduled.
+func (l *failureLimit) allow(now func() time.Time, interval time.Duration, err error, summarize func(error, uint64)) (bool, uint64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.reported++
+	at := now()
+	if interval > 0 && !l.logged.IsZero() && at.Sub(l.logged) < interval {
+		l.pending++
+		l.suppressed++
+		l.last = err
+		if l.stop == nil {
+			l.stop = exportAfterFunc(l.logged.Add(interval).Sub(at), func() { l.summarize(now, summarize) })
+		}
+		return false, 0
+	}
+	if l.stop != nil {
+		l.stop()
+		l.stop = nil
+	}
+	pending := l.pending
+	l.logged = at
+	l.pending = 0
+	l.last = nil
+	return true, pending
+}
+
+// summarize reports the failures suppressed in the interval that just ended and starts a new one,
+// so the summary counts as that interval's logged failure.
+func (l *failureLimit) summarize(now func() time.Time, summarize func(error, uint64)) {
+	l.mu.Lock()
+	l.stop = nil
+	pending, last := l.pending, l.last
+	if pending == 0 {
+		l.mu.Unlock()
+		return
+	}
+	l.logged = now()
+	l.pending = 0
+	l.last = nil
+	l.mu.Unlock()
+	summarize(last, pending)
+}
+
 func init() {
 	exportHandlers.Store(failureHandler(defaultFailureLog))
+	exportInterval.Store(int64(DefaultExportFailureInterval))
 }
 
 // LogExportFailure writes exporter failures to stderr so that telemetry delivery issues are visible during operation.
+// Within the interval set by SetExportFailureInterval only the first failure per component and transport is
+// logged; at the end of the interval the last suppressed failure is logged with how many were suppressed.
 func LogExportFailure(component, transport string, err error) {
 	if err == nil {
 		return
 	}
 
+	limitKey := component + "|" + transport
+	value, _ := exportLimits.LoadOrStore(limitKey, &failureLimit{})
+	allowed, suppressed := value.(*failureLimit).allow(exportNow, time.Duration(exportInterval.Load()), err,
+		func(last error, suppressed uint64) {
+			reportExportFailure(component, transport, fmt.Errorf("%w (%d similar failures suppressed)", last, suppressed))
+		})
+	if !allowed {
+		return
+	}
+	if suppressed > 0 {
+		err = fmt.Errorf("%w (%d similar failures suppressed)", err, suppressed)
+	}
+	reportExportFailure(component, transport, err)
+}
+
+func reportExportFailure(component, transport string, err error) {
 	handler, _ := exportHandlers.Load().(failureHandler)
 	if handler == nil {
 		handler = defaultFailureLog
@@ -54,8 +153,16 @@ func LogExportFailure(component, transport string, err error) {
 }
 
 // SetExportFailureHandler overrides the failure handler used for exporter errors.
-// Passing nil restores the default stderr logger.
+// Passing nil restores the default stderr logger. The new handler receives the next failure of
+// every component and transport regardless of the interval.
 func SetExportFailureHandler(handler func(component, transport string, err error)) {
+	exportLimits.Range(func(_, value any) bool {
+		limit := value.(*failureLimit)
+		limit.mu.Lock()
+		limit.logged = time.Time{}
+		limit.mu.Unlock()
+		return true
+	})
 	if handler == nil {
 		exportHandlers.Store(failureHandler(defaultFailureLog))
 		return
@@ -63,6 +170,26 @@ func SetExportFailureHandler(handler func(component, transport string, err error
 	exportHandlers.Store(failureHandler(handler))
 }
 
+// SetExportFailureInterval sets how long repeated failures are suppressed after one is logged.
+// Zero or less logs every failure.
+func SetExportFailureInterval(interval time.Duration) {
+	exportInterval.Store(int64(interval))
+}
+
+// ExportFailures returns the failure counters keyed by "component/transport".
+func ExportFailures() map[string]ExportFailureStats {
+	out := make(map[string]ExportFailureStats)
+	exportLimits.Range(func(key, value any) bool {
+		component, transport, _ := strings.Cut(key.(string), "|")
+		limit := value.(*failureLimit)
+		limit.mu.Lock()
+		out[component+"/"+transport] = ExportFailureStats{Reported: limit.reported, Suppressed: limit.suppressed}
+		limit.mu.Unlock()
+		return true
+	})
+	return out
+}
+
 func defaultFailureLog(component, transport string, err error) {
 	if err == nil {
 		return
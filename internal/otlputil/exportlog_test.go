@@ -4,6 +4,7 @@ import (
 	"errors"
 	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestLogExportFailureRecursionGuard(t *testing.T) {
@@ -22,3 +23,87 @@ func TestLogExportFailureRecursionGuard(t *testing.T) {
 		t.Fatalf("expected handler to run once, got %d", calls.Load())
 	}
 }
+
+func TestLogExportFailureSuppressesRepeatsWithinInterval(t *testing.T) {
+	now := time.Unix(0, 0)
+	exportNow = func() time.Time { return now }
+	SetExportFailureInterval(time.Minute)
+	t.Cleanup(func() {
+		exportNow = time.Now
+		SetExportFailureInterval(DefaultExportFailureInterval)
+	})
+
+	var logged []string
+	SetExportFailureHandler(func(component, transport string, err error) {
+		logged = append(logged, err.Error())
+	})
+	defer SetExportFailureHandler(nil)
+
+	for range 3 {
+		LogExportFailure("limited", "http", errors.New("down"))
+	}
+	LogExportFailure("limited", "grpc", errors.New("down"))
+	now = now.Add(time.Minute)
+	LogExportFailure("limited", "http", errors.New("still down"))
+
+	want := []string{"down", "down", "still down (2 similar failures suppressed)"}
+	if len(logged) != len(want) {
+		t.Fatalf("unexpected logged failures %q", logged)
+	}
+	for idx := range want {
+		if logged[idx] != want[idx] {
+			t.Fatalf("unexpected logged failures %q", logged)
+		}
+	}
+
+	stats := ExportFailures()["limited/http"]
+	if stats.Reported != 4 || stats.Suppressed != 2 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+func TestLogExportFailureSummarizesSuppressedFailuresAtIntervalEnd(t *testing.T) {
+	now := time.Unix(0, 0)
+	var scheduled []time.Duration
+	var fire func()
+	exportNow = func() time.Time { return now }
+	exportAfterFunc = func(d time.Duration, f func()) func() bool {
+		scheduled = append(scheduled, d)
+		fire = f
+		return func() bool { return true }
+	}
+	SetExportFailureInterval(time.Minute)
+	t.Cleanup(func() {
+		exportNow = time.Now
+		exportAfterFunc = func(d time.Duration, f func()) func() bool { return time.AfterFunc(d, f).Stop }
+		SetExportFailureInterval(DefaultExportFailureInterval)
+	})
+
+	var logged []string
+	SetExportFailureHandler(func(component, transport string, err error) {
+		logged = append(logged, err.Error())
+	})
+	defer SetExportFailureHandler(nil)
+
+	LogExportFailure("summarized", "http", errors.New("down"))
+	now = now.Add(10 * time.Second)
+	LogExportFailure("summarized", "http", errors.New("down"))
+	LogExportFailure("summarized", "http", errors.New("refused"))
+	if len(scheduled) != 1 || scheduled[0] != 50*time.Second {
+		t.Fatalf("expected one summary at the end of the interval, got %v", scheduled)
+	}
+
+	// No further failure arrives; the summary is still logged when the interval ends.
+	now = now.Add(50 * time.Second)
+	fire()
+	want := []string{"down", "refused (2 similar failures suppressed)"}
+	if len(logged) != len(want) || logged[0] != want[0] || logged[1] != want[1] {
+		t.Fatalf("unexpected logged failures %q", logged)
+	}
+
+	// The summary starts a new interval.
+	LogExportFailure("summarized", "http", errors.New("down"))
+	if len(logged) != 2 || len(scheduled) != 2 {
+		t.Fatalf("expected the failure after the summary to be suppressed, logged %q", logged)
+	}
+}
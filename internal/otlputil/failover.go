@@ -0,0 +1,103 @@
+package otlputil
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Failover routes exports between a primary and a secondary endpoint. After threshold consecutive
+// primary failures it switches to the secondary, and while switched it probes the primary at most
+// once per probe interval, switching back on the first success.
+type Failover struct {
+	component string
+	transport string
+	threshold int
+	probe     time.Duration
+	now       func() time.Time
+
+	mu          sync.Mutex
+	failures    int
+	onSecondary bool
+	lastProbe   time.Time
+}
+
+// NewFailover creates a Failover. A threshold below one switches on the first failure, and a
+// non-positive probe interval probes the primary on every attempt.
+func NewFailover(component, transport string, threshold int, probe time.Duration) *Failover {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &Failover{
+		component: component,
+		transport: transport,
+		threshold: threshold,
+		probe:     probe,
+		now:       time.Now,
+	}
+}
+
+// Do runs primary unless the primary is currently failed over, in which case secondary runs instead.
+// When primary fails and that failure trips or keeps the failover, secondary runs in the same call so
+// the payload is not delayed by another retry cycle. The error of the last attempt is returned.
+func (f *Failover) Do(primary, secondary func() error) error {
+	if f == nil || secondary == nil {
+		return primary()
+	}
+	if !f.useSecondary() {
+		err := primary()
+		if !f.report(err) {
+			return err
+		}
+	}
+	return secondary()
+}
+
+// Active reports whether exports are currently routed to the secondary endpoint.
+func (f *Failover) Active() bool {
+	if f == nil {
+		return false
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.onSecondary
+}
+
+func (f *Failover) useSecondary() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.onSecondary {
+		return false
+	}
+	now := f.now()
+	if now.Sub(f.lastProbe) >= f.probe {
+		f.lastProbe = now
+		return false
+	}
+	return true
+}
+
+// report records a primary attempt and returns whether the export should continue on the secondary.
+func (f *Failover) report(err error) bool {
+	f.mu.Lock()
+	if err == nil {
+		f.failures = 0
+		f.onSecondary = false
+		f.mu.Unlock()
+		return false
+	}
+	f.failures++
+	if f.failures < f.threshold {
+		f.mu.Unlock()
+		return false
+	}
+	switched := !f.onSecondary
+	f.onSecondary = true
+	f.lastProbe = f.now()
+	failures := f.failures
+	f.mu.Unlock()
+	if switched {
+		LogExportFailure(f.component, f.transport, fmt.Errorf("failing over to secondary endpoint after %d consecutive failures: %w", failures, err))
+	}
+	return true
+}
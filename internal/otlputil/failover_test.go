@@ -0,0 +1,79 @@
+package otlputil
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFailoverSwitchesAfterThreshold(t *testing.T) {
+	f := NewFailover("test", "http", 2, time.Minute)
+	failing := errors.New("unavailable")
+	var primaryCalls, secondaryCalls int
+	primary := func() error { primaryCalls++; return failing }
+	secondary := func() error { secondaryCalls++; return nil }
+
+	if err := f.Do(primary, secondary); !errors.Is(err, failing) {
+		t.Fatalf("expected primary error below threshold, got %v", err)
+	}
+	if f.Active() {
+		t.Fatal("failover should not be active after one failure")
+	}
+	if err := f.Do(primary, secondary); err != nil {
+		t.Fatalf("expected secondary to take over in the same call, got %v", err)
+	}
+	if !f.Active() || secondaryCalls != 1 {
+		t.Fatalf("expected failover after threshold, active=%v secondary calls=%d", f.Active(), secondaryCalls)
+	}
+	if err := f.Do(primary, secondary); err != nil {
+		t.Fatalf("Do on secondary: %v", err)
+	}
+	if primaryCalls != 2 || secondaryCalls != 2 {
+		t.Fatalf("primary should not be retried inside the probe interval, primary=%d secondary=%d", primaryCalls, secondaryCalls)
+	}
+}
+
+func TestFailoverProbesPrimaryAndFailsBack(t *testing.T) {
+	now := time.Unix(0, 0)
+	f := NewFailover("test", "http", 1, 30*time.Second)
+	f.now = func() time.Time { return now }
+
+	primaryErr := errors.New("down")
+	primary := func() error { return primaryErr }
+	secondary := func() error { return nil }
+	if err := f.Do(primary, secondary); err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if !f.Active() {
+		t.Fatal("expected failover to be active")
+	}
+
+	now = now.Add(31 * time.Second)
+	if err := f.Do(primary, secondary); err != nil {
+		t.Fatalf("failed probe should fall through to secondary, got %v", err)
+	}
+	if !f.Active() {
+		t.Fatal("failed probe should keep failover active")
+	}
+
+	now = now.Add(31 * time.Second)
+	primaryErr = nil
+	var secondaryUsed bool
+	if err := f.Do(primary, func() error { secondaryUsed = true; return nil }); err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if f.Active() || secondaryUsed {
+		t.Fatalf("expected fail back to primary, active=%v secondaryUsed=%v", f.Active(), secondaryUsed)
+	}
+}
+
+func TestFailoverNilRunsPrimary(t *testing.T) {
+	var f *Failover
+	called := false
+	if err := f.Do(func() error { called = true; return nil }, func() error { return errors.New("unexpected") }); err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	if !called || f.Active() {
+		t.Fatal("nil failover should always use the primary")
+	}
+}
@@ -0,0 +1,47 @@
+package otlputil
+
+import "context"
+
+// Lazy builds a value, typically an exporter, in the background so the caller does not wait for
+// dials, TLS handshakes, or spool replays.
+type Lazy[T any] struct {
+	done  chan struct{}
+	value T
+	err   error
+}
+
+// NewLazy starts build in a goroutine. When build fails, the error is reported through
+// LogExportFailure for component with the "init" transport and returned by every Wait.
+func NewLazy[T any](component string, build func() (T, error)) *Lazy[T] {
+	l := &Lazy[T]{done: make(chan struct{})}
+	go func() {
+		defer close(l.done)
+		l.value, l.err = build()
+		if l.err != nil {
+			LogExportFailure(component, "init", l.err)
+		}
+	}()
+	return l
+}
+
+// Wait blocks until the value is built or ctx is done.
+func (l *Lazy[T]) Wait(ctx context.Context) (T, error) {
+	select {
+	case <-l.done:
+		return l.value, l.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// Get returns the value without waiting, and false while it is being built or when building failed.
+func (l *Lazy[T]) Get() (T, bool) {
+	select {
+	case <-l.done:
+		return l.value, l.err == nil
+	default:
+		var zero T
+		return zero, false
+	}
+}
@@ -0,0 +1,53 @@
+package otlputil
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestLazyWaitsForBuild(t *testing.T) {
+	release := make(chan struct{})
+	lazy := NewLazy("tracer", func() (int, error) {
+		<-release
+		return 42, nil
+	})
+
+	if _, ok := lazy.Get(); ok {
+		t.Fatal("expected Get to report the value as not ready")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := lazy.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the context error while building, got %v", err)
+	}
+
+	close(release)
+	value, err := lazy.Wait(context.Background())
+	if err != nil || value != 42 {
+		t.Fatalf("Wait = %d, %v", value, err)
+	}
+	if value, ok := lazy.Get(); !ok || value != 42 {
+		t.Fatalf("Get = %d, %v", value, ok)
+	}
+}
+
+func TestLazyReportsBuildFailure(t *testing.T) {
+	var reported []string
+	SetExportFailureHandler(func(component, transport string, err error) {
+		reported = append(reported, component+"/"+transport)
+	})
+	defer SetExportFailureHandler(nil)
+
+	buildErr := errors.New("dial failed")
+	lazy := NewLazy("meter", func() (int, error) { return 0, buildErr })
+	if _, err := lazy.Wait(context.Background()); !errors.Is(err, buildErr) {
+		t.Fatalf("expected the build error, got %v", err)
+	}
+	if _, ok := lazy.Get(); ok {
+		t.Fatal("expected Get to report a failed build")
+	}
+	if len(reported) != 1 || reported[0] != "meter/init" {
+		t.Fatalf("expected one init failure report, got %v", reported)
+	}
+}
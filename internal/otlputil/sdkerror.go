@@ -0,0 +1,112 @@
+package otlputil
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// maxSDKErrorKeys bounds how many distinct error messages SDKErrorHandler tracks; messages beyond
+// it share one limit so errors embedding ids or addresses cannot grow the map without bound.
+const maxSDKErrorKeys = 256
+
+const sdkErrorOverflowKey = "\x00overflow"
+
+var sdkHandlers atomic.Value // func(error)
+
+func init() {
+	sdkHandlers.Store(defaultSDKErrorLog)
+}
+
+// SetSDKErrorHandler overrides where SDKErrorHandler sends the errors it lets through.
+// Passing nil restores the default stderr logger.
+func SetSDKErrorHandler(handler func(err error)) {
+	if handler == nil {
+		handler = defaultSDKErrorLog
+	}
+	sdkHandlers.Store(handler)
+}
+
+// SDKErrorHandler is an otel.ErrorHandler for errors the SDK raises outside any exporter call, such
+// as dropped spans or conflicting instruments. Within interval only the first error with a given
+// message is passed to the handler set by SetSDKErrorHandler; at the end of the interval the last
+// suppressed one is passed with how many were suppressed.
+type SDKErrorHandler struct {
+	interval time.Duration
+	now      func() time.Time
+	inFlight sync.Map
+
+	mu     sync.Mutex
+	limits map[string]*failureLimit
+}
+
+// NewSDKErrorHandler returns a handler limiting repeated messages to one per interval. Zero or less
+// logs every error.
+func NewSDKErrorHandler(interval time.Duration) *SDKErrorHandler {
+	return &SDKErrorHandler{
+		interval: interval,
+		now:      time.Now,
+		limits:   make(map[string]*failureLimit),
+	}
+}
+
+// Handle implements otel.ErrorHandler.
+func (h *SDKErrorHandler) Handle(err error) {
+	if err == nil {
+		return
+	}
+	allowed, suppressed := h.limit(err.Error()).allow(h.now, h.interval, err, func(last error, suppressed uint64) {
+		h.report(fmt.Errorf("%w (%d similar errors suppressed)", last, suppressed))
+	})
+	if !allowed {
+		return
+	}
+	if suppressed > 0 {
+		err = fmt.Errorf("%w (%d similar errors suppressed)", err, suppressed)
+	}
+	h.report(err)
+}
+
+func (h *SDKErrorHandler) report(err error) {
+	// The same error raised while the handler logs it would otherwise re-enter the handler.
+	key := err.Error()
+	if _, loaded := h.inFlight.LoadOrStore(key, struct{}{}); loaded {
+		defaultSDKErrorLog(err)
+		return
+	}
+	defer h.inFlight.Delete(key)
+
+	handler, _ := sdkHandlers.Load().(func(error))
+	if handler == nil {
+		handler = defaultSDKErrorLog
+	}
+	handler(err)
+}
+
+func (h *SDKErrorHandler) limit(message string) *failureLimit {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if limit, ok := h.limits[message]; ok {
+		return limit
+	}
+	if len(h.limits) >= maxSDKErrorKeys {
+		message = sdkErrorOverflowKey
+		if limit, ok := h.limits[message]; ok {
+			return limit
+		}
+	}
+	limit := &failureLimit{}
+	h.limits[message] = limit
+	return limit
+}
+
+func defaultSDKErrorLog(err error) {
+	if err == nil {
+		return
+	}
+	exportLogMu.Lock()
+	defer exportLogMu.Unlock()
+	_, _ = os.Stderr.WriteString("goo11y otel error: " + err.Error() + "\n")
+}
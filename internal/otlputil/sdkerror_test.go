@@ -0,0 +1,63 @@
+package otlputil
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSDKErrorHandlerSuppressesRepeats(t *testing.T) {
+	var logged []string
+	SetSDKErrorHandler(func(err error) { logged = append(logged, err.Error()) })
+	defer SetSDKErrorHandler(nil)
+
+	now := time.Unix(0, 0)
+	handler := NewSDKErrorHandler(time.Minute)
+	handler.now = func() time.Time { return now }
+
+	for range 3 {
+		handler.Handle(errors.New("span dropped"))
+	}
+	handler.Handle(errors.New("instrument conflict"))
+	handler.Handle(nil)
+	now = now.Add(time.Minute)
+	handler.Handle(errors.New("span dropped"))
+
+	want := []string{"span dropped", "instrument conflict", "span dropped (2 similar errors suppressed)"}
+	if fmt.Sprint(logged) != fmt.Sprint(want) {
+		t.Fatalf("unexpected logged errors %q", logged)
+	}
+}
+
+func TestSDKErrorHandlerBoundsTrackedMessages(t *testing.T) {
+	var logged int
+	SetSDKErrorHandler(func(error) { logged++ })
+	defer SetSDKErrorHandler(nil)
+
+	handler := NewSDKErrorHandler(time.Hour)
+	for i := range maxSDKErrorKeys + 10 {
+		handler.Handle(fmt.Errorf("span %d dropped", i))
+	}
+	if len(handler.limits) != maxSDKErrorKeys+1 {
+		t.Fatalf("expected %d tracked messages, got %d", maxSDKErrorKeys+1, len(handler.limits))
+	}
+	if logged != maxSDKErrorKeys+1 {
+		t.Fatalf("expected overflow messages to share one limit, logged %d", logged)
+	}
+}
+
+func TestSDKErrorHandlerRecursionGuard(t *testing.T) {
+	handler := NewSDKErrorHandler(0)
+	var calls int
+	SetSDKErrorHandler(func(err error) {
+		calls++
+		handler.Handle(err)
+	})
+	defer SetSDKErrorHandler(nil)
+
+	handler.Handle(errors.New("recursion"))
+	if calls != 1 {
+		t.Fatalf("expected handler to run once, got %d", calls)
+	}
+}
@@ -0,0 +1,313 @@
+package otlputil
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/tls"
+	"io"
+	"net/http"
+	"time"
+
+	collogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetric "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	coltrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// item addresses one span, log record, or metric by its resource and scope positions.
+type item struct {
+	resource, scope, index int
+}
+
+// SplitRequest divides an OTLP trace, log, or metric export request whose encoded size exceeds
+// maxBytes into requests of the same type that each fit, keeping resources and scopes intact. A
+// single span, log record, or metric is never divided, so a part holding one may still exceed
+// maxBytes. Other messages, and requests already within maxBytes, are returned as they are.
+func SplitRequest(req proto.Message, maxBytes int) []proto.Message {
+	if maxBytes <= 0 || proto.Size(req) <= maxBytes {
+		return []proto.Message{req}
+	}
+	switch r := req.(type) {
+	case *coltrace.ExportTraceServiceRequest:
+		return split(r, maxBytes, traceItems(r), buildTraces)
+	case *collogs.ExportLogsServiceRequest:
+		return split(r, maxBytes, logItems(r), buildLogs)
+	case *colmetric.ExportMetricsServiceRequest:
+		return split(r, maxBytes, metricItems(r), buildMetrics)
+	default:
+		return []proto.Message{req}
+	}
+}
+
+// split halves items until every part built from them fits in maxBytes.
+func split[R proto.Message](req R, maxBytes int, items []item, build func(R, []item) R) []proto.Message {
+	if len(items) <= 1 {
+		return []proto.Message{req}
+	}
+	var parts []proto.Message
+	var walk func([]item)
+	walk = func(items []item) {
+		part := build(req, items)
+		if len(items) == 1 || proto.Size(part) <= maxBytes {
+			parts = append(parts, part)
+			return
+		}
+		half := len(items) / 2
+		walk(items[:half])
+		walk(items[half:])
+	}
+	walk(items)
+	return parts
+}
+
+func traceItems(req *coltrace.ExportTraceServiceRequest) []item {
+	var items []item
+	for r, rs := range req.GetResourceSpans() {
+		for s, ss := range rs.GetScopeSpans() {
+			for i := range ss.GetSpans() {
+				items = append(items, item{r, s, i})
+			}
+		}
+	}
+	return items
+}
+
+func buildTraces(req *coltrace.ExportTraceServiceRequest, items []item) *coltrace.ExportTraceServiceRequest {
+	out := &coltrace.ExportTraceServiceRequest{}
+	var resource *tracepb.ResourceSpans
+	var scope *tracepb.ScopeSpans
+	lastResource, lastScope := -1, -1
+	for _, it := range items {
+		src := req.ResourceSpans[it.resource]
+		if it.resource != lastResource {
+			resource = &tracepb.ResourceSpans{Resource: src.Resource, SchemaUrl: src.SchemaUrl}
+			out.ResourceSpans = append(out.ResourceSpans, resource)
+			lastResource, lastScope = it.resource, -1
+		}
+		if it.scope != lastScope {
+			scopeSrc := src.ScopeSpans[it.scope]
+			scope = &tracepb.ScopeSpans{Scope: scopeSrc.Scope, SchemaUrl: scopeSrc.SchemaUrl}
+			resource.ScopeSpans = append(resource.ScopeSpans, scope)
+			lastScope = it.scope
+		}
+		scope.Spans = append(scope.Spans, src.ScopeSpans[it.scope].Spans[it.index])
+	}
+	return out
+}
+
+func logItems(req *collogs.ExportLogsServiceRequest) []item {
+	var items []item
+	for r, rl := range req.GetResourceLogs() {
+		for s, sl := range rl.GetScopeLogs() {
+			for i := range sl.GetLogRecords() {
+				items = append(items, item{r, s, i})
+			}
+		}
+	}
+	return items
+}
+
+func buildLogs(req *collogs.ExportLogsServiceRequest, items []item) *collogs.ExportLogsServiceRequest {
+	out := &collogs.ExportLogsServiceRequest{}
+	var resource *logspb.ResourceLogs
+	var scope *logspb.ScopeLogs
+	lastResource, lastScope := -1, -1
+	for _, it := range items {
+		src := req.ResourceLogs[it.resource]
+		if it.resource != lastResource {
+			resource = &logspb.ResourceLogs{Resource: src.Resource, SchemaUrl: src.SchemaUrl}
+			out.ResourceLogs = append(out.ResourceLogs, resource)
+			lastResource, lastScope = it.resource, -1
+		}
+		if it.scope != lastScope {
+			scopeSrc := src.ScopeLogs[it.scope]
+			scope = &logspb.ScopeLogs{Scope: scopeSrc.Scope, SchemaUrl: scopeSrc.SchemaUrl}
+			resource.ScopeLogs = append(resource.ScopeLogs, scope)
+			lastScope = it.scope
+		}
+		scope.LogRecords = append(scope.LogRecords, src.ScopeLogs[it.scope].LogRecords[it.index])
+	}
+	return out
+}
+
+func metricItems(req *colmetric.ExportMetricsServiceRequest) []item {
+	var items []item
+	for r, rm := range req.GetResourceMetrics() {
+		for s, sm := range rm.GetScopeMetrics() {
+			for i := range sm.GetMetrics() {
+				items = append(items, item{r, s, i})
+			}
+		}
+	}
+	return items
+}
+
+func buildMetrics(req *colmetric.ExportMetricsServiceRequest, items []item) *colmetric.ExportMetricsServiceRequest {
+	out := &colmetric.ExportMetricsServiceRequest{}
+	var resource *metricspb.ResourceMetrics
+	var scope *metricspb.ScopeMetrics
+	lastResource, lastScope := -1, -1
+	for _, it := range items {
+		src := req.ResourceMetrics[it.resource]
+		if it.resource != lastResource {
+			resource = &metricspb.ResourceMetrics{Resource: src.Resource, SchemaUrl: src.SchemaUrl}
+			out.ResourceMetrics = append(out.ResourceMetrics, resource)
+			lastResource, lastScope = it.resource, -1
+		}
+		if it.scope != lastScope {
+			scopeSrc := src.ScopeMetrics[it.scope]
+			scope = &metricspb.ScopeMetrics{Scope: scopeSrc.Scope, SchemaUrl: scopeSrc.SchemaUrl}
+			resource.ScopeMetrics = append(resource.ScopeMetrics, scope)
+			lastScope = it.scope
+		}
+		scope.Metrics = append(scope.Metrics, src.ScopeMetrics[it.scope].Metrics[it.index])
+	}
+	return out
+}
+
+// SplitInterceptor returns a gRPC interceptor sending export requests larger than maxBytes as
+// several smaller calls. It stops at the first failing part and returns its error, so when the
+// exporter retries the whole request, the parts accepted before the failure are sent again:
+// delivery of a split request is at least once, and a collector may see those records twice.
+func SplitInterceptor(maxBytes int) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		msg, ok := req.(proto.Message)
+		if !ok || maxBytes <= 0 {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		for _, part := range SplitRequest(msg, maxBytes) {
+			if err := invoker(ctx, method, part, reply, cc, opts...); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// NewHTTPClient returns a client like the one OTLP HTTP exporters build for themselves, trusting
+// tlsConfig when it is set.
+func NewHTTPClient(timeout time.Duration, tlsConfig *tls.Config) *http.Client {
+	client := &http.Client{Timeout: timeout}
+	if base, ok := http.DefaultTransport.(*http.Transport); ok {
+		transport := base.Clone()
+		if tlsConfig != nil {
+			transport.TLSClientConfig = tlsConfig
+		}
+		client.Transport = transport
+	}
+	return client
+}
+
+// SplitHTTPClient returns a copy of client whose protobuf export requests larger than maxBytes
+// are decoded into newRequest and sent as several smaller requests. Gzip bodies are measured and
+// split uncompressed, and each part is compressed again; other encodings are sent as they are.
+// Responses of parts that succeed are discarded; the first failing response, or the last one, is
+// returned. As with SplitInterceptor, a retry of the whole request resends the parts accepted
+// before the failure, so delivery is at least once. A non-positive maxBytes returns client
+// unchanged.
+func SplitHTTPClient(client *http.Client, maxBytes int, newRequest func() proto.Message) *http.Client {
+	if maxBytes <= 0 {
+		return client
+	}
+	wrapped := *client
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	wrapped.Transport = &splitTransport{base: base, maxBytes: maxBytes, newRequest: newRequest}
+	return &wrapped
+}
+
+type splitTransport struct {
+	base       http.RoundTripper
+	maxBytes   int
+	newRequest func() proto.Message
+}
+
+func (t *splitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	encoding := req.Header.Get("Content-Encoding")
+	// A compressed body may be small on the wire and still exceed maxBytes once decoded.
+	if req.Body == nil || encoding == "" && req.ContentLength >= 0 && req.ContentLength <= int64(t.maxBytes) ||
+		req.Header.Get("Content-Type") != "application/x-protobuf" || encoding != "" && encoding != "gzip" {
+		return t.base.RoundTrip(req)
+	}
+	body, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	payload := body
+	if encoding == "gzip" {
+		if payload, err = gunzip(body); err != nil {
+			return t.base.RoundTrip(withBody(req, body))
+		}
+	}
+	msg := t.newRequest()
+	if len(payload) <= t.maxBytes || proto.Unmarshal(payload, msg) != nil {
+		return t.base.RoundTrip(withBody(req, body))
+	}
+	parts := SplitRequest(msg, t.maxBytes)
+	if len(parts) == 1 {
+		return t.base.RoundTrip(withBody(req, body))
+	}
+
+	var resp *http.Response
+	for i, part := range parts {
+		data, err := proto.Marshal(part)
+		if err != nil {
+			return nil, err
+		}
+		if encoding == "gzip" {
+			if data, err = gzipBytes(data); err != nil {
+				return nil, err
+			}
+		}
+		resp, err = t.base.RoundTrip(withBody(req, data))
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices || i == len(parts)-1 {
+			return resp, nil
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}
+	return resp, nil
+}
+
+func gunzip(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+	return io.ReadAll(reader)
+}
+
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// withBody clones req with body as its payload.
+func withBody(req *http.Request, body []byte) *http.Request {
+	clone := req.Clone(req.Context())
+	clone.Body = io.NopCloser(bytes.NewReader(body))
+	clone.ContentLength = int64(len(body))
+	clone.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return clone
+}
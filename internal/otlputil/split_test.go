@@ -0,0 +1,207 @@
+package otlputil
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	collogs "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+func logsRequest(resources, records int) *collogs.ExportLogsServiceRequest {
+	req := &collogs.ExportLogsServiceRequest{}
+	for r := range resources {
+		scope := &logspb.ScopeLogs{Scope: &commonpb.InstrumentationScope{Name: "scope"}}
+		for range records {
+			scope.LogRecords = append(scope.LogRecords, &logspb.LogRecord{
+				Body: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: strings.Repeat("x", 100)}},
+			})
+		}
+		req.ResourceLogs = append(req.ResourceLogs, &logspb.ResourceLogs{
+			Resource: &resourcepb.Resource{Attributes: []*commonpb.KeyValue{{
+				Key:   "service.name",
+				Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: string(rune('a' + r))}},
+			}}},
+			ScopeLogs: []*logspb.ScopeLogs{scope},
+		})
+	}
+	return req
+}
+
+func countRecords(parts []proto.Message) (records int, services map[string]int) {
+	services = make(map[string]int)
+	for _, part := range parts {
+		for _, rl := range part.(*collogs.ExportLogsServiceRequest).ResourceLogs {
+			name := rl.Resource.Attributes[0].Value.GetStringValue()
+			for _, sl := range rl.ScopeLogs {
+				if sl.Scope.GetName() != "scope" {
+					panic("scope lost")
+				}
+				records += len(sl.LogRecords)
+				services[name] += len(sl.LogRecords)
+			}
+		}
+	}
+	return records, services
+}
+
+func TestSplitRequestKeepsRecordsAndResources(t *testing.T) {
+	req := logsRequest(2, 40)
+	parts := SplitRequest(req, 1024)
+	if len(parts) < 8 {
+		t.Fatalf("expected the request to be split, got %d parts", len(parts))
+	}
+	for _, part := range parts {
+		if size := proto.Size(part); size > 1024 {
+			t.Fatalf("part of %d bytes exceeds the limit", size)
+		}
+	}
+	records, services := countRecords(parts)
+	if records != 80 || services["a"] != 40 || services["b"] != 40 {
+		t.Fatalf("expected every record kept under its resource, got %d records %v", records, services)
+	}
+
+	if parts := SplitRequest(req, 0); len(parts) != 1 || parts[0] != proto.Message(req) {
+		t.Fatal("expected no split without a limit")
+	}
+	single := logsRequest(1, 1)
+	if parts := SplitRequest(single, 10); len(parts) != 1 {
+		t.Fatalf("expected a single record to stay whole, got %d parts", len(parts))
+	}
+}
+
+func TestSplitInterceptorInvokesEachPart(t *testing.T) {
+	var calls int
+	invoker := func(_ context.Context, _ string, req, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		calls++
+		if size := proto.Size(req.(proto.Message)); size > 2048 {
+			t.Errorf("call of %d bytes exceeds the limit", size)
+		}
+		return nil
+	}
+	err := SplitInterceptor(2048)(context.Background(), "/export", logsRequest(1, 60), &collogs.ExportLogsServiceResponse{}, nil, invoker)
+	if err != nil {
+		t.Fatalf("interceptor: %v", err)
+	}
+	if calls < 4 {
+		t.Fatalf("expected several calls, got %d", calls)
+	}
+}
+
+func TestSplitHTTPClientSendsParts(t *testing.T) {
+	var mu sync.Mutex
+	var received []proto.Message
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if len(body) > 2048 {
+			t.Errorf("request of %d bytes exceeds the limit", len(body))
+		}
+		msg := &collogs.ExportLogsServiceRequest{}
+		if err := proto.Unmarshal(body, msg); err != nil {
+			t.Errorf("unmarshal: %v", err)
+		}
+		mu.Lock()
+		received = append(received, msg)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	client := SplitHTTPClient(srv.Client(), 2048, func() proto.Message { return new(collogs.ExportLogsServiceRequest) })
+	body, err := proto.Marshal(logsRequest(2, 30))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader(string(body)))
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unexpected status %d", resp.StatusCode)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) < 2 {
+		t.Fatalf("expected several requests, got %d", len(received))
+	}
+	if records, _ := countRecords(received); records != 60 {
+		t.Fatalf("expected 60 records delivered, got %d", records)
+	}
+}
+
+func TestSplitHTTPClientSplitsGzipBodies(t *testing.T) {
+	var mu sync.Mutex
+	var received []proto.Message
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Encoding") != "gzip" {
+			t.Errorf("expected a gzip part, got encoding %q", r.Header.Get("Content-Encoding"))
+		}
+		compressed, _ := io.ReadAll(r.Body)
+		body, err := gunzip(compressed)
+		if err != nil {
+			t.Errorf("gunzip: %v", err)
+		}
+		if len(body) > 2048 {
+			t.Errorf("request of %d bytes uncompressed exceeds the limit", len(body))
+		}
+		msg := &collogs.ExportLogsServiceRequest{}
+		if err := proto.Unmarshal(body, msg); err != nil {
+			t.Errorf("unmarshal: %v", err)
+		}
+		mu.Lock()
+		received = append(received, msg)
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	client := SplitHTTPClient(srv.Client(), 2048, func() proto.Message { return new(collogs.ExportLogsServiceRequest) })
+	body, err := proto.Marshal(logsRequest(2, 30))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	compressed, err := gzipBytes(body)
+	if err != nil {
+		t.Fatalf("gzip: %v", err)
+	}
+	if len(compressed) > 2048 {
+		t.Fatalf("test body compresses to %d bytes; it must fit the limit to exercise decoding", len(compressed))
+	}
+	req, err := http.NewRequest(http.MethodPost, srv.URL, bytes.NewReader(compressed))
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("Content-Encoding", "gzip")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) < 2 {
+		t.Fatalf("expected several requests, got %d", len(received))
+	}
+	if records, _ := countRecords(received); records != 60 {
+		t.Fatalf("expected 60 records delivered, got %d", records)
+	}
+}
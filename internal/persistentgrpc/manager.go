@@ -3,14 +3,22 @@ package persistentgrpc
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/mfahmialkautsar/goo11y/internal/otlputil"
 	"github.com/mfahmialkautsar/goo11y/internal/spool"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
@@ -27,6 +35,77 @@ type Manager struct {
 	ctx         context.Context
 	cancel      context.CancelFunc
 	conn        atomic.Pointer[grpc.ClientConn]
+	secondary   *Secondary
+	endpoint    *otlputil.Endpoint
+	dialOpts    []grpc.DialOption
+	owned       *grpc.ClientConn
+	queueOpts   []spool.Option
+	timeout     time.Duration
+	policy      spool.StatusPolicy
+}
+
+// Secondary routes spooled requests over a fail-over connection whenever Failover selects it.
+// Headers replace the metadata keys of the same name, and StripHeaders names primary metadata,
+// such as credentials, that must not reach the secondary. Stop closes Conn.
+type Secondary struct {
+	Conn         *grpc.ClientConn
+	Headers      map[string]string
+	StripHeaders []string
+	Failover     *otlputil.Failover
+}
+
+// Option configures a Manager.
+type Option func(*Manager)
+
+// WithSecondary delivers spooled requests over secondary while its Failover is active.
+func WithSecondary(secondary Secondary) Option {
+	return func(m *Manager) {
+		if secondary.Conn != nil {
+			m.secondary = &secondary
+		}
+	}
+}
+
+// WithEndpoint makes the Manager dial its own connection to endpoint, so spooled requests drain after a
+// restart even before the exporter sends live traffic. A connection seen by the interceptor still takes
+// precedence. Stop closes the dialed connection.
+func WithEndpoint(endpoint otlputil.Endpoint, opts ...grpc.DialOption) Option {
+	return func(m *Manager) {
+		m.endpoint = &endpoint
+		m.dialOpts = opts
+	}
+}
+
+// WithQueueOptions configures the spool queue backing the Manager.
+func WithQueueOptions(opts ...spool.Option) Option {
+	return func(m *Manager) {
+		m.queueOpts = append(m.queueOpts, opts...)
+	}
+}
+
+// WithTimeout bounds each delivery of a spooled request; zero leaves deliveries unbounded.
+func WithTimeout(timeout time.Duration) Option {
+	return func(m *Manager) {
+		m.timeout = timeout
+	}
+}
+
+// WithStatusPolicy overrides how deliveries failing with the listed gRPC status codes are handled.
+// By default the codes OTLP marks retryable are retried, honoring RetryInfo, and the rest dropped.
+func WithStatusPolicy(policy spool.StatusPolicy) Option {
+	return func(m *Manager) {
+		m.policy = policy
+	}
+}
+
+// Dial opens a client connection to endpoint, using TLS unless the endpoint is insecure.
+func Dial(endpoint otlputil.Endpoint, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	creds := credentials.NewClientTLSFromCert(nil, "")
+	if endpoint.Insecure {
+		creds = insecure.NewCredentials()
+	}
+	opts = append([]grpc.DialOption{grpc.WithTransportCredentials(creds)}, opts...)
+	return grpc.NewClient(endpoint.HostWithPath(), opts...)
 }
 
 type envelope struct {
@@ -38,23 +117,37 @@ type envelope struct {
 type bypassKey struct{}
 
 // NewManager creates a new Manager instance that spools requests to the specified queue directory.
-func NewManager(queueDir, component, transport, method string, newReq, newResp func() proto.Message) (*Manager, error) {
-	queue, err := spool.NewWithErrorLogger(queueDir, spool.ErrorLoggerFunc(func(err error) {
-		otlputil.LogExportFailure(component, transport, err)
-	}))
-	if err != nil {
-		return nil, fmt.Errorf("persistentgrpc: create queue: %w", err)
-	}
-	ctx, cancel := context.WithCancel(context.Background())
+func NewManager(queueDir, component, transport, method string, newReq, newResp func() proto.Message, opts ...Option) (*Manager, error) {
 	m := &Manager{
 		component:   component,
 		transport:   transport,
 		method:      method,
 		newRequest:  newReq,
 		newResponse: newResp,
-		queue:       queue,
-		ctx:         ctx,
-		cancel:      cancel,
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(m)
+		}
+	}
+	queue, err := spool.NewWithErrorLogger(queueDir, spool.ErrorLoggerFunc(func(err error) {
+		otlputil.LogExportFailure(component, transport, err)
+	}), m.queueOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("persistentgrpc: create queue: %w", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.queue = queue
+	m.ctx = ctx
+	m.cancel = cancel
+	if m.endpoint != nil {
+		conn, err := Dial(*m.endpoint, m.dialOpts...)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("persistentgrpc: dial: %w", err)
+		}
+		m.owned = conn
+		m.conn.Store(conn)
 	}
 	m.start()
 	return m, nil
@@ -71,7 +164,34 @@ func (m *Manager) Stop(context.Context) error {
 	if m.cancel != nil {
 		m.cancel()
 	}
-	return nil
+	var errs error
+	if m.owned != nil {
+		errs = errors.Join(errs, m.owned.Close())
+	}
+	if m.secondary != nil {
+		errs = errors.Join(errs, m.secondary.Conn.Close())
+	}
+	return errs
+}
+
+// QueueDepth returns the number of requests waiting in the spool.
+func (m *Manager) QueueDepth() int {
+	if m == nil || m.queue == nil {
+		return 0
+	}
+	return m.queue.Len()
+}
+
+// QueueDropped returns the number of requests the spool discarded without delivering them.
+func (m *Manager) QueueDropped() uint64 {
+	if m == nil || m.queue == nil {
+		return 0
+	}
+	var total uint64
+	for _, n := range m.queue.Dropped() {
+		total += n
+	}
+	return total
 }
 
 // Interceptor returns a gRPC UnaryClientInterceptor that intercepts requests and spools them if the outgoing call fails.
@@ -138,18 +258,43 @@ func (m *Manager) handle(ctx context.Context, payload []byte) error {
 	if err := proto.Unmarshal(env.Payload, req); err != nil {
 		return spool.ErrCorrupt
 	}
-	conn := m.conn.Load()
-	if conn == nil {
-		return fmt.Errorf("persistentgrpc: connection unavailable")
+	primary := func() error {
+		conn := m.conn.Load()
+		if conn == nil {
+			return fmt.Errorf("persistentgrpc: connection unavailable")
+		}
+		return m.invoke(conn, env, req, nil)
 	}
-	callCtx := context.Background()
-	if len(env.Metadata) > 0 {
-		md := metadata.MD{}
-		for k, v := range env.Metadata {
-			copied := make([]string, len(v))
-			copy(copied, v)
-			md[k] = copied
+	if m.secondary == nil {
+		return primary()
+	}
+	return m.secondary.Failover.Do(primary, func() error {
+		return m.invoke(m.secondary.Conn, env, req, m.secondary)
+	})
+}
+
+func (m *Manager) invoke(conn *grpc.ClientConn, env envelope, req proto.Message, secondary *Secondary) error {
+	md := metadata.MD{}
+	for k, v := range env.Metadata {
+		copied := make([]string, len(v))
+		copy(copied, v)
+		md[k] = copied
+	}
+	if secondary != nil {
+		for _, key := range secondary.StripHeaders {
+			delete(md, strings.ToLower(key))
+		}
+		for key, value := range secondary.Headers {
+			md.Set(strings.ToLower(key), value)
 		}
+	}
+	callCtx := context.Background()
+	if m.timeout > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(callCtx, m.timeout)
+		defer cancel()
+	}
+	if len(md) > 0 {
 		callCtx = metadata.NewOutgoingContext(callCtx, md)
 	}
 	callCtx = context.WithValue(callCtx, bypassKey{}, struct{}{})
@@ -160,7 +305,54 @@ func (m *Manager) handle(ctx context.Context, payload []byte) error {
 	}
 	if err := conn.Invoke(callCtx, env.Method, req, resp); err != nil {
 		otlputil.LogExportFailure(m.component, m.transport, err)
-		return err
+		return classifyCode(err, m.policy)
 	}
 	return nil
 }
+
+// defaultStatusAction retries the codes the OTLP specification marks retryable and drops the
+// others, such as INVALID_ARGUMENT or UNAUTHENTICATED, since sending the same request again cannot
+// succeed.
+func defaultStatusAction(code codes.Code) spool.StatusAction {
+	switch code {
+	case codes.Canceled, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted,
+		codes.OutOfRange, codes.Unavailable, codes.DataLoss:
+		return spool.StatusRetry
+	default:
+		return spool.StatusDrop
+	}
+}
+
+// classifyCode turns a failed delivery into the error the queue acts on. Errors that carry no gRPC
+// status, such as a missing connection, are retried.
+func classifyCode(err error, policy spool.StatusPolicy) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	action, ok := policy[int(st.Code())]
+	if !ok {
+		action = defaultStatusAction(st.Code())
+	}
+	if action == spool.StatusRetry {
+		if delay := throttleDelay(err); delay > 0 {
+			return &spool.ThrottledError{Err: err, RetryAfter: delay}
+		}
+	}
+	return action.Reject(err)
+}
+
+// throttleDelay returns the delay a RESOURCE_EXHAUSTED or UNAVAILABLE status asks for through its
+// RetryInfo detail, which is how OTLP collectors signal overload, or zero when there is none.
+func throttleDelay(err error) time.Duration {
+	st, ok := status.FromError(err)
+	if !ok || (st.Code() != codes.ResourceExhausted && st.Code() != codes.Unavailable) {
+		return 0
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok && info.GetRetryDelay() != nil {
+			return info.GetRetryDelay().AsDuration()
+		}
+	}
+	return 0
+}
@@ -2,14 +2,22 @@ package persistentgrpc
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"net"
 	"testing"
 	"time"
 
+	"github.com/mfahmialkautsar/goo11y/internal/otlputil"
+	"github.com/mfahmialkautsar/goo11y/internal/spool"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
 
 	coltrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
 )
@@ -80,3 +88,154 @@ func TestManagerReplaysRequests(t *testing.T) {
 
 	waitForQueueDrain(t, queueDir)
 }
+
+func TestManagerDrainsSpoolWithOwnConnection(t *testing.T) {
+	t.Parallel()
+
+	queueDir := t.TempDir()
+	method := "/opentelemetry.proto.collector.trace.v1.TraceService/Export"
+
+	req := &coltrace.ExportTraceServiceRequest{}
+	payload, err := proto.Marshal(req)
+	if err != nil {
+		t.Fatalf("proto.Marshal: %v", err)
+	}
+	data, err := json.Marshal(envelope{
+		Method:   method,
+		Metadata: map[string][]string{"x-test": {"value"}},
+		Payload:  payload,
+	})
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	queue, err := spool.New(queueDir)
+	if err != nil {
+		t.Fatalf("spool.New: %v", err)
+	}
+	if _, err := queue.Enqueue(data); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	server := &traceServer{received: make(chan traceRequest, 1)}
+	grpcServer := grpc.NewServer()
+	coltrace.RegisterTraceServiceServer(grpcServer, server)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		_ = grpcServer.Serve(listener)
+	}()
+	t.Cleanup(grpcServer.Stop)
+
+	manager, err := NewManager(
+		queueDir,
+		"tracer",
+		"grpc",
+		method,
+		func() proto.Message { return new(coltrace.ExportTraceServiceRequest) },
+		func() proto.Message { return new(coltrace.ExportTraceServiceResponse) },
+		WithEndpoint(otlputil.Endpoint{Host: listener.Addr().String(), Insecure: true}),
+	)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	t.Cleanup(func() { _ = manager.Stop(context.Background()) })
+
+	select {
+	case received := <-server.received:
+		if got := received.md.Get("x-test"); len(got) != 1 || got[0] != "value" {
+			t.Fatalf("metadata missing: %#v", received.md)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for replay without live traffic")
+	}
+
+	waitForQueueDrain(t, queueDir)
+}
+
+func TestManagerTimeoutBoundsDelivery(t *testing.T) {
+	t.Parallel()
+
+	method := "/opentelemetry.proto.collector.trace.v1.TraceService/Export"
+	server := &traceServer{received: make(chan traceRequest)}
+	grpcServer := grpc.NewServer()
+	coltrace.RegisterTraceServiceServer(grpcServer, server)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen: %v", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		_ = grpcServer.Serve(listener)
+	}()
+	t.Cleanup(grpcServer.Stop)
+
+	manager, err := NewManager(
+		t.TempDir(),
+		"tracer",
+		"grpc",
+		method,
+		func() proto.Message { return new(coltrace.ExportTraceServiceRequest) },
+		func() proto.Message { return new(coltrace.ExportTraceServiceResponse) },
+		WithEndpoint(otlputil.Endpoint{Host: listener.Addr().String(), Insecure: true}),
+		WithTimeout(50*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("NewManager: %v", err)
+	}
+	t.Cleanup(func() { _ = manager.Stop(context.Background()) })
+
+	begin := time.Now()
+	err = manager.invoke(manager.conn.Load(), envelope{Method: method}, &coltrace.ExportTraceServiceRequest{}, nil)
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("expected deadline exceeded from a stalled collector, got %v", err)
+	}
+	if elapsed := time.Since(begin); elapsed > 2*time.Second {
+		t.Fatalf("delivery was not bounded by the timeout, took %v", elapsed)
+	}
+}
+
+func TestThrottleDelayReadsRetryInfo(t *testing.T) {
+	throttled, err := status.New(codes.ResourceExhausted, "collector overloaded").
+		WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(12 * time.Second)})
+	if err != nil {
+		t.Fatalf("WithDetails: %v", err)
+	}
+	if got := throttleDelay(throttled.Err()); got != 12*time.Second {
+		t.Fatalf("throttleDelay = %v, want 12s", got)
+	}
+	if got := throttleDelay(status.Error(codes.ResourceExhausted, "no hint")); got != 0 {
+		t.Fatalf("expected no delay without RetryInfo, got %v", got)
+	}
+	withInfo, _ := status.New(codes.InvalidArgument, "bad").
+		WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(time.Second)})
+	if got := throttleDelay(withInfo.Err()); got != 0 {
+		t.Fatalf("expected no delay for a non-throttling code, got %v", got)
+	}
+}
+
+func TestClassifyCodeFollowsPolicy(t *testing.T) {
+	var rejected *spool.RejectedError
+	if err := classifyCode(status.Error(codes.Unavailable, "restarting"), nil); errors.As(err, &rejected) {
+		t.Fatalf("expected UNAVAILABLE to be retried, got %v", err)
+	}
+	if err := classifyCode(status.Error(codes.InvalidArgument, "bad"), nil); !errors.As(err, &rejected) || rejected.Quarantine {
+		t.Fatalf("expected INVALID_ARGUMENT to be dropped, got %v", err)
+	}
+	if err := classifyCode(errors.New("connection unavailable"), nil); errors.As(err, &rejected) {
+		t.Fatalf("expected errors without a status to be retried, got %v", err)
+	}
+
+	policy := spool.StatusPolicy{
+		int(codes.InvalidArgument): spool.StatusQuarantine,
+		int(codes.Unavailable):     spool.StatusDrop,
+	}
+	if err := classifyCode(status.Error(codes.InvalidArgument, "bad"), policy); !errors.As(err, &rejected) || !rejected.Quarantine {
+		t.Fatalf("expected the policy to quarantine INVALID_ARGUMENT, got %v", err)
+	}
+	if err := classifyCode(status.Error(codes.Unavailable, "gone"), policy); !errors.As(err, &rejected) || rejected.Quarantine {
+		t.Fatalf("expected the policy to drop UNAVAILABLE, got %v", err)
+	}
+}
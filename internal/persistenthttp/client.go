@@ -3,6 +3,7 @@ package persistenthttp
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,6 +11,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/mfahmialkautsar/goo11y/internal/otlputil"
 	"github.com/mfahmialkautsar/goo11y/internal/spool"
 )
 
@@ -27,8 +29,65 @@ func NewClient(queueDir string, timeout time.Duration) (*Client, error) {
 	return NewClientWithComponent(queueDir, timeout, "")
 }
 
+// Secondary routes queued requests to a fail-over URL whenever Failover selects it.
+// StripHeaders names headers of the primary, such as credentials, that must not reach URL.
+type Secondary struct {
+	URL          string
+	Headers      map[string]string
+	StripHeaders []string
+	Failover     *otlputil.Failover
+}
+
+// Option configures a Client.
+type Option func(*clientOptions)
+
+type clientOptions struct {
+	secondary *Secondary
+	queue     []spool.Option
+	tls       *tls.Config
+	policy    spool.StatusPolicy
+}
+
+// WithSecondary delivers queued requests to secondary while its Failover is active.
+func WithSecondary(secondary Secondary) Option {
+	return func(o *clientOptions) {
+		if secondary.URL != "" {
+			o.secondary = &secondary
+		}
+	}
+}
+
+// WithTLSConfig sends queued requests with cfg instead of the default TLS settings.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(o *clientOptions) {
+		o.tls = cfg
+	}
+}
+
+// WithStatusPolicy overrides how responses with the listed statuses are handled. By default 408,
+// 429, and 5xx responses are retried, honoring Retry-After, and other 4xx responses are dropped.
+func WithStatusPolicy(policy spool.StatusPolicy) Option {
+	return func(o *clientOptions) {
+		o.policy = policy
+	}
+}
+
+// WithQueueOptions configures the spool queue backing the Client.
+func WithQueueOptions(opts ...spool.Option) Option {
+	return func(o *clientOptions) {
+		o.queue = append(o.queue, opts...)
+	}
+}
+
 // NewClientWithComponent creates a new Client instance with a specific component name for logging.
-func NewClientWithComponent(queueDir string, timeout time.Duration, component string) (*Client, error) {
+func NewClientWithComponent(queueDir string, timeout time.Duration, component string, opts ...Option) (*Client, error) {
+	options := clientOptions{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&options)
+		}
+	}
+
 	queue, err := spool.NewWithErrorLogger(queueDir, spool.ErrorLoggerFunc(func(err error) {
 		if err == nil {
 			return
@@ -38,12 +97,15 @@ func NewClientWithComponent(queueDir string, timeout time.Duration, component st
 			prefix = "[" + component + "/spool]"
 		}
 		fmt.Fprintf(os.Stderr, "%s %v\n", prefix, err)
-	}))
+	}), options.queue...)
 	if err != nil {
 		return nil, err
 	}
 
 	transport := cloneDefaultTransport()
+	if httpTransport, ok := transport.(*http.Transport); ok && options.tls != nil {
+		httpTransport.TLSClientConfig = options.tls
+	}
 	workerClient := &http.Client{
 		Timeout:   timeout,
 		Transport: transport,
@@ -54,7 +116,11 @@ func NewClientWithComponent(queueDir string, timeout time.Duration, component st
 	if false {
 		cancel()
 	}
-	queue.Start(subCtx, spool.HTTPHandler(workerClient))
+	handler := spool.HTTPHandlerWithPolicy(workerClient, options.policy, queue.Clock())
+	if options.secondary != nil {
+		handler = failoverHandler(handler, *options.secondary)
+	}
+	queue.Start(subCtx, handler)
 
 	persistent := &transportWrapper{queue: queue}
 
@@ -69,6 +135,26 @@ func NewClientWithComponent(queueDir string, timeout time.Duration, component st
 	}, nil
 }
 
+// QueueDepth returns the number of requests waiting in the spool.
+func (c *Client) QueueDepth() int {
+	if c == nil || c.queue == nil {
+		return 0
+	}
+	return c.queue.Len()
+}
+
+// QueueDropped returns the number of requests the spool discarded without delivering them.
+func (c *Client) QueueDropped() uint64 {
+	if c == nil || c.queue == nil {
+		return 0
+	}
+	var total uint64
+	for _, n := range c.queue.Dropped() {
+		total += n
+	}
+	return total
+}
+
 // Close gracefully stops the background queue processing of the Client.
 func (c *Client) Close() error {
 	if c == nil {
@@ -82,6 +168,21 @@ func (c *Client) Close() error {
 	return nil
 }
 
+func failoverHandler(handler spool.Handler, secondary Secondary) spool.Handler {
+	return func(ctx context.Context, payload []byte) error {
+		return secondary.Failover.Do(
+			func() error { return handler(ctx, payload) },
+			func() error {
+				retargeted, err := spool.RetargetHTTPRequest(payload, secondary.URL, secondary.Headers, secondary.StripHeaders)
+				if err != nil {
+					return err
+				}
+				return handler(ctx, retargeted)
+			},
+		)
+	}
+}
+
 type transportWrapper struct {
 	queue *spool.Queue
 }
@@ -7,11 +7,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/mfahmialkautsar/goo11y/internal/otlputil"
 	"github.com/mfahmialkautsar/goo11y/internal/spool"
 	"github.com/mfahmialkautsar/goo11y/internal/testutil"
 )
@@ -265,3 +267,149 @@ func TestClientFailureDoesNotBlockNewRequests(t *testing.T) {
 		t.Fatalf("expected spool error log, got %q", output)
 	}
 }
+
+func TestClientFailsOverToSecondary(t *testing.T) {
+	queueDir := t.TempDir()
+
+	var fail atomic.Bool
+	fail.Store(true)
+	primaryResults := make(chan captured, 16)
+	primary := newFailingServer(t, &fail, primaryResults)
+	defer primary.Close()
+
+	authCh := make(chan string, 16)
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := io.Copy(io.Discard, r.Body); err != nil {
+			t.Fatalf("io.Copy: %v", err)
+		}
+		if err := r.Body.Close(); err != nil {
+			t.Fatalf("r.Body.Close: %v", err)
+		}
+		authCh <- r.Header.Get("Authorization") + "|" + r.Header.Get("X-Secondary")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer secondary.Close()
+
+	failover := otlputil.NewFailover("test", "http", 1, time.Hour)
+	client, err := NewClientWithComponent(queueDir, 100*time.Millisecond, "test", WithSecondary(Secondary{
+		URL:          secondary.URL,
+		Headers:      map[string]string{"X-Secondary": "yes"},
+		StripHeaders: []string{"Authorization"},
+		Failover:     failover,
+	}))
+	if err != nil {
+		t.Fatalf("NewClientWithComponent: %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	req, err := http.NewRequest(http.MethodPost, primary.URL, bytes.NewBufferString("switch"))
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	req.Header.Set("Authorization", "Bearer primary")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("client.Do: %v", err)
+	}
+	if err := resp.Body.Close(); err != nil {
+		t.Fatalf("resp.Body.Close: %v", err)
+	}
+
+	select {
+	case got := <-authCh:
+		if got != "|yes" {
+			t.Fatalf("unexpected secondary headers: %q", got)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for secondary delivery")
+	}
+	if !failover.Active() {
+		t.Fatal("expected failover to be active")
+	}
+	waitForQueueFiles(t, queueDir, func(n int) bool { return n == 0 })
+}
+
+func TestClientDropsRejectedRequests(t *testing.T) {
+	queueDir := t.TempDir()
+
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithComponent(queueDir, 100*time.Millisecond, "test")
+	if err != nil {
+		t.Fatalf("NewClientWithComponent: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+
+	req, err := http.NewRequest(http.MethodPost, server.URL, bytes.NewBufferString("bad"))
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("client.Do: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	deadline := time.After(3 * time.Second)
+	for client.QueueDropped() == 0 {
+		select {
+		case <-deadline:
+			t.Fatalf("expected rejected request to be dropped, attempts=%d", atomic.LoadInt32(&attempts))
+		default:
+			time.Sleep(20 * time.Millisecond)
+		}
+	}
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Fatalf("expected a single attempt for a 400 response, got %d", got)
+	}
+	if depth := client.QueueDepth(); depth != 0 {
+		t.Fatalf("expected empty queue, got %d", depth)
+	}
+}
+
+func TestClientQuarantinesByStatusPolicy(t *testing.T) {
+	queueDir := t.TempDir()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	client, err := NewClientWithComponent(queueDir, 100*time.Millisecond, "test",
+		WithStatusPolicy(spool.StatusPolicy{http.StatusBadRequest: spool.StatusQuarantine}))
+	if err != nil {
+		t.Fatalf("NewClientWithComponent: %v", err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+
+	req, err := http.NewRequest(http.MethodPost, server.URL, bytes.NewBufferString("bad"))
+	if err != nil {
+		t.Fatalf("NewRequest: %v", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("client.Do: %v", err)
+	}
+	_ = resp.Body.Close()
+
+	deadline := time.After(3 * time.Second)
+	for client.QueueDropped() == 0 {
+		select {
+		case <-deadline:
+			t.Fatal("expected the rejected request to be quarantined")
+		default:
+			time.Sleep(20 * time.Millisecond)
+		}
+	}
+	entries, err := os.ReadDir(filepath.Join(queueDir, spool.QuarantineDir))
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("expected one quarantined request, got %d (%v)", len(entries), err)
+	}
+}
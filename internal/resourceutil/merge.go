@@ -0,0 +1,104 @@
+// Package resourceutil merges OpenTelemetry resources whose schema URLs may disagree.
+package resourceutil
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// Strategies for resources whose schema URLs differ, typically because a detector was built
+// against a different semantic conventions version than the SDK.
+const (
+	// ConflictError fails the merge, as resource.Merge does.
+	ConflictError = "error"
+	// ConflictPreferLatest keeps the schema URL with the highest semconv version.
+	ConflictPreferLatest = "prefer-latest"
+	// ConflictStrip keeps the attributes and drops the schema URL.
+	ConflictStrip = "strip"
+)
+
+// Merge combines a and b like resource.Merge, with b's attributes winning, and resolves a schema
+// URL conflict with strategy. An empty strategy means ConflictError.
+func Merge(strategy string, a, b *resource.Resource) (*resource.Resource, error) {
+	merged, err := resource.Merge(a, b)
+	if err == nil || !errors.Is(err, resource.ErrSchemaURLConflict) {
+		return merged, err
+	}
+	switch strategy {
+	case ConflictPreferLatest:
+		return resource.NewWithAttributes(Latest(a.SchemaURL(), b.SchemaURL()), merged.Attributes()...), nil
+	case ConflictStrip:
+		return merged, nil
+	default:
+		return merged, err
+	}
+}
+
+// Latest returns whichever schema URL names the higher version, such as
+// https://opentelemetry.io/schemas/1.28.0 over .../1.26.0. When a version cannot be read, b wins.
+func Latest(a, b string) string {
+	va, okA := schemaVersion(a)
+	vb, okB := schemaVersion(b)
+	if !okA || !okB {
+		if b != "" {
+			return b
+		}
+		return a
+	}
+	for i := range max(len(va), len(vb)) {
+		var x, y int
+		if i < len(va) {
+			x = va[i]
+		}
+		if i < len(vb) {
+			y = vb[i]
+		}
+		if x != y {
+			if x > y {
+				return a
+			}
+			return b
+		}
+	}
+	return b
+}
+
+func schemaVersion(url string) ([]int, bool) {
+	idx := strings.LastIndex(url, "/")
+	if idx < 0 || idx == len(url)-1 {
+		return nil, false
+	}
+	parts := strings.Split(url[idx+1:], ".")
+	version := make([]int, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, false
+		}
+		version[i] = n
+	}
+	return version, true
+}
+
+// Merger applies one strategy across a sequence of merges. Once ConflictStrip has dropped a schema
+// URL, later merges stay schemaless too, so a schema URL merged in afterwards cannot claim
+// attributes recorded under another version.
+type Merger struct {
+	Strategy string
+	stripped bool
+}
+
+// Merge combines a and b like the package-level Merge.
+func (m *Merger) Merge(a, b *resource.Resource) (*resource.Resource, error) {
+	if m.stripped {
+		return resource.NewSchemaless(append(a.Attributes(), b.Attributes()...)...), nil
+	}
+	merged, err := Merge(m.Strategy, a, b)
+	if err == nil && m.Strategy == ConflictStrip && merged.SchemaURL() == "" && (a.SchemaURL() != "" || b.SchemaURL() != "") {
+		m.stripped = true
+	}
+	return merged, err
+}
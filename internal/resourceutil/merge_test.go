@@ -0,0 +1,84 @@
+package resourceutil
+
+import (
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+func TestLatest(t *testing.T) {
+	cases := []struct{ a, b, want string }{
+		{"https://opentelemetry.io/schemas/1.26.0", "https://opentelemetry.io/schemas/1.28.0", "https://opentelemetry.io/schemas/1.28.0"},
+		{"https://opentelemetry.io/schemas/1.28.0", "https://opentelemetry.io/schemas/1.9.0", "https://opentelemetry.io/schemas/1.28.0"},
+		{"https://opentelemetry.io/schemas/1.28", "https://opentelemetry.io/schemas/1.28.0", "https://opentelemetry.io/schemas/1.28.0"},
+		{"https://example.com/custom", "https://opentelemetry.io/schemas/1.4.0", "https://opentelemetry.io/schemas/1.4.0"},
+		{"https://opentelemetry.io/schemas/1.4.0", "", "https://opentelemetry.io/schemas/1.4.0"},
+	}
+	for _, tc := range cases {
+		if got := Latest(tc.a, tc.b); got != tc.want {
+			t.Errorf("Latest(%q, %q) = %q, want %q", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestMergeStrategies(t *testing.T) {
+	older := resource.NewWithAttributes("https://opentelemetry.io/schemas/1.20.0", attribute.String("a", "old"), attribute.String("only.old", "1"))
+	newer := resource.NewWithAttributes("https://opentelemetry.io/schemas/1.26.0", attribute.String("a", "new"))
+
+	if _, err := Merge("", newer, older); !errors.Is(err, resource.ErrSchemaURLConflict) {
+		t.Fatalf("expected conflict error by default, got %v", err)
+	}
+	if _, err := Merge(ConflictError, newer, older); !errors.Is(err, resource.ErrSchemaURLConflict) {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+
+	merged, err := Merge(ConflictPreferLatest, newer, older)
+	if err != nil {
+		t.Fatalf("prefer-latest: %v", err)
+	}
+	if merged.SchemaURL() != newer.SchemaURL() {
+		t.Fatalf("expected newer schema URL, got %q", merged.SchemaURL())
+	}
+	if v, _ := merged.Set().Value("a"); v.AsString() != "old" {
+		t.Fatalf("expected the second resource's attribute to win, got %q", v.AsString())
+	}
+	if _, ok := merged.Set().Value("only.old"); !ok {
+		t.Fatal("expected attributes from both resources")
+	}
+
+	merged, err = Merge(ConflictStrip, newer, older)
+	if err != nil {
+		t.Fatalf("strip: %v", err)
+	}
+	if merged.SchemaURL() != "" || merged.Len() != 2 {
+		t.Fatalf("expected schemaless merge with both attributes, got %q %d", merged.SchemaURL(), merged.Len())
+	}
+
+	same, err := Merge(ConflictError, older, resource.NewSchemaless(attribute.String("b", "1")))
+	if err != nil || same.SchemaURL() != older.SchemaURL() {
+		t.Fatalf("expected schemaless resource to merge cleanly, got %q %v", same.SchemaURL(), err)
+	}
+}
+
+func TestMergerStripStaysSchemaless(t *testing.T) {
+	merger := &Merger{Strategy: ConflictStrip}
+	res, err := merger.Merge(
+		resource.NewWithAttributes("https://opentelemetry.io/schemas/1.20.0", attribute.String("a", "1")),
+		resource.NewWithAttributes("https://opentelemetry.io/schemas/1.26.0", attribute.String("b", "1")),
+	)
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	res, err = merger.Merge(res, resource.NewWithAttributes("https://opentelemetry.io/schemas/1.28.0", attribute.String("a", "2")))
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if res.SchemaURL() != "" {
+		t.Fatalf("expected schema URL to stay stripped, got %q", res.SchemaURL())
+	}
+	if v, _ := res.Set().Value("a"); v.AsString() != "2" || res.Len() != 2 {
+		t.Fatalf("expected later attributes to win, got %v", res.Attributes())
+	}
+}
@@ -0,0 +1,91 @@
+package spool
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mfahmialkautsar/goo11y/clock"
+)
+
+// manualClock hands every timer to the test, which decides when it fires.
+type manualClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers chan *manualTimer
+}
+
+func (c *manualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *manualClock) advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+func (c *manualClock) NewTimer(d time.Duration) clock.Timer {
+	timer := &manualTimer{d: d, ch: make(chan time.Time, 1)}
+	c.timers <- timer
+	return timer
+}
+
+func (c *manualClock) NewTicker(time.Duration) clock.Ticker {
+	panic("spool: unexpected ticker")
+}
+
+type manualTimer struct {
+	d  time.Duration
+	ch chan time.Time
+}
+
+func (t *manualTimer) C() <-chan time.Time        { return t.ch }
+func (t *manualTimer) Stop() bool                 { return true }
+func (t *manualTimer) Reset(d time.Duration) bool { t.d = d; return true }
+func (t *manualTimer) fire(now time.Time)         { t.ch <- now }
+
+func TestQueueRetryFollowsInjectedClock(t *testing.T) {
+	clk := &manualClock{now: time.Unix(1_700_000_000, 0), timers: make(chan *manualTimer, 8)}
+	queue, err := NewWithErrorLogger(t.TempDir(), nil, WithClock(clk))
+	if err != nil {
+		t.Fatalf("NewWithErrorLogger: %v", err)
+	}
+
+	var attempts atomic.Int32
+	done := make(chan struct{})
+	queue.Start(t.Context(), func(context.Context, []byte) error {
+		if attempts.Add(1) == 1 {
+			return errors.New("unavailable")
+		}
+		close(done)
+		return nil
+	})
+	if _, err := queue.Enqueue([]byte("payload")); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	deadline := time.After(2 * time.Second)
+	for {
+		select {
+		case timer := <-clk.timers:
+			if timer.d != defaultRetryBaseDelay {
+				t.Fatalf("expected retry after %v, waited %v", defaultRetryBaseDelay, timer.d)
+			}
+			if got := attempts.Load(); got != 1 {
+				t.Fatalf("expected retry to wait for the clock, got %d attempts", got)
+			}
+			clk.advance(timer.d)
+			timer.fire(clk.Now())
+		case <-done:
+			return
+		case <-deadline:
+			t.Fatal("timed out waiting for retry")
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package spool_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mfahmialkautsar/goo11y/goo11ytest"
+	"github.com/mfahmialkautsar/goo11y/internal/spool"
+)
+
+func TestQueueExpiresWaitingPayloadOnFakeClock(t *testing.T) {
+	clk := goo11ytest.NewFakeClock(time.Unix(1_700_000_000, 0))
+	queue, err := spool.NewWithErrorLogger(t.TempDir(), nil, spool.WithClock(clk), spool.WithMaxAge(time.Minute))
+	if err != nil {
+		t.Fatalf("NewWithErrorLogger: %v", err)
+	}
+
+	var attempts atomic.Int32
+	queue.Start(t.Context(), func(context.Context, []byte) error {
+		attempts.Add(1)
+		return errors.New("unavailable")
+	})
+	if _, err := queue.Enqueue([]byte("payload")); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for clk.Waiters() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the retry timer")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	clk.Advance(2 * time.Minute)
+
+	for queue.Len() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the expired payload to be dropped")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if got := queue.Dropped()[spool.DropExpired]; got != 1 {
+		t.Fatalf("expected 1 expired drop, got %d", got)
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Fatalf("expected the expired payload not to be retried, got %d attempts", got)
+	}
+}
+
+func TestHTTPHandlerReadsRetryAfterDateOnQueueClock(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", now.Add(90*time.Second).Format(http.TimeFormat))
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(srv.Close)
+	queue, err := spool.NewWithErrorLogger(t.TempDir(), nil, spool.WithClock(goo11ytest.NewFakeClock(now)))
+	if err != nil {
+		t.Fatalf("NewWithErrorLogger: %v", err)
+	}
+	payload, err := (&spool.HTTPRequest{Method: http.MethodPost, URL: srv.URL}).Marshal()
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	err = spool.HTTPHandlerWithPolicy(srv.Client(), nil, queue.Clock())(context.Background(), payload)
+	var statusErr *spool.StatusError
+	if !errors.As(err, &statusErr) || statusErr.RetryAfter != 90*time.Second {
+		t.Fatalf("expected a 90s Retry-After read on the queue clock, got %v", err)
+	}
+}
@@ -9,6 +9,8 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+
+	"github.com/mfahmialkautsar/goo11y/clock"
 )
 
 // HTTPRequest represents a serialized HTTP request for queueing.
@@ -35,8 +37,17 @@ func (h *HTTPRequest) Unmarshal(data []byte) error {
 	return json.Unmarshal(data, h)
 }
 
-// HTTPHandler returns a Handler that processes queued HTTP requests.
+// HTTPHandler returns a Handler that processes queued HTTP requests, classifying failed responses
+// with DefaultStatusAction.
 func HTTPHandler(client *http.Client) Handler {
+	return HTTPHandlerWithPolicy(client, nil, nil)
+}
+
+// HTTPHandlerWithPolicy is HTTPHandler with policy overriding the action for individual statuses.
+// Retried statuses return a *StatusError; dropped or quarantined ones return a *RejectedError.
+// Retry-After dates are read against clk, normally the queue's Clock; nil means the real clock.
+func HTTPHandlerWithPolicy(client *http.Client, policy StatusPolicy, clk clock.Clock) Handler {
+	clk = clock.OrReal(clk)
 	return func(ctx context.Context, payload []byte) (err error) {
 		req, err := unmarshalAndValidateRequest(payload)
 		if err != nil {
@@ -62,12 +73,34 @@ func HTTPHandler(client *http.Client) Handler {
 			return copyErr
 		}
 		if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
-			return fmt.Errorf("spool: remote status %d", resp.StatusCode)
+			return classifyStatus(resp, policy, clk.Now())
 		}
 		return nil
 	}
 }
 
+// RetargetHTTPRequest returns the queued request in payload addressed to target instead. Headers named in
+// strip are removed before headers is applied, so credentials meant for the original endpoint are not forwarded.
+func RetargetHTTPRequest(payload []byte, target string, headers map[string]string, strip []string) ([]byte, error) {
+	var req HTTPRequest
+	if err := req.Unmarshal(payload); err != nil {
+		return nil, ErrCorrupt
+	}
+	req.URL = target
+	header := http.Header(req.Header)
+	if header == nil {
+		header = make(http.Header, len(headers))
+	}
+	for _, key := range strip {
+		header.Del(key)
+	}
+	for key, value := range headers {
+		header.Set(key, value)
+	}
+	req.Header = header
+	return req.Marshal()
+}
+
 func unmarshalAndValidateRequest(payload []byte) (*HTTPRequest, error) {
 	var req HTTPRequest
 	if err := req.Unmarshal(payload); err != nil {
@@ -121,3 +121,39 @@ func TestHTTPHandlerErrors(t *testing.T) {
 		t.Fatal("expected non-2xx error")
 	}
 }
+
+func TestRetargetHTTPRequest(t *testing.T) {
+	original := &HTTPRequest{
+		Method: http.MethodPost,
+		URL:    "http://primary/v1/logs",
+		Header: map[string][]string{"Authorization": {"Bearer primary"}, "Content-Type": {"application/x-protobuf"}},
+		Body:   []byte("payload"),
+	}
+	payload, err := original.Marshal()
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	retargeted, err := RetargetHTTPRequest(payload, "http://secondary/v1/logs", map[string]string{"X-Scope-OrgID": "tenant"}, []string{"authorization"})
+	if err != nil {
+		t.Fatalf("RetargetHTTPRequest: %v", err)
+	}
+	var restored HTTPRequest
+	if err := restored.Unmarshal(retargeted); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	header := http.Header(restored.Header)
+	if restored.URL != "http://secondary/v1/logs" || string(restored.Body) != "payload" {
+		t.Fatalf("unexpected request: %#v", restored)
+	}
+	if header.Get("Authorization") != "" {
+		t.Fatalf("expected primary credentials to be stripped, got %q", header.Get("Authorization"))
+	}
+	if header.Get("X-Scope-OrgID") != "tenant" || header.Get("Content-Type") != "application/x-protobuf" {
+		t.Fatalf("unexpected headers: %#v", header)
+	}
+
+	if _, err := RetargetHTTPRequest([]byte("not json"), "http://secondary", nil, nil); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("expected ErrCorrupt, got %v", err)
+	}
+}
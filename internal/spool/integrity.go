@@ -0,0 +1,49 @@
+package spool
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+)
+
+// payloadMagic prefixes payload files written with a checksum header. Files without it predate the
+// header and are read as raw payloads.
+var payloadMagic = []byte("GSP1")
+
+const payloadHeaderSize = 8
+
+var checksumTable = crc32.MakeTable(crc32.Castagnoli)
+
+// encodePayload prepends the magic and a CRC-32C of payload.
+func encodePayload(payload []byte) []byte {
+	data := make([]byte, payloadHeaderSize+len(payload))
+	copy(data, payloadMagic)
+	binary.BigEndian.PutUint32(data[len(payloadMagic):payloadHeaderSize], crc32.Checksum(payload, checksumTable))
+	copy(data[payloadHeaderSize:], payload)
+	return data
+}
+
+// decodePayload verifies and strips the checksum header, returning ErrCorrupt on a mismatch.
+func decodePayload(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, payloadMagic) {
+		return data, nil
+	}
+	if len(data) < payloadHeaderSize {
+		return nil, fmt.Errorf("%w: truncated header", ErrCorrupt)
+	}
+	payload := data[payloadHeaderSize:]
+	want := binary.BigEndian.Uint32(data[len(payloadMagic):payloadHeaderSize])
+	if crc32.Checksum(payload, checksumTable) != want {
+		return nil, fmt.Errorf("%w: checksum mismatch", ErrCorrupt)
+	}
+	return payload, nil
+}
+
+// quarantine sets the file stored under token aside, keeping at most maxFiles quarantined files.
+func (q *Queue) quarantine(token string) error {
+	if !validToken(token) {
+		return fmt.Errorf("spool: invalid token path")
+	}
+	return q.store.quarantine(token, q.maxFiles)
+}
@@ -0,0 +1,145 @@
+package spool
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPayloadChecksumRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	encoded := encodePayload([]byte("payload"))
+	decoded, err := decodePayload(encoded)
+	if err != nil || string(decoded) != "payload" {
+		t.Fatalf("decodePayload = %q, %v", decoded, err)
+	}
+
+	encoded[len(encoded)-1] ^= 0xff
+	if _, err := decodePayload(encoded); err == nil {
+		t.Fatal("expected checksum mismatch")
+	}
+	if _, err := decodePayload(payloadMagic); err == nil {
+		t.Fatal("expected truncated header error")
+	}
+	legacy, err := decodePayload([]byte("raw"))
+	if err != nil || string(legacy) != "raw" {
+		t.Fatalf("expected headerless payload to be read as is, got %q, %v", legacy, err)
+	}
+}
+
+func TestEnqueueLeavesNoTemporaryFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	leftover := filepath.Join(dir, ".spool-write-123")
+	if err := os.WriteFile(leftover, []byte("partial"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	queue, err := New(dir)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if _, err := os.Stat(leftover); !os.IsNotExist(err) {
+		t.Fatalf("expected interrupted write to be removed, got %v", err)
+	}
+
+	token, err := queue.Enqueue([]byte("payload"))
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != token {
+		t.Fatalf("expected only the payload file, got %v", entries)
+	}
+	payload, err := queue.readPayload(token)
+	if err != nil || string(payload) != "payload" {
+		t.Fatalf("readPayload = %q, %v", payload, err)
+	}
+}
+
+func TestQueueQuarantinesCorruptFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	recorder := &dropRecorder{}
+	queue, err := NewWithErrorLogger(dir, nil, WithDropFunc(recorder.record))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	token, err := queue.Enqueue([]byte("payload"))
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	path := filepath.Join(dir, token)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	data[len(data)-1] ^= 0xff
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	queue.Start(ctx, func(context.Context, []byte) error {
+		t.Error("corrupt payload should not be handled")
+		return nil
+	})
+
+	quarantined := filepath.Join(dir, QuarantineDir, token)
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, err := os.Stat(quarantined); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("corrupt payload was not quarantined")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+
+	if queue.Len() != 0 {
+		t.Fatalf("expected corrupt payload to leave the queue, got %d", queue.Len())
+	}
+	drops := recorder.snapshot()
+	if len(drops) != 1 || drops[0].reason != DropCorrupt || drops[0].payload != string(data) {
+		t.Fatalf("unexpected drops: %+v", drops)
+	}
+}
+
+func TestQuarantineKeepsNewestFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	queue, err := New(dir)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	queue.maxFiles = 2
+	for _, name := range []string{"a.spool", "b.spool", "c.spool"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("x"), 0o600); err != nil {
+			t.Fatalf("WriteFile: %v", err)
+		}
+		if err := queue.quarantine(name); err != nil {
+			t.Fatalf("quarantine: %v", err)
+		}
+	}
+	entries, err := os.ReadDir(filepath.Join(dir, QuarantineDir))
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Name() != "b.spool" {
+		t.Fatalf("expected the two newest files, got %v", entries)
+	}
+	if err := queue.quarantine("../escape"); err == nil {
+		t.Fatal("expected path escape to be rejected")
+	}
+}
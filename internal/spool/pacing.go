@@ -0,0 +1,92 @@
+package spool
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/mfahmialkautsar/goo11y/clock"
+)
+
+// pacingJitter is the largest share of a request's pacing interval added at random to its wait, so
+// instances draining at the same time do not fall into step against a shared collector limit.
+const pacingJitter = 0.2
+
+// Pacer spaces requests out so they stay under a request rate and a byte rate. Requests are not
+// bunched into bursts: each one starts no earlier than the previous one's share of the rates
+// allows, plus some jitter. A nil Pacer never waits.
+type Pacer struct {
+	requestsPerSecond float64
+	bytesPerSecond    float64
+	clock             clock.Clock
+	jitter            func() float64
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// NewPacer returns a Pacer allowing requestsPerSecond requests and bytesPerSecond bytes per second.
+// Zero or less leaves that limit off, and NewPacer returns nil when both are off. A nil clk uses
+// the real clock.
+func NewPacer(requestsPerSecond float64, bytesPerSecond int, clk clock.Clock) *Pacer {
+	if requestsPerSecond <= 0 && bytesPerSecond <= 0 {
+		return nil
+	}
+	return &Pacer{
+		requestsPerSecond: max(requestsPerSecond, 0),
+		bytesPerSecond:    float64(max(bytesPerSecond, 0)),
+		clock:             clock.OrReal(clk),
+		jitter:            rand.Float64,
+	}
+}
+
+// Wait blocks until a request of size bytes may be sent. It returns false when ctx ends first.
+func (p *Pacer) Wait(ctx context.Context, size int) bool {
+	if p == nil {
+		return ctx.Err() == nil
+	}
+	delay := p.reserve(size)
+	if delay <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := p.clock.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C():
+		return true
+	}
+}
+
+// reserve books the next slot for a request of size bytes and returns how long to wait for it.
+func (p *Pacer) reserve(size int) time.Duration {
+	cost := p.cost(size)
+	p.mu.Lock()
+	now := p.clock.Now()
+	start := p.next
+	if start.Before(now) {
+		start = now
+	}
+	p.next = start.Add(cost)
+	p.mu.Unlock()
+
+	delay := start.Sub(now)
+	if delay > 0 {
+		delay += time.Duration(p.jitter() * pacingJitter * float64(cost))
+	}
+	return delay
+}
+
+// cost is the share of the slower limit a request of size bytes uses up.
+func (p *Pacer) cost(size int) time.Duration {
+	var seconds float64
+	if p.requestsPerSecond > 0 {
+		seconds = 1 / p.requestsPerSecond
+	}
+	if p.bytesPerSecond > 0 {
+		seconds = max(seconds, float64(size)/p.bytesPerSecond)
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
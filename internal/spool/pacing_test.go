@@ -0,0 +1,49 @@
+package spool
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestPacerSpacesRequestsByTheSlowerLimit(t *testing.T) {
+	clk := &manualClock{now: time.Unix(1_700_000_000, 0)}
+	pacer := NewPacer(10, 1000, clk)
+	pacer.jitter = func() float64 { return 0 }
+
+	want := []time.Duration{0, 100 * time.Millisecond, 200 * time.Millisecond, 700 * time.Millisecond}
+	sizes := []int{10, 10, 500, 10}
+	for idx, size := range sizes {
+		if got := pacer.reserve(size); got != want[idx] {
+			t.Fatalf("request %d waits %v, want %v", idx, got, want[idx])
+		}
+	}
+
+	clk.advance(time.Minute)
+	if got := pacer.reserve(10); got != 0 {
+		t.Fatalf("expected an idle pacer not to wait, got %v", got)
+	}
+}
+
+func TestPacerAddsJitterOnlyWhileHolding(t *testing.T) {
+	clk := &manualClock{now: time.Unix(1_700_000_000, 0)}
+	pacer := NewPacer(1, 0, clk)
+	pacer.jitter = func() float64 { return 1 }
+
+	if got := pacer.reserve(0); got != 0 {
+		t.Fatalf("expected the first request to go at once, got %v", got)
+	}
+	if got, want := pacer.reserve(0), time.Second+time.Duration(pacingJitter*float64(time.Second)); got != want {
+		t.Fatalf("expected a jittered wait of %v, got %v", want, got)
+	}
+}
+
+func TestNilPacerNeverWaits(t *testing.T) {
+	pacer := NewPacer(0, 0, nil)
+	if pacer != nil {
+		t.Fatal("expected no pacer without limits")
+	}
+	if !pacer.Wait(context.Background(), 1<<20) {
+		t.Fatal("expected a nil pacer to let requests through")
+	}
+}
@@ -4,15 +4,16 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io"
 	"io/fs"
-	"os"
 	"path/filepath"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
+
+	"github.com/mfahmialkautsar/goo11y/clock"
 )
 
 var (
@@ -38,6 +39,10 @@ const (
 	defaultQueueMaxFiles  = 1000
 	defaultRetryBaseDelay = time.Second
 	defaultRetryMaxDelay  = time.Minute
+
+	// QuarantineDir is the subdirectory that keeps corrupt payloads for inspection.
+	QuarantineDir    = "quarantine"
+	writeTempPattern = ".spool-write-*"
 )
 
 // Handler represents a function that processes a dequeued payload.
@@ -56,9 +61,66 @@ func (f ErrorLoggerFunc) Log(err error) {
 	f(err)
 }
 
+// DropReason explains why a payload left the queue without being delivered.
+type DropReason string
+
+// Reasons reported to a DropFunc.
+const (
+	DropCorrupt          DropReason = "corrupt"
+	DropExpired          DropReason = "expired"
+	DropRetriesExhausted DropReason = "retries_exhausted"
+	DropOverflow         DropReason = "overflow"
+	DropRejected         DropReason = "rejected"
+)
+
+// DropFunc receives every payload the queue discards, before its file is removed.
+// The payload is nil if the file could no longer be read.
+type DropFunc func(token string, payload []byte, reason DropReason)
+
+// Option configures a Queue.
+type Option func(*Queue)
+
+// WithMaxAge drops payloads spooled longer than maxAge, regardless of how many attempts they had.
+// Zero or less keeps payloads until the retry limit.
+func WithMaxAge(maxAge time.Duration) Option {
+	return func(q *Queue) {
+		if maxAge > 0 {
+			q.maxAge = maxAge
+		}
+	}
+}
+
+// WithClock schedules retries and stamps payloads with c instead of the real clock.
+func WithClock(c clock.Clock) Option {
+	return func(q *Queue) {
+		if c != nil {
+			q.clock = c
+			q.now = c.Now
+		}
+	}
+}
+
+// WithDropFunc calls fn for every payload the queue discards.
+func WithDropFunc(fn DropFunc) Option {
+	return func(q *Queue) {
+		q.onDrop = fn
+	}
+}
+
+// WithPacing spaces deliveries out to at most requestsPerSecond payloads and bytesPerSecond bytes
+// per second, with jitter, so draining a backlog does not trip the remote's rate limits and get
+// the payloads throttled back into the queue. Zero or less leaves that limit off.
+func WithPacing(requestsPerSecond float64, bytesPerSecond int) Option {
+	return func(q *Queue) {
+		q.pacingRequests = requestsPerSecond
+		q.pacingBytes = bytesPerSecond
+	}
+}
+
 // Queue provides a disk-backed, reliable queue for delayed processing.
 type Queue struct {
 	dir         string
+	store       store
 	notify      chan struct{}
 	counter     uint64
 	errorLogger ErrorLogger
@@ -68,6 +130,20 @@ type Queue struct {
 	retryBase time.Duration
 	retryMax  time.Duration
 	now       func() time.Time
+	clock     clock.Clock
+	maxAge    time.Duration
+	onDrop    DropFunc
+
+	pacingRequests float64
+	pacingBytes    int
+	pacer          *Pacer
+
+	dropMu  sync.Mutex
+	dropped map[DropReason]uint64
+
+	// pausedUntil holds every payload back after the remote throttled the queue; only the worker
+	// goroutine touches it.
+	pausedUntil time.Time
 }
 
 type fileToken struct {
@@ -84,7 +160,9 @@ func New(dir string) (*Queue, error) {
 }
 
 // NewWithErrorLogger creates a new Queue with a custom ErrorLogger.
-func NewWithErrorLogger(dir string, logger ErrorLogger) (*Queue, error) {
+// When dir cannot be created or written, for example on a read-only filesystem, the queue logs a
+// warning and keeps payloads in memory instead of failing; Persistent reports which mode is in use.
+func NewWithErrorLogger(dir string, logger ErrorLogger, opts ...Option) (*Queue, error) {
 	if dir == "" {
 		return nil, fmt.Errorf("spool: queue dir is required")
 	}
@@ -96,40 +174,35 @@ func NewWithErrorLogger(dir string, logger ErrorLogger) (*Queue, error) {
 		}
 	}
 
-	if err := os.MkdirAll(cleaned, 0o750); err != nil {
-		return nil, fmt.Errorf("spool: create dir: %w", err)
-	}
-
-	probe, err := os.CreateTemp(cleaned, ".spool-probe-*")
-	if err != nil {
-		return nil, fmt.Errorf("spool: probe write: %w", err)
-	}
-	probeName := filepath.Base(probe.Name())
-	root, err := os.OpenRoot(cleaned)
+	var backing store
+	disk, err := openDiskStore(cleaned)
 	if err != nil {
-		return nil, fmt.Errorf("spool: open root: %w", err)
-	}
-	defer func() {
-		_ = root.Close()
-	}()
-
-	if cerr := probe.Close(); cerr != nil {
-		_ = root.Remove(probeName)
-		return nil, fmt.Errorf("spool: probe close: %w", cerr)
-	}
-	if err := root.Remove(probeName); err != nil {
-		return nil, fmt.Errorf("spool: probe cleanup: %w", err)
+		if logger != nil {
+			logger.Log(fmt.Errorf("spool: %s unusable, buffering in memory: %w", cleaned, err))
+		}
+		backing = newMemoryStore()
+	} else {
+		backing = disk
 	}
 
-	return &Queue{
+	q := &Queue{
 		dir:         cleaned,
+		store:       backing,
 		notify:      make(chan struct{}, notifierBuffer),
 		errorLogger: logger,
 		maxFiles:    defaultQueueMaxFiles,
 		retryBase:   defaultRetryBaseDelay,
 		retryMax:    defaultRetryMaxDelay,
 		now:         defaultNow,
-	}, nil
+		clock:       clock.Real(),
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(q)
+		}
+	}
+	q.pacer = NewPacer(q.pacingRequests, q.pacingBytes, q.clock)
+	return q, nil
 }
 
 // Enqueue adds a payload to the queue.
@@ -150,8 +223,7 @@ func (q *Queue) Enqueue(payload []byte) (string, error) {
 		attempts:  0,
 	}
 	name := formatToken(token)
-	path := filepath.Join(q.dir, name)
-	if err := os.WriteFile(path, payload, 0o600); err != nil {
+	if err := q.store.write(name, encodePayload(payload)); err != nil {
 		return "", fmt.Errorf("spool: write payload: %w", err)
 	}
 	q.signal()
@@ -163,19 +235,52 @@ func (q *Queue) Complete(token string) error {
 	if token == "" {
 		return nil
 	}
-	if strings.Contains(token, string(os.PathSeparator)) {
+	if !validToken(token) {
 		return fmt.Errorf("spool: invalid token path")
 	}
-	path := filepath.Join(q.dir, token)
-	if !strings.HasPrefix(path, q.dir+string(os.PathSeparator)) {
-		return fmt.Errorf("spool: invalid token path")
-	}
-	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+	if err := q.store.remove(token); err != nil {
 		return fmt.Errorf("spool: remove payload: %w", err)
 	}
 	return nil
 }
 
+// Persistent reports whether payloads are stored on disk rather than in memory.
+func (q *Queue) Persistent() bool {
+	_, ok := q.store.(*diskStore)
+	return ok
+}
+
+// validToken reports whether token names a file directly inside the queue directory. Both slash
+// and backslash are rejected so tokens stay portable between platforms.
+func validToken(token string) bool {
+	return token != "" && !strings.ContainsAny(token, `/\`) && filepath.IsLocal(token)
+}
+
+// Clock returns the clock the queue schedules retries and stamps payloads with.
+func (q *Queue) Clock() clock.Clock {
+	return q.clock
+}
+
+// Len returns the number of payloads currently waiting in the queue.
+func (q *Queue) Len() int {
+	tokens, err := q.listTokens()
+	if err != nil {
+		return 0
+	}
+	return len(tokens)
+}
+
+// Dropped returns the number of payloads discarded so far, by reason.
+func (q *Queue) Dropped() map[DropReason]uint64 {
+	q.dropMu.Lock()
+	defer q.dropMu.Unlock()
+	out := make(map[DropReason]uint64, len(q.dropped))
+	for reason, n := range q.dropped {
+		out[reason] = n
+	}
+	return out
+}
+
 // Start begins processing the queue in the background using the given handler.
 func (q *Queue) Start(ctx context.Context, handler Handler) {
 	go q.loop(ctx, handler)
@@ -203,26 +308,45 @@ func (q *Queue) loop(ctx context.Context, handler Handler) {
 }
 
 func (q *Queue) processNext(ctx context.Context, handler Handler, backoff *time.Duration) bool {
+	if delay := q.pausedUntil.Sub(q.now()); delay > 0 {
+		return q.waitWithBackoff(ctx, delay)
+	}
+
 	token, count, err := q.oldest()
 	if err != nil {
 		return q.handleOldestError(ctx, err, backoff)
 	}
 
-	if delay := time.Until(token.retryAt); delay > 0 {
-		if !q.waitWithBackoff(ctx, delay) {
-			return false
+	if q.expired(token) {
+		if err := q.drop(token.name, nil, DropExpired); err != nil {
+			q.logError(err)
 		}
 		*backoff = initialBackoff
 		return true
 	}
 
+	if delay := token.retryAt.Sub(q.now()); delay > 0 {
+		due, ok := q.waitForRetry(ctx, delay)
+		if !ok {
+			return false
+		}
+		// Once the retry timer fires the payload is due, even if the clock's Now lags behind it.
+		if !due || q.expired(token) {
+			*backoff = initialBackoff
+			return true
+		}
+	}
+
 	payload, err := q.readPayload(token.name)
 	if err != nil {
 		return q.handleReadError(ctx, token.name, err, backoff)
 	}
 
+	if !q.pacer.Wait(ctx, len(payload)) {
+		return false
+	}
 	if err := handler(ctx, payload); err != nil {
-		return q.handleHandlerError(ctx, &token, count, err, backoff)
+		return q.handleHandlerError(ctx, &token, payload, count, err, backoff)
 	}
 
 	if err := q.Complete(token.name); err != nil {
@@ -254,6 +378,13 @@ func (q *Queue) handleReadError(ctx context.Context, name string, err error, bac
 		*backoff = initialBackoff
 		return true
 	}
+	if errors.Is(err, ErrCorrupt) {
+		if err := q.drop(name, nil, DropCorrupt); err != nil {
+			q.logError(err)
+		}
+		*backoff = initialBackoff
+		return true
+	}
 	if !q.waitWithBackoff(ctx, *backoff) {
 		return false
 	}
@@ -261,17 +392,29 @@ func (q *Queue) handleReadError(ctx context.Context, name string, err error, bac
 	return true
 }
 
-func (q *Queue) handleHandlerError(ctx context.Context, token *fileToken, count int, err error, backoff *time.Duration) bool {
+func (q *Queue) handleHandlerError(ctx context.Context, token *fileToken, payload []byte, count int, err error, backoff *time.Duration) bool {
 	if errors.Is(err, ErrCorrupt) {
 		q.logError(fmt.Errorf("spool: corrupt payload in %s: %w", token.name, err))
-		_ = q.Complete(token.name)
+		_ = q.drop(token.name, payload, DropCorrupt)
+		*backoff = initialBackoff
+		return true
+	}
+	var rejected *RejectedError
+	if errors.As(err, &rejected) {
+		q.logError(fmt.Errorf("spool: dropping %s: %w", token.name, err))
+		_ = q.dropTo(token.name, payload, DropRejected, rejected.Quarantine)
 		*backoff = initialBackoff
 		return true
 	}
 	q.logError(fmt.Errorf("spool: handler failed for %s: %w", token.name, err))
-	if q.shouldDrop(*token, count) {
-		_ = q.Complete(token.name)
-	} else if err := q.scheduleRetry(*token); err != nil {
+	throttle := retryAfter(err)
+	if throttle > 0 {
+		// The remote is overloaded; sending the payloads behind this one would only add to it.
+		q.pausedUntil = q.now().Add(throttle)
+	}
+	if reason, drop := q.dropReason(*token, count); drop {
+		_ = q.drop(token.name, payload, reason)
+	} else if err := q.scheduleRetry(*token, throttle); err != nil {
 		q.logError(fmt.Errorf("spool: schedule retry for %s: %w", token.name, err))
 		if !q.waitWithBackoff(ctx, *backoff) {
 			return false
@@ -299,18 +442,33 @@ func (q *Queue) wait(ctx context.Context) bool {
 }
 
 func (q *Queue) waitWithBackoff(ctx context.Context, d time.Duration) bool {
-	timer := time.NewTimer(d)
+	timer := q.clock.NewTimer(d)
 	defer timer.Stop()
 	select {
 	case <-ctx.Done():
 		return false
-	case <-timer.C:
+	case <-timer.C():
 		return true
 	case <-q.notify:
 		return true
 	}
 }
 
+// waitForRetry waits d for a payload's retry. due reports whether the wait ran its full course
+// rather than being cut short by Notify.
+func (q *Queue) waitForRetry(ctx context.Context, d time.Duration) (due, ok bool) {
+	timer := q.clock.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false, false
+	case <-timer.C():
+		return true, true
+	case <-q.notify:
+		return false, true
+	}
+}
+
 func nextBackoff(current time.Duration) time.Duration {
 	next := current * 2
 	if next > maxBackoff {
@@ -335,16 +493,12 @@ func (q *Queue) oldest() (fileToken, int, error) {
 }
 
 func (q *Queue) listTokens() ([]fileToken, error) {
-	entries, err := os.ReadDir(q.dir)
+	names, err := q.store.names()
 	if err != nil {
-		return nil, fmt.Errorf("spool: read dir: %w", err)
+		return nil, err
 	}
-	tokens := make([]fileToken, 0, len(entries))
-	for _, entry := range entries {
-		if entry.IsDir() {
-			continue
-		}
-		name := entry.Name()
+	tokens := make([]fileToken, 0, len(names))
+	for _, name := range names {
 		if !strings.HasSuffix(name, tokenSuffix) {
 			continue
 		}
@@ -375,23 +529,15 @@ func sortTokens(tokens []fileToken) {
 }
 
 func (q *Queue) readPayload(name string) ([]byte, error) {
-	root, err := os.OpenRoot(q.dir)
-	if err != nil {
-		return nil, err
-	}
-	defer func() {
-		_ = root.Close()
-	}()
-
-	f, err := root.Open(name)
+	data, err := q.readFile(name)
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		_ = f.Close()
-	}()
+	return decodePayload(data)
+}
 
-	return io.ReadAll(f)
+func (q *Queue) readFile(name string) ([]byte, error) {
+	return q.store.read(name)
 }
 
 func parseToken(name string) (fileToken, error) {
@@ -462,28 +608,73 @@ func formatToken(token fileToken) string {
 	return fmt.Sprintf("%020d-%020d-%06d-%03d%s", retry, created, token.seq%1_000_000, token.attempts, tokenSuffix)
 }
 
-func (q *Queue) shouldDrop(token fileToken, queueLen int) bool {
+func (q *Queue) dropReason(token fileToken, queueLen int) (DropReason, bool) {
 	if queueLen >= q.maxFiles {
-		return true
+		return DropOverflow, true
+	}
+	if q.expired(token) {
+		return DropExpired, true
 	}
 	if token.attempts+1 >= maxRetryAttempts {
 		if q.now().Sub(token.createdAt) > staleAttemptAge {
-			return true
+			return DropRetriesExhausted, true
+		}
+	}
+	return "", false
+}
+
+func (q *Queue) expired(token fileToken) bool {
+	return q.maxAge > 0 && q.now().Sub(token.createdAt) > q.maxAge
+}
+
+// drop hands the payload stored under token to the drop callback, removes it, and counts it.
+// Corrupt payloads are moved to the quarantine directory instead of being deleted. The payload is
+// read from disk when the caller does not already hold it; corrupt files are passed as stored.
+func (q *Queue) drop(token string, payload []byte, reason DropReason) error {
+	return q.dropTo(token, payload, reason, reason == DropCorrupt)
+}
+
+// dropTo is drop with the choice between quarantining and deleting the file made by the caller.
+func (q *Queue) dropTo(token string, payload []byte, reason DropReason, quarantine bool) error {
+	if q.onDrop != nil {
+		if payload == nil {
+			if reason == DropCorrupt {
+				payload, _ = q.readFile(token)
+			} else {
+				payload, _ = q.readPayload(token)
+			}
 		}
+		q.onDrop(token, payload, reason)
+	}
+	remove := q.Complete
+	if quarantine {
+		remove = q.quarantine
+	}
+	if err := remove(token); err != nil {
+		return err
 	}
-	return false
+	q.dropMu.Lock()
+	if q.dropped == nil {
+		q.dropped = make(map[DropReason]uint64)
+	}
+	q.dropped[reason]++
+	q.dropMu.Unlock()
+	return nil
 }
 
-func (q *Queue) scheduleRetry(token fileToken) error {
+// scheduleRetry postpones token by the backoff for its attempt count, or by throttle when the
+// remote asked for a delay of its own.
+func (q *Queue) scheduleRetry(token fileToken, throttle time.Duration) error {
 	next := token
 	next.attempts++
 	delay := q.retryDelay(next.attempts)
+	if throttle > 0 {
+		delay = throttle
+	}
 	next.retryAt = q.now().Add(delay)
 	next.seq = int(atomic.AddUint64(&q.counter, 1) % 1_000_000)
 	newName := formatToken(next)
-	oldPath := filepath.Join(q.dir, token.name)
-	newPath := filepath.Join(q.dir, newName)
-	if err := os.Rename(oldPath, newPath); err != nil {
+	if err := q.store.rename(token.name, newName); err != nil {
 		return err
 	}
 	q.signal()
@@ -541,12 +732,18 @@ func (q *Queue) removeStaleFiles(tokens []fileToken) int {
 	now := q.now()
 	removed := 0
 	for _, token := range tokens {
-		if token.attempts >= maxRetryAttempts && now.Sub(token.createdAt) > staleAttemptAge {
-			if err := q.Complete(token.name); err != nil && !errors.Is(err, fs.ErrNotExist) {
-				q.logError(fmt.Errorf("spool: remove stale file %s: %w", token.name, err))
-			} else {
-				removed++
-			}
+		reason := DropRetriesExhausted
+		switch {
+		case q.expired(token):
+			reason = DropExpired
+		case token.attempts >= maxRetryAttempts && now.Sub(token.createdAt) > staleAttemptAge:
+		default:
+			continue
+		}
+		if err := q.drop(token.name, nil, reason); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			q.logError(fmt.Errorf("spool: remove stale file %s: %w", token.name, err))
+		} else {
+			removed++
 		}
 	}
 	return removed
@@ -562,7 +759,7 @@ func (q *Queue) removeOverflowFiles(tokens []fileToken) int {
 	excess := len(tokens) - q.maxFiles
 	for i := range excess {
 		name := tokens[i].name
-		if err := q.Complete(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		if err := q.drop(name, nil, DropOverflow); err != nil && !errors.Is(err, fs.ErrNotExist) {
 			q.logError(fmt.Errorf("spool: remove overflow file %s: %w", name, err))
 		} else {
 			removed++
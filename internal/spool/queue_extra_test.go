@@ -168,3 +168,21 @@ func TestQueueCompleteIgnoresMissingFiles(t *testing.T) {
 		t.Fatalf("expected missing file removal to succeed, got %v", err)
 	}
 }
+
+func TestQueueLenCountsPendingPayloads(t *testing.T) {
+	queue, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if got := queue.Len(); got != 0 {
+		t.Fatalf("expected empty queue, got %d", got)
+	}
+	for range 3 {
+		if _, err := queue.Enqueue([]byte("payload")); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+	if got := queue.Len(); got != 3 {
+		t.Fatalf("expected 3 pending payloads, got %d", got)
+	}
+}
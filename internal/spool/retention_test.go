@@ -0,0 +1,152 @@
+package spool
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+type dropRecord struct {
+	token   string
+	payload string
+	reason  DropReason
+}
+
+type dropRecorder struct {
+	mu    sync.Mutex
+	drops []dropRecord
+}
+
+func (r *dropRecorder) record(token string, payload []byte, reason DropReason) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.drops = append(r.drops, dropRecord{token: token, payload: string(payload), reason: reason})
+}
+
+func (r *dropRecorder) snapshot() []dropRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]dropRecord(nil), r.drops...)
+}
+
+func TestQueueDropsExpiredPayloads(t *testing.T) {
+	t.Parallel()
+
+	recorder := &dropRecorder{}
+	queue, err := NewWithErrorLogger(t.TempDir(), nil, WithMaxAge(time.Minute), WithDropFunc(recorder.record))
+	if err != nil {
+		t.Fatalf("NewWithErrorLogger: %v", err)
+	}
+	base := time.Now()
+	queue.now = func() time.Time { return base }
+	token, err := queue.Enqueue([]byte("old"))
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	queue.now = func() time.Time { return base.Add(2 * time.Minute) }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	queue.Start(ctx, func(context.Context, []byte) error {
+		t.Error("expired payload should not be handled")
+		return nil
+	})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for queue.Len() > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expired payload was not dropped")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+
+	drops := recorder.snapshot()
+	if len(drops) != 1 || drops[0].token != token || drops[0].payload != "old" || drops[0].reason != DropExpired {
+		t.Fatalf("unexpected drops: %+v", drops)
+	}
+	if got := queue.Dropped()[DropExpired]; got != 1 {
+		t.Fatalf("expected one expired drop counted, got %d", got)
+	}
+}
+
+func TestQueueReportsCorruptDrops(t *testing.T) {
+	t.Parallel()
+
+	recorder := &dropRecorder{}
+	queue, err := NewWithErrorLogger(t.TempDir(), nil, WithDropFunc(recorder.record))
+	if err != nil {
+		t.Fatalf("NewWithErrorLogger: %v", err)
+	}
+	if _, err := queue.Enqueue([]byte("garbage")); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	queue.Start(ctx, func(context.Context, []byte) error { return ErrCorrupt })
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(recorder.snapshot()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("corrupt payload was not reported")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if drop := recorder.snapshot()[0]; drop.reason != DropCorrupt || drop.payload != "garbage" {
+		t.Fatalf("unexpected drop: %+v", drop)
+	}
+}
+
+func TestCleanOldFilesReportsOverflowAndExpiry(t *testing.T) {
+	t.Parallel()
+
+	recorder := &dropRecorder{}
+	queue, err := NewWithErrorLogger(t.TempDir(), nil, WithMaxAge(time.Hour), WithDropFunc(recorder.record))
+	if err != nil {
+		t.Fatalf("NewWithErrorLogger: %v", err)
+	}
+	base := time.Now()
+	queue.now = func() time.Time { return base }
+	if _, err := queue.Enqueue([]byte("stale")); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	queue.now = func() time.Time { return base.Add(2 * time.Hour) }
+	queue.maxFiles = 1
+	for _, payload := range []string{"a", "b", "c"} {
+		if _, err := queue.Enqueue([]byte(payload)); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+
+	reasons := map[DropReason][]string{}
+	for _, drop := range recorder.snapshot() {
+		reasons[drop.reason] = append(reasons[drop.reason], drop.payload)
+	}
+	if len(reasons[DropExpired]) != 1 || reasons[DropExpired][0] != "stale" {
+		t.Fatalf("expected stale payload to expire, got %+v", reasons)
+	}
+	if len(reasons[DropOverflow]) != 1 || reasons[DropOverflow][0] != "a" {
+		t.Fatalf("expected oldest payload to overflow, got %+v", reasons)
+	}
+	if queue.Len() != 2 {
+		t.Fatalf("expected two payloads left, got %d", queue.Len())
+	}
+}
+
+func TestWithMaxAgeIgnoresNonPositive(t *testing.T) {
+	t.Parallel()
+
+	queue, err := NewWithErrorLogger(t.TempDir(), nil, WithMaxAge(-time.Second))
+	if err != nil {
+		t.Fatalf("NewWithErrorLogger: %v", err)
+	}
+	if queue.maxAge != 0 {
+		t.Fatalf("expected max age to stay off, got %v", queue.maxAge)
+	}
+	if dropped := queue.Dropped(); len(dropped) != 0 {
+		t.Fatalf("expected no drops, got %v", dropped)
+	}
+}
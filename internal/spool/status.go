@@ -0,0 +1,183 @@
+package spool
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRetryAfter caps how long a Retry-After header can postpone a payload.
+const maxRetryAfter = time.Hour
+
+// StatusAction tells the queue what to do with a payload the remote answered with a given status.
+type StatusAction int
+
+// Actions a StatusPolicy can assign to a response status.
+const (
+	// StatusRetry keeps the payload and retries it with backoff, or after Retry-After when the
+	// response carries one.
+	StatusRetry StatusAction = iota
+	// StatusDrop discards the payload as rejected.
+	StatusDrop
+	// StatusQuarantine moves the payload to the quarantine directory as rejected.
+	StatusQuarantine
+)
+
+// StatusPolicy overrides the action for individual response statuses; statuses it does not list
+// follow DefaultStatusAction. gRPC transports key it by status code instead of HTTP status.
+type StatusPolicy map[int]StatusAction
+
+// Names of the actions in configuration, as ParseStatusPolicy reads them.
+const (
+	StatusRetryName      = "retry"
+	StatusDropName       = "drop"
+	StatusQuarantineName = "quarantine"
+)
+
+// ParseStatusPolicy converts a configured policy of action names into a StatusPolicy. Unknown
+// names are left out, so their statuses keep the default action; configs validate the names first.
+func ParseStatusPolicy(names map[int]string) StatusPolicy {
+	if len(names) == 0 {
+		return nil
+	}
+	policy := make(StatusPolicy, len(names))
+	for code, name := range names {
+		switch name {
+		case StatusRetryName:
+			policy[code] = StatusRetry
+		case StatusDropName:
+			policy[code] = StatusDrop
+		case StatusQuarantineName:
+			policy[code] = StatusQuarantine
+		}
+	}
+	return policy
+}
+
+// DefaultStatusAction retries 408, 429, and 5xx responses, which a collector returns while
+// overloaded or restarting, and drops every other 4xx response, such as 400, 401, or 413, since
+// sending the same payload again cannot succeed. Anything else is retried.
+func DefaultStatusAction(code int) StatusAction {
+	switch {
+	case code == http.StatusRequestTimeout, code == http.StatusTooManyRequests:
+		return StatusRetry
+	case code >= 400 && code < 500:
+		return StatusDrop
+	default:
+		return StatusRetry
+	}
+}
+
+func (p StatusPolicy) action(code int) StatusAction {
+	if action, ok := p[code]; ok {
+		return action
+	}
+	return DefaultStatusAction(code)
+}
+
+// Reject wraps err in the error that makes the queue carry out action: a *RejectedError for drop
+// and quarantine, err itself for retry.
+func (a StatusAction) Reject(err error) error {
+	switch a {
+	case StatusDrop:
+		return &RejectedError{Err: err}
+	case StatusQuarantine:
+		return &RejectedError{Err: err, Quarantine: true}
+	default:
+		return err
+	}
+}
+
+// StatusError reports a non-2xx response to a queued HTTP request.
+type StatusError struct {
+	StatusCode int
+	// RetryAfter is the delay the Retry-After header asked for, or zero.
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	text := fmt.Sprintf("spool: remote status %d", e.StatusCode)
+	if status := http.StatusText(e.StatusCode); status != "" {
+		text += " " + status
+	}
+	if e.RetryAfter > 0 {
+		text += fmt.Sprintf(" (retry after %s)", e.RetryAfter)
+	}
+	return text
+}
+
+// RejectedError marks a handler failure that retrying cannot fix. The queue drops the payload, or
+// quarantines it when Quarantine is set, and reports DropRejected.
+type RejectedError struct {
+	Err        error
+	Quarantine bool
+}
+
+func (e *RejectedError) Error() string {
+	return "spool: payload rejected: " + e.Err.Error()
+}
+
+func (e *RejectedError) Unwrap() error {
+	return e.Err
+}
+
+// ThrottledError marks a handler failure where the remote asked to be left alone for RetryAfter,
+// such as a gRPC RESOURCE_EXHAUSTED carrying RetryInfo. It is treated like a StatusError with
+// Retry-After.
+type ThrottledError struct {
+	Err        error
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Error() string {
+	return fmt.Sprintf("spool: remote throttled (retry after %s): %v", e.RetryAfter, e.Err)
+}
+
+func (e *ThrottledError) Unwrap() error {
+	return e.Err
+}
+
+// retryAfter returns the delay err asks for before anything is sent again, or zero when the
+// remote gave none.
+func retryAfter(err error) time.Duration {
+	var delay time.Duration
+	var throttled *ThrottledError
+	var statusErr *StatusError
+	switch {
+	case errors.As(err, &throttled):
+		delay = throttled.RetryAfter
+	case errors.As(err, &statusErr):
+		delay = statusErr.RetryAfter
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return min(delay, maxRetryAfter)
+}
+
+// classifyStatus turns a non-2xx response into the error the queue acts on.
+func classifyStatus(resp *http.Response, policy StatusPolicy, now time.Time) error {
+	err := &StatusError{StatusCode: resp.StatusCode, RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), now)}
+	return policy.action(resp.StatusCode).Reject(err)
+}
+
+// parseRetryAfter reads a Retry-After value given in seconds or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	var delay time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		delay = time.Duration(seconds) * time.Second
+	} else if at, err := http.ParseTime(value); err == nil {
+		delay = at.Sub(now)
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return min(delay, maxRetryAfter)
+}
@@ -0,0 +1,244 @@
+package spool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDefaultStatusAction(t *testing.T) {
+	cases := map[int]StatusAction{
+		http.StatusBadRequest:            StatusDrop,
+		http.StatusUnauthorized:          StatusDrop,
+		http.StatusRequestEntityTooLarge: StatusDrop,
+		http.StatusRequestTimeout:        StatusRetry,
+		http.StatusTooManyRequests:       StatusRetry,
+		http.StatusInternalServerError:   StatusRetry,
+		http.StatusServiceUnavailable:    StatusRetry,
+		http.StatusMovedPermanently:      StatusRetry,
+	}
+	for code, want := range cases {
+		if got := DefaultStatusAction(code); got != want {
+			t.Errorf("DefaultStatusAction(%d) = %v, want %v", code, got, want)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	cases := map[string]time.Duration{
+		"":                              0,
+		"30":                            30 * time.Second,
+		"-5":                            0,
+		"soon":                          0,
+		"86400":                         maxRetryAfter,
+		"Mon, 01 Jan 2024 12:02:00 GMT": 2 * time.Minute,
+		"Mon, 01 Jan 2024 11:00:00 GMT": 0,
+	}
+	for value, want := range cases {
+		if got := parseRetryAfter(value, now); got != want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", value, got, want)
+		}
+	}
+}
+
+func TestHTTPHandlerClassifiesStatus(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Retry-After", "7")
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(srv.Close)
+	payload, err := (&HTTPRequest{Method: http.MethodPost, URL: srv.URL}).Marshal()
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	err = HTTPHandler(srv.Client())(context.Background(), payload)
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != status || statusErr.RetryAfter != 7*time.Second {
+		t.Fatalf("expected retryable 503 with Retry-After, got %v", err)
+	}
+	var rejected *RejectedError
+	if errors.As(err, &rejected) {
+		t.Fatalf("503 should not be rejected: %v", err)
+	}
+
+	status = http.StatusRequestEntityTooLarge
+	err = HTTPHandler(srv.Client())(context.Background(), payload)
+	if !errors.As(err, &rejected) || rejected.Quarantine {
+		t.Fatalf("expected 413 to be dropped, got %v", err)
+	}
+
+	status = http.StatusBadRequest
+	err = HTTPHandlerWithPolicy(srv.Client(), StatusPolicy{http.StatusBadRequest: StatusQuarantine}, nil)(context.Background(), payload)
+	if !errors.As(err, &rejected) || !rejected.Quarantine {
+		t.Fatalf("expected policy to quarantine 400, got %v", err)
+	}
+	err = HTTPHandlerWithPolicy(srv.Client(), StatusPolicy{http.StatusBadRequest: StatusRetry}, nil)(context.Background(), payload)
+	if errors.As(err, &rejected) || !errors.As(err, &statusErr) {
+		t.Fatalf("expected policy to retry 400, got %v", err)
+	}
+}
+
+func TestQueueDropsRejectedPayloads(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	recorder := &dropRecorder{}
+	queue, err := NewWithErrorLogger(dir, nil, WithDropFunc(recorder.record))
+	if err != nil {
+		t.Fatalf("NewWithErrorLogger: %v", err)
+	}
+	dropped, err := queue.Enqueue([]byte("drop"))
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	quarantined, err := queue.Enqueue([]byte("quarantine"))
+	if err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	queue.Start(t.Context(), func(_ context.Context, payload []byte) error {
+		return &RejectedError{Err: &StatusError{StatusCode: http.StatusBadRequest}, Quarantine: string(payload) == "quarantine"}
+	})
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(recorder.snapshot()) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected both payloads rejected, got %+v", recorder.snapshot())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	for _, drop := range recorder.snapshot() {
+		if drop.reason != DropRejected {
+			t.Fatalf("expected rejected drop, got %+v", drop)
+		}
+	}
+	if got := queue.Dropped()[DropRejected]; got != 2 {
+		t.Fatalf("expected 2 rejected drops, got %d", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, QuarantineDir, quarantined)); err != nil {
+		t.Fatalf("expected quarantined payload kept: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, QuarantineDir, dropped)); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected dropped payload deleted, got %v", err)
+	}
+}
+
+func TestQueueRetryHonorsRetryAfter(t *testing.T) {
+	clk := &manualClock{now: time.Unix(1_700_000_000, 0), timers: make(chan *manualTimer, 8)}
+	queue, err := NewWithErrorLogger(t.TempDir(), nil, WithClock(clk))
+	if err != nil {
+		t.Fatalf("NewWithErrorLogger: %v", err)
+	}
+
+	attempts := make(chan struct{}, 2)
+	queue.Start(t.Context(), func(context.Context, []byte) error {
+		attempts <- struct{}{}
+		return &StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: 45 * time.Second}
+	})
+	if _, err := queue.Enqueue([]byte("payload")); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	select {
+	case <-attempts:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for first attempt")
+	}
+	select {
+	case timer := <-clk.timers:
+		if timer.d != 45*time.Second {
+			t.Fatalf("expected retry after 45s, waited %v", timer.d)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for retry timer")
+	}
+}
+
+func TestQueueThrottleHoldsEveryPayload(t *testing.T) {
+	clk := &manualClock{now: time.Unix(1_700_000_000, 0), timers: make(chan *manualTimer, 8)}
+	queue, err := NewWithErrorLogger(t.TempDir(), nil, WithClock(clk))
+	if err != nil {
+		t.Fatalf("NewWithErrorLogger: %v", err)
+	}
+	for _, payload := range []string{"first", "second"} {
+		if _, err := queue.Enqueue([]byte(payload)); err != nil {
+			t.Fatalf("Enqueue: %v", err)
+		}
+	}
+
+	attempts := make(chan string, 4)
+	queue.Start(t.Context(), func(_ context.Context, payload []byte) error {
+		attempts <- string(payload)
+		return &ThrottledError{Err: errors.New("resource exhausted"), RetryAfter: 30 * time.Second}
+	})
+
+	select {
+	case got := <-attempts:
+		if got != "first" {
+			t.Fatalf("expected the oldest payload first, got %q", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for first attempt")
+	}
+	select {
+	case timer := <-clk.timers:
+		if timer.d != 30*time.Second {
+			t.Fatalf("expected the queue to pause for 30s, waited %v", timer.d)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the throttle pause")
+	}
+	select {
+	case got := <-attempts:
+		t.Fatalf("expected no delivery while throttled, got %q", got)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestRetryAfterReadsThrottledErrors(t *testing.T) {
+	wrapped := fmt.Errorf("export: %w", &ThrottledError{Err: errors.New("busy"), RetryAfter: 5 * time.Second})
+	if got := retryAfter(wrapped); got != 5*time.Second {
+		t.Fatalf("retryAfter = %v, want 5s", got)
+	}
+	if got := retryAfter(&ThrottledError{RetryAfter: 48 * time.Hour}); got != maxRetryAfter {
+		t.Fatalf("expected the delay capped at %v, got %v", maxRetryAfter, got)
+	}
+	if got := retryAfter(errors.New("dial failed")); got != 0 {
+		t.Fatalf("expected no delay for plain errors, got %v", got)
+	}
+}
+
+func TestParseStatusPolicy(t *testing.T) {
+	policy := ParseStatusPolicy(map[int]string{
+		http.StatusBadRequest:         StatusQuarantineName,
+		http.StatusServiceUnavailable: StatusDropName,
+		http.StatusConflict:           StatusRetryName,
+		http.StatusTeapot:             "ignore",
+	})
+	want := StatusPolicy{
+		http.StatusBadRequest:         StatusQuarantine,
+		http.StatusServiceUnavailable: StatusDrop,
+		http.StatusConflict:           StatusRetry,
+	}
+	if len(policy) != len(want) {
+		t.Fatalf("expected %v, got %v", want, policy)
+	}
+	for code, action := range want {
+		if policy[code] != action {
+			t.Fatalf("status %d: expected %v, got %v", code, action, policy[code])
+		}
+	}
+	if ParseStatusPolicy(nil) != nil {
+		t.Fatal("expected no policy without names")
+	}
+}
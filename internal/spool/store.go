@@ -0,0 +1,241 @@
+package spool
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// store holds queued payload files by token name.
+type store interface {
+	names() ([]string, error)
+	read(name string) ([]byte, error)
+	write(name string, data []byte) error
+	remove(name string) error
+	rename(oldName, newName string) error
+	// quarantine sets the named file aside for inspection, keeping at most keep quarantined files.
+	quarantine(name string, keep int) error
+}
+
+// diskStore keeps payloads as files in dir.
+type diskStore struct {
+	dir string
+}
+
+// openDiskStore creates dir and verifies that it accepts writes.
+func openDiskStore(dir string) (*diskStore, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("spool: create dir: %w", err)
+	}
+
+	probe, err := os.CreateTemp(dir, ".spool-probe-*")
+	if err != nil {
+		return nil, fmt.Errorf("spool: probe write: %w", err)
+	}
+	probeName := filepath.Base(probe.Name())
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		_ = probe.Close()
+		return nil, fmt.Errorf("spool: open root: %w", err)
+	}
+	defer func() {
+		_ = root.Close()
+	}()
+
+	if cerr := probe.Close(); cerr != nil {
+		_ = root.Remove(probeName)
+		return nil, fmt.Errorf("spool: probe close: %w", cerr)
+	}
+	if err := root.Remove(probeName); err != nil {
+		return nil, fmt.Errorf("spool: probe cleanup: %w", err)
+	}
+	removeInterruptedWrites(dir)
+	return &diskStore{dir: dir}, nil
+}
+
+func (s *diskStore) names() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("spool: read dir: %w", err)
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	return names, nil
+}
+
+func (s *diskStore) read(name string) ([]byte, error) {
+	root, err := os.OpenRoot(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = root.Close()
+	}()
+
+	f, err := root.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	return io.ReadAll(f)
+}
+
+func (s *diskStore) write(name string, data []byte) error {
+	return writeAtomic(s.dir, name, data)
+}
+
+func (s *diskStore) remove(name string) error {
+	if err := os.Remove(filepath.Join(s.dir, name)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *diskStore) rename(oldName, newName string) error {
+	return os.Rename(filepath.Join(s.dir, oldName), filepath.Join(s.dir, newName))
+}
+
+func (s *diskStore) quarantine(name string, keep int) error {
+	dir := filepath.Join(s.dir, QuarantineDir)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return fmt.Errorf("spool: create quarantine: %w", err)
+	}
+	if err := os.Rename(filepath.Join(s.dir, name), filepath.Join(dir, name)); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("spool: quarantine payload: %w", err)
+	}
+	return pruneDir(dir, keep)
+}
+
+// writeAtomic writes data to a temporary file in dir and renames it to name once it is synced,
+// so a crash never leaves a partially written payload under a queue token.
+func writeAtomic(dir, name string, data []byte) (err error) {
+	tmp, err := os.CreateTemp(dir, writeTempPattern)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+	if _, err = tmp.Write(data); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(dir, name))
+}
+
+// removeInterruptedWrites deletes temporary files left behind by writes that never completed.
+func removeInterruptedWrites(dir string) {
+	matches, err := filepath.Glob(filepath.Join(dir, writeTempPattern))
+	if err != nil {
+		return
+	}
+	for _, match := range matches {
+		_ = os.Remove(match)
+	}
+}
+
+// pruneDir removes the lexically smallest, and so oldest, files in dir beyond keep.
+func pruneDir(dir string, keep int) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) <= keep {
+		return err
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	var errs error
+	for i := 0; i < len(names)-keep; i++ {
+		if err := os.Remove(filepath.Join(dir, names[i])); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = errors.Join(errs, fmt.Errorf("spool: prune quarantine %s: %w", names[i], err))
+		}
+	}
+	return errs
+}
+
+// memoryStore keeps payloads in memory when the queue directory is unusable. Payloads do not survive
+// a restart, and corrupt payloads are discarded rather than quarantined.
+type memoryStore struct {
+	mu    sync.Mutex
+	files map[string][]byte
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{files: make(map[string][]byte)}
+}
+
+func (s *memoryStore) names() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.files))
+	for name := range s.files {
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func (s *memoryStore) read(name string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.files[name]
+	if !ok {
+		return nil, fs.ErrNotExist
+	}
+	return append([]byte(nil), data...), nil
+}
+
+func (s *memoryStore) write(name string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.files[name] = append([]byte(nil), data...)
+	return nil
+}
+
+func (s *memoryStore) remove(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.files, name)
+	return nil
+}
+
+func (s *memoryStore) rename(oldName, newName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, ok := s.files[oldName]
+	if !ok {
+		return fs.ErrNotExist
+	}
+	delete(s.files, oldName)
+	s.files[newName] = data
+	return nil
+}
+
+func (s *memoryStore) quarantine(name string, _ int) error {
+	return s.remove(name)
+}
@@ -0,0 +1,100 @@
+package spool
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQueueFallsBackToMemoryWhenDirUnusable(t *testing.T) {
+	t.Parallel()
+
+	blocker := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(blocker, []byte("x"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	var logged []error
+	queue, err := NewWithErrorLogger(filepath.Join(blocker, "queue"), ErrorLoggerFunc(func(err error) { logged = append(logged, err) }))
+	if err != nil {
+		t.Fatalf("expected fallback instead of error: %v", err)
+	}
+	if queue.Persistent() {
+		t.Fatal("expected in-memory queue")
+	}
+	if len(logged) != 1 || !strings.Contains(logged[0].Error(), "buffering in memory") {
+		t.Fatalf("expected fallback warning, got %v", logged)
+	}
+
+	if _, err := queue.Enqueue([]byte("payload")); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if queue.Len() != 1 {
+		t.Fatalf("expected one queued payload, got %d", queue.Len())
+	}
+
+	received := make(chan string, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	queue.Start(ctx, func(_ context.Context, payload []byte) error {
+		received <- string(payload)
+		return nil
+	})
+	select {
+	case got := <-received:
+		if got != "payload" {
+			t.Fatalf("unexpected payload %q", got)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for in-memory payload")
+	}
+}
+
+func TestQueueIsPersistentOnWritableDir(t *testing.T) {
+	t.Parallel()
+
+	queue, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if !queue.Persistent() {
+		t.Fatal("expected disk-backed queue")
+	}
+}
+
+func TestValidTokenRejectsSeparators(t *testing.T) {
+	t.Parallel()
+
+	for _, token := range []string{"", "..", `..\escape`, "../escape", `dir\file.spool`, "/abs.spool"} {
+		if validToken(token) {
+			t.Fatalf("expected %q to be rejected", token)
+		}
+	}
+	if !validToken("00000000000000000000-000001.spool") {
+		t.Fatal("expected plain token to be accepted")
+	}
+}
+
+func TestMemoryStoreRename(t *testing.T) {
+	t.Parallel()
+
+	store := newMemoryStore()
+	if err := store.write("a", []byte("x")); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if err := store.rename("a", "b"); err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	if _, err := store.read("a"); !os.IsNotExist(err) {
+		t.Fatalf("expected old name to be gone, got %v", err)
+	}
+	if data, err := store.read("b"); err != nil || string(data) != "x" {
+		t.Fatalf("read renamed = %q, %v", data, err)
+	}
+	if err := store.rename("missing", "c"); err == nil {
+		t.Fatal("expected rename of missing file to fail")
+	}
+}
@@ -0,0 +1,150 @@
+package goo11y
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/pyroscope-go"
+	"github.com/mfahmialkautsar/goo11y/logger"
+	"github.com/mfahmialkautsar/goo11y/meter"
+	"github.com/mfahmialkautsar/goo11y/tracer"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	jobInstrumentation  = "github.com/mfahmialkautsar/goo11y/job"
+	jobDurationName     = "job.duration"
+	jobNameKey          = attribute.Key("job.name")
+	jobOutcomeKey       = attribute.Key("job.outcome")
+	jobProfileLabelName = "job_name"
+)
+
+// Job outcome values recorded on the job.outcome attribute.
+const (
+	JobOutcomeSuccess = "success"
+	JobOutcomeFailure = "failure"
+	JobOutcomePanic   = "panic"
+)
+
+// JobOption configures job instrumentation.
+type JobOption func(*jobConfig)
+
+type jobConfig struct {
+	profile bool
+	attrs   []attribute.KeyValue
+}
+
+// WithJobProfiling tags the CPU samples taken during each run with the job name via pyroscope.TagWrapper.
+func WithJobProfiling() JobOption {
+	return func(c *jobConfig) {
+		c.profile = true
+	}
+}
+
+// WithJobAttributes adds attributes to every run span.
+func WithJobAttributes(attrs ...attribute.KeyValue) JobOption {
+	return func(c *jobConfig) {
+		c.attrs = append(c.attrs, attrs...)
+	}
+}
+
+// InstrumentJob wraps a periodic or background job using the global components.
+func InstrumentJob(name string, fn func(context.Context) error, opts ...JobOption) func(context.Context) error {
+	return instrumentJob(nil, name, fn, opts)
+}
+
+// InstrumentJob wraps a periodic or background job so that every run starts a root span,
+// records its duration and outcome, and logs panics with their stack instead of crashing.
+// A recovered panic is returned as an error. Falls back to the global components if receiver is nil.
+func (t *Telemetry) InstrumentJob(name string, fn func(context.Context) error, opts ...JobOption) func(context.Context) error {
+	return instrumentJob(t, name, fn, opts)
+}
+
+func instrumentJob(t *Telemetry, name string, fn func(context.Context) error, opts []JobOption) func(context.Context) error {
+	cfg := jobConfig{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+
+	return func(ctx context.Context) error {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		if fn == nil {
+			return nil
+		}
+		log, tp, mp := t.jobTargets()
+
+		spanAttrs := append([]attribute.KeyValue{jobNameKey.String(name)}, cfg.attrs...)
+		ctx, span := tp.Tracer(jobInstrumentation).Start(ctx, name,
+			trace.WithNewRoot(),
+			trace.WithSpanKind(trace.SpanKindInternal),
+			trace.WithAttributes(spanAttrs...),
+		)
+		defer span.End()
+
+		start := t.now()
+		outcome, err := runJob(ctx, fn, cfg.profile, name, log, mp)
+
+		span.SetAttributes(jobOutcomeKey.String(outcome))
+		if err != nil && outcome == JobOutcomeFailure {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			if log != nil {
+				log.Error().Ctx(ctx).Str("job", name).Err(err).Msg("job failed")
+			}
+		}
+		recordJob(ctx, mp, name, outcome, t.now().Sub(start))
+		return err
+	}
+}
+
+func runJob(ctx context.Context, fn func(context.Context) error, profile bool, name string, log *logger.Logger, mp *meter.Provider) (outcome string, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = log.LogPanic(ctx, recovered)
+			recordPanic(ctx, mp, "job")
+			outcome = JobOutcomePanic
+		}
+	}()
+
+	if profile {
+		pyroscope.TagWrapper(ctx, pyroscope.Labels(jobProfileLabelName, name), func(ctx context.Context) {
+			err = fn(ctx)
+		})
+	} else {
+		err = fn(ctx)
+	}
+	if err != nil {
+		return JobOutcomeFailure, err
+	}
+	return JobOutcomeSuccess, nil
+}
+
+func recordJob(ctx context.Context, provider *meter.Provider, name, outcome string, elapsed time.Duration) {
+	histogram, err := provider.Meter(jobInstrumentation).Float64Histogram(
+		jobDurationName,
+		metric.WithDescription("Duration of background job runs"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return
+	}
+	histogram.Record(ctx, elapsed.Seconds(), metric.WithAttributes(
+		jobNameKey.String(name),
+		jobOutcomeKey.String(outcome),
+	))
+}
+
+func (t *Telemetry) jobTargets() (*logger.Logger, *tracer.Provider, *meter.Provider) {
+	log, mp := t.panicTargets()
+	if t == nil {
+		return log, tracer.Global(), mp
+	}
+	return log, t.Tracer(), mp
+}
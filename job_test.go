@@ -0,0 +1,153 @@
+package goo11y
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mfahmialkautsar/goo11y/clock"
+	"github.com/mfahmialkautsar/goo11y/internal/testutil/inmemory"
+	"github.com/mfahmialkautsar/goo11y/tracer"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newJobTelemetry(t *testing.T) (*Telemetry, *tracetest.SpanRecorder, *sdkmetric.ManualReader) {
+	t.Helper()
+	tele, _, reader := newRecoverTelemetry(t)
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	tele.tracer.Store(tracer.NewProvider(tp))
+	return tele, recorder, reader
+}
+
+// steppingClock moves forward by step every time it is read.
+type steppingClock struct {
+	mu      sync.Mutex
+	current time.Time
+	step    time.Duration
+}
+
+func (c *steppingClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.current = c.current.Add(c.step)
+	return c.current
+}
+
+func (c *steppingClock) NewTimer(d time.Duration) clock.Timer { return clock.Real().NewTimer(d) }
+
+func (c *steppingClock) NewTicker(d time.Duration) clock.Ticker { return clock.Real().NewTicker(d) }
+
+func jobOutcomes(t *testing.T, reader *sdkmetric.ManualReader) map[string]uint64 {
+	t.Helper()
+	rm, err := inmemory.GetMetrics(context.Background(), reader)
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	m, ok := inmemory.FindMetricByName(rm, jobDurationName)
+	if !ok {
+		t.Fatalf("metric %s not recorded", jobDurationName)
+	}
+	hist, ok := m.Data.(metricdata.Histogram[float64])
+	if !ok {
+		t.Fatalf("unexpected data type %T", m.Data)
+	}
+	out := map[string]uint64{}
+	for _, dp := range hist.DataPoints {
+		outcome, _ := dp.Attributes.Value(jobOutcomeKey)
+		out[outcome.AsString()] += dp.Count
+	}
+	return out
+}
+
+func TestInstrumentJobRecordsRuns(t *testing.T) {
+	tele, recorder, reader := newJobTelemetry(t)
+
+	parent, parentSpan := tele.Tracer().Tracer("caller").Start(context.Background(), "scheduler")
+	defer parentSpan.End()
+
+	calls := 0
+	job := tele.InstrumentJob("cleanup", func(context.Context) error {
+		calls++
+		if calls == 2 {
+			return errors.New("disk full")
+		}
+		return nil
+	}, WithJobProfiling())
+
+	if err := job(parent); err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+	if err := job(parent); err == nil {
+		t.Fatal("expected second run to fail")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 job spans, got %d", len(spans))
+	}
+	for _, span := range spans {
+		if span.Name() != "cleanup" || span.Parent().IsValid() {
+			t.Fatalf("expected root span named cleanup, got %s (parent %v)", span.Name(), span.Parent())
+		}
+	}
+	if spans[1].Status().Code != codes.Error {
+		t.Fatalf("expected failed run to set error status")
+	}
+
+	outcomes := jobOutcomes(t, reader)
+	if outcomes[JobOutcomeSuccess] != 1 || outcomes[JobOutcomeFailure] != 1 {
+		t.Fatalf("unexpected outcomes %v", outcomes)
+	}
+}
+
+func TestInstrumentJobRecoversPanic(t *testing.T) {
+	tele, buf, reader := newRecoverTelemetry(t)
+
+	job := tele.InstrumentJob("reindex", func(context.Context) error {
+		panic("corrupt index")
+	})
+	err := job(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "corrupt index") {
+		t.Fatalf("expected panic converted to error, got %v", err)
+	}
+	if !strings.Contains(buf.String(), "panic recovered") {
+		t.Fatalf("expected panic log, got %q", buf.String())
+	}
+	if got := panicCount(t, reader); got != 1 {
+		t.Fatalf("expected 1 panic counted, got %d", got)
+	}
+	if outcomes := jobOutcomes(t, reader); outcomes[JobOutcomePanic] != 1 {
+		t.Fatalf("unexpected outcomes %v", outcomes)
+	}
+}
+
+func TestInstrumentJobUsesClock(t *testing.T) {
+	tele, _, reader := newJobTelemetry(t)
+	WithClock(&steppingClock{current: time.Unix(0, 0), step: 3 * time.Second})(&tele.opts)
+
+	if err := tele.InstrumentJob("tick", func(context.Context) error { return nil })(context.Background()); err != nil {
+		t.Fatalf("job: %v", err)
+	}
+
+	rm, err := inmemory.GetMetrics(context.Background(), reader)
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	m, ok := inmemory.FindMetricByName(rm, jobDurationName)
+	if !ok {
+		t.Fatalf("metric %s not recorded", jobDurationName)
+	}
+	hist := m.Data.(metricdata.Histogram[float64])
+	if len(hist.DataPoints) != 1 || hist.DataPoints[0].Sum != 3 {
+		t.Fatalf("expected a 3s run from the injected clock, got %+v", hist.DataPoints)
+	}
+}
@@ -0,0 +1,39 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/mfahmialkautsar/goo11y/internal/otlputil"
+	"go.opentelemetry.io/otel/sdk/log"
+)
+
+// lazyLogExporter forwards to an exporter built in the background for OTLPConfig.AsyncInit.
+// Exports wait for it, within their own deadline, while the batch processor keeps queuing records.
+type lazyLogExporter struct {
+	exporter *otlputil.Lazy[log.Exporter]
+}
+
+func (e lazyLogExporter) Export(ctx context.Context, records []log.Record) error {
+	exporter, err := e.exporter.Wait(ctx)
+	if err != nil {
+		return err
+	}
+	return exporter.Export(ctx, records)
+}
+
+func (e lazyLogExporter) ForceFlush(ctx context.Context) error {
+	exporter, err := e.exporter.Wait(ctx)
+	if err != nil {
+		return err
+	}
+	return exporter.ForceFlush(ctx)
+}
+
+func (e lazyLogExporter) Shutdown(ctx context.Context) error {
+	exporter, err := e.exporter.Wait(ctx)
+	if err != nil {
+		// A failed build was already reported; there is nothing to shut down.
+		return ctx.Err()
+	}
+	return exporter.Shutdown(ctx)
+}
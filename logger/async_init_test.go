@@ -0,0 +1,30 @@
+package logger
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNewOTLPWriterAsyncInitDefersExporterErrors(t *testing.T) {
+	ctx := context.Background()
+	cfg := OTLPConfig{Endpoint: "collector:4318", Protocol: "udp"}
+	if _, err := newOTLPWriter(ctx, cfg, Config{ServiceName: "async-init"}); err == nil {
+		t.Fatal("expected an error for the unsupported protocol without AsyncInit")
+	}
+
+	cfg.AsyncInit = true
+	writer, err := newOTLPWriter(ctx, cfg, Config{ServiceName: "async-init"})
+	if err != nil {
+		t.Fatalf("newOTLPWriter: %v", err)
+	}
+	if _, err := writer.Write([]byte(`{"level":"info","message":"queued"}`)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if writer.Queued() != 0 {
+		t.Fatalf("expected no spool depth without an exporter, got %d", writer.Queued())
+	}
+	if err := writer.provider.ForceFlush(ctx); err == nil {
+		t.Fatal("expected the flush to report the failed build")
+	}
+	_ = writer.provider.Shutdown(ctx)
+}
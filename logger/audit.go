@@ -0,0 +1,242 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+const auditInstrumentation = "github.com/mfahmialkautsar/goo11y/logger/audit"
+
+type auditChannel struct {
+	logger  zerolog.Logger
+	writers *writerRegistry
+}
+
+func newAuditChannel(ctx context.Context, cfg Config, naming *fieldNaming) (*auditChannel, error) {
+	audit := cfg.Audit
+
+	registry := newWriterRegistry()
+	registry.naming = naming
+	closeOnErr := func(err error) (*auditChannel, error) {
+		_ = registry.close()
+		return nil, err
+	}
+
+	for idx, w := range audit.Writers {
+		registry.add(fmt.Sprintf("audit_custom_%d", idx), w, WriterTagLocal)
+	}
+	prev := audit.PrevHash
+	if audit.File.Enabled {
+		fileWriter, err := newDailyFileWriter(ctx, audit.File, cfg)
+		if err != nil {
+			return closeOnErr(fmt.Errorf("setup audit file writer: %w", err))
+		}
+		registry.add("audit_file", fileWriter, WriterTagLocal)
+		last, err := resumeAuditChain(fileWriter, audit.HashField)
+		if err != nil {
+			return closeOnErr(fmt.Errorf("resume audit chain: %w", err))
+		}
+		if last != "" {
+			prev = last
+		}
+	}
+	if audit.OTLP.Enabled {
+		otlpWriter, err := newOTLPWriter(ctx, audit.OTLP, cfg)
+		if err != nil {
+			return closeOnErr(fmt.Errorf("setup audit otlp writer: %w", err))
+		}
+		otlpWriter.logger = otlpWriter.provider.Logger(auditInstrumentation)
+		registry.add("audit_otlp", otlpWriter, WriterTagOTLP, WriterTagNetwork)
+	}
+	if registry.len() == 0 {
+		return nil, fmt.Errorf("audit: at least one writer must be configured")
+	}
+
+	chain := newHashChainWriter(registry.writer(), audit.HashField)
+	chain.prev = prev
+	base := zerolog.New(naming.writer(chain)).
+		With().
+		Timestamp().
+		Logger().
+		Hook(spanHook{naming: naming})
+
+	return &auditChannel{
+		logger:  withIdentity(base.With(), cfg, naming).Logger(),
+		writers: registry,
+	}, nil
+}
+
+// Audit opens an audit event routed to the dedicated audit writers.
+// Returns a disabled event when the audit channel is not configured.
+func (l *Logger) Audit() *zerolog.Event {
+	if l == nil || l.audit == nil {
+		return nil
+	}
+	return l.audit.logger.Log()
+}
+
+// hashChainWriter appends a hash of the previous entry hash and the current payload to every JSON line.
+type hashChainWriter struct {
+	mu    sync.Mutex
+	out   io.Writer
+	field string
+	prev  string
+}
+
+func newHashChainWriter(out io.Writer, field string) *hashChainWriter {
+	if field == "" {
+		field = "audit_hash"
+	}
+	return &hashChainWriter{out: out, field: field}
+}
+
+func (w *hashChainWriter) Write(p []byte) (int, error) {
+	payload := bytes.TrimRight(p, "\n")
+	if len(payload) == 0 || payload[len(payload)-1] != '}' {
+		return 0, fmt.Errorf("audit: payload is not a JSON object")
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	sum := chainHash(w.prev, payload)
+
+	line := make([]byte, 0, len(payload)+len(w.field)+len(sum)+8)
+	line = append(line, payload[:len(payload)-1]...)
+	if len(payload) > 2 {
+		line = append(line, ',')
+	}
+	line = strconv.AppendQuote(line, w.field)
+	line = append(line, ':')
+	line = strconv.AppendQuote(line, sum)
+	line = append(line, '}', '\n')
+
+	if _, err := w.out.Write(line); err != nil {
+		return 0, err
+	}
+	w.prev = sum
+	return len(p), nil
+}
+
+// maxAuditLine bounds the audit lines read back by VerifyAuditChain and lastAuditHash.
+const maxAuditLine = 1024 * 1024
+
+// resumeAuditChain returns the hash of the last entry written by w's predecessors: the last line of
+// the newest audit file by rotation period, skipping empty ones, so a chain continues across restarts
+// and rotations. It returns the empty chain start when no file holds an entry.
+func resumeAuditChain(w *dailyFileWriter, field string) (string, error) {
+	names, err := w.existingFiles()
+	if err != nil {
+		return "", err
+	}
+	for _, name := range names {
+		hash, err := lastAuditHash(filepath.Join(w.directory, name), field)
+		if err != nil || hash != "" {
+			return hash, err
+		}
+	}
+	return "", nil
+}
+
+// lastAuditHash returns the hash of the last entry in the audit file at path, so a restarted
+// process appends to the existing chain instead of starting a new one. A missing or empty file
+// yields the empty chain start.
+func lastAuditHash(path, field string) (string, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return "", err
+	}
+	offset := max(info.Size()-maxAuditLine, 0)
+	tail := make([]byte, info.Size()-offset)
+	if _, err := file.ReadAt(tail, offset); err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	tail = bytes.TrimRight(tail, "\r\n\t ")
+	if len(tail) == 0 {
+		return "", nil
+	}
+	line := tail[bytes.LastIndexByte(tail, '\n')+1:]
+	hash, _, err := splitAuditLine(line, field)
+	if err != nil {
+		return "", fmt.Errorf("%s: last line: %w", path, err)
+	}
+	return hash, nil
+}
+
+// splitAuditLine returns the hash field of an audit line and the payload it was computed over.
+func splitAuditLine(line []byte, field string) (string, []byte, error) {
+	if field == "" {
+		field = "audit_hash"
+	}
+	marker := strconv.AppendQuote(nil, field)
+	marker = append(marker, ':', '"')
+
+	idx := bytes.LastIndex(line, marker)
+	if idx < 0 {
+		return "", nil, fmt.Errorf("missing %s field", field)
+	}
+	rest := line[idx+len(marker):]
+	if len(rest) < 2 || !bytes.HasSuffix(rest, []byte(`"}`)) {
+		return "", nil, fmt.Errorf("malformed %s field", field)
+	}
+	payload := bytes.TrimSuffix(line[:idx], []byte(","))
+	payload = append(append([]byte(nil), payload...), '}')
+	return string(rest[:len(rest)-2]), payload, nil
+}
+
+func chainHash(prev string, payload []byte) string {
+	hasher := sha256.New()
+	hasher.Write([]byte(prev))
+	hasher.Write(payload)
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+// VerifyAuditChain checks that every audit line read from r carries a hash chained to its predecessor.
+// It returns an error naming the first line whose hash does not match.
+func VerifyAuditChain(r io.Reader, field string) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxAuditLine)
+
+	prev := ""
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		got, payload, err := splitAuditLine(line, field)
+		if err != nil {
+			return fmt.Errorf("audit: line %d: %w", lineNo, err)
+		}
+
+		want := chainHash(prev, payload)
+		if got != want {
+			return fmt.Errorf("audit: line %d: hash mismatch", lineNo)
+		}
+		prev = got
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,214 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAuditRoutesToDedicatedWriters(t *testing.T) {
+	var appBuf, auditBuf bytes.Buffer
+	log, err := New(context.Background(), Config{
+		Enabled:     true,
+		ServiceName: "audit-service",
+		Console:     false,
+		Writers:     []io.Writer{&appBuf},
+		Audit: AuditConfig{
+			Enabled: true,
+			Writers: []io.Writer{&auditBuf},
+		},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = log.Close()
+	})
+
+	log.Info().Msg("application")
+	log.Audit().Str("actor", "alice").Msg("login")
+	log.Audit().Str("actor", "bob").Msg("logout")
+
+	if strings.Contains(appBuf.String(), "login") {
+		t.Fatalf("audit entry leaked into application writers: %q", appBuf.String())
+	}
+	if strings.Contains(auditBuf.String(), "application") {
+		t.Fatalf("application entry leaked into audit writers: %q", auditBuf.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(auditBuf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 audit lines, got %d", len(lines))
+	}
+	entry := decodeLogLine(t, []byte(lines[0]))
+	if got := entry["actor"]; got != "alice" {
+		t.Fatalf("unexpected actor: %v", got)
+	}
+	if hash, _ := entry["audit_hash"].(string); len(hash) != 64 {
+		t.Fatalf("expected sha256 audit hash, got %v", entry["audit_hash"])
+	}
+
+	if err := VerifyAuditChain(strings.NewReader(auditBuf.String()), ""); err != nil {
+		t.Fatalf("VerifyAuditChain: %v", err)
+	}
+}
+
+func TestVerifyAuditChainDetectsTampering(t *testing.T) {
+	var buf bytes.Buffer
+	writer := newHashChainWriter(&buf, "chain")
+	for _, line := range []string{`{"n":1}`, `{"n":2}`, `{"n":3}`} {
+		if _, err := writer.Write([]byte(line + "\n")); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+
+	if err := VerifyAuditChain(strings.NewReader(buf.String()), "chain"); err != nil {
+		t.Fatalf("expected intact chain, got %v", err)
+	}
+
+	tampered := strings.Replace(buf.String(), `"n":2`, `"n":9`, 1)
+	err := VerifyAuditChain(strings.NewReader(tampered), "chain")
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("expected mismatch on line 2, got %v", err)
+	}
+
+	removed := strings.Join(append(strings.Split(buf.String(), "\n")[:1], strings.Split(buf.String(), "\n")[2:]...), "\n")
+	if err := VerifyAuditChain(strings.NewReader(removed), "chain"); err == nil {
+		t.Fatal("expected error after removing a line")
+	}
+}
+
+func TestAuditDisabledReturnsNoopEvent(t *testing.T) {
+	log, buf := newBufferedLogger(t, "audit-disabled", "debug")
+	log.Audit().Str("actor", "nobody").Msg("ignored")
+	if buf.Len() != 0 {
+		t.Fatalf("expected no output, got %q", buf.String())
+	}
+}
+
+func TestAuditRequiresWriter(t *testing.T) {
+	_, err := New(context.Background(), Config{
+		Enabled: true,
+		Console: false,
+		Writers: []io.Writer{io.Discard},
+		Audit:   AuditConfig{Enabled: true},
+	})
+	if err == nil {
+		t.Fatal("expected error when audit has no writers")
+	}
+}
+
+func TestAuditChainContinuesAcrossRestarts(t *testing.T) {
+	dir := t.TempDir()
+	for _, actor := range []string{"alice", "bob"} {
+		log, err := New(context.Background(), Config{
+			Enabled: true,
+			Console: false,
+			Writers: []io.Writer{io.Discard},
+			Audit: AuditConfig{
+				Enabled: true,
+				File:    FileConfig{Enabled: true, Directory: dir, Filename: "audit.log"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		log.Audit().Str("actor", actor).Msg("login")
+		if err := log.Close(); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "audit.log"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 2 {
+		t.Fatalf("expected 2 audit lines, got %d", lines)
+	}
+	if err := VerifyAuditChain(bytes.NewReader(data), ""); err != nil {
+		t.Fatalf("VerifyAuditChain across a restart: %v", err)
+	}
+}
+
+func TestAuditChainResumesFromNewestFile(t *testing.T) {
+	dir := t.TempDir()
+	newAudit := func(day time.Time, prevHash string) *Logger {
+		t.Helper()
+		clk := &dedupClock{now: day, timers: make(chan chan time.Time, 4)}
+		log, err := New(context.Background(), Config{
+			Enabled: true,
+			Console: false,
+			Writers: []io.Writer{io.Discard},
+			Clock:   clk,
+			Audit: AuditConfig{
+				Enabled:  true,
+				File:     FileConfig{Enabled: true, Directory: dir, Timezone: "UTC"},
+				PrevHash: prevHash,
+			},
+		})
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		return log
+	}
+
+	// The seed only applies while the directory holds no entry.
+	seed := strings.Repeat("0", 64)
+	first := newAudit(time.Date(2024, 12, 30, 12, 0, 0, 0, time.UTC), seed)
+	first.Audit().Str("actor", "alice").Msg("login")
+	if err := first.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	// An older file must not win over the newest one.
+	if err := os.WriteFile(filepath.Join(dir, "2024-01-05.log"), []byte(`{"audit_hash":"stale"}`+"\n"), 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	// A restart on a later day starts a new file and continues the chain of the previous one.
+	second := newAudit(time.Date(2025, 1, 2, 9, 0, 0, 0, time.UTC), seed)
+	second.Audit().Str("actor", "bob").Msg("login")
+	if err := second.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	lastLine := func(name string) (string, []byte) {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("ReadFile: %v", err)
+		}
+		hash, payload, err := splitAuditLine(bytes.TrimSpace(data), "")
+		if err != nil {
+			t.Fatalf("splitAuditLine: %v", err)
+		}
+		return hash, payload
+	}
+	firstHash, firstPayload := lastLine("2024-12-30.log")
+	if firstHash != chainHash(seed, firstPayload) {
+		t.Fatal("expected the first entry chained to PrevHash")
+	}
+	secondHash, secondPayload := lastLine("2025-01-02.log")
+	if secondHash != chainHash(firstHash, secondPayload) {
+		t.Fatal("expected the new day's file to chain to the newest earlier file")
+	}
+}
+
+func TestAuditRejectsDropMode(t *testing.T) {
+	_, err := New(context.Background(), Config{
+		Enabled: true,
+		Console: false,
+		Writers: []io.Writer{io.Discard},
+		Audit: AuditConfig{
+			Enabled: true,
+			File:    FileConfig{Enabled: true, Directory: t.TempDir(), Mode: FileModeDrop},
+		},
+	})
+	if err == nil || !strings.Contains(err.Error(), "audit file mode") {
+		t.Fatalf("expected drop mode to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/mfahmialkautsar/goo11y/clock"
+	"github.com/rs/zerolog"
+)
+
+const defaultBufferedEntries = 256
+
+// BufferOption configures a request-scoped buffered logger.
+type BufferOption func(*bufferConfig)
+
+type bufferConfig struct {
+	slowThreshold time.Duration
+	maxEntries    int
+}
+
+// WithSlowThreshold keeps the buffered entries of requests that take at least d, even when they succeed.
+func WithSlowThreshold(d time.Duration) BufferOption {
+	return func(c *bufferConfig) {
+		c.slowThreshold = d
+	}
+}
+
+// WithMaxBufferedEntries caps the entries held in memory; the oldest are dropped first.
+func WithMaxBufferedEntries(n int) BufferOption {
+	return func(c *bufferConfig) {
+		if n > 0 {
+			c.maxEntries = n
+		}
+	}
+}
+
+// BufferedLogger holds the debug and info entries of one request in memory and writes them only when
+// Finish reports a failure or a slow request. Warn and above are written immediately.
+type BufferedLogger struct {
+	zerolog.Logger
+	buffer *entryBuffer
+	clock  clock.Clock
+	start  time.Time
+	slow   time.Duration
+}
+
+// BufferedForContext returns a logger for a single request bound to ctx. It logs from debug up
+// regardless of Level, since the buffered entries only surface when the request needs diagnosing.
+// Call Finish when the request ends. Returns a disabled logger if receiver is nil or disabled.
+func (l *Logger) BufferedForContext(ctx context.Context, opts ...BufferOption) *BufferedLogger {
+	cfg := bufferConfig{maxEntries: defaultBufferedEntries}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&cfg)
+		}
+	}
+	if l == nil || l.Logger == nil || l.out == nil {
+		return &BufferedLogger{Logger: zerolog.Nop()}
+	}
+
+	buffer := &entryBuffer{next: l.out, max: cfg.maxEntries, pending: l.spanEvents}
+	child := l.Logger.Output(buffer)
+	if child.GetLevel() > zerolog.DebugLevel {
+		child = child.Level(zerolog.DebugLevel)
+	}
+	if ctx != nil {
+		child = child.With().Ctx(ctx).Logger()
+	}
+	clk := clock.OrReal(l.clock)
+	return &BufferedLogger{
+		Logger: child,
+		buffer: buffer,
+		clock:  clk,
+		start:  clk.Now(),
+		slow:   cfg.slowThreshold,
+	}
+}
+
+// BufferedForContext returns a request-scoped buffered logger derived from the global logger.
+func BufferedForContext(ctx context.Context, opts ...BufferOption) *BufferedLogger {
+	return Global().BufferedForContext(ctx, opts...)
+}
+
+// Finish ends the request. The buffered entries are written when err is non-nil or the request
+// exceeded the slow threshold, and discarded otherwise; entries logged afterwards are written directly.
+// It reports whether the buffer was written and is a no-op after the first call.
+func (b *BufferedLogger) Finish(err error) bool {
+	if b == nil || b.buffer == nil {
+		return false
+	}
+	keep := err != nil || (b.slow > 0 && b.clock.Now().Sub(b.start) >= b.slow)
+	return b.buffer.release(keep)
+}
+
+// entryBuffer holds entries below warn until release; afterwards it writes everything through.
+// Entries it drops release their deferred span events from pending.
+type entryBuffer struct {
+	next    io.Writer
+	max     int
+	pending *pendingSpanEvents
+
+	mu       sync.Mutex
+	entries  [][]byte
+	released bool
+}
+
+func (b *entryBuffer) Write(p []byte) (int, error) {
+	return b.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (b *entryBuffer) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	b.mu.Lock()
+	if b.released || level >= zerolog.WarnLevel {
+		b.mu.Unlock()
+		return b.next.Write(p)
+	}
+	if len(b.entries) >= b.max {
+		b.pending.discard(b.entries[0])
+		copy(b.entries, b.entries[1:])
+		b.entries = b.entries[:len(b.entries)-1]
+	}
+	b.entries = append(b.entries, append([]byte(nil), p...))
+	b.mu.Unlock()
+	return len(p), nil
+}
+
+func (b *entryBuffer) release(keep bool) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.released {
+		return false
+	}
+	b.released = true
+	entries := b.entries
+	b.entries = nil
+	if !keep {
+		for _, entry := range entries {
+			b.pending.discard(entry)
+		}
+		return false
+	}
+	for _, entry := range entries {
+		_, _ = b.next.Write(entry)
+	}
+	return true
+}
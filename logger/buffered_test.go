@@ -0,0 +1,153 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newBufferTestLogger(t *testing.T, buf *bytes.Buffer) *Logger {
+	t.Helper()
+	log, err := New(context.Background(), Config{
+		Enabled: true,
+		Level:   "info",
+		Console: false,
+		Writers: []io.Writer{buf},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return log
+}
+
+func TestBufferedLoggerDiscardsOnSuccess(t *testing.T) {
+	var buf bytes.Buffer
+	log := newBufferTestLogger(t, &buf)
+
+	req := log.BufferedForContext(context.Background())
+	req.Debug().Msg("step one")
+	req.Info().Msg("step two")
+	req.Warn().Msg("slow dependency")
+
+	if !strings.Contains(buf.String(), "slow dependency") {
+		t.Fatalf("warn entries should be written immediately, got %s", buf.String())
+	}
+	if strings.Contains(buf.String(), "step one") {
+		t.Fatalf("debug entries should be held until Finish, got %s", buf.String())
+	}
+	if req.Finish(nil) {
+		t.Fatal("successful request should not flush its buffer")
+	}
+	if strings.Contains(buf.String(), "step") {
+		t.Fatalf("buffered entries should be discarded, got %s", buf.String())
+	}
+}
+
+func TestBufferedLoggerFlushesOnError(t *testing.T) {
+	var buf bytes.Buffer
+	log := newBufferTestLogger(t, &buf)
+
+	req := log.BufferedForContext(context.Background(), WithMaxBufferedEntries(2))
+	req.Debug().Msg("first")
+	req.Debug().Msg("second")
+	req.Info().Msg("third")
+
+	if !req.Finish(errors.New("request failed")) {
+		t.Fatal("expected buffer to be written on error")
+	}
+	out := buf.String()
+	if strings.Contains(out, "first") {
+		t.Fatalf("oldest entry should be dropped past the cap, got %s", out)
+	}
+	if strings.Index(out, "second") < 0 || strings.Index(out, "second") > strings.Index(out, "third") {
+		t.Fatalf("expected buffered entries in order, got %s", out)
+	}
+
+	req.Info().Msg("after finish")
+	if !strings.Contains(buf.String(), "after finish") {
+		t.Fatal("entries after Finish should be written directly")
+	}
+	if req.Finish(errors.New("again")) {
+		t.Fatal("Finish should only act once")
+	}
+}
+
+func TestBufferedLoggerFlushesSlowRequests(t *testing.T) {
+	var buf bytes.Buffer
+	clk := &dedupClock{now: time.Unix(1_700_000_000, 0), timers: make(chan chan time.Time, 4)}
+	log, err := New(context.Background(), Config{
+		Enabled: true,
+		Level:   "info",
+		Console: false,
+		Writers: []io.Writer{&buf},
+		Clock:   clk,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	fast := log.BufferedForContext(context.Background(), WithSlowThreshold(time.Second))
+	fast.Debug().Msg("fast path")
+	if fast.Finish(nil) {
+		t.Fatalf("expected a request within the threshold to discard its entries, got %s", buf.String())
+	}
+
+	req := log.BufferedForContext(context.Background(), WithSlowThreshold(time.Second))
+	req.Debug().Msg("slow path")
+	clk.advance(2 * time.Second)
+	if !req.Finish(nil) || !strings.Contains(buf.String(), "slow path") {
+		t.Fatalf("expected slow request to keep its entries, got %s", buf.String())
+	}
+	if !strings.Contains(buf.String(), `"time":"2023-11-14T22:13:20Z"`) {
+		t.Fatalf("expected entries stamped by the logger clock, got %s", buf.String())
+	}
+}
+
+func TestBufferedLoggerDisabled(t *testing.T) {
+	var log *Logger
+	req := log.BufferedForContext(context.Background())
+	req.Info().Msg("ignored")
+	if req.Finish(errors.New("boom")) {
+		t.Fatal("disabled buffered logger should not flush")
+	}
+}
+
+func TestBufferedLoggerReleasesSpanEventsOfDiscardedEntries(t *testing.T) {
+	log, err := New(context.Background(), Config{
+		Enabled:    true,
+		Level:      "info",
+		Console:    false,
+		Writers:    []io.Writer{io.Discard},
+		SpanEvents: SpanEventConfig{Levels: []string{"debug", "info"}, Attributes: []string{"step"}},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = log.Close() })
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	ctx, span := tp.Tracer("buffered").Start(context.Background(), "op")
+	req := log.BufferedForContext(ctx, WithMaxBufferedEntries(1))
+	req.Debug().Int("step", 1).Msg("dropped on overflow")
+	req.Info().Int("step", 2).Msg("discarded on finish")
+	req.Finish(nil)
+	if got := log.spanEvents.len(); got != 0 {
+		t.Fatalf("expected discarded entries to release their span events, got %d pending", got)
+	}
+
+	log.Info().Ctx(ctx).Int("step", 3).Msg("written")
+	span.End()
+	events := recorder.Ended()[0].Events()
+	if len(events) != 1 || events[0].Attributes[0].Value.AsString() != "written" {
+		t.Fatalf("expected only the written entry on the span, got %v", events)
+	}
+}
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"fmt"
 	"io"
 	"strings"
 	"time"
@@ -8,31 +9,143 @@ import (
 	"github.com/creasty/defaults"
 	"github.com/go-playground/validator/v10"
 	"github.com/mfahmialkautsar/goo11y/auth"
+	"github.com/mfahmialkautsar/goo11y/clock"
 	"github.com/mfahmialkautsar/goo11y/internal/fileutil"
+	"github.com/mfahmialkautsar/goo11y/internal/otlputil"
 )
 
 const defaultConsoleTimeFormat = time.RFC3339Nano
 
+const (
+	FileModeBlock = "block"
+	FileModeDrop  = "drop"
+)
+
+const (
+	FileRotationDaily  = "daily"
+	FileRotationHourly = "hourly"
+)
+
+// Fsync policies of the file writer.
+const (
+	FileSyncNever      = "never"
+	FileSyncInterval   = "interval"
+	FileSyncEveryWrite = "every-write"
+)
+
 // Config drives logger construction without importing the logging implementation details.
+// Format selects the encoding applied to Writers and, unless File.Format overrides it, the file writer.
+// The OTLP writer always receives JSON.
 type Config struct {
 	Enabled     bool
 	Level       string `default:"info"`
 	Environment string `default:"development"`
 	ServiceName string `default:"unknown-service"`
 	Console     bool   `default:"true"`
+	Format      string `default:"json" validate:"omitempty,oneof=json logfmt console"`
 	Writers     []io.Writer
 	OTLP        OTLPConfig
 	File        FileConfig
 	Fields      FieldConfig
+	Audit       AuditConfig
 	UseGlobal   bool
+	// SemconvFields renames the emitted keys to the OTel log data model (severity_text, body,
+	// trace_id, span_id, exception.message, exception.stacktrace). Trace and span names take
+	// precedence over Fields.
+	SemconvFields bool
+	// ExceptionEvents controls the standard "exception" span event recorded for error entries logged
+	// with Ctx and Err: "off" keeps only the log.error event, "append" records both, and "replace"
+	// records the exception event instead of log.error. Entries logged through Logger.Err also set
+	// exception.type to the Go type of the error.
+	ExceptionEvents string `default:"off" validate:"omitempty,oneof=off append replace"`
+	SpanEvents      SpanEventConfig
+	// ServiceInstanceID and RunID, when set, are added to every entry and to the OTLP resource
+	// as service.instance.id and process.run_id.
+	ServiceInstanceID string
+	RunID             string
+	// ResourceSchemaConflict resolves a schema URL conflict when building the OTLP resource; see
+	// goo11y.ResourceConfig.SchemaConflict.
+	ResourceSchemaConflict string `validate:"omitempty,oneof=error prefer-latest strip"`
+	// TraceDebug emits entries below Level, down to debug, only when they are logged with Ctx and the
+	// span is sampled or the baggage carries debug=true. Such entries are still built before being
+	// discarded, so this costs more than a plain level filter.
+	TraceDebug bool
+	// TraceURLTemplate, when set, adds a trace_url field to warn and error entries logged with a span
+	// context, so a log line links straight to its trace. {trace_id} and {span_id} expand to the
+	// span's IDs, and {from} and {to} to Unix milliseconds an hour before and after the entry, as in
+	// "https://grafana.example.com/explore?traceId={trace_id}&from={from}&to={to}".
+	TraceURLTemplate string
+
+	// ErrorChain adds an error_chain array to entries logged with Logger.Err, describing each error
+	// of the wrapped chain with its message, type, and the stack frames it contributed.
+	ErrorChain bool
+	// Delivery chooses between each sink's own buffering, strictly ordered synchronous writes, and
+	// a queue per sink; see DeliveryConfig.
+	Delivery DeliveryConfig
+	// Hooks see and may rewrite every entry before it is written, and learn each sink's result.
+	Hooks []Hook
+	// Stack limits and filters the Go frames recorded for errors; see StackConfig.
+	Stack StackConfig
+	// Dedup drops repeats of the same entry within a window and writes one summary with repeat_count.
+	Dedup DedupConfig
+	// Recent keeps the last entries in memory for RecentHandler; see RecentConfig.
+	Recent RecentConfig
+	// Crash writes a crash report before a Fatal entry or a panic reaching RecoverCrash ends the
+	// process, captures the runtime's output for unrecovered panics, and re-logs both on the next
+	// start; see CrashConfig.
+	Crash CrashConfig
+	// MessageMetrics counts entries per level in a log_messages_total counter.
+	MessageMetrics MessageMetricsConfig
+	// ConsoleOptions tunes colors, field order, and excluded fields of the console sink.
+	ConsoleOptions ConsoleConfig
+	// Clock drives file rollover, OTLP record timestamps of entries without a time field, and spool
+	// retry scheduling. Nil uses the real clock; tests can pass goo11ytest.NewFakeClock.
+	Clock clock.Clock
+	// FieldNames renames the core keys of every entry, for example to ECS or Datadog naming.
+	// Names left empty keep the name Fields, SemconvFields, or Profile gives them. Field names,
+	// like the stack format, belong to the logger: loggers with different naming can coexist.
+	FieldNames FieldNamesConfig
+	// Profile shapes entries for a log backend's schema. "ecs" emits Elastic Common Schema keys
+	// (@timestamp, log.level, trace.id, span.id, service.name, service.environment, error.message,
+	// error.stack_trace as text) plus ecs.version. "datadog" adds dd.trace_id and dd.span_id in the
+	// decimal form Datadog correlates on, and writes the service and environment as dd.service and
+	// dd.env. FieldNames still overrides individual keys.
+	Profile string `validate:"omitempty,oneof=ecs datadog"`
+}
+
+// FieldNamesConfig overrides the keys of the core entry fields. The OTLP writer recognizes the
+// overridden keys when mapping entries to log records.
+type FieldNamesConfig struct {
+	Time        string
+	Level       string
+	Message     string
+	TraceID     string
+	SpanID      string
+	ServiceName string
+}
+
+// SpanEventConfig controls how entries logged with a span context are mirrored onto that span.
+// Levels lists the levels that create span events (warn and error by default; error also covers fatal
+// and panic). Error entries mark the span as failed unless DisableErrorStatus is set. Attributes names
+// log fields copied onto each event, and MaxPerSpan caps the logger events recorded on one span (0 is
+// unlimited).
+type SpanEventConfig struct {
+	Disabled           bool
+	Levels             []string `validate:"dive,oneof=trace debug info warn error fatal panic"`
+	DisableErrorStatus bool
+	Attributes         []string
+	MaxPerSpan         int `validate:"gte=0"`
 }
 
 // FieldConfig allows customization of internal OTel-related field names.
-// Standard Zerolog fields (level, message, time, caller, error, stack)
-// should be configured via zerolog globals directly.
+// The standard zerolog fields (time, level, message, error, stack) are renamed through FieldNames,
+// SemconvFields, or Profile.
 type FieldConfig struct {
-	TraceID               string `default:"trace_id"`
-	SpanID                string `default:"span_id"`
+	TraceID string `default:"trace_id"`
+	SpanID  string `default:"span_id"`
+	// Sampled holds whether the span's trace is sampled, so a trace ID that will never reach the
+	// tracing backend can be told apart from one worth looking up.
+	Sampled               string `default:"sampled"`
 	ServiceName           string `default:"service_name"`
 	DeploymentEnvironment string `default:"deployment_environment_name"`
 	Internal              InternalFieldConfig
@@ -49,28 +162,121 @@ type InternalFieldConfig struct {
 // Endpoint accepts a base URL (host[:port] with optional path). When a scheme is provided,
 // TLS is inferred automatically (http => insecure, https => secure). Without a
 // scheme, the Insecure flag determines whether TLS is disabled.
+// Endpoint, Insecure, Headers, Timeout, Protocol, and Certificate fall back to the standard
+// OTEL_EXPORTER_OTLP_LOGS_* and OTEL_EXPORTER_OTLP_* variables when left unset.
 type OTLPConfig struct {
-	Enabled     bool
-	Endpoint    string `validate:"required_if=Enabled true"`
-	Insecure    bool
-	Headers     map[string]string
-	Timeout     time.Duration `default:"5s" validate:"omitempty,gt=0"`
-	Protocol    string        `default:"http" validate:"oneof=http grpc"`
-	Credentials auth.Credentials
-	Async       bool `default:"true"`
-	UseSpool    bool
-	QueueDir    string
+	Enabled  bool
+	Endpoint string `validate:"required_if=Enabled true"`
+	// EndpointIsURL sends HTTP exports to Endpoint's path as given instead of appending /v1/logs.
+	// It is set when Endpoint comes from OTEL_EXPORTER_OTLP_LOGS_ENDPOINT, which holds the full URL.
+	EndpointIsURL bool
+	Insecure      bool
+	Headers       map[string]string
+	Timeout       time.Duration `default:"5s" validate:"omitempty,gt=0"`
+	Protocol      string        `default:"http" validate:"oneof=http grpc"`
+	Credentials   auth.Credentials
+	Async         bool `default:"true"`
+	UseSpool      bool
+	QueueDir      string
+	Secondary     SecondaryConfig
+	// SyncExport exports every record as it is written instead of batching, overriding Async.
+	SyncExport bool
+	// SpoolMaxAge drops spooled requests older than this regardless of their attempts; zero keeps them
+	// until the retry limit. OnSpoolDrop receives every request the spool discards, with the reason
+	// (corrupt, expired, retries_exhausted, overflow, or rejected), so it can be archived or counted.
+	SpoolMaxAge time.Duration `validate:"gte=0"`
+	OnSpoolDrop func(token string, payload []byte, reason string)
+	// StatusPolicy overrides what the spool does with a request the collector refuses: "retry",
+	// "drop", or "quarantine" (keep it in the queue's quarantine directory). It is keyed by HTTP
+	// status, or by gRPC status code with the grpc protocol. Unlisted HTTP statuses retry 408, 429,
+	// and 5xx and drop other 4xx; unlisted gRPC codes retry those OTLP marks retryable and drop the
+	// rest.
+	StatusPolicy map[int]string `validate:"dive,oneof=retry drop quarantine"`
+	// MaxRequestsPerSecond and MaxBytesPerSecond pace the spool's deliveries, with jitter, so a
+	// backlog drained after an outage does not trip the collector's rate limits and get throttled
+	// back into the spool. Zero leaves a limit off.
+	MaxRequestsPerSecond float64 `validate:"gte=0"`
+	MaxBytesPerSecond    int     `validate:"gte=0"`
+	// Certificate is a PEM file of CA certificates trusted for the primary and secondary collectors
+	// instead of the system roots.
+	Certificate string
+	// MaxPayloadBytes splits export requests whose encoded size exceeds it into smaller requests
+	// before they are sent or spooled, so a collector's request size limit does not reject whole
+	// batches. Without the spool, a retry after one part fails resends the parts already accepted,
+	// so those records may arrive twice. Zero sends batches as they are.
+	MaxPayloadBytes int `validate:"gte=0"`
+	// AsyncInit builds the exporter, its connection, and its spool in the background, so New does not
+	// wait for the collector. Records queue in the batch processor until the exporter is ready, and
+	// build errors are logged as export failures instead of failing New. With SyncExport, the first
+	// writes wait for the exporter.
+	AsyncInit bool
+}
+
+// SecondaryConfig names a fail-over collector used by the spool when the primary endpoint keeps failing.
+// Exports move to Endpoint after Threshold consecutive failures and move back once a probe of the primary,
+// sent at most every ProbeInterval, succeeds. It requires UseSpool so requests in flight during a switch
+// stay on disk. Primary headers are not forwarded; Credentials authenticate against the secondary instead.
+type SecondaryConfig struct {
+	Endpoint      string
+	Insecure      bool
+	Credentials   auth.Credentials
+	Threshold     int           `default:"3" validate:"gte=0"`
+	ProbeInterval time.Duration `default:"30s" validate:"gte=0"`
 }
 
 // FileConfig controls optional file-based logging.
+// Entries are handed to a background writer through a bounded queue holding up to Buffer entries.
+// When the queue is full, Mode "block" makes the logging call wait for room (suited to audit trails),
+// while Mode "drop" discards the entry and counts it in Logger.Stats.
+// Format overrides Config.Format for this file; it is ignored for the audit channel, which stays JSON.
 type FileConfig struct {
 	Enabled   bool
 	Directory string `validate:"required_if=Enabled true"`
 	Buffer    int    `default:"1024" validate:"omitempty,gt=0"`
+	Mode      string `default:"block" validate:"omitempty,oneof=block drop"`
+	Format    string `validate:"omitempty,oneof=json logfmt console"`
+	// Rotation starts a new file every day or every hour, at boundaries in Timezone.
+	Rotation string `default:"daily" validate:"omitempty,oneof=daily hourly"`
+	// Timezone is an IANA name such as "UTC" or "Europe/Berlin" used for rollover boundaries and
+	// file names. Empty uses the local timezone.
+	Timezone string
+	// Filename names each file from the start of its period: %Y, %m, %d, and %H expand to the year,
+	// month, day, and hour, {service} to Config.ServiceName, and %% to a literal percent sign.
+	// Empty uses "%Y-%m-%d.log", or "%Y-%m-%d-%H.log" with hourly rotation.
+	Filename string `validate:"omitempty,excludesall=/\\"`
+	// BatchSize joins up to this many queued entries into one write; the writer never waits for a
+	// batch to fill, it takes what is queued. Zero or one writes entries one by one.
+	BatchSize int `validate:"gte=0"`
+	// Sync chooses when written entries are fsynced: "never" leaves it to the OS, "interval" every
+	// SyncInterval (1s when zero), and "every-write" after each write, trading throughput for
+	// durability across power loss or a kernel crash.
+	Sync         string        `default:"never" validate:"omitempty,oneof=never interval every-write"`
+	SyncInterval time.Duration `validate:"gte=0"`
+}
+
+// AuditConfig routes audit events to a dedicated writer set, segregated from application logs.
+// Every audit entry carries a hash chained to the previous entry for tamper evidence; after a
+// restart the chain continues from the last entry of the newest audit file, or from PrevHash.
+// File.Mode must not be drop.
+type AuditConfig struct {
+	Enabled   bool
+	Writers   []io.Writer
+	File      FileConfig
+	OTLP      OTLPConfig
+	HashField string `default:"audit_hash"`
+	// PrevHash seeds the chain with the hash of the last entry written before this process started.
+	// With File enabled the chain resumes from the newest audit file instead, and PrevHash only
+	// applies while the directory holds no entry. Writers and OTLP sinks cannot be read back, so
+	// without File set it to the last hash the sink stored, or the chain restarts on every start.
+	PrevHash string
 }
 
 func (c Config) withDefaults() Config {
+	c.OTLP.applyEnv(otlputil.SignalLogs)
 	_ = defaults.Set(&c)
+	if c.SpanEvents.Levels == nil {
+		c.SpanEvents.Levels = []string{"warn", "error"}
+	}
 	if c.File.Enabled && c.File.Directory == "" {
 		c.File.Directory = fileutil.DefaultQueueDir("file-logs")
 	}
@@ -80,6 +286,12 @@ func (c Config) withDefaults() Config {
 	if c.OTLP.QueueDir == "" {
 		c.OTLP.QueueDir = fileutil.DefaultQueueDir("logs")
 	}
+	if c.Audit.File.Enabled && c.Audit.File.Directory == "" {
+		c.Audit.File.Directory = fileutil.DefaultQueueDir("file-audit-logs")
+	}
+	if c.Audit.OTLP.QueueDir == "" {
+		c.Audit.OTLP.QueueDir = fileutil.DefaultQueueDir("audit-logs")
+	}
 	return c
 }
 
@@ -91,7 +303,13 @@ func (c Config) ApplyDefaults() Config {
 // Validate ensures the logger configuration is complete when logging is enabled.
 func (c Config) Validate() error {
 	validate := validator.New(validator.WithRequiredStructEnabled())
-	return validate.Struct(c)
+	if err := validate.Struct(c); err != nil {
+		return err
+	}
+	if c.Audit.Enabled && c.Audit.File.Enabled && c.Audit.File.Mode == FileModeDrop {
+		return fmt.Errorf("audit file mode %q would silently lose audit entries", FileModeDrop)
+	}
+	return nil
 }
 
 func (c OTLPConfig) headerMap() map[string]string {
@@ -0,0 +1,137 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// Console color modes accepted by ConsoleConfig.Color.
+const (
+	ConsoleColorAuto   = "auto"
+	ConsoleColorAlways = "always"
+	ConsoleColorNever  = "never"
+)
+
+// ConsoleConfig tunes the console sink enabled by Config.Console. Color "auto" colors output only when
+// stdout is a terminal and NO_COLOR is unset. LevelColors overrides the ANSI color code of a level, for
+// example {"info": 36}. FieldOrder lists keys printed first, in that order, ahead of the remaining
+// fields; ExcludeFields hides keys. Stack traces are printed as indented lines below the entry.
+type ConsoleConfig struct {
+	Color         string         `default:"auto" validate:"omitempty,oneof=auto always never"`
+	LevelColors   map[string]int `validate:"dive,keys,oneof=trace debug info warn error fatal panic,endkeys,gte=0"`
+	FieldOrder    []string
+	ExcludeFields []string
+}
+
+// newConsoleWriter builds the zerolog console writer for out according to cfg, for entries written
+// under the keys of naming.
+func newConsoleWriter(out io.Writer, cfg ConsoleConfig, noColor bool, naming *fieldNaming) zerolog.ConsoleWriter {
+	writer := zerolog.ConsoleWriter{
+		Out:         out,
+		NoColor:     noColor,
+		TimeFormat:  defaultConsoleTimeFormat,
+		FieldsOrder: cfg.FieldOrder,
+		FormatExtra: func(evt map[string]any, buf *bytes.Buffer) error {
+			return formatConsoleStack(evt[naming.to.stack], buf)
+		},
+	}
+	writer.FieldsExclude = append(append([]string(nil), cfg.ExcludeFields...), naming.to.stack)
+	if len(naming.renames) > 0 {
+		// The console writer finds the level, message, and error under zerolog's names.
+		writer.FormatPrepare = func(evt map[string]any) error {
+			for _, pair := range [][2]string{
+				{naming.to.time, naming.from.time},
+				{naming.to.level, naming.from.level},
+				{naming.to.message, naming.from.message},
+				{naming.to.error, naming.from.error},
+			} {
+				if value, ok := evt[pair[0]]; ok && pair[0] != pair[1] {
+					delete(evt, pair[0])
+					evt[pair[1]] = value
+				}
+			}
+			return nil
+		}
+	}
+	writer.FormatCaller = absoluteConsoleCallerFormatter(noColor)
+	if len(cfg.LevelColors) > 0 && !noColor {
+		writer.FormatLevel = consoleLevelFormatter(cfg.LevelColors)
+	}
+	return writer
+}
+
+// consoleNoColor resolves a ConsoleConfig.Color mode for the file behind out.
+func consoleNoColor(mode string, out *os.File) bool {
+	switch mode {
+	case ConsoleColorAlways:
+		return false
+	case ConsoleColorNever:
+		return true
+	}
+	if _, set := os.LookupEnv("NO_COLOR"); set {
+		return true
+	}
+	info, err := out.Stat()
+	return err != nil || info.Mode()&os.ModeCharDevice == 0
+}
+
+func consoleLevelFormatter(colors map[string]int) zerolog.Formatter {
+	return func(i any) string {
+		name, _ := i.(string)
+		level, err := zerolog.ParseLevel(name)
+		if err != nil {
+			return strings.ToUpper(name)
+		}
+		text, ok := zerolog.FormattedLevels[level]
+		if !ok {
+			text = strings.ToUpper(name)
+		}
+		code, ok := colors[level.String()]
+		if !ok {
+			code = zerolog.LevelColors[level]
+		}
+		if code == 0 {
+			return text
+		}
+		return fmt.Sprintf("\x1b[%dm%s\x1b[0m", code, text)
+	}
+}
+
+// formatConsoleStack renders the stack field below the entry, one frame per line, instead of as JSON.
+func formatConsoleStack(value any, buf *bytes.Buffer) error {
+	var b strings.Builder
+	switch stack := value.(type) {
+	case []any:
+		for _, raw := range stack {
+			frame, ok := raw.(map[string]any)
+			if !ok {
+				continue
+			}
+			function, _ := frame["function"].(string)
+			location, _ := frame["location"].(string)
+			writeFrameText(&b, function, location)
+		}
+	case string:
+		b.WriteString(stack)
+	default:
+		return nil
+	}
+	text := strings.TrimRight(b.String(), "\n")
+	if text == "" {
+		return nil
+	}
+	buf.WriteByte('\n')
+	for idx, line := range strings.Split(text, "\n") {
+		if idx > 0 {
+			buf.WriteByte('\n')
+		}
+		buf.WriteString("    ")
+		buf.WriteString(line)
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestConsoleWriterOrdersAndExcludesFields(t *testing.T) {
+	var buf bytes.Buffer
+	writer := newConsoleWriter(&buf, ConsoleConfig{
+		FieldOrder:    []string{"zeta", "alpha"},
+		ExcludeFields: []string{"secret"},
+	}, true, defaultNaming)
+
+	line := `{"level":"info","message":"hello","alpha":"a","zeta":"z","secret":"s","beta":"b"}` + "\n"
+	if _, err := writer.Write([]byte(line)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	out := buf.String()
+	if strings.Contains(out, "secret") {
+		t.Fatalf("expected excluded field to be hidden, got %q", out)
+	}
+	zeta, alpha, beta := strings.Index(out, "zeta="), strings.Index(out, "alpha="), strings.Index(out, "beta=")
+	if zeta < 0 || alpha < 0 || beta < 0 || zeta > alpha || alpha > beta {
+		t.Fatalf("expected zeta, alpha, then beta, got %q", out)
+	}
+}
+
+func TestConsoleWriterRendersStackOnSeparateLines(t *testing.T) {
+	var buf bytes.Buffer
+	writer := newConsoleWriter(&buf, ConsoleConfig{}, true, defaultNaming)
+
+	line := `{"level":"error","message":"failed","stack":[{"function":"main.run","location":"/app/main.go:10"},{"location":"/app/main.go:3"}]}` + "\n"
+	if _, err := writer.Write([]byte(line)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected entry plus three stack lines, got %q", buf.String())
+	}
+	if strings.Contains(lines[0], "stack") {
+		t.Fatalf("stack should not be rendered as a field: %q", lines[0])
+	}
+	if lines[1] != "    main.run" || lines[2] != "    \t/app/main.go:10" || lines[3] != "    \t/app/main.go:3" {
+		t.Fatalf("unexpected stack rendering %q", lines[1:])
+	}
+}
+
+func TestConsoleLevelColors(t *testing.T) {
+	var buf bytes.Buffer
+	writer := newConsoleWriter(&buf, ConsoleConfig{LevelColors: map[string]int{"info": 36}}, false, defaultNaming)
+	if _, err := writer.Write([]byte(`{"level":"info","message":"hi"}` + "\n")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if !strings.Contains(buf.String(), "\x1b[36mINF\x1b[0m") {
+		t.Fatalf("expected themed level, got %q", buf.String())
+	}
+}
+
+func TestConsoleNoColorModes(t *testing.T) {
+	file, err := os.CreateTemp(t.TempDir(), "console")
+	if err != nil {
+		t.Fatalf("CreateTemp: %v", err)
+	}
+	t.Cleanup(func() { _ = file.Close() })
+
+	if consoleNoColor(ConsoleColorAlways, file) {
+		t.Fatal("always should force color")
+	}
+	if !consoleNoColor(ConsoleColorNever, file) {
+		t.Fatal("never should disable color")
+	}
+	if !consoleNoColor(ConsoleColorAuto, file) {
+		t.Fatal("auto should disable color for a regular file")
+	}
+}
+
+func TestConsoleConfigValidation(t *testing.T) {
+	cfg := Config{Enabled: true, ConsoleOptions: ConsoleConfig{Color: "sometimes"}}.ApplyDefaults()
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected invalid color mode to fail validation")
+	}
+	cfg = Config{Enabled: true, ConsoleOptions: ConsoleConfig{LevelColors: map[string]int{"loud": 31}}}.ApplyDefaults()
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected unknown level to fail validation")
+	}
+	cfg = Config{Enabled: true}.ApplyDefaults()
+	if cfg.ConsoleOptions.Color != ConsoleColorAuto {
+		t.Fatalf("expected auto color by default, got %q", cfg.ConsoleOptions.Color)
+	}
+}
+
+func TestConsoleWriterReadsRenamedKeys(t *testing.T) {
+	var buf bytes.Buffer
+	writer := newConsoleWriter(&buf, ConsoleConfig{}, true, newFieldNaming(Config{SemconvFields: true}))
+
+	line := `{"severity_text":"error","body":"failed","exception.message":"boom","exception.stacktrace":"main.run\n\t/app/main.go:10\n"}` + "\n"
+	if _, err := writer.Write([]byte(line)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if !strings.Contains(lines[0], "ERR") || !strings.Contains(lines[0], "failed") || strings.Contains(lines[0], "severity_text") {
+		t.Fatalf("expected the renamed level and message in place, got %q", lines[0])
+	}
+	if len(lines) != 3 || lines[1] != "    main.run" {
+		t.Fatalf("expected the text stacktrace below the entry, got %q", buf.String())
+	}
+}
@@ -28,11 +28,11 @@ func TestLoggerIndependenceWithoutContext(t *testing.T) {
 	log.Info().Msg("independent")
 
 	entry := decodeLogLine(t, standalone.Bytes())
-	if _, ok := entry[traceIDField]; ok {
-		t.Fatalf("unexpected trace_id without context: %v", entry[traceIDField])
+	if _, ok := entry[defaultNaming.traceID]; ok {
+		t.Fatalf("unexpected trace_id without context: %v", entry[defaultNaming.traceID])
 	}
-	if _, ok := entry[spanIDField]; ok {
-		t.Fatalf("unexpected span_id without context: %v", entry[spanIDField])
+	if _, ok := entry[defaultNaming.spanID]; ok {
+		t.Fatalf("unexpected span_id without context: %v", entry[defaultNaming.spanID])
 	}
 
 	var nilBuffer bytes.Buffer
@@ -47,10 +47,10 @@ func TestLoggerIndependenceWithoutContext(t *testing.T) {
 
 	withCtx.Info().Msg("nil-context")
 	ctxEntry := decodeLogLine(t, nilBuffer.Bytes())
-	if _, ok := ctxEntry[traceIDField]; ok {
-		t.Fatalf("unexpected trace_id with nil context: %v", ctxEntry[traceIDField])
+	if _, ok := ctxEntry[defaultNaming.traceID]; ok {
+		t.Fatalf("unexpected trace_id with nil context: %v", ctxEntry[defaultNaming.traceID])
 	}
-	if _, ok := ctxEntry[spanIDField]; ok {
-		t.Fatalf("unexpected span_id with nil context: %v", ctxEntry[spanIDField])
+	if _, ok := ctxEntry[defaultNaming.spanID]; ok {
+		t.Fatalf("unexpected span_id with nil context: %v", ctxEntry[defaultNaming.spanID])
 	}
 }
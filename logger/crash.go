@@ -0,0 +1,316 @@
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mfahmialkautsar/goo11y/clock"
+	"github.com/rs/zerolog"
+)
+
+const (
+	crashFilePrefix = "crash-"
+	crashFileExt    = ".json"
+	// runtimeCrashPrefix and runtimeCrashExt name the files the runtime writes unrecovered panics
+	// and fatal errors to.
+	runtimeCrashPrefix = "runtime-"
+	runtimeCrashExt    = ".log"
+	// maxGoroutineDump bounds the goroutine dump kept in a crash report.
+	maxGoroutineDump   = 4 << 20
+	crashReportMessage = "crash report from previous run"
+)
+
+// CrashConfig writes a crash report to Directory when the process is about to die: on a Fatal
+// entry, and on a panic reaching Logger.RecoverCrash. Recovered panics, such as those net/http or
+// goo11y.Recover catch, write none. The report holds the reason, a dump of
+// every goroutine, the build and service identity, and the entries kept by Config.Recent. Panics
+// nothing recovers and fatal runtime errors, such as concurrent map writes, are captured too:
+// New points debug.SetCrashOutput at a file in Directory, so the runtime's own crash output lands
+// there. On the next New with the same Directory, each report is logged as one error entry, so it
+// travels through the OTLP writer and its spool like any other entry, and the file is removed.
+// The runtime has a single crash output, so the last logger built with a Directory owns it, and a
+// Directory should belong to one process at a time. Empty Directory turns crash reports off.
+type CrashConfig struct {
+	Directory string
+}
+
+// CrashReport is the content of a crash file.
+type CrashReport struct {
+	Time              string            `json:"time"`
+	Level             string            `json:"level"`
+	Reason            string            `json:"reason"`
+	ServiceName       string            `json:"service_name,omitempty"`
+	Environment       string            `json:"environment,omitempty"`
+	ServiceInstanceID string            `json:"service_instance_id,omitempty"`
+	RunID             string            `json:"run_id,omitempty"`
+	PID               int               `json:"pid"`
+	Build             map[string]string `json:"build,omitempty"`
+	Goroutines        string            `json:"goroutines"`
+	Recent            []json.RawMessage `json:"recent,omitempty"`
+}
+
+// crashReporter writes crash reports for one logger.
+type crashReporter struct {
+	dir      string
+	identity Config
+	clock    clock.Clock
+	recent   *recentBuffer
+}
+
+func newCrashReporter(cfg Config, recent *recentBuffer) *crashReporter {
+	return &crashReporter{dir: cfg.Crash.Directory, identity: cfg, clock: clock.OrReal(cfg.Clock), recent: recent}
+}
+
+// Run writes a report for Fatal entries, which end the process right after they are written. Panic
+// entries are left to RecoverCrash, since callers such as net/http recover them.
+func (c *crashReporter) Run(_ *zerolog.Event, level zerolog.Level, msg string) {
+	if level == zerolog.FatalLevel {
+		_, _ = c.write(level.String(), msg)
+	}
+}
+
+// write stores a report and returns its path. The file is written under a temporary name and
+// renamed, so the next start never reads a half-written report.
+func (c *crashReporter) write(level, reason string) (string, error) {
+	now := c.clock.Now()
+	report := CrashReport{
+		Time:              now.UTC().Format(defaultConsoleTimeFormat),
+		Level:             level,
+		Reason:            reason,
+		ServiceName:       c.identity.ServiceName,
+		Environment:       c.identity.Environment,
+		ServiceInstanceID: c.identity.ServiceInstanceID,
+		RunID:             c.identity.RunID,
+		PID:               os.Getpid(),
+		Build:             crashBuildInfo(),
+		Goroutines:        goroutineDump(),
+	}
+	if c.recent != nil {
+		for _, entry := range c.recent.snapshot(0) {
+			if json.Valid(entry) {
+				report.Recent = append(report.Recent, entry)
+			}
+		}
+	}
+	data, err := json.Marshal(report)
+	if err != nil {
+		return "", fmt.Errorf("logger: encode crash report: %w", err)
+	}
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return "", fmt.Errorf("logger: create crash directory: %w", err)
+	}
+	name := fmt.Sprintf("%s%d-%d%s", crashFilePrefix, now.UnixNano(), os.Getpid(), crashFileExt)
+	path := filepath.Join(c.dir, name)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return "", fmt.Errorf("logger: write crash report: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return "", fmt.Errorf("logger: write crash report: %w", err)
+	}
+	return path, nil
+}
+
+// reingest logs every report left in the directory by a previous run, oldest first, and removes it.
+// Runtime crash output is logged as a report of level panic; empty output files, left by runs that
+// ended without crashing or without closing the logger, are only removed.
+func (c *crashReporter) reingest(l *Logger) {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() {
+			continue
+		}
+		if strings.HasPrefix(name, crashFilePrefix) && strings.HasSuffix(name, crashFileExt) ||
+			strings.HasPrefix(name, runtimeCrashPrefix) && strings.HasSuffix(name, runtimeCrashExt) {
+			names = append(names, name)
+		}
+	}
+	sort.Slice(names, func(i, j int) bool { return crashFileTime(names[i]) < crashFileTime(names[j]) })
+	for _, name := range names {
+		path := filepath.Join(c.dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if strings.HasSuffix(name, runtimeCrashExt) {
+			if len(data) == 0 {
+				_ = os.Remove(path)
+				continue
+			}
+			data = runtimeCrashReport(name, data)
+		}
+		event := l.Error().Str("crash_file", path)
+		if json.Valid(data) {
+			event = event.RawJSON("crash", data)
+		} else {
+			event = event.Str("crash", string(data))
+		}
+		event.Msg(crashReportMessage)
+		_ = os.Remove(path)
+	}
+}
+
+// crashFileTime returns the UnixNano timestamp that starts the name of a report or runtime crash
+// file after its prefix, so both kinds are logged in the order they were written.
+func crashFileTime(name string) string {
+	name = strings.TrimPrefix(strings.TrimPrefix(name, crashFilePrefix), runtimeCrashPrefix)
+	stamp, _, _ := strings.Cut(name, "-")
+	// Left-pad so timestamps of different lengths still sort numerically.
+	return fmt.Sprintf("%020s", stamp)
+}
+
+// runtimeCrashReport turns the runtime's crash output in the file name into a CrashReport. The
+// first line, such as "panic: boom" or "fatal error: concurrent map writes", is the reason.
+func runtimeCrashReport(name string, output []byte) []byte {
+	text := string(output)
+	report := CrashReport{Level: "panic", Goroutines: text}
+	report.Reason, _, _ = strings.Cut(strings.TrimSpace(text), "\n")
+	fields := strings.Split(strings.TrimSuffix(strings.TrimPrefix(name, runtimeCrashPrefix), runtimeCrashExt), "-")
+	if len(fields) == 2 {
+		if nanos, err := strconv.ParseInt(fields[0], 10, 64); err == nil {
+			report.Time = time.Unix(0, nanos).UTC().Format(defaultConsoleTimeFormat)
+		}
+		report.PID, _ = strconv.Atoi(fields[1])
+	}
+	data, err := json.Marshal(report)
+	if err != nil {
+		return output
+	}
+	return data
+}
+
+// runtimeCrashOutput tracks the file installed with debug.SetCrashOutput and the reporter that
+// installed it, since the runtime keeps one crash output for the whole process.
+var runtimeCrashOutput struct {
+	mu    sync.Mutex
+	owner *crashReporter
+	path  string
+}
+
+// captureRuntimeCrashes makes the runtime write unrecovered panics and fatal errors to a new file
+// in the directory, named after the start time and PID like the reports.
+func (c *crashReporter) captureRuntimeCrashes() error {
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return fmt.Errorf("logger: create crash directory: %w", err)
+	}
+	name := fmt.Sprintf("%s%d-%d%s", runtimeCrashPrefix, c.clock.Now().UnixNano(), os.Getpid(), runtimeCrashExt)
+	path := filepath.Join(c.dir, name)
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("logger: create crash output: %w", err)
+	}
+	// SetCrashOutput keeps a duplicate of the descriptor, so the file can be closed right away.
+	err = debug.SetCrashOutput(file, debug.CrashOptions{})
+	_ = file.Close()
+	if err != nil {
+		_ = os.Remove(path)
+		return fmt.Errorf("logger: set crash output: %w", err)
+	}
+
+	runtimeCrashOutput.mu.Lock()
+	previous := runtimeCrashOutput.path
+	runtimeCrashOutput.owner, runtimeCrashOutput.path = c, path
+	runtimeCrashOutput.mu.Unlock()
+	removeIfEmpty(previous)
+	return nil
+}
+
+// release stops capturing runtime crashes if c still owns the crash output and removes its file,
+// which is empty unless the process is crashing.
+func (c *crashReporter) release() {
+	runtimeCrashOutput.mu.Lock()
+	defer runtimeCrashOutput.mu.Unlock()
+	if runtimeCrashOutput.owner != c {
+		return
+	}
+	_ = debug.SetCrashOutput(nil, debug.CrashOptions{})
+	removeIfEmpty(runtimeCrashOutput.path)
+	runtimeCrashOutput.owner, runtimeCrashOutput.path = nil, ""
+}
+
+func removeIfEmpty(path string) {
+	if path == "" {
+		return
+	}
+	if info, err := os.Stat(path); err == nil && info.Size() == 0 {
+		_ = os.Remove(path)
+	}
+}
+
+// WriteCrashReport writes a crash report for reason to Config.Crash.Directory and returns its path.
+// Use it before ending the process in ways the logger does not see, such as os.Exit after a failed
+// startup. It returns an empty path when crash reports are off.
+func (l *Logger) WriteCrashReport(reason string) (string, error) {
+	if l == nil || l.crash == nil {
+		return "", nil
+	}
+	return l.crash.write("panic", reason)
+}
+
+// RecoverCrash writes a crash report for a panic and re-raises it, so the process still crashes
+// with the usual output. It must be invoked directly via defer, typically first in main and at the
+// top of long-lived goroutines.
+func (l *Logger) RecoverCrash() {
+	if recovered := recover(); recovered != nil {
+		if l != nil && l.crash != nil {
+			_, _ = l.crash.write("panic", fmt.Sprintf("panic: %v", recovered))
+		}
+		panic(recovered)
+	}
+}
+
+// RecoverCrash is Logger.RecoverCrash for the global logger. It must be invoked directly via defer.
+func RecoverCrash() {
+	if recovered := recover(); recovered != nil {
+		if log := Global(); log.crash != nil {
+			_, _ = log.crash.write("panic", fmt.Sprintf("panic: %v", recovered))
+		}
+		panic(recovered)
+	}
+}
+
+// goroutineDump returns the stacks of every goroutine, truncated to maxGoroutineDump bytes.
+func goroutineDump() string {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) || len(buf) >= maxGoroutineDump {
+			return string(buf[:n])
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+// crashBuildInfo returns the Go version, main module, and VCS settings stamped into the binary.
+func crashBuildInfo() map[string]string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+	build := map[string]string{
+		"go_version":   info.GoVersion,
+		"main_path":    info.Main.Path,
+		"main_version": info.Main.Version,
+	}
+	for _, setting := range info.Settings {
+		if strings.HasPrefix(setting.Key, "vcs.") {
+			build[setting.Key] = setting.Value
+		}
+	}
+	return build
+}
@@ -0,0 +1,180 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestFatalEntryWritesCrashReport(t *testing.T) {
+	dir := t.TempDir()
+	log, err := New(context.Background(), Config{
+		Enabled:     true,
+		Console:     false,
+		ServiceName: "crashy",
+		Writers:     []io.Writer{io.Discard},
+		Recent:      RecentConfig{Size: 4},
+		Crash:       CrashConfig{Directory: dir},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = log.Close() })
+
+	log.Info().Msg("before the crash")
+	// A recovered Panic entry does not end the process, for example under net/http.
+	func() {
+		defer func() { _ = recover() }()
+		log.Panic().Msg("handler exploded")
+	}()
+	if files, _ := filepath.Glob(filepath.Join(dir, crashFilePrefix+"*"+crashFileExt)); len(files) != 0 {
+		t.Fatalf("expected no crash report for a panic entry, got %v", files)
+	}
+	// WithLevel writes a fatal entry without exiting.
+	log.WithLevel(zerolog.FatalLevel).Msg("out of memory")
+
+	files, _ := filepath.Glob(filepath.Join(dir, crashFilePrefix+"*"+crashFileExt))
+	if len(files) != 1 {
+		t.Fatalf("expected one crash report, got %v", files)
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("read crash report: %v", err)
+	}
+	var report CrashReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("decode crash report: %v", err)
+	}
+	if report.Level != "fatal" || report.Reason != "out of memory" || report.ServiceName != "crashy" {
+		t.Fatalf("unexpected report header: %+v", report)
+	}
+	if !strings.Contains(report.Goroutines, "goroutine ") {
+		t.Fatalf("expected a goroutine dump, got %q", report.Goroutines)
+	}
+	if len(report.Recent) != 2 || !strings.Contains(string(report.Recent[0]), "before the crash") {
+		t.Fatalf("expected the recent entries, got %s", report.Recent)
+	}
+}
+
+func TestNewReingestsCrashReports(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, crashFilePrefix+"1-1"+crashFileExt)
+	if err := os.WriteFile(path, []byte(`{"level":"fatal","reason":"disk full"}`), 0o644); err != nil {
+		t.Fatalf("write crash report: %v", err)
+	}
+
+	out := &syncLines{}
+	log, err := New(context.Background(), Config{
+		Enabled: true,
+		Console: false,
+		Writers: []io.Writer{out},
+		Crash:   CrashConfig{Directory: dir},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = log.Close() })
+
+	entry := strings.Join(out.lines(), "\n")
+	if !strings.Contains(entry, crashReportMessage) || !strings.Contains(entry, `"crash":{"level":"fatal","reason":"disk full"}`) {
+		t.Fatalf("expected the crash report to be logged, got %s", entry)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected the crash report to be removed, got %v", err)
+	}
+}
+
+func TestRecoverCrashWritesReportAndRepanics(t *testing.T) {
+	dir := t.TempDir()
+	log, err := New(context.Background(), Config{
+		Enabled: true,
+		Console: false,
+		Writers: []io.Writer{io.Discard},
+		Crash:   CrashConfig{Directory: dir},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = log.Close() })
+
+	var repanicked any
+	func() {
+		defer func() { repanicked = recover() }()
+		defer log.RecoverCrash()
+		panic("boom")
+	}()
+	if repanicked != "boom" {
+		t.Fatalf("expected the panic to be re-raised, got %v", repanicked)
+	}
+	if files, _ := filepath.Glob(filepath.Join(dir, "*"+crashFileExt)); len(files) != 1 {
+		t.Fatalf("expected one crash report, got %v", files)
+	}
+}
+
+// crashHelperEnv makes the test binary act as a process that dies of an unrecovered panic.
+const crashHelperEnv = "GOO11Y_CRASH_HELPER_DIR"
+
+func TestRuntimeCrashOutputIsReingested(t *testing.T) {
+	if dir := os.Getenv(crashHelperEnv); dir != "" {
+		log, err := New(context.Background(), Config{
+			Enabled: true,
+			Console: false,
+			Writers: []io.Writer{io.Discard},
+			Crash:   CrashConfig{Directory: dir},
+		})
+		if err != nil {
+			t.Fatalf("New: %v", err)
+		}
+		t.Cleanup(func() { _ = log.Close() })
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			panic("unrecovered in worker")
+		}()
+		<-done
+		return
+	}
+
+	dir := t.TempDir()
+	cmd := exec.Command(os.Args[0], "-test.run=^TestRuntimeCrashOutputIsReingested$")
+	cmd.Env = append(os.Environ(), crashHelperEnv+"="+dir)
+	if err := cmd.Run(); err == nil {
+		t.Fatal("expected the helper process to crash")
+	}
+	files, _ := filepath.Glob(filepath.Join(dir, runtimeCrashPrefix+"*"+runtimeCrashExt))
+	if len(files) != 1 {
+		t.Fatalf("expected one runtime crash file, got %v", files)
+	}
+
+	out := &syncLines{}
+	log, err := New(context.Background(), Config{
+		Enabled: true,
+		Console: false,
+		Writers: []io.Writer{out},
+		Crash:   CrashConfig{Directory: dir},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	entry := strings.Join(out.lines(), "\n")
+	if !strings.Contains(entry, crashReportMessage) || !strings.Contains(entry, `"reason":"panic: unrecovered in worker`) {
+		t.Fatalf("expected the runtime crash to be logged, got %s", entry)
+	}
+	if _, err := os.Stat(files[0]); !os.IsNotExist(err) {
+		t.Fatalf("expected the runtime crash file to be removed, got %v", err)
+	}
+
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if left, _ := filepath.Glob(filepath.Join(dir, runtimeCrashPrefix+"*")); len(left) != 0 {
+		t.Fatalf("expected Close to remove the empty crash output, got %v", left)
+	}
+}
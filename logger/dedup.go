@@ -0,0 +1,142 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mfahmialkautsar/goo11y/clock"
+	"github.com/rs/zerolog"
+)
+
+// RepeatCountField is added to the summary entry written for repeats suppressed by DedupConfig.
+const RepeatCountField = "repeat_count"
+
+// DedupConfig suppresses repeats of the same entry, such as an error logged in a tight loop.
+// Consecutive entries sharing level, message, caller, and error count as repeats for Window after
+// the first one is written. The repeats are dropped and, when the window closes or a different
+// entry arrives, the last of them is written once more with repeat_count set to how many were
+// dropped. Zero Window turns deduplication off.
+type DedupConfig struct {
+	Window time.Duration `validate:"gte=0"`
+}
+
+// dedupWriter drops repeated entries before they reach the sinks and writes their summary.
+type dedupWriter struct {
+	next   io.Writer
+	window time.Duration
+	clock  clock.Clock
+	naming *fieldNaming
+
+	mu         sync.Mutex
+	key        []byte
+	started    time.Time
+	last       []byte
+	suppressed int
+	run        uint64
+}
+
+func newDedupWriter(next io.Writer, window time.Duration, clk clock.Clock, naming *fieldNaming) *dedupWriter {
+	return &dedupWriter{next: next, window: window, clock: clock.OrReal(clk), naming: naming}
+}
+
+func (w *dedupWriter) Write(p []byte) (int, error) {
+	key := dedupKey(p, w.naming)
+	now := w.clock.Now()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.key != nil && bytes.Equal(key, w.key) && now.Sub(w.started) < w.window {
+		w.last = append(w.last[:0], p...)
+		w.suppressed++
+		if w.suppressed == 1 {
+			go w.closeAfter(w.run, w.window-now.Sub(w.started))
+		}
+		return len(p), nil
+	}
+
+	w.summarize()
+	w.key = key
+	w.started = now
+	w.run++
+	return w.next.Write(p)
+}
+
+// closeAfter writes the summary of run once its window ends, unless another entry ended it first.
+func (w *dedupWriter) closeAfter(run uint64, d time.Duration) {
+	timer := w.clock.NewTimer(d)
+	defer timer.Stop()
+	<-timer.C()
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.run == run {
+		w.summarize()
+		w.key = nil
+	}
+}
+
+// flush writes the summary of the current run, if it has suppressed repeats.
+func (w *dedupWriter) flush() {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.summarize()
+	w.key = nil
+}
+
+// summarize writes the last suppressed entry with repeat_count. Callers hold mu.
+func (w *dedupWriter) summarize() {
+	if w.suppressed == 0 {
+		return
+	}
+	entry := withRepeatCount(w.last, w.suppressed)
+	w.suppressed = 0
+	w.last = w.last[:0]
+	w.run++
+	_, _ = w.next.Write(entry)
+}
+
+// dedupKey fingerprints an entry by its level, message, caller, and error, under the keys of naming.
+func dedupKey(p []byte, naming *fieldNaming) []byte {
+	var level, message, caller, errValue []byte
+	scanJSONObject(bytes.TrimRight(p, "\r\n"), func(key, value []byte) {
+		switch {
+		case keyIs(key, naming.to.level):
+			level = value
+		case keyIs(key, naming.to.message):
+			message = value
+		case keyIs(key, zerolog.CallerFieldName):
+			caller = value
+		case keyIs(key, naming.to.error):
+			errValue = value
+		}
+	})
+	key := make([]byte, 0, len(level)+len(message)+len(caller)+len(errValue)+3)
+	for _, part := range [][]byte{level, message, caller, errValue} {
+		key = append(key, part...)
+		key = append(key, 0)
+	}
+	return key
+}
+
+// withRepeatCount appends the repeat_count field to an encoded JSON entry.
+func withRepeatCount(entry []byte, count int) []byte {
+	trimmed := bytes.TrimRight(entry, "\r\n")
+	end := bytes.LastIndexByte(trimmed, '}')
+	if end < 0 {
+		return append([]byte(nil), entry...)
+	}
+	out := make([]byte, 0, len(entry)+len(RepeatCountField)+16)
+	out = append(out, trimmed[:end]...)
+	out = append(out, ',', '"')
+	out = append(out, RepeatCountField...)
+	out = append(out, `":`...)
+	out = strconv.AppendInt(out, int64(count), 10)
+	out = append(out, '}', '\n')
+	return out
+}
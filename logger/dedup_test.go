@@ -0,0 +1,153 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mfahmialkautsar/goo11y/clock"
+)
+
+type dedupClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers chan chan time.Time
+}
+
+func (c *dedupClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *dedupClock) advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	c.mu.Unlock()
+}
+
+func (c *dedupClock) NewTimer(time.Duration) clock.Timer {
+	ch := make(chan time.Time, 1)
+	c.timers <- ch
+	return dedupTimer(ch)
+}
+
+func (c *dedupClock) NewTicker(d time.Duration) clock.Ticker {
+	return clock.Real().NewTicker(d)
+}
+
+type dedupTimer chan time.Time
+
+func (t dedupTimer) C() <-chan time.Time      { return t }
+func (t dedupTimer) Stop() bool               { return true }
+func (t dedupTimer) Reset(time.Duration) bool { return true }
+
+func newDedupLogger(t *testing.T, clk clock.Clock) (*Logger, *syncLines) {
+	t.Helper()
+	out := &syncLines{}
+	log, err := New(context.Background(), Config{
+		Enabled: true,
+		Console: false,
+		Writers: []io.Writer{out},
+		Dedup:   DedupConfig{Window: time.Minute},
+		Clock:   clk,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = log.Close() })
+	return log, out
+}
+
+type syncLines struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (s *syncLines) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.Write(p)
+}
+
+func (s *syncLines) lines() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return strings.Split(strings.TrimSpace(s.buf.String()), "\n")
+}
+
+func TestDedupSummarizesRepeatsOnNextEntry(t *testing.T) {
+	clk := &dedupClock{now: time.Unix(1_700_000_000, 0), timers: make(chan chan time.Time, 4)}
+	log, out := newDedupLogger(t, clk)
+
+	for range 5 {
+		log.Error().Err(errors.New("db down")).Msg("query failed")
+	}
+	log.Error().Err(errors.New("db down")).Msg("other failure")
+
+	lines := out.lines()
+	if len(lines) != 3 {
+		t.Fatalf("expected first entry, summary, and next entry, got %q", lines)
+	}
+	if strings.Contains(lines[0], RepeatCountField) || !strings.Contains(lines[1], `"repeat_count":4`) || !strings.Contains(lines[1], "query failed") {
+		t.Fatalf("unexpected summary lines %q", lines)
+	}
+	if !strings.Contains(lines[2], "other failure") {
+		t.Fatalf("expected the different entry last, got %q", lines[2])
+	}
+}
+
+func TestDedupSummarizesWhenWindowCloses(t *testing.T) {
+	clk := &dedupClock{now: time.Unix(1_700_000_000, 0), timers: make(chan chan time.Time, 4)}
+	log, out := newDedupLogger(t, clk)
+
+	retry := func() { log.Warn().Msg("retrying") }
+	for range 3 {
+		retry()
+	}
+
+	var fire chan time.Time
+	select {
+	case fire = <-clk.timers:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected a window timer")
+	}
+	clk.advance(time.Minute)
+	fire <- clk.Now()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(out.lines()) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected summary after the window, got %q", out.lines())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !strings.Contains(out.lines()[1], `"repeat_count":2`) {
+		t.Fatalf("unexpected summary %q", out.lines()[1])
+	}
+
+	retry()
+	if lines := out.lines(); len(lines) != 3 || strings.Contains(lines[2], RepeatCountField) {
+		t.Fatalf("expected a fresh entry after the window, got %q", lines)
+	}
+}
+
+func TestDedupFlushWritesPendingSummary(t *testing.T) {
+	log, out := newDedupLogger(t, nil)
+
+	for _, user := range []string{"a", "b"} {
+		log.Info().Str("user", user).Msg("tick")
+	}
+	if err := log.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	lines := out.lines()
+	if len(lines) != 2 || !strings.Contains(lines[1], `"user":"b"`) || !strings.Contains(lines[1], `"repeat_count":1`) {
+		t.Fatalf("expected the last repeat summarized on flush, got %q", lines)
+	}
+}
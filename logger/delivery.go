@@ -0,0 +1,212 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mfahmialkautsar/goo11y/internal/otlputil"
+	otelLog "go.opentelemetry.io/otel/log"
+)
+
+// Delivery modes.
+const (
+	DeliveryDefault = "default"
+	DeliveryOrdered = "ordered"
+	DeliveryAsync   = "async"
+)
+
+const defaultSinkQueueSize = 1024
+
+// DeliveryConfig decides how entries travel from the logging call to the sinks. By default each
+// sink keeps its own buffering: custom writers and the console are written synchronously, the file
+// writer through its queue, and OTLP through its batch processor, so sinks can disagree on order.
+//
+// "ordered" writes entries one at a time, every sink synchronously in the logging goroutine,
+// including the file; every sink sees the same order, at the cost of each call waiting for the
+// slowest local sink. The OTLP writer still batches, in entry order.
+//
+// "async" gives every sink its own queue and goroutine, so a slow sink delays neither the caller
+// nor the other sinks. QueueSizes sets the queue length per sink name (custom_0, file, console,
+// otlp, or an AddWriter name); other sinks use QueueSize. A full queue blocks the caller, or drops
+// the entry when DropWhenFull is set. Logger.Stats reports each queue's depth, drops, and lag. Fatal
+// and Panic entries, after which the process usually ends, are never dropped: the caller waits until
+// every sink has written them, after the entries queued before them.
+type DeliveryConfig struct {
+	Mode         string `default:"default" validate:"omitempty,oneof=default ordered async"`
+	QueueSize    int    `default:"1024" validate:"gte=0"`
+	QueueSizes   map[string]int
+	DropWhenFull bool
+}
+
+func (c DeliveryConfig) queueSize(name string) int {
+	if size, ok := c.QueueSizes[name]; ok && size > 0 {
+		return size
+	}
+	if c.QueueSize > 0 {
+		return c.QueueSize
+	}
+	return defaultSinkQueueSize
+}
+
+// SinkStats reports the queue of one sink in async delivery.
+type SinkStats struct {
+	// Queued is the number of entries waiting for the sink.
+	Queued int
+	// Dropped is the number of entries discarded because the queue was full.
+	Dropped uint64
+	// Lag is how long the most recently written entry waited in the queue.
+	Lag time.Duration
+}
+
+type asyncEntry struct {
+	payload []byte
+	queued  time.Time
+	flushed chan struct{}
+	// written receives the write result of an entry the caller waits for.
+	written chan error
+}
+
+// asyncSink writes to next from its own goroutine, through a bounded queue.
+type asyncSink struct {
+	name     string
+	next     io.Writer
+	drop     bool
+	failures *atomic.Uint64
+
+	mu      sync.RWMutex
+	closed  bool
+	queue   chan asyncEntry
+	done    chan struct{}
+	dropped atomic.Uint64
+	lag     atomic.Int64
+}
+
+func newAsyncSink(name string, next io.Writer, size int, drop bool, failures *atomic.Uint64) *asyncSink {
+	s := &asyncSink{
+		name:     name,
+		next:     next,
+		drop:     drop,
+		failures: failures,
+		queue:    make(chan asyncEntry, size),
+		done:     make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+func (s *asyncSink) Write(p []byte) (int, error) {
+	entry := asyncEntry{payload: append([]byte(nil), p...), queued: time.Now()}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return 0, errors.New("logger: sink closed")
+	}
+	if s.drop {
+		select {
+		case s.queue <- entry:
+		default:
+			s.dropped.Add(1)
+		}
+		return len(p), nil
+	}
+	s.queue <- entry
+	return len(p), nil
+}
+
+// writeSync queues p behind the pending entries and waits until it is written, returning the
+// sink's own result. It never drops p.
+func (s *asyncSink) writeSync(p []byte) (int, error) {
+	entry := asyncEntry{payload: append([]byte(nil), p...), queued: time.Now(), written: make(chan error, 1)}
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return 0, errors.New("logger: sink closed")
+	}
+	s.queue <- entry
+	s.mu.RUnlock()
+	if err := <-entry.written; err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (s *asyncSink) run() {
+	defer close(s.done)
+	for entry := range s.queue {
+		if entry.flushed != nil {
+			close(entry.flushed)
+			continue
+		}
+		_, err := s.next.Write(entry.payload)
+		s.lag.Store(int64(time.Since(entry.queued)))
+		if entry.written != nil {
+			// The caller counts and reports the failure itself.
+			entry.written <- err
+			continue
+		}
+		if err != nil {
+			if s.failures != nil {
+				s.failures.Add(1)
+			}
+			otlputil.LogExportFailure("logger", s.name, err)
+		}
+	}
+}
+
+// endsProcess reports whether p is a Fatal or Panic entry, reading its level from levelKey.
+func endsProcess(p []byte, levelKey string) bool {
+	ends := false
+	scanJSONObject(bytes.TrimRight(p, "\r\n"), func(key, value []byte) {
+		if keyIs(key, levelKey) && len(value) >= 2 && value[0] == '"' {
+			ends = levelSeverity(value[1:len(value)-1]) >= otelLog.SeverityFatal
+		}
+	})
+	return ends
+}
+
+// drain waits until the entries queued before the call are written.
+func (s *asyncSink) drain(ctx context.Context) error {
+	flushed := make(chan struct{})
+	s.mu.RLock()
+	if s.closed {
+		s.mu.RUnlock()
+		return nil
+	}
+	select {
+	case s.queue <- asyncEntry{flushed: flushed}:
+	case <-ctx.Done():
+		s.mu.RUnlock()
+		return ctx.Err()
+	}
+	s.mu.RUnlock()
+	select {
+	case <-flushed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// stop writes the queued entries and ends the goroutine. It does not close next.
+func (s *asyncSink) stop() {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+	<-s.done
+}
+
+func (s *asyncSink) stats() SinkStats {
+	return SinkStats{
+		Queued:  len(s.queue),
+		Dropped: s.dropped.Load(),
+		Lag:     time.Duration(s.lag.Load()),
+	}
+}
@@ -0,0 +1,155 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// gatedWriter blocks every write until gate is closed.
+type gatedWriter struct {
+	gate chan struct{}
+	syncLines
+}
+
+func (w *gatedWriter) Write(p []byte) (int, error) {
+	<-w.gate
+	return w.syncLines.Write(p)
+}
+
+func TestAsyncDeliveryIsolatesSlowSinks(t *testing.T) {
+	slow := &gatedWriter{gate: make(chan struct{})}
+	fast := &syncLines{}
+	log, err := New(context.Background(), Config{
+		Enabled:  true,
+		Console:  false,
+		Writers:  []io.Writer{slow, fast},
+		Delivery: DeliveryConfig{Mode: DeliveryAsync, QueueSizes: map[string]int{"custom_0": 2}, DropWhenFull: true},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = log.Close() })
+
+	for range 5 {
+		log.Info().Msg("queued")
+	}
+	deadline := time.Now().Add(time.Second)
+	for len(fast.lines()) < 5 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := len(fast.lines()); got != 5 {
+		t.Fatalf("expected the fast sink to get every entry while the slow one is stuck, got %d", got)
+	}
+
+	stats := log.Stats().Sinks["custom_0"]
+	if stats.Dropped == 0 {
+		t.Fatalf("expected the slow sink's small queue to drop entries, got %+v", stats)
+	}
+
+	close(slow.gate)
+	if err := log.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if got := strings.Count(slow.buf.String(), "\n") + int(log.Stats().Sinks["custom_0"].Dropped); got != 5 {
+		t.Fatalf("expected written and dropped entries to add up to 5, got %d", got)
+	}
+}
+
+func TestOrderedDeliveryWritesFileInCallOrder(t *testing.T) {
+	dir := t.TempDir()
+	var buf syncLines
+	log, err := New(context.Background(), Config{
+		Enabled:  true,
+		Console:  false,
+		Writers:  []io.Writer{&buf},
+		File:     FileConfig{Enabled: true, Directory: dir},
+		Delivery: DeliveryConfig{Mode: DeliveryOrdered},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = log.Close() })
+
+	var wg sync.WaitGroup
+	for worker := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range 25 {
+				log.Info().Int("worker", worker).Int("i", i).Msg("ordered")
+			}
+		}()
+	}
+	wg.Wait()
+
+	// No Close or Flush: ordered delivery has already written the file.
+	files, _ := filepath.Glob(filepath.Join(dir, "*.log"))
+	if len(files) != 1 {
+		t.Fatalf("expected one log file, got %v", files)
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	want := strings.Join(buf.lines(), "\n")
+	if got := string(bytes.TrimRight(data, "\n")); got != want {
+		t.Fatalf("expected the file and the custom writer to hold the same sequence")
+	}
+	if len(buf.lines()) != 100 {
+		t.Fatalf("expected 100 entries, got %d", len(buf.lines()))
+	}
+}
+
+func TestAsyncDeliveryWritesFatalEntriesBeforeReturning(t *testing.T) {
+	slow := &gatedWriter{gate: make(chan struct{})}
+	hook := &recordingHook{before: func(map[string]any) bool { return true }}
+	log, err := New(context.Background(), Config{
+		Enabled:  true,
+		Console:  false,
+		Writers:  []io.Writer{slow, failingWriter{}},
+		Hooks:    []Hook{hook},
+		Delivery: DeliveryConfig{Mode: DeliveryAsync, QueueSize: 1, DropWhenFull: true},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = log.Close() })
+
+	log.Info().Msg("before")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		log.WithLevel(zerolog.FatalLevel).Msg("dying")
+	}()
+	select {
+	case <-done:
+		t.Fatal("expected the fatal entry to wait for the stuck sink")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(slow.gate)
+	<-done
+
+	lines := slow.lines()
+	if len(lines) != 2 || !strings.Contains(lines[0], "before") || !strings.Contains(lines[1], "dying") {
+		t.Fatalf("expected both entries written in order when the call returns, got %q", lines)
+	}
+
+	if len(hook.results) != 2 {
+		t.Fatalf("expected results for two entries, got %+v", hook.results)
+	}
+	if queued := hook.results[0][1]; queued.Err != nil {
+		t.Fatalf("expected a queued entry to report acceptance only, got %v", queued.Err)
+	}
+	if fatal := hook.results[1][1]; fatal.Err == nil {
+		t.Fatalf("expected the fatal entry to report the sink's own failure, got %+v", fatal)
+	}
+}
@@ -0,0 +1,25 @@
+package logger
+
+import "github.com/mfahmialkautsar/goo11y/internal/otlputil"
+
+// applyEnv fills the fields left unset from the OTLP exporter environment variables of signal.
+func (c *OTLPConfig) applyEnv(signal string) {
+	env := otlputil.ReadEnv(signal)
+	if c.Endpoint == "" {
+		c.Endpoint = env.Endpoint
+		c.EndpointIsURL = c.EndpointIsURL || env.EndpointIsURL
+	}
+	if !c.Insecure {
+		c.Insecure = env.Insecure
+	}
+	c.Headers = otlputil.MergeHeaders(c.Headers, env.Headers)
+	if c.Timeout == 0 {
+		c.Timeout = env.Timeout
+	}
+	if c.Protocol == "" {
+		c.Protocol = env.Protocol
+	}
+	if c.Certificate == "" {
+		c.Certificate = env.Certificate
+	}
+}
@@ -0,0 +1,26 @@
+package logger
+
+import (
+	"testing"
+	"time"
+)
+
+func TestOTLPConfigFallsBackToEnv(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_LOGS_ENDPOINT", "collector:4317")
+	t.Setenv("OTEL_EXPORTER_OTLP_LOGS_PROTOCOL", "grpc")
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "x-tenant=env")
+	t.Setenv("OTEL_EXPORTER_OTLP_TIMEOUT", "1500")
+
+	cfg := Config{}.ApplyDefaults()
+	if cfg.OTLP.Endpoint != "collector:4317" || cfg.OTLP.Protocol != "grpc" {
+		t.Fatalf("unexpected endpoint %q or protocol %q", cfg.OTLP.Endpoint, cfg.OTLP.Protocol)
+	}
+	if cfg.OTLP.Timeout != 1500*time.Millisecond || cfg.OTLP.Headers["x-tenant"] != "env" {
+		t.Fatalf("unexpected timeout %v or headers %v", cfg.OTLP.Timeout, cfg.OTLP.Headers)
+	}
+
+	explicit := Config{OTLP: OTLPConfig{Endpoint: "https://logs.example.com", Protocol: "http"}}.ApplyDefaults()
+	if explicit.OTLP.Endpoint != "https://logs.example.com" || explicit.OTLP.Protocol != "http" {
+		t.Fatalf("expected config values to win, got %q and %q", explicit.OTLP.Endpoint, explicit.OTLP.Protocol)
+	}
+}
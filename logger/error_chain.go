@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/rs/zerolog"
+)
+
+// ErrorChainField is the key of the array written by Logger.Err when Config.ErrorChain is set.
+const ErrorChainField = "error_chain"
+
+// chainLink describes one error of a wrapped chain.
+type chainLink struct {
+	message string
+	kind    string
+	frames  []map[string]any
+}
+
+// errorChain renders as an array of {message, type, stack} objects, outermost error first.
+type errorChain []chainLink
+
+// MarshalZerologArray implements zerolog.LogArrayMarshaler.
+func (c errorChain) MarshalZerologArray(a *zerolog.Array) {
+	for _, link := range c {
+		a.Object(link)
+	}
+}
+
+// MarshalZerologObject implements zerolog.LogObjectMarshaler.
+func (l chainLink) MarshalZerologObject(e *zerolog.Event) {
+	e.Str("message", l.message).Str("type", l.kind)
+	if len(l.frames) > 0 {
+		e.Interface("stack", l.frames)
+	}
+}
+
+// ErrorChain describes err and every error it wraps, outermost first, each with its message, Go
+// type, and the stack frames it contributed: frames of its own stack trace that no error it wraps
+// already reported. Unlike the merged stack field, this keeps which wrapper added which frames.
+// Log it with event.Array(ErrorChainField, ErrorChain(err)). Frames are recorded unfiltered;
+// Logger.Err applies the logger's StackConfig.
+func ErrorChain(err error) zerolog.LogArrayMarshaler {
+	return errorChainOf(err, stackFilter{})
+}
+
+// errorChainOf builds the chain of err with its Go frames shaped by filter.
+func errorChainOf(err error, filter stackFilter) errorChain {
+	var errs []error
+	visited := make(map[uintptr]struct{})
+	var walk func(error)
+	walk = func(current error) {
+		if current == nil || shouldStopWalking(current, visited) {
+			return
+		}
+		errs = append(errs, current)
+		handleUnwrap(current, walk)
+	}
+	walk(err)
+
+	chain := make(errorChain, len(errs))
+	seen := make(map[string]struct{})
+	// Inner errors claim their frames first, so a wrapper keeps only the frames it added.
+	for idx := len(errs) - 1; idx >= 0; idx-- {
+		current := errs[idx]
+		link := chainLink{message: current.Error(), kind: fmt.Sprintf("%T", current)}
+		var own []runtime.Frame
+		handleTracer(current, &own, make(map[string]struct{}))
+		var added []runtime.Frame
+		for _, frame := range own {
+			key := fmt.Sprintf("%s|%s|%d", frame.Function, frame.File, frame.Line)
+			if _, dup := seen[key]; dup {
+				continue
+			}
+			seen[key] = struct{}{}
+			added = append(added, frame)
+		}
+		for _, frame := range filter.apply(added) {
+			link.frames = append(link.frames, stackEntry(frame.Function, filter.location(frame)))
+		}
+		if external, ok := current.(interface{ ExternalStack() []StackFrame }); ok {
+			for _, frame := range external.ExternalStack() {
+				link.frames = append(link.frames, stackEntry(frame.Function, frame.location()))
+			}
+		}
+		chain[idx] = link
+	}
+	return chain
+}
@@ -0,0 +1,76 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+)
+
+//go:noinline
+func chainWrapError() error {
+	inner := nestedInnerError()
+	return pkgerrors.Wrap(inner, "wrap failed")
+}
+
+func TestLoggerErrWritesErrorChain(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := New(context.Background(), Config{
+		Enabled:    true,
+		Console:    false,
+		Writers:    []io.Writer{&buf},
+		ErrorChain: true,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	log.Err(fmt.Errorf("handler: %w", chainWrapError())).Msg("request failed")
+
+	entry := decodeLogLine(t, buf.Bytes())
+	if msg, _ := entry["error"].(string); msg != "handler: wrap failed: nested boom" {
+		t.Fatalf("expected the flattened error kept, got %v", entry["error"])
+	}
+	chain, ok := entry[ErrorChainField].([]any)
+	if !ok || len(chain) != 4 {
+		t.Fatalf("expected four chain links, got %v", entry[ErrorChainField])
+	}
+
+	links := make([]map[string]any, len(chain))
+	for idx, raw := range chain {
+		links[idx] = raw.(map[string]any)
+	}
+	if links[0]["type"] != "*fmt.wrapError" || links[0]["stack"] != nil {
+		t.Fatalf("unexpected outermost link %v", links[0])
+	}
+	if links[3]["type"] != "*errors.fundamental" || links[3]["message"] != "nested boom" {
+		t.Fatalf("unexpected innermost link %v", links[3])
+	}
+
+	functions := func(link map[string]any) string {
+		stack, _ := link["stack"].([]any)
+		var names []string
+		for _, frame := range stack {
+			names = append(names, fmt.Sprint(frame.(map[string]any)["function"]))
+		}
+		return strings.Join(names, ",")
+	}
+	if wrap := functions(links[1]); wrap != "github.com/mfahmialkautsar/goo11y/logger.chainWrapError" {
+		t.Fatalf("expected the wrapper to own only the frames it added, got %s", wrap)
+	}
+	if inner := functions(links[3]); !strings.Contains(inner, "nestedInnerError") || !strings.Contains(inner, "TestLoggerErrWritesErrorChain") {
+		t.Fatalf("expected the innermost error to own its full creation stack, got %s", inner)
+	}
+}
+
+func TestLoggerErrOmitsErrorChainByDefault(t *testing.T) {
+	log, buf := newBufferedLogger(t, "no-chain", "")
+	log.Err(chainWrapError()).Msg("request failed")
+	if _, ok := decodeLogLine(t, buf.Bytes())[ErrorChainField]; ok {
+		t.Fatal("expected no error_chain without Config.ErrorChain")
+	}
+}
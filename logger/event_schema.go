@@ -0,0 +1,295 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// EventNameKey is the field that carries the schema name of structured events.
+var EventNameKey = StandardizeKey("event.name")
+
+// FieldType is the value type accepted by an event schema field.
+type FieldType int
+
+// Supported event field types.
+const (
+	FieldString FieldType = iota + 1
+	FieldInt
+	FieldFloat
+	FieldBool
+	FieldDuration
+	FieldTime
+)
+
+// ErrUnknownEvent is returned when emitting an event whose schema was never registered.
+var ErrUnknownEvent = errors.New("logger: unknown event")
+
+// EventField declares one typed field of an event schema.
+type EventField struct {
+	Name     string
+	Type     FieldType
+	Required bool
+}
+
+// EventSchema describes a named domain event. Every emitted event is checked against its fields and
+// written at info level with the fields in declaration order. When SpanEvent is set, the event is also
+// recorded on the span found in the emitting context.
+type EventSchema struct {
+	Name      string
+	Fields    []EventField
+	SpanEvent bool
+}
+
+type eventRegistry struct {
+	mu      sync.RWMutex
+	schemas map[string]EventSchema
+}
+
+func (r *eventRegistry) register(schema EventSchema) error {
+	if schema.Name == "" {
+		return errors.New("logger: event schema requires a name")
+	}
+	seen := make(map[string]struct{}, len(schema.Fields))
+	for _, field := range schema.Fields {
+		if field.Name == "" {
+			return fmt.Errorf("logger: event %q has a field without a name", schema.Name)
+		}
+		if field.Type < FieldString || field.Type > FieldTime {
+			return fmt.Errorf("logger: event %q field %q has unsupported type %d", schema.Name, field.Name, field.Type)
+		}
+		if _, dup := seen[field.Name]; dup {
+			return fmt.Errorf("logger: event %q declares field %q twice", schema.Name, field.Name)
+		}
+		seen[field.Name] = struct{}{}
+	}
+	schema.Fields = append([]EventField(nil), schema.Fields...)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.schemas == nil {
+		r.schemas = make(map[string]EventSchema)
+	}
+	r.schemas[schema.Name] = schema
+	return nil
+}
+
+func (r *eventRegistry) lookup(name string) (EventSchema, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	schema, ok := r.schemas[name]
+	return schema, ok
+}
+
+// RegisterEvent adds schema to the logger, replacing any schema registered under the same name.
+// No-op if receiver is nil.
+func (l *Logger) RegisterEvent(schema EventSchema) error {
+	if l == nil || l.events == nil {
+		return nil
+	}
+	return l.events.register(schema)
+}
+
+// EmitEvent validates fields against the schema registered as name and logs the event. Unknown
+// fields, missing required fields, and values of the wrong type are rejected without logging.
+// No-op if receiver is nil.
+func (l *Logger) EmitEvent(ctx context.Context, name string, fields map[string]any) error {
+	if l == nil || l.events == nil {
+		return nil
+	}
+	schema, ok := l.events.lookup(name)
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownEvent, name)
+	}
+	values, err := schema.normalize(fields)
+	if err != nil {
+		return err
+	}
+
+	event := l.Info()
+	if ctx != nil {
+		event = event.Ctx(ctx)
+	}
+	event = event.Str(EventNameKey, schema.Name)
+	attrs := make([]attribute.KeyValue, 0, len(values))
+	for _, field := range schema.Fields {
+		value, ok := values[field.Name]
+		if !ok {
+			continue
+		}
+		event = appendEventField(event, field, value)
+		attrs = append(attrs, eventAttribute(field, value))
+	}
+	event.Msg(schema.Name)
+
+	if schema.SpanEvent && ctx != nil {
+		if span := trace.SpanFromContext(ctx); span.IsRecording() {
+			span.AddEvent(schema.Name, trace.WithAttributes(attrs...))
+		}
+	}
+	return nil
+}
+
+// RegisterEvent adds schema to the global logger.
+func RegisterEvent(schema EventSchema) error {
+	return Global().RegisterEvent(schema)
+}
+
+// EmitEvent validates and logs a structured event through the global logger.
+func EmitEvent(ctx context.Context, name string, fields map[string]any) error {
+	return Global().EmitEvent(ctx, name, fields)
+}
+
+func (s EventSchema) normalize(fields map[string]any) (map[string]any, error) {
+	declared := make(map[string]EventField, len(s.Fields))
+	for _, field := range s.Fields {
+		declared[field.Name] = field
+		if _, ok := fields[field.Name]; field.Required && !ok {
+			return nil, fmt.Errorf("logger: event %q is missing required field %q", s.Name, field.Name)
+		}
+	}
+	values := make(map[string]any, len(fields))
+	for name, raw := range fields {
+		field, ok := declared[name]
+		if !ok {
+			return nil, fmt.Errorf("logger: event %q has no field %q", s.Name, name)
+		}
+		value, ok := convertEventValue(field.Type, raw)
+		if !ok {
+			return nil, fmt.Errorf("logger: event %q field %q expects %s, got %T", s.Name, name, field.Type, raw)
+		}
+		values[name] = value
+	}
+	return values, nil
+}
+
+func convertEventValue(kind FieldType, raw any) (any, bool) {
+	switch kind {
+	case FieldString:
+		v, ok := raw.(string)
+		return v, ok
+	case FieldInt:
+		return toInt64(raw)
+	case FieldFloat:
+		switch v := raw.(type) {
+		case float64:
+			return v, true
+		case float32:
+			return float64(v), true
+		}
+		if v, ok := toInt64(raw); ok {
+			return float64(v), true
+		}
+		return nil, false
+	case FieldBool:
+		v, ok := raw.(bool)
+		return v, ok
+	case FieldDuration:
+		v, ok := raw.(time.Duration)
+		return v, ok
+	case FieldTime:
+		v, ok := raw.(time.Time)
+		return v, ok
+	default:
+		return nil, false
+	}
+}
+
+func toInt64(raw any) (int64, bool) {
+	switch v := raw.(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint:
+		return uintToInt64(uint64(v))
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint64:
+		return uintToInt64(v)
+	default:
+		return 0, false
+	}
+}
+
+func uintToInt64(v uint64) (int64, bool) {
+	if v > math.MaxInt64 {
+		return 0, false
+	}
+	return int64(v), true
+}
+
+func appendEventField(event *zerolog.Event, field EventField, value any) *zerolog.Event {
+	switch v := value.(type) {
+	case string:
+		return event.Str(field.Name, v)
+	case int64:
+		return event.Int64(field.Name, v)
+	case float64:
+		return event.Float64(field.Name, v)
+	case bool:
+		return event.Bool(field.Name, v)
+	case time.Duration:
+		return event.Dur(field.Name, v)
+	case time.Time:
+		return event.Time(field.Name, v)
+	default:
+		return event
+	}
+}
+
+func eventAttribute(field EventField, value any) attribute.KeyValue {
+	key := attribute.Key(field.Name)
+	switch v := value.(type) {
+	case string:
+		return key.String(v)
+	case int64:
+		return key.Int64(v)
+	case float64:
+		return key.Float64(v)
+	case bool:
+		return key.Bool(v)
+	case time.Duration:
+		return key.Float64(float64(v) / float64(zerolog.DurationFieldUnit))
+	case time.Time:
+		return key.String(v.Format(zerolog.TimeFieldFormat))
+	default:
+		return key.String(fmt.Sprint(v))
+	}
+}
+
+func (t FieldType) String() string {
+	switch t {
+	case FieldString:
+		return "string"
+	case FieldInt:
+		return "int"
+	case FieldFloat:
+		return "float"
+	case FieldBool:
+		return "bool"
+	case FieldDuration:
+		return "duration"
+	case FieldTime:
+		return "time"
+	default:
+		return fmt.Sprintf("FieldType(%d)", int(t))
+	}
+}
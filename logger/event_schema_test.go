@@ -0,0 +1,160 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newEventSchemaLogger(t *testing.T, buf *bytes.Buffer) *Logger {
+	t.Helper()
+	log, err := New(context.Background(), Config{
+		Enabled: true,
+		Level:   "info",
+		Console: false,
+		Writers: []io.Writer{buf},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = log.Close() })
+	return log
+}
+
+var orderCreated = EventSchema{
+	Name: "order.created",
+	Fields: []EventField{
+		{Name: "order_id", Type: FieldString, Required: true},
+		{Name: "items", Type: FieldInt, Required: true},
+		{Name: "total", Type: FieldFloat},
+		{Name: "latency", Type: FieldDuration},
+	},
+	SpanEvent: true,
+}
+
+func TestEmitEventWritesTypedFields(t *testing.T) {
+	var buf bytes.Buffer
+	log := newEventSchemaLogger(t, &buf)
+	if err := log.RegisterEvent(orderCreated); err != nil {
+		t.Fatalf("RegisterEvent: %v", err)
+	}
+
+	err := log.EmitEvent(context.Background(), "order.created", map[string]any{
+		"order_id": "o-1",
+		"items":    uint8(3),
+		"total":    12,
+		"latency":  1500 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("EmitEvent: %v", err)
+	}
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("decode entry: %v", err)
+	}
+	if entry[EventNameKey] != "order.created" || entry["message"] != "order.created" {
+		t.Fatalf("unexpected event name fields: %v", entry)
+	}
+	if entry["order_id"] != "o-1" || entry["items"] != float64(3) || entry["total"] != float64(12) {
+		t.Fatalf("unexpected event fields: %v", entry)
+	}
+	if entry["latency"] != float64(1500) {
+		t.Fatalf("expected latency in milliseconds, got %v", entry["latency"])
+	}
+	if strings.Index(buf.String(), `"order_id"`) > strings.Index(buf.String(), `"items"`) {
+		t.Fatalf("expected fields in schema order, got %s", buf.String())
+	}
+}
+
+func TestEmitEventRejectsInvalidFields(t *testing.T) {
+	var buf bytes.Buffer
+	log := newEventSchemaLogger(t, &buf)
+	if err := log.RegisterEvent(orderCreated); err != nil {
+		t.Fatalf("RegisterEvent: %v", err)
+	}
+
+	cases := map[string]map[string]any{
+		"missing required": {"order_id": "o-1"},
+		"wrong type":       {"order_id": "o-1", "items": "three"},
+		"unknown field":    {"order_id": "o-1", "items": 1, "coupon": "x"},
+		"overflow":         {"order_id": "o-1", "items": uint64(1 << 63)},
+	}
+	for name, fields := range cases {
+		if err := log.EmitEvent(context.Background(), "order.created", fields); err == nil {
+			t.Fatalf("%s: expected validation error", name)
+		}
+	}
+	if err := log.EmitEvent(context.Background(), "order.deleted", nil); !errors.Is(err, ErrUnknownEvent) {
+		t.Fatalf("expected ErrUnknownEvent, got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Fatalf("rejected events should not be logged, got %s", buf.String())
+	}
+}
+
+func TestRegisterEventValidatesSchema(t *testing.T) {
+	var buf bytes.Buffer
+	log := newEventSchemaLogger(t, &buf)
+
+	invalid := []EventSchema{
+		{},
+		{Name: "a", Fields: []EventField{{Type: FieldString}}},
+		{Name: "a", Fields: []EventField{{Name: "x"}}},
+		{Name: "a", Fields: []EventField{{Name: "x", Type: FieldInt}, {Name: "x", Type: FieldBool}}},
+	}
+	for i, schema := range invalid {
+		if err := log.RegisterEvent(schema); err == nil {
+			t.Fatalf("schema %d: expected error", i)
+		}
+	}
+}
+
+func TestEmitEventRecordsSpanEvent(t *testing.T) {
+	var buf bytes.Buffer
+	log := newEventSchemaLogger(t, &buf)
+	if err := log.RegisterEvent(orderCreated); err != nil {
+		t.Fatalf("RegisterEvent: %v", err)
+	}
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	ctx, span := tp.Tracer("events").Start(context.Background(), "checkout")
+	if err := log.EmitEvent(ctx, "order.created", map[string]any{"order_id": "o-2", "items": 1}); err != nil {
+		t.Fatalf("EmitEvent: %v", err)
+	}
+	span.End()
+
+	ended := recorder.Ended()[0]
+	if !slices.Contains(spanEventNames(ended), "order.created") {
+		t.Fatalf("expected order.created span event, got %v", spanEventNames(ended))
+	}
+	for _, event := range ended.Events() {
+		if event.Name != "order.created" {
+			continue
+		}
+		if len(event.Attributes) != 2 || event.Attributes[0].Value.AsString() != "o-2" || event.Attributes[1].Value.AsInt64() != 1 {
+			t.Fatalf("unexpected span event attributes: %v", event.Attributes)
+		}
+	}
+}
+
+func TestEventSchemaNilLogger(t *testing.T) {
+	var log *Logger
+	if err := log.RegisterEvent(orderCreated); err != nil {
+		t.Fatalf("RegisterEvent on nil logger: %v", err)
+	}
+	if err := log.EmitEvent(context.Background(), "order.created", nil); err != nil {
+		t.Fatalf("EmitEvent on nil logger: %v", err)
+	}
+}
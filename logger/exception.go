@@ -0,0 +1,35 @@
+package logger
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Exception event modes accepted by Config.ExceptionEvents.
+const (
+	ExceptionEventsOff     = "off"
+	ExceptionEventsAppend  = "append"
+	ExceptionEventsReplace = "replace"
+)
+
+const exceptionEventName = "exception"
+
+// stackText renders the encoded stack field as text, accepting both the frame list and the text
+// that namingWriter renders.
+func stackText(raw []byte) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	if text, ok := jsonString(raw); ok {
+		return text
+	}
+	var frames []map[string]string
+	if err := json.Unmarshal(raw, &frames); err != nil {
+		return string(raw)
+	}
+	var b strings.Builder
+	for _, frame := range frames {
+		writeFrameText(&b, frame["function"], frame["location"])
+	}
+	return b.String()
+}
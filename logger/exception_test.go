@@ -0,0 +1,144 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newExceptionLogger(t *testing.T, mode string) (*Logger, *tracetest.SpanRecorder, *sdktrace.TracerProvider) {
+	t.Helper()
+	log, err := New(context.Background(), Config{
+		Enabled:         true,
+		Console:         false,
+		Writers:         []io.Writer{io.Discard},
+		ExceptionEvents: mode,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() {
+		_ = tp.Shutdown(context.Background())
+		_ = log.Close()
+	})
+	return log, recorder, tp
+}
+
+func spanEventNames(span sdktrace.ReadOnlySpan) []string {
+	names := make([]string, 0, len(span.Events()))
+	for _, event := range span.Events() {
+		names = append(names, event.Name)
+	}
+	return names
+}
+
+func TestExceptionEventsAppend(t *testing.T) {
+	log, recorder, tp := newExceptionLogger(t, ExceptionEventsAppend)
+
+	ctx, span := tp.Tracer("exception").Start(context.Background(), "op")
+	log.Error().Ctx(ctx).Err(pkgerrors.New("db down")).Msg("query failed")
+	span.End()
+
+	ended := recorder.Ended()
+	if len(ended) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(ended))
+	}
+	names := spanEventNames(ended[0])
+	if strings.Join(names, ",") != "log.error,exception" {
+		t.Fatalf("unexpected span events %v", names)
+	}
+
+	attrs := map[string]string{}
+	for _, kv := range ended[0].Events()[1].Attributes {
+		attrs[string(kv.Key)] = kv.Value.AsString()
+	}
+	if attrs["exception.message"] != "db down" {
+		t.Fatalf("unexpected exception.message %q", attrs["exception.message"])
+	}
+	if !strings.Contains(attrs["exception.stacktrace"], "exception_test.go") {
+		t.Fatalf("expected stacktrace to reference caller, got %q", attrs["exception.stacktrace"])
+	}
+}
+
+func TestExceptionEventsReplace(t *testing.T) {
+	log, recorder, tp := newExceptionLogger(t, ExceptionEventsReplace)
+
+	ctx, span := tp.Tracer("exception").Start(context.Background(), "op")
+	log.Error().Ctx(ctx).Err(errors.New("timeout")).Msg("call failed")
+	log.Error().Ctx(ctx).Msg("no error attached")
+	span.End()
+
+	names := spanEventNames(recorder.Ended()[0])
+	if strings.Join(names, ",") != "exception,log.error" {
+		t.Fatalf("unexpected span events %v", names)
+	}
+}
+
+func TestExceptionEventsOffByDefault(t *testing.T) {
+	log, recorder, tp := newExceptionLogger(t, "")
+
+	ctx, span := tp.Tracer("exception").Start(context.Background(), "op")
+	log.Error().Ctx(ctx).Err(errors.New("timeout")).Msg("call failed")
+	span.End()
+
+	names := spanEventNames(recorder.Ended()[0])
+	if strings.Join(names, ",") != "log.error" {
+		t.Fatalf("unexpected span events %v", names)
+	}
+}
+
+type quotaError struct{}
+
+func (quotaError) Error() string { return "quota exceeded" }
+
+func TestExceptionEventsCarryErrorType(t *testing.T) {
+	var out bytes.Buffer
+	log, err := New(context.Background(), Config{
+		Enabled:         true,
+		Console:         false,
+		Writers:         []io.Writer{&out},
+		ExceptionEvents: ExceptionEventsAppend,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() {
+		_ = tp.Shutdown(context.Background())
+		_ = log.Close()
+	})
+
+	ctx, span := tp.Tracer("exception").Start(context.Background(), "op")
+	log.Err(fmt.Errorf("charge: %w", &quotaError{})).Ctx(ctx).Msg("charge failed")
+	log.Err(quotaError{}).Msg("no span")
+	span.End()
+
+	events := recorder.Ended()[0].Events()
+	if len(events) != 2 || events[1].Name != exceptionEventName {
+		t.Fatalf("expected an exception event, got %v", spanEventNames(recorder.Ended()[0]))
+	}
+	attrs := map[string]string{}
+	for _, kv := range events[1].Attributes {
+		attrs[string(kv.Key)] = kv.Value.AsString()
+	}
+	if attrs["exception.type"] != "*fmt.wrapError" {
+		t.Fatalf("unexpected exception.type %q", attrs["exception.type"])
+	}
+	if strings.Contains(out.String(), errorTypeField) {
+		t.Fatalf("expected the error type field to be removed from the lines, got %s", out.String())
+	}
+	if strings.Count(out.String(), "quota exceeded") != 2 {
+		t.Fatalf("expected both entries written, got %s", out.String())
+	}
+}
@@ -14,8 +14,8 @@ func TestExportFailureLoggerSkipsFailingTransport(t *testing.T) {
 	fanout := newWriterRegistry()
 	otlpBuf := &bytes.Buffer{}
 	stdoutBuf := &bytes.Buffer{}
-	fanout.add("http", otlpBuf)
-	fanout.add("stdout", stdoutBuf)
+	fanout.add("http", otlpBuf, WriterTagLocal)
+	fanout.add("stdout", stdoutBuf, WriterTagLocal)
 
 	base := zerolog.New(fanout.writer())
 	log := &Logger{
@@ -66,30 +66,75 @@ func TestWriterRegistryReportsFailures(t *testing.T) {
 	}
 }
 
-func TestFailureExclusionsEmptyTransport(t *testing.T) {
-	got := failureExclusions("logger", "")
-	wanted := map[string]struct{}{
-		"http":    {},
-		"grpc":    {},
-		"file":    {},
-		"stdout":  {},
-		"stderr":  {},
-		"console": {},
-	}
-	if len(got) != len(wanted) {
-		t.Fatalf("expected %d exclusions, got %d", len(wanted), len(got))
+func TestExportFailureLoggerRoutesToLocalWriters(t *testing.T) {
+	fanout := newWriterRegistry()
+	localBuf := &bytes.Buffer{}
+	firstOTLP := &bytes.Buffer{}
+	secondOTLP := &bytes.Buffer{}
+	remoteBuf := &bytes.Buffer{}
+	fanout.add("otlp", firstOTLP, WriterTagOTLP, WriterTagNetwork)
+	fanout.add("otlp_backup", secondOTLP, WriterTagOTLP, WriterTagNetwork)
+	fanout.add("shipper", remoteBuf, WriterTagNetwork)
+	fanout.add("custom_0", localBuf, WriterTagLocal)
+
+	base := zerolog.New(fanout.writer())
+	log := &Logger{Logger: &base, writers: fanout}
+
+	exportFailureLogger(log)("logger", "grpc", errors.New("boom"))
+
+	if localBuf.Len() == 0 {
+		t.Fatal("expected local writer to receive failure log")
 	}
-	for _, entry := range got {
-		if _, ok := wanted[entry]; !ok {
-			t.Fatalf("unexpected exclusion %q", entry)
+	for name, buf := range map[string]*bytes.Buffer{"otlp": firstOTLP, "otlp_backup": secondOTLP, "shipper": remoteBuf} {
+		if buf.Len() != 0 {
+			t.Fatalf("expected %s writer to be skipped, got %q", name, buf.String())
 		}
 	}
 }
 
-func TestWriterRegistryWriterExceptFallback(t *testing.T) {
+func TestSDKErrorLoggerRoutesToLocalWriters(t *testing.T) {
+	fanout := newWriterRegistry()
+	localBuf := &bytes.Buffer{}
+	otlpBuf := &bytes.Buffer{}
+	fanout.add("otlp", otlpBuf, WriterTagOTLP, WriterTagNetwork)
+	fanout.add("stdout", localBuf, WriterTagLocal)
+
+	base := zerolog.New(fanout.writer())
+	log := &Logger{Logger: &base, writers: fanout}
+
+	sdkErrorLogger(log)(errors.New("instrument conflict"))
+
+	if otlpBuf.Len() != 0 {
+		t.Fatalf("expected otlp writer to be skipped, got %q", otlpBuf.String())
+	}
+	if !bytes.Contains(localBuf.Bytes(), []byte(`"component":"otel"`)) || !bytes.Contains(localBuf.Bytes(), []byte("instrument conflict")) {
+		t.Fatalf("expected sdk error in local writer, got %q", localBuf.String())
+	}
+}
+
+type taggedBuffer struct {
+	bytes.Buffer
+	tags []string
+}
+
+func (b *taggedBuffer) WriterTags() []string { return b.tags }
+
+func TestWriterRegistryDeclaredTags(t *testing.T) {
+	fanout := newWriterRegistry()
+	declared := &taggedBuffer{tags: []string{"Network"}}
+	fanout.add("custom_0", NewFormatWriter(declared, FormatJSON), WriterTagLocal)
+
+	writers := fanout.snapshot()
+	if len(writers) != 1 || !writers[0].hasTag(WriterTagNetwork) || writers[0].hasTag(WriterTagLocal) {
+		t.Fatalf("expected declared network tag to replace local, got %v", writers[0].tags)
+	}
+}
+
+func TestWriterRegistryWriterTaggedFallback(t *testing.T) {
 	fanout := newWriterRegistry()
-	fanout.add("stdout", &bytes.Buffer{})
-	writer := fanout.writerExcept("stdout")
+	fanout.add("stdout", &bytes.Buffer{}, WriterTagLocal)
+	fanout.add("otlp", &bytes.Buffer{}, WriterTagOTLP, WriterTagNetwork)
+	writer := fanout.writerTagged(WriterTagLocal, "stdout")
 	file, ok := writer.(*os.File)
 	if !ok || file != os.Stderr {
 		t.Fatalf("expected os.Stderr fallback, got %#v", writer)
@@ -0,0 +1,278 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"runtime"
+	"strings"
+
+	"github.com/creasty/defaults"
+	"github.com/rs/zerolog"
+)
+
+// reservedFields are the keys zerolog writes itself.
+type reservedFields struct {
+	time    string
+	level   string
+	message string
+	error   string
+	stack   string
+}
+
+// fieldNaming holds the keys and stack format of one logger, resolved from Config.Fields,
+// SemconvFields, Profile, FieldNames, and Stack. zerolog encodes its own fields under its global
+// names; namingWriter renames them per logger, so loggers with different naming share a process.
+type fieldNaming struct {
+	// from holds zerolog's names when the logger was built, to holds the ones the logger writes.
+	from reservedFields
+	to   reservedFields
+
+	traceID      string
+	spanID       string
+	sampled      string
+	serviceName  string
+	environment  string
+	warnEvent    string
+	errorEvent   string
+	eventMessage string
+
+	// textStacks renders the stack field as one string instead of an array of frames.
+	textStacks bool
+	// datadogIDs adds the decimal Datadog trace and span IDs next to the hex ones.
+	datadogIDs bool
+	// stack filters and links the Go frames of the stack field and of error_chain.
+	stack stackFilter
+
+	// renames holds the JSON-quoted target of every reserved key that changes name.
+	renames []fieldRename
+	// stackMarker finds lines carrying a stack without decoding them.
+	stackMarker []byte
+}
+
+type fieldRename struct {
+	from string
+	to   []byte
+}
+
+// defaultNaming is used by the writers built outside New, such as NewFormatWriter.
+var defaultNaming = newFieldNaming(Config{})
+
+// newFieldNaming resolves the naming of cfg. FieldNames wins over Profile, which wins over
+// SemconvFields, which wins over Fields.
+func newFieldNaming(cfg Config) *fieldNaming {
+	zerologNames := reservedFields{
+		time:    zerolog.TimestampFieldName,
+		level:   zerolog.LevelFieldName,
+		message: zerolog.MessageFieldName,
+		error:   zerolog.ErrorFieldName,
+		stack:   zerolog.ErrorStackFieldName,
+	}
+	var fields FieldConfig
+	_ = defaults.Set(&fields)
+	n := &fieldNaming{
+		from:         zerologNames,
+		to:           zerologNames,
+		traceID:      fields.TraceID,
+		spanID:       fields.SpanID,
+		sampled:      fields.Sampled,
+		serviceName:  fields.ServiceName,
+		environment:  fields.DeploymentEnvironment,
+		warnEvent:    fields.Internal.WarnEvent,
+		errorEvent:   fields.Internal.ErrorEvent,
+		eventMessage: fields.Internal.EventMessageAttr,
+		stack:        newStackFilter(cfg.Stack),
+	}
+	override(&n.traceID, cfg.Fields.TraceID)
+	override(&n.spanID, cfg.Fields.SpanID)
+	override(&n.sampled, cfg.Fields.Sampled)
+	override(&n.serviceName, cfg.Fields.ServiceName)
+	override(&n.environment, cfg.Fields.DeploymentEnvironment)
+	override(&n.warnEvent, cfg.Fields.Internal.WarnEvent)
+	override(&n.errorEvent, cfg.Fields.Internal.ErrorEvent)
+	override(&n.eventMessage, cfg.Fields.Internal.EventMessageAttr)
+	if cfg.SemconvFields {
+		n.applySemconv()
+	}
+	n.applyProfile(cfg.Profile)
+	n.applyFieldNames(profileFieldNames(cfg.Profile, cfg.FieldNames))
+
+	for _, pair := range [][2]string{
+		{n.from.time, n.to.time},
+		{n.from.level, n.to.level},
+		{n.from.message, n.to.message},
+		{n.from.error, n.to.error},
+		{n.from.stack, n.to.stack},
+	} {
+		if pair[0] != pair[1] {
+			quoted, _ := json.Marshal(pair[1])
+			n.renames = append(n.renames, fieldRename{from: pair[0], to: quoted})
+		}
+	}
+	n.stackMarker = []byte(`"` + n.from.stack + `":`)
+	return n
+}
+
+func override(field *string, value string) {
+	if value != "" {
+		*field = value
+	}
+}
+
+// applyFieldNames installs the FieldNames overrides. Names left empty keep the value resolved so far.
+func (n *fieldNaming) applyFieldNames(names FieldNamesConfig) {
+	override(&n.to.time, names.Time)
+	override(&n.to.level, names.Level)
+	override(&n.to.message, names.Message)
+	override(&n.traceID, names.TraceID)
+	override(&n.spanID, names.SpanID)
+	override(&n.serviceName, names.ServiceName)
+}
+
+// eventName returns the span event name of an entry logged at level.
+func (n *fieldNaming) eventName(level zerolog.Level) string {
+	switch {
+	case level >= zerolog.ErrorLevel:
+		return n.errorEvent
+	case level == zerolog.WarnLevel:
+		return n.warnEvent
+	default:
+		return "log." + level.String()
+	}
+}
+
+// coreField reports the keys the OTLP writer maps to record fields or the resource rather than to
+// attributes.
+func (n *fieldNaming) coreField(key string) bool {
+	switch key {
+	case n.to.time, n.to.level, n.to.message, n.traceID, n.spanID, n.sampled, n.serviceName, n.environment,
+		ServiceInstanceIDKey, ProcessRunIDKey:
+		return true
+	default:
+		return false
+	}
+}
+
+// public reports the naming as FieldNaming.
+func (n *fieldNaming) public() FieldNaming {
+	return FieldNaming{
+		Time:             n.to.time,
+		Level:            n.to.level,
+		Message:          n.to.message,
+		TraceID:          n.traceID,
+		SpanID:           n.spanID,
+		ServiceName:      n.serviceName,
+		Environment:      n.environment,
+		ServiceNameLabel: lokiServiceNameLabel,
+	}
+}
+
+// namingWriter renames zerolog's keys and renders the stack field of each line for one logger.
+type namingWriter struct {
+	next   io.Writer
+	naming *fieldNaming
+}
+
+func (w namingWriter) Write(p []byte) (int, error) {
+	if _, err := w.next.Write(w.naming.rewrite(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// writer wraps next with the renaming of n.
+func (n *fieldNaming) writer(next io.Writer) io.Writer {
+	return namingWriter{next: next, naming: n}
+}
+
+// rewrite returns line with the reserved keys renamed and the stack rendered. Lines that need
+// neither, or that are not a JSON object, are returned as they are.
+func (n *fieldNaming) rewrite(line []byte) []byte {
+	if len(n.renames) == 0 && !bytes.Contains(line, n.stackMarker) {
+		return line
+	}
+	payload := bytes.TrimRight(line, "\r\n")
+	out := make([]byte, 0, len(line)+64)
+	out = append(out, '{')
+	ok := scanJSONObject(payload, func(key, value []byte) {
+		if keyIs(key, n.from.stack) {
+			if value = n.renderStack(value); value == nil {
+				return
+			}
+		}
+		if len(out) > 1 {
+			out = append(out, ',')
+		}
+		renamed := false
+		for _, rename := range n.renames {
+			if keyIs(key, rename.from) {
+				out = append(out, rename.to...)
+				renamed = true
+				break
+			}
+		}
+		if !renamed {
+			out = append(append(append(out, '"'), key...), '"')
+		}
+		out = append(append(out, ':'), value...)
+	})
+	if !ok {
+		return line
+	}
+	out = append(out, '}')
+	return append(out, line[len(payload):]...)
+}
+
+// recordedFrame is a stack frame as marshalStackTrace records it. Go frames carry File and Line and
+// are rendered per logger; frames parsed from another runtime carry Location and are kept as written.
+type recordedFrame struct {
+	Function string  `json:"function,omitempty"`
+	File     string  `json:"file,omitempty"`
+	Line     int     `json:"line,omitempty"`
+	Location *string `json:"location,omitempty"`
+}
+
+// renderStack turns the recorded frames into the logger's stack format: an array of function and
+// location objects, or the text of a Go panic trace. Values that are not recorded frames are kept.
+// It returns nil when no frame is left to write as text.
+func (n *fieldNaming) renderStack(raw []byte) []byte {
+	var recorded []recordedFrame
+	if err := json.Unmarshal(raw, &recorded); err != nil {
+		return raw
+	}
+	var frames []runtime.Frame
+	var external []map[string]any
+	for _, frame := range recorded {
+		if frame.Location != nil {
+			external = append(external, stackEntry(frame.Function, *frame.Location))
+			continue
+		}
+		frames = append(frames, runtime.Frame{Function: frame.Function, File: frame.File, Line: frame.Line})
+	}
+	frames = n.stack.apply(frames)
+	entries := make([]map[string]any, 0, len(frames)+len(external))
+	for _, frame := range frames {
+		entries = append(entries, stackEntry(frame.Function, n.stack.location(frame)))
+	}
+	// Parsed frames name files of another process or runtime, so they are kept as written.
+	entries = append(entries, external...)
+
+	var rendered any = entries
+	if n.textStacks {
+		var b strings.Builder
+		for _, entry := range entries {
+			function, _ := entry["function"].(string)
+			location, _ := entry["location"].(string)
+			writeFrameText(&b, function, location)
+		}
+		if b.Len() == 0 {
+			return nil
+		}
+		rendered = b.String()
+	}
+	encoded, err := json.Marshal(rendered)
+	if err != nil {
+		return raw
+	}
+	return encoded
+}
@@ -0,0 +1,143 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	otelLog "go.opentelemetry.io/otel/log"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestFieldNamesRenameCoreKeys(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := New(context.Background(), Config{
+		Enabled:     true,
+		Console:     false,
+		ServiceName: "checkout",
+		Writers:     []io.Writer{&buf},
+		FieldNames: FieldNamesConfig{
+			Time:        "@timestamp",
+			Level:       "log.level",
+			Message:     "msg",
+			TraceID:     "trace.id",
+			SpanID:      "span.id",
+			ServiceName: "service.name",
+		},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = log.Close() })
+
+	tp := sdktrace.NewTracerProvider()
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	ctx, span := tp.Tracer("fieldnames").Start(context.Background(), "op")
+	log.Info().Ctx(ctx).Msg("renamed")
+	span.End()
+
+	raw := bytes.TrimSpace(buf.Bytes())
+	var entry map[string]any
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		t.Fatalf("decode entry: %v", err)
+	}
+	for key, want := range map[string]any{
+		"log.level":    "info",
+		"msg":          "renamed",
+		"trace.id":     span.SpanContext().TraceID().String(),
+		"span.id":      span.SpanContext().SpanID().String(),
+		"service.name": "checkout",
+	} {
+		if got := entry[key]; got != want {
+			t.Fatalf("%s: got %v want %v (entry %v)", key, got, want, entry)
+		}
+	}
+	if _, ok := entry["@timestamp"]; !ok {
+		t.Fatalf("expected @timestamp key, got %v", entry)
+	}
+
+	record, spanCtx := buildRecord(raw, time.Now(), log.naming)
+	if record.Body().AsString() != "renamed" || record.Severity() != otelLog.SeverityInfo {
+		t.Fatalf("unexpected record body %q severity %v", record.Body().AsString(), record.Severity())
+	}
+	if record.Timestamp().IsZero() {
+		t.Fatal("expected record timestamp from @timestamp")
+	}
+	if spanCtx.TraceID() != span.SpanContext().TraceID() || spanCtx.SpanID() != span.SpanContext().SpanID() {
+		t.Fatalf("unexpected span context %v", spanCtx)
+	}
+	record.WalkAttributes(func(kv otelLog.KeyValue) bool {
+		if log.naming.coreField(kv.Key) {
+			t.Fatalf("core field %q leaked into attributes", kv.Key)
+		}
+		return true
+	})
+}
+
+func TestLoggersKeepTheirOwnFieldNames(t *testing.T) {
+	var first, second bytes.Buffer
+	renamed, err := New(context.Background(), Config{
+		Enabled:       true,
+		Console:       false,
+		Writers:       []io.Writer{&first},
+		SemconvFields: true,
+		Profile:       ProfileDatadog,
+		Fields:        FieldConfig{Sampled: "trace_sampled"},
+		FieldNames:    FieldNamesConfig{Message: "text"},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = renamed.Close() })
+	plain, err := New(context.Background(), Config{Enabled: true, Console: false, Writers: []io.Writer{&second}})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = plain.Close() })
+
+	tp := sdktrace.NewTracerProvider()
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	ctx, span := tp.Tracer("fieldnames").Start(context.Background(), "op")
+	renamed.Err(errors.New("boom")).Ctx(ctx).Msg("kept")
+	plain.Err(errors.New("boom")).Ctx(ctx).Msg("default")
+	span.End()
+
+	var entry map[string]any
+	if err := json.Unmarshal(bytes.TrimSpace(first.Bytes()), &entry); err != nil {
+		t.Fatalf("decode entry: %v", err)
+	}
+	for _, key := range []string{"text", "severity_text", "trace_sampled", datadogTraceIDField} {
+		if _, ok := entry[key]; !ok {
+			t.Fatalf("expected %s to survive a later logger without the setting, got %v", key, entry)
+		}
+	}
+	if _, ok := entry["exception.stacktrace"].(string); !ok {
+		t.Fatalf("expected a textual stacktrace, got %v", entry)
+	}
+
+	entry = nil
+	if err := json.Unmarshal(bytes.TrimSpace(second.Bytes()), &entry); err != nil {
+		t.Fatalf("decode entry: %v", err)
+	}
+	for _, key := range []string{"message", "level", "sampled", "error"} {
+		if _, ok := entry[key]; !ok {
+			t.Fatalf("expected %s on a logger without renaming, got %v", key, entry)
+		}
+	}
+	if _, ok := entry["stack"].([]any); !ok {
+		t.Fatalf("expected stack frames on a logger without text stacks, got %v", entry)
+	}
+	if _, ok := entry[datadogTraceIDField]; ok {
+		t.Fatalf("expected no Datadog IDs on a logger without the profile, got %v", entry)
+	}
+	if naming := plain.FieldNaming(); naming.Message != "message" || naming.TraceID != "trace_id" {
+		t.Fatalf("unexpected naming %+v", naming)
+	}
+	if naming := renamed.FieldNaming(); naming.Message != "text" || naming.Level != "severity_text" {
+		t.Fatalf("unexpected naming %+v", naming)
+	}
+}
@@ -94,3 +94,63 @@ func TestFileLoggerRecreatesDeletedFile(t *testing.T) {
 		t.Fatalf("unexpected message: %v", got)
 	}
 }
+
+func TestFileLoggerUsesFilenameTemplate(t *testing.T) {
+	dir := t.TempDir()
+	log, err := New(context.Background(), Config{
+		Enabled:     true,
+		ServiceName: "billing",
+		Console:     false,
+		File: FileConfig{
+			Enabled:   true,
+			Directory: dir,
+			Buffer:    4,
+			Timezone:  "UTC",
+			Filename:  "{service}-%Y%m%d.log",
+		},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = log.Close() })
+
+	log.Info().Msg("templated")
+	waitForFileEntry(t, filepath.Join(dir, "billing-"+time.Now().UTC().Format("20060102")+".log"), "templated")
+}
+
+func TestFileWriterNamesRotationPeriod(t *testing.T) {
+	w, err := newDailyFileWriter(context.Background(), FileConfig{
+		Directory: t.TempDir(),
+		Rotation:  FileRotationHourly,
+		Timezone:  "UTC",
+		Filename:  "app-{service}-%Y-%m-%d-%H%%.log",
+	}, Config{ServiceName: "svc"})
+	if err != nil {
+		t.Fatalf("newDailyFileWriter: %v", err)
+	}
+	t.Cleanup(func() { _ = w.Close() })
+
+	at := time.Date(2024, 6, 2, 23, 30, 0, 0, time.FixedZone("EST", -5*60*60))
+	if got := w.name(at); got != "app-svc-2024-06-03-04%.log" {
+		t.Fatalf("unexpected hourly name %q", got)
+	}
+	if got := w.name(at.Add(29 * time.Minute)); got != "app-svc-2024-06-03-04%.log" {
+		t.Fatalf("expected same period, got %q", got)
+	}
+	if got := w.name(at.Add(31 * time.Minute)); got != "app-svc-2024-06-03-05%.log" {
+		t.Fatalf("expected next period, got %q", got)
+	}
+}
+
+func TestFileWriterRejectsBadNaming(t *testing.T) {
+	if _, err := newDailyFileWriter(context.Background(), FileConfig{Directory: t.TempDir(), Timezone: "Nowhere/City"}, Config{}); err == nil {
+		t.Fatal("expected unknown timezone to fail")
+	}
+	if _, err := newDailyFileWriter(context.Background(), FileConfig{Directory: t.TempDir(), Filename: "{service}.log"}, Config{ServiceName: "../up"}); err == nil {
+		t.Fatal("expected service name with a path to fail")
+	}
+	cfg := Config{Enabled: true, File: FileConfig{Enabled: true, Directory: t.TempDir(), Filename: "logs/%Y.log"}}
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected filename with a directory to fail validation")
+	}
+}
@@ -0,0 +1,156 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestFileWriterBatchesQueuedEntries(t *testing.T) {
+	dir := t.TempDir()
+	w, err := newDailyFileWriter(context.Background(), FileConfig{
+		Directory: dir,
+		Filename:  "app.log",
+		BatchSize: 16,
+		Sync:      FileSyncEveryWrite,
+	}, Config{})
+	if err != nil {
+		t.Fatalf("newDailyFileWriter: %v", err)
+	}
+
+	// Hold the file lock so the entries pile up behind the first one.
+	w.mu.Lock()
+	for range 10 {
+		if _, err := w.Write([]byte("entry\n")); err != nil {
+			w.mu.Unlock()
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	w.mu.Unlock()
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "app.log"))
+	if err != nil {
+		t.Fatalf("read log file: %v", err)
+	}
+	if got := strings.Count(string(data), "entry\n"); got != 10 {
+		t.Fatalf("expected 10 entries, got %d", got)
+	}
+	writes, took := w.Writes()
+	if writes == 0 || writes > 2 {
+		t.Fatalf("expected the queued entries written in at most 2 batches, got %d", writes)
+	}
+	if took <= 0 {
+		t.Fatalf("expected write latency to be measured, got %v", took)
+	}
+	if syncs, _ := w.Syncs(); syncs < writes {
+		t.Fatalf("expected an fsync per write, got %d for %d writes", syncs, writes)
+	}
+}
+
+func TestFileWriterSplitsBatchesAtRotation(t *testing.T) {
+	dir := t.TempDir()
+	clk := &dedupClock{now: time.Date(2024, 3, 1, 10, 59, 59, 0, time.UTC)}
+	w, err := newDailyFileWriter(context.Background(), FileConfig{
+		Directory: dir,
+		Filename:  "%H.log",
+		Rotation:  FileRotationHourly,
+		Timezone:  "UTC",
+		BatchSize: 16,
+	}, Config{Clock: clk})
+	if err != nil {
+		t.Fatalf("newDailyFileWriter: %v", err)
+	}
+
+	// Hold the file lock so entries from both hours are queued before any is written.
+	w.mu.Lock()
+	for idx := range 6 {
+		if idx == 3 {
+			clk.advance(time.Second)
+		}
+		if _, err := w.Write([]byte("entry\n")); err != nil {
+			w.mu.Unlock()
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	w.mu.Unlock()
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	for _, name := range []string{"10.log", "11.log"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		if got := strings.Count(string(data), "entry\n"); got != 3 {
+			t.Fatalf("expected 3 entries in %s, got %d", name, got)
+		}
+	}
+}
+
+func TestLoggerReportsFileWritesAndSyncs(t *testing.T) {
+	log, err := New(context.Background(), Config{
+		Enabled: true,
+		Console: false,
+		File: FileConfig{
+			Enabled:   true,
+			Directory: t.TempDir(),
+			Sync:      FileSyncEveryWrite,
+		},
+		Delivery: DeliveryConfig{Mode: DeliveryOrdered},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = log.Close() })
+
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	t.Cleanup(func() { _ = mp.Shutdown(context.Background()) })
+	if err := log.BindMeter(mp.Meter("test")); err != nil {
+		t.Fatalf("BindMeter: %v", err)
+	}
+
+	log.Info().Msg("written in the caller")
+	stats := log.Stats()
+	if stats.FileWrites != 1 || stats.FileWriteTime <= 0 {
+		t.Fatalf("expected one measured write, got %d in %v", stats.FileWrites, stats.FileWriteTime)
+	}
+	if stats.FileSyncs != 1 || stats.FileSyncTime <= 0 {
+		t.Fatalf("expected one measured fsync, got %d in %v", stats.FileSyncs, stats.FileSyncTime)
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	counts := make(map[string]uint64)
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if histogram, ok := m.Data.(metricdata.Histogram[float64]); ok {
+				for _, point := range histogram.DataPoints {
+					counts[m.Name] += point.Count
+				}
+			}
+		}
+	}
+	if counts[FileWriteDurationName] != 1 || counts[FileSyncDurationName] != 1 {
+		t.Fatalf("expected one write and one fsync recorded, got %v", counts)
+	}
+}
+
+func TestFileConfigRejectsUnknownSyncPolicy(t *testing.T) {
+	cfg := Config{Enabled: true, File: FileConfig{Enabled: true, Directory: t.TempDir(), Sync: "sometimes"}}
+	if _, err := New(context.Background(), cfg); err == nil {
+		t.Fatal("expected an error for an unknown sync policy")
+	}
+}
@@ -2,38 +2,134 @@ package logger
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/mfahmialkautsar/goo11y/clock"
+	"go.opentelemetry.io/otel/metric"
 )
 
 const (
+	defaultFileName         = "%Y-%m-%d.log"
+	defaultHourlyFileName   = "%Y-%m-%d-%H.log"
 	defaultFileWriterBuffer = 1024
+	defaultFileSyncInterval = time.Second
 	fileWriterDirMode       = 0o755
 	fileWriterFileMode      = 0o644
 )
 
+// FileWriteDurationName and FileSyncDurationName are the histograms BindMeter records file writes
+// and fsyncs in, in seconds.
+const (
+	FileWriteDurationName = "goo11y.logger.file.write.duration"
+	FileSyncDurationName  = "goo11y.logger.file.sync.duration"
+)
+
+// fileLatencyBuckets span a cached write of a few microseconds up to a stalled fsync.
+var fileLatencyBuckets = []float64{0.00001, 0.00005, 0.0001, 0.0005, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1, 5}
+
+// fileEntry is a queued payload with the time it was logged, which picks its rotation period.
+type fileEntry struct {
+	payload []byte
+	at      time.Time
+}
+
+// fileHistograms record the latency of each file write and fsync.
+type fileHistograms struct {
+	write metric.Float64Histogram
+	sync  metric.Float64Histogram
+}
+
+func newFileHistograms(meter metric.Meter) (*fileHistograms, error) {
+	write, err := meter.Float64Histogram(
+		FileWriteDurationName,
+		metric.WithDescription("Time the file writer spent writing a batch of entries to the log file"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(fileLatencyBuckets...),
+	)
+	if err != nil {
+		return nil, err
+	}
+	sync, err := meter.Float64Histogram(
+		FileSyncDurationName,
+		metric.WithDescription("Time the file writer spent in fsync of the log file"),
+		metric.WithUnit("s"),
+		metric.WithExplicitBucketBoundaries(fileLatencyBuckets...),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &fileHistograms{write: write, sync: sync}, nil
+}
+
 type dailyFileWriter struct {
 	directory string
-	queue     chan []byte
-	now       func() time.Time
+	queue     chan fileEntry
+	blocking  bool
+	// direct writes in the caller instead of through queue, when Delivery orders or queues entries itself.
+	direct    bool
+	directMu  sync.Mutex
+	dropped   atomic.Uint64
+	clock     clock.Clock
+	location  *time.Location
+	hourly    bool
+	filename  string
 	ctx       context.Context
 	cancel    context.CancelFunc
 	wg        sync.WaitGroup
 	closeOnce sync.Once
 
+	// batchSize, sync, and syncInterval come from FileConfig; syncInterval is zero unless the
+	// policy is FileSyncInterval.
+	batchSize    int
+	sync         string
+	syncInterval time.Duration
+	writes       atomic.Uint64
+	writeNanos   atomic.Int64
+	syncs        atomic.Uint64
+	syncNanos    atomic.Int64
+	histograms   atomic.Pointer[fileHistograms]
+
 	mu          sync.Mutex
-	currentDate string
+	currentName string
 	file        *os.File
 }
 
-func newDailyFileWriter(ctx context.Context, cfg FileConfig) (*dailyFileWriter, error) {
+func newDailyFileWriter(ctx context.Context, cfg FileConfig, identity Config) (*dailyFileWriter, error) {
 	if cfg.Directory == "" {
 		return nil, fmt.Errorf("missing file log directory")
 	}
 
+	location := time.Local
+	if cfg.Timezone != "" {
+		loaded, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("file log timezone: %w", err)
+		}
+		location = loaded
+	}
+	hourly := cfg.Rotation == FileRotationHourly
+	filename := cfg.Filename
+	if filename == "" {
+		filename = defaultFileName
+		if hourly {
+			filename = defaultHourlyFileName
+		}
+	}
+	filename = strings.ReplaceAll(filename, "{service}", identity.ServiceName)
+	if strings.ContainsAny(filename, `/\`) || filepath.Base(filename) != filename {
+		return nil, fmt.Errorf("file log name %q must not contain a path", cfg.Filename)
+	}
+
 	buffer := cfg.Buffer
 	if buffer <= 0 {
 		buffer = defaultFileWriterBuffer
@@ -45,12 +141,28 @@ func newDailyFileWriter(ctx context.Context, cfg FileConfig) (*dailyFileWriter,
 		cancel()
 	}
 
+	syncInterval := time.Duration(0)
+	if cfg.Sync == FileSyncInterval {
+		syncInterval = cfg.SyncInterval
+		if syncInterval <= 0 {
+			syncInterval = defaultFileSyncInterval
+		}
+	}
+
 	w := &dailyFileWriter{
-		directory: cfg.Directory,
-		queue:     make(chan []byte, buffer),
-		now:       time.Now,
-		ctx:       subCtx,
-		cancel:    cancel,
+		batchSize:    max(cfg.BatchSize, 1),
+		sync:         cfg.Sync,
+		syncInterval: syncInterval,
+		directory:    cfg.Directory,
+		queue:        make(chan fileEntry, buffer),
+		blocking:     cfg.Mode != FileModeDrop,
+		clock:        clock.OrReal(identity.Clock),
+		location:     location,
+		hourly:       hourly,
+		filename:     filename,
+		ctx:          subCtx,
+		cancel:       cancel,
+		direct:       identity.Delivery.Mode == DeliveryOrdered || identity.Delivery.Mode == DeliveryAsync,
 	}
 
 	w.wg.Add(1)
@@ -64,8 +176,23 @@ func (w *dailyFileWriter) Write(p []byte) (int, error) {
 		return 0, nil
 	}
 
+	if w.direct {
+		w.directMu.Lock()
+		defer w.directMu.Unlock()
+		select {
+		case <-w.ctx.Done():
+			return 0, fmt.Errorf("file writer closed")
+		default:
+		}
+		if err := w.write(w.name(w.clock.Now()), p); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+
 	copyBuf := make([]byte, len(p))
 	copy(copyBuf, p)
+	entry := fileEntry{payload: copyBuf, at: w.clock.Now()}
 
 	defer func() {
 		if r := recover(); r != nil {
@@ -82,14 +209,48 @@ func (w *dailyFileWriter) Write(p []byte) (int, error) {
 	default:
 	}
 
+	if !w.blocking {
+		select {
+		case w.queue <- entry:
+		default:
+			w.dropped.Add(1)
+		}
+		return len(p), nil
+	}
+
 	select {
-	case w.queue <- copyBuf:
+	case w.queue <- entry:
 		return len(p), nil
 	case <-w.ctx.Done():
 		return 0, fmt.Errorf("file writer closed")
 	}
 }
 
+// Dropped reports how many entries were discarded because the queue was full.
+func (w *dailyFileWriter) Dropped() uint64 {
+	return w.dropped.Load()
+}
+
+// Writes reports how many batches were written to the file and the total time the writes took.
+func (w *dailyFileWriter) Writes() (uint64, time.Duration) {
+	return w.writes.Load(), time.Duration(w.writeNanos.Load())
+}
+
+// Syncs reports how many fsyncs were done and the total time they took.
+func (w *dailyFileWriter) Syncs() (uint64, time.Duration) {
+	return w.syncs.Load(), time.Duration(w.syncNanos.Load())
+}
+
+// bindMeter records every later write and fsync in histograms.
+func (w *dailyFileWriter) bindMeter(histograms *fileHistograms) {
+	w.histograms.Store(histograms)
+}
+
+// Queued reports how many entries are waiting for the background writer.
+func (w *dailyFileWriter) Queued() int {
+	return len(w.queue)
+}
+
 func (w *dailyFileWriter) Close() error {
 	var err error
 	w.closeOnce.Do(func() {
@@ -101,6 +262,9 @@ func (w *dailyFileWriter) Close() error {
 		defer w.mu.Unlock()
 
 		if w.file != nil {
+			if w.sync != "" && w.sync != FileSyncNever {
+				_ = w.fsync(w.file)
+			}
 			err = w.file.Close()
 			w.file = nil
 		}
@@ -110,25 +274,108 @@ func (w *dailyFileWriter) Close() error {
 
 func (w *dailyFileWriter) run() {
 	defer w.wg.Done()
-	for payload := range w.queue {
-		if err := w.write(payload); err != nil {
+	var tick <-chan time.Time
+	if w.syncInterval > 0 {
+		ticker := w.clock.NewTicker(w.syncInterval)
+		defer ticker.Stop()
+		tick = ticker.C()
+	}
+
+	var (
+		batch []byte
+		next  fileEntry
+		carry bool
+	)
+	for {
+		// An entry that ended the previous batch by starting a new rotation period opens the next
+		// one.
+		entry := next
+		if !carry {
+			select {
+			case queued, ok := <-w.queue:
+				if !ok {
+					w.closeFile()
+					return
+				}
+				entry = queued
+			case <-tick:
+				if err := w.syncFile(); err != nil {
+					fmt.Fprintf(os.Stderr, "goo11y logger file writer error: %v\n", err)
+				}
+				continue
+			}
+		}
+		period := w.period(entry.at)
+		batch = append(batch[:0], entry.payload...)
+		batch, next, carry = w.fillBatch(batch, period)
+		if err := w.write(expandFileName(w.filename, period), batch); err != nil {
 			fmt.Fprintf(os.Stderr, "goo11y logger file writer error: %v\n", err)
 		}
 	}
+}
+
+// fillBatch appends entries already waiting in the queue to batch, up to batchSize entries, as
+// long as they were logged in the same rotation period. The first entry of a later period is
+// returned with carry set, to start the next batch.
+func (w *dailyFileWriter) fillBatch(batch []byte, period time.Time) (_ []byte, next fileEntry, carry bool) {
+	for count := 1; count < w.batchSize; count++ {
+		select {
+		case entry, ok := <-w.queue:
+			if !ok {
+				return batch, fileEntry{}, false
+			}
+			if !w.period(entry.at).Equal(period) {
+				return batch, entry, true
+			}
+			batch = append(batch, entry.payload...)
+		default:
+			return batch, fileEntry{}, false
+		}
+	}
+	return batch, fileEntry{}, false
+}
 
+func (w *dailyFileWriter) closeFile() {
 	w.mu.Lock()
+	defer w.mu.Unlock()
 	if w.file != nil {
+		if w.sync != "" && w.sync != FileSyncNever {
+			_ = w.fsync(w.file)
+		}
 		_ = w.file.Close()
 		w.file = nil
 	}
-	w.mu.Unlock()
 }
 
-func (w *dailyFileWriter) write(payload []byte) error {
-	now := w.now()
-	currentDate := now.Format("2006-01-02")
+// syncFile fsyncs the current file for the interval policy.
+func (w *dailyFileWriter) syncFile() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	if err := w.fsync(w.file); err != nil {
+		return fmt.Errorf("sync log file: %w", err)
+	}
+	return nil
+}
 
-	if err := w.ensureFile(currentDate); err != nil {
+// fsync syncs file to disk and records how long it took.
+func (w *dailyFileWriter) fsync(file *os.File) error {
+	start := time.Now()
+	err := file.Sync()
+	took := time.Since(start)
+	w.syncs.Add(1)
+	w.syncNanos.Add(int64(took))
+	if histograms := w.histograms.Load(); histograms != nil {
+		histograms.sync.Record(context.Background(), took.Seconds())
+	}
+	return err
+}
+
+// write appends payload to the file called name, opening it first when the period rolled over.
+func (w *dailyFileWriter) write(name string, payload []byte) error {
+	if err := w.ensureFile(name); err != nil {
 		return err
 	}
 
@@ -140,19 +387,156 @@ func (w *dailyFileWriter) write(payload []byte) error {
 		return fmt.Errorf("file handle unavailable")
 	}
 
-	if _, err := file.Write(payload); err != nil {
+	start := time.Now()
+	_, err := file.Write(payload)
+	took := time.Since(start)
+	w.writes.Add(1)
+	w.writeNanos.Add(int64(took))
+	if histograms := w.histograms.Load(); histograms != nil {
+		histograms.write.Record(context.Background(), took.Seconds())
+	}
+	if err != nil {
 		return fmt.Errorf("write log file: %w", err)
 	}
+	if w.sync == FileSyncEveryWrite {
+		if err := w.fsync(file); err != nil {
+			return fmt.Errorf("sync log file: %w", err)
+		}
+	}
 
 	return nil
 }
 
-func (w *dailyFileWriter) ensureFile(date string) error {
+// name renders the file name for the rotation period containing now.
+func (w *dailyFileWriter) name(now time.Time) string {
+	return expandFileName(w.filename, w.period(now))
+}
+
+// period returns the start of the rotation period containing now.
+func (w *dailyFileWriter) period(now time.Time) time.Time {
+	now = now.In(w.location)
+	hour := 0
+	if w.hourly {
+		hour = now.Hour()
+	}
+	return time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, w.location)
+}
+
+func expandFileName(pattern string, start time.Time) string {
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		if pattern[i] != '%' || i+1 == len(pattern) {
+			b.WriteByte(pattern[i])
+			continue
+		}
+		i++
+		switch pattern[i] {
+		case 'Y':
+			b.WriteString(strconv.Itoa(start.Year()))
+		case 'm':
+			fmt.Fprintf(&b, "%02d", int(start.Month()))
+		case 'd':
+			fmt.Fprintf(&b, "%02d", start.Day())
+		case 'H':
+			fmt.Fprintf(&b, "%02d", start.Hour())
+		case '%':
+			b.WriteByte('%')
+		default:
+			b.WriteByte('%')
+			b.WriteByte(pattern[i])
+		}
+	}
+	return b.String()
+}
+
+// filePeriod returns the start of the rotation period the file called name was written for, or
+// false when name does not follow the writer's file name pattern.
+func (w *dailyFileWriter) filePeriod(name string) (time.Time, bool) {
+	year, month, day, hour := 0, 1, 1, 0
+	pattern := w.filename
+	pos := 0
+	number := func(digits int) (int, bool) {
+		if pos+digits > len(name) {
+			return 0, false
+		}
+		value, err := strconv.Atoi(name[pos : pos+digits])
+		if err != nil || value < 0 {
+			return 0, false
+		}
+		pos += digits
+		return value, true
+	}
+	for i := 0; i < len(pattern); i++ {
+		ok := true
+		if pattern[i] == '%' && i+1 < len(pattern) {
+			i++
+			switch pattern[i] {
+			case 'Y':
+				year, ok = number(4)
+			case 'm':
+				month, ok = number(2)
+			case 'd':
+				day, ok = number(2)
+			case 'H':
+				hour, ok = number(2)
+			case '%':
+				ok = pos < len(name) && name[pos] == '%'
+				pos++
+			default:
+				ok = strings.HasPrefix(name[pos:], pattern[i-1:i+1])
+				pos += 2
+			}
+		} else {
+			ok = pos < len(name) && name[pos] == pattern[i]
+			pos++
+		}
+		if !ok {
+			return time.Time{}, false
+		}
+	}
+	if pos != len(name) {
+		return time.Time{}, false
+	}
+	return time.Date(year, time.Month(month), day, hour, 0, 0, 0, w.location), true
+}
+
+// existingFiles returns the names of the files in the directory that follow the writer's file name
+// pattern, newest rotation period first.
+func (w *dailyFileWriter) existingFiles() ([]string, error) {
+	entries, err := os.ReadDir(w.directory)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	type periodFile struct {
+		name   string
+		period time.Time
+	}
+	var files []periodFile
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() {
+			continue
+		}
+		if period, ok := w.filePeriod(entry.Name()); ok {
+			files = append(files, periodFile{name: entry.Name(), period: period})
+		}
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].period.After(files[j].period) })
+	names := make([]string, len(files))
+	for idx, file := range files {
+		names[idx] = file.name
+	}
+	return names, nil
+}
+
+func (w *dailyFileWriter) ensureFile(name string) error {
 	w.mu.Lock()
 	defer w.mu.Unlock()
 
-	if w.currentDate == date && w.file != nil {
-		if _, err := os.Stat(filepath.Join(w.directory, date+".log")); err == nil {
+	if w.currentName == name && w.file != nil {
+		if _, err := os.Stat(filepath.Join(w.directory, name)); err == nil {
 			return nil
 		}
 	}
@@ -174,13 +558,13 @@ func (w *dailyFileWriter) ensureFile(date string) error {
 		_ = root.Close()
 	}()
 
-	file, err := root.OpenFile(date+".log", os.O_CREATE|os.O_APPEND|os.O_WRONLY, fileWriterFileMode)
+	file, err := root.OpenFile(name, os.O_CREATE|os.O_APPEND|os.O_WRONLY, fileWriterFileMode)
 	if err != nil {
 		return fmt.Errorf("open log file: %w", err)
 	}
 
 	w.file = file
-	w.currentDate = date
+	w.currentName = name
 
 	return nil
 }
@@ -0,0 +1,129 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// Output formats accepted by Config.Format and FileConfig.Format.
+const (
+	FormatJSON    = "json"
+	FormatLogfmt  = "logfmt"
+	FormatConsole = "console"
+)
+
+// formatWriter re-encodes zerolog's JSON lines into another format before handing them to out.
+type formatWriter struct {
+	out     io.Writer
+	format  string
+	console zerolog.ConsoleWriter
+}
+
+// NewFormatWriter wraps out so that every JSON log line is re-encoded in format.
+// JSON (or an empty format) returns out unchanged, as does a writer already returned by NewFormatWriter,
+// so a per-writer format takes precedence over Config.Format.
+func NewFormatWriter(out io.Writer, format string) io.Writer {
+	return newFormatWriter(out, format, defaultNaming)
+}
+
+// newFormatWriter is NewFormatWriter for entries written under the keys of naming.
+func newFormatWriter(out io.Writer, format string, naming *fieldNaming) io.Writer {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if out == nil || format == "" || format == FormatJSON {
+		return out
+	}
+	if _, ok := out.(*formatWriter); ok {
+		return out
+	}
+	w := &formatWriter{out: out, format: format}
+	if format == FormatConsole {
+		w.console = newConsoleWriter(out, ConsoleConfig{}, true, naming)
+	}
+	return w
+}
+
+func (w *formatWriter) Write(p []byte) (int, error) {
+	switch w.format {
+	case FormatConsole:
+		if _, err := w.console.Write(p); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	case FormatLogfmt:
+		line, ok := encodeLogfmt(p)
+		if !ok {
+			line = p
+		}
+		if _, err := w.out.Write(line); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	default:
+		return w.out.Write(p)
+	}
+}
+
+// unwrapFormat returns the writer underneath any format conversion.
+func unwrapFormat(w io.Writer) io.Writer {
+	if async, ok := w.(*asyncSink); ok {
+		w = async.next
+	}
+	if fw, ok := w.(*formatWriter); ok {
+		return fw.out
+	}
+	return w
+}
+
+// encodeLogfmt converts a single JSON object line into key=value pairs, preserving field order.
+func encodeLogfmt(p []byte) ([]byte, bool) {
+	payload := bytes.TrimRight(p, "\n")
+	out := make([]byte, 0, len(payload))
+	ok := scanJSONObject(payload, func(key, value []byte) {
+		if len(out) > 0 {
+			out = append(out, ' ')
+		}
+		out = append(out, key...)
+		out = append(out, '=')
+		out = appendLogfmtValue(out, value)
+	})
+	if !ok {
+		return nil, false
+	}
+	return append(out, '\n'), true
+}
+
+func appendLogfmtValue(dst, raw []byte) []byte {
+	switch raw[0] {
+	case '"':
+		value, ok := jsonString(raw)
+		if !ok {
+			return strconv.AppendQuote(dst, string(raw))
+		}
+		if needsLogfmtQuote(value) {
+			return strconv.AppendQuote(dst, value)
+		}
+		return append(dst, value...)
+	case '{', '[':
+		return strconv.AppendQuote(dst, string(raw))
+	case 'n':
+		return dst
+	default:
+		return append(dst, raw...)
+	}
+}
+
+func needsLogfmtQuote(value string) bool {
+	if value == "" {
+		return true
+	}
+	for _, r := range value {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r == 0x7f {
+			return true
+		}
+	}
+	return false
+}
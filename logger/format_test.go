@@ -0,0 +1,92 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncodeLogfmt(t *testing.T) {
+	line, ok := encodeLogfmt([]byte(`{"level":"info","message":"hello world","count":3,"ok":true,"empty":"","nested":{"a":1},"nil":null,"path":"/v1/x"}` + "\n"))
+	if !ok {
+		t.Fatal("expected valid JSON to encode")
+	}
+	want := `level=info message="hello world" count=3 ok=true empty="" nested="{\"a\":1}" nil= path=/v1/x` + "\n"
+	if string(line) != want {
+		t.Fatalf("unexpected logfmt line:\n got %q\nwant %q", line, want)
+	}
+
+	if _, ok := encodeLogfmt([]byte("not json")); ok {
+		t.Fatal("expected malformed input to be rejected")
+	}
+}
+
+func TestFormatWriterPassesMalformedLinesThrough(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewFormatWriter(&buf, FormatLogfmt)
+	if _, err := w.Write([]byte("raw line\n")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if buf.String() != "raw line\n" {
+		t.Fatalf("unexpected output %q", buf.String())
+	}
+}
+
+func TestNewFormatWriterJSONIsIdentity(t *testing.T) {
+	var buf bytes.Buffer
+	if got := NewFormatWriter(&buf, FormatJSON); got != io.Writer(&buf) {
+		t.Fatalf("expected JSON format to return the writer unchanged, got %T", got)
+	}
+}
+
+func TestLoggerFormatAppliesPerWriter(t *testing.T) {
+	var logfmtBuf, consoleBuf bytes.Buffer
+	dir := t.TempDir()
+
+	log, err := New(context.Background(), Config{
+		Enabled:     true,
+		ServiceName: "format-test",
+		Format:      FormatLogfmt,
+		Writers:     []io.Writer{&logfmtBuf, NewFormatWriter(&consoleBuf, FormatConsole)},
+		File: FileConfig{
+			Enabled:   true,
+			Directory: dir,
+			Format:    FormatJSON,
+		},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	log.Info().Str("user", "alice").Msg("signed in")
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if got := logfmtBuf.String(); !strings.Contains(got, "level=info") || !strings.Contains(got, `message="signed in"`) || !strings.Contains(got, "user=alice") {
+		t.Fatalf("unexpected logfmt output %q", got)
+	}
+	if got := consoleBuf.String(); strings.HasPrefix(got, "{") || !strings.Contains(got, "signed in") || !strings.Contains(got, "INF") {
+		t.Fatalf("unexpected console output %q", got)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, time.Now().Format("2006-01-02")+".log"))
+	if err != nil {
+		t.Fatalf("read log file: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte("{")) {
+		t.Fatalf("expected file override to keep JSON, got %q", data)
+	}
+}
+
+func TestConfigRejectsUnknownFormat(t *testing.T) {
+	cfg := Config{Enabled: true, Format: "xml"}.ApplyDefaults()
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected validation error for unknown format")
+	}
+}
@@ -2,17 +2,21 @@ package logger
 
 import (
 	"context"
+	"io"
 	"sync/atomic"
 
 	"github.com/rs/zerolog"
 )
 
 var globalLogger atomic.Pointer[Logger]
-var disabledLogger = newDisabledLogger()
+var disabledLogger = Nop()
 
-func newDisabledLogger() *Logger {
-	nop := zerolog.Nop()
-	return &Logger{Logger: &nop}
+// fallback is the logger Global returns before Init or Use, set by SetFallback.
+var fallback atomic.Pointer[fallbackLogger]
+
+type fallbackLogger struct {
+	log    *Logger
+	warned atomic.Bool
 }
 
 // Init constructs a logger using New and makes it globally available via package-level helpers.
@@ -21,9 +25,6 @@ func Init(ctx context.Context, cfg Config) error {
 	if err != nil {
 		return err
 	}
-	if log == nil {
-		log = disabledLogger
-	}
 	Use(log)
 	return nil
 }
@@ -37,14 +38,32 @@ func Use(log *Logger) {
 	globalLogger.Store(log)
 }
 
-// Global returns the current global logger reference.
-// Returns a disabled noop logger if not initialized.
+// Global returns the current global logger reference. It never returns nil: before Init or Use it
+// returns the SetFallback logger, or a disabled noop logger when no fallback is set.
 func Global() *Logger {
 	logger := globalLogger.Load()
-	if logger == nil {
-		return disabledLogger
+	if logger != nil {
+		return logger
 	}
-	return logger
+	if fb := fallback.Load(); fb != nil {
+		if fb.warned.CompareAndSwap(false, true) {
+			fb.log.Warn().Msg("logger: global logger used before Init; writing to the fallback writer")
+		}
+		return fb.log
+	}
+	return disabledLogger
+}
+
+// SetFallback makes Global write JSON entries to w until Init or Use installs a logger, so entries
+// logged during startup, before the configuration is loaded, are not lost. The first entry through
+// the fallback is preceded by a warning. Passing nil restores the disabled noop logger.
+func SetFallback(w io.Writer) {
+	if w == nil {
+		fallback.Store(nil)
+		return
+	}
+	zl := zerolog.New(defaultNaming.writer(w)).With().Timestamp().Logger()
+	fallback.Store(&fallbackLogger{log: &Logger{Logger: &zl}})
 }
 
 // With returns a context for adding fields to the global logger.
@@ -77,6 +96,11 @@ func Fatal() *zerolog.Event {
 	return Global().Logger.Fatal().Stack()
 }
 
+// Audit opens an audit event through the global logger.
+func Audit() *zerolog.Event {
+	return Global().Audit()
+}
+
 // WithLevel opens an event at the specified level through the global logger.
 func WithLevel(level zerolog.Level) *zerolog.Event {
 	event := Global().Logger.WithLevel(level)
@@ -85,3 +109,11 @@ func WithLevel(level zerolog.Level) *zerolog.Event {
 	}
 	return event
 }
+
+// RecoverAndLog recovers a panic and records it through the global logger.
+// It must be invoked directly via defer.
+func RecoverAndLog(ctx context.Context) {
+	if recovered := recover(); recovered != nil {
+		Global().LogPanic(ctx, recovered)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"errors"
 	"io"
+	"strings"
 	"testing"
 
 	"go.opentelemetry.io/otel/codes"
@@ -41,6 +42,33 @@ func TestInitSetsGlobalLogger(t *testing.T) {
 	}
 }
 
+func TestNewDisabledReturnsNop(t *testing.T) {
+	ctx := context.Background()
+	log, err := New(ctx, Config{Enabled: false})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if log == nil {
+		t.Fatal("expected no-op logger when disabled, got nil")
+	}
+
+	log.Info().Str("foo", "bar").Msg("discarded")
+	log.Err(errors.New("boom")).Msg("discarded")
+	if err := log.AddWriter("extra", io.Discard); err != nil {
+		t.Fatalf("AddWriter: %v", err)
+	}
+	if err := log.EmitEvent(ctx, "unknown", nil); err != nil {
+		t.Fatalf("EmitEvent: %v", err)
+	}
+	log.BufferedForContext(ctx).Finish(nil)
+	if err := log.Flush(ctx); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if err := log.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
 func TestGlobalUpdateAndContext(t *testing.T) {
 	var buf bytes.Buffer
 	cfg := Config{
@@ -122,7 +150,7 @@ func TestGlobalLoggerAddsSpanEvents(t *testing.T) {
 	if len(events) != 1 {
 		t.Fatalf("expected 1 error event, got %d", len(events))
 	}
-	if events[0].Name != errorEventName {
+	if events[0].Name != defaultNaming.errorEvent {
 		t.Fatalf("unexpected error event name: %s", events[0].Name)
 	}
 	attrs := attributesToMap(events[0].Attributes)
@@ -153,6 +181,31 @@ func TestGlobalInitializesWhenUnconfigured(t *testing.T) {
 	log.Info().Msg("this should be a no-op")
 }
 
+func TestGlobalWritesToFallbackBeforeInit(t *testing.T) {
+	var buf bytes.Buffer
+	globalLogger.Store(nil)
+	SetFallback(&buf)
+	t.Cleanup(func() { SetFallback(nil) })
+
+	Info().Msg("starting up")
+	Info().Msg("loading config")
+
+	out := buf.String()
+	if strings.Count(out, "used before Init") != 1 {
+		t.Fatalf("expected one fallback warning, got %q", out)
+	}
+	if !strings.Contains(out, "starting up") || !strings.Contains(out, "loading config") {
+		t.Fatalf("expected entries in the fallback writer, got %q", out)
+	}
+
+	Use(Nop())
+	Info().Msg("after init")
+	if strings.Contains(buf.String(), "after init") {
+		t.Fatal("fallback used after a logger was installed")
+	}
+	globalLogger.Store(nil)
+}
+
 func TestGlobalErrorIncludesStackTrace(t *testing.T) {
 	var buf bytes.Buffer
 	cfg := Config{
@@ -0,0 +1,100 @@
+package logger
+
+import (
+	"encoding/json"
+	"regexp"
+)
+
+// lokiServiceNameLabel is the stream label Loki derives from the service.name resource attribute.
+const lokiServiceNameLabel = "service_name"
+
+// FieldNaming describes the keys the logger currently emits, so log backends and dashboards can be
+// configured from the same source as the logger.
+type FieldNaming struct {
+	Time        string `json:"time"`
+	Level       string `json:"level"`
+	Message     string `json:"message"`
+	TraceID     string `json:"trace_id"`
+	SpanID      string `json:"span_id"`
+	ServiceName string `json:"service_name"`
+	Environment string `json:"environment"`
+	// ServiceNameLabel is the Loki stream label carrying the service name of OTLP log records.
+	ServiceNameLabel string `json:"service_name_label"`
+}
+
+// FieldNaming reports the field names l writes, after Config.Fields, SemconvFields, Profile, and
+// FieldNames applied.
+func (l *Logger) FieldNaming() FieldNaming {
+	return l.fieldNaming().public()
+}
+
+// CurrentFieldNaming reports the field names of the global logger.
+func CurrentFieldNaming() FieldNaming {
+	return Global().FieldNaming()
+}
+
+// GrafanaDerivedField is one entry of a Loki datasource's jsonData.derivedFields.
+type GrafanaDerivedField struct {
+	Name            string `json:"name"`
+	MatcherType     string `json:"matcherType"`
+	MatcherRegex    string `json:"matcherRegex"`
+	URL             string `json:"url"`
+	URLDisplayLabel string `json:"urlDisplayLabel,omitempty"`
+	DatasourceUID   string `json:"datasourceUid"`
+}
+
+// GrafanaDerivedFields returns derived fields linking Loki log lines to traces in the Tempo
+// datasource tempoUID. The first matches the trace ID field in JSON and logfmt lines; the second
+// matches the trace_id structured metadata that OTLP-ingested records carry.
+func (n FieldNaming) GrafanaDerivedFields(tempoUID string) []GrafanaDerivedField {
+	key := regexp.QuoteMeta(n.TraceID)
+	return []GrafanaDerivedField{
+		{
+			Name:            "TraceID",
+			MatcherType:     "regex",
+			MatcherRegex:    `(?:"` + key + `":"|\b` + key + `=)(\w+)`,
+			URL:             "${__value.raw}",
+			URLDisplayLabel: "View trace",
+			DatasourceUID:   tempoUID,
+		},
+		{
+			Name:            "trace_id",
+			MatcherType:     "label",
+			MatcherRegex:    "trace_id",
+			URL:             "${__value.raw}",
+			URLDisplayLabel: "View trace",
+			DatasourceUID:   tempoUID,
+		},
+	}
+}
+
+// GrafanaLokiDatasource renders a Grafana datasource provisioning entry for Loki at url whose
+// derived fields link to the Tempo datasource tempoUID.
+func (n FieldNaming) GrafanaLokiDatasource(name, uid, url, tempoUID string) ([]byte, error) {
+	return json.MarshalIndent(map[string]any{
+		"name":   name,
+		"uid":    uid,
+		"type":   "loki",
+		"access": "proxy",
+		"url":    url,
+		"jsonData": map[string]any{
+			"derivedFields": n.GrafanaDerivedFields(tempoUID),
+		},
+	}, "", "  ")
+}
+
+// GrafanaTempoTracesToLogs renders the jsonData.tracesToLogsV2 block of a Tempo datasource so span
+// links query the Loki datasource lokiUID by service name and trace ID.
+func (n FieldNaming) GrafanaTempoTracesToLogs(lokiUID string) ([]byte, error) {
+	return json.MarshalIndent(map[string]any{
+		"tracesToLogsV2": map[string]any{
+			"datasourceUid":   lokiUID,
+			"filterByTraceID": true,
+			"filterBySpanID":  false,
+			"customQuery":     false,
+			"tags": []map[string]string{
+				{"key": "service.name", "value": n.ServiceNameLabel},
+			},
+		},
+	}, "", "  ")
+}
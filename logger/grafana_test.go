@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"regexp"
+	"testing"
+)
+
+func TestFieldNamingFollowsCustomFields(t *testing.T) {
+	log, err := New(context.Background(), Config{
+		Enabled: true,
+		Console: false,
+		Writers: []io.Writer{io.Discard},
+		Fields:  FieldConfig{TraceID: "dd.trace_id", SpanID: "dd.span_id"},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = log.Close() })
+
+	naming := log.FieldNaming()
+	if naming.TraceID != "dd.trace_id" || naming.SpanID != "dd.span_id" {
+		t.Fatalf("unexpected naming %+v", naming)
+	}
+	if naming.ServiceNameLabel != "service_name" {
+		t.Fatalf("unexpected service name label %q", naming.ServiceNameLabel)
+	}
+
+	fields := naming.GrafanaDerivedFields("tempo")
+	matcher := regexp.MustCompile(fields[0].MatcherRegex)
+	for _, line := range []string{
+		`{"level":"info","dd.trace_id":"4bf92f3577b34da6a3ce929d0e0e4736"}`,
+		`level=info dd.trace_id=4bf92f3577b34da6a3ce929d0e0e4736`,
+	} {
+		match := matcher.FindStringSubmatch(line)
+		if len(match) != 2 || match[1] != "4bf92f3577b34da6a3ce929d0e0e4736" {
+			t.Fatalf("regex %q did not extract trace id from %q: %v", fields[0].MatcherRegex, line, match)
+		}
+	}
+	if matcher.MatchString(`{"ddxtrace_id":"4bf92f3577b34da6a3ce929d0e0e4736"}`) {
+		t.Fatal("expected dots in the field name to be matched literally")
+	}
+}
+
+func TestGrafanaDatasourceJSON(t *testing.T) {
+	naming := FieldNaming{TraceID: "trace_id", ServiceNameLabel: "service_name"}
+
+	raw, err := naming.GrafanaLokiDatasource("Loki", "loki", "http://loki:3100", "tempo")
+	if err != nil {
+		t.Fatalf("GrafanaLokiDatasource: %v", err)
+	}
+	var ds struct {
+		Type     string `json:"type"`
+		JSONData struct {
+			DerivedFields []GrafanaDerivedField `json:"derivedFields"`
+		} `json:"jsonData"`
+	}
+	if err := json.Unmarshal(raw, &ds); err != nil {
+		t.Fatalf("decode datasource: %v", err)
+	}
+	if ds.Type != "loki" || len(ds.JSONData.DerivedFields) != 2 || ds.JSONData.DerivedFields[0].DatasourceUID != "tempo" {
+		t.Fatalf("unexpected datasource %s", raw)
+	}
+
+	raw, err = naming.GrafanaTempoTracesToLogs("loki")
+	if err != nil {
+		t.Fatalf("GrafanaTempoTracesToLogs: %v", err)
+	}
+	var tempo struct {
+		TracesToLogs struct {
+			DatasourceUID string              `json:"datasourceUid"`
+			Tags          []map[string]string `json:"tags"`
+		} `json:"tracesToLogsV2"`
+	}
+	if err := json.Unmarshal(raw, &tempo); err != nil {
+		t.Fatalf("decode tempo block: %v", err)
+	}
+	if tempo.TracesToLogs.DatasourceUID != "loki" || tempo.TracesToLogs.Tags[0]["value"] != "service_name" {
+		t.Fatalf("unexpected tempo block %s", raw)
+	}
+}
@@ -1,45 +1,182 @@
 package logger
 
 import (
+	"context"
+	"strings"
+	"sync/atomic"
+
+	"github.com/mfahmialkautsar/goo11y/clock"
 	"github.com/rs/zerolog"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
 )
 
-type spanHook struct{}
+// debugBaggageKey is the baggage member that forces debug entries for a request when TraceDebug is on.
+const debugBaggageKey = "debug"
+
+// traceDebugHook discards entries below threshold unless they were logged with a context whose span
+// is sampled or whose baggage carries debug=true. It must run before the other hooks so that they see
+// discarded entries as disabled.
+type traceDebugHook struct {
+	threshold zerolog.Level
+}
+
+func (h traceDebugHook) Run(event *zerolog.Event, level zerolog.Level, _ string) {
+	if level >= h.threshold || debugForced(event.GetCtx()) {
+		return
+	}
+	event.Discard()
+}
+
+func debugForced(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	if trace.SpanContextFromContext(ctx).IsSampled() {
+		return true
+	}
+	return strings.EqualFold(baggage.FromContext(ctx).Member(debugBaggageKey).Value(), "true")
+}
+
+// levelCounter counts emitted entries per level. Index 0 holds trace; zerolog levels map to level+1.
+type levelCounter struct {
+	counts [9]atomic.Uint64
+}
+
+func (c *levelCounter) Run(_ *zerolog.Event, level zerolog.Level, _ string) {
+	if level == zerolog.Disabled {
+		return
+	}
+	idx := int(level) + 1
+	if idx >= 0 && idx < len(c.counts) {
+		c.counts[idx].Add(1)
+	}
+}
+
+func (c *levelCounter) snapshot() map[string]uint64 {
+	out := make(map[string]uint64)
+	for idx := range c.counts {
+		if n := c.counts[idx].Load(); n > 0 {
+			out[zerolog.Level(idx-1).String()] = n
+		}
+	}
+	return out
+}
+
+// timestampHook writes the entry time from the logger's clock, where zerolog's Timestamp reads the
+// process-wide zerolog.TimestampFunc.
+type timestampHook struct {
+	clock clock.Clock
+}
+
+func (h timestampHook) Run(event *zerolog.Event, _ zerolog.Level, _ string) {
+	event.Time(zerolog.TimestampFieldName, h.clock.Now())
+}
+
+// spanHook annotates entries with trace metadata and mirrors them onto the active span according to policy.
+// A nil policy applies the defaults: warn and error events, with errors marking the span as failed.
+type spanHook struct {
+	policy   *spanEventPolicy
+	traceURL *traceURLTemplate
+	// unattached counts span events that found no recording span and no room in a
+	// ContextWithSpanEventBuffer buffer.
+	unattached *atomic.Uint64
+	// clock timestamps buffered span events; nil uses the real clock.
+	clock clock.Clock
+	// naming holds the keys and event names written; nil uses the defaults.
+	naming *fieldNaming
+}
 
-func (spanHook) Run(event *zerolog.Event, level zerolog.Level, msg string) {
+func (h spanHook) Run(event *zerolog.Event, level zerolog.Level, msg string) {
+	if level == zerolog.Disabled {
+		// Discarded by traceDebugHook; the entry is never written.
+		return
+	}
 	ctx := event.GetCtx()
 	if ctx == nil {
 		return
 	}
+	naming := h.naming
+	if naming == nil {
+		naming = defaultNaming
+	}
+	if id := RequestIDFromContext(ctx); id != "" {
+		event.Str(RequestIDField, id)
+	}
 
 	spanCtx := trace.SpanContextFromContext(ctx)
 	if spanCtx.IsValid() {
 		traceID := spanCtx.TraceID().String()
 		spanID := spanCtx.SpanID().String()
 		if traceID != "" {
-			event.Str(traceIDField, traceID)
+			event.Str(naming.traceID, traceID)
 		}
 		if spanID != "" {
-			event.Str(spanIDField, spanID)
+			event.Str(naming.spanID, spanID)
+		}
+		event.Bool(naming.sampled, spanCtx.IsSampled())
+		if level >= zerolog.WarnLevel && level < zerolog.NoLevel {
+			h.traceURL.add(event, spanCtx)
+		}
+		if naming.datadogIDs {
+			addDatadogIDs(event, spanCtx)
 		}
 	}
 
+	policy := h.policy
+	if policy == nil {
+		policy = defaultSpanEventPolicy
+	}
 	span := trace.SpanFromContext(ctx)
 	if !span.IsRecording() {
+		// An unsampled span is expected to record nothing; a missing or ended one loses the event.
+		if policy.enabled(level) && (!spanCtx.IsValid() || spanCtx.IsSampled()) {
+			h.bufferEvent(ctx, naming, level, msg, spanCtx.HasSpanID())
+		}
 		return
 	}
-	attrs := []attribute.KeyValue{}
-	if msg != "" {
-		attrs = append(attrs, attribute.String(LogMessageKey, msg))
-	}
-	switch {
-	case level >= zerolog.ErrorLevel:
+	if level >= zerolog.ErrorLevel && policy.setStatus {
 		span.SetStatus(codes.Error, msg)
-		span.AddEvent(errorEventName, trace.WithAttributes(attrs...))
-	case level == zerolog.WarnLevel:
-		span.AddEvent(warnEventName, trace.WithAttributes(attrs...))
 	}
+
+	logEvent := policy.enabled(level) && policy.underLimit(span, naming)
+	exception := policy.pending != nil && policy.exceptions && level >= zerolog.ErrorLevel
+	deferEvent := logEvent && len(policy.attributes) > 0
+	if policy.pending != nil && (exception || deferEvent) {
+		event.Uint64(pendingSpanEventField, policy.pending.push(pendingSpanEvent{
+			span:      span,
+			name:      naming.eventName(level),
+			msg:       msg,
+			logEvent:  logEvent,
+			exception: exception,
+		}))
+		return
+	}
+	if logEvent {
+		addLogEvent(span, naming.eventName(level), naming.eventMessage, msg)
+	}
+}
+
+// bufferEvent keeps the event of an entry without a recording span for the next span started from
+// ctx. Otherwise, when ctx carried a span that has ended, the event is counted as unattached; entries
+// logged without any span are not, so services that do not trace keep the count at zero.
+func (h spanHook) bufferEvent(ctx context.Context, naming *fieldNaming, level zerolog.Level, msg string, hadSpan bool) {
+	if buffer, ok := ctx.Value(spanEventBufferKey{}).(*spanEventBuffer); ok && buffer.add(naming.eventName(level), naming.eventMessage, msg, h.clock) {
+		return
+	}
+	if hadSpan && h.unattached != nil {
+		h.unattached.Add(1)
+	}
+}
+
+// addLogEvent records the event name on span, with msg under the attribute messageKey.
+func addLogEvent(span trace.Span, name, messageKey, msg string, extra ...attribute.KeyValue) {
+	attrs := make([]attribute.KeyValue, 0, len(extra)+1)
+	if msg != "" {
+		attrs = append(attrs, attribute.String(messageKey, msg))
+	}
+	attrs = append(attrs, extra...)
+	span.AddEvent(name, trace.WithAttributes(attrs...))
 }
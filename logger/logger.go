@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
@@ -11,34 +12,41 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
 
+	"github.com/mfahmialkautsar/goo11y/clock"
 	"github.com/mfahmialkautsar/goo11y/internal/otlputil"
 	pkgerrors "github.com/pkg/errors"
 	"github.com/rs/zerolog"
+	otelLog "go.opentelemetry.io/otel/log"
 	semconv "go.opentelemetry.io/otel/semconv/v1.28.0"
 )
 
-var (
-	traceIDField   = "trace_id"
-	spanIDField    = "span_id"
-	warnEventName  = "log.warn"
-	errorEventName = "log.error"
-	// LogMessageKey is the key to use for the main string message in structured logs.
-	LogMessageKey = "log.message"
-)
+// LogMessageKey is the default span event attribute carrying the entry message; see
+// InternalFieldConfig.EventMessageAttr.
+var LogMessageKey = "log.message"
 
 var (
-	// ServiceNameKey is the standardized service name key.
+	// ServiceNameKey is the standardized service name key, written unless the logger renames it.
 	ServiceNameKey = StandardizeKey(string(semconv.ServiceNameKey))
-	// DeploymentEnvironmentNameKey is the standardized environment name key.
+	// DeploymentEnvironmentNameKey is the standardized environment name key, written unless the
+	// logger renames it.
 	DeploymentEnvironmentNameKey = StandardizeKey(string(semconv.DeploymentEnvironmentNameKey))
+	// ServiceInstanceIDKey is the standardized service instance ID key.
+	ServiceInstanceIDKey = StandardizeKey(string(semconv.ServiceInstanceIDKey))
+	// ProcessRunIDKey is the standardized process run ID key.
+	ProcessRunIDKey = StandardizeKey(processRunIDAttr)
 )
 
+// processRunIDAttr identifies a single run of the process; it changes on every restart.
+const processRunIDAttr = "process.run_id"
+
 const callerSkipFrameCount = 2
 
 var (
 	processRoot     string
 	processRootOnce sync.Once
+	zerologOnce     sync.Once
 )
 
 // StandardizeKey standardizes a key string by replacing periods with underscores.
@@ -46,37 +54,71 @@ func StandardizeKey(key string) string {
 	return strings.ReplaceAll(key, ".", "_")
 }
 
-func applyFields(f FieldConfig) {
-	if f.TraceID != "" {
-		traceIDField = f.TraceID
-	}
-	if f.SpanID != "" {
-		spanIDField = f.SpanID
-	}
-	if f.Internal.WarnEvent != "" {
-		warnEventName = f.Internal.WarnEvent
+// withIdentity adds the service identity fields configured on cfg, under the keys of naming.
+func withIdentity(ctx zerolog.Context, cfg Config, naming *fieldNaming) zerolog.Context {
+	if cfg.ServiceName != "" {
+		ctx = ctx.Str(naming.serviceName, cfg.ServiceName)
 	}
-	if f.Internal.ErrorEvent != "" {
-		errorEventName = f.Internal.ErrorEvent
+	if cfg.Environment != "" {
+		ctx = ctx.Str(naming.environment, cfg.Environment)
 	}
-	if f.Internal.EventMessageAttr != "" {
-		LogMessageKey = f.Internal.EventMessageAttr
+	if cfg.ServiceInstanceID != "" {
+		ctx = ctx.Str(ServiceInstanceIDKey, cfg.ServiceInstanceID)
 	}
-	if f.ServiceName != "" {
-		ServiceNameKey = f.ServiceName
+	if cfg.RunID != "" {
+		ctx = ctx.Str(ProcessRunIDKey, cfg.RunID)
 	}
-	if f.DeploymentEnvironment != "" {
-		DeploymentEnvironmentNameKey = f.DeploymentEnvironment
+	if cfg.Profile == ProfileECS {
+		ctx = ctx.Str(ecsVersionField, ecsVersion)
 	}
+	return ctx
+}
+
+// configureZerolog sets the zerolog globals the loggers rely on. They are process-wide, so they are
+// set once and never changed per logger: field names and stack format live in fieldNaming.
+func configureZerolog() {
+	zerologOnce.Do(func() {
+		zerolog.TimeFieldFormat = defaultConsoleTimeFormat
+		zerolog.ErrorStackMarshaler = marshalStackTrace
+		zerolog.CallerSkipFrameCount = callerSkipFrameCount
+		zerolog.CallerMarshalFunc = callerLocationFormatter
+	})
 }
 
 // Logger wraps zerolog.Logger with trace metadata injection and resource management.
 type Logger struct {
 	*zerolog.Logger
-	writers *writerRegistry
+	writers  *writerRegistry
+	audit    *auditChannel
+	levels   *levelCounter
+	otlp     otelLog.LoggerProvider
+	out      io.Writer
+	events   *eventRegistry
+	messages *messageCounter
+	dedup    *dedupWriter
+	recent   *recentBuffer
+	crash    *crashReporter
+	// spanEvents holds the span events waiting for their lines; nil when none are deferred.
+	spanEvents *pendingSpanEvents
+	// unattached counts span events lost for want of a recording span.
+	unattached *atomic.Uint64
+	// errorChain adds the error_chain array to Err events.
+	errorChain bool
+	// naming holds the keys and stack format of the entries; nil uses the defaults.
+	naming *fieldNaming
+	// clock stamps the entries and times buffered requests; nil uses the real clock.
+	clock clock.Clock
+}
+
+// Nop returns a logger that discards every entry. New returns it when the logger is disabled, so
+// callers can use the chainable APIs without nil checks.
+func Nop() *Logger {
+	nop := zerolog.Nop()
+	return &Logger{Logger: &nop}
 }
 
 // New constructs a Zerolog-backed logger based on the provided configuration.
+// Returns Nop when cfg.Enabled is false.
 func New(ctx context.Context, cfg Config) (*Logger, error) {
 	cfg = cfg.ApplyDefaults()
 
@@ -85,86 +127,181 @@ func New(ctx context.Context, cfg Config) (*Logger, error) {
 	}
 
 	if !cfg.Enabled {
-		return nil, nil
+		return Nop(), nil
 	}
 
-	applyFields(cfg.Fields)
-
-	zerolog.TimeFieldFormat = defaultConsoleTimeFormat
-	zerolog.ErrorStackMarshaler = marshalStackTrace
-	zerolog.CallerSkipFrameCount = callerSkipFrameCount
-	zerolog.CallerMarshalFunc = callerLocationFormatter
+	configureZerolog()
+	naming := newFieldNaming(cfg)
 
 	fanout := newWriterRegistry()
+	fanout.delivery = cfg.Delivery
+	fanout.naming = naming
 	for idx, w := range cfg.Writers {
-		fanout.add(fmt.Sprintf("custom_%d", idx), w)
+		fanout.add(fmt.Sprintf("custom_%d", idx), newFormatWriter(w, cfg.Format, naming), WriterTagLocal)
 	}
 	if cfg.File.Enabled {
-		fileWriter, err := newDailyFileWriter(ctx, cfg.File)
+		fileWriter, err := newDailyFileWriter(ctx, cfg.File, cfg)
 		if err != nil {
 			return nil, fmt.Errorf("setup file writer: %w", err)
 		}
-		fanout.add("file", fileWriter)
+		fileFormat := cfg.File.Format
+		if fileFormat == "" {
+			fileFormat = cfg.Format
+		}
+		fanout.add("file", newFormatWriter(fileWriter, fileFormat, naming), WriterTagLocal)
 	}
 	if cfg.Console {
-		writer := zerolog.ConsoleWriter{
-			Out:        os.Stdout,
-			TimeFormat: defaultConsoleTimeFormat,
-		}
-		writer.FormatCaller = absoluteConsoleCallerFormatter(writer.NoColor)
-		fanout.add("console", writer)
+		fanout.add("console", newConsoleWriter(os.Stdout, cfg.ConsoleOptions, consoleNoColor(cfg.ConsoleOptions.Color, os.Stdout), naming), WriterTagLocal)
 	}
+	var otlpProvider otelLog.LoggerProvider
 	if cfg.OTLP.Enabled {
-		otlpWriter, err := newOTLPWriter(ctx, cfg.OTLP, cfg.ServiceName, cfg.Environment)
+		otlpWriter, err := newOTLPWriter(ctx, cfg.OTLP, cfg)
 		if err != nil {
 			return nil, fmt.Errorf("setup otlp writer: %w", err)
 		}
-		fanout.add("otlp", otlpWriter)
+		fanout.add("otlp", otlpWriter, WriterTagOTLP, WriterTagNetwork)
+		otlpProvider = otlpWriter.provider
 	}
 	if fanout.len() == 0 {
-		fanout.add("stdout", os.Stdout)
+		fanout.add("stdout", os.Stdout, WriterTagLocal)
+	}
+	var recent *recentBuffer
+	if cfg.Recent.Size > 0 {
+		recent = newRecentBuffer(cfg.Recent.Size)
+		fanout.add("recent", recent, WriterTagLocal)
 	}
 
+	fanout.hooks = cfg.Hooks
 	multiWriter := fanout.writer()
+	var dedup *dedupWriter
+	if cfg.Dedup.Window > 0 {
+		dedup = newDedupWriter(multiWriter, cfg.Dedup.Window, cfg.Clock, naming)
+		multiWriter = dedup
+	}
+
+	hook := spanHook{
+		policy:     newSpanEventPolicy(cfg.SpanEvents, cfg.ExceptionEvents, cfg.Clock),
+		traceURL:   newTraceURLTemplate(cfg.TraceURLTemplate, cfg.Clock),
+		unattached: new(atomic.Uint64),
+		clock:      cfg.Clock,
+		naming:     naming,
+	}
+	if hook.policy.pending != nil {
+		multiWriter = &spanEventWriter{next: multiWriter, policy: hook.policy, naming: naming}
+	}
+	multiWriter = naming.writer(multiWriter)
 
+	clk := clock.OrReal(cfg.Clock)
 	base := zerolog.New(multiWriter).
+		Hook(timestampHook{clock: clk}).
 		With().
-		Timestamp().
 		Caller().
 		Logger()
-	base = base.Hook(spanHook{})
-
-	baseCtx := base.With()
-	if cfg.ServiceName != "" {
-		baseCtx = baseCtx.Str(ServiceNameKey, cfg.ServiceName)
-	}
-	if cfg.Environment != "" {
-		baseCtx = baseCtx.Str(DeploymentEnvironmentNameKey, cfg.Environment)
-	}
-	base = baseCtx.Logger()
-
 	level, err := zerolog.ParseLevel(strings.ToLower(cfg.Level))
 	if err != nil {
 		level = zerolog.InfoLevel
 	}
+
+	levels := &levelCounter{}
+	hooks := make([]zerolog.Hook, 0, 4)
+	if cfg.TraceDebug && level > zerolog.DebugLevel {
+		hooks = append(hooks, traceDebugHook{threshold: level})
+		level = zerolog.DebugLevel
+	}
+	hooks = append(hooks, hook, levels)
+	var crash *crashReporter
+	if cfg.Crash.Directory != "" {
+		crash = newCrashReporter(cfg, recent)
+		hooks = append(hooks, crash)
+	}
+	var messages *messageCounter
+	if cfg.MessageMetrics.Enabled {
+		component := cfg.MessageMetrics.Component
+		if component == "" {
+			component = cfg.ServiceName
+		}
+		messages = newMessageCounter(component)
+		hooks = append(hooks, messages)
+	}
+	base = base.Hook(hooks...)
+
+	base = withIdentity(base.With(), cfg, naming).Logger()
 	base = base.Level(level)
 
 	logger := &Logger{
-		Logger:  &base,
-		writers: fanout,
+		Logger:   &base,
+		writers:  fanout,
+		levels:   levels,
+		otlp:     otlpProvider,
+		out:      multiWriter,
+		events:   &eventRegistry{},
+		messages: messages,
+		dedup:    dedup,
+		recent:   recent,
+		crash:    crash,
+
+		spanEvents: hook.policy.pending,
+		unattached: hook.unattached,
+
+		errorChain: cfg.ErrorChain,
+		naming:     naming,
+		clock:      clk,
+	}
+
+	if cfg.Audit.Enabled {
+		audit, err := newAuditChannel(ctx, cfg, naming)
+		if err != nil {
+			_ = fanout.close()
+			return nil, fmt.Errorf("setup audit channel: %w", err)
+		}
+		logger.audit = audit
 	}
 
 	otlputil.SetExportFailureHandler(exportFailureLogger(logger))
+	otlputil.SetSDKErrorHandler(sdkErrorLogger(logger))
 
+	if crash != nil {
+		crash.reingest(logger)
+		if err := crash.captureRuntimeCrashes(); err != nil {
+			logger.Warn().Err(err).Msg("runtime crashes are not captured")
+		}
+	}
 	return logger, nil
 }
 
+// Flush exports the records buffered by the OTLP writers, including the audit channel.
+func (l *Logger) Flush(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	l.dedup.flush()
+	var errs error
+	if l.writers != nil {
+		errs = errors.Join(errs, l.writers.flush(ctx))
+	}
+	if l.audit != nil {
+		errs = errors.Join(errs, l.audit.writers.flush(ctx))
+	}
+	return errs
+}
+
 // Close shuts down the logger and releases any resources including file handles and background goroutines.
 func (l *Logger) Close() error {
-	if l == nil || l.writers == nil {
+	if l == nil {
 		return nil
 	}
-	return l.writers.close()
+	l.dedup.flush()
+	var errs error
+	if l.writers != nil {
+		errs = errors.Join(errs, l.writers.close())
+	}
+	if l.audit != nil {
+		errs = errors.Join(errs, l.audit.writers.close())
+	}
+	if l.crash != nil {
+		l.crash.release()
+	}
+	return errs
 }
 
 // With returns a context for adding fields to the logger.
@@ -198,8 +335,18 @@ func (l *Logger) Fatal() *zerolog.Event {
 }
 
 // Err opens an error level event with the given error wrapped with stack trace.
+// With Config.ErrorChain set, the event also carries the error_chain array. The exception span
+// event of the entry records the error's Go type as exception.type.
 func (l *Logger) Err(err error) *zerolog.Event {
-	return l.Logger.Error().Stack().Err(err)
+	event := l.Logger.Error().Stack()
+	if l.errorChain && err != nil {
+		event = event.Array(ErrorChainField, errorChainOf(err, l.fieldNaming().stack))
+	}
+	if l.spanEvents != nil && err != nil {
+		// Only spanEventWriter, installed with spanEvents, removes the field again.
+		event = event.Str(errorTypeField, fmt.Sprintf("%T", err))
+	}
+	return event.Err(err)
 }
 
 // WithLevel opens an event at the specified level.
@@ -220,17 +367,8 @@ func exportFailureLogger(logger *Logger) func(component, transport string, err e
 			log.Printf("telemetry export failure (component=%s transport=%s): %v", component, transport, err)
 			return
 		}
-		exclusions := failureExclusions(component, transport)
-		targetLogger := logger
-		if logger.writers != nil && len(exclusions) > 0 {
-			writer := logger.writers.writerExcept(exclusions...)
-			base := logger.Output(writer)
-			targetLogger = &Logger{
-				Logger:  &base,
-				writers: logger.writers,
-			}
-		}
-		event := targetLogger.Error()
+		// Report only to local sinks, and never to the sink that failed.
+		event := logger.localOnly(transport).Error()
 		if component != "" {
 			event = event.Str("component", component)
 		}
@@ -241,17 +379,36 @@ func exportFailureLogger(logger *Logger) func(component, transport string, err e
 	}
 }
 
-func failureExclusions(component, transport string) []string {
-	transport = strings.ToLower(strings.TrimSpace(transport))
-	exclusions := make([]string, 0, 2)
-	switch transport {
-	case "http", "grpc", "file", "stdout", "stderr", "console":
-		exclusions = append(exclusions, transport)
+// sdkErrorLogger routes errors raised inside the OpenTelemetry SDK to the local sinks, keeping them
+// out of the OTLP pipeline that may be the one failing.
+func sdkErrorLogger(logger *Logger) func(err error) {
+	return func(err error) {
+		if err == nil {
+			return
+		}
+		logger.localOnly("").Error().Str("component", "otel").Err(err).Msg("opentelemetry sdk error")
+	}
+}
+
+// localOnly returns a logger writing only to the sinks tagged WriterTagLocal, leaving out excluded.
+func (l *Logger) localOnly(excluded string) *Logger {
+	if l.writers == nil {
+		return l
 	}
-	if strings.EqualFold(component, "logger") && transport == "" {
-		exclusions = append(exclusions, "http", "grpc", "file", "stdout", "stderr", "console")
+	base := l.Output(l.fieldNaming().writer(l.writers.writerTagged(WriterTagLocal, excluded)))
+	return &Logger{
+		Logger:  &base,
+		writers: l.writers,
+		naming:  l.naming,
 	}
-	return exclusions
+}
+
+// fieldNaming returns the naming of l's entries.
+func (l *Logger) fieldNaming() *fieldNaming {
+	if l == nil || l.naming == nil {
+		return defaultNaming
+	}
+	return l.naming
 }
 
 type stackTracer interface {
@@ -277,31 +434,42 @@ func formatLocation(file string, line int) string {
 	return fmt.Sprintf("%s:%d", filePath, line)
 }
 
+// marshalStackTrace records the frames of err for namingWriter, which filters and renders them in
+// the format of the logger that wrote the entry.
 func marshalStackTrace(err error) any {
 	if err == nil {
 		return nil
 	}
 	collected, frameSeen := collectFrames(err)
+	external := externalFrames(err)
 
-	if len(collected) == 0 {
+	if len(collected) == 0 && len(external) == 0 {
 		collected = collectCurrentCallstack(frameSeen)
 	}
 
-	if len(collected) == 0 {
+	if len(collected) == 0 && len(external) == 0 {
 		return nil
 	}
 
-	result := make([]map[string]any, 0, len(collected))
+	result := make([]recordedFrame, 0, len(collected)+len(external))
 	for _, frame := range collected {
-		entry := map[string]any{"location": frameLocation(frame)}
-		if frame.Function != "" {
-			entry["function"] = frame.Function
-		}
-		result = append(result, entry)
+		result = append(result, recordedFrame{Function: frame.Function, File: frame.File, Line: frame.Line})
+	}
+	for _, frame := range external {
+		location := frame.location()
+		result = append(result, recordedFrame{Function: frame.Function, Location: &location})
 	}
 	return result
 }
 
+func stackEntry(function, location string) map[string]any {
+	entry := map[string]any{"location": location}
+	if function != "" {
+		entry["function"] = function
+	}
+	return entry
+}
+
 func collectFrames(err error) ([]runtime.Frame, map[string]struct{}) {
 	var collected []runtime.Frame
 	frameSeen := make(map[string]struct{})
@@ -415,12 +583,12 @@ func addFrameIfNew(frame runtime.Frame, collected *[]runtime.Frame, frameSeen ma
 	}
 }
 
+// isSkippedFrame reports the frames of the runtime, zerolog, and this package that sit above the
+// logging call site. Frames are matched by package, so application files named like ours are kept.
 func isSkippedFrame(frame runtime.Frame) bool {
-	return strings.Contains(frame.File, "github.com/rs/zerolog") ||
-		strings.Contains(frame.File, "runtime/") ||
-		strings.Contains(frame.Function, "runtime.Callers") ||
-		strings.HasSuffix(frame.File, "logger.go") ||
-		strings.HasSuffix(frame.File, "global.go")
+	return strings.HasPrefix(frame.Function, "runtime.") ||
+		inModule(frame.Function, "github.com/rs/zerolog") ||
+		(inModule(frame.Function, loggerPackage) && !strings.HasSuffix(frame.File, "_test.go"))
 }
 
 func errorPointer(err error) uintptr {
@@ -166,7 +166,7 @@ func TestLoggerSpanEventsIntegration(t *testing.T) {
 	if len(events) != 1 {
 		t.Fatalf("expected 1 span event, got %d", len(events))
 	}
-	if events[0].Name != warnEventName {
+	if events[0].Name != defaultNaming.warnEvent {
 		t.Fatalf("unexpected event name: %s", events[0].Name)
 	}
 	warnAttrs := attributesToMap(events[0].Attributes)
@@ -0,0 +1,99 @@
+package logger
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// MessageMetricsName is the counter incremented for every entry when MessageMetrics is enabled.
+const MessageMetricsName = "log_messages_total"
+
+// MessageMetricsConfig counts written entries in a log_messages_total{level, component} counter so that
+// error rates can be alerted on without log-based metrics in the backend. Counting starts once a meter is
+// bound with BindMeter; goo11y.New binds the configured meter automatically. Component defaults to the
+// service name.
+type MessageMetricsConfig struct {
+	Enabled   bool
+	Component string
+}
+
+// messageCounter increments the bound counter for every entry that is not discarded.
+type messageCounter struct {
+	component string
+	counter   atomic.Pointer[metric.Int64Counter]
+	sets      [9]attribute.Set
+}
+
+func newMessageCounter(component string) *messageCounter {
+	c := &messageCounter{component: component}
+	for idx := range c.sets {
+		c.sets[idx] = attribute.NewSet(
+			attribute.String("level", zerolog.Level(idx-1).String()),
+			attribute.String("component", component),
+		)
+	}
+	return c
+}
+
+func (c *messageCounter) bind(meter metric.Meter) error {
+	counter, err := meter.Int64Counter(
+		MessageMetricsName,
+		metric.WithDescription("Log entries written per level and component"),
+		metric.WithUnit("{entry}"),
+	)
+	if err != nil {
+		return err
+	}
+	c.counter.Store(&counter)
+	return nil
+}
+
+func (c *messageCounter) Run(event *zerolog.Event, level zerolog.Level, _ string) {
+	if level == zerolog.Disabled {
+		return
+	}
+	counter := c.counter.Load()
+	idx := int(level) + 1
+	if counter == nil || idx < 0 || idx >= len(c.sets) {
+		return
+	}
+	ctx := event.GetCtx()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	(*counter).Add(ctx, 1, metric.WithAttributeSet(c.sets[idx]))
+}
+
+// BindMeter starts counting entries in log_messages_total through meter when MessageMetrics is
+// enabled, and records the file writer's write and fsync latencies in the FileWriteDurationName and
+// FileSyncDurationName histograms. Binding again replaces the previous instruments.
+// No-op if receiver or meter is nil.
+func (l *Logger) BindMeter(meter metric.Meter) error {
+	if l == nil || l.writers == nil || meter == nil {
+		return nil
+	}
+	if l.messages != nil {
+		if err := l.messages.bind(meter); err != nil {
+			return err
+		}
+	}
+	var histograms *fileHistograms
+	for _, w := range l.writers.snapshot() {
+		file, ok := unwrapFormat(w.writer).(*dailyFileWriter)
+		if !ok {
+			continue
+		}
+		if histograms == nil {
+			var err error
+			if histograms, err = newFileHistograms(meter); err != nil {
+				return err
+			}
+		}
+		file.bindMeter(histograms)
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestMessageMetricsCountsPerLevelAndComponent(t *testing.T) {
+	log, err := New(context.Background(), Config{
+		Enabled:        true,
+		Level:          "info",
+		Console:        false,
+		ServiceName:    "orders",
+		Writers:        []io.Writer{io.Discard},
+		MessageMetrics: MessageMetricsConfig{Enabled: true},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = log.Close() })
+
+	log.Info().Msg("before bind")
+
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	t.Cleanup(func() { _ = mp.Shutdown(context.Background()) })
+	if err := log.BindMeter(mp.Meter("test")); err != nil {
+		t.Fatalf("BindMeter: %v", err)
+	}
+
+	log.Info().Msg("one")
+	log.Error().Msg("two")
+	log.Error().Msg("three")
+	log.Debug().Msg("filtered")
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	counts := make(map[string]int64)
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if m.Name != MessageMetricsName {
+				continue
+			}
+			for _, point := range m.Data.(metricdata.Sum[int64]).DataPoints {
+				component, _ := point.Attributes.Value(attribute.Key("component"))
+				if component.AsString() != "orders" {
+					t.Fatalf("expected service name as component, got %q", component.AsString())
+				}
+				level, _ := point.Attributes.Value(attribute.Key("level"))
+				counts[level.AsString()] = point.Value
+			}
+		}
+	}
+	if counts["info"] != 1 || counts["error"] != 2 || len(counts) != 2 {
+		t.Fatalf("unexpected counts: %v", counts)
+	}
+}
+
+func TestBindMeterWithoutMessageMetrics(t *testing.T) {
+	log, err := New(context.Background(), Config{
+		Enabled: true,
+		Console: false,
+		Writers: []io.Writer{io.Discard},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = log.Close() })
+
+	mp := sdkmetric.NewMeterProvider()
+	if err := log.BindMeter(mp.Meter("test")); err != nil {
+		t.Fatalf("BindMeter: %v", err)
+	}
+	var nilLogger *Logger
+	if err := nilLogger.BindMeter(mp.Meter("test")); err != nil {
+		t.Fatalf("BindMeter on nil logger: %v", err)
+	}
+}
@@ -1,18 +1,19 @@
 package logger
 
 import (
+	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/mfahmialkautsar/goo11y/clock"
 	"github.com/mfahmialkautsar/goo11y/constant"
-	"github.com/mfahmialkautsar/goo11y/internal/attrutil"
 	"github.com/mfahmialkautsar/goo11y/internal/otlputil"
 	"github.com/mfahmialkautsar/goo11y/internal/persistentgrpc"
 	"github.com/mfahmialkautsar/goo11y/internal/persistenthttp"
-	"github.com/rs/zerolog"
+	"github.com/mfahmialkautsar/goo11y/internal/resourceutil"
+	"github.com/mfahmialkautsar/goo11y/internal/spool"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
@@ -30,24 +31,46 @@ import (
 const loggerInstrumentation = "github.com/mfahmialkautsar/goo11y/logger"
 
 type otlpWriter struct {
-	logger   otelLog.Logger
-	provider *log.LoggerProvider
+	logger     otelLog.Logger
+	provider   *log.LoggerProvider
+	spoolDepth func() int
+	spoolDrops func() uint64
+	clock      clock.Clock
+	// flushTimeout bounds the export forced by fatal and panic entries.
+	flushTimeout time.Duration
+	// naming holds the keys mapped to record fields.
+	naming *fieldNaming
 }
 
-func newOTLPWriter(ctx context.Context, cfg OTLPConfig, serviceName, environment string) (*otlpWriter, error) {
-	exporter, spool, httpClient, err := configureExporter(ctx, cfg)
-	if err != nil {
-		return nil, err
+func newOTLPWriter(ctx context.Context, cfg OTLPConfig, identity Config) (*otlpWriter, error) {
+	clk := clock.OrReal(identity.Clock)
+	build := func(ctx context.Context) (log.Exporter, error) {
+		exporter, spool, httpClient, err := configureExporter(ctx, cfg, clk)
+		if err != nil {
+			return nil, err
+		}
+		return wrapLogExporter(exporter, "logger", cfg.Protocol, spool, httpClient), nil
+	}
+	var exporter log.Exporter
+	var lazy *otlputil.Lazy[log.Exporter]
+	if cfg.AsyncInit {
+		buildCtx := context.WithoutCancel(ctx)
+		lazy = otlputil.NewLazy("logger", func() (log.Exporter, error) { return build(buildCtx) })
+		exporter = lazyLogExporter{exporter: lazy}
+	} else {
+		var err error
+		if exporter, err = build(ctx); err != nil {
+			return nil, err
+		}
 	}
-	exporter = wrapLogExporter(exporter, "logger", cfg.Protocol, spool, httpClient)
 
-	res, err := buildResource(ctx, serviceName, environment)
+	res, err := buildResource(ctx, identity)
 	if err != nil {
 		return nil, err
 	}
 
 	var processor log.Processor
-	if !cfg.Async {
+	if !cfg.Async || cfg.SyncExport {
 		processor = log.NewSimpleProcessor(exporter)
 	} else {
 		processor = log.NewBatchProcessor(exporter)
@@ -58,10 +81,71 @@ func newOTLPWriter(ctx context.Context, cfg OTLPConfig, serviceName, environment
 		log.WithProcessor(processor),
 	)
 
-	return &otlpWriter{
-		logger:   provider.Logger(loggerInstrumentation),
-		provider: provider,
-	}, nil
+	writer := &otlpWriter{
+		logger:       provider.Logger(loggerInstrumentation),
+		provider:     provider,
+		clock:        clk,
+		flushTimeout: cfg.Timeout,
+		naming:       newFieldNaming(identity),
+	}
+	if lazy != nil {
+		writer.spoolDepth = func() int {
+			if built, ok := lazy.Get(); ok {
+				if depth, _ := spoolCounters(built); depth != nil {
+					return depth()
+				}
+			}
+			return 0
+		}
+		writer.spoolDrops = func() uint64 {
+			if built, ok := lazy.Get(); ok {
+				if _, drops := spoolCounters(built); drops != nil {
+					return drops()
+				}
+			}
+			return 0
+		}
+	} else {
+		writer.spoolDepth, writer.spoolDrops = spoolCounters(exporter)
+	}
+	return writer, nil
+}
+
+// spoolCounters returns the queue depth and drop counters of the spool behind exporter, or nil
+// when it exports without one.
+func spoolCounters(exporter log.Exporter) (func() int, func() uint64) {
+	wrapped, ok := exporter.(*logExporterWithLogging)
+	if !ok {
+		return nil, nil
+	}
+	switch {
+	case wrapped.spool != nil:
+		return wrapped.spool.QueueDepth, wrapped.spool.QueueDropped
+	case wrapped.httpClient != nil:
+		return wrapped.httpClient.QueueDepth, wrapped.httpClient.QueueDropped
+	}
+	return nil, nil
+}
+
+// Queued returns the number of export requests waiting in the spool.
+func (w *otlpWriter) Queued() int {
+	if w.spoolDepth == nil {
+		return 0
+	}
+	return w.spoolDepth()
+}
+
+// Dropped returns the number of export requests the spool discarded.
+func (w *otlpWriter) Dropped() uint64 {
+	if w.spoolDrops == nil {
+		return 0
+	}
+	return w.spoolDrops()
+}
+
+// ForceFlush exports all buffered records.
+func (w *otlpWriter) ForceFlush(ctx context.Context) error {
+	return w.provider.ForceFlush(ctx)
 }
 
 func (w *otlpWriter) Close() error {
@@ -69,7 +153,7 @@ func (w *otlpWriter) Close() error {
 }
 
 func (w *otlpWriter) Write(p []byte) (int, error) {
-	record, spanCtx := buildRecord(p)
+	record, spanCtx := buildRecord(p, clock.OrReal(w.clock).Now(), w.naming)
 
 	emitCtx := context.Background()
 	if spanCtx.IsValid() {
@@ -77,10 +161,25 @@ func (w *otlpWriter) Write(p []byte) (int, error) {
 	}
 
 	w.logger.Emit(emitCtx, record)
+	if record.Severity() >= otelLog.SeverityFatal {
+		// Fatal exits and an unrecovered panic crashes right after this write, before any batch is
+		// due, so export now while the process still runs.
+		w.flushNow()
+	}
 	return len(p), nil
 }
 
-func configureExporter(ctx context.Context, cfg OTLPConfig) (log.Exporter, *persistentgrpc.Manager, *persistenthttp.Client, error) {
+func (w *otlpWriter) flushNow() {
+	ctx := context.Background()
+	if w.flushTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.flushTimeout)
+		defer cancel()
+	}
+	_ = w.provider.ForceFlush(ctx)
+}
+
+func configureExporter(ctx context.Context, cfg OTLPConfig, clk clock.Clock) (log.Exporter, *persistentgrpc.Manager, *persistenthttp.Client, error) {
 	endpoint := strings.TrimSpace(cfg.Endpoint)
 	if endpoint == "" {
 		return nil, nil, nil, fmt.Errorf("otlp: endpoint is required")
@@ -90,6 +189,9 @@ func configureExporter(ctx context.Context, cfg OTLPConfig) (log.Exporter, *pers
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("otlp: %w", err)
 	}
+	if cfg.Secondary.Endpoint != "" && !cfg.UseSpool {
+		return nil, nil, nil, fmt.Errorf("otlp: secondary endpoint requires UseSpool")
+	}
 
 	var exporter log.Exporter
 	var grpcManager *persistentgrpc.Manager
@@ -97,12 +199,12 @@ func configureExporter(ctx context.Context, cfg OTLPConfig) (log.Exporter, *pers
 
 	switch cfg.Protocol {
 	case constant.ProtocolHTTP:
-		exporter, httpClient, err = setupHTTPExporter(ctx, cfg, parsed)
+		exporter, httpClient, err = setupHTTPExporter(ctx, cfg, parsed, clk)
 		if err != nil {
 			return nil, nil, nil, err
 		}
 	case constant.ProtocolGRPC:
-		exporter, grpcManager, err = setupGRPCExporter(ctx, cfg, parsed)
+		exporter, grpcManager, err = setupGRPCExporter(ctx, cfg, parsed, clk)
 		if err != nil {
 			return nil, nil, nil, err
 		}
@@ -174,29 +276,59 @@ func (l logExporterWithLogging) ForceFlush(ctx context.Context) error {
 	return err
 }
 
-func setupHTTPExporter(ctx context.Context, cfg OTLPConfig, endpoint otlputil.Endpoint) (log.Exporter, *persistenthttp.Client, error) {
+func newLogsRequest() proto.Message {
+	return new(collog.ExportLogsServiceRequest)
+}
+
+func setupHTTPExporter(ctx context.Context, cfg OTLPConfig, endpoint otlputil.Endpoint, clk clock.Clock) (log.Exporter, *persistenthttp.Client, error) {
 	options := []otlploghttp.Option{
 		otlploghttp.WithEndpoint(strings.TrimRight(endpoint.Host, "/")),
-		otlploghttp.WithURLPath(endpoint.PathWithSuffix("/v1/logs")),
+		otlploghttp.WithURLPath(endpoint.ExportPath("/v1/logs", cfg.EndpointIsURL)),
 	}
 
 	if cfg.Timeout > 0 {
 		options = append(options, otlploghttp.WithTimeout(cfg.Timeout))
 	}
+	tlsConfig, err := otlputil.TLSConfig(cfg.Certificate)
+	if err != nil {
+		return nil, nil, fmt.Errorf("otlp: %w", err)
+	}
 	if endpoint.Insecure {
 		options = append(options, otlploghttp.WithInsecure())
+	} else if tlsConfig != nil {
+		options = append(options, otlploghttp.WithTLSClientConfig(tlsConfig))
 	}
 	if headers := cfg.headerMap(); len(headers) > 0 {
 		options = append(options, otlploghttp.WithHeaders(headers))
 	}
 	var spoolClient *persistenthttp.Client
 	if cfg.UseSpool {
-		client, err := persistenthttp.NewClientWithComponent(cfg.QueueDir, cfg.Timeout, "logger")
+		clientOpts := []persistenthttp.Option{
+			persistenthttp.WithQueueOptions(spoolOptions(cfg, clk)...),
+			persistenthttp.WithTLSConfig(tlsConfig),
+			persistenthttp.WithStatusPolicy(spool.ParseStatusPolicy(cfg.StatusPolicy)),
+		}
+		if cfg.Secondary.Endpoint != "" {
+			secondary, err := parseSecondary(cfg)
+			if err != nil {
+				return nil, nil, err
+			}
+			clientOpts = append(clientOpts, persistenthttp.WithSecondary(persistenthttp.Secondary{
+				URL:          secondary.URL("/v1/logs"),
+				Headers:      cfg.Secondary.Credentials.HeaderMap(),
+				StripHeaders: headerNames(cfg.headerMap()),
+				Failover:     otlputil.NewFailover("logger", cfg.Protocol, cfg.Secondary.Threshold, cfg.Secondary.ProbeInterval),
+			}))
+		}
+		client, err := persistenthttp.NewClientWithComponent(cfg.QueueDir, cfg.Timeout, "logger", clientOpts...)
 		if err != nil {
 			return nil, nil, fmt.Errorf("create log client: %w", err)
 		}
 		spoolClient = client
-		options = append(options, otlploghttp.WithHTTPClient(client.Client))
+		options = append(options, otlploghttp.WithHTTPClient(otlputil.SplitHTTPClient(client.Client, cfg.MaxPayloadBytes, newLogsRequest)))
+	} else if cfg.MaxPayloadBytes > 0 {
+		client := otlputil.NewHTTPClient(cfg.Timeout, tlsConfig)
+		options = append(options, otlploghttp.WithHTTPClient(otlputil.SplitHTTPClient(client, cfg.MaxPayloadBytes, newLogsRequest)))
 	}
 
 	options = append(options, otlploghttp.WithRetry(otlploghttp.RetryConfig{Enabled: true}))
@@ -211,7 +343,7 @@ func setupHTTPExporter(ctx context.Context, cfg OTLPConfig, endpoint otlputil.En
 	return exporter, spoolClient, nil
 }
 
-func setupGRPCExporter(ctx context.Context, cfg OTLPConfig, endpoint otlputil.Endpoint) (log.Exporter, *persistentgrpc.Manager, error) {
+func setupGRPCExporter(ctx context.Context, cfg OTLPConfig, endpoint otlputil.Endpoint, clk clock.Clock) (log.Exporter, *persistentgrpc.Manager, error) {
 	if endpoint.HasPath() {
 		return nil, nil, fmt.Errorf("otlp: grpc endpoint %q must not include a path", cfg.Endpoint)
 	}
@@ -223,8 +355,16 @@ func setupGRPCExporter(ctx context.Context, cfg OTLPConfig, endpoint otlputil.En
 	if cfg.Timeout > 0 {
 		options = append(options, otlploggrpc.WithTimeout(cfg.Timeout))
 	}
+	tlsConfig, err := otlputil.TLSConfig(cfg.Certificate)
+	if err != nil {
+		return nil, nil, fmt.Errorf("otlp: %w", err)
+	}
+	var dialOpts []grpc.DialOption
 	if endpoint.Insecure {
 		options = append(options, otlploggrpc.WithInsecure())
+	} else if tlsConfig != nil {
+		options = append(options, otlploggrpc.WithTLSCredentials(credentials.NewTLS(tlsConfig)))
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
 	} else {
 		options = append(options, otlploggrpc.WithTLSCredentials(credentials.NewClientTLSFromCert(nil, "")))
 	}
@@ -232,21 +372,58 @@ func setupGRPCExporter(ctx context.Context, cfg OTLPConfig, endpoint otlputil.En
 		options = append(options, otlploggrpc.WithHeaders(headers))
 	}
 
+	var interceptors []grpc.UnaryClientInterceptor
+	if cfg.MaxPayloadBytes > 0 {
+		interceptors = append(interceptors, otlputil.SplitInterceptor(cfg.MaxPayloadBytes))
+	}
 	var spoolManager *persistentgrpc.Manager
 	if cfg.UseSpool {
+		managerOpts := []persistentgrpc.Option{
+			persistentgrpc.WithEndpoint(endpoint, dialOpts...),
+			persistentgrpc.WithQueueOptions(spoolOptions(cfg, clk)...),
+			persistentgrpc.WithStatusPolicy(spool.ParseStatusPolicy(cfg.StatusPolicy)),
+		}
+		var secondaryConn *grpc.ClientConn
+		if cfg.Secondary.Endpoint != "" {
+			secondary, err := parseSecondary(cfg)
+			if err != nil {
+				return nil, nil, err
+			}
+			if secondary.HasPath() {
+				return nil, nil, fmt.Errorf("otlp: grpc secondary endpoint %q must not include a path", cfg.Secondary.Endpoint)
+			}
+			conn, err := persistentgrpc.Dial(secondary)
+			if err != nil {
+				return nil, nil, fmt.Errorf("otlp: secondary: %w", err)
+			}
+			secondaryConn = conn
+			managerOpts = append(managerOpts, persistentgrpc.WithSecondary(persistentgrpc.Secondary{
+				Conn:         conn,
+				Headers:      cfg.Secondary.Credentials.HeaderMap(),
+				StripHeaders: headerNames(cfg.headerMap()),
+				Failover:     otlputil.NewFailover("logger", cfg.Protocol, cfg.Secondary.Threshold, cfg.Secondary.ProbeInterval),
+			}))
+		}
 		manager, err := persistentgrpc.NewManager(
 			cfg.QueueDir,
 			"logger",
 			cfg.Protocol,
 			"/opentelemetry.proto.collector.logs.v1.LogsService/Export",
-			func() proto.Message { return new(collog.ExportLogsServiceRequest) },
+			newLogsRequest,
 			func() proto.Message { return new(collog.ExportLogsServiceResponse) },
+			managerOpts...,
 		)
 		if err != nil {
+			if secondaryConn != nil {
+				_ = secondaryConn.Close()
+			}
 			return nil, nil, err
 		}
 		spoolManager = manager
-		options = append(options, otlploggrpc.WithDialOption(grpc.WithUnaryInterceptor(manager.Interceptor())))
+		interceptors = append(interceptors, manager.Interceptor())
+	}
+	if len(interceptors) > 0 {
+		options = append(options, otlploggrpc.WithDialOption(grpc.WithChainUnaryInterceptor(interceptors...)))
 	}
 
 	options = append(options, otlploggrpc.WithRetry(otlploggrpc.RetryConfig{Enabled: true}))
@@ -261,18 +438,40 @@ func setupGRPCExporter(ctx context.Context, cfg OTLPConfig, endpoint otlputil.En
 	return exporter, spoolManager, nil
 }
 
-func buildResource(ctx context.Context, serviceName, environment string) (*resource.Resource, error) {
+func parseSecondary(cfg OTLPConfig) (otlputil.Endpoint, error) {
+	endpoint, err := otlputil.ParseEndpoint(cfg.Secondary.Endpoint, cfg.Secondary.Insecure)
+	if err != nil {
+		return otlputil.Endpoint{}, fmt.Errorf("otlp: secondary: %w", err)
+	}
+	return endpoint, nil
+}
+
+func headerNames(headers map[string]string) []string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	return names
+}
+
+func buildResource(ctx context.Context, identity Config) (*resource.Resource, error) {
 	attrs := make([]attribute.KeyValue, 0, 5)
-	if serviceName != "" {
+	if identity.ServiceName != "" {
 		attrs = append(attrs,
-			semconv.ServiceNameKey.String(serviceName),
+			semconv.ServiceNameKey.String(identity.ServiceName),
 		)
 	}
-	if environment != "" {
+	if identity.Environment != "" {
 		attrs = append(attrs,
-			semconv.DeploymentEnvironmentNameKey.String(environment),
+			semconv.DeploymentEnvironmentNameKey.String(identity.Environment),
 		)
 	}
+	if identity.ServiceInstanceID != "" {
+		attrs = append(attrs, semconv.ServiceInstanceID(identity.ServiceInstanceID))
+	}
+	if identity.RunID != "" {
+		attrs = append(attrs, attribute.String(processRunIDAttr, identity.RunID))
+	}
 
 	userResource := resource.Empty()
 	if len(attrs) > 0 {
@@ -283,108 +482,111 @@ func buildResource(ctx context.Context, serviceName, environment string) (*resou
 		}
 	}
 
-	merged, err := resource.Merge(resource.Default(), userResource)
+	merged, err := resourceutil.Merge(identity.ResourceSchemaConflict, resource.Default(), userResource)
 	if err != nil {
 		return nil, fmt.Errorf("otlp resource merge: %w", err)
 	}
 	return merged, nil
 }
 
-func buildRecord(entry []byte) (otelLog.Record, trace.SpanContext) {
+// buildRecord decodes a JSON entry written under the keys of naming into a log record, stamped with
+// now unless the entry carries a time.
+func buildRecord(entry []byte, now time.Time, naming *fieldNaming) (otelLog.Record, trace.SpanContext) {
+	if naming == nil {
+		naming = defaultNaming
+	}
 	record := otelLog.Record{}
-	observed := time.Now()
-	record.SetTimestamp(observed)
+	record.SetTimestamp(now)
 	record.SetSeverity(otelLog.SeverityInfo)
-	record.SetBody(otelLog.StringValue(strings.TrimSpace(string(entry))))
 
-	var spanCtx trace.SpanContext
-
-	var payload map[string]any
-	if err := json.Unmarshal(entry, &payload); err != nil {
-		return record, spanCtx
-	}
+	var (
+		traceID    trace.TraceID
+		spanID     trace.SpanID
+		unsampled  bool
+		hasMessage bool
+	)
 
-	if ts, ok := payload[zerolog.TimestampFieldName].(string); ok {
-		if parsed, err := time.Parse(time.RFC3339Nano, ts); err == nil {
-			record.SetTimestamp(parsed)
+	ok := scanJSONObject(entry, func(rawKey, raw []byte) {
+		switch {
+		case keyIs(rawKey, naming.to.time):
+			if ts, ok := jsonString(raw); ok {
+				if parsed, err := time.Parse(time.RFC3339Nano, ts); err == nil {
+					record.SetTimestamp(parsed)
+				}
+			}
+		case keyIs(rawKey, naming.to.message):
+			if msg, ok := jsonString(raw); ok {
+				record.SetBody(otelLog.StringValue(msg))
+				hasMessage = true
+			}
+		case keyIs(rawKey, naming.to.level):
+			if len(raw) >= 2 && raw[0] == '"' {
+				record.SetSeverity(levelSeverity(raw[1 : len(raw)-1]))
+			}
+		case keyIs(rawKey, naming.traceID):
+			if len(raw) >= 2 && raw[0] == '"' {
+				if id, err := trace.TraceIDFromHex(string(raw[1 : len(raw)-1])); err == nil {
+					traceID = id
+				}
+			}
+		case keyIs(rawKey, naming.spanID):
+			if len(raw) >= 2 && raw[0] == '"' {
+				if id, err := trace.SpanIDFromHex(string(raw[1 : len(raw)-1])); err == nil {
+					spanID = id
+				}
+			}
+		case keyIs(rawKey, naming.sampled):
+			unsampled = string(raw) == "false"
+		case keyIs(rawKey, naming.serviceName), keyIs(rawKey, naming.environment),
+			keyIs(rawKey, ServiceInstanceIDKey), keyIs(rawKey, ProcessRunIDKey):
+		default:
+			key := string(rawKey)
+			if bytes.IndexByte(rawKey, '\\') >= 0 {
+				quoted := make([]byte, 0, len(rawKey)+2)
+				quoted = append(append(append(quoted, '"'), rawKey...), '"')
+				if decoded, ok := jsonString(quoted); ok {
+					key = decoded
+				}
+				if naming.coreField(key) {
+					return
+				}
+			}
+			record.AddAttributes(jsonKeyValue(key, raw))
 		}
-	}
+	})
 
-	if msg, ok := payload[zerolog.MessageFieldName].(string); ok {
-		record.SetBody(otelLog.StringValue(msg))
+	if !ok {
+		fallback := otelLog.Record{}
+		fallback.SetTimestamp(now)
+		fallback.SetSeverity(otelLog.SeverityInfo)
+		fallback.SetBody(otelLog.StringValue(strings.TrimSpace(string(entry))))
+		return fallback, trace.SpanContext{}
 	}
 
-	if lvl, ok := payload[zerolog.LevelFieldName].(string); ok {
-		severityText := strings.ToUpper(lvl)
-		record.SetSeverity(toSeverity(severityText))
+	if !hasMessage {
+		record.SetBody(otelLog.StringValue(strings.TrimSpace(string(entry))))
 	}
 
-	var traceID trace.TraceID
-	if traceVal, ok := payload[traceIDField].(string); ok {
-		if id, err := trace.TraceIDFromHex(traceVal); err == nil {
-			traceID = id
-		}
-	}
-	var spanID trace.SpanID
-	if spanVal, ok := payload[spanIDField].(string); ok {
-		if id, err := trace.SpanIDFromHex(spanVal); err == nil {
-			spanID = id
-		}
-	}
+	var spanCtx trace.SpanContext
 	if traceID.IsValid() {
 		cfg := trace.SpanContextConfig{
 			TraceID:    traceID,
 			TraceFlags: trace.FlagsSampled,
 		}
+		if unsampled {
+			cfg.TraceFlags = 0
+		}
 		if spanID.IsValid() {
 			cfg.SpanID = spanID
 		}
 		spanCtx = trace.NewSpanContext(cfg)
 	}
 
-	for _, attr := range attributesFromPayload(payload) {
-		record.AddAttributes(toLogKeyValue(attr))
-	}
-
 	return record, spanCtx
 }
 
-func attributesFromPayload(payload map[string]any) []attribute.KeyValue {
-	attrs := make([]attribute.KeyValue, 0, len(payload))
-	for key, value := range payload {
-		if skipField(key) {
-			continue
-		}
-		if attr, ok := attrutil.FromValue(key, value); ok {
-			attrs = append(attrs, attr)
-		}
-	}
-	return attrs
-}
-
-func skipField(key string) bool {
-	switch key {
-	case zerolog.TimestampFieldName, zerolog.LevelFieldName, zerolog.MessageFieldName, traceIDField, spanIDField, ServiceNameKey, DeploymentEnvironmentNameKey:
-		return true
-	default:
-		return false
-	}
-}
-
-func toLogKeyValue(attr attribute.KeyValue) otelLog.KeyValue {
-	key := string(attr.Key)
-	switch attr.Value.Type() {
-	case attribute.BOOL:
-		return otelLog.Bool(key, attr.Value.AsBool())
-	case attribute.INT64:
-		return otelLog.Int64(key, attr.Value.AsInt64())
-	case attribute.FLOAT64:
-		return otelLog.Float64(key, attr.Value.AsFloat64())
-	case attribute.STRING:
-		return otelLog.String(key, attr.Value.AsString())
-	default:
-		return otelLog.String(key, attr.Value.Emit())
-	}
+func keyIs(raw []byte, name string) bool {
+	return string(raw) == name
 }
 
 func toSeverity(level string) otelLog.Severity {
@@ -405,3 +607,17 @@ func toSeverity(level string) otelLog.Severity {
 		return otelLog.SeverityUndefined
 	}
 }
+
+func spoolOptions(cfg OTLPConfig, clk clock.Clock) []spool.Option {
+	opts := []spool.Option{
+		spool.WithMaxAge(cfg.SpoolMaxAge),
+		spool.WithClock(clk),
+		spool.WithPacing(cfg.MaxRequestsPerSecond, cfg.MaxBytesPerSecond),
+	}
+	if onDrop := cfg.OnSpoolDrop; onDrop != nil {
+		opts = append(opts, spool.WithDropFunc(func(token string, payload []byte, reason spool.DropReason) {
+			onDrop(token, payload, string(reason))
+		}))
+	}
+	return opts
+}
@@ -0,0 +1,195 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+
+	otelLog "go.opentelemetry.io/otel/log"
+)
+
+// scanJSONObject walks the top-level members of a JSON object in a single pass without building
+// an intermediate map. fn receives the raw key (without quotes) and the raw value bytes. It reports
+// whether data held exactly one well-formed object; fn may already have been invoked when it returns false.
+func scanJSONObject(data []byte, fn func(key, value []byte)) bool {
+	i := skipSpace(data, 0)
+	if i >= len(data) || data[i] != '{' {
+		return false
+	}
+	i = skipSpace(data, i+1)
+	if i < len(data) && data[i] == '}' {
+		return skipSpace(data, i+1) == len(data)
+	}
+
+	for i < len(data) {
+		if data[i] != '"' {
+			return false
+		}
+		keyEnd, ok := skipString(data, i)
+		if !ok {
+			return false
+		}
+		key := data[i+1 : keyEnd-1]
+
+		i = skipSpace(data, keyEnd)
+		if i >= len(data) || data[i] != ':' {
+			return false
+		}
+		i = skipSpace(data, i+1)
+
+		valueEnd, ok := skipValue(data, i)
+		if !ok {
+			return false
+		}
+		fn(key, data[i:valueEnd])
+
+		i = skipSpace(data, valueEnd)
+		if i >= len(data) {
+			return false
+		}
+		switch data[i] {
+		case ',':
+			i = skipSpace(data, i+1)
+		case '}':
+			return skipSpace(data, i+1) == len(data)
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+func skipSpace(data []byte, i int) int {
+	for i < len(data) {
+		switch data[i] {
+		case ' ', '\t', '\n', '\r':
+			i++
+		default:
+			return i
+		}
+	}
+	return i
+}
+
+// skipString returns the index just past the closing quote of the string starting at data[i].
+func skipString(data []byte, i int) (int, bool) {
+	for j := i + 1; j < len(data); j++ {
+		switch data[j] {
+		case '\\':
+			j++
+		case '"':
+			return j + 1, true
+		}
+	}
+	return 0, false
+}
+
+// skipValue returns the index just past the JSON value starting at data[i].
+func skipValue(data []byte, i int) (int, bool) {
+	if i >= len(data) {
+		return 0, false
+	}
+	switch data[i] {
+	case '"':
+		return skipString(data, i)
+	case '{', '[':
+		depth := 0
+		for j := i; j < len(data); j++ {
+			switch data[j] {
+			case '"':
+				end, ok := skipString(data, j)
+				if !ok {
+					return 0, false
+				}
+				j = end - 1
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+				if depth == 0 {
+					return j + 1, true
+				}
+			}
+		}
+		return 0, false
+	default:
+		j := i
+		for j < len(data) {
+			switch data[j] {
+			case ',', '}', ']', ' ', '\t', '\n', '\r':
+				if j == i {
+					return 0, false
+				}
+				return j, true
+			}
+			j++
+		}
+		return 0, false
+	}
+}
+
+// jsonString decodes a raw JSON string value, including its quotes.
+func jsonString(raw []byte) (string, bool) {
+	if len(raw) < 2 || raw[0] != '"' || raw[len(raw)-1] != '"' {
+		return "", false
+	}
+	content := raw[1 : len(raw)-1]
+	if bytes.IndexByte(content, '\\') < 0 {
+		return string(content), true
+	}
+	var decoded string
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return "", false
+	}
+	return decoded, true
+}
+
+// jsonKeyValue converts a raw JSON member into a log attribute, preserving integers as Int64
+// and keeping nested objects and arrays as their JSON text.
+func jsonKeyValue(key string, raw []byte) otelLog.KeyValue {
+	switch raw[0] {
+	case '"':
+		if value, ok := jsonString(raw); ok {
+			return otelLog.String(key, value)
+		}
+		return otelLog.String(key, string(raw))
+	case 't':
+		return otelLog.Bool(key, true)
+	case 'f':
+		return otelLog.Bool(key, false)
+	case 'n':
+		return otelLog.String(key, "")
+	case '{', '[':
+		return otelLog.String(key, string(raw))
+	}
+
+	if bytes.IndexAny(raw, ".eE") < 0 {
+		if value, err := strconv.ParseInt(string(raw), 10, 64); err == nil {
+			return otelLog.Int64(key, value)
+		}
+	}
+	if value, err := strconv.ParseFloat(string(raw), 64); err == nil {
+		return otelLog.Float64(key, value)
+	}
+	return otelLog.String(key, string(raw))
+}
+
+// levelSeverity maps zerolog's lowercase level names without allocating, deferring to toSeverity otherwise.
+func levelSeverity(raw []byte) otelLog.Severity {
+	switch string(raw) {
+	case "trace":
+		return otelLog.SeverityTrace
+	case "debug":
+		return otelLog.SeverityDebug
+	case "info":
+		return otelLog.SeverityInfo
+	case "warn":
+		return otelLog.SeverityWarn
+	case "error":
+		return otelLog.SeverityError
+	case "fatal", "panic":
+		return otelLog.SeverityFatal
+	default:
+		return toSeverity(string(raw))
+	}
+}
@@ -0,0 +1,114 @@
+package logger
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	otelLog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/sdk/log"
+)
+
+const benchmarkLogLine = `{"level":"info","service_name":"bench","deployment_environment_name":"test","user":"alice","attempt":3,"latency":1.25,"ok":true,"time":"2024-06-02T15:04:05.0000009Z","caller":"/srv/app/main.go:42","trace_id":"000000000000000000000000000000ab","span_id":"00000000000000ef","message":"request served"}` + "\n"
+
+func recordAttributes(record otelLog.Record) map[string]otelLog.Value {
+	attrs := make(map[string]otelLog.Value, record.AttributesLen())
+	record.WalkAttributes(func(kv otelLog.KeyValue) bool {
+		attrs[kv.Key] = kv.Value
+		return true
+	})
+	return attrs
+}
+
+func TestBuildRecordPreservesValueKinds(t *testing.T) {
+	record, spanCtx := buildRecord([]byte(`{"level":"error","count":7,"big":12345678901234567890,"ratio":0.5,"ok":false,"nothing":null,"stack":[{"function":"main.run","location":"main.go:1"}],"quoted":"a \"b\" é","message":"kinds"}`), time.Now(), nil)
+
+	if record.Severity() != otelLog.SeverityError {
+		t.Fatalf("unexpected severity: %v", record.Severity())
+	}
+	if record.Body().AsString() != "kinds" {
+		t.Fatalf("unexpected body: %q", record.Body().AsString())
+	}
+	if spanCtx.IsValid() {
+		t.Fatal("unexpected span context")
+	}
+
+	attrs := recordAttributes(record)
+	if got := attrs["count"]; got.Kind() != otelLog.KindInt64 || got.AsInt64() != 7 {
+		t.Fatalf("unexpected count attribute: %v", got)
+	}
+	if got := attrs["big"]; got.Kind() != otelLog.KindFloat64 {
+		t.Fatalf("expected overflowing integer to fall back to float, got %v", got.Kind())
+	}
+	if got := attrs["ratio"]; got.Kind() != otelLog.KindFloat64 || got.AsFloat64() != 0.5 {
+		t.Fatalf("unexpected ratio attribute: %v", got)
+	}
+	if got := attrs["ok"]; got.Kind() != otelLog.KindBool || got.AsBool() {
+		t.Fatalf("unexpected ok attribute: %v", got)
+	}
+	if got := attrs["nothing"]; got.AsString() != "" {
+		t.Fatalf("unexpected null attribute: %v", got)
+	}
+	if got := attrs["stack"].AsString(); got != `[{"function":"main.run","location":"main.go:1"}]` {
+		t.Fatalf("expected nested value kept as JSON, got %q", got)
+	}
+	if got := attrs["quoted"].AsString(); got != "a \"b\" é" {
+		t.Fatalf("unexpected unescaped string: %q", got)
+	}
+}
+
+func TestBuildRecordMalformedFallsBackToBody(t *testing.T) {
+	for _, input := range []string{`{"message":"half"`, `{"a":1}{"b":2}`, `{"a" 1}`, `[1,2]`} {
+		record, spanCtx := buildRecord([]byte(input), time.Now(), nil)
+		if record.Body().AsString() != input {
+			t.Fatalf("%s: unexpected body %q", input, record.Body().AsString())
+		}
+		if record.AttributesLen() != 0 {
+			t.Fatalf("%s: expected no attributes, got %d", input, record.AttributesLen())
+		}
+		if spanCtx.IsValid() {
+			t.Fatalf("%s: unexpected span context", input)
+		}
+	}
+}
+
+func TestBuildRecordAllocationBound(t *testing.T) {
+	entry := []byte(benchmarkLogLine)
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = buildRecord(entry, time.Now(), nil)
+	})
+	// Only retained attribute keys and string values should allocate; reserved fields are matched in place.
+	if allocs > 12 {
+		t.Fatalf("buildRecord allocations regressed: %.0f per run", allocs)
+	}
+}
+
+func BenchmarkBuildRecord(b *testing.B) {
+	entry := []byte(benchmarkLogLine)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(entry)))
+	for b.Loop() {
+		_, _ = buildRecord(entry, time.Now(), nil)
+	}
+}
+
+func BenchmarkOTLPWriterWrite(b *testing.B) {
+	provider := log.NewLoggerProvider(log.WithProcessor(log.NewSimpleProcessor(discardLogExporter{})))
+	b.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+	writer := &otlpWriter{logger: provider.Logger("bench")}
+
+	entry := []byte(benchmarkLogLine)
+	b.ReportAllocs()
+	b.SetBytes(int64(len(entry)))
+	for b.Loop() {
+		_, _ = writer.Write(entry)
+	}
+}
+
+type discardLogExporter struct{}
+
+func (discardLogExporter) Export(context.Context, []log.Record) error { return nil }
+
+func (discardLogExporter) Shutdown(context.Context) error { return nil }
+
+func (discardLogExporter) ForceFlush(context.Context) error { return nil }
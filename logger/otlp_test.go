@@ -41,6 +41,50 @@ func TestOTLPWriterEmitsRecords(t *testing.T) {
 	}
 }
 
+func TestLoggerFlushExportsBatchedRecords(t *testing.T) {
+	exporter := &fakeExporter{}
+	provider := log.NewLoggerProvider(log.WithProcessor(log.NewBatchProcessor(exporter, log.WithExportInterval(time.Hour))))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	registry := newWriterRegistry()
+	registry.add("otlp", &otlpWriter{logger: provider.Logger("test"), provider: provider})
+	l := &Logger{writers: registry}
+
+	if _, err := registry.writer().Write([]byte(`{"level":"info","message":"buffered"}`)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := l.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if len(exporter.records) != 1 {
+		t.Fatalf("expected flushed record, got %d", len(exporter.records))
+	}
+}
+
+func TestOTLPWriterFlushesFatalAndPanicEntries(t *testing.T) {
+	exporter := &fakeExporter{}
+	provider := log.NewLoggerProvider(log.WithProcessor(log.NewBatchProcessor(exporter, log.WithExportInterval(time.Hour))))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+	writer := &otlpWriter{logger: provider.Logger("test"), provider: provider, flushTimeout: time.Second}
+
+	for _, entry := range []string{
+		`{"level":"error","message":"batched"}`,
+		`{"level":"panic","message":"crashing"}`,
+		`{"level":"fatal","message":"exiting"}`,
+	} {
+		if _, err := writer.Write([]byte(entry)); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+
+	if len(exporter.records) != 3 {
+		t.Fatalf("expected panic and fatal entries to flush the batch, got %d records", len(exporter.records))
+	}
+	if got := exporter.records[2].Severity(); got != otelLog.SeverityFatal {
+		t.Fatalf("expected fatal severity, got %v", got)
+	}
+}
+
 func TestLoggerOTLPSpoolRecoversAfterFailure(t *testing.T) {
 	queueDir := t.TempDir()
 
@@ -115,15 +159,38 @@ func TestLoggerOTLPSpoolRecoversAfterFailure(t *testing.T) {
 	}
 }
 
+func TestBuildResourceIncludesInstanceAndRunID(t *testing.T) {
+	resource, err := buildResource(context.Background(), Config{ServiceName: "svc", ServiceInstanceID: "pod-1", RunID: "run-1"})
+	if err != nil {
+		t.Fatalf("buildResource: %v", err)
+	}
+	if got, _ := resource.Set().Value(semconv.ServiceInstanceIDKey); got.AsString() != "pod-1" {
+		t.Fatalf("service.instance.id = %q", got.AsString())
+	}
+	if got, _ := resource.Set().Value(attribute.Key(processRunIDAttr)); got.AsString() != "run-1" {
+		t.Fatalf("process.run_id = %q", got.AsString())
+	}
+	if !defaultNaming.coreField(ServiceInstanceIDKey) || !defaultNaming.coreField(ProcessRunIDKey) {
+		t.Fatal("identity fields should not be exported as record attributes")
+	}
+}
+
 func TestConfigureExporterRejectsUnknown(t *testing.T) {
-	_, _, _, err := configureExporter(context.Background(), OTLPConfig{Endpoint: "collector:4318", Protocol: "udp"})
+	_, _, _, err := configureExporter(context.Background(), OTLPConfig{Endpoint: "collector:4318", Protocol: "udp"}, nil)
 	if err == nil {
 		t.Fatal("expected error for unsupported exporter")
 	}
 }
 
+func TestConfigureExporterSecondaryRequiresSpool(t *testing.T) {
+	cfg := OTLPConfig{Endpoint: "collector:4318", Protocol: "http", Secondary: SecondaryConfig{Endpoint: "backup:4318"}}
+	if _, _, _, err := configureExporter(context.Background(), cfg, nil); err == nil {
+		t.Fatal("expected error for secondary endpoint without spool")
+	}
+}
+
 func TestBuildResourceIncludesServiceAndEnvironment(t *testing.T) {
-	resource, err := buildResource(context.Background(), "svc", "prod")
+	resource, err := buildResource(context.Background(), Config{ServiceName: "svc", Environment: "prod"})
 	if err != nil {
 		t.Fatalf("buildResource: %v", err)
 	}
@@ -143,18 +210,18 @@ func TestBuildResourceIncludesServiceAndEnvironment(t *testing.T) {
 func TestBuildRecordFromStructuredPayload(t *testing.T) {
 	ts := time.Date(2024, time.June, 2, 15, 4, 5, 900, time.UTC)
 	payload, err := json.Marshal(map[string]any{
-		"time":        ts.Format(time.RFC3339Nano),
-		"level":       "warn",
-		"message":     "structured",
-		traceIDField:  "000000000000000000000000000000ab",
-		spanIDField:   "00000000000000ef",
-		"http.status": 200,
+		"time":                ts.Format(time.RFC3339Nano),
+		"level":               "warn",
+		"message":             "structured",
+		defaultNaming.traceID: "000000000000000000000000000000ab",
+		defaultNaming.spanID:  "00000000000000ef",
+		"http.status":         200,
 	})
 	if err != nil {
 		t.Fatalf("json.Marshal: %v", err)
 	}
 
-	record, spanCtx := buildRecord(payload)
+	record, spanCtx := buildRecord(payload, time.Now(), nil)
 	if record.Severity() != otelLog.SeverityWarn {
 		t.Fatalf("unexpected severity: %v", record.Severity())
 	}
@@ -182,7 +249,7 @@ func TestBuildRecordFromStructuredPayload(t *testing.T) {
 }
 
 func TestBuildRecordFallbackBody(t *testing.T) {
-	record, spanCtx := buildRecord([]byte("  plain text  "))
+	record, spanCtx := buildRecord([]byte("  plain text  "), time.Now(), nil)
 	if record.Body().AsString() != "plain text" {
 		t.Fatalf("unexpected body: %q", record.Body().AsString())
 	}
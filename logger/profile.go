@@ -0,0 +1,87 @@
+package logger
+
+import (
+	"encoding/binary"
+	"strconv"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Output profiles accepted by Config.Profile.
+const (
+	ProfileECS     = "ecs"
+	ProfileDatadog = "datadog"
+)
+
+// ECS field names and the schema version stamped on every entry under the ECS profile.
+// Elasticsearch maps dotted keys such as service.name onto the nested ECS objects.
+const (
+	ecsVersion          = "8.11.0"
+	ecsVersionField     = "ecs.version"
+	ecsEnvironmentField = "service.environment"
+	ecsErrorField       = "error.message"
+	ecsStackField       = "error.stack_trace"
+)
+
+// Datadog reserved attributes. Datadog correlates logs with traces through the low 64 bits of the
+// trace and span IDs in decimal, so these are written next to the hex IDs rather than replacing them.
+const (
+	datadogTraceIDField     = "dd.trace_id"
+	datadogSpanIDField      = "dd.span_id"
+	datadogServiceField     = "dd.service"
+	datadogEnvironmentField = "dd.env"
+)
+
+// ecsFieldNames are the ECS core keys used for every FieldNames entry left empty.
+var ecsFieldNames = FieldNamesConfig{
+	Time:        "@timestamp",
+	Level:       "log.level",
+	Message:     "message",
+	TraceID:     "trace.id",
+	SpanID:      "span.id",
+	ServiceName: "service.name",
+}
+
+// profileFieldNames fills the names left empty in names with the defaults of profile.
+func profileFieldNames(profile string, names FieldNamesConfig) FieldNamesConfig {
+	fill := func(field *string, fallback string) {
+		if *field == "" {
+			*field = fallback
+		}
+	}
+	switch profile {
+	case ProfileECS:
+		fill(&names.Time, ecsFieldNames.Time)
+		fill(&names.Level, ecsFieldNames.Level)
+		fill(&names.Message, ecsFieldNames.Message)
+		fill(&names.TraceID, ecsFieldNames.TraceID)
+		fill(&names.SpanID, ecsFieldNames.SpanID)
+		fill(&names.ServiceName, ecsFieldNames.ServiceName)
+	case ProfileDatadog:
+		fill(&names.ServiceName, datadogServiceField)
+	}
+	return names
+}
+
+// applyProfile switches the error and environment keys to the names of profile.
+func (n *fieldNaming) applyProfile(profile string) {
+	switch profile {
+	case ProfileDatadog:
+		n.datadogIDs = true
+		n.environment = datadogEnvironmentField
+	case ProfileECS:
+		n.to.error = ecsErrorField
+		n.to.stack = ecsStackField
+		n.textStacks = true
+		n.environment = ecsEnvironmentField
+	}
+}
+
+// addDatadogIDs writes the Datadog decimal trace and span IDs of spanCtx onto event.
+func addDatadogIDs(event *zerolog.Event, spanCtx trace.SpanContext) {
+	tid := spanCtx.TraceID()
+	sid := spanCtx.SpanID()
+	event.Str(datadogTraceIDField, strconv.FormatUint(binary.BigEndian.Uint64(tid[8:]), 10))
+	event.Str(datadogSpanIDField, strconv.FormatUint(binary.BigEndian.Uint64(sid[:]), 10))
+}
@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestECSProfileEmitsECSKeys(t *testing.T) {
+
+	var buf bytes.Buffer
+	log, err := New(context.Background(), Config{
+		Enabled:     true,
+		Console:     false,
+		ServiceName: "checkout",
+		Environment: "production",
+		Profile:     ProfileECS,
+		Writers:     []io.Writer{&buf},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = log.Close() })
+
+	tp := sdktrace.NewTracerProvider()
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	ctx, span := tp.Tracer("ecs").Start(context.Background(), "op")
+	log.Warn().Ctx(ctx).Stack().Err(pkgerrors.Wrap(errors.New("boom"), "charge")).Msg("failed")
+	span.End()
+
+	var entry map[string]any
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &entry); err != nil {
+		t.Fatalf("decode entry: %v", err)
+	}
+	for key, want := range map[string]any{
+		"log.level":           "warn",
+		"message":             "failed",
+		"error.message":       "charge: boom",
+		"trace.id":            span.SpanContext().TraceID().String(),
+		"span.id":             span.SpanContext().SpanID().String(),
+		"service.name":        "checkout",
+		"service.environment": "production",
+		"ecs.version":         ecsVersion,
+	} {
+		if got := entry[key]; got != want {
+			t.Fatalf("%s: got %v want %v (entry %v)", key, got, want, entry)
+		}
+	}
+	if _, ok := entry["@timestamp"]; !ok {
+		t.Fatalf("expected @timestamp key, got %v", entry)
+	}
+	if stack, ok := entry["error.stack_trace"].(string); !ok || !strings.Contains(stack, "profile_test.go") {
+		t.Fatalf("expected textual error.stack_trace, got %v", entry["error.stack_trace"])
+	}
+}
+
+func TestDatadogProfileAddsDecimalIDs(t *testing.T) {
+
+	var buf bytes.Buffer
+	log, err := New(context.Background(), Config{
+		Enabled:     true,
+		Console:     false,
+		ServiceName: "checkout",
+		Environment: "production",
+		Profile:     ProfileDatadog,
+		Writers:     []io.Writer{&buf},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = log.Close() })
+
+	tp := sdktrace.NewTracerProvider()
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	ctx, span := tp.Tracer("datadog").Start(context.Background(), "op")
+	log.Info().Ctx(ctx).Msg("correlated")
+	span.End()
+
+	var entry map[string]any
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &entry); err != nil {
+		t.Fatalf("decode entry: %v", err)
+	}
+	tid := span.SpanContext().TraceID()
+	sid := span.SpanContext().SpanID()
+	for key, want := range map[string]any{
+		"dd.trace_id": strconv.FormatUint(binary.BigEndian.Uint64(tid[8:]), 10),
+		"dd.span_id":  strconv.FormatUint(binary.BigEndian.Uint64(sid[:]), 10),
+		"trace_id":    tid.String(),
+		"dd.service":  "checkout",
+		"dd.env":      "production",
+	} {
+		if got := entry[key]; got != want {
+			t.Fatalf("%s: got %v want %v (entry %v)", key, got, want, entry)
+		}
+	}
+}
+
+func TestECSProfileRejectsUnknownProfile(t *testing.T) {
+	if err := (Config{Enabled: true, Profile: "splunk"}).Validate(); err == nil {
+		t.Fatal("expected unknown profile to fail validation")
+	}
+}
@@ -0,0 +1,22 @@
+package logger
+
+import (
+	otelLog "go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/noop"
+)
+
+// LoggerProvider returns the OpenTelemetry LoggerProvider behind the OTLP writer, so libraries that
+// emit OTel log records share its resource, processors, and spool instead of building their own
+// exporter. Records emitted through it bypass zerolog hooks and writers.
+// Returns a no-op provider if receiver is nil or OTLP export is disabled.
+func (l *Logger) LoggerProvider() otelLog.LoggerProvider {
+	if l == nil || l.otlp == nil {
+		return noop.NewLoggerProvider()
+	}
+	return l.otlp
+}
+
+// Provider returns the OpenTelemetry LoggerProvider of the global logger.
+func Provider() otelLog.LoggerProvider {
+	return Global().LoggerProvider()
+}
@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	otelLog "go.opentelemetry.io/otel/log"
+)
+
+func TestLoggerProviderSharesOTLPPipeline(t *testing.T) {
+	var received atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		_ = r.Body.Close()
+		if r.URL.Path == "/v1/logs" {
+			received.Add(1)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	l, err := New(context.Background(), Config{
+		Enabled:     true,
+		ServiceName: "bridge",
+		Console:     false,
+		Writers:     []io.Writer{io.Discard},
+		OTLP: OTLPConfig{
+			Enabled:  true,
+			Endpoint: server.URL,
+		},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = l.Close() })
+
+	var record otelLog.Record
+	record.SetBody(otelLog.StringValue("from a third-party library"))
+	record.SetSeverity(otelLog.SeverityInfo)
+	l.LoggerProvider().Logger("third-party").Emit(context.Background(), record)
+
+	if err := l.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if received.Load() == 0 {
+		t.Fatal("expected record emitted through the provider to reach the OTLP endpoint")
+	}
+}
+
+func TestLoggerProviderNoopWithoutOTLP(t *testing.T) {
+	var l *Logger
+	if l.LoggerProvider() == nil {
+		t.Fatal("expected no-op provider for nil logger")
+	}
+	previous := Global()
+	t.Cleanup(func() { Use(previous) })
+	Use(nil)
+	if Provider().Logger("x").Enabled(context.Background(), otelLog.EnabledParameters{}) {
+		t.Fatal("expected disabled global provider without OTLP")
+	}
+}
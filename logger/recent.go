@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// RecentConfig keeps the last Size entries in memory, served as JSON by RecentHandler, so what the
+// process logged can be read even when the file or OTLP pipeline is the thing that is broken.
+// Zero Size turns the buffer off.
+type RecentConfig struct {
+	Size int `validate:"gte=0"`
+}
+
+// recentBuffer is a ring of the most recent entries, written to like any other sink.
+type recentBuffer struct {
+	mu      sync.Mutex
+	entries [][]byte
+	next    int
+	full    bool
+}
+
+func newRecentBuffer(size int) *recentBuffer {
+	return &recentBuffer{entries: make([][]byte, size)}
+}
+
+func (b *recentBuffer) Write(p []byte) (int, error) {
+	entry := bytes.Clone(bytes.TrimRight(p, "\n"))
+	b.mu.Lock()
+	b.entries[b.next] = entry
+	b.next = (b.next + 1) % len(b.entries)
+	if b.next == 0 {
+		b.full = true
+	}
+	b.mu.Unlock()
+	return len(p), nil
+}
+
+// snapshot returns up to limit of the newest entries, oldest first; limit <= 0 returns them all.
+func (b *recentBuffer) snapshot(limit int) [][]byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	count := b.next
+	if b.full {
+		count = len(b.entries)
+	}
+	if limit > 0 && limit < count {
+		count = limit
+	}
+	out := make([][]byte, 0, count)
+	for idx := b.next - count; idx < b.next; idx++ {
+		out = append(out, b.entries[(idx+len(b.entries))%len(b.entries)])
+	}
+	return out
+}
+
+// Recent returns the entries kept by Config.Recent, oldest first, or nil when the buffer is off.
+func (l *Logger) Recent() [][]byte {
+	if l.recent == nil {
+		return nil
+	}
+	return l.recent.snapshot(0)
+}
+
+// RecentHandler serves the entries kept by Config.Recent as a JSON array, oldest first. The limit
+// query parameter returns only the newest entries. It answers 404 when the buffer is off. Mount it
+// on an internal debug listener: the entries are the raw log output.
+func (l *Logger) RecentHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveRecent(w, r, l)
+	})
+}
+
+// RecentHandler is Logger.RecentHandler for the global logger, resolved on every request so it can
+// be mounted before Init.
+func RecentHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		serveRecent(w, r, Global())
+	})
+}
+
+func serveRecent(w http.ResponseWriter, r *http.Request, l *Logger) {
+	if l.recent == nil {
+		http.Error(w, "logger: recent entry buffer is disabled", http.StatusNotFound)
+		return
+	}
+	limit := 0
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 {
+			http.Error(w, "logger: invalid limit", http.StatusBadRequest)
+			return
+		}
+		limit = parsed
+	}
+
+	entries := l.recent.snapshot(limit)
+	out := make([]json.RawMessage, 0, len(entries))
+	for _, entry := range entries {
+		if !json.Valid(entry) {
+			// Hooks may rewrite an entry into something other than JSON; keep it as a string.
+			entry, _ = json.Marshal(string(entry))
+		}
+		out = append(out, entry)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(out)
+}
@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRecentHandlerServesNewestEntries(t *testing.T) {
+	log, err := New(context.Background(), Config{
+		Enabled: true,
+		Console: false,
+		Writers: []io.Writer{io.Discard},
+		Recent:  RecentConfig{Size: 2},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = log.Close() })
+
+	for _, msg := range []string{"first", "second", "third"} {
+		log.Info().Msg(msg)
+	}
+	if got := len(log.Recent()); got != 2 {
+		t.Fatalf("expected 2 kept entries, got %d", got)
+	}
+
+	messages := func(target string) []string {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		log.RecentHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s: status %d", target, rec.Code)
+		}
+		var entries []map[string]any
+		if err := json.Unmarshal(rec.Body.Bytes(), &entries); err != nil {
+			t.Fatalf("decode %s: %v", rec.Body.String(), err)
+		}
+		var out []string
+		for _, entry := range entries {
+			out = append(out, entry["message"].(string))
+		}
+		return out
+	}
+	if got := messages("/debug/logs"); len(got) != 2 || got[0] != "second" || got[1] != "third" {
+		t.Fatalf("expected the two newest entries oldest first, got %v", got)
+	}
+	if got := messages("/debug/logs?limit=1"); len(got) != 1 || got[0] != "third" {
+		t.Fatalf("expected only the newest entry, got %v", got)
+	}
+}
+
+func TestRecentHandlerReportsDisabledBuffer(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Nop().RecentHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/logs", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 without a buffer, got %d", rec.Code)
+	}
+}
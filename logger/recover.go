@@ -0,0 +1,54 @@
+package logger
+
+import (
+	"context"
+	"fmt"
+
+	pkgerrors "github.com/pkg/errors"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const panicLogMessage = "panic recovered"
+
+// PanicError converts a recovered panic value into an error carrying the panicking call stack.
+// Must be called from the deferred function that recovered the panic so the stack is still intact.
+func PanicError(recovered any) error {
+	if recovered == nil {
+		return nil
+	}
+	if err, ok := recovered.(error); ok {
+		return pkgerrors.Wrap(err, "panic")
+	}
+	return pkgerrors.New(fmt.Sprintf("panic: %v", recovered))
+}
+
+// RecoverAndLog recovers a panic, logs it with its stack, and marks the active span as failed.
+// It must be invoked directly via defer.
+func (l *Logger) RecoverAndLog(ctx context.Context) {
+	if recovered := recover(); recovered != nil {
+		l.LogPanic(ctx, recovered)
+	}
+}
+
+// LogPanic records an already recovered panic value and returns it as an error.
+func (l *Logger) LogPanic(ctx context.Context, recovered any) error {
+	err := PanicError(recovered)
+	if err == nil {
+		return nil
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if l != nil && l.Logger != nil {
+		l.Error().Ctx(ctx).Bool("panic", true).Err(err).Msg(panicLogMessage)
+	}
+
+	span := trace.SpanFromContext(ctx)
+	if span.IsRecording() {
+		span.RecordError(err, trace.WithStackTrace(true))
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return err
+}
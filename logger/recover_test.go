@@ -0,0 +1,93 @@
+package logger
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestRecoverAndLogRecordsPanic(t *testing.T) {
+	log, buf := newBufferedLogger(t, "recover-logger", "debug")
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() {
+		_ = tp.Shutdown(context.Background())
+	})
+
+	ctx, span := tp.Tracer("logger/recover").Start(context.Background(), "recover-span")
+	func() {
+		defer log.RecoverAndLog(ctx)
+		panic("boom")
+	}()
+	span.End()
+
+	entry := decodeLogLine(t, buf.Bytes())
+	if got := entry["message"]; got != panicLogMessage {
+		t.Fatalf("unexpected message: %v", got)
+	}
+	if got := entry["panic"]; got != true {
+		t.Fatalf("expected panic flag, got %v", got)
+	}
+	if msg, _ := entry["error"].(string); !strings.Contains(msg, "boom") {
+		t.Fatalf("unexpected error field: %v", entry["error"])
+	}
+	if stack, ok := entry["stack"].([]any); !ok || len(stack) == 0 {
+		t.Fatalf("expected stack trace, got %v", entry["stack"])
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	if status := spans[0].Status(); status.Code != codes.Error {
+		t.Fatalf("expected error status, got %v", status.Code)
+	}
+	var hasException bool
+	for _, event := range spans[0].Events() {
+		if event.Name == "exception" {
+			hasException = true
+		}
+	}
+	if !hasException {
+		t.Fatalf("expected exception event, got %v", spans[0].Events())
+	}
+}
+
+func TestPanicErrorWrapsErrors(t *testing.T) {
+	if PanicError(nil) != nil {
+		t.Fatal("expected nil error for nil panic value")
+	}
+
+	cause := context.Canceled
+	err := PanicError(cause)
+	if !strings.HasPrefix(err.Error(), "panic: ") {
+		t.Fatalf("unexpected error message: %q", err.Error())
+	}
+	if _, ok := err.(stackTracer); !ok {
+		t.Fatalf("expected stack carrying error, got %T", err)
+	}
+}
+
+func TestLogPanicNilLoggerStillMarksSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() {
+		_ = tp.Shutdown(context.Background())
+	})
+
+	ctx, span := tp.Tracer("logger/recover").Start(context.Background(), "nil-logger")
+	var log *Logger
+	if err := log.LogPanic(ctx, "nil logger"); err == nil {
+		t.Fatal("expected panic error")
+	}
+	span.End()
+
+	if status := recorder.Ended()[0].Status(); status.Code != codes.Error {
+		t.Fatalf("expected error status, got %v", status.Code)
+	}
+}
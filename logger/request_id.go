@@ -0,0 +1,39 @@
+package logger
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/baggage"
+)
+
+// RequestIDField holds the request ID of entries logged with a context that carries one.
+const RequestIDField = "request_id"
+
+// RequestIDBaggageKey is the baggage member carrying the request ID across services.
+const RequestIDBaggageKey = "request.id"
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns ctx carrying id, which entries logged with Ctx(ctx) record as
+// request_id whether or not a span is active. Empty ids leave ctx unchanged.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID stored by ContextWithRequestID, falling back to the
+// request.id baggage member so services downstream of the one that assigned it log it too.
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+		return id
+	}
+	return baggage.FromContext(ctx).Member(RequestIDBaggageKey).Value()
+}
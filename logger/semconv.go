@@ -0,0 +1,39 @@
+package logger
+
+import "strings"
+
+// OpenTelemetry log data model keys emitted when Config.SemconvFields is enabled.
+const (
+	semconvTimestampField  = "timestamp"
+	semconvSeverityField   = "severity_text"
+	semconvBodyField       = "body"
+	semconvTraceIDField    = "trace_id"
+	semconvSpanIDField     = "span_id"
+	semconvExceptionField  = "exception.message"
+	semconvStacktraceField = "exception.stacktrace"
+)
+
+// applySemconv switches the keys to the OTel log data model names.
+func (n *fieldNaming) applySemconv() {
+	n.to = reservedFields{
+		time:    semconvTimestampField,
+		level:   semconvSeverityField,
+		message: semconvBodyField,
+		error:   semconvExceptionField,
+		stack:   semconvStacktraceField,
+	}
+	n.textStacks = true
+	n.traceID = semconvTraceIDField
+	n.spanID = semconvSpanIDField
+}
+
+// writeFrameText appends a frame in the layout of a Go panic trace: the function, then its tab-indented location.
+func writeFrameText(b *strings.Builder, function, location string) {
+	if function != "" {
+		b.WriteString(function)
+		b.WriteByte('\n')
+	}
+	b.WriteByte('\t')
+	b.WriteString(location)
+	b.WriteByte('\n')
+}
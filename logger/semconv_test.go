@@ -0,0 +1,62 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestSemconvFieldsRenameKeys(t *testing.T) {
+
+	var buf bytes.Buffer
+	log, err := New(context.Background(), Config{
+		Enabled:       true,
+		Console:       false,
+		SemconvFields: true,
+		Writers:       []io.Writer{&buf},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = log.Close() })
+
+	tp := sdktrace.NewTracerProvider()
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	ctx, span := tp.Tracer("semconv").Start(context.Background(), "op")
+	log.Err(errors.New("boom")).Ctx(ctx).Msg("failed")
+	span.End()
+
+	var entry map[string]any
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &entry); err != nil {
+		t.Fatalf("decode entry: %v", err)
+	}
+	for key, want := range map[string]any{
+		"severity_text":     "error",
+		"body":              "failed",
+		"exception.message": "boom",
+		"trace_id":          span.SpanContext().TraceID().String(),
+		"span_id":           span.SpanContext().SpanID().String(),
+	} {
+		if got := entry[key]; got != want {
+			t.Fatalf("%s: got %v want %v (entry %v)", key, got, want, entry)
+		}
+	}
+	if _, ok := entry["timestamp"]; !ok {
+		t.Fatalf("expected timestamp key, got %v", entry)
+	}
+	stack, ok := entry["exception.stacktrace"].(string)
+	if !ok || !strings.Contains(stack, "semconv_test.go") {
+		t.Fatalf("expected textual stacktrace, got %v", entry["exception.stacktrace"])
+	}
+	for _, legacy := range []string{"level", "message", "error", "stack", "time"} {
+		if _, ok := entry[legacy]; ok {
+			t.Fatalf("unexpected legacy key %q in %v", legacy, entry)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package logger
+
+import (
+	"path"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
+
+const defaultSourceLinkTemplate = "{repo}/blob/{revision}/{path}#L{line}"
+
+// SourceLinkConfig rewrites the location of stack frames from mapped modules into a link to the
+// source at the running revision, so log UIs such as Grafana render frames as clickable URLs.
+// Template expands {repo}, {revision}, {path} (the file relative to the module root), and {line};
+// the default suits GitHub and Gitea, and GitLab needs "{repo}/-/blob/{revision}/{path}#L{line}".
+// Repositories maps module paths to repository URLs; when empty, the main module maps to
+// "https://" followed by its path without a /vN major version suffix. Frames of package main link
+// through the main package's path. Revision defaults to the vcs.revision stamped in the build info.
+// Without a revision, or for frames of unmapped modules, locations are left as file paths.
+type SourceLinkConfig struct {
+	Enabled      bool
+	Template     string
+	Repositories map[string]string
+	Revision     string
+}
+
+// sourceLinker is the compiled SourceLinkConfig.
+type sourceLinker struct {
+	template string
+	repos    map[string]string
+	revision string
+	// mainPackage is the import path runtime reports as "main" in function names.
+	mainPackage string
+}
+
+func newSourceLinker(cfg SourceLinkConfig) *sourceLinker {
+	info, _ := debug.ReadBuildInfo()
+	return buildSourceLinker(cfg, info)
+}
+
+// buildSourceLinker compiles cfg against the build info of the binary; info may be nil.
+func buildSourceLinker(cfg SourceLinkConfig, info *debug.BuildInfo) *sourceLinker {
+	if !cfg.Enabled {
+		return nil
+	}
+	linker := &sourceLinker{template: cfg.Template, repos: make(map[string]string), revision: cfg.Revision}
+	if linker.template == "" {
+		linker.template = defaultSourceLinkTemplate
+	}
+	if linker.revision == "" && info != nil {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" {
+				linker.revision = setting.Value
+			}
+		}
+	}
+	for module, repo := range cfg.Repositories {
+		linker.repos[strings.TrimSuffix(module, "/")] = strings.TrimSuffix(repo, "/")
+	}
+	if len(linker.repos) == 0 && info != nil && info.Main.Path != "" {
+		linker.repos[info.Main.Path] = "https://" + trimMajorVersion(info.Main.Path)
+	}
+	// go run with file arguments builds a main package without an import path.
+	if info != nil && info.Path != "" && info.Path != "command-line-arguments" {
+		linker.mainPackage = info.Path
+	}
+	if linker.revision == "" || len(linker.repos) == 0 {
+		return nil
+	}
+	return linker
+}
+
+// link returns the source URL of frame, or false when its module is not mapped.
+func (l *sourceLinker) link(frame runtime.Frame) (string, bool) {
+	if l == nil || frame.File == "" {
+		return "", false
+	}
+	pkg := functionPackage(frame.Function)
+	if pkg == "main" && l.mainPackage != "" {
+		pkg = l.mainPackage
+	}
+	module, repo := "", ""
+	for candidate, url := range l.repos {
+		if inModule(pkg, candidate) && len(candidate) > len(module) {
+			module, repo = candidate, url
+		}
+	}
+	if module == "" {
+		return "", false
+	}
+	file := path.Join(strings.TrimPrefix(strings.TrimPrefix(pkg, module), "/"), path.Base(frame.File))
+	return strings.NewReplacer(
+		"{repo}", repo,
+		"{revision}", l.revision,
+		"{path}", file,
+		"{line}", strconv.Itoa(frame.Line),
+	).Replace(l.template), true
+}
+
+// functionPackage returns the import path of the package declaring function, as runtime reports
+// it, for example "example.com/app/db" for "example.com/app/db.(*Store).Get".
+func functionPackage(function string) string {
+	slash := strings.LastIndexByte(function, '/')
+	dot := strings.IndexByte(function[slash+1:], '.')
+	if dot < 0 {
+		return function
+	}
+	return function[:slash+1+dot]
+}
+
+// trimMajorVersion drops the /vN suffix Go appends to the path of major versions from v2 on, which
+// is not part of the repository URL.
+func trimMajorVersion(modulePath string) string {
+	slash := strings.LastIndexByte(modulePath, '/')
+	suffix := modulePath[slash+1:]
+	if len(suffix) < 2 || suffix[0] != 'v' {
+		return modulePath
+	}
+	if major, err := strconv.Atoi(suffix[1:]); err != nil || major < 2 || suffix[1] == '0' {
+		return modulePath
+	}
+	return modulePath[:slash]
+}
@@ -0,0 +1,140 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"testing"
+)
+
+func TestSourceLinkerRewritesMappedFrames(t *testing.T) {
+	linker := newSourceLinker(SourceLinkConfig{
+		Enabled: true,
+		Repositories: map[string]string{
+			"example.com/app":     "https://git.example.com/app/",
+			"example.com/app/api": "https://git.example.com/api",
+		},
+		Revision: "abc123",
+	})
+
+	cases := []struct {
+		frame runtime.Frame
+		want  string
+	}{
+		{
+			runtime.Frame{Function: "example.com/app/internal/db.(*Store).Get", File: "/build/app/internal/db/store.go", Line: 42},
+			"https://git.example.com/app/blob/abc123/internal/db/store.go#L42",
+		},
+		{
+			runtime.Frame{Function: "example.com/app.main", File: "/build/app/main.go", Line: 7},
+			"https://git.example.com/app/blob/abc123/main.go#L7",
+		},
+		{
+			runtime.Frame{Function: "example.com/app/api/v2.Handler.func1", File: "/mod/api/v2/handler.go", Line: 3},
+			"https://git.example.com/api/blob/abc123/v2/handler.go#L3",
+		},
+	}
+	for _, tc := range cases {
+		got, ok := linker.link(tc.frame)
+		if !ok || got != tc.want {
+			t.Fatalf("link(%s) = %q, %v; want %q", tc.frame.Function, got, ok, tc.want)
+		}
+	}
+
+	if _, ok := linker.link(runtime.Frame{Function: "example.com/application.Run", File: "/x/run.go", Line: 1}); ok {
+		t.Fatal("expected a module sharing only a name prefix to stay unmapped")
+	}
+}
+
+func TestSourceLinkerDefaultsToMainModule(t *testing.T) {
+	linker := buildSourceLinker(SourceLinkConfig{Enabled: true, Revision: "abc123"}, &debug.BuildInfo{
+		Path: "example.com/app/v2/cmd/server",
+		Main: debug.Module{Path: "example.com/app/v2"},
+	})
+
+	cases := []struct {
+		frame runtime.Frame
+		want  string
+	}{
+		{
+			runtime.Frame{Function: "main.run", File: "/build/cmd/server/main.go", Line: 10},
+			"https://example.com/app/blob/abc123/cmd/server/main.go#L10",
+		},
+		{
+			runtime.Frame{Function: "example.com/app/v2/store.Open", File: "/build/store/open.go", Line: 4},
+			"https://example.com/app/blob/abc123/store/open.go#L4",
+		},
+	}
+	for _, tc := range cases {
+		got, ok := linker.link(tc.frame)
+		if !ok || got != tc.want {
+			t.Fatalf("link(%s) = %q, %v; want %q", tc.frame.Function, got, ok, tc.want)
+		}
+	}
+
+	for path, want := range map[string]string{
+		"example.com/app":       "example.com/app",
+		"example.com/app/v2":    "example.com/app",
+		"example.com/app/v1":    "example.com/app/v1",
+		"example.com/app/v02":   "example.com/app/v02",
+		"example.com/app/vnext": "example.com/app/vnext",
+	} {
+		if got := trimMajorVersion(path); got != want {
+			t.Fatalf("trimMajorVersion(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestStackRendersSourceLinks(t *testing.T) {
+	locations := stackLocations(t, StackConfig{SourceLinks: SourceLinkConfig{
+		Enabled:      true,
+		Template:     "{repo}/-/blob/{revision}/{path}#L{line}",
+		Repositories: map[string]string{"github.com/mfahmialkautsar/goo11y": "https://gitlab.example.com/goo11y"},
+		Revision:     "deadbeef",
+	}})
+
+	var linked, plain bool
+	for _, location := range locations {
+		switch {
+		case strings.HasPrefix(location, "https://gitlab.example.com/goo11y/-/blob/deadbeef/logger/logger_test_helpers_test.go#L"):
+			linked = true
+		case strings.HasPrefix(location, "https://"):
+			if !strings.HasPrefix(location, "https://gitlab.example.com/goo11y/-/blob/deadbeef/logger/") {
+				t.Fatalf("unexpected link %q", location)
+			}
+		default:
+			plain = true
+		}
+	}
+	if !linked || !plain {
+		t.Fatalf("expected linked application frames and plain dependency frames, got %v", locations)
+	}
+}
+
+func stackLocations(t *testing.T, cfg StackConfig) []string {
+	t.Helper()
+	var buf bytes.Buffer
+	log, err := New(context.Background(), Config{
+		Enabled: true,
+		Console: false,
+		Writers: []io.Writer{&buf},
+		Stack:   cfg,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = log.Close() })
+
+	log.Err(nestedOuterError()).Msg("linked stack")
+	stack, _ := decodeLogLine(t, buf.Bytes())["stack"].([]any)
+	var locations []string
+	for _, entry := range stack {
+		frame, _ := entry.(map[string]any)
+		location, _ := frame["location"].(string)
+		locations = append(locations, location)
+	}
+	return locations
+}
@@ -0,0 +1,115 @@
+package logger
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mfahmialkautsar/goo11y/clock"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// maxBufferedSpanEvents caps the events a SpanEventBuffer holds; later ones are counted in
+	// Stats.SpanEventsUnattached instead.
+	maxBufferedSpanEvents = 32
+	// bufferedSpanEventMaxAge is how long a buffered event waits for a span before it is discarded.
+	bufferedSpanEventMaxAge = 10 * time.Second
+)
+
+type spanEventBufferKey struct{}
+
+// spanEventBuffer holds the span events of entries logged before a span was started from the
+// context carrying it, or after the context's span ended.
+type spanEventBuffer struct {
+	mu     sync.Mutex
+	events []bufferedSpanEvent
+	// clock is the clock of the logger that buffered the events, so their age is measured on it.
+	clock clock.Clock
+}
+
+type bufferedSpanEvent struct {
+	name string
+	// msgKey is the attribute the message is recorded under.
+	msgKey string
+	msg    string
+	at     time.Time
+}
+
+// ContextWithSpanEventBuffer returns ctx carrying a buffer for span events that find no recording
+// span, such as warnings logged while a request is parsed, before its span starts, or after the
+// span ended. The next span started from ctx, or from a context derived from it, records them with
+// their original timestamps, provided the tracer provider runs SpanEventBufferProcessor (a
+// goo11y.Telemetry tracer does). Events wait at most ten seconds, and at most 32 are kept.
+func ContextWithSpanEventBuffer(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if _, ok := ctx.Value(spanEventBufferKey{}).(*spanEventBuffer); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, spanEventBufferKey{}, &spanEventBuffer{})
+}
+
+// add buffers an event stamped by clk and reports whether there was room for it.
+func (b *spanEventBuffer) add(name, msgKey, msg string, clk clock.Clock) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if len(b.events) >= maxBufferedSpanEvents {
+		return false
+	}
+	b.clock = clock.OrReal(clk)
+	b.events = append(b.events, bufferedSpanEvent{name: name, msgKey: msgKey, msg: msg, at: b.clock.Now()})
+	return true
+}
+
+// drain returns the buffered events younger than bufferedSpanEventMaxAge and empties the buffer.
+func (b *spanEventBuffer) drain() []bufferedSpanEvent {
+	b.mu.Lock()
+	events := b.events
+	b.events = nil
+	clk := b.clock
+	b.mu.Unlock()
+	if len(events) == 0 {
+		return nil
+	}
+	now := clk.Now()
+
+	fresh := events[:0]
+	for _, event := range events {
+		if now.Sub(event.at) <= bufferedSpanEventMaxAge {
+			fresh = append(fresh, event)
+		}
+	}
+	return fresh
+}
+
+// SpanEventBufferProcessor returns a span processor recording the events held by
+// ContextWithSpanEventBuffer on the next span started from that context.
+func SpanEventBufferProcessor() sdktrace.SpanProcessor {
+	return spanEventBufferProcessor{}
+}
+
+type spanEventBufferProcessor struct{}
+
+func (spanEventBufferProcessor) OnStart(parent context.Context, span sdktrace.ReadWriteSpan) {
+	buffer, ok := parent.Value(spanEventBufferKey{}).(*spanEventBuffer)
+	if !ok {
+		return
+	}
+	for _, event := range buffer.drain() {
+		var attrs []attribute.KeyValue
+		if event.msg != "" {
+			attrs = append(attrs, attribute.String(event.msgKey, event.msg))
+		}
+		span.AddEvent(event.name, trace.WithTimestamp(event.at), trace.WithAttributes(attrs...))
+	}
+}
+
+func (spanEventBufferProcessor) OnEnd(sdktrace.ReadOnlySpan) {}
+
+func (spanEventBufferProcessor) Shutdown(context.Context) error { return nil }
+
+func (spanEventBufferProcessor) ForceFlush(context.Context) error { return nil }
@@ -0,0 +1,104 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSpanEventBufferAttachesEventsToNextSpan(t *testing.T) {
+	log, _ := newBufferedLogger(t, "span-event-buffer", "info")
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(SpanEventBufferProcessor()),
+		sdktrace.WithSpanProcessor(recorder),
+	)
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	ctx := ContextWithSpanEventBuffer(context.Background())
+	log.Warn().Ctx(ctx).Msg("malformed header")
+	log.Info().Ctx(ctx).Msg("not a span event level")
+
+	_, span := provider.Tracer("test").Start(ctx, "request")
+	span.End()
+	_, next := provider.Tracer("test").Start(ctx, "second")
+	next.End()
+
+	events := spanByName(t, recorder.Ended(), "request").Events()
+	if len(events) != 1 || events[0].Name != defaultNaming.warnEvent {
+		t.Fatalf("expected the buffered warn event, got %+v", events)
+	}
+	assertAttrString(t, events[0].Attributes, LogMessageKey, "malformed header")
+	if got := len(spanByName(t, recorder.Ended(), "second").Events()); got != 0 {
+		t.Fatalf("expected the buffer to be drained by the first span, got %d events", got)
+	}
+	if got := log.Stats().SpanEventsUnattached; got != 0 {
+		t.Fatalf("expected no unattached events, got %d", got)
+	}
+}
+
+func TestSpanEventsWithoutSpanAreCounted(t *testing.T) {
+	log, _ := newBufferedLogger(t, "span-event-buffer", "info")
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.NeverSample()))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	log.Warn().Ctx(context.Background()).Msg("no span, so not counted")
+
+	ended, span := provider.Tracer("test").Start(context.Background(), "unsampled")
+	span.End()
+	log.Error().Ctx(ended).Msg("unsampled spans record nothing by design")
+
+	sampled := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{1},
+		TraceFlags: trace.FlagsSampled,
+	}))
+	log.Error().Ctx(sampled).Msg("span already ended")
+
+	if got := log.Stats().SpanEventsUnattached; got != 1 {
+		t.Fatalf("expected 1 unattached event, got %d", got)
+	}
+}
+
+func TestSpanEventBufferUsesLoggerClock(t *testing.T) {
+	clk := &dedupClock{now: time.Unix(1_700_000_000, 0), timers: make(chan chan time.Time, 4)}
+	log, err := New(context.Background(), Config{
+		Enabled: true,
+		Level:   "info",
+		Console: false,
+		Writers: []io.Writer{io.Discard},
+		Clock:   clk,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = log.Close() })
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(SpanEventBufferProcessor()),
+		sdktrace.WithSpanProcessor(recorder),
+	)
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	ctx := ContextWithSpanEventBuffer(context.Background())
+	log.Warn().Ctx(ctx).Msg("kept")
+	_, span := provider.Tracer("test").Start(ctx, "fresh")
+	span.End()
+	events := spanByName(t, recorder.Ended(), "fresh").Events()
+	if len(events) != 1 || !events[0].Time.Equal(clk.Now()) {
+		t.Fatalf("expected the event stamped by the logger clock, got %+v", events)
+	}
+
+	log.Warn().Ctx(ctx).Msg("expired")
+	clk.advance(bufferedSpanEventMaxAge + time.Second)
+	_, span = provider.Tracer("test").Start(ctx, "late")
+	span.End()
+	if got := len(spanByName(t, recorder.Ended(), "late").Events()); got != 0 {
+		t.Fatalf("expected the event to expire on the logger clock, got %d events", got)
+	}
+}
@@ -0,0 +1,312 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mfahmialkautsar/goo11y/clock"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.28.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// spanEventPolicy is the resolved form of SpanEventConfig and ExceptionEvents.
+type spanEventPolicy struct {
+	levels     [9]bool
+	setStatus  bool
+	attributes []string
+	maxPerSpan int
+	exceptions bool
+	replace    bool
+	// pending is set when some events must wait for the encoded line, see spanEventWriter.
+	pending *pendingSpanEvents
+}
+
+var defaultSpanEventPolicy = newSpanEventPolicy(SpanEventConfig{Levels: []string{"warn", "error"}}, ExceptionEventsOff, nil)
+
+func newSpanEventPolicy(cfg SpanEventConfig, exceptions string, clk clock.Clock) *spanEventPolicy {
+	policy := &spanEventPolicy{
+		setStatus:  !cfg.DisableErrorStatus,
+		attributes: cfg.Attributes,
+		maxPerSpan: cfg.MaxPerSpan,
+		exceptions: exceptions == ExceptionEventsAppend || exceptions == ExceptionEventsReplace,
+		replace:    exceptions == ExceptionEventsReplace,
+	}
+	if !cfg.Disabled {
+		for _, name := range cfg.Levels {
+			level, err := zerolog.ParseLevel(strings.ToLower(strings.TrimSpace(name)))
+			if err != nil {
+				continue
+			}
+			if level == zerolog.ErrorLevel {
+				// Fatal and panic entries share the log.error event.
+				policy.setLevel(zerolog.FatalLevel)
+				policy.setLevel(zerolog.PanicLevel)
+			}
+			policy.setLevel(level)
+		}
+	}
+	if policy.exceptions || len(policy.attributes) > 0 {
+		policy.pending = newPendingSpanEvents(clk)
+	}
+	return policy
+}
+
+func (p *spanEventPolicy) setLevel(level zerolog.Level) {
+	if idx := int(level) + 1; idx >= 0 && idx < len(p.levels) {
+		p.levels[idx] = true
+	}
+}
+
+func (p *spanEventPolicy) enabled(level zerolog.Level) bool {
+	idx := int(level) + 1
+	return idx >= 0 && idx < len(p.levels) && p.levels[idx]
+}
+
+// underLimit reports whether span has room for another logger event. Limits are only
+// enforced for SDK spans, whose recorded events can be inspected.
+func (p *spanEventPolicy) underLimit(span trace.Span, naming *fieldNaming) bool {
+	if p.maxPerSpan <= 0 {
+		return true
+	}
+	recorded, ok := span.(interface{ Events() []sdktrace.Event })
+	if !ok {
+		return true
+	}
+	count := 0
+	for _, event := range recorded.Events() {
+		if strings.HasPrefix(event.Name, "log.") || event.Name == naming.warnEvent || event.Name == naming.errorEvent || event.Name == exceptionEventName {
+			count++
+		}
+	}
+	return count < p.maxPerSpan
+}
+
+const (
+	// pendingSpanEventField carries the key of a deferred span event on the encoded line.
+	// spanEventWriter removes it before the line reaches any writer.
+	pendingSpanEventField = "_span_event"
+	// errorTypeField carries the Go type of the error logged through Logger.Err, which the
+	// encoded line no longer shows. spanEventWriter reads it into exception.type and removes it.
+	errorTypeField = "_error_type"
+	// maxPendingSpanEvents and pendingSpanEventTTL bound the events whose lines never reach
+	// spanEventWriter, such as lines dropped by a hook or discarded by a buffered logger.
+	maxPendingSpanEvents = 1024
+	pendingSpanEventTTL  = time.Minute
+)
+
+type pendingSpanEvent struct {
+	span      trace.Span
+	name      string
+	msg       string
+	logEvent  bool
+	exception bool
+	at        time.Time
+}
+
+// pendingSpanEvents hands spans from spanHook to spanEventWriter. zerolog hides the fields of an
+// event, so the error, stack and allow-listed attributes are read back from the encoded line, which
+// names its entry by the sequence number in pendingSpanEventField.
+type pendingSpanEvents struct {
+	clock clock.Clock
+
+	mu      sync.Mutex
+	seq     uint64
+	pending map[uint64]pendingSpanEvent
+}
+
+func newPendingSpanEvents(clk clock.Clock) *pendingSpanEvents {
+	return &pendingSpanEvents{clock: clock.OrReal(clk), pending: make(map[uint64]pendingSpanEvent)}
+}
+
+// push stores entry and returns the sequence number to add to its line.
+func (s *pendingSpanEvents) push(entry pendingSpanEvent) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry.at = s.clock.Now()
+	if len(s.pending) >= maxPendingSpanEvents {
+		s.evict(entry.at)
+	}
+	s.seq++
+	s.pending[s.seq] = entry
+	return s.seq
+}
+
+// evict drops the entries older than pendingSpanEventTTL, or the oldest one when none expired.
+func (s *pendingSpanEvents) evict(now time.Time) {
+	var oldest uint64
+	for seq, entry := range s.pending {
+		if now.Sub(entry.at) >= pendingSpanEventTTL {
+			delete(s.pending, seq)
+			continue
+		}
+		if oldest == 0 || seq < oldest {
+			oldest = seq
+		}
+	}
+	if len(s.pending) >= maxPendingSpanEvents {
+		delete(s.pending, oldest)
+	}
+}
+
+func (s *pendingSpanEvents) pop(seq uint64) (pendingSpanEvent, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.pending[seq]
+	if ok {
+		delete(s.pending, seq)
+	}
+	return entry, ok
+}
+
+// discard drops the entry of a line that will never be written. It is a no-op on a nil receiver.
+func (s *pendingSpanEvents) discard(line []byte) {
+	if s == nil {
+		return
+	}
+	if _, seq := cutPendingSpanEvent(line); seq != 0 {
+		s.pop(seq)
+	}
+}
+
+func (s *pendingSpanEvents) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.pending)
+}
+
+// cutPendingSpanEvent removes pendingSpanEventField from line and returns its sequence number, or
+// zero when the line carries none.
+func cutPendingSpanEvent(line []byte) ([]byte, uint64) {
+	marker := `,"` + pendingSpanEventField + `":`
+	start := bytes.Index(line, []byte(marker))
+	if start < 0 {
+		return line, 0
+	}
+	digits := start + len(marker)
+	end := digits
+	for end < len(line) && line[end] >= '0' && line[end] <= '9' {
+		end++
+	}
+	seq, err := strconv.ParseUint(string(line[digits:end]), 10, 64)
+	if err != nil {
+		return line, 0
+	}
+	out := make([]byte, 0, len(line)-(end-start))
+	return append(append(out, line[:start]...), line[end:]...), seq
+}
+
+// cutStringField removes the string member key from line and returns its value, or an empty value
+// when the line carries none. The member must follow another one, as zerolog's level always does.
+func cutStringField(line []byte, key string) ([]byte, string) {
+	marker := `,"` + key + `":"`
+	start := bytes.Index(line, []byte(marker))
+	if start < 0 {
+		return line, ""
+	}
+	end := start + len(marker)
+	for end < len(line) && line[end] != '"' {
+		if line[end] == '\\' {
+			end++
+		}
+		end++
+	}
+	if end >= len(line) {
+		return line, ""
+	}
+	value, ok := jsonString(line[start+len(marker)-1 : end+1])
+	if !ok {
+		return line, ""
+	}
+	out := make([]byte, 0, len(line)-(end+1-start))
+	return append(append(out, line[:start]...), line[end+1:]...), value
+}
+
+// spanEventWriter completes the span events deferred by spanHook before the line is fanned out.
+type spanEventWriter struct {
+	next   io.Writer
+	policy *spanEventPolicy
+	naming *fieldNaming
+}
+
+func (w *spanEventWriter) Write(p []byte) (int, error) {
+	line, errorType := cutStringField(p, errorTypeField)
+	line, seq := cutPendingSpanEvent(line)
+	if seq == 0 && errorType == "" {
+		return w.next.Write(p)
+	}
+	if entry, ok := w.policy.pending.pop(seq); ok {
+		w.record(entry, line, errorType)
+	}
+	if _, err := w.next.Write(line); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (w *spanEventWriter) record(entry pendingSpanEvent, p []byte, errorType string) {
+	var message string
+	var stack []byte
+	var extra []attribute.KeyValue
+	scanJSONObject(bytes.TrimRight(p, "\r\n"), func(key, value []byte) {
+		switch {
+		case keyIs(key, w.naming.to.error):
+			message, _ = jsonString(value)
+		case keyIs(key, w.naming.to.stack):
+			stack = value
+		}
+		for _, name := range w.policy.attributes {
+			if keyIs(key, name) {
+				extra = append(extra, jsonAttribute(name, value))
+				break
+			}
+		}
+	})
+
+	hasError := message != "" || stack != nil
+	if entry.logEvent && (!entry.exception || !hasError || !w.policy.replace) {
+		addLogEvent(entry.span, entry.name, w.naming.eventMessage, entry.msg, extra...)
+	}
+	if entry.exception && hasError {
+		attrs := []attribute.KeyValue{semconv.ExceptionMessage(message)}
+		if errorType != "" {
+			attrs = append(attrs, semconv.ExceptionType(errorType))
+		}
+		if text := stackText(stack); text != "" {
+			attrs = append(attrs, semconv.ExceptionStacktrace(text))
+		}
+		entry.span.AddEvent(exceptionEventName, trace.WithAttributes(attrs...))
+	}
+}
+
+// jsonAttribute converts a raw JSON member into a span attribute, keeping nested values as JSON text.
+func jsonAttribute(key string, raw []byte) attribute.KeyValue {
+	switch raw[0] {
+	case '"':
+		if value, ok := jsonString(raw); ok {
+			return attribute.String(key, value)
+		}
+	case 't':
+		return attribute.Bool(key, true)
+	case 'f':
+		return attribute.Bool(key, false)
+	case 'n', '{', '[':
+		return attribute.String(key, string(raw))
+	default:
+		if bytes.IndexAny(raw, ".eE") < 0 {
+			if value, err := strconv.ParseInt(string(raw), 10, 64); err == nil {
+				return attribute.Int64(key, value)
+			}
+		}
+		if value, err := strconv.ParseFloat(string(raw), 64); err == nil {
+			return attribute.Float64(key, value)
+		}
+	}
+	return attribute.String(key, string(raw))
+}
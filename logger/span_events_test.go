@@ -0,0 +1,165 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newSpanEventLogger(t *testing.T, cfg SpanEventConfig) (*Logger, *tracetest.SpanRecorder, *sdktrace.TracerProvider) {
+	t.Helper()
+	log, err := New(context.Background(), Config{
+		Enabled:    true,
+		Level:      "debug",
+		Console:    false,
+		Writers:    []io.Writer{io.Discard},
+		SpanEvents: cfg,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() {
+		_ = tp.Shutdown(context.Background())
+		_ = log.Close()
+	})
+	return log, recorder, tp
+}
+
+func TestSpanEventLevelsAndStatus(t *testing.T) {
+	log, recorder, tp := newSpanEventLogger(t, SpanEventConfig{
+		Levels:             []string{"info"},
+		DisableErrorStatus: true,
+	})
+
+	ctx, span := tp.Tracer("events").Start(context.Background(), "op")
+	log.Info().Ctx(ctx).Msg("started")
+	log.Warn().Ctx(ctx).Msg("slow")
+	log.Error().Ctx(ctx).Msg("failed")
+	span.End()
+
+	ended := recorder.Ended()[0]
+	if got := strings.Join(spanEventNames(ended), ","); got != "log.info" {
+		t.Fatalf("unexpected span events %q", got)
+	}
+	if ended.Status().Code == codes.Error {
+		t.Fatal("expected error status to be left untouched")
+	}
+}
+
+func TestSpanEventAttributesAllowList(t *testing.T) {
+	log, recorder, tp := newSpanEventLogger(t, SpanEventConfig{
+		Attributes: []string{"user_id", "attempt"},
+	})
+
+	ctx, span := tp.Tracer("events").Start(context.Background(), "op")
+	log.Warn().Ctx(ctx).Str("user_id", "u-1").Int("attempt", 3).Str("secret", "hidden").Msg("retrying")
+	span.End()
+
+	events := recorder.Ended()[0].Events()
+	if len(events) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(events))
+	}
+	attrs := map[string]any{}
+	for _, kv := range events[0].Attributes {
+		attrs[string(kv.Key)] = kv.Value.AsInterface()
+	}
+	if attrs["user_id"] != "u-1" || attrs["attempt"] != int64(3) || attrs[LogMessageKey] != "retrying" {
+		t.Fatalf("unexpected event attributes %v", attrs)
+	}
+	if _, ok := attrs["secret"]; ok {
+		t.Fatalf("field outside allow-list copied: %v", attrs)
+	}
+}
+
+func TestSpanEventMaxPerSpan(t *testing.T) {
+	log, recorder, tp := newSpanEventLogger(t, SpanEventConfig{MaxPerSpan: 2})
+
+	ctx, span := tp.Tracer("events").Start(context.Background(), "op")
+	for range 5 {
+		log.Warn().Ctx(ctx).Msg("noisy")
+	}
+	span.End()
+
+	if got := len(recorder.Ended()[0].Events()); got != 2 {
+		t.Fatalf("expected events capped at 2, got %d", got)
+	}
+}
+
+func TestSpanEventsDisabled(t *testing.T) {
+	log, recorder, tp := newSpanEventLogger(t, SpanEventConfig{Disabled: true})
+
+	ctx, span := tp.Tracer("events").Start(context.Background(), "op")
+	log.Error().Ctx(ctx).Msg("failed")
+	span.End()
+
+	ended := recorder.Ended()[0]
+	if len(ended.Events()) != 0 {
+		t.Fatalf("expected no events, got %v", spanEventNames(ended))
+	}
+	if ended.Status().Code != codes.Error {
+		t.Fatal("expected error status to still be set")
+	}
+}
+
+func TestSpanEventsMatchTheirOwnLine(t *testing.T) {
+	var out syncLines
+	policy := newSpanEventPolicy(SpanEventConfig{Levels: []string{"error"}}, ExceptionEventsAppend, nil)
+	writer := &spanEventWriter{next: &out, policy: policy, naming: defaultNaming}
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	_, span := tp.Tracer("events").Start(context.Background(), "op")
+	first := policy.pending.push(pendingSpanEvent{span: span, name: defaultNaming.errorEvent, msg: "first", exception: true})
+	second := policy.pending.push(pendingSpanEvent{span: span, name: defaultNaming.errorEvent, msg: "second", exception: true})
+	// The lines of two goroutines logging on one span can arrive in either order.
+	for _, line := range []string{
+		`{"level":"error","error":"second cause","` + pendingSpanEventField + `":` + strconv.FormatUint(second, 10) + `,"message":"second"}`,
+		`{"level":"error","error":"first cause","` + pendingSpanEventField + `":` + strconv.FormatUint(first, 10) + `,"message":"first"}`,
+	} {
+		if _, err := writer.Write([]byte(line + "\n")); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+	}
+	span.End()
+
+	var messages []string
+	for _, event := range recorder.Ended()[0].Events() {
+		for _, kv := range event.Attributes {
+			if kv.Key == "exception.message" {
+				messages = append(messages, kv.Value.AsString())
+			}
+		}
+	}
+	if strings.Join(messages, ",") != "second cause,first cause" {
+		t.Fatalf("expected each line to complete its own entry, got %v", messages)
+	}
+	if strings.Contains(strings.Join(out.lines(), "\n"), pendingSpanEventField) {
+		t.Fatalf("expected the sequence field to be removed, got %q", out.lines())
+	}
+	if policy.pending.len() != 0 {
+		t.Fatalf("expected no pending entries, got %d", policy.pending.len())
+	}
+}
+
+func TestPendingSpanEventsAreBounded(t *testing.T) {
+	pending := newPendingSpanEvents(nil)
+	first := pending.push(pendingSpanEvent{msg: "lost"})
+	for range maxPendingSpanEvents {
+		pending.push(pendingSpanEvent{})
+	}
+	if got := pending.len(); got != maxPendingSpanEvents {
+		t.Fatalf("expected %d pending entries, got %d", maxPendingSpanEvents, got)
+	}
+	if _, ok := pending.pop(first); ok {
+		t.Fatal("expected the oldest entry to be evicted")
+	}
+}
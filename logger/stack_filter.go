@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"reflect"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// StackConfig shapes the Go frames recorded in the stack field and in error_chain of one logger.
+type StackConfig struct {
+	// MaxFrames keeps at most this many frames per stack, innermost first. Zero keeps all of them.
+	MaxFrames int `validate:"gte=0"`
+	// SkipPrefixes drops frames whose function starts with one of these import paths, such as
+	// "github.com/gin-gonic/gin" for a framework's middleware chain. Matching uses the function
+	// name, so vendored copies are skipped too.
+	SkipPrefixes []string
+	// AppOnly keeps only frames of the application module, along with package main. AppModule
+	// names that module; empty uses the main module from the binary's build info. A stack with no
+	// application frame is recorded unfiltered rather than dropped.
+	AppOnly   bool
+	AppModule string
+	// SourceLinks turns frame locations into links to the source; see SourceLinkConfig.
+	SourceLinks SourceLinkConfig
+}
+
+// stackFilter is the compiled StackConfig applied when a logger renders its stacks.
+type stackFilter struct {
+	maxFrames int
+	skip      []string
+	appModule string
+	links     *sourceLinker
+}
+
+// loggerPackage is the import path of this package, whose frames never start a recorded stack.
+var loggerPackage = reflect.TypeOf(stackFilter{}).PkgPath()
+
+func newStackFilter(cfg StackConfig) stackFilter {
+	filter := stackFilter{maxFrames: cfg.MaxFrames, links: newSourceLinker(cfg.SourceLinks)}
+	for _, prefix := range cfg.SkipPrefixes {
+		if prefix = strings.TrimSpace(prefix); prefix != "" {
+			filter.skip = append(filter.skip, prefix)
+		}
+	}
+	if cfg.AppOnly {
+		filter.appModule = cfg.AppModule
+		if filter.appModule == "" {
+			filter.appModule = mainModulePath()
+		}
+	}
+	return filter
+}
+
+func mainModulePath() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	return info.Main.Path
+}
+
+// apply drops the frames filtered out by the config and truncates the rest to MaxFrames.
+func (f stackFilter) apply(frames []runtime.Frame) []runtime.Frame {
+	if len(f.skip) == 0 && f.appModule == "" && f.maxFrames == 0 {
+		return frames
+	}
+	kept := make([]runtime.Frame, 0, len(frames))
+	for _, frame := range frames {
+		if !f.skipped(frame.Function) {
+			kept = append(kept, frame)
+		}
+	}
+	if f.appModule != "" {
+		app := kept[:0:0]
+		for _, frame := range kept {
+			if inModule(frame.Function, f.appModule) || strings.HasPrefix(frame.Function, "main.") {
+				app = append(app, frame)
+			}
+		}
+		if len(app) > 0 {
+			kept = app
+		}
+	}
+	if f.maxFrames > 0 && len(kept) > f.maxFrames {
+		kept = kept[:f.maxFrames]
+	}
+	return kept
+}
+
+// location renders frame as a source link when SourceLinks maps its module, or as a file path.
+func (f stackFilter) location(frame runtime.Frame) string {
+	if link, ok := f.links.link(frame); ok {
+		return link
+	}
+	return frameLocation(frame)
+}
+
+func (f stackFilter) skipped(function string) bool {
+	for _, prefix := range f.skip {
+		if strings.HasPrefix(function, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// inModule reports whether function belongs to a package of module.
+func inModule(function, module string) bool {
+	rest, ok := strings.CutPrefix(function, module)
+	return ok && (rest == "" || rest[0] == '.' || rest[0] == '/')
+}
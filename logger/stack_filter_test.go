@@ -0,0 +1,113 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func stackFunctions(t *testing.T, cfg StackConfig) []string {
+	t.Helper()
+	var buf bytes.Buffer
+	log, err := New(context.Background(), Config{
+		Enabled: true,
+		Console: false,
+		Writers: []io.Writer{&buf},
+		Stack:   cfg,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = log.Close() })
+	log.Err(nestedOuterError()).Msg("filtered stack")
+
+	stack, _ := decodeLogLine(t, buf.Bytes())["stack"].([]any)
+	var functions []string
+	for _, frame := range decodeStackFrames(t, stack) {
+		functions = append(functions, frame.Function)
+	}
+	return functions
+}
+
+func TestStackConfigFiltersFrames(t *testing.T) {
+	full := stackFunctions(t, StackConfig{})
+	if !strings.Contains(strings.Join(full, ","), "testing.tRunner") {
+		t.Fatalf("expected the unfiltered stack to reach testing.tRunner, got %v", full)
+	}
+
+	skipped := stackFunctions(t, StackConfig{SkipPrefixes: []string{"testing.", "github.com/pkg/errors"}})
+	for _, function := range skipped {
+		if strings.HasPrefix(function, "testing.") || strings.HasPrefix(function, "github.com/pkg/errors") {
+			t.Fatalf("expected skipped prefixes to be dropped, got %v", skipped)
+		}
+	}
+
+	limited := stackFunctions(t, StackConfig{MaxFrames: 2})
+	if len(limited) != 2 || limited[0] != full[0] || limited[1] != full[1] {
+		t.Fatalf("expected the two innermost frames %v, got %v", full[:2], limited)
+	}
+
+	app := stackFunctions(t, StackConfig{AppOnly: true, AppModule: "github.com/mfahmialkautsar/goo11y"})
+	if len(app) == 0 {
+		t.Fatal("expected application frames")
+	}
+	for _, function := range app {
+		if !inModule(function, "github.com/mfahmialkautsar/goo11y") {
+			t.Fatalf("expected only application frames, got %v", app)
+		}
+	}
+
+	fallback := stackFunctions(t, StackConfig{AppOnly: true, AppModule: "example.com/absent"})
+	if len(fallback) != len(full) {
+		t.Fatalf("expected a stack without application frames to stay whole, got %v", fallback)
+	}
+}
+
+func TestStackConfigBelongsToItsLogger(t *testing.T) {
+	var limitedBuf, fullBuf bytes.Buffer
+	limited, err := New(context.Background(), Config{
+		Enabled: true,
+		Console: false,
+		Writers: []io.Writer{&limitedBuf},
+		Stack:   StackConfig{MaxFrames: 1},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = limited.Close() })
+	full, err := New(context.Background(), Config{Enabled: true, Console: false, Writers: []io.Writer{&fullBuf}})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = full.Close() })
+
+	limited.Err(nestedOuterError()).Msg("limited")
+	full.Err(nestedOuterError()).Msg("full")
+	if stack, _ := decodeLogLine(t, limitedBuf.Bytes())["stack"].([]any); len(stack) != 1 {
+		t.Fatalf("expected the first logger to keep MaxFrames 1, got %d frames", len(stack))
+	}
+	if stack, _ := decodeLogLine(t, fullBuf.Bytes())["stack"].([]any); len(stack) < 2 {
+		t.Fatalf("expected the later logger without a limit to keep every frame, got %d", len(stack))
+	}
+}
+
+func TestIsSkippedFrameMatchesPackagesNotFileNames(t *testing.T) {
+	cases := []struct {
+		frame runtime.Frame
+		want  bool
+	}{
+		{runtime.Frame{Function: "runtime.Callers", File: "/go/src/runtime/extern.go"}, true},
+		{runtime.Frame{Function: "github.com/rs/zerolog.(*Event).Msg", File: "/mod/github.com/rs/zerolog/event.go"}, true},
+		{runtime.Frame{Function: loggerPackage + ".(*Logger).Err", File: "/src/goo11y/logger/logger.go"}, true},
+		{runtime.Frame{Function: loggerPackage + ".TestSomething", File: "/src/goo11y/logger/logger_test.go"}, false},
+		{runtime.Frame{Function: "example.com/app/internal.Run", File: "/src/app/internal/logger.go"}, false},
+	}
+	for _, tc := range cases {
+		if got := isSkippedFrame(tc.frame); got != tc.want {
+			t.Fatalf("isSkippedFrame(%s) = %v, want %v", tc.frame.Function, got, tc.want)
+		}
+	}
+}
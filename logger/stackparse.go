@@ -0,0 +1,125 @@
+package logger
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// StackFrame is one frame of a stack trace read from text rather than from the Go runtime.
+type StackFrame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+func (f StackFrame) location() string {
+	if f.Line <= 0 {
+		return f.File
+	}
+	return f.File + ":" + strconv.Itoa(f.Line)
+}
+
+var (
+	goLocationPattern     = regexp.MustCompile(`^\s+(\S.*?):(\d+)(?: \+0x[0-9a-f]+)?$`)
+	goCreatedByPattern    = regexp.MustCompile(`^created by (\S+)(?: in goroutine \d+)?$`)
+	javaFramePattern      = regexp.MustCompile(`^at (\S+?)\((.*?)(?::(\d+))?\)$`)
+	pythonLocationPattern = regexp.MustCompile(`^File "(.+)", line (\d+)(?:, in (.+))?$`)
+)
+
+// ParseStackTrace reads the frames of a textual stack trace, such as a Go goroutine dump, a Java
+// or Kotlin exception with "at" lines, or a Python traceback, innermost frame first as printed.
+// Lines that are not frames, like exception headers, "Caused by:" or "... 3 more", are skipped.
+func ParseStackTrace(text string) []StackFrame {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	var frames []StackFrame
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			continue
+		}
+		if match := javaFramePattern.FindStringSubmatch(line); match != nil {
+			n, _ := strconv.Atoi(match[3])
+			frames = append(frames, StackFrame{Function: match[1], File: match[2], Line: n})
+			continue
+		}
+		if match := pythonLocationPattern.FindStringSubmatch(line); match != nil {
+			n, _ := strconv.Atoi(match[2])
+			frames = append(frames, StackFrame{Function: match[3], File: match[1], Line: n})
+			continue
+		}
+		if i+1 < len(lines) {
+			if match := goLocationPattern.FindStringSubmatch(lines[i+1]); match != nil {
+				n, _ := strconv.Atoi(match[2])
+				frames = append(frames, StackFrame{Function: goFunctionName(line), File: match[1], Line: n})
+				i++
+			}
+		}
+	}
+	return frames
+}
+
+// goFunctionName strips the argument list and "created by" wording from a goroutine dump line.
+func goFunctionName(line string) string {
+	if match := goCreatedByPattern.FindStringSubmatch(line); match != nil {
+		return match[1]
+	}
+	if strings.HasSuffix(line, ")") {
+		if idx := strings.LastIndex(line, "("); idx > 0 {
+			return line[:idx]
+		}
+	}
+	return line
+}
+
+// WithExternalStack attaches the frames parsed from trace to err, so the stack field renders them
+// after any Go frames, in the same format. With an empty trace the frames are parsed from err's
+// own message, and Error then returns only the message's first line. err is returned unchanged
+// when nothing parses.
+func WithExternalStack(err error, trace string) error {
+	if err == nil {
+		return nil
+	}
+	message := err.Error()
+	if trace == "" {
+		trace = message
+		message = firstLine(message)
+	}
+	frames := ParseStackTrace(trace)
+	if len(frames) == 0 {
+		return err
+	}
+	return &externalStackError{err: err, message: message, frames: frames}
+}
+
+func firstLine(text string) string {
+	for line := range strings.SplitSeq(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+type externalStackError struct {
+	err     error
+	message string
+	frames  []StackFrame
+}
+
+func (e *externalStackError) Error() string { return e.message }
+
+func (e *externalStackError) Unwrap() error { return e.err }
+
+// ExternalStack returns the parsed frames.
+func (e *externalStackError) ExternalStack() []StackFrame { return e.frames }
+
+// externalFrames returns the parsed frames attached anywhere in err's chain.
+func externalFrames(err error) []StackFrame {
+	var external interface{ ExternalStack() []StackFrame }
+	if errors.As(err, &external) {
+		return external.ExternalStack()
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package logger
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseStackTraceFormats(t *testing.T) {
+	cases := map[string]struct {
+		text string
+		want []StackFrame
+	}{
+		"goroutine dump": {
+			text: "panic: boom\n\ngoroutine 7 [running]:\nmain.(*Server).handle(0xc000010000, {0x1, 0x2})\n\t/app/server.go:42 +0x1d\nmain.main()\n\t/app/main.go:12 +0x25\ncreated by net/http.(*Server).Serve in goroutine 1\n\t/usr/local/go/src/net/http/server.go:3285 +0x4b4\n",
+			want: []StackFrame{
+				{Function: "main.(*Server).handle", File: "/app/server.go", Line: 42},
+				{Function: "main.main", File: "/app/main.go", Line: 12},
+				{Function: "net/http.(*Server).Serve", File: "/usr/local/go/src/net/http/server.go", Line: 3285},
+			},
+		},
+		"java": {
+			text: "java.lang.IllegalStateException: closed\n\tat com.example.Pool.take(Pool.java:88)\n\tat java.base/java.lang.Thread.run(Native Method)\nCaused by: java.io.IOException: reset\n\t... 3 more",
+			want: []StackFrame{
+				{Function: "com.example.Pool.take", File: "Pool.java", Line: 88},
+				{Function: "java.base/java.lang.Thread.run", File: "Native Method"},
+			},
+		},
+		"python": {
+			text: "Traceback (most recent call last):\r\n  File \"/srv/app.py\", line 10, in handler\r\n    run()\r\nValueError: bad",
+			want: []StackFrame{{Function: "handler", File: "/srv/app.py", Line: 10}},
+		},
+		"plain": {text: "connection refused"},
+	}
+	for name, tc := range cases {
+		if got := ParseStackTrace(tc.text); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: ParseStackTrace = %+v, want %+v", name, got, tc.want)
+		}
+	}
+}
+
+func TestWithExternalStackRendersParsedFrames(t *testing.T) {
+	log, buf := newBufferedLogger(t, "external-stack", "debug")
+
+	remote := errors.New("java.lang.IllegalStateException: closed\n\tat com.example.Pool.take(Pool.java:88)")
+	err := WithExternalStack(remote, "")
+	if err.Error() != "java.lang.IllegalStateException: closed" || !errors.Is(err, remote) {
+		t.Fatalf("unexpected wrapped error %q", err)
+	}
+	log.Error().Err(err).Msg("remote failure")
+
+	entry := decodeLogLine(t, buf.Bytes())
+	stack, ok := entry["stack"].([]any)
+	if !ok || len(stack) != 1 {
+		t.Fatalf("expected only the parsed frame, got %v", entry["stack"])
+	}
+	frame := stack[0].(map[string]any)
+	if frame["location"] != "Pool.java:88" || frame["function"] != "com.example.Pool.take" {
+		t.Fatalf("unexpected parsed frame %v", frame)
+	}
+
+	plain := errors.New("timeout")
+	if WithExternalStack(plain, "") != plain || WithExternalStack(nil, "x") != nil {
+		t.Fatal("expected errors without a parsable trace to be returned unchanged")
+	}
+}
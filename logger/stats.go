@@ -0,0 +1,76 @@
+package logger
+
+import "time"
+
+// Stats reports runtime counters for the logger's writers.
+type Stats struct {
+	// Lines is the number of entries emitted per level name.
+	Lines map[string]uint64
+	// WriteErrors is the number of failed writes per sink name.
+	WriteErrors map[string]uint64
+	// FileQueued is the number of entries waiting for the file writer.
+	FileQueued int
+	// FileDropped is the number of entries the file writer discarded because its queue was full.
+	FileDropped uint64
+	// FileWrites is the number of batches written to the file, and FileWriteTime the total time
+	// the writes took.
+	FileWrites    uint64
+	FileWriteTime time.Duration
+	// FileSyncs is the number of fsyncs of the file, and FileSyncTime the total time they took.
+	FileSyncs    uint64
+	FileSyncTime time.Duration
+	// OTLPQueued is the number of OTLP export requests waiting in the spool.
+	OTLPQueued int
+	// OTLPDropped is the number of OTLP export requests the spool discarded without delivering them.
+	OTLPDropped uint64
+	// Sinks reports the queue of every sink by name in async delivery.
+	Sinks map[string]SinkStats
+	// SpanEventsUnattached is the number of entries logged with a context carrying a span that
+	// would have become span events but found the span no longer recording, and that no
+	// ContextWithSpanEventBuffer buffer took. Entries logged without any span are not counted.
+	SpanEventsUnattached uint64
+}
+
+// Stats returns a snapshot of the logger's writer counters.
+// Returns zero values if receiver is nil.
+func (l *Logger) Stats() Stats {
+	var stats Stats
+	if l == nil || l.writers == nil {
+		return stats
+	}
+	if l.levels != nil {
+		stats.Lines = l.levels.snapshot()
+	}
+	if l.unattached != nil {
+		stats.SpanEventsUnattached = l.unattached.Load()
+	}
+	stats.WriteErrors = make(map[string]uint64)
+	for _, w := range l.writers.snapshot() {
+		if w.failures != nil {
+			if n := w.failures.Load(); n > 0 {
+				stats.WriteErrors[w.name] = n
+			}
+		}
+		if async, ok := w.writer.(*asyncSink); ok {
+			if stats.Sinks == nil {
+				stats.Sinks = make(map[string]SinkStats)
+			}
+			stats.Sinks[w.name] = async.stats()
+		}
+		switch writer := unwrapFormat(w.writer).(type) {
+		case *dailyFileWriter:
+			stats.FileQueued += writer.Queued()
+			stats.FileDropped += writer.Dropped()
+			writes, writeTime := writer.Writes()
+			stats.FileWrites += writes
+			stats.FileWriteTime += writeTime
+			syncs, syncTime := writer.Syncs()
+			stats.FileSyncs += syncs
+			stats.FileSyncTime += syncTime
+		case *otlpWriter:
+			stats.OTLPQueued += writer.Queued()
+			stats.OTLPDropped += writer.Dropped()
+		}
+	}
+	return stats
+}
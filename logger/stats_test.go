@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"context"
+	"io"
+	"testing"
+)
+
+func TestFileWriterDropModeCountsDrops(t *testing.T) {
+	w, err := newDailyFileWriter(context.Background(), FileConfig{
+		Directory: t.TempDir(),
+		Buffer:    1,
+		Mode:      FileModeDrop,
+	}, Config{})
+	if err != nil {
+		t.Fatalf("newDailyFileWriter: %v", err)
+	}
+	t.Cleanup(func() { _ = w.Close() })
+
+	// Hold the file lock so the background writer cannot drain the queue.
+	w.mu.Lock()
+	for range 10 {
+		if n, err := w.Write([]byte("entry\n")); err != nil || n != len("entry\n") {
+			w.mu.Unlock()
+			t.Fatalf("Write: n=%d err=%v", n, err)
+		}
+	}
+	w.mu.Unlock()
+
+	if got := w.Dropped(); got == 0 {
+		t.Fatal("expected drops when queue is full")
+	}
+}
+
+func TestLoggerStatsReportsFileDrops(t *testing.T) {
+	log, err := New(context.Background(), Config{
+		Enabled: true,
+		Console: false,
+		File: FileConfig{
+			Enabled:   true,
+			Directory: t.TempDir(),
+			Buffer:    1,
+			Mode:      FileModeDrop,
+		},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = log.Close() })
+
+	file := log.writers.snapshot()[0].writer.(*dailyFileWriter)
+	file.mu.Lock()
+	for range 10 {
+		log.Info().Msg("flood")
+	}
+	file.mu.Unlock()
+
+	stats := log.Stats()
+	if stats.FileDropped == 0 {
+		t.Fatalf("expected dropped entries in stats, got %+v", stats)
+	}
+
+	var nilLogger *Logger
+	if got := nilLogger.Stats(); got.Lines != nil || got.FileDropped != 0 || got.FileQueued != 0 {
+		t.Fatalf("expected zero stats for nil logger, got %+v", got)
+	}
+}
+
+func TestLoggerStatsCountsLevelsAndWriteErrors(t *testing.T) {
+	log, err := New(context.Background(), Config{
+		Enabled: true,
+		Level:   "debug",
+		Writers: []io.Writer{failingWriter{}},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = log.Close() })
+
+	log.Info().Msg("one")
+	log.Info().Msg("two")
+	log.Warn().Msg("three")
+	log.Trace().Msg("filtered")
+
+	stats := log.Stats()
+	if got := stats.Lines["info"]; got != 2 {
+		t.Fatalf("expected 2 info lines, got %d (%v)", got, stats.Lines)
+	}
+	if got := stats.Lines["warn"]; got != 1 {
+		t.Fatalf("expected 1 warn line, got %d (%v)", got, stats.Lines)
+	}
+	if _, ok := stats.Lines["trace"]; ok {
+		t.Fatalf("filtered trace entry should not be counted: %v", stats.Lines)
+	}
+	if got := stats.WriteErrors["custom_0"]; got < 3 {
+		t.Fatalf("expected write errors for custom_0, got %v", stats.WriteErrors)
+	}
+}
@@ -0,0 +1,104 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceDebugEmitsOnlyForSampledOrFlaggedContexts(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := New(context.Background(), Config{
+		Enabled:    true,
+		Level:      "info",
+		Console:    false,
+		Writers:    []io.Writer{&buf},
+		TraceDebug: true,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	sampled := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{1},
+		TraceFlags: trace.FlagsSampled,
+	}))
+	unsampled := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{2},
+		SpanID:  trace.SpanID{2},
+	}))
+	member, err := baggage.NewMember(debugBaggageKey, "true")
+	if err != nil {
+		t.Fatalf("NewMember: %v", err)
+	}
+	bag, err := baggage.New(member)
+	if err != nil {
+		t.Fatalf("baggage.New: %v", err)
+	}
+	flagged := baggage.ContextWithBaggage(context.Background(), bag)
+
+	log.Debug().Msg("no-context")
+	log.Debug().Ctx(unsampled).Msg("unsampled")
+	log.Debug().Ctx(sampled).Msg("sampled")
+	log.Debug().Ctx(flagged).Msg("flagged")
+	log.Info().Msg("info")
+
+	out := buf.String()
+	for _, want := range []string{"sampled", "flagged", "info"} {
+		if !strings.Contains(out, `"`+want+`"`) {
+			t.Fatalf("expected %q entry, got %s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"no-context", "unsampled"} {
+		if strings.Contains(out, `"`+unwanted+`"`) {
+			t.Fatalf("unexpected %q entry, got %s", unwanted, out)
+		}
+	}
+	if got := log.Stats().Lines["debug"]; got != 2 {
+		t.Fatalf("expected only emitted debug entries counted, got %d", got)
+	}
+}
+
+type recordOnlySampler struct{}
+
+func (recordOnlySampler) ShouldSample(sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return sdktrace.SamplingResult{Decision: sdktrace.RecordOnly}
+}
+
+func (recordOnlySampler) Description() string { return "RecordOnly" }
+
+func TestTraceDebugDiscardedEntriesLeaveSpanUntouched(t *testing.T) {
+	log, err := New(context.Background(), Config{
+		Enabled:    true,
+		Level:      "info",
+		Console:    false,
+		Writers:    []io.Writer{io.Discard},
+		TraceDebug: true,
+		SpanEvents: SpanEventConfig{Levels: []string{"debug", "warn", "error"}},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = log.Close() })
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(recordOnlySampler{}), sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	ctx, span := tp.Tracer("debug").Start(context.Background(), "op")
+	log.Debug().Ctx(ctx).Msg("discarded")
+	span.End()
+
+	ended := recorder.Ended()[0]
+	if ended.Status().Code == codes.Error || len(ended.Events()) != 0 {
+		t.Fatalf("expected a discarded entry to leave the span alone, got status %v and events %v", ended.Status(), spanEventNames(ended))
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"context"
 	"io"
 	"testing"
+	"time"
 
 	"go.opentelemetry.io/otel/codes"
+	otelLog "go.opentelemetry.io/otel/log"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
@@ -59,10 +61,10 @@ func TestLoggerWithTracing(t *testing.T) {
 	}
 
 	entry := decodeLogLine(t, buf.Bytes())
-	if got := entry[traceIDField]; got != traceID {
+	if got := entry[defaultNaming.traceID]; got != traceID {
 		t.Fatalf("unexpected trace_id: %v", got)
 	}
-	if got := entry[spanIDField]; got != spanID {
+	if got := entry[defaultNaming.spanID]; got != spanID {
 		t.Fatalf("unexpected span_id: %v", got)
 	}
 	if got := entry["static"]; got != "value" {
@@ -154,10 +156,10 @@ func TestLoggerInjectsTraceMetadata(t *testing.T) {
 	span.End()
 
 	entry := decodeLogLine(t, buf.Bytes())
-	if got := entry[traceIDField]; got != traceID {
+	if got := entry[defaultNaming.traceID]; got != traceID {
 		t.Fatalf("unexpected trace_id: %v", got)
 	}
-	if got := entry[spanIDField]; got != spanID {
+	if got := entry[defaultNaming.spanID]; got != spanID {
 		t.Fatalf("unexpected span_id: %v", got)
 	}
 	if got := entry["foo"]; got != "bar" {
@@ -175,7 +177,7 @@ func TestLoggerInjectsTraceMetadata(t *testing.T) {
 	}
 	logNoCtx.Info().Msg("no-context")
 	plain := decodeLogLine(t, second.Bytes())
-	if _, ok := plain[traceIDField]; ok {
+	if _, ok := plain[defaultNaming.traceID]; ok {
 		t.Fatalf("unexpected trace metadata in logger without context")
 	}
 }
@@ -225,7 +227,7 @@ func TestLoggerWarnAndErrorMarkSpan(t *testing.T) {
 	if len(warnEvents) != 1 {
 		t.Fatalf("expected 1 warn event, got %d", len(warnEvents))
 	}
-	if warnEvents[0].Name != warnEventName {
+	if warnEvents[0].Name != defaultNaming.warnEvent {
 		t.Fatalf("unexpected warn event name: %s", warnEvents[0].Name)
 	}
 	warnAttrs := attributesToMap(warnEvents[0].Attributes)
@@ -241,7 +243,7 @@ func TestLoggerWarnAndErrorMarkSpan(t *testing.T) {
 	if len(errorEvents) != 1 {
 		t.Fatalf("expected 1 error event, got %d", len(errorEvents))
 	}
-	if errorEvents[0].Name != errorEventName {
+	if errorEvents[0].Name != defaultNaming.errorEvent {
 		t.Fatalf("unexpected error event name: %s", errorEvents[0].Name)
 	}
 	errorAttrs := attributesToMap(errorEvents[0].Attributes)
@@ -252,3 +254,73 @@ func TestLoggerWarnAndErrorMarkSpan(t *testing.T) {
 		t.Fatalf("expected error status code error, got %v", errorSnapshot.Status().Code)
 	}
 }
+
+func TestLoggerRecordsSamplingDecision(t *testing.T) {
+	log, buf := newBufferedLogger(t, "sampled-logger", "")
+
+	for _, sampler := range []sdktrace.Sampler{sdktrace.AlwaysSample(), sdktrace.NeverSample()} {
+		tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(sampler))
+		ctx, span := tp.Tracer("logger/test").Start(context.Background(), "sampled-span")
+		buf.Reset()
+		log.Info().Ctx(ctx).Msg("sampling")
+		span.End()
+		_ = tp.Shutdown(context.Background())
+
+		entry := decodeLogLine(t, buf.Bytes())
+		if got := entry[defaultNaming.sampled]; got != span.SpanContext().IsSampled() {
+			t.Fatalf("expected %s=%v, got %v", defaultNaming.sampled, span.SpanContext().IsSampled(), got)
+		}
+		if entry[defaultNaming.traceID] != span.SpanContext().TraceID().String() {
+			t.Fatalf("expected the trace ID next to the sampling decision, got %v", entry[defaultNaming.traceID])
+		}
+
+		record, spanCtx := buildRecord(buf.Bytes(), time.Now(), nil)
+		if spanCtx.IsSampled() != span.SpanContext().IsSampled() {
+			t.Fatalf("expected OTLP trace flags to follow the sampling decision, got %v", spanCtx.TraceFlags())
+		}
+		record.WalkAttributes(func(kv otelLog.KeyValue) bool {
+			if kv.Key == defaultNaming.sampled {
+				t.Fatalf("expected %s to map to trace flags, not an attribute", defaultNaming.sampled)
+			}
+			return true
+		})
+	}
+}
+
+func TestLoggerAddsTraceURLToWarnAndError(t *testing.T) {
+	var buf bytes.Buffer
+	clk := &dedupClock{now: time.UnixMilli(1_700_000_000_000)}
+	log, err := New(context.Background(), Config{
+		Enabled:          true,
+		Console:          false,
+		Writers:          []io.Writer{&buf},
+		Clock:            clk,
+		TraceURLTemplate: "https://grafana.test/trace/{trace_id}?span={span_id}&from={from}&to={to}",
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	tp := sdktrace.NewTracerProvider()
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	ctx, span := tp.Tracer("logger/test").Start(context.Background(), "linked-span")
+	defer span.End()
+	want := "https://grafana.test/trace/" + span.SpanContext().TraceID().String() +
+		"?span=" + span.SpanContext().SpanID().String() + "&from=1699996400000&to=1700003600000"
+
+	log.Info().Ctx(ctx).Msg("no link")
+	if _, ok := decodeLogLine(t, buf.Bytes())[TraceURLField]; ok {
+		t.Fatal("expected info entries without trace_url")
+	}
+
+	for _, write := range []func(){
+		func() { log.Warn().Ctx(ctx).Msg("linked") },
+		func() { log.Error().Ctx(ctx).Msg("linked") },
+	} {
+		buf.Reset()
+		write()
+		if got := decodeLogLine(t, buf.Bytes())[TraceURLField]; got != want {
+			t.Fatalf("expected trace_url %q, got %v", want, got)
+		}
+	}
+}
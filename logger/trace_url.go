@@ -0,0 +1,43 @@
+package logger
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mfahmialkautsar/goo11y/clock"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceURLField holds the link to the entry's trace added when Config.TraceURLTemplate is set.
+const TraceURLField = "trace_url"
+
+// traceURLWindow is how far before and after the entry the {from} and {to} placeholders reach.
+const traceURLWindow = time.Hour
+
+// traceURLTemplate expands Config.TraceURLTemplate for the spans of warn and error entries.
+type traceURLTemplate struct {
+	template string
+	clock    clock.Clock
+}
+
+func newTraceURLTemplate(template string, clk clock.Clock) *traceURLTemplate {
+	if strings.TrimSpace(template) == "" {
+		return nil
+	}
+	return &traceURLTemplate{template: template, clock: clock.OrReal(clk)}
+}
+
+func (t *traceURLTemplate) add(event *zerolog.Event, spanCtx trace.SpanContext) {
+	if t == nil {
+		return
+	}
+	now := t.clock.Now()
+	event.Str(TraceURLField, strings.NewReplacer(
+		"{trace_id}", spanCtx.TraceID().String(),
+		"{span_id}", spanCtx.SpanID().String(),
+		"{from}", strconv.FormatInt(now.Add(-traceURLWindow).UnixMilli(), 10),
+		"{to}", strconv.FormatInt(now.Add(traceURLWindow).UnixMilli(), 10),
+	).Replace(t.template))
+}
@@ -0,0 +1,118 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// Hook observes every entry on its way to the sinks. Unlike a zerolog.Hook, it sees the fields
+// already added to the entry and learns how each sink handled it.
+//
+// BeforeWrite receives the entry's fields decoded from JSON, with numbers as json.Number, and may
+// add, change, or delete fields; returning false drops the entry for every sink. AfterWrite receives
+// the fields as written and one SinkResult per sink. Both run on the logging goroutine, after Dedup
+// and before each sink's encoding; entries written by the export failure logger skip them. A hook
+// must not log through the same logger, which would recurse.
+type Hook interface {
+	BeforeWrite(fields map[string]any) bool
+	AfterWrite(fields map[string]any, results []SinkResult)
+}
+
+// SinkResult reports how one sink handled an entry. Err is nil when the write succeeded. In async
+// delivery it only tells whether the sink's queue accepted the entry, and nil also when DropWhenFull
+// discarded it; the sink writes it later, counting failures and drops in Logger.Stats. Fatal and
+// Panic entries are the exception: they are written before the call returns and report the sink's
+// own result.
+type SinkResult struct {
+	Sink string
+	Err  error
+}
+
+// writeWithHooks runs hooks around out.Write for one JSON entry.
+func writeWithHooks(out fanoutWriter, hooks []Hook, p []byte) (int, error) {
+	entry := bytes.TrimRight(p, "\r\n")
+	var order []string
+	fields := make(map[string]any)
+	decoded := scanJSONObject(entry, func(key, value []byte) {
+		decoder := json.NewDecoder(bytes.NewReader(value))
+		decoder.UseNumber()
+		var v any
+		if decoder.Decode(&v) != nil {
+			return
+		}
+		name := string(key)
+		if _, dup := fields[name]; !dup {
+			order = append(order, name)
+		}
+		fields[name] = v
+	})
+	if !decoded {
+		// Entries that are not JSON objects are written as they are, without field access.
+		fields = nil
+	}
+
+	payload := p
+	if fields != nil {
+		for _, hook := range hooks {
+			if !hook.BeforeWrite(fields) {
+				return len(p), nil
+			}
+		}
+		if encoded, err := encodeFields(order, fields); err == nil {
+			payload = encoded
+		}
+	}
+
+	results := make([]SinkResult, 0, len(out.writers))
+	_, err := out.writeEach(payload, func(name string, err error) {
+		results = append(results, SinkResult{Sink: name, Err: err})
+	})
+	for _, hook := range hooks {
+		hook.AfterWrite(fields, results)
+	}
+	return len(p), err
+}
+
+// encodeFields writes fields as a JSON entry, keeping the original order of the keys that remain
+// and appending the keys added by hooks in sorted order.
+func encodeFields(order []string, fields map[string]any) ([]byte, error) {
+	keys := make([]string, 0, len(fields))
+	known := make(map[string]struct{}, len(order))
+	for _, key := range order {
+		known[key] = struct{}{}
+		if _, ok := fields[key]; ok {
+			keys = append(keys, key)
+		}
+	}
+	added := make([]string, 0)
+	for key := range fields {
+		if _, ok := known[key]; !ok {
+			added = append(added, key)
+		}
+	}
+	sort.Strings(added)
+	keys = append(keys, added...)
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	out := []byte{'{'}
+	for idx, key := range keys {
+		if idx > 0 {
+			out = append(out, ',')
+		}
+		buf.Reset()
+		if err := encoder.Encode(key); err != nil {
+			return nil, err
+		}
+		out = append(out, bytes.TrimRight(buf.Bytes(), "\n")...)
+		out = append(out, ':')
+		buf.Reset()
+		if err := encoder.Encode(fields[key]); err != nil {
+			return nil, err
+		}
+		out = append(out, bytes.TrimRight(buf.Bytes(), "\n")...)
+	}
+	return append(out, '}', '\n'), nil
+}
@@ -0,0 +1,91 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+type recordingHook struct {
+	before  func(map[string]any) bool
+	results [][]SinkResult
+	written []map[string]any
+}
+
+func (h *recordingHook) BeforeWrite(fields map[string]any) bool {
+	return h.before(fields)
+}
+
+func (h *recordingHook) AfterWrite(fields map[string]any, results []SinkResult) {
+	h.written = append(h.written, fields)
+	h.results = append(h.results, results)
+}
+
+type failingSink struct{}
+
+func (failingSink) Write([]byte) (int, error) { return 0, errors.New("sink down") }
+
+func TestHooksRewriteEntriesAndReportSinkResults(t *testing.T) {
+	hook := &recordingHook{before: func(fields map[string]any) bool {
+		if fields["message"] == "drop me" {
+			return false
+		}
+		delete(fields, "secret")
+		fields["team"] = "payments"
+		fields["attempt"] = fields["attempt"].(json.Number)
+		return true
+	}}
+
+	var buf bytes.Buffer
+	log, err := New(context.Background(), Config{
+		Enabled: true,
+		Console: false,
+		Writers: []io.Writer{&buf, failingSink{}},
+		Hooks:   []Hook{hook},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	log.Info().Str("secret", "hunter2").Int64("attempt", 9007199254740993).Msg("charged")
+	log.Info().Msg("drop me")
+
+	var lines []string
+	for line := range strings.SplitSeq(strings.TrimSpace(buf.String()), "\n") {
+		// The failing sink also reports its failure to the local sinks.
+		if !strings.Contains(line, "telemetry export failure") {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) != 1 {
+		t.Fatalf("expected the dropped entry to be skipped, got %q", buf.String())
+	}
+	line := lines[0]
+	if strings.Contains(line, "secret") || !strings.Contains(line, `"team":"payments"`) {
+		t.Fatalf("expected BeforeWrite changes to be written, got %s", line)
+	}
+	if !strings.Contains(line, `"attempt":9007199254740993`) {
+		t.Fatalf("expected integers to keep their precision, got %s", line)
+	}
+	if !strings.HasPrefix(line, `{"level":"info"`) || !strings.HasSuffix(line, `"team":"payments"}`) {
+		t.Fatalf("expected original key order with added keys last, got %s", line)
+	}
+
+	if len(hook.results) != 1 || len(hook.results[0]) < 2 {
+		t.Fatalf("expected one result per sink, got %v", hook.results)
+	}
+	ok, failed := hook.results[0][0], hook.results[0][1]
+	if ok.Sink != "custom_0" || ok.Err != nil {
+		t.Fatalf("unexpected result for the buffer sink: %+v", ok)
+	}
+	if failed.Sink != "custom_1" || failed.Err == nil {
+		t.Fatalf("expected the failing sink's error, got %+v", failed)
+	}
+	if hook.written[0]["team"] != "payments" {
+		t.Fatalf("expected AfterWrite to see the written fields, got %v", hook.written[0])
+	}
+}
@@ -1,45 +1,171 @@
 package logger
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"io"
 	"os"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/mfahmialkautsar/goo11y/internal/otlputil"
 	"github.com/rs/zerolog"
 )
 
+// Writer capability tags. Export failure logs are routed only to sinks tagged WriterTagLocal, so a
+// failing network sink never receives the report of its own failure.
+const (
+	WriterTagLocal   = "local"
+	WriterTagNetwork = "network"
+	WriterTagOTLP    = "otlp"
+)
+
+// TaggedWriter is implemented by sinks that declare their own capability tags. The declared tags
+// replace the ones the sink would otherwise get when it is added to the logger.
+type TaggedWriter interface {
+	io.Writer
+	WriterTags() []string
+}
+
 type namedWriter struct {
-	name   string
-	writer io.Writer
+	name     string
+	writer   io.Writer
+	tags     []string
+	failures *atomic.Uint64
+	// attached marks writers added at runtime; they belong to the caller and are not closed.
+	attached bool
+}
+
+func (w namedWriter) hasTag(tag string) bool {
+	return slices.Contains(w.tags, tag)
+}
+
+// writerTags returns the tags declared by writer, or tags when it declares none.
+func writerTags(writer io.Writer, tags []string) []string {
+	if tagged, ok := unwrapFormat(writer).(TaggedWriter); ok {
+		tags = tagged.WriterTags()
+	}
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if tag = normalizeWriterName(tag); tag != "" && !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
 }
 
+// writerRegistry holds the logger's sinks. The set is replaced copy-on-write so writes never lock.
 type writerRegistry struct {
-	writers []namedWriter
+	mu      sync.Mutex
+	entries atomic.Pointer[[]namedWriter]
+	// hooks run around every write through writer; they are set before the logger is built.
+	hooks []Hook
+	// delivery is set before any sink is added.
+	delivery DeliveryConfig
+	// ordered serializes writes in ordered delivery.
+	ordered sync.Mutex
+	// naming tells the sinks which keys the entries carry; nil uses the defaults.
+	naming *fieldNaming
 }
 
 func newWriterRegistry() *writerRegistry {
-	return &writerRegistry{writers: make([]namedWriter, 0)}
+	f := &writerRegistry{}
+	f.entries.Store(&[]namedWriter{})
+	return f
+}
+
+func (f *writerRegistry) snapshot() []namedWriter {
+	return *f.entries.Load()
 }
 
-func (f *writerRegistry) add(name string, writer io.Writer) {
+func (f *writerRegistry) add(name string, writer io.Writer, tags ...string) {
 	if writer == nil {
 		return
 	}
-	name = strings.ToLower(strings.TrimSpace(name))
+	name = normalizeWriterName(name)
 	if name == "" {
 		name = "custom"
 	}
-	f.writers = append(f.writers, namedWriter{name: name, writer: writer})
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.store(append(f.snapshot(), f.newEntry(name, writer, tags, false)))
+}
+
+// newEntry builds the sink entry for writer, behind its own queue in async delivery.
+func (f *writerRegistry) newEntry(name string, writer io.Writer, tags []string, attached bool) namedWriter {
+	entry := namedWriter{name: name, writer: writer, tags: writerTags(writer, tags), failures: new(atomic.Uint64), attached: attached}
+	if f.delivery.Mode == DeliveryAsync {
+		entry.writer = newAsyncSink(name, writer, f.delivery.queueSize(name), f.delivery.DropWhenFull, entry.failures)
+	}
+	return entry
+}
+
+// attach adds a caller-owned writer under a unique name.
+func (f *writerRegistry) attach(name string, writer io.Writer, tags ...string) error {
+	name = normalizeWriterName(name)
+	if name == "" {
+		return errors.New("logger: writer name is required")
+	}
+	if writer == nil {
+		return errors.New("logger: writer is nil")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	current := f.snapshot()
+	for _, entry := range current {
+		if entry.name == name {
+			return fmt.Errorf("logger: writer %q already exists", name)
+		}
+	}
+	f.store(append(current, f.newEntry(name, writer, tags, true)))
+	return nil
+}
+
+// detach removes the runtime writer registered as name and reports whether it was found.
+func (f *writerRegistry) detach(name string) bool {
+	name = normalizeWriterName(name)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	current := f.snapshot()
+	for idx, entry := range current {
+		if entry.name == name && entry.attached {
+			next := make([]namedWriter, 0, len(current)-1)
+			next = append(next, current[:idx]...)
+			f.store(append(next, current[idx+1:]...))
+			if async, ok := entry.writer.(*asyncSink); ok {
+				async.stop()
+			}
+			return true
+		}
+	}
+	return false
+}
+
+func (f *writerRegistry) store(entries []namedWriter) {
+	f.entries.Store(&entries)
+}
+
+func normalizeWriterName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
 }
 
 func (f *writerRegistry) len() int {
-	return len(f.writers)
+	return len(f.snapshot())
 }
 
 func (f *writerRegistry) close() error {
 	var firstErr error
-	for _, w := range f.writers {
+	for _, entry := range f.snapshot() {
+		if async, ok := entry.writer.(*asyncSink); ok {
+			async.stop()
+		}
+		if entry.attached {
+			continue
+		}
+		w := namedWriter{name: entry.name, writer: unwrapFormat(entry.writer)}
 		// Don't close standard streams or zerolog.ConsoleWriter
 		switch w.writer.(type) {
 		case *os.File:
@@ -68,27 +194,34 @@ func (f *writerRegistry) close() error {
 	return firstErr
 }
 
-func (f *writerRegistry) writer() io.Writer {
-	if len(f.writers) == 0 {
-		return io.Discard
+func (f *writerRegistry) flush(ctx context.Context) error {
+	var errs error
+	for _, entry := range f.snapshot() {
+		if async, ok := entry.writer.(*asyncSink); ok {
+			errs = errors.Join(errs, async.drain(ctx))
+		}
+	}
+	for _, entry := range f.snapshot() {
+		if flusher, ok := unwrapFormat(entry.writer).(interface{ ForceFlush(context.Context) error }); ok {
+			errs = errors.Join(errs, flusher.ForceFlush(ctx))
+		}
 	}
-	return fanoutWriter{writers: append([]namedWriter(nil), f.writers...)}
+	return errs
 }
 
-func (f *writerRegistry) writerExcept(excluded ...string) io.Writer {
-	if len(f.writers) == 0 {
-		return os.Stderr
-	}
-	if len(excluded) == 0 {
-		return fanoutWriter{writers: append([]namedWriter(nil), f.writers...)}
-	}
-	exclude := make(map[string]struct{}, len(excluded))
-	for _, name := range excluded {
-		exclude[strings.ToLower(strings.TrimSpace(name))] = struct{}{}
-	}
-	filtered := make([]namedWriter, 0, len(f.writers))
-	for _, w := range f.writers {
-		if _, skip := exclude[w.name]; skip {
+// writer returns a writer that fans out to the registry's current sinks, including ones attached later.
+func (f *writerRegistry) writer() io.Writer {
+	return registryWriter{registry: f}
+}
+
+// writerTagged returns a writer over the sinks carrying tag, leaving out the sink named excluded.
+// Falls back to os.Stderr when no sink qualifies.
+func (f *writerRegistry) writerTagged(tag, excluded string) io.Writer {
+	excluded = normalizeWriterName(excluded)
+	writers := f.snapshot()
+	filtered := make([]namedWriter, 0, len(writers))
+	for _, w := range writers {
+		if w.name == excluded || !w.hasTag(tag) {
 			continue
 		}
 		filtered = append(filtered, w)
@@ -96,26 +229,79 @@ func (f *writerRegistry) writerExcept(excluded ...string) io.Writer {
 	if len(filtered) == 0 {
 		return os.Stderr
 	}
-	return fanoutWriter{writers: filtered}
+	return fanoutWriter{writers: filtered, levelKey: f.levelKey()}
+}
+
+type registryWriter struct {
+	registry *writerRegistry
+}
+
+func (w registryWriter) Write(p []byte) (int, error) {
+	if w.registry.delivery.Mode == DeliveryOrdered {
+		w.registry.ordered.Lock()
+		defer w.registry.ordered.Unlock()
+	}
+	out := fanoutWriter{writers: w.registry.snapshot(), levelKey: w.registry.levelKey()}
+	if len(w.registry.hooks) > 0 {
+		return writeWithHooks(out, w.registry.hooks, p)
+	}
+	return out.Write(p)
+}
+
+// levelKey returns the key carrying the level of the entries.
+func (f *writerRegistry) levelKey() string {
+	if f.naming == nil {
+		return defaultNaming.to.level
+	}
+	return f.naming.to.level
 }
 
 type fanoutWriter struct {
 	writers []namedWriter
+	// levelKey is the key endsProcess reads the level from.
+	levelKey string
 }
 
 func (w fanoutWriter) Write(p []byte) (int, error) {
+	return w.writeEach(p, nil)
+}
+
+// writeEach writes p to every sink, reporting each sink's outcome to result when it is not nil.
+func (w fanoutWriter) writeEach(p []byte, result func(name string, err error)) (int, error) {
 	if len(w.writers) == 0 {
 		return len(p), nil
 	}
 	var firstErr error
+	// Async sinks write Fatal and Panic entries before the call returns, since the process usually
+	// ends right after it.
+	checked, ends := false, false
 	for _, writer := range w.writers {
 		if writer.writer == nil {
 			continue
 		}
-		if _, err := writer.writer.Write(p); err != nil {
+		var err error
+		if async, ok := writer.writer.(*asyncSink); ok {
+			if !checked {
+				checked, ends = true, endsProcess(p, w.levelKey)
+			}
+			if ends {
+				_, err = async.writeSync(p)
+			} else {
+				_, err = async.Write(p)
+			}
+		} else {
+			_, err = writer.writer.Write(p)
+		}
+		if result != nil {
+			result(writer.name, err)
+		}
+		if err != nil {
 			if firstErr == nil {
 				firstErr = err
 			}
+			if writer.failures != nil {
+				writer.failures.Add(1)
+			}
 			otlputil.LogExportFailure("logger", writer.name, err)
 		}
 	}
@@ -124,3 +310,28 @@ func (w fanoutWriter) Write(p []byte) (int, error) {
 	}
 	return len(p), nil
 }
+
+// AddWriter attaches w as an extra sink named name until RemoveWriter detaches it, without rebuilding the
+// logger. w receives JSON entries; wrap it with NewFormatWriter for another encoding. The caller keeps
+// ownership of w: Close does not close it. Names are case-insensitive and must be unique among the sinks.
+// tags declare the sink's capabilities, WriterTagLocal when omitted; a w implementing TaggedWriter
+// declares its own. Pass WriterTagNetwork for remote sinks so export failure logs skip them.
+// No-op if receiver is nil.
+func (l *Logger) AddWriter(name string, w io.Writer, tags ...string) error {
+	if l == nil || l.writers == nil {
+		return nil
+	}
+	if len(tags) == 0 {
+		tags = []string{WriterTagLocal}
+	}
+	return l.writers.attach(name, w, tags...)
+}
+
+// RemoveWriter detaches the sink added by AddWriter under name and reports whether it was attached.
+// Sinks configured at construction cannot be removed.
+func (l *Logger) RemoveWriter(name string) bool {
+	if l == nil || l.writers == nil {
+		return false
+	}
+	return l.writers.detach(name)
+}
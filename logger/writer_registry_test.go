@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestAddAndRemoveWriterAtRuntime(t *testing.T) {
+	var base bytes.Buffer
+	log, err := New(context.Background(), Config{
+		Enabled: true,
+		Level:   "info",
+		Console: false,
+		Writers: []io.Writer{&base},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = log.Close() })
+
+	var tap bytes.Buffer
+	if err := log.AddWriter("Debug-Tap", &tap); err != nil {
+		t.Fatalf("AddWriter: %v", err)
+	}
+	if err := log.AddWriter("debug-tap", &tap); err == nil {
+		t.Fatal("expected duplicate name to be rejected")
+	}
+	if err := log.AddWriter("custom_0", &tap); err == nil {
+		t.Fatal("expected configured sink name to be rejected")
+	}
+
+	log.Info().Msg("attached")
+	if !log.RemoveWriter("debug-tap") {
+		t.Fatal("expected writer to be removed")
+	}
+	log.Info().Msg("detached")
+
+	if got := tap.String(); !strings.Contains(got, "attached") || strings.Contains(got, "detached") {
+		t.Fatalf("unexpected tap output %q", got)
+	}
+	if !strings.Contains(base.String(), "detached") {
+		t.Fatalf("expected configured writer to keep receiving entries, got %q", base.String())
+	}
+	if log.RemoveWriter("custom_0") {
+		t.Fatal("configured writers should not be removable")
+	}
+}
+
+func TestWriterRegistryConcurrentAttach(t *testing.T) {
+	registry := newWriterRegistry()
+	registry.add("stdout", io.Discard)
+	writer := registry.writer()
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(2)
+		name := string(rune('a' + i))
+		go func() {
+			defer wg.Done()
+			if err := registry.attach(name, io.Discard); err != nil {
+				t.Errorf("attach: %v", err)
+			}
+			registry.detach(name)
+		}()
+		go func() {
+			defer wg.Done()
+			_, _ = writer.Write([]byte("{}"))
+		}()
+	}
+	wg.Wait()
+
+	if registry.len() != 1 {
+		t.Fatalf("expected only the configured writer to remain, got %d", registry.len())
+	}
+}
+
+func TestAddWriterNilLogger(t *testing.T) {
+	var log *Logger
+	if err := log.AddWriter("tap", io.Discard); err != nil {
+		t.Fatalf("AddWriter on nil logger: %v", err)
+	}
+	if log.RemoveWriter("tap") {
+		t.Fatal("RemoveWriter on nil logger should report false")
+	}
+}
@@ -0,0 +1,225 @@
+// Package messaging instruments message producers and consumers independently of the broker client.
+// Wrap the send and handle calls of a Kafka, NATS, RabbitMQ or similar client with Publish and Consume
+// to get semconv spans, header-based context propagation, latency histograms and failure logs.
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mfahmialkautsar/goo11y/logger"
+	"github.com/mfahmialkautsar/goo11y/meter"
+	"github.com/mfahmialkautsar/goo11y/tracer"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.28.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/mfahmialkautsar/goo11y/messaging"
+
+const (
+	publishDurationName = "messaging.client.operation.duration"
+	processDurationName = "messaging.process.duration"
+)
+
+// Well-known messaging.system values.
+const (
+	SystemKafka    = "kafka"
+	SystemNATS     = "nats"
+	SystemRabbitMQ = "rabbitmq"
+)
+
+// Message describes a message crossing the broker boundary.
+// Headers carry the propagated trace context and are allocated on publish when nil.
+type Message struct {
+	Destination   string
+	ID            string
+	ConsumerGroup string
+	BodySize      int
+	Headers       map[string]string
+}
+
+// Option configures an Instrumenter.
+type Option func(*Instrumenter)
+
+// WithTracerProvider records spans through provider instead of the global tracer provider.
+func WithTracerProvider(provider *tracer.Provider) Option {
+	return func(i *Instrumenter) {
+		i.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider records latency through provider instead of the global meter provider.
+func WithMeterProvider(provider *meter.Provider) Option {
+	return func(i *Instrumenter) {
+		i.meterProvider = provider
+	}
+}
+
+// WithLogger logs failures through log instead of the global logger.
+func WithLogger(log *logger.Logger) Option {
+	return func(i *Instrumenter) {
+		i.logger = log
+	}
+}
+
+// Instrumenter wraps publish and consume calls for a single messaging system.
+type Instrumenter struct {
+	system         string
+	tracerProvider *tracer.Provider
+	meterProvider  *meter.Provider
+	logger         *logger.Logger
+
+	tracer          trace.Tracer
+	publishDuration metric.Float64Histogram
+	processDuration metric.Float64Histogram
+}
+
+// New creates an Instrumenter for system, such as SystemKafka.
+// Components not supplied through options fall back to the globals.
+func New(system string, opts ...Option) (*Instrumenter, error) {
+	i := &Instrumenter{system: system}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(i)
+		}
+	}
+	if i.tracerProvider == nil {
+		i.tracerProvider = tracer.Global()
+	}
+	if i.meterProvider == nil {
+		i.meterProvider = meter.Global()
+	}
+
+	i.tracer = i.tracerProvider.Tracer(instrumentationName)
+	m := i.meterProvider.Meter(instrumentationName)
+
+	var err error
+	i.publishDuration, err = m.Float64Histogram(
+		publishDurationName,
+		metric.WithDescription("Duration of messaging publish operations"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("messaging: create publish histogram: %w", err)
+	}
+	i.processDuration, err = m.Float64Histogram(
+		processDurationName,
+		metric.WithDescription("Duration of processing consumed messages"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("messaging: create process histogram: %w", err)
+	}
+	return i, nil
+}
+
+// Publish starts a producer span, injects its context into msg.Headers and calls send.
+// The error returned by send is recorded and passed through.
+func (i *Instrumenter) Publish(ctx context.Context, msg *Message, send func(context.Context, *Message) error) error {
+	if msg == nil {
+		msg = &Message{}
+	}
+	if msg.Headers == nil {
+		msg.Headers = make(map[string]string)
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	attrs := i.attributes(msg, "publish", semconv.MessagingOperationTypePublish)
+	ctx, span := i.tracer.Start(ctx, spanName("publish", msg.Destination),
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(attrs...),
+	)
+	defer span.End()
+
+	otel.GetTextMapPropagator().Inject(ctx, propagation.MapCarrier(msg.Headers))
+
+	start := time.Now()
+	err := send(ctx, msg)
+	i.finish(ctx, span, i.publishDuration, start, attrs, err, "message publish failed", msg)
+	return err
+}
+
+// Consume extracts the producer context from msg.Headers, starts a consumer span as its child
+// and calls handle. The error returned by handle is recorded and passed through.
+func (i *Instrumenter) Consume(ctx context.Context, msg *Message, handle func(context.Context, *Message) error) error {
+	if msg == nil {
+		msg = &Message{}
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if len(msg.Headers) > 0 {
+		ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(msg.Headers))
+	}
+
+	attrs := i.attributes(msg, "process", semconv.MessagingOperationTypeProcess)
+	ctx, span := i.tracer.Start(ctx, spanName("process", msg.Destination),
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(attrs...),
+	)
+	defer span.End()
+
+	start := time.Now()
+	err := handle(ctx, msg)
+	i.finish(ctx, span, i.processDuration, start, attrs, err, "message processing failed", msg)
+	return err
+}
+
+func (i *Instrumenter) attributes(msg *Message, operation string, operationType attribute.KeyValue) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		semconv.MessagingSystemKey.String(i.system),
+		semconv.MessagingOperationName(operation),
+		operationType,
+	}
+	if msg.Destination != "" {
+		attrs = append(attrs, semconv.MessagingDestinationName(msg.Destination))
+	}
+	if msg.ConsumerGroup != "" {
+		attrs = append(attrs, semconv.MessagingConsumerGroupName(msg.ConsumerGroup))
+	}
+	return attrs
+}
+
+func (i *Instrumenter) finish(ctx context.Context, span trace.Span, histogram metric.Float64Histogram, start time.Time, attrs []attribute.KeyValue, err error, failure string, msg *Message) {
+	if msg.ID != "" {
+		span.SetAttributes(semconv.MessagingMessageID(msg.ID))
+	}
+	if msg.BodySize > 0 {
+		span.SetAttributes(semconv.MessagingMessageBodySize(msg.BodySize))
+	}
+
+	metricAttrs := attrs
+	if err != nil {
+		errorType := semconv.ErrorTypeKey.String(fmt.Sprintf("%T", err))
+		metricAttrs = append(append([]attribute.KeyValue(nil), attrs...), errorType)
+		span.SetAttributes(errorType)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+
+		log := i.logger
+		if log == nil {
+			log = logger.Global()
+		}
+		log.Error().Ctx(ctx).
+			Str("messaging_system", i.system).
+			Str("messaging_destination_name", msg.Destination).
+			Err(err).
+			Msg(failure)
+	}
+	histogram.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(metricAttrs...))
+}
+
+func spanName(operation, destination string) string {
+	if destination == "" {
+		return operation
+	}
+	return operation + " " + destination
+}
@@ -0,0 +1,158 @@
+package messaging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mfahmialkautsar/goo11y/internal/testutil/inmemory"
+	"github.com/mfahmialkautsar/goo11y/logger"
+	"github.com/mfahmialkautsar/goo11y/meter"
+	"github.com/mfahmialkautsar/goo11y/tracer"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type fixture struct {
+	inst     *Instrumenter
+	recorder *tracetest.SpanRecorder
+	reader   *sdkmetric.ManualReader
+	logs     *bytes.Buffer
+}
+
+func newFixture(t *testing.T) fixture {
+	t.Helper()
+
+	previous := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(previous) })
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	var logs bytes.Buffer
+	log, err := logger.New(context.Background(), logger.Config{
+		Enabled: true,
+		Console: false,
+		Writers: []io.Writer{&logs},
+	})
+	if err != nil {
+		t.Fatalf("logger.New: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = tp.Shutdown(context.Background())
+		_ = mp.Shutdown(context.Background())
+		_ = log.Close()
+	})
+
+	inst, err := New(SystemKafka,
+		WithTracerProvider(tracer.NewProvider(tp)),
+		WithMeterProvider(meter.NewProvider(mp)),
+		WithLogger(log),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return fixture{inst: inst, recorder: recorder, reader: reader, logs: &logs}
+}
+
+func TestPublishAndConsumePropagateContext(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+
+	msg := &Message{Destination: "orders", ID: "m-1", BodySize: 42}
+	var published trace.SpanContext
+	if err := f.inst.Publish(ctx, msg, func(ctx context.Context, _ *Message) error {
+		published = trace.SpanContextFromContext(ctx)
+		return nil
+	}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if msg.Headers["traceparent"] == "" {
+		t.Fatalf("expected traceparent header, got %v", msg.Headers)
+	}
+
+	if err := f.inst.Consume(ctx, &Message{Destination: "orders", Headers: msg.Headers}, func(context.Context, *Message) error {
+		return nil
+	}); err != nil {
+		t.Fatalf("Consume: %v", err)
+	}
+
+	spans := f.recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(spans))
+	}
+	producer, consumer := spans[0], spans[1]
+	if producer.Name() != "publish orders" || producer.SpanKind() != trace.SpanKindProducer {
+		t.Fatalf("unexpected producer span %s (%v)", producer.Name(), producer.SpanKind())
+	}
+	if consumer.Name() != "process orders" || consumer.SpanKind() != trace.SpanKindConsumer {
+		t.Fatalf("unexpected consumer span %s (%v)", consumer.Name(), consumer.SpanKind())
+	}
+	if consumer.Parent().SpanID() != published.SpanID() {
+		t.Fatal("expected consumer span to be a child of the producer span")
+	}
+
+	rm, err := inmemory.GetMetrics(ctx, f.reader)
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	for _, name := range []string{publishDurationName, processDurationName} {
+		m, ok := inmemory.FindMetricByName(rm, name)
+		if !ok {
+			t.Fatalf("metric %s not recorded", name)
+		}
+		if hist, ok := m.Data.(metricdata.Histogram[float64]); !ok || len(hist.DataPoints) != 1 || hist.DataPoints[0].Count != 1 {
+			t.Fatalf("unexpected %s data %+v", name, m.Data)
+		}
+	}
+}
+
+func TestConsumeRecordsFailure(t *testing.T) {
+	f := newFixture(t)
+
+	boom := errors.New("handler failed")
+	err := f.inst.Consume(context.Background(), &Message{Destination: "payments"}, func(context.Context, *Message) error {
+		return boom
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected handler error, got %v", err)
+	}
+
+	span := f.recorder.Ended()[0]
+	if span.Status().Code != codes.Error {
+		t.Fatalf("expected error status, got %v", span.Status())
+	}
+	if !strings.Contains(f.logs.String(), "message processing failed") {
+		t.Fatalf("expected failure log, got %q", f.logs.String())
+	}
+}
+
+func TestPublishAcceptsNilContext(t *testing.T) {
+	f := newFixture(t)
+
+	msg := &Message{Destination: "orders"}
+	var ctx context.Context
+	if err := f.inst.Publish(ctx, msg, func(ctx context.Context, _ *Message) error {
+		if ctx == nil {
+			t.Fatal("expected send to receive a context")
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("Publish: %v", err)
+	}
+	if msg.Headers["traceparent"] == "" {
+		t.Fatalf("expected traceparent header, got %v", msg.Headers)
+	}
+}
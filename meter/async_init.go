@@ -0,0 +1,51 @@
+package meter
+
+import (
+	"context"
+
+	"github.com/mfahmialkautsar/goo11y/internal/otlputil"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// lazyMetricExporter forwards to an exporter built in the background for Config.AsyncInit. The
+// reader needs temporality and aggregation up front, so they come from the config, matching what
+// the OTLP exporters report. Exports wait for the exporter within their own deadline; instruments
+// keep aggregating meanwhile, so cumulative series lose nothing.
+type lazyMetricExporter struct {
+	exporter    *otlputil.Lazy[sdkmetric.Exporter]
+	temporality sdkmetric.TemporalitySelector
+}
+
+func (e lazyMetricExporter) Temporality(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+	return e.temporality(kind)
+}
+
+func (e lazyMetricExporter) Aggregation(kind sdkmetric.InstrumentKind) sdkmetric.Aggregation {
+	return sdkmetric.DefaultAggregationSelector(kind)
+}
+
+func (e lazyMetricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	exporter, err := e.exporter.Wait(ctx)
+	if err != nil {
+		return err
+	}
+	return exporter.Export(ctx, rm)
+}
+
+func (e lazyMetricExporter) ForceFlush(ctx context.Context) error {
+	exporter, err := e.exporter.Wait(ctx)
+	if err != nil {
+		return err
+	}
+	return exporter.ForceFlush(ctx)
+}
+
+func (e lazyMetricExporter) Shutdown(ctx context.Context) error {
+	exporter, err := e.exporter.Wait(ctx)
+	if err != nil {
+		// A failed build was already reported; there is nothing to shut down.
+		return ctx.Err()
+	}
+	return exporter.Shutdown(ctx)
+}
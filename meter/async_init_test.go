@@ -0,0 +1,48 @@
+package meter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+func TestSetupAsyncInitExportsOnceBuilt(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	provider, err := Setup(ctx, Config{
+		Enabled:     true,
+		Endpoint:    server.Listener.Addr().String(),
+		Insecure:    true,
+		Protocol:    "http",
+		ServiceName: "test-meter-async",
+		AsyncInit:   true,
+	}, resource.Empty())
+	if err != nil {
+		t.Fatalf("setup meter: %v", err)
+	}
+	defer func() {
+		_ = provider.Shutdown(ctx)
+	}()
+
+	counter, err := provider.Meter("async").Int64Counter("requests")
+	if err != nil {
+		t.Fatalf("counter: %v", err)
+	}
+	counter.Add(ctx, 1)
+	if err := provider.ForceFlush(ctx); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+	if requests.Load() == 0 {
+		t.Fatal("expected the lazily built exporter to send the metrics")
+	}
+}
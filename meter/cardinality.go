@@ -0,0 +1,203 @@
+package meter
+
+import (
+	"context"
+	"math"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+const guardInstrumentation = "github.com/mfahmialkautsar/goo11y/meter"
+
+// overflowValue replaces attribute values past MaxValuesPerKey, so their measurements still count.
+const overflowValue = "other"
+
+// cardinalityGuard tracks the attribute keys and values seen per instrument. Keys past the limit are
+// dropped and values past it are replaced with overflowValue. Series past MaxSeries are left to the
+// SDK's overflow aggregation.
+type cardinalityGuard struct {
+	maxAttributes int
+	maxValues     int
+	dropped       atomic.Uint64
+	overflowed    atomic.Uint64
+
+	instruments sync.Map // scope/name -> *instrumentAttributes
+}
+
+// instrumentAttributes holds the keys and values admitted for one instrument. Admitted values are
+// looked up without locking; mu only serializes admitting new ones, so instruments never contend.
+type instrumentAttributes struct {
+	mu       sync.Mutex
+	keys     sync.Map // attribute.Key -> *keyValues
+	keyCount int
+}
+
+type keyValues struct {
+	values sync.Map // string -> struct{}
+	count  int
+	full   atomic.Bool
+}
+
+type attributeVerdict int
+
+const (
+	attributeAdmitted attributeVerdict = iota
+	// attributeOverflowed marks an admitted key carrying a value past MaxValuesPerKey.
+	attributeOverflowed
+	// attributeRejected marks a key past MaxAttributes.
+	attributeRejected
+)
+
+func newCardinalityGuard(cfg CardinalityConfig) *cardinalityGuard {
+	if cfg.MaxAttributes <= 0 && cfg.MaxValuesPerKey <= 0 {
+		return nil
+	}
+	return &cardinalityGuard{
+		maxAttributes: cfg.MaxAttributes,
+		maxValues:     cfg.MaxValuesPerKey,
+	}
+}
+
+// remaps reports whether instruments need a guarded meter to replace overflowing values.
+func (g *cardinalityGuard) remaps() bool {
+	return g != nil && g.maxValues > 0
+}
+
+func (g *cardinalityGuard) state(scope, name string) *instrumentAttributes {
+	id := scope + "/" + name
+	if state, ok := g.instruments.Load(id); ok {
+		return state.(*instrumentAttributes)
+	}
+	state, _ := g.instruments.LoadOrStore(id, &instrumentAttributes{})
+	return state.(*instrumentAttributes)
+}
+
+// filter drops keys past MaxAttributes. Values past MaxValuesPerKey are dropped too unless they were
+// already replaced with overflowValue, which only instruments from a guarded meter do.
+func (g *cardinalityGuard) filter(inst sdkmetric.Instrument) attribute.Filter {
+	state := g.state(inst.Scope.Name, inst.Name)
+	return func(kv attribute.KeyValue) bool {
+		switch g.check(state, kv) {
+		case attributeAdmitted:
+			return true
+		case attributeOverflowed:
+			if isOverflowValue(kv.Value) {
+				return true
+			}
+		}
+		g.dropped.Add(1)
+		return false
+	}
+}
+
+func (g *cardinalityGuard) check(state *instrumentAttributes, kv attribute.KeyValue) attributeVerdict {
+	var value string
+	if g.maxValues > 0 {
+		value = kv.Value.Emit()
+	}
+	if v, ok := state.keys.Load(kv.Key); ok {
+		if verdict, known := g.known(v.(*keyValues), value); known {
+			return verdict
+		}
+	}
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	var values *keyValues
+	if v, ok := state.keys.Load(kv.Key); ok {
+		values = v.(*keyValues)
+	} else {
+		if g.maxAttributes > 0 && state.keyCount >= g.maxAttributes {
+			return attributeRejected
+		}
+		values = &keyValues{}
+		state.keys.Store(kv.Key, values)
+		state.keyCount++
+	}
+	if verdict, known := g.known(values, value); known {
+		return verdict
+	}
+	values.values.Store(value, struct{}{})
+	values.count++
+	if values.count >= g.maxValues {
+		values.full.Store(true)
+	}
+	return attributeAdmitted
+}
+
+// known answers for a value without taking the lock: admitted when seen before, overflowed when
+// the key is full. It reports false when the value still has to be admitted.
+func (g *cardinalityGuard) known(values *keyValues, value string) (attributeVerdict, bool) {
+	if g.maxValues <= 0 {
+		return attributeAdmitted, true
+	}
+	if _, seen := values.values.Load(value); seen {
+		return attributeAdmitted, true
+	}
+	if values.full.Load() {
+		return attributeOverflowed, true
+	}
+	return attributeAdmitted, false
+}
+
+// remap replaces the values past MaxValuesPerKey in set with overflowValue. It reports false when
+// set is unchanged.
+func (g *cardinalityGuard) remap(state *instrumentAttributes, set attribute.Set) (attribute.Set, bool) {
+	var kvs []attribute.KeyValue
+	iter := set.Iter()
+	for i := 0; iter.Next(); i++ {
+		kv := iter.Attribute()
+		if isOverflowValue(kv.Value) || g.check(state, kv) != attributeOverflowed {
+			continue
+		}
+		if kvs == nil {
+			kvs = set.ToSlice()
+		}
+		kvs[i] = attribute.String(string(kv.Key), overflowValue)
+		g.overflowed.Add(1)
+	}
+	if kvs == nil {
+		return set, false
+	}
+	return attribute.NewSet(kvs...), true
+}
+
+func isOverflowValue(v attribute.Value) bool {
+	return v.Type() == attribute.STRING && v.AsString() == overflowValue
+}
+
+func (g *cardinalityGuard) register(m metric.Meter) error {
+	_, err := m.Int64ObservableCounter(
+		"goo11y.meter.attributes.dropped",
+		metric.WithDescription("Metric attributes dropped by the cardinality guard"),
+		metric.WithUnit("{attribute}"),
+		metric.WithInt64Callback(func(_ context.Context, observer metric.Int64Observer) error {
+			observer.Observe(clampInt64(g.dropped.Load()))
+			return nil
+		}),
+	)
+	if err != nil {
+		return err
+	}
+	_, err = m.Int64ObservableCounter(
+		"goo11y.meter.attributes.overflowed",
+		metric.WithDescription("Metric attribute values replaced with \""+overflowValue+"\" by the cardinality guard"),
+		metric.WithUnit("{attribute}"),
+		metric.WithInt64Callback(func(_ context.Context, observer metric.Int64Observer) error {
+			observer.Observe(clampInt64(g.overflowed.Load()))
+			return nil
+		}),
+	)
+	return err
+}
+
+func clampInt64(v uint64) int64 {
+	if v > math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return int64(v)
+}
@@ -0,0 +1,429 @@
+package meter
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/embedded"
+)
+
+// guardedProvider hands out guarded meters, so instruments created from the global meter provider
+// have their overflowing values replaced too.
+type guardedProvider struct {
+	embedded.MeterProvider
+	provider metric.MeterProvider
+	guard    *cardinalityGuard
+}
+
+func (p guardedProvider) Meter(name string, opts ...metric.MeterOption) metric.Meter {
+	return guardedMeter{Meter: p.provider.Meter(name, opts...), scope: name, guard: p.guard}
+}
+
+// guardedMeter replaces attribute values past MaxValuesPerKey with overflowValue before they reach
+// the SDK, whose attribute filter can only drop them. Observable instruments are wrapped so callbacks
+// registered through RegisterCallback can be matched to their state; they are unwrapped again before
+// reaching the SDK.
+type guardedMeter struct {
+	metric.Meter
+	scope string
+	guard *cardinalityGuard
+}
+
+func (g *cardinalityGuard) addOptions(state *instrumentAttributes, opts []metric.AddOption) []metric.AddOption {
+	if len(opts) == 0 {
+		return opts
+	}
+	if set, changed := g.remap(state, metric.NewAddConfig(opts).Attributes()); changed {
+		return []metric.AddOption{metric.WithAttributeSet(set)}
+	}
+	return opts
+}
+
+func (g *cardinalityGuard) recordOptions(state *instrumentAttributes, opts []metric.RecordOption) []metric.RecordOption {
+	if len(opts) == 0 {
+		return opts
+	}
+	if set, changed := g.remap(state, metric.NewRecordConfig(opts).Attributes()); changed {
+		return []metric.RecordOption{metric.WithAttributeSet(set)}
+	}
+	return opts
+}
+
+func (g *cardinalityGuard) observeOptions(state *instrumentAttributes, opts []metric.ObserveOption) []metric.ObserveOption {
+	if len(opts) == 0 {
+		return opts
+	}
+	if set, changed := g.remap(state, metric.NewObserveConfig(opts).Attributes()); changed {
+		return []metric.ObserveOption{metric.WithAttributeSet(set)}
+	}
+	return opts
+}
+
+func (m guardedMeter) Int64Counter(name string, options ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	inst, err := m.Meter.Int64Counter(name, options...)
+	if inst == nil {
+		return nil, err
+	}
+	return guardedInt64Counter{inst, m.guard, m.guard.state(m.scope, name)}, err
+}
+
+func (m guardedMeter) Int64UpDownCounter(name string, options ...metric.Int64UpDownCounterOption) (metric.Int64UpDownCounter, error) {
+	inst, err := m.Meter.Int64UpDownCounter(name, options...)
+	if inst == nil {
+		return nil, err
+	}
+	return guardedInt64UpDownCounter{inst, m.guard, m.guard.state(m.scope, name)}, err
+}
+
+func (m guardedMeter) Int64Histogram(name string, options ...metric.Int64HistogramOption) (metric.Int64Histogram, error) {
+	inst, err := m.Meter.Int64Histogram(name, options...)
+	if inst == nil {
+		return nil, err
+	}
+	return guardedInt64Histogram{inst, m.guard, m.guard.state(m.scope, name)}, err
+}
+
+func (m guardedMeter) Int64Gauge(name string, options ...metric.Int64GaugeOption) (metric.Int64Gauge, error) {
+	inst, err := m.Meter.Int64Gauge(name, options...)
+	if inst == nil {
+		return nil, err
+	}
+	return guardedInt64Gauge{inst, m.guard, m.guard.state(m.scope, name)}, err
+}
+
+func (m guardedMeter) Float64Counter(name string, options ...metric.Float64CounterOption) (metric.Float64Counter, error) {
+	inst, err := m.Meter.Float64Counter(name, options...)
+	if inst == nil {
+		return nil, err
+	}
+	return guardedFloat64Counter{inst, m.guard, m.guard.state(m.scope, name)}, err
+}
+
+func (m guardedMeter) Float64UpDownCounter(name string, options ...metric.Float64UpDownCounterOption) (metric.Float64UpDownCounter, error) {
+	inst, err := m.Meter.Float64UpDownCounter(name, options...)
+	if inst == nil {
+		return nil, err
+	}
+	return guardedFloat64UpDownCounter{inst, m.guard, m.guard.state(m.scope, name)}, err
+}
+
+func (m guardedMeter) Float64Histogram(name string, options ...metric.Float64HistogramOption) (metric.Float64Histogram, error) {
+	inst, err := m.Meter.Float64Histogram(name, options...)
+	if inst == nil {
+		return nil, err
+	}
+	return guardedFloat64Histogram{inst, m.guard, m.guard.state(m.scope, name)}, err
+}
+
+func (m guardedMeter) Float64Gauge(name string, options ...metric.Float64GaugeOption) (metric.Float64Gauge, error) {
+	inst, err := m.Meter.Float64Gauge(name, options...)
+	if inst == nil {
+		return nil, err
+	}
+	return guardedFloat64Gauge{inst, m.guard, m.guard.state(m.scope, name)}, err
+}
+
+func (m guardedMeter) Int64ObservableCounter(name string, options ...metric.Int64ObservableCounterOption) (metric.Int64ObservableCounter, error) {
+	cfg := metric.NewInt64ObservableCounterConfig(options...)
+	state := m.guard.state(m.scope, name)
+	opts := []metric.Int64ObservableCounterOption{metric.WithDescription(cfg.Description()), metric.WithUnit(cfg.Unit())}
+	for _, callback := range cfg.Callbacks() {
+		opts = append(opts, metric.WithInt64Callback(m.guard.int64Callback(state, callback)))
+	}
+	inst, err := m.Meter.Int64ObservableCounter(name, opts...)
+	if inst == nil {
+		return nil, err
+	}
+	return guardedInt64ObservableCounter{inst, state}, err
+}
+
+func (m guardedMeter) Int64ObservableUpDownCounter(name string, options ...metric.Int64ObservableUpDownCounterOption) (metric.Int64ObservableUpDownCounter, error) {
+	cfg := metric.NewInt64ObservableUpDownCounterConfig(options...)
+	state := m.guard.state(m.scope, name)
+	opts := []metric.Int64ObservableUpDownCounterOption{metric.WithDescription(cfg.Description()), metric.WithUnit(cfg.Unit())}
+	for _, callback := range cfg.Callbacks() {
+		opts = append(opts, metric.WithInt64Callback(m.guard.int64Callback(state, callback)))
+	}
+	inst, err := m.Meter.Int64ObservableUpDownCounter(name, opts...)
+	if inst == nil {
+		return nil, err
+	}
+	return guardedInt64ObservableUpDownCounter{inst, state}, err
+}
+
+func (m guardedMeter) Int64ObservableGauge(name string, options ...metric.Int64ObservableGaugeOption) (metric.Int64ObservableGauge, error) {
+	cfg := metric.NewInt64ObservableGaugeConfig(options...)
+	state := m.guard.state(m.scope, name)
+	opts := []metric.Int64ObservableGaugeOption{metric.WithDescription(cfg.Description()), metric.WithUnit(cfg.Unit())}
+	for _, callback := range cfg.Callbacks() {
+		opts = append(opts, metric.WithInt64Callback(m.guard.int64Callback(state, callback)))
+	}
+	inst, err := m.Meter.Int64ObservableGauge(name, opts...)
+	if inst == nil {
+		return nil, err
+	}
+	return guardedInt64ObservableGauge{inst, state}, err
+}
+
+func (m guardedMeter) Float64ObservableCounter(name string, options ...metric.Float64ObservableCounterOption) (metric.Float64ObservableCounter, error) {
+	cfg := metric.NewFloat64ObservableCounterConfig(options...)
+	state := m.guard.state(m.scope, name)
+	opts := []metric.Float64ObservableCounterOption{metric.WithDescription(cfg.Description()), metric.WithUnit(cfg.Unit())}
+	for _, callback := range cfg.Callbacks() {
+		opts = append(opts, metric.WithFloat64Callback(m.guard.float64Callback(state, callback)))
+	}
+	inst, err := m.Meter.Float64ObservableCounter(name, opts...)
+	if inst == nil {
+		return nil, err
+	}
+	return guardedFloat64ObservableCounter{inst, state}, err
+}
+
+func (m guardedMeter) Float64ObservableUpDownCounter(name string, options ...metric.Float64ObservableUpDownCounterOption) (metric.Float64ObservableUpDownCounter, error) {
+	cfg := metric.NewFloat64ObservableUpDownCounterConfig(options...)
+	state := m.guard.state(m.scope, name)
+	opts := []metric.Float64ObservableUpDownCounterOption{metric.WithDescription(cfg.Description()), metric.WithUnit(cfg.Unit())}
+	for _, callback := range cfg.Callbacks() {
+		opts = append(opts, metric.WithFloat64Callback(m.guard.float64Callback(state, callback)))
+	}
+	inst, err := m.Meter.Float64ObservableUpDownCounter(name, opts...)
+	if inst == nil {
+		return nil, err
+	}
+	return guardedFloat64ObservableUpDownCounter{inst, state}, err
+}
+
+func (m guardedMeter) Float64ObservableGauge(name string, options ...metric.Float64ObservableGaugeOption) (metric.Float64ObservableGauge, error) {
+	cfg := metric.NewFloat64ObservableGaugeConfig(options...)
+	state := m.guard.state(m.scope, name)
+	opts := []metric.Float64ObservableGaugeOption{metric.WithDescription(cfg.Description()), metric.WithUnit(cfg.Unit())}
+	for _, callback := range cfg.Callbacks() {
+		opts = append(opts, metric.WithFloat64Callback(m.guard.float64Callback(state, callback)))
+	}
+	inst, err := m.Meter.Float64ObservableGauge(name, opts...)
+	if inst == nil {
+		return nil, err
+	}
+	return guardedFloat64ObservableGauge{inst, state}, err
+}
+
+// RegisterCallback unwraps the guarded observables for the SDK and wraps the observer f receives,
+// so its measurements are remapped against the state of the instrument they are made for.
+func (m guardedMeter) RegisterCallback(f metric.Callback, instruments ...metric.Observable) (metric.Registration, error) {
+	raw := make([]metric.Observable, len(instruments))
+	for i, inst := range instruments {
+		raw[i] = unwrapObservable(inst)
+	}
+	return m.Meter.RegisterCallback(func(ctx context.Context, observer metric.Observer) error {
+		return f(ctx, guardedObserver{Observer: observer, guard: m.guard})
+	}, raw...)
+}
+
+func (g *cardinalityGuard) int64Callback(state *instrumentAttributes, callback metric.Int64Callback) metric.Int64Callback {
+	return func(ctx context.Context, observer metric.Int64Observer) error {
+		return callback(ctx, guardedInt64Observer{observer, g, state})
+	}
+}
+
+func (g *cardinalityGuard) float64Callback(state *instrumentAttributes, callback metric.Float64Callback) metric.Float64Callback {
+	return func(ctx context.Context, observer metric.Float64Observer) error {
+		return callback(ctx, guardedFloat64Observer{observer, g, state})
+	}
+}
+
+type guardedInt64Observer struct {
+	metric.Int64Observer
+	guard *cardinalityGuard
+	state *instrumentAttributes
+}
+
+func (o guardedInt64Observer) Observe(value int64, options ...metric.ObserveOption) {
+	o.Int64Observer.Observe(value, o.guard.observeOptions(o.state, options)...)
+}
+
+type guardedFloat64Observer struct {
+	metric.Float64Observer
+	guard *cardinalityGuard
+	state *instrumentAttributes
+}
+
+func (o guardedFloat64Observer) Observe(value float64, options ...metric.ObserveOption) {
+	o.Float64Observer.Observe(value, o.guard.observeOptions(o.state, options)...)
+}
+
+type guardedObserver struct {
+	metric.Observer
+	guard *cardinalityGuard
+}
+
+func (o guardedObserver) ObserveInt64(inst metric.Int64Observable, value int64, options ...metric.ObserveOption) {
+	if guarded, ok := inst.(guardedInt64Observable); ok {
+		raw, state := guarded.unwrapInt64()
+		o.Observer.ObserveInt64(raw, value, o.guard.observeOptions(state, options)...)
+		return
+	}
+	o.Observer.ObserveInt64(inst, value, options...)
+}
+
+func (o guardedObserver) ObserveFloat64(inst metric.Float64Observable, value float64, options ...metric.ObserveOption) {
+	if guarded, ok := inst.(guardedFloat64Observable); ok {
+		raw, state := guarded.unwrapFloat64()
+		o.Observer.ObserveFloat64(raw, value, o.guard.observeOptions(state, options)...)
+		return
+	}
+	o.Observer.ObserveFloat64(inst, value, options...)
+}
+
+type guardedInt64Observable interface {
+	unwrapInt64() (metric.Int64Observable, *instrumentAttributes)
+}
+
+type guardedFloat64Observable interface {
+	unwrapFloat64() (metric.Float64Observable, *instrumentAttributes)
+}
+
+func unwrapObservable(inst metric.Observable) metric.Observable {
+	switch guarded := inst.(type) {
+	case guardedInt64Observable:
+		raw, _ := guarded.unwrapInt64()
+		return raw
+	case guardedFloat64Observable:
+		raw, _ := guarded.unwrapFloat64()
+		return raw
+	}
+	return inst
+}
+
+type guardedInt64Counter struct {
+	metric.Int64Counter
+	guard *cardinalityGuard
+	state *instrumentAttributes
+}
+
+func (c guardedInt64Counter) Add(ctx context.Context, incr int64, options ...metric.AddOption) {
+	c.Int64Counter.Add(ctx, incr, c.guard.addOptions(c.state, options)...)
+}
+
+type guardedInt64UpDownCounter struct {
+	metric.Int64UpDownCounter
+	guard *cardinalityGuard
+	state *instrumentAttributes
+}
+
+func (c guardedInt64UpDownCounter) Add(ctx context.Context, incr int64, options ...metric.AddOption) {
+	c.Int64UpDownCounter.Add(ctx, incr, c.guard.addOptions(c.state, options)...)
+}
+
+type guardedInt64Histogram struct {
+	metric.Int64Histogram
+	guard *cardinalityGuard
+	state *instrumentAttributes
+}
+
+func (h guardedInt64Histogram) Record(ctx context.Context, value int64, options ...metric.RecordOption) {
+	h.Int64Histogram.Record(ctx, value, h.guard.recordOptions(h.state, options)...)
+}
+
+type guardedInt64Gauge struct {
+	metric.Int64Gauge
+	guard *cardinalityGuard
+	state *instrumentAttributes
+}
+
+func (g guardedInt64Gauge) Record(ctx context.Context, value int64, options ...metric.RecordOption) {
+	g.Int64Gauge.Record(ctx, value, g.guard.recordOptions(g.state, options)...)
+}
+
+type guardedFloat64Counter struct {
+	metric.Float64Counter
+	guard *cardinalityGuard
+	state *instrumentAttributes
+}
+
+func (c guardedFloat64Counter) Add(ctx context.Context, incr float64, options ...metric.AddOption) {
+	c.Float64Counter.Add(ctx, incr, c.guard.addOptions(c.state, options)...)
+}
+
+type guardedFloat64UpDownCounter struct {
+	metric.Float64UpDownCounter
+	guard *cardinalityGuard
+	state *instrumentAttributes
+}
+
+func (c guardedFloat64UpDownCounter) Add(ctx context.Context, incr float64, options ...metric.AddOption) {
+	c.Float64UpDownCounter.Add(ctx, incr, c.guard.addOptions(c.state, options)...)
+}
+
+type guardedFloat64Histogram struct {
+	metric.Float64Histogram
+	guard *cardinalityGuard
+	state *instrumentAttributes
+}
+
+func (h guardedFloat64Histogram) Record(ctx context.Context, value float64, options ...metric.RecordOption) {
+	h.Float64Histogram.Record(ctx, value, h.guard.recordOptions(h.state, options)...)
+}
+
+type guardedFloat64Gauge struct {
+	metric.Float64Gauge
+	guard *cardinalityGuard
+	state *instrumentAttributes
+}
+
+func (g guardedFloat64Gauge) Record(ctx context.Context, value float64, options ...metric.RecordOption) {
+	g.Float64Gauge.Record(ctx, value, g.guard.recordOptions(g.state, options)...)
+}
+
+type guardedInt64ObservableCounter struct {
+	metric.Int64ObservableCounter
+	state *instrumentAttributes
+}
+
+func (o guardedInt64ObservableCounter) unwrapInt64() (metric.Int64Observable, *instrumentAttributes) {
+	return o.Int64ObservableCounter, o.state
+}
+
+type guardedInt64ObservableUpDownCounter struct {
+	metric.Int64ObservableUpDownCounter
+	state *instrumentAttributes
+}
+
+func (o guardedInt64ObservableUpDownCounter) unwrapInt64() (metric.Int64Observable, *instrumentAttributes) {
+	return o.Int64ObservableUpDownCounter, o.state
+}
+
+type guardedInt64ObservableGauge struct {
+	metric.Int64ObservableGauge
+	state *instrumentAttributes
+}
+
+func (o guardedInt64ObservableGauge) unwrapInt64() (metric.Int64Observable, *instrumentAttributes) {
+	return o.Int64ObservableGauge, o.state
+}
+
+type guardedFloat64ObservableCounter struct {
+	metric.Float64ObservableCounter
+	state *instrumentAttributes
+}
+
+func (o guardedFloat64ObservableCounter) unwrapFloat64() (metric.Float64Observable, *instrumentAttributes) {
+	return o.Float64ObservableCounter, o.state
+}
+
+type guardedFloat64ObservableUpDownCounter struct {
+	metric.Float64ObservableUpDownCounter
+	state *instrumentAttributes
+}
+
+func (o guardedFloat64ObservableUpDownCounter) unwrapFloat64() (metric.Float64Observable, *instrumentAttributes) {
+	return o.Float64ObservableUpDownCounter, o.state
+}
+
+type guardedFloat64ObservableGauge struct {
+	metric.Float64ObservableGauge
+	state *instrumentAttributes
+}
+
+func (o guardedFloat64ObservableGauge) unwrapFloat64() (metric.Float64Observable, *instrumentAttributes) {
+	return o.Float64ObservableGauge, o.state
+}
@@ -0,0 +1,181 @@
+package meter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+func setupGuarded(t *testing.T, cardinality CardinalityConfig) (*Provider, *sdkmetric.ManualReader) {
+	t.Helper()
+	reader := sdkmetric.NewManualReader()
+	provider, err := Setup(context.Background(), Config{
+		Enabled:     true,
+		Endpoint:    "localhost:4318",
+		Cardinality: cardinality,
+	}, resource.Empty(), WithMetricReader(reader))
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+	return provider, reader
+}
+
+func collectSums(t *testing.T, reader *sdkmetric.ManualReader, name string) []metricdata.DataPoint[int64] {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			if sum, ok := m.Data.(metricdata.Sum[int64]); ok && m.Name == name {
+				return sum.DataPoints
+			}
+		}
+	}
+	t.Fatalf("metric %s not collected", name)
+	return nil
+}
+
+func TestCardinalityGuardDropsExcessAttributes(t *testing.T) {
+	provider, reader := setupGuarded(t, CardinalityConfig{MaxAttributes: 1, MaxValuesPerKey: 2})
+
+	counter, err := provider.Meter("guarded").Int64Counter("requests")
+	if err != nil {
+		t.Fatalf("Int64Counter: %v", err)
+	}
+	ctx := context.Background()
+	counter.Add(ctx, 1, metric.WithAttributes(attribute.String("route", "/a")))
+	for _, route := range []string{"/b", "/c", "/d"} {
+		counter.Add(ctx, 1, metric.WithAttributes(attribute.String("route", route), attribute.String("user", "u1")))
+	}
+
+	points := collectSums(t, reader, "requests")
+	seen := map[string]int64{}
+	for _, point := range points {
+		if _, ok := point.Attributes.Value("user"); ok {
+			t.Fatalf("attribute past MaxAttributes should be dropped: %v", point.Attributes)
+		}
+		route, _ := point.Attributes.Value("route")
+		seen[route.AsString()] += point.Value
+	}
+	if seen["/a"] != 1 || seen["/b"] != 1 || seen[overflowValue] != 2 || len(seen) != 3 {
+		t.Fatalf("expected routes past the limit to become %q, got %v", overflowValue, seen)
+	}
+
+	stats := provider.Stats()
+	if stats.DroppedAttributes != 3 {
+		t.Fatalf("expected 3 dropped attributes, got %d", stats.DroppedAttributes)
+	}
+	if stats.OverflowedAttributes != 2 {
+		t.Fatalf("expected 2 overflowed values, got %d", stats.OverflowedAttributes)
+	}
+	if points := collectSums(t, reader, "goo11y.meter.attributes.dropped"); len(points) != 1 || points[0].Value != 3 {
+		t.Fatalf("expected dropped counter to be exported, got %v", points)
+	}
+	if points := collectSums(t, reader, "goo11y.meter.attributes.overflowed"); len(points) != 1 || points[0].Value != 2 {
+		t.Fatalf("expected overflowed counter to be exported, got %v", points)
+	}
+}
+
+func TestCardinalityGuardRemapsObservedValues(t *testing.T) {
+	provider, reader := setupGuarded(t, CardinalityConfig{MaxValuesPerKey: 1})
+	m := provider.Meter("guarded")
+
+	_, err := m.Int64ObservableCounter("queue.depth", metric.WithInt64Callback(func(_ context.Context, o metric.Int64Observer) error {
+		o.Observe(1, metric.WithAttributes(attribute.String("queue", "a")))
+		o.Observe(2, metric.WithAttributes(attribute.String("queue", "b")))
+		return nil
+	}))
+	if err != nil {
+		t.Fatalf("Int64ObservableCounter: %v", err)
+	}
+	jobs, err := m.Int64ObservableCounter("jobs")
+	if err != nil {
+		t.Fatalf("Int64ObservableCounter: %v", err)
+	}
+	if _, err := m.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		o.ObserveInt64(jobs, 3, metric.WithAttributes(attribute.String("kind", "x")))
+		o.ObserveInt64(jobs, 4, metric.WithAttributes(attribute.String("kind", "y")))
+		return nil
+	}, jobs); err != nil {
+		t.Fatalf("RegisterCallback: %v", err)
+	}
+
+	for name, key := range map[string]string{"queue.depth": "queue", "jobs": "kind"} {
+		seen := map[string]int64{}
+		for _, point := range collectSums(t, reader, name) {
+			v, _ := point.Attributes.Value(attribute.Key(key))
+			seen[v.AsString()] += point.Value
+		}
+		if len(seen) != 2 || seen[overflowValue] == 0 {
+			t.Fatalf("%s: expected the second value to become %q, got %v", name, overflowValue, seen)
+		}
+	}
+}
+
+func TestCardinalityGuardConcurrentInstruments(t *testing.T) {
+	provider, reader := setupGuarded(t, CardinalityConfig{MaxValuesPerKey: 4})
+	m := provider.Meter("guarded")
+
+	var wg sync.WaitGroup
+	for i := range 4 {
+		counter, err := m.Int64Counter(fmt.Sprintf("requests.%d", i))
+		if err != nil {
+			t.Fatalf("Int64Counter: %v", err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 100 {
+				counter.Add(context.Background(), 1, metric.WithAttributes(attribute.Int("id", j%8)))
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i := range 4 {
+		var total int64
+		points := collectSums(t, reader, fmt.Sprintf("requests.%d", i))
+		for _, point := range points {
+			total += point.Value
+		}
+		if len(points) != 5 || total != 100 {
+			t.Fatalf("requests.%d: expected 4 values plus %q covering 100 adds, got %d series totalling %d", i, overflowValue, len(points), total)
+		}
+	}
+}
+
+func TestCardinalityMaxSeriesOverflows(t *testing.T) {
+	provider, reader := setupGuarded(t, CardinalityConfig{MaxSeries: 2})
+
+	counter, err := provider.Meter("guarded").Int64Counter("requests")
+	if err != nil {
+		t.Fatalf("Int64Counter: %v", err)
+	}
+	for _, id := range []string{"a", "b", "c", "d"} {
+		counter.Add(context.Background(), 1, metric.WithAttributes(attribute.String("id", id)))
+	}
+
+	points := collectSums(t, reader, "requests")
+	if len(points) != 2 {
+		t.Fatalf("expected series capped at 2, got %d", len(points))
+	}
+	overflow := false
+	for _, point := range points {
+		if v, ok := point.Attributes.Value("otel.metric.overflow"); ok && v.AsBool() {
+			overflow = true
+		}
+	}
+	if !overflow {
+		t.Fatalf("expected overflow series, got %v", points)
+	}
+}
@@ -1,20 +1,27 @@
 package meter
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/creasty/defaults"
 	"github.com/go-playground/validator/v10"
 	"github.com/mfahmialkautsar/goo11y/auth"
+	"github.com/mfahmialkautsar/goo11y/clock"
 	"github.com/mfahmialkautsar/goo11y/internal/fileutil"
 )
 
 // Config governs metric provider setup.
 // Endpoint accepts a base URL (host[:port] with optional path). Provided schemes decide TLS mode;
-// when absent, the Insecure flag controls whether HTTP is used.
+// when absent, the Insecure flag controls whether HTTP is used. Endpoint, Insecure, Headers, Protocol,
+// Timeout, and Certificate fall back to the standard OTEL_EXPORTER_OTLP_METRICS_* and OTEL_EXPORTER_OTLP_*
+// variables when left unset.
 type Config struct {
-	Enabled        bool
-	Endpoint       string `validate:"required_if=Enabled true"`
+	Enabled  bool
+	Endpoint string `validate:"required_if=Enabled true"`
+	// EndpointIsURL sends HTTP exports to Endpoint's path as given instead of appending /v1/metrics.
+	// It is set when Endpoint comes from OTEL_EXPORTER_OTLP_METRICS_ENDPOINT, which holds the full URL.
+	EndpointIsURL  bool
 	Insecure       bool
 	Protocol       string `default:"http" validate:"oneof=http grpc"`
 	Async          bool   `default:"true"`
@@ -23,8 +30,104 @@ type Config struct {
 	ExportInterval time.Duration `default:"10s" validate:"gt=0"`
 	QueueDir       string
 	Runtime        RuntimeConfig
-	Credentials    auth.Credentials
-	UseGlobal      bool
+	Info           InfoConfig
+	// DisableUptime skips the process.uptime and process.start_time_unix gauges registered with
+	// every enabled provider.
+	DisableUptime bool
+	Credentials   auth.Credentials
+	UseGlobal     bool
+	Secondary     SecondaryConfig
+	// HistogramBuckets sets explicit bucket boundaries for histograms by instrument name.
+	// DefaultHistogramBuckets applies to every other histogram; when nil the SDK defaults remain.
+	// Boundaries must be strictly increasing.
+	HistogramBuckets        map[string][]float64
+	DefaultHistogramBuckets []float64
+	Cardinality             CardinalityConfig
+	// ExportTimeout bounds each collect and export cycle of the periodic reader, as
+	// OTEL_METRIC_EXPORT_TIMEOUT does; the OTLP requests of a cycle are bounded by Timeout as well.
+	ExportTimeout time.Duration `default:"30s" validate:"gt=0"`
+	// AsyncInit builds the exporter, its connection, and its spool in the background, so Setup does
+	// not wait for the collector. Exports before it is ready wait for it within ExportTimeout, and
+	// build errors are logged as export failures instead of failing Setup.
+	AsyncInit bool
+	// Timeout bounds each OTLP export request, like logger.OTLPConfig.Timeout and tracer's
+	// BackendConfig.Timeout, and reads OTEL_EXPORTER_OTLP_METRICS_TIMEOUT. A Timeout longer than
+	// ExportTimeout is cut short by the cycle. SpoolTimeout bounds each delivery of a spooled request
+	// when the spool drains, outside any cycle, and defaults to Timeout. Neither follows ExportInterval.
+	Timeout      time.Duration `default:"10s" validate:"gt=0"`
+	SpoolTimeout time.Duration `validate:"gte=0"`
+	// ExportJitterPercent delays every export by a random share of ExportInterval up to this
+	// percentage, and AlignExports schedules exports on multiples of ExportInterval on the wall
+	// clock. Together they spread a fleet's pushes instead of letting restarts synchronize them.
+	ExportJitterPercent int `validate:"gte=0,lte=100"`
+	AlignExports        bool
+	// ManualExport turns the push schedule off; metrics are exported only by ForceFlush and Shutdown.
+	ManualExport bool
+	// SpoolMaxAge drops spooled requests older than this regardless of their attempts; zero keeps them
+	// until the retry limit. OnSpoolDrop receives every request the spool discards, with the reason
+	// (corrupt, expired, retries_exhausted, overflow, or rejected).
+	SpoolMaxAge time.Duration `validate:"gte=0"`
+	OnSpoolDrop func(token string, payload []byte, reason string)
+	// StatusPolicy overrides what the spool does with a request the collector refuses: "retry",
+	// "drop", or "quarantine" (keep it in the queue's quarantine directory). It is keyed by HTTP
+	// status, or by gRPC status code with the grpc protocol. Unlisted HTTP statuses retry 408, 429,
+	// and 5xx and drop other 4xx; unlisted gRPC codes retry those OTLP marks retryable and drop the
+	// rest.
+	StatusPolicy map[int]string `validate:"dive,oneof=retry drop quarantine"`
+	// OnInstrumentConflict receives each instrument created through Provider.Meter under a name its
+	// meter already used with a different kind, unit, or description, with both call sites. Nil
+	// reports conflicts through otel.Handle; goo11y.New logs them as warnings. Stats counts them and
+	// Provider.InstrumentConflicts lists them.
+	OnInstrumentConflict func(InstrumentConflict)
+	// MaxRequestsPerSecond and MaxBytesPerSecond pace the spool's deliveries, with jitter, so a
+	// backlog drained after an outage does not trip the collector's rate limits and get throttled
+	// back into the spool. Zero leaves a limit off.
+	MaxRequestsPerSecond float64 `validate:"gte=0"`
+	MaxBytesPerSecond    int     `validate:"gte=0"`
+	// Temporality selects the aggregation temporality requested from the OTLP exporter: "cumulative"
+	// (the default), "delta" for backends such as Datadog and Dynatrace (up-down counters stay
+	// cumulative), or "lowmemory", which uses delta for synchronous counters and histograms only.
+	// When empty, OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE applies; likewise a zero
+	// ExportInterval or ExportTimeout reads OTEL_METRIC_EXPORT_INTERVAL or OTEL_METRIC_EXPORT_TIMEOUT.
+	Temporality string `validate:"omitempty,oneof=cumulative delta lowmemory"`
+	// Headers are sent with every export, below the Credentials headers.
+	Headers map[string]string
+	// Certificate is a PEM file of CA certificates trusted for the primary and secondary collectors
+	// instead of the system roots.
+	Certificate string
+	// MaxPayloadBytes splits export requests whose encoded size exceeds it into smaller requests,
+	// metric by metric, before they are sent or spooled. Without the spool, a retry after one part
+	// fails resends the parts already accepted, so those metrics may arrive twice. Zero sends
+	// exports as they are.
+	MaxPayloadBytes int `validate:"gte=0"`
+	// Clock drives spool retry scheduling and, with ExportJitterPercent, AlignExports, or
+	// ManualExport, the export schedule. Nil uses the real clock; tests can pass
+	// goo11ytest.NewFakeClock.
+	Clock clock.Clock
+}
+
+// CardinalityConfig bounds attribute cardinality per instrument; zero leaves a limit off.
+// Attributes past MaxAttributes distinct keys are dropped from the measurement and counted in
+// goo11y.meter.attributes.dropped. Values past MaxValuesPerKey distinct values for their key are
+// replaced with "other" and counted in goo11y.meter.attributes.overflowed, so their measurements
+// still count.
+// Attribute sets past MaxSeries per collection are aggregated into one series marked
+// otel.metric.overflow=true.
+type CardinalityConfig struct {
+	MaxAttributes   int `validate:"gte=0"`
+	MaxValuesPerKey int `validate:"gte=0"`
+	MaxSeries       int `validate:"gte=0"`
+}
+
+// SecondaryConfig names a fail-over collector used by the spool when the primary endpoint keeps failing.
+// Exports move to Endpoint after Threshold consecutive failures and move back once a probe of the
+// primary, sent at most every ProbeInterval, succeeds. It requires UseSpool.
+type SecondaryConfig struct {
+	Endpoint      string
+	Insecure      bool
+	Credentials   auth.Credentials
+	Threshold     int           `default:"3" validate:"gte=0"`
+	ProbeInterval time.Duration `default:"30s" validate:"gte=0"`
 }
 
 // RuntimeConfig controls optional runtime metric instrumentation.
@@ -33,7 +136,11 @@ type RuntimeConfig struct {
 }
 
 func (c Config) withDefaults() Config {
+	c.applyEnv()
 	_ = defaults.Set(&c)
+	if c.SpoolTimeout == 0 {
+		c.SpoolTimeout = c.Timeout
+	}
 	if c.QueueDir == "" {
 		c.QueueDir = fileutil.DefaultQueueDir("metrics")
 	}
@@ -48,5 +155,16 @@ func (c Config) ApplyDefaults() Config {
 // Validate ensures the configuration is complete when metrics are enabled.
 func (c Config) Validate() error {
 	configValidator := validator.New(validator.WithRequiredStructEnabled())
-	return configValidator.Struct(c)
+	if err := configValidator.Struct(c); err != nil {
+		return err
+	}
+	for name, bounds := range c.HistogramBuckets {
+		if err := validateBoundaries(bounds); err != nil {
+			return fmt.Errorf("histogram buckets %q: %w", name, err)
+		}
+	}
+	if err := validateBoundaries(c.DefaultHistogramBuckets); err != nil {
+		return fmt.Errorf("default histogram buckets: %w", err)
+	}
+	return nil
 }
@@ -93,6 +93,16 @@ func TestConfigValidate(t *testing.T) {
 			}.ApplyDefaults(),
 			wantErr: false,
 		},
+		{
+			name: "invalid status policy action",
+			config: Config{
+				Enabled:      true,
+				Endpoint:     "localhost:4318",
+				ServiceName:  "test-service",
+				StatusPolicy: map[int]string{400: "ignore"},
+			}.ApplyDefaults(),
+			wantErr: true,
+		},
 		{
 			name: "invalid exporter type",
 			config: Config{
@@ -0,0 +1,180 @@
+package meter
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+type contextAttributesKey struct{}
+
+// ContextWithAttributes returns ctx carrying attrs for the instruments of a ContextMeter, on top of
+// the attributes ctx already carries; a repeated key takes the new value. Use it for request-scoped
+// dimensions such as the route or tenant, set once where the request enters.
+func ContextWithAttributes(ctx context.Context, attrs ...attribute.KeyValue) context.Context {
+	if len(attrs) == 0 {
+		return ctx
+	}
+	existing := AttributesFromContext(ctx)
+	merged := make([]attribute.KeyValue, 0, len(existing)+len(attrs))
+	merged = append(merged, existing...)
+	merged = append(merged, attrs...)
+	return context.WithValue(ctx, contextAttributesKey{}, merged)
+}
+
+// AttributesFromContext returns the attributes added by ContextWithAttributes, oldest first.
+func AttributesFromContext(ctx context.Context) []attribute.KeyValue {
+	if ctx == nil {
+		return nil
+	}
+	attrs, _ := ctx.Value(contextAttributesKey{}).([]attribute.KeyValue)
+	return attrs
+}
+
+// ContextMeter wraps m so its synchronous instruments add the attributes carried by the context
+// passed to Add or Record. Attributes given to the call itself win over context ones with the same
+// key. Observable instruments are returned unchanged, since their callbacks run outside any request.
+func ContextMeter(m metric.Meter) metric.Meter {
+	if m == nil {
+		return nil
+	}
+	if _, ok := m.(contextMeter); ok {
+		return m
+	}
+	return contextMeter{Meter: m}
+}
+
+type contextMeter struct {
+	metric.Meter
+}
+
+func (m contextMeter) Int64Counter(name string, options ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	inst, err := m.Meter.Int64Counter(name, options...)
+	if inst == nil {
+		return nil, err
+	}
+	return int64Counter{inst}, err
+}
+
+func (m contextMeter) Int64UpDownCounter(name string, options ...metric.Int64UpDownCounterOption) (metric.Int64UpDownCounter, error) {
+	inst, err := m.Meter.Int64UpDownCounter(name, options...)
+	if inst == nil {
+		return nil, err
+	}
+	return int64UpDownCounter{inst}, err
+}
+
+func (m contextMeter) Int64Histogram(name string, options ...metric.Int64HistogramOption) (metric.Int64Histogram, error) {
+	inst, err := m.Meter.Int64Histogram(name, options...)
+	if inst == nil {
+		return nil, err
+	}
+	return int64Histogram{inst}, err
+}
+
+func (m contextMeter) Int64Gauge(name string, options ...metric.Int64GaugeOption) (metric.Int64Gauge, error) {
+	inst, err := m.Meter.Int64Gauge(name, options...)
+	if inst == nil {
+		return nil, err
+	}
+	return int64Gauge{inst}, err
+}
+
+func (m contextMeter) Float64Counter(name string, options ...metric.Float64CounterOption) (metric.Float64Counter, error) {
+	inst, err := m.Meter.Float64Counter(name, options...)
+	if inst == nil {
+		return nil, err
+	}
+	return float64Counter{inst}, err
+}
+
+func (m contextMeter) Float64UpDownCounter(name string, options ...metric.Float64UpDownCounterOption) (metric.Float64UpDownCounter, error) {
+	inst, err := m.Meter.Float64UpDownCounter(name, options...)
+	if inst == nil {
+		return nil, err
+	}
+	return float64UpDownCounter{inst}, err
+}
+
+func (m contextMeter) Float64Histogram(name string, options ...metric.Float64HistogramOption) (metric.Float64Histogram, error) {
+	inst, err := m.Meter.Float64Histogram(name, options...)
+	if inst == nil {
+		return nil, err
+	}
+	return float64Histogram{inst}, err
+}
+
+func (m contextMeter) Float64Gauge(name string, options ...metric.Float64GaugeOption) (metric.Float64Gauge, error) {
+	inst, err := m.Meter.Float64Gauge(name, options...)
+	if inst == nil {
+		return nil, err
+	}
+	return float64Gauge{inst}, err
+}
+
+// addOptions puts the context attributes ahead of opts, so attributes passed to the call win.
+func addOptions(ctx context.Context, opts []metric.AddOption) []metric.AddOption {
+	attrs := AttributesFromContext(ctx)
+	if len(attrs) == 0 {
+		return opts
+	}
+	return append([]metric.AddOption{metric.WithAttributes(attrs...)}, opts...)
+}
+
+// recordOptions puts the context attributes ahead of opts, so attributes passed to the call win.
+func recordOptions(ctx context.Context, opts []metric.RecordOption) []metric.RecordOption {
+	attrs := AttributesFromContext(ctx)
+	if len(attrs) == 0 {
+		return opts
+	}
+	return append([]metric.RecordOption{metric.WithAttributes(attrs...)}, opts...)
+}
+
+type int64Counter struct{ metric.Int64Counter }
+
+func (c int64Counter) Add(ctx context.Context, incr int64, options ...metric.AddOption) {
+	c.Int64Counter.Add(ctx, incr, addOptions(ctx, options)...)
+}
+
+type int64UpDownCounter struct{ metric.Int64UpDownCounter }
+
+func (c int64UpDownCounter) Add(ctx context.Context, incr int64, options ...metric.AddOption) {
+	c.Int64UpDownCounter.Add(ctx, incr, addOptions(ctx, options)...)
+}
+
+type int64Histogram struct{ metric.Int64Histogram }
+
+func (h int64Histogram) Record(ctx context.Context, value int64, options ...metric.RecordOption) {
+	h.Int64Histogram.Record(ctx, value, recordOptions(ctx, options)...)
+}
+
+type int64Gauge struct{ metric.Int64Gauge }
+
+func (g int64Gauge) Record(ctx context.Context, value int64, options ...metric.RecordOption) {
+	g.Int64Gauge.Record(ctx, value, recordOptions(ctx, options)...)
+}
+
+type float64Counter struct{ metric.Float64Counter }
+
+func (c float64Counter) Add(ctx context.Context, incr float64, options ...metric.AddOption) {
+	c.Float64Counter.Add(ctx, incr, addOptions(ctx, options)...)
+}
+
+type float64UpDownCounter struct{ metric.Float64UpDownCounter }
+
+func (c float64UpDownCounter) Add(ctx context.Context, incr float64, options ...metric.AddOption) {
+	c.Float64UpDownCounter.Add(ctx, incr, addOptions(ctx, options)...)
+}
+
+type float64Histogram struct{ metric.Float64Histogram }
+
+func (h float64Histogram) Record(ctx context.Context, value float64, options ...metric.RecordOption) {
+	h.Float64Histogram.Record(ctx, value, recordOptions(ctx, options)...)
+}
+
+type float64Gauge struct{ metric.Float64Gauge }
+
+func (g float64Gauge) Record(ctx context.Context, value float64, options ...metric.RecordOption) {
+	g.Float64Gauge.Record(ctx, value, recordOptions(ctx, options)...)
+}
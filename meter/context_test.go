@@ -0,0 +1,80 @@
+package meter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mfahmialkautsar/goo11y/internal/testutil/inmemory"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestContextWithAttributesAccumulates(t *testing.T) {
+	ctx := ContextWithAttributes(context.Background(), attribute.String("route", "/a"))
+	ctx = ContextWithAttributes(ctx, attribute.String("tenant", "acme"))
+	ctx = ContextWithAttributes(ctx)
+
+	attrs := AttributesFromContext(ctx)
+	if len(attrs) != 2 || attrs[0].Key != "route" || attrs[1].Key != "tenant" {
+		t.Fatalf("unexpected context attributes %v", attrs)
+	}
+	if AttributesFromContext(context.Background()) != nil {
+		t.Fatal("expected no attributes on a bare context")
+	}
+}
+
+func TestContextMeterMergesContextAttributes(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	m := ContextMeter(provider.Meter("context"))
+	if ContextMeter(m) != m {
+		t.Fatal("expected wrapping twice to return the same meter")
+	}
+	counter, err := m.Int64Counter("requests")
+	if err != nil {
+		t.Fatalf("Int64Counter: %v", err)
+	}
+	histogram, err := m.Float64Histogram("latency")
+	if err != nil {
+		t.Fatalf("Float64Histogram: %v", err)
+	}
+
+	ctx := ContextWithAttributes(context.Background(), attribute.String("route", "/orders"), attribute.String("tenant", "acme"))
+	counter.Add(ctx, 1)
+	counter.Add(ctx, 1, metric.WithAttributes(attribute.String("tenant", "override")))
+	histogram.Record(ctx, 2.5)
+
+	rm, err := inmemory.GetMetrics(context.Background(), reader)
+	if err != nil {
+		t.Fatalf("GetMetrics: %v", err)
+	}
+	requests, ok := inmemory.FindMetricByName(rm, "requests")
+	if !ok {
+		t.Fatal("requests metric missing")
+	}
+	sum := requests.Data.(metricdata.Sum[int64])
+	tenants := map[string]int64{}
+	for _, dp := range sum.DataPoints {
+		if route, _ := dp.Attributes.Value("route"); route.AsString() != "/orders" {
+			t.Fatalf("expected route from context, got %v", dp.Attributes.ToSlice())
+		}
+		tenant, _ := dp.Attributes.Value("tenant")
+		tenants[tenant.AsString()] = dp.Value
+	}
+	if tenants["acme"] != 1 || tenants["override"] != 1 {
+		t.Fatalf("expected call attributes to win over context ones, got %v", tenants)
+	}
+
+	latency, ok := inmemory.FindMetricByName(rm, "latency")
+	if !ok {
+		t.Fatal("latency metric missing")
+	}
+	points := latency.Data.(metricdata.Histogram[float64]).DataPoints
+	if len(points) != 1 || points[0].Attributes.Len() != 2 {
+		t.Fatalf("expected histogram point with context attributes, got %+v", points)
+	}
+}
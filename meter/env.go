@@ -0,0 +1,68 @@
+package meter
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mfahmialkautsar/goo11y/internal/otlputil"
+)
+
+// Environment variables from the OpenTelemetry specification that Config falls back to when the
+// matching field is left zero. Values that cannot be parsed are ignored, as the specification requires.
+const (
+	// TemporalityPreferenceEnv holds "cumulative", "delta", or "lowmemory" and fills Temporality.
+	TemporalityPreferenceEnv = "OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE"
+	// ExportIntervalEnv holds milliseconds and fills ExportInterval.
+	ExportIntervalEnv = "OTEL_METRIC_EXPORT_INTERVAL"
+	// ExportTimeoutEnv holds milliseconds and fills ExportTimeout.
+	ExportTimeoutEnv = "OTEL_METRIC_EXPORT_TIMEOUT"
+)
+
+func (c *Config) applyEnv() {
+	env := otlputil.ReadEnv(otlputil.SignalMetrics)
+	if c.Endpoint == "" {
+		c.Endpoint = env.Endpoint
+		c.EndpointIsURL = c.EndpointIsURL || env.EndpointIsURL
+	}
+	if !c.Insecure {
+		c.Insecure = env.Insecure
+	}
+	c.Headers = otlputil.MergeHeaders(c.Headers, env.Headers)
+	if c.Protocol == "" {
+		c.Protocol = env.Protocol
+	}
+	if c.Certificate == "" {
+		c.Certificate = env.Certificate
+	}
+	if c.Timeout == 0 {
+		c.Timeout = env.Timeout
+	}
+	if c.Temporality == "" {
+		switch value := strings.ToLower(strings.TrimSpace(os.Getenv(TemporalityPreferenceEnv))); value {
+		case TemporalityCumulative, TemporalityDelta, TemporalityLowMemory:
+			c.Temporality = value
+		}
+	}
+	if c.ExportInterval == 0 {
+		c.ExportInterval = envMillis(ExportIntervalEnv)
+	}
+	if c.ExportTimeout == 0 {
+		c.ExportTimeout = envMillis(ExportTimeoutEnv)
+	}
+}
+
+// envMillis reads a positive millisecond count from name, or returns zero.
+func envMillis(name string) time.Duration {
+	ms, err := strconv.ParseInt(strings.TrimSpace(os.Getenv(name)), 10, 64)
+	if err != nil || ms <= 0 {
+		return 0
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// headerMap returns the headers sent to the collector, letting Credentials override Headers.
+func (c Config) headerMap() map[string]string {
+	return otlputil.MergeHeaders(c.Credentials.HeaderMap(), c.Headers)
+}
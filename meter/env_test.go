@@ -0,0 +1,80 @@
+package meter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/mfahmialkautsar/goo11y/auth"
+)
+
+func TestApplyDefaultsReadsEnv(t *testing.T) {
+	t.Setenv(TemporalityPreferenceEnv, "Delta")
+	t.Setenv(ExportIntervalEnv, "15000")
+	t.Setenv(ExportTimeoutEnv, "2500")
+
+	cfg := Config{}.ApplyDefaults()
+	if cfg.Temporality != TemporalityDelta {
+		t.Fatalf("expected delta temporality, got %q", cfg.Temporality)
+	}
+	if cfg.ExportInterval != 15*time.Second {
+		t.Fatalf("expected 15s interval, got %v", cfg.ExportInterval)
+	}
+	if cfg.ExportTimeout != 2500*time.Millisecond {
+		t.Fatalf("expected 2.5s timeout, got %v", cfg.ExportTimeout)
+	}
+}
+
+func TestApplyDefaultsPrefersConfigOverEnv(t *testing.T) {
+	t.Setenv(TemporalityPreferenceEnv, "delta")
+	t.Setenv(ExportIntervalEnv, "15000")
+
+	cfg := Config{Temporality: TemporalityCumulative, ExportInterval: time.Minute}.ApplyDefaults()
+	if cfg.Temporality != TemporalityCumulative || cfg.ExportInterval != time.Minute {
+		t.Fatalf("expected config values to win, got %q and %v", cfg.Temporality, cfg.ExportInterval)
+	}
+}
+
+func TestApplyDefaultsIgnoresInvalidEnv(t *testing.T) {
+	t.Setenv(TemporalityPreferenceEnv, "sometimes")
+	t.Setenv(ExportIntervalEnv, "soon")
+	t.Setenv(ExportTimeoutEnv, "-1")
+
+	cfg := Config{}.ApplyDefaults()
+	if cfg.Temporality != "" {
+		t.Fatalf("expected unknown temporality to be ignored, got %q", cfg.Temporality)
+	}
+	if cfg.ExportInterval != 10*time.Second || cfg.ExportTimeout != 30*time.Second {
+		t.Fatalf("expected defaults, got %v and %v", cfg.ExportInterval, cfg.ExportTimeout)
+	}
+}
+
+func TestApplyDefaultsReadsExporterEnv(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_METRICS_ENDPOINT", "https://collector:4318/v1/metrics")
+	t.Setenv("OTEL_EXPORTER_OTLP_PROTOCOL", "grpc")
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "x-tenant=env")
+
+	cfg := Config{Credentials: auth.Credentials{BearerToken: "token"}}.ApplyDefaults()
+	if cfg.Endpoint != "https://collector:4318/v1/metrics" || !cfg.EndpointIsURL || cfg.Protocol != "grpc" {
+		t.Fatalf("unexpected endpoint %q or protocol %q", cfg.Endpoint, cfg.Protocol)
+	}
+	headers := cfg.headerMap()
+	if headers["x-tenant"] != "env" || headers["Authorization"] != "Bearer token" {
+		t.Fatalf("unexpected headers %v", headers)
+	}
+}
+
+func TestApplyDefaultsSeparatesExportTimeouts(t *testing.T) {
+	cfg := Config{}.ApplyDefaults()
+	if cfg.Timeout != 10*time.Second || cfg.SpoolTimeout != cfg.Timeout {
+		t.Fatalf("expected 10s request and spool timeouts, got %v and %v", cfg.Timeout, cfg.SpoolTimeout)
+	}
+	if cfg.ExportInterval != 10*time.Second {
+		t.Fatalf("expected export interval untouched, got %v", cfg.ExportInterval)
+	}
+
+	t.Setenv("OTEL_EXPORTER_OTLP_METRICS_TIMEOUT", "2000")
+	cfg = Config{SpoolTimeout: time.Minute}.ApplyDefaults()
+	if cfg.Timeout != 2*time.Second || cfg.SpoolTimeout != time.Minute {
+		t.Fatalf("expected env request timeout and explicit spool timeout, got %v and %v", cfg.Timeout, cfg.SpoolTimeout)
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/mfahmialkautsar/goo11y/constant"
 	"github.com/mfahmialkautsar/goo11y/internal/otlputil"
 	"github.com/mfahmialkautsar/goo11y/internal/persistentgrpc"
 	"github.com/mfahmialkautsar/goo11y/internal/persistenthttp"
+	"github.com/mfahmialkautsar/goo11y/internal/spool"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
@@ -17,29 +19,60 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
+func newMetricsRequest() proto.Message {
+	return new(colmetric.ExportMetricsServiceRequest)
+}
+
 func setupHTTPExporter(ctx context.Context, cfg Config, endpoint otlputil.Endpoint) (sdkmetric.Exporter, *persistenthttp.Client, error) {
 	opts := []otlpmetrichttp.Option{
 		otlpmetrichttp.WithEndpoint(endpoint.Host),
-		otlpmetrichttp.WithURLPath(endpoint.PathWithSuffix("/v1/metrics")),
-		otlpmetrichttp.WithTimeout(cfg.ExportInterval),
+		otlpmetrichttp.WithURLPath(endpoint.ExportPath("/v1/metrics", cfg.EndpointIsURL)),
+		otlpmetrichttp.WithTimeout(cfg.Timeout),
+		otlpmetrichttp.WithTemporalitySelector(temporalitySelector(cfg.Temporality)),
 	}
 
+	tlsConfig, err := otlputil.TLSConfig(cfg.Certificate)
+	if err != nil {
+		return nil, nil, fmt.Errorf("meter: %w", err)
+	}
 	if endpoint.Insecure {
 		opts = append(opts, otlpmetrichttp.WithInsecure())
+	} else if tlsConfig != nil {
+		opts = append(opts, otlpmetrichttp.WithTLSClientConfig(tlsConfig))
 	}
 
-	if headers := cfg.Credentials.HeaderMap(); len(headers) > 0 {
+	if headers := cfg.headerMap(); len(headers) > 0 {
 		opts = append(opts, otlpmetrichttp.WithHeaders(headers))
 	}
 
 	var spoolClient *persistenthttp.Client
 	if cfg.UseSpool {
-		client, err := persistenthttp.NewClientWithComponent(cfg.QueueDir, cfg.ExportInterval, "meter")
+		clientOpts := []persistenthttp.Option{
+			persistenthttp.WithQueueOptions(spoolOptions(cfg)...),
+			persistenthttp.WithTLSConfig(tlsConfig),
+			persistenthttp.WithStatusPolicy(spool.ParseStatusPolicy(cfg.StatusPolicy)),
+		}
+		if cfg.Secondary.Endpoint != "" {
+			secondary, err := parseSecondary(cfg)
+			if err != nil {
+				return nil, nil, err
+			}
+			clientOpts = append(clientOpts, persistenthttp.WithSecondary(persistenthttp.Secondary{
+				URL:          secondary.URL("/v1/metrics"),
+				Headers:      cfg.Secondary.Credentials.HeaderMap(),
+				StripHeaders: headerNames(cfg.headerMap()),
+				Failover:     otlputil.NewFailover("meter", cfg.Protocol, cfg.Secondary.Threshold, cfg.Secondary.ProbeInterval),
+			}))
+		}
+		client, err := persistenthttp.NewClientWithComponent(cfg.QueueDir, cfg.SpoolTimeout, "meter", clientOpts...)
 		if err != nil {
 			return nil, nil, fmt.Errorf("create metric client: %w", err)
 		}
 		spoolClient = client
-		opts = append(opts, otlpmetrichttp.WithHTTPClient(client.Client))
+		opts = append(opts, otlpmetrichttp.WithHTTPClient(otlputil.SplitHTTPClient(client.Client, cfg.MaxPayloadBytes, newMetricsRequest)))
+	} else if cfg.MaxPayloadBytes > 0 {
+		client := otlputil.NewHTTPClient(cfg.Timeout, tlsConfig)
+		opts = append(opts, otlpmetrichttp.WithHTTPClient(otlputil.SplitHTTPClient(client, cfg.MaxPayloadBytes, newMetricsRequest)))
 	}
 	opts = append(opts, otlpmetrichttp.WithRetry(otlpmetrichttp.RetryConfig{Enabled: true}))
 
@@ -60,34 +93,81 @@ func setupGRPCExporter(ctx context.Context, cfg Config, endpoint otlputil.Endpoi
 
 	opts := []otlpmetricgrpc.Option{
 		otlpmetricgrpc.WithEndpoint(endpoint.HostWithPath()),
-		otlpmetricgrpc.WithTimeout(cfg.ExportInterval),
+		otlpmetricgrpc.WithTimeout(cfg.Timeout),
+		otlpmetricgrpc.WithTemporalitySelector(temporalitySelector(cfg.Temporality)),
 	}
 
+	tlsConfig, err := otlputil.TLSConfig(cfg.Certificate)
+	if err != nil {
+		return nil, fmt.Errorf("meter: %w", err)
+	}
+	var dialOpts []grpc.DialOption
 	if endpoint.Insecure {
 		opts = append(opts, otlpmetricgrpc.WithInsecure())
+	} else if tlsConfig != nil {
+		opts = append(opts, otlpmetricgrpc.WithTLSCredentials(credentials.NewTLS(tlsConfig)))
+		dialOpts = append(dialOpts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
 	} else {
 		opts = append(opts, otlpmetricgrpc.WithTLSCredentials(credentials.NewClientTLSFromCert(nil, "")))
 	}
 
-	if headers := cfg.Credentials.HeaderMap(); len(headers) > 0 {
+	if headers := cfg.headerMap(); len(headers) > 0 {
 		opts = append(opts, otlpmetricgrpc.WithHeaders(headers))
 	}
 
+	var interceptors []grpc.UnaryClientInterceptor
+	if cfg.MaxPayloadBytes > 0 {
+		interceptors = append(interceptors, otlputil.SplitInterceptor(cfg.MaxPayloadBytes))
+	}
 	var spoolManager *persistentgrpc.Manager
 	if cfg.UseSpool {
+		managerOpts := []persistentgrpc.Option{
+			persistentgrpc.WithEndpoint(endpoint, dialOpts...),
+			persistentgrpc.WithQueueOptions(spoolOptions(cfg)...),
+			persistentgrpc.WithTimeout(cfg.SpoolTimeout),
+			persistentgrpc.WithStatusPolicy(spool.ParseStatusPolicy(cfg.StatusPolicy)),
+		}
+		var secondaryConn *grpc.ClientConn
+		if cfg.Secondary.Endpoint != "" {
+			secondary, err := parseSecondary(cfg)
+			if err != nil {
+				return nil, err
+			}
+			if secondary.HasPath() {
+				return nil, fmt.Errorf("meter: grpc secondary endpoint %q must not include a path", cfg.Secondary.Endpoint)
+			}
+			conn, err := persistentgrpc.Dial(secondary)
+			if err != nil {
+				return nil, fmt.Errorf("meter: secondary: %w", err)
+			}
+			secondaryConn = conn
+			managerOpts = append(managerOpts, persistentgrpc.WithSecondary(persistentgrpc.Secondary{
+				Conn:         conn,
+				Headers:      cfg.Secondary.Credentials.HeaderMap(),
+				StripHeaders: headerNames(cfg.headerMap()),
+				Failover:     otlputil.NewFailover("meter", cfg.Protocol, cfg.Secondary.Threshold, cfg.Secondary.ProbeInterval),
+			}))
+		}
 		manager, err := persistentgrpc.NewManager(
 			cfg.QueueDir,
 			"meter",
 			cfg.Protocol,
 			"/opentelemetry.proto.collector.metrics.v1.MetricsService/Export",
-			func() proto.Message { return new(colmetric.ExportMetricsServiceRequest) },
+			newMetricsRequest,
 			func() proto.Message { return new(colmetric.ExportMetricsServiceResponse) },
+			managerOpts...,
 		)
 		if err != nil {
+			if secondaryConn != nil {
+				_ = secondaryConn.Close()
+			}
 			return nil, err
 		}
 		spoolManager = manager
-		opts = append(opts, otlpmetricgrpc.WithDialOption(grpc.WithUnaryInterceptor(manager.Interceptor())))
+		interceptors = append(interceptors, manager.Interceptor())
+	}
+	if len(interceptors) > 0 {
+		opts = append(opts, otlpmetricgrpc.WithDialOption(grpc.WithChainUnaryInterceptor(interceptors...)))
 	}
 
 	opts = append(opts, otlpmetricgrpc.WithRetry(otlpmetricgrpc.RetryConfig{Enabled: true}))
@@ -102,12 +182,106 @@ func setupGRPCExporter(ctx context.Context, cfg Config, endpoint otlputil.Endpoi
 	return wrapMetricExporter(exporter, "meter", cfg.Protocol, spoolManager, nil), nil
 }
 
+// Temporality values accepted by Config.Temporality.
+const (
+	TemporalityCumulative = "cumulative"
+	TemporalityDelta      = "delta"
+	TemporalityLowMemory  = "lowmemory"
+)
+
+// temporalitySelector maps a Config.Temporality value to the selector the OTLP exporters take.
+func temporalitySelector(name string) sdkmetric.TemporalitySelector {
+	switch name {
+	case TemporalityDelta:
+		return func(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+			if kind == sdkmetric.InstrumentKindUpDownCounter || kind == sdkmetric.InstrumentKindObservableUpDownCounter {
+				return metricdata.CumulativeTemporality
+			}
+			return metricdata.DeltaTemporality
+		}
+	case TemporalityLowMemory:
+		return func(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+			switch kind {
+			case sdkmetric.InstrumentKindCounter, sdkmetric.InstrumentKindHistogram:
+				return metricdata.DeltaTemporality
+			default:
+				return metricdata.CumulativeTemporality
+			}
+		}
+	default:
+		return sdkmetric.DefaultTemporalitySelector
+	}
+}
+
+func parseSecondary(cfg Config) (otlputil.Endpoint, error) {
+	endpoint, err := otlputil.ParseEndpoint(cfg.Secondary.Endpoint, cfg.Secondary.Insecure)
+	if err != nil {
+		return otlputil.Endpoint{}, fmt.Errorf("meter: secondary: %w", err)
+	}
+	return endpoint, nil
+}
+
+func headerNames(headers map[string]string) []string {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	return names
+}
+
+// newOTLPExporter builds the OTLP exporter for cfg, wrapped to log failures and count them in stats.
+func newOTLPExporter(ctx context.Context, cfg Config, endpoint otlputil.Endpoint, stats *exportStats) (sdkmetric.Exporter, error) {
+	var exporter sdkmetric.Exporter
+	var httpClient *persistenthttp.Client
+	var grpcManager *persistentgrpc.Manager
+	var err error
+
+	switch cfg.Protocol {
+	case constant.ProtocolGRPC:
+		exporter, err = setupGRPCExporter(ctx, cfg, endpoint)
+		if wrapper, ok := exporter.(*metricExporterWithLogging); ok {
+			grpcManager = wrapper.spool
+			exporter = wrapper.Exporter
+		}
+	case constant.ProtocolHTTP:
+		exporter, httpClient, err = setupHTTPExporter(ctx, cfg, endpoint)
+	default:
+		return nil, fmt.Errorf("meter: unsupported protocol %s", cfg.Protocol)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	exporter = wrapMetricExporter(exporter, "meter", cfg.Protocol, grpcManager, httpClient)
+	if wrapper, ok := exporter.(*metricExporterWithLogging); ok {
+		wrapper.stats = stats
+	}
+	return exporter, nil
+}
+
+// spoolCounters returns the queue depth and drop counters of the spool behind exporter, or nil
+// when it exports without one.
+func spoolCounters(exporter sdkmetric.Exporter) (func() int, func() uint64) {
+	wrapped, ok := exporter.(*metricExporterWithLogging)
+	if !ok {
+		return nil, nil
+	}
+	switch {
+	case wrapped.spool != nil:
+		return wrapped.spool.QueueDepth, wrapped.spool.QueueDropped
+	case wrapped.httpClient != nil:
+		return wrapped.httpClient.QueueDepth, wrapped.httpClient.QueueDropped
+	}
+	return nil, nil
+}
+
 type metricExporterWithLogging struct {
 	sdkmetric.Exporter
 	component  string
 	transport  string
 	spool      *persistentgrpc.Manager
 	httpClient *persistenthttp.Client
+	stats      *exportStats
 }
 
 func wrapMetricExporter(exp sdkmetric.Exporter, component, transport string, spool *persistentgrpc.Manager, httpClient *persistenthttp.Client) sdkmetric.Exporter {
@@ -139,6 +313,9 @@ func (m metricExporterWithLogging) Aggregation(kind sdkmetric.InstrumentKind) sd
 
 func (m metricExporterWithLogging) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
 	err := m.Exporter.Export(ctx, rm)
+	if m.stats != nil {
+		m.stats.record(err)
+	}
 	if err != nil {
 		otlputil.LogExportFailure(m.component, m.transport, err)
 	}
@@ -170,3 +347,17 @@ func (m metricExporterWithLogging) Shutdown(ctx context.Context) error {
 	}
 	return err
 }
+
+func spoolOptions(cfg Config) []spool.Option {
+	opts := []spool.Option{
+		spool.WithMaxAge(cfg.SpoolMaxAge),
+		spool.WithClock(cfg.Clock),
+		spool.WithPacing(cfg.MaxRequestsPerSecond, cfg.MaxBytesPerSecond),
+	}
+	if onDrop := cfg.OnSpoolDrop; onDrop != nil {
+		opts = append(opts, spool.WithDropFunc(func(token string, payload []byte, reason spool.DropReason) {
+			onDrop(token, payload, string(reason))
+		}))
+	}
+	return opts
+}
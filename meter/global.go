@@ -4,13 +4,12 @@ import (
 	"context"
 	"sync/atomic"
 
-	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
 )
 
 var globalProvider atomic.Value
-var disabledProvider = &Provider{}
+var disabledProvider = Nop()
 
 // Init configures the meter provider and stores it as the package-level singleton.
 func Init(ctx context.Context, cfg Config, res *resource.Resource, opts ...Option) error {
@@ -18,9 +17,6 @@ func Init(ctx context.Context, cfg Config, res *resource.Resource, opts ...Optio
 	if err != nil {
 		return err
 	}
-	if provider == nil {
-		provider = disabledProvider
-	}
 	Use(provider)
 	return nil
 }
@@ -50,10 +46,7 @@ func Global() *Provider {
 
 // Meter yields a metric meter backed by the global provider.
 func Meter(name string, opts ...metric.MeterOption) metric.Meter {
-	if provider := Global(); provider != nil && provider.provider != nil {
-		return provider.provider.Meter(name, opts...)
-	}
-	return otel.Meter(name, opts...)
+	return Global().Meter(name, opts...)
 }
 
 // RegisterRuntimeMetrics instruments runtime metrics using the global provider.
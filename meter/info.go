@@ -0,0 +1,130 @@
+package meter
+
+import (
+	"context"
+	"maps"
+	"runtime"
+	"runtime/debug"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// InfoConfig publishes application state as gauges, following the Prometheus *_build_info
+// pattern: the value is constant and the attributes carry the information, so dashboards can
+// group or join series by version, revision, configuration, or feature flag.
+type InfoConfig struct {
+	Enabled bool
+	// Version sets service.version on app.build.info; goo11y.New fills it from Resource.ServiceVersion.
+	Version string
+	// Revision sets vcs.revision on app.build.info. It defaults to the revision the binary was built
+	// from, when the Go toolchain recorded one.
+	Revision string
+	// Attributes are added to app.build.info next to service.version, vcs.revision, and go.version.
+	Attributes map[string]string
+	// ConfigHash, when set, is reported as config.hash on app.config.info, so a fleet running mixed
+	// configurations shows several series.
+	ConfigHash string
+	// FeatureFlags reports one app.feature_flag series per flag, keyed by feature_flag.key, with the
+	// value 1 when the flag is on and 0 when it is off.
+	FeatureFlags map[string]bool
+}
+
+// RegisterInfoMetrics adds the app.build.info, app.config.info, and app.feature_flag gauges if enabled.
+// The maps in cfg are copied, so later changes to them are not reported.
+func (p *Provider) RegisterInfoMetrics(_ context.Context, cfg InfoConfig) error {
+	if !cfg.Enabled || p.meter == nil {
+		return nil
+	}
+	return registerInfoInstruments(p.meter, cfg)
+}
+
+// RegisterInfoMetrics adds the info gauges using the global provider.
+func RegisterInfoMetrics(ctx context.Context, cfg InfoConfig) error {
+	return Global().RegisterInfoMetrics(ctx, cfg)
+}
+
+func registerInfoInstruments(m metric.Meter, cfg InfoConfig) error {
+	build := buildInfoAttributes(cfg)
+	_, err := m.Int64ObservableGauge(
+		"app.build.info",
+		metric.WithDescription("Build information of the running binary; the value is always 1"),
+		metric.WithInt64Callback(func(_ context.Context, observer metric.Int64Observer) error {
+			observer.Observe(1, metric.WithAttributeSet(build))
+			return nil
+		}),
+	)
+	if err != nil {
+		return err
+	}
+
+	if cfg.ConfigHash != "" {
+		config := attribute.NewSet(attribute.String("config.hash", cfg.ConfigHash))
+		_, err = m.Int64ObservableGauge(
+			"app.config.info",
+			metric.WithDescription("Hash of the loaded configuration; the value is always 1"),
+			metric.WithInt64Callback(func(_ context.Context, observer metric.Int64Observer) error {
+				observer.Observe(1, metric.WithAttributeSet(config))
+				return nil
+			}),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(cfg.FeatureFlags) > 0 {
+		flags := maps.Clone(cfg.FeatureFlags)
+		_, err = m.Int64ObservableGauge(
+			"app.feature_flag",
+			metric.WithDescription("Feature flag state, 1 when enabled and 0 when disabled"),
+			metric.WithInt64Callback(func(_ context.Context, observer metric.Int64Observer) error {
+				for key, enabled := range flags {
+					var value int64
+					if enabled {
+						value = 1
+					}
+					observer.Observe(value, metric.WithAttributes(attribute.String("feature_flag.key", key)))
+				}
+				return nil
+			}),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func buildInfoAttributes(cfg InfoConfig) attribute.Set {
+	attrs := make([]attribute.KeyValue, 0, len(cfg.Attributes)+3)
+	for key, value := range cfg.Attributes {
+		attrs = append(attrs, attribute.String(key, value))
+	}
+	attrs = append(attrs, attribute.String("go.version", runtime.Version()))
+	if cfg.Version != "" {
+		attrs = append(attrs, attribute.String("service.version", cfg.Version))
+	}
+	revision := cfg.Revision
+	if revision == "" {
+		revision = buildRevision()
+	}
+	if revision != "" {
+		attrs = append(attrs, attribute.String("vcs.revision", revision))
+	}
+	return attribute.NewSet(attrs...)
+}
+
+// buildRevision returns the VCS revision the Go toolchain stamped into the binary, if any.
+func buildRevision() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			return setting.Value
+		}
+	}
+	return ""
+}
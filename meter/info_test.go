@@ -0,0 +1,99 @@
+package meter
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/mfahmialkautsar/goo11y/internal/testutil/inmemory"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestRegisterInfoMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	sdkProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	t.Cleanup(func() { _ = sdkProvider.Shutdown(context.Background()) })
+	provider := NewProvider(sdkProvider)
+
+	flags := map[string]bool{"checkout_v2": true, "dark_mode": false}
+	err := provider.RegisterInfoMetrics(context.Background(), InfoConfig{
+		Enabled:      true,
+		Version:      "1.4.0",
+		Revision:     "abc123",
+		Attributes:   map[string]string{"build.branch": "main"},
+		ConfigHash:   "sha256:ff00",
+		FeatureFlags: flags,
+	})
+	if err != nil {
+		t.Fatalf("RegisterInfoMetrics: %v", err)
+	}
+	flags["late_flag"] = true
+
+	rm, err := inmemory.GetMetrics(context.Background(), reader)
+	if err != nil {
+		t.Fatalf("GetMetrics: %v", err)
+	}
+
+	build, ok := inmemory.FindMetricByName(rm, "app.build.info")
+	if !ok {
+		t.Fatal("app.build.info missing")
+	}
+	points := build.Data.(metricdata.Gauge[int64]).DataPoints
+	if len(points) != 1 || points[0].Value != 1 {
+		t.Fatalf("unexpected build info points %+v", points)
+	}
+	want := map[string]string{
+		"service.version": "1.4.0",
+		"vcs.revision":    "abc123",
+		"go.version":      runtime.Version(),
+		"build.branch":    "main",
+	}
+	for key, value := range want {
+		if got, _ := points[0].Attributes.Value(attribute.Key(key)); got.AsString() != value {
+			t.Fatalf("build info %s = %q, want %q", key, got.AsString(), value)
+		}
+	}
+
+	config, ok := inmemory.FindMetricByName(rm, "app.config.info")
+	if !ok {
+		t.Fatal("app.config.info missing")
+	}
+	if hash, _ := config.Data.(metricdata.Gauge[int64]).DataPoints[0].Attributes.Value("config.hash"); hash.AsString() != "sha256:ff00" {
+		t.Fatalf("unexpected config hash %q", hash.AsString())
+	}
+
+	featureFlags, ok := inmemory.FindMetricByName(rm, "app.feature_flag")
+	if !ok {
+		t.Fatal("app.feature_flag missing")
+	}
+	states := map[string]int64{}
+	for _, dp := range featureFlags.Data.(metricdata.Gauge[int64]).DataPoints {
+		key, _ := dp.Attributes.Value("feature_flag.key")
+		states[key.AsString()] = dp.Value
+	}
+	if len(states) != 2 || states["checkout_v2"] != 1 || states["dark_mode"] != 0 {
+		t.Fatalf("unexpected feature flag states %v", states)
+	}
+}
+
+func TestRegisterInfoMetricsDisabled(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	sdkProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	t.Cleanup(func() { _ = sdkProvider.Shutdown(context.Background()) })
+
+	if err := NewProvider(sdkProvider).RegisterInfoMetrics(context.Background(), InfoConfig{Version: "1.0.0"}); err != nil {
+		t.Fatalf("RegisterInfoMetrics: %v", err)
+	}
+	if err := Nop().RegisterInfoMetrics(context.Background(), InfoConfig{Enabled: true}); err != nil {
+		t.Fatalf("RegisterInfoMetrics on nop provider: %v", err)
+	}
+	rm, err := inmemory.GetMetrics(context.Background(), reader)
+	if err != nil {
+		t.Fatalf("GetMetrics: %v", err)
+	}
+	if _, ok := inmemory.FindMetricByName(rm, "app.build.info"); ok {
+		t.Fatal("expected no info metrics when disabled")
+	}
+}
@@ -0,0 +1,227 @@
+package meter
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// InstrumentConflict describes two instruments created under the same name in one meter with a
+// different kind, unit, or description. The SDK keeps both and exports them as separate streams,
+// which backends usually reject or merge wrongly.
+type InstrumentConflict struct {
+	// Scope is the name of the meter both instruments were created from.
+	Scope    string
+	Name     string
+	First    InstrumentSignature
+	Conflict InstrumentSignature
+}
+
+// InstrumentSignature is what an instrument was created with and where.
+type InstrumentSignature struct {
+	Kind        string
+	Unit        string
+	Description string
+	// CallSite is the file:line of the first caller outside this package and the OpenTelemetry API.
+	CallSite string
+}
+
+func (c InstrumentConflict) Error() string {
+	return fmt.Sprintf("meter: instrument %q of meter %q created as %s at %s conflicts with %s at %s",
+		c.Name, c.Scope, c.Conflict.describe(), c.Conflict.CallSite, c.First.describe(), c.First.CallSite)
+}
+
+func (s InstrumentSignature) describe() string {
+	return fmt.Sprintf("%s(unit=%q, description=%q)", s.Kind, s.Unit, s.Description)
+}
+
+// instrumentRegistry remembers the first signature of every instrument name per meter and reports
+// each distinct conflicting signature once. Creating the same instrument again is not a conflict.
+type instrumentRegistry struct {
+	onConflict func(InstrumentConflict)
+
+	mu        sync.Mutex
+	first     map[string]InstrumentSignature
+	seen      map[string]bool
+	conflicts []InstrumentConflict
+}
+
+func newInstrumentRegistry(onConflict func(InstrumentConflict)) *instrumentRegistry {
+	return &instrumentRegistry{
+		onConflict: onConflict,
+		first:      make(map[string]InstrumentSignature),
+		seen:       make(map[string]bool),
+	}
+}
+
+func (r *instrumentRegistry) check(scope, name, kind, unit, description string) {
+	// Instrument names are case-insensitive.
+	key := scope + "\x00" + strings.ToLower(name)
+	sig := InstrumentSignature{Kind: kind, Unit: unit, Description: description}
+
+	r.mu.Lock()
+	first, ok := r.first[key]
+	if !ok {
+		sig.CallSite = instrumentCallSite()
+		r.first[key] = sig
+		r.mu.Unlock()
+		return
+	}
+	if first.Kind == kind && first.Unit == unit && first.Description == description {
+		r.mu.Unlock()
+		return
+	}
+	conflictKey := key + "\x00" + sig.describe()
+	if r.seen[conflictKey] {
+		r.mu.Unlock()
+		return
+	}
+	r.seen[conflictKey] = true
+	sig.CallSite = instrumentCallSite()
+	conflict := InstrumentConflict{Scope: scope, Name: name, First: first, Conflict: sig}
+	r.conflicts = append(r.conflicts, conflict)
+	r.mu.Unlock()
+
+	if r.onConflict != nil {
+		r.onConflict(conflict)
+		return
+	}
+	otel.Handle(conflict)
+}
+
+func (r *instrumentRegistry) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.conflicts)
+}
+
+func (r *instrumentRegistry) snapshot() []InstrumentConflict {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.conflicts) == 0 {
+		return nil
+	}
+	return append([]InstrumentConflict(nil), r.conflicts...)
+}
+
+// InstrumentConflicts returns the conflicting instrument definitions seen so far, oldest first.
+// Returns nil if provider is disabled.
+func (p *Provider) InstrumentConflicts() []InstrumentConflict {
+	if p == nil || p.registry == nil {
+		return nil
+	}
+	return p.registry.snapshot()
+}
+
+// instrumentCallSite returns the file:line of the first frame outside this package and the
+// OpenTelemetry modules. Tests of this package count as callers.
+func instrumentCallSite() string {
+	var pcs [32]uintptr
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs[:])])
+	for {
+		frame, more := frames.Next()
+		internal := strings.HasPrefix(frame.Function, "github.com/mfahmialkautsar/goo11y/meter.") &&
+			!strings.HasSuffix(frame.File, "_test.go")
+		if !internal && !strings.HasPrefix(frame.Function, "go.opentelemetry.io/") {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+// registryMeter checks every instrument it creates against the registry before delegating.
+type registryMeter struct {
+	metric.Meter
+	scope    string
+	registry *instrumentRegistry
+}
+
+func (m registryMeter) Int64Counter(name string, options ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	cfg := metric.NewInt64CounterConfig(options...)
+	m.registry.check(m.scope, name, "Int64Counter", cfg.Unit(), cfg.Description())
+	return m.Meter.Int64Counter(name, options...)
+}
+
+func (m registryMeter) Int64UpDownCounter(name string, options ...metric.Int64UpDownCounterOption) (metric.Int64UpDownCounter, error) {
+	cfg := metric.NewInt64UpDownCounterConfig(options...)
+	m.registry.check(m.scope, name, "Int64UpDownCounter", cfg.Unit(), cfg.Description())
+	return m.Meter.Int64UpDownCounter(name, options...)
+}
+
+func (m registryMeter) Int64Histogram(name string, options ...metric.Int64HistogramOption) (metric.Int64Histogram, error) {
+	cfg := metric.NewInt64HistogramConfig(options...)
+	m.registry.check(m.scope, name, "Int64Histogram", cfg.Unit(), cfg.Description())
+	return m.Meter.Int64Histogram(name, options...)
+}
+
+func (m registryMeter) Int64Gauge(name string, options ...metric.Int64GaugeOption) (metric.Int64Gauge, error) {
+	cfg := metric.NewInt64GaugeConfig(options...)
+	m.registry.check(m.scope, name, "Int64Gauge", cfg.Unit(), cfg.Description())
+	return m.Meter.Int64Gauge(name, options...)
+}
+
+func (m registryMeter) Int64ObservableCounter(name string, options ...metric.Int64ObservableCounterOption) (metric.Int64ObservableCounter, error) {
+	cfg := metric.NewInt64ObservableCounterConfig(options...)
+	m.registry.check(m.scope, name, "Int64ObservableCounter", cfg.Unit(), cfg.Description())
+	return m.Meter.Int64ObservableCounter(name, options...)
+}
+
+func (m registryMeter) Int64ObservableUpDownCounter(name string, options ...metric.Int64ObservableUpDownCounterOption) (metric.Int64ObservableUpDownCounter, error) {
+	cfg := metric.NewInt64ObservableUpDownCounterConfig(options...)
+	m.registry.check(m.scope, name, "Int64ObservableUpDownCounter", cfg.Unit(), cfg.Description())
+	return m.Meter.Int64ObservableUpDownCounter(name, options...)
+}
+
+func (m registryMeter) Int64ObservableGauge(name string, options ...metric.Int64ObservableGaugeOption) (metric.Int64ObservableGauge, error) {
+	cfg := metric.NewInt64ObservableGaugeConfig(options...)
+	m.registry.check(m.scope, name, "Int64ObservableGauge", cfg.Unit(), cfg.Description())
+	return m.Meter.Int64ObservableGauge(name, options...)
+}
+
+func (m registryMeter) Float64Counter(name string, options ...metric.Float64CounterOption) (metric.Float64Counter, error) {
+	cfg := metric.NewFloat64CounterConfig(options...)
+	m.registry.check(m.scope, name, "Float64Counter", cfg.Unit(), cfg.Description())
+	return m.Meter.Float64Counter(name, options...)
+}
+
+func (m registryMeter) Float64UpDownCounter(name string, options ...metric.Float64UpDownCounterOption) (metric.Float64UpDownCounter, error) {
+	cfg := metric.NewFloat64UpDownCounterConfig(options...)
+	m.registry.check(m.scope, name, "Float64UpDownCounter", cfg.Unit(), cfg.Description())
+	return m.Meter.Float64UpDownCounter(name, options...)
+}
+
+func (m registryMeter) Float64Histogram(name string, options ...metric.Float64HistogramOption) (metric.Float64Histogram, error) {
+	cfg := metric.NewFloat64HistogramConfig(options...)
+	m.registry.check(m.scope, name, "Float64Histogram", cfg.Unit(), cfg.Description())
+	return m.Meter.Float64Histogram(name, options...)
+}
+
+func (m registryMeter) Float64Gauge(name string, options ...metric.Float64GaugeOption) (metric.Float64Gauge, error) {
+	cfg := metric.NewFloat64GaugeConfig(options...)
+	m.registry.check(m.scope, name, "Float64Gauge", cfg.Unit(), cfg.Description())
+	return m.Meter.Float64Gauge(name, options...)
+}
+
+func (m registryMeter) Float64ObservableCounter(name string, options ...metric.Float64ObservableCounterOption) (metric.Float64ObservableCounter, error) {
+	cfg := metric.NewFloat64ObservableCounterConfig(options...)
+	m.registry.check(m.scope, name, "Float64ObservableCounter", cfg.Unit(), cfg.Description())
+	return m.Meter.Float64ObservableCounter(name, options...)
+}
+
+func (m registryMeter) Float64ObservableUpDownCounter(name string, options ...metric.Float64ObservableUpDownCounterOption) (metric.Float64ObservableUpDownCounter, error) {
+	cfg := metric.NewFloat64ObservableUpDownCounterConfig(options...)
+	m.registry.check(m.scope, name, "Float64ObservableUpDownCounter", cfg.Unit(), cfg.Description())
+	return m.Meter.Float64ObservableUpDownCounter(name, options...)
+}
+
+func (m registryMeter) Float64ObservableGauge(name string, options ...metric.Float64ObservableGaugeOption) (metric.Float64ObservableGauge, error) {
+	cfg := metric.NewFloat64ObservableGaugeConfig(options...)
+	m.registry.check(m.scope, name, "Float64ObservableGauge", cfg.Unit(), cfg.Description())
+	return m.Meter.Float64ObservableGauge(name, options...)
+}
@@ -0,0 +1,79 @@
+package meter
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+func TestProviderReportsConflictingInstruments(t *testing.T) {
+	var reported []InstrumentConflict
+	provider, err := Setup(context.Background(), Config{
+		Enabled:              true,
+		Endpoint:             "localhost:4318",
+		OnInstrumentConflict: func(c InstrumentConflict) { reported = append(reported, c) },
+	}, resource.Empty(), WithMetricReader(sdkmetric.NewManualReader()))
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	m := provider.Meter("checkout")
+	create := func(unit string) {
+		if _, err := m.Int64Counter("orders", metric.WithUnit(unit), metric.WithDescription("Orders placed")); err != nil {
+			t.Fatalf("Int64Counter: %v", err)
+		}
+	}
+	create("1")
+	create("1")
+	if len(reported) != 0 {
+		t.Fatalf("expected identical instruments to be deduplicated, got %+v", reported)
+	}
+	create("{order}")
+	create("{order}")
+	if _, err := provider.Meter("payments").Int64Counter("orders", metric.WithUnit("ms")); err != nil {
+		t.Fatalf("Int64Counter: %v", err)
+	}
+
+	if len(reported) != 1 {
+		t.Fatalf("expected one conflict, got %+v", reported)
+	}
+	conflict := reported[0]
+	if conflict.Scope != "checkout" || conflict.Name != "orders" || conflict.First.Unit != "1" || conflict.Conflict.Unit != "{order}" {
+		t.Fatalf("unexpected conflict: %+v", conflict)
+	}
+	for _, site := range []string{conflict.First.CallSite, conflict.Conflict.CallSite} {
+		if !strings.Contains(site, "instrument_registry_test.go:") {
+			t.Fatalf("expected the call site in this test, got %q", site)
+		}
+	}
+	if stats := provider.Stats(); stats.InstrumentConflicts != 1 {
+		t.Fatalf("expected the conflict counted in Stats, got %d", stats.InstrumentConflicts)
+	}
+	if listed := provider.InstrumentConflicts(); len(listed) != 1 || listed[0] != conflict {
+		t.Fatalf("expected the conflict listed, got %+v", listed)
+	}
+}
+
+func TestProviderReportsInstrumentKindConflicts(t *testing.T) {
+	sdkProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(sdkmetric.NewManualReader()))
+	t.Cleanup(func() { _ = sdkProvider.Shutdown(context.Background()) })
+	provider := NewProvider(sdkProvider)
+
+	m := provider.Meter("jobs")
+	if _, err := m.Int64Counter("Queue.Depth"); err != nil {
+		t.Fatalf("Int64Counter: %v", err)
+	}
+	if _, err := m.Float64Gauge("queue.depth"); err != nil {
+		t.Fatalf("Float64Gauge: %v", err)
+	}
+
+	conflicts := provider.InstrumentConflicts()
+	if len(conflicts) != 1 || conflicts[0].First.Kind != "Int64Counter" || conflicts[0].Conflict.Kind != "Float64Gauge" {
+		t.Fatalf("expected a kind conflict, got %+v", conflicts)
+	}
+}
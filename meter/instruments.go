@@ -0,0 +1,116 @@
+package meter
+
+import (
+	"fmt"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// instrumentCache holds the instruments created through the Provider helpers, keyed by kind and name.
+type instrumentCache struct {
+	mu          sync.Mutex
+	instruments map[string]any
+}
+
+// cachedInstrument returns the instrument cached under kind and name, creating it with create on
+// first use. A creation error is reported once through otel.Handle; the instrument returned with it
+// is still cached when non-nil, as the SDK returns a working one for names it only warns about, and
+// fallback is cached otherwise.
+func cachedInstrument[T any](p *Provider, kind, name string, create func(metric.Meter) (T, error), fallback T) T {
+	if p == nil {
+		p = disabledProvider
+	}
+	cache := &p.cache
+	key := kind + "/" + name
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if inst, ok := cache.instruments[key].(T); ok {
+		return inst
+	}
+	inst, err := create(ContextMeter(p.defaultMeter()))
+	if err != nil {
+		otel.Handle(fmt.Errorf("meter: create %s %q: %w", kind, name, err))
+		if any(inst) == nil {
+			inst = fallback
+		}
+	}
+	if cache.instruments == nil {
+		cache.instruments = make(map[string]any)
+	}
+	cache.instruments[key] = inst
+	return inst
+}
+
+// defaultMeter is the meter the Provider helpers create instruments from.
+func (p *Provider) defaultMeter() metric.Meter {
+	if p.meter == nil {
+		return p.Meter("")
+	}
+	return p.meter
+}
+
+// Counter returns the int64 counter called name, creating it on first use and returning the same
+// instrument afterwards; opts only apply to the first call. A creation error is reported once
+// through otel.Handle; if the meter returned no instrument with it, a no-op counter is used, so the
+// result is never nil and the call never panics. Like every helper below, the instrument adds the attributes of ContextWithAttributes.
+func (p *Provider) Counter(name string, opts ...metric.Int64CounterOption) metric.Int64Counter {
+	return cachedInstrument(p, "counter", name, func(m metric.Meter) (metric.Int64Counter, error) {
+		return m.Int64Counter(name, opts...)
+	}, metric.Int64Counter(noop.Int64Counter{}))
+}
+
+// FloatCounter returns the float64 counter called name, cached like Counter.
+func (p *Provider) FloatCounter(name string, opts ...metric.Float64CounterOption) metric.Float64Counter {
+	return cachedInstrument(p, "float_counter", name, func(m metric.Meter) (metric.Float64Counter, error) {
+		return m.Float64Counter(name, opts...)
+	}, metric.Float64Counter(noop.Float64Counter{}))
+}
+
+// UpDownCounter returns the int64 up-down counter called name, cached like Counter.
+func (p *Provider) UpDownCounter(name string, opts ...metric.Int64UpDownCounterOption) metric.Int64UpDownCounter {
+	return cachedInstrument(p, "updown_counter", name, func(m metric.Meter) (metric.Int64UpDownCounter, error) {
+		return m.Int64UpDownCounter(name, opts...)
+	}, metric.Int64UpDownCounter(noop.Int64UpDownCounter{}))
+}
+
+// Histogram returns the float64 histogram called name, cached like Counter.
+func (p *Provider) Histogram(name string, opts ...metric.Float64HistogramOption) metric.Float64Histogram {
+	return cachedInstrument(p, "histogram", name, func(m metric.Meter) (metric.Float64Histogram, error) {
+		return m.Float64Histogram(name, opts...)
+	}, metric.Float64Histogram(noop.Float64Histogram{}))
+}
+
+// Gauge returns the float64 gauge called name, cached like Counter.
+func (p *Provider) Gauge(name string, opts ...metric.Float64GaugeOption) metric.Float64Gauge {
+	return cachedInstrument(p, "gauge", name, func(m metric.Meter) (metric.Float64Gauge, error) {
+		return m.Float64Gauge(name, opts...)
+	}, metric.Float64Gauge(noop.Float64Gauge{}))
+}
+
+// Counter returns the global provider's counter called name; see Provider.Counter.
+func Counter(name string, opts ...metric.Int64CounterOption) metric.Int64Counter {
+	return Global().Counter(name, opts...)
+}
+
+// FloatCounter returns the global provider's float64 counter called name; see Provider.Counter.
+func FloatCounter(name string, opts ...metric.Float64CounterOption) metric.Float64Counter {
+	return Global().FloatCounter(name, opts...)
+}
+
+// UpDownCounter returns the global provider's up-down counter called name; see Provider.Counter.
+func UpDownCounter(name string, opts ...metric.Int64UpDownCounterOption) metric.Int64UpDownCounter {
+	return Global().UpDownCounter(name, opts...)
+}
+
+// Histogram returns the global provider's histogram called name; see Provider.Counter.
+func Histogram(name string, opts ...metric.Float64HistogramOption) metric.Float64Histogram {
+	return Global().Histogram(name, opts...)
+}
+
+// Gauge returns the global provider's gauge called name; see Provider.Counter.
+func Gauge(name string, opts ...metric.Float64GaugeOption) metric.Float64Gauge {
+	return Global().Gauge(name, opts...)
+}
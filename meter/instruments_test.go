@@ -0,0 +1,91 @@
+package meter
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/mfahmialkautsar/goo11y/internal/testutil/inmemory"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+type errorRecorder []error
+
+func (r *errorRecorder) Handle(err error) { *r = append(*r, err) }
+
+func TestProviderInstrumentHelpersCacheByName(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	sdkProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	t.Cleanup(func() { _ = sdkProvider.Shutdown(context.Background()) })
+	provider := NewProvider(sdkProvider)
+
+	counter := provider.Counter("orders")
+	if provider.Counter("orders") != counter {
+		t.Fatal("expected the cached counter for the same name")
+	}
+	ctx := ContextWithAttributes(context.Background(), attribute.String("tenant", "acme"))
+	counter.Add(ctx, 2)
+	provider.Histogram("latency").Record(ctx, 1.5)
+
+	rm, err := inmemory.GetMetrics(context.Background(), reader)
+	if err != nil {
+		t.Fatalf("GetMetrics: %v", err)
+	}
+	for _, name := range []string{"orders", "latency"} {
+		if _, ok := inmemory.FindMetricByName(rm, name); !ok {
+			t.Fatalf("metric %s missing", name)
+		}
+	}
+}
+
+func TestProviderInstrumentHelpersKeepInstrumentReturnedWithError(t *testing.T) {
+	previous := otel.GetErrorHandler()
+	var errs errorRecorder
+	otel.SetErrorHandler(&errs)
+	t.Cleanup(func() { otel.SetErrorHandler(previous) })
+
+	sdkProvider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(sdkmetric.NewManualReader()))
+	t.Cleanup(func() { _ = sdkProvider.Shutdown(context.Background()) })
+	provider := NewProvider(sdkProvider)
+
+	for range 3 {
+		counter := provider.Counter("1invalid name")
+		if _, ok := counter.(noop.Int64Counter); ok {
+			t.Fatal("expected the SDK counter returned with the error, got a no-op one")
+		}
+		counter.Add(context.Background(), 1)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("expected the creation error reported once, got %v", errs)
+	}
+}
+
+func TestProviderInstrumentHelpersFallBackToNoop(t *testing.T) {
+	previous := otel.GetErrorHandler()
+	var errs errorRecorder
+	otel.SetErrorHandler(&errs)
+	t.Cleanup(func() { otel.SetErrorHandler(previous) })
+
+	failed := cachedInstrument(NewProvider(nil), "counter", "orders", func(metric.Meter) (metric.Int64Counter, error) {
+		return nil, errors.New("no instrument")
+	}, metric.Int64Counter(noop.Int64Counter{}))
+	if _, ok := failed.(noop.Int64Counter); !ok {
+		t.Fatalf("expected a no-op counter when none is returned, got %T", failed)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("expected the creation error reported, got %v", errs)
+	}
+}
+
+func TestGlobalHelpersUseGlobalProvider(t *testing.T) {
+	Use(nil)
+	if Counter("calls") == nil || Gauge("temperature") == nil || UpDownCounter("inflight") == nil {
+		t.Fatal("expected instruments from the disabled global provider")
+	}
+	Histogram("latency").Record(context.Background(), 1)
+	FloatCounter("bytes").Add(context.Background(), 1)
+}
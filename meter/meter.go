@@ -5,10 +5,7 @@ import (
 	"errors"
 	"fmt"
 
-	"github.com/mfahmialkautsar/goo11y/constant"
 	"github.com/mfahmialkautsar/goo11y/internal/otlputil"
-	"github.com/mfahmialkautsar/goo11y/internal/persistentgrpc"
-	"github.com/mfahmialkautsar/goo11y/internal/persistenthttp"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/metric"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
@@ -17,21 +14,35 @@ import (
 
 // Provider wraps the SDK meter provider.
 type Provider struct {
-	provider *sdkmetric.MeterProvider
-	meter    metric.Meter
-	flush    func(context.Context) error
+	provider   *sdkmetric.MeterProvider
+	meter      metric.Meter
+	flush      func(context.Context) error
+	stats      *exportStats
+	spoolDepth func() int
+	spoolDrops func() uint64
+	guard      *cardinalityGuard
+	cache      instrumentCache
+	registry   *instrumentRegistry
 }
 
 // NewProvider creates a new Provider wrapping the given SDK provider.
 // This is primarily used for testing.
 func NewProvider(p *sdkmetric.MeterProvider) *Provider {
-	return &Provider{
+	provider := &Provider{
 		provider: p,
-		meter:    p.Meter(""),
 		flush: func(ctx context.Context) error {
 			return p.ForceFlush(ctx)
 		},
+		registry: newInstrumentRegistry(nil),
 	}
+	provider.meter = provider.Meter("")
+	return provider
+}
+
+// Nop returns a disabled provider that records nothing and whose Meter falls back to the
+// OpenTelemetry global. Setup returns it when metrics are disabled.
+func Nop() *Provider {
+	return &Provider{}
 }
 
 // Option configures the meter provider.
@@ -54,7 +65,7 @@ func Setup(ctx context.Context, cfg Config, res *resource.Resource, opts ...Opti
 	cfg = cfg.ApplyDefaults()
 
 	if !cfg.Enabled {
-		return nil, nil
+		return Nop(), nil
 	}
 
 	if err := cfg.Validate(); err != nil {
@@ -67,7 +78,10 @@ func Setup(ctx context.Context, cfg Config, res *resource.Resource, opts ...Opti
 	}
 
 	var (
-		reader sdkmetric.Reader
+		reader     sdkmetric.Reader
+		stats      *exportStats
+		spoolDepth func() int
+		spoolDrops func() uint64
 	)
 
 	if c.reader != nil {
@@ -80,53 +94,108 @@ func Setup(ctx context.Context, cfg Config, res *resource.Resource, opts ...Opti
 		if err != nil {
 			return nil, fmt.Errorf("meter: %w", err)
 		}
+		if cfg.Secondary.Endpoint != "" && !cfg.UseSpool {
+			return nil, fmt.Errorf("meter: secondary endpoint requires UseSpool")
+		}
 
+		stats = &exportStats{}
 		var exporter sdkmetric.Exporter
-		var httpClient *persistenthttp.Client
-		var grpcManager *persistentgrpc.Manager
-
-		switch cfg.Protocol {
-		case constant.ProtocolGRPC:
-			exporter, err = setupGRPCExporter(ctx, cfg, endpoint)
-			if wrapper, ok := exporter.(metricExporterWithLogging); ok {
-				grpcManager = wrapper.spool
+		if cfg.AsyncInit {
+			buildCtx := context.WithoutCancel(ctx)
+			lazy := otlputil.NewLazy("meter", func() (sdkmetric.Exporter, error) {
+				return newOTLPExporter(buildCtx, cfg, endpoint, stats)
+			})
+			exporter = lazyMetricExporter{exporter: lazy, temporality: temporalitySelector(cfg.Temporality)}
+			spoolDepth = func() int {
+				if built, ok := lazy.Get(); ok {
+					if depth, _ := spoolCounters(built); depth != nil {
+						return depth()
+					}
+				}
+				return 0
 			}
-		case constant.ProtocolHTTP:
-			var httpSpool *persistenthttp.Client
-			exporter, httpSpool, err = setupHTTPExporter(ctx, cfg, endpoint)
-			httpClient = httpSpool
-		default:
-			return nil, fmt.Errorf("meter: unsupported protocol %s", cfg.Protocol)
-		}
-
-		if err != nil {
-			return nil, err
+			spoolDrops = func() uint64 {
+				if built, ok := lazy.Get(); ok {
+					if _, drops := spoolCounters(built); drops != nil {
+						return drops()
+					}
+				}
+				return 0
+			}
+		} else {
+			if exporter, err = newOTLPExporter(ctx, cfg, endpoint, stats); err != nil {
+				return nil, err
+			}
+			spoolDepth, spoolDrops = spoolCounters(exporter)
 		}
 
-		exporter = wrapMetricExporter(exporter, "meter", cfg.Protocol, grpcManager, httpClient)
-
-		reader = sdkmetric.NewPeriodicReader(
-			exporter,
-			sdkmetric.WithInterval(cfg.ExportInterval),
-		)
+		reader = newExportReader(exporter, cfg)
 	}
 
-	provider := sdkmetric.NewMeterProvider(
+	providerOpts := []sdkmetric.Option{
 		sdkmetric.WithReader(reader),
 		sdkmetric.WithResource(res),
-	)
+	}
+	guard := newCardinalityGuard(cfg.Cardinality)
+	if views := configuredViews(cfg, guard); len(views) > 0 {
+		providerOpts = append(providerOpts, sdkmetric.WithView(views...))
+	}
+	if cfg.Cardinality.MaxSeries > 0 {
+		providerOpts = append(providerOpts, sdkmetric.WithCardinalityLimit(cfg.Cardinality.MaxSeries))
+	}
+	provider := sdkmetric.NewMeterProvider(providerOpts...)
+	if guard != nil {
+		if err := guard.register(provider.Meter(guardInstrumentation)); err != nil {
+			_ = provider.Shutdown(context.Background())
+			return nil, fmt.Errorf("meter: register cardinality guard: %w", err)
+		}
+	}
+
+	if !cfg.DisableUptime {
+		if err := registerUptimeInstruments(provider.Meter(uptimeInstrumentation)); err != nil {
+			_ = provider.Shutdown(context.Background())
+			return nil, fmt.Errorf("meter: register uptime metrics: %w", err)
+		}
+	}
 
 	flush := func(ctx context.Context) error {
 		return provider.ForceFlush(ctx)
 	}
 
-	otel.SetMeterProvider(provider)
+	if guard.remaps() {
+		otel.SetMeterProvider(guardedProvider{provider: provider, guard: guard})
+	} else {
+		otel.SetMeterProvider(provider)
+	}
 
-	return &Provider{
-		provider: provider,
-		meter:    provider.Meter(cfg.ServiceName),
-		flush:    flush,
-	}, nil
+	p := &Provider{
+		provider:   provider,
+		flush:      flush,
+		stats:      stats,
+		spoolDepth: spoolDepth,
+		spoolDrops: spoolDrops,
+		guard:      guard,
+		registry:   newInstrumentRegistry(cfg.OnInstrumentConflict),
+	}
+	p.meter = p.Meter(cfg.ServiceName)
+	return p, nil
+}
+
+// Meter yields a metric meter backed by this provider. Instruments created from it are checked for
+// conflicting redefinitions; see Config.OnInstrumentConflict.
+// Falls back to the OpenTelemetry global meter if provider is disabled.
+func (p *Provider) Meter(name string, opts ...metric.MeterOption) metric.Meter {
+	if p == nil || p.provider == nil {
+		return otel.Meter(name, opts...)
+	}
+	m := p.provider.Meter(name, opts...)
+	if p.guard.remaps() {
+		m = guardedMeter{Meter: m, scope: name, guard: p.guard}
+	}
+	if p.registry == nil {
+		return m
+	}
+	return registryMeter{Meter: m, scope: name, registry: p.registry}
 }
 
 // RegisterRuntimeMetrics adds basic Go runtime metrics if enabled.
@@ -18,16 +18,24 @@ func TestSetupDisabledMeter(t *testing.T) {
 		t.Fatalf("setup disabled meter: %v", err)
 	}
 
-	if provider != nil {
-		t.Fatalf("expected nil provider when disabled, got %#v", provider)
+	if provider == nil {
+		t.Fatal("expected no-op provider when disabled, got nil")
 	}
 
-	defer func() {
-		if r := recover(); r == nil {
-			t.Fatal("expected panic when invoking method on nil provider")
-		}
-	}()
-	_ = provider.RegisterRuntimeMetrics(ctx, RuntimeConfig{Enabled: true})
+	if err := provider.RegisterRuntimeMetrics(ctx, RuntimeConfig{Enabled: true}); err != nil {
+		t.Fatalf("register runtime metrics on disabled meter: %v", err)
+	}
+	counter, err := provider.Meter("disabled").Int64Counter("calls")
+	if err != nil {
+		t.Fatalf("counter on disabled meter: %v", err)
+	}
+	counter.Add(ctx, 1)
+	if err := provider.ForceFlush(ctx); err != nil {
+		t.Fatalf("force flush disabled meter: %v", err)
+	}
+	if err := provider.Shutdown(ctx); err != nil {
+		t.Fatalf("shutdown disabled meter: %v", err)
+	}
 }
 
 func TestSetupRequiresEndpointWhenEnabled(t *testing.T) {
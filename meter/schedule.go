@@ -0,0 +1,130 @@
+package meter
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/mfahmialkautsar/goo11y/clock"
+	"go.opentelemetry.io/otel"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// newExportReader returns the reader that pushes metrics to exporter. The SDK periodic reader is used
+// unless jitter, alignment, or manual export is configured, which it cannot express.
+func newExportReader(exporter sdkmetric.Exporter, cfg Config) sdkmetric.Reader {
+	if cfg.ExportJitterPercent == 0 && !cfg.AlignExports && !cfg.ManualExport {
+		return sdkmetric.NewPeriodicReader(
+			exporter,
+			sdkmetric.WithInterval(cfg.ExportInterval),
+			sdkmetric.WithTimeout(cfg.ExportTimeout),
+		)
+	}
+	clk := clock.OrReal(cfg.Clock)
+	r := &scheduledReader{
+		ManualReader: sdkmetric.NewManualReader(
+			sdkmetric.WithTemporalitySelector(exporter.Temporality),
+			sdkmetric.WithAggregationSelector(exporter.Aggregation),
+		),
+		exporter: exporter,
+		interval: cfg.ExportInterval,
+		timeout:  cfg.ExportTimeout,
+		jitter:   float64(cfg.ExportJitterPercent) / 100,
+		align:    cfg.AlignExports,
+		clock:    clk,
+		now:      clk.Now,
+		random:   rand.Float64,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if cfg.ManualExport {
+		close(r.done)
+	} else {
+		go r.run()
+	}
+	return r
+}
+
+// scheduledReader exports on a schedule that is optionally aligned to multiples of the interval
+// on the wall clock and delayed by a random fraction of the interval, so instances started
+// together do not push at the same instant. Without a schedule it exports only when flushed.
+type scheduledReader struct {
+	*sdkmetric.ManualReader
+	exporter sdkmetric.Exporter
+	interval time.Duration
+	timeout  time.Duration
+	jitter   float64
+	align    bool
+	clock    clock.Clock
+	now      func() time.Time
+	random   func() float64
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func (r *scheduledReader) run() {
+	defer close(r.done)
+	timer := r.clock.NewTimer(r.nextDelay())
+	defer timer.Stop()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case <-timer.C():
+		}
+		if err := r.export(context.Background()); err != nil {
+			otel.Handle(err)
+		}
+		timer.Reset(r.nextDelay())
+	}
+}
+
+func (r *scheduledReader) nextDelay() time.Duration {
+	wait := r.interval
+	if r.align {
+		now := r.now()
+		wait = now.Truncate(r.interval).Add(r.interval).Sub(now)
+	}
+	if r.jitter > 0 {
+		wait += time.Duration(r.random() * r.jitter * float64(r.interval))
+	}
+	return wait
+}
+
+func (r *scheduledReader) export(ctx context.Context) error {
+	ctx, cancel := withTimeout(ctx, r.timeout)
+	defer cancel()
+	var rm metricdata.ResourceMetrics
+	if err := r.Collect(ctx, &rm); err != nil {
+		return err
+	}
+	return r.exporter.Export(ctx, &rm)
+}
+
+// ForceFlush collects and exports immediately, then flushes the exporter.
+func (r *scheduledReader) ForceFlush(ctx context.Context) error {
+	return errors.Join(r.export(ctx), r.exporter.ForceFlush(ctx))
+}
+
+// Shutdown stops the schedule, exports what remains, and shuts the exporter down.
+func (r *scheduledReader) Shutdown(ctx context.Context) error {
+	var err error
+	r.stopOnce.Do(func() {
+		close(r.stop)
+		<-r.done
+		err = errors.Join(r.export(ctx), r.ManualReader.Shutdown(ctx), r.exporter.Shutdown(ctx))
+	})
+	return err
+}
+
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
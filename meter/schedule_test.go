@@ -0,0 +1,159 @@
+package meter
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+type countingExporter struct {
+	exports atomic.Int32
+	points  atomic.Int32
+}
+
+func (e *countingExporter) Temporality(kind sdkmetric.InstrumentKind) metricdata.Temporality {
+	return sdkmetric.DefaultTemporalitySelector(kind)
+}
+
+func (e *countingExporter) Aggregation(kind sdkmetric.InstrumentKind) sdkmetric.Aggregation {
+	return sdkmetric.DefaultAggregationSelector(kind)
+}
+
+func (e *countingExporter) Export(_ context.Context, rm *metricdata.ResourceMetrics) error {
+	e.exports.Add(1)
+	for _, scope := range rm.ScopeMetrics {
+		e.points.Add(int32(len(scope.Metrics)))
+	}
+	return nil
+}
+
+func (e *countingExporter) ForceFlush(context.Context) error { return nil }
+
+func (e *countingExporter) Shutdown(context.Context) error { return nil }
+
+func TestScheduledReaderNextDelay(t *testing.T) {
+	r := &scheduledReader{
+		interval: 10 * time.Second,
+		align:    true,
+		now:      func() time.Time { return time.Date(2026, 1, 1, 12, 0, 3, 0, time.UTC) },
+		random:   func() float64 { return 0.5 },
+	}
+	if got := r.nextDelay(); got != 7*time.Second {
+		t.Fatalf("aligned delay = %v, want 7s", got)
+	}
+
+	r.jitter = 0.2
+	if got := r.nextDelay(); got != 8*time.Second {
+		t.Fatalf("aligned delay with jitter = %v, want 8s", got)
+	}
+
+	r.align = false
+	if got := r.nextDelay(); got != 11*time.Second {
+		t.Fatalf("jittered delay = %v, want 11s", got)
+	}
+}
+
+func TestJitteredReaderExportsOnScheduleAndShutdown(t *testing.T) {
+	exporter := &countingExporter{}
+	reader := newExportReader(exporter, Config{
+		ExportInterval:      20 * time.Millisecond,
+		ExportTimeout:       time.Second,
+		ExportJitterPercent: 50,
+	})
+	if _, ok := reader.(*scheduledReader); !ok {
+		t.Fatalf("expected scheduled reader when jitter is set, got %T", reader)
+	}
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader), sdkmetric.WithResource(resource.Empty()))
+	counter, err := provider.Meter("schedule").Int64Counter("ticks")
+	if err != nil {
+		t.Fatalf("Int64Counter: %v", err)
+	}
+	counter.Add(context.Background(), 1)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for exporter.exports.Load() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for scheduled export")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	before := exporter.exports.Load()
+	if err := provider.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if exporter.exports.Load() <= before {
+		t.Fatal("expected a final export on shutdown")
+	}
+	if exporter.points.Load() == 0 {
+		t.Fatal("expected exported metrics")
+	}
+}
+
+func TestExportReaderDefaultsToPeriodic(t *testing.T) {
+	reader := newExportReader(&countingExporter{}, Config{ExportInterval: time.Second, ExportTimeout: time.Second})
+	t.Cleanup(func() { _ = reader.Shutdown(context.Background()) })
+	if _, ok := reader.(*scheduledReader); ok {
+		t.Fatal("expected the SDK periodic reader without jitter or alignment")
+	}
+}
+
+func TestManualExportOnlyOnFlush(t *testing.T) {
+	exporter := &countingExporter{}
+	reader := newExportReader(exporter, Config{
+		ExportInterval: time.Millisecond,
+		ExportTimeout:  time.Second,
+		ManualExport:   true,
+	})
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader), sdkmetric.WithResource(resource.Empty()))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+	counter, err := provider.Meter("manual").Int64Counter("invocations")
+	if err != nil {
+		t.Fatalf("Int64Counter: %v", err)
+	}
+	counter.Add(context.Background(), 1)
+
+	time.Sleep(20 * time.Millisecond)
+	if got := exporter.exports.Load(); got != 0 {
+		t.Fatalf("expected no scheduled export, got %d", got)
+	}
+	if err := provider.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+	if got := exporter.exports.Load(); got != 1 {
+		t.Fatalf("expected one export on flush, got %d", got)
+	}
+}
+
+func TestTemporalitySelector(t *testing.T) {
+	cases := map[string]map[sdkmetric.InstrumentKind]metricdata.Temporality{
+		"": {
+			sdkmetric.InstrumentKindCounter: metricdata.CumulativeTemporality,
+		},
+		TemporalityDelta: {
+			sdkmetric.InstrumentKindCounter:                 metricdata.DeltaTemporality,
+			sdkmetric.InstrumentKindObservableCounter:       metricdata.DeltaTemporality,
+			sdkmetric.InstrumentKindHistogram:               metricdata.DeltaTemporality,
+			sdkmetric.InstrumentKindUpDownCounter:           metricdata.CumulativeTemporality,
+			sdkmetric.InstrumentKindObservableUpDownCounter: metricdata.CumulativeTemporality,
+		},
+		TemporalityLowMemory: {
+			sdkmetric.InstrumentKindCounter:           metricdata.DeltaTemporality,
+			sdkmetric.InstrumentKindHistogram:         metricdata.DeltaTemporality,
+			sdkmetric.InstrumentKindObservableCounter: metricdata.CumulativeTemporality,
+		},
+	}
+	for name, kinds := range cases {
+		selector := temporalitySelector(name)
+		for kind, want := range kinds {
+			if got := selector(kind); got != want {
+				t.Fatalf("%q %v: got %v want %v", name, kind, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package meter
+
+import "sync/atomic"
+
+// Stats reports runtime counters for the meter's export pipeline.
+type Stats struct {
+	// ExportBatches is the number of metric export calls made by the periodic reader.
+	ExportBatches uint64
+	// ExportFailures is the number of export calls that returned an error.
+	ExportFailures uint64
+	// SpoolDepth is the number of export requests waiting in the spool.
+	SpoolDepth int
+	// DroppedAttributes is the number of attributes removed by the cardinality guard.
+	DroppedAttributes uint64
+	// OverflowedAttributes is the number of attribute values the cardinality guard replaced with
+	// "other".
+	OverflowedAttributes uint64
+	// SpoolDropped is the number of export requests the spool discarded without delivering them.
+	SpoolDropped uint64
+	// InstrumentConflicts is the number of conflicting instrument definitions seen so far, so a smoke
+	// test can fail on them; Provider.InstrumentConflicts lists them.
+	InstrumentConflicts int
+}
+
+type exportStats struct {
+	batches  atomic.Uint64
+	failures atomic.Uint64
+}
+
+func (s *exportStats) record(err error) {
+	s.batches.Add(1)
+	if err != nil {
+		s.failures.Add(1)
+	}
+}
+
+// Stats returns a snapshot of the meter's export counters.
+// Returns zero values if provider is disabled or uses a custom reader.
+func (p *Provider) Stats() Stats {
+	var stats Stats
+	if p == nil {
+		return stats
+	}
+	if p.stats != nil {
+		stats.ExportBatches = p.stats.batches.Load()
+		stats.ExportFailures = p.stats.failures.Load()
+	}
+	if p.spoolDepth != nil {
+		stats.SpoolDepth = p.spoolDepth()
+	}
+	if p.spoolDrops != nil {
+		stats.SpoolDropped = p.spoolDrops()
+	}
+	if p.guard != nil {
+		stats.DroppedAttributes = p.guard.dropped.Load()
+		stats.OverflowedAttributes = p.guard.overflowed.Load()
+	}
+	if p.registry != nil {
+		stats.InstrumentConflicts = p.registry.count()
+	}
+	return stats
+}
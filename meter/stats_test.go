@@ -0,0 +1,64 @@
+package meter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+func TestProviderStatsCountsExportBatches(t *testing.T) {
+	var failing atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	provider, err := Setup(ctx, Config{
+		Enabled:     true,
+		Endpoint:    server.Listener.Addr().String(),
+		Insecure:    true,
+		Protocol:    "http",
+		ServiceName: "test-meter-stats",
+	}, resource.Empty())
+	if err != nil {
+		t.Fatalf("setup meter: %v", err)
+	}
+	defer func() {
+		failing.Store(false)
+		_ = provider.Shutdown(ctx)
+	}()
+
+	counter, err := provider.Meter("stats").Int64Counter("stats.requests")
+	if err != nil {
+		t.Fatalf("Int64Counter: %v", err)
+	}
+	counter.Add(ctx, 1)
+
+	if err := provider.ForceFlush(ctx); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+	failing.Store(true)
+	counter.Add(ctx, 1)
+	if err := provider.ForceFlush(ctx); err == nil {
+		t.Fatal("expected ForceFlush to surface the export failure")
+	}
+
+	stats := provider.Stats()
+	if stats.ExportBatches != 2 || stats.ExportFailures != 1 {
+		t.Fatalf("unexpected export counters: %+v", stats)
+	}
+
+	var disabled *Provider
+	if got := disabled.Stats(); got != (Stats{}) {
+		t.Fatalf("expected zero stats for nil provider, got %+v", got)
+	}
+}
@@ -0,0 +1,41 @@
+package meter
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+)
+
+// uptimeInstrumentation is the scope of process.uptime and process.start_time_unix, kept apart from
+// the cardinality guard's so dashboards can select the uptime series by scope.
+const uptimeInstrumentation = "github.com/mfahmialkautsar/goo11y/meter/uptime"
+
+// processStart approximates the process start time with the moment this package was initialized.
+var processStart = time.Now()
+
+func registerUptimeInstruments(m metric.Meter) error {
+	_, err := m.Float64ObservableGauge(
+		"process.uptime",
+		metric.WithDescription("Time since the process started"),
+		metric.WithUnit("s"),
+		metric.WithFloat64Callback(func(_ context.Context, observer metric.Float64Observer) error {
+			observer.Observe(time.Since(processStart).Seconds())
+			return nil
+		}),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.Int64ObservableGauge(
+		"process.start_time_unix",
+		metric.WithDescription("Time the process started, in seconds since the Unix epoch"),
+		metric.WithUnit("s"),
+		metric.WithInt64Callback(func(_ context.Context, observer metric.Int64Observer) error {
+			observer.Observe(processStart.Unix())
+			return nil
+		}),
+	)
+	return err
+}
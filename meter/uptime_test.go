@@ -0,0 +1,55 @@
+package meter
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mfahmialkautsar/goo11y/internal/testutil/inmemory"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+func TestSetupRegistersUptimeMetrics(t *testing.T) {
+	for _, disabled := range []bool{false, true} {
+		reader := sdkmetric.NewManualReader()
+		provider, err := Setup(context.Background(), Config{
+			Enabled:       true,
+			Endpoint:      "localhost:4318",
+			DisableUptime: disabled,
+		}, resource.Empty(), WithMetricReader(reader))
+		if err != nil {
+			t.Fatalf("Setup: %v", err)
+		}
+		t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+		rm, err := inmemory.GetMetrics(context.Background(), reader)
+		if err != nil {
+			t.Fatalf("GetMetrics: %v", err)
+		}
+		uptime, foundUptime := inmemory.FindMetricByName(rm, "process.uptime")
+		start, foundStart := inmemory.FindMetricByName(rm, "process.start_time_unix")
+		if disabled {
+			if foundUptime || foundStart {
+				t.Fatal("expected no uptime metrics when disabled")
+			}
+			continue
+		}
+		if !foundUptime || !foundStart {
+			t.Fatalf("expected uptime metrics, found uptime=%v start=%v", foundUptime, foundStart)
+		}
+		for _, scope := range rm.ScopeMetrics {
+			for _, m := range scope.Metrics {
+				if m.Name == "process.uptime" && scope.Scope.Name != uptimeInstrumentation {
+					t.Fatalf("expected process.uptime under scope %q, got %q", uptimeInstrumentation, scope.Scope.Name)
+				}
+			}
+		}
+		if got := uptime.Data.(metricdata.Gauge[float64]).DataPoints[0].Value; got <= 0 {
+			t.Fatalf("expected positive uptime, got %v", got)
+		}
+		if got := start.Data.(metricdata.Gauge[int64]).DataPoints[0].Value; got != processStart.Unix() {
+			t.Fatalf("expected start time %d, got %d", processStart.Unix(), got)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package meter
+
+import (
+	"fmt"
+	"math"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// LatencyBuckets are boundaries in seconds suited to request latencies, from 5ms to 10s.
+var LatencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// configuredView assigns explicit bucket boundaries to histograms and attaches the cardinality
+// guard's attribute filter. A single view is used so an instrument never matches twice and
+// produces a duplicate stream.
+func configuredView(cfg Config, guard *cardinalityGuard) sdkmetric.View {
+	return func(inst sdkmetric.Instrument) (sdkmetric.Stream, bool) {
+		stream := sdkmetric.Stream{
+			Name:        inst.Name,
+			Description: inst.Description,
+			Unit:        inst.Unit,
+		}
+		matched := false
+		if inst.Kind == sdkmetric.InstrumentKindHistogram {
+			bounds, ok := cfg.HistogramBuckets[inst.Name]
+			if !ok {
+				bounds = cfg.DefaultHistogramBuckets
+			}
+			if bounds != nil {
+				stream.Aggregation = sdkmetric.AggregationExplicitBucketHistogram{
+					Boundaries: append([]float64(nil), bounds...),
+				}
+				matched = true
+			}
+		}
+		if guard != nil && inst.Scope.Name != guardInstrumentation {
+			stream.AttributeFilter = guard.filter(inst)
+			matched = true
+		}
+		return stream, matched
+	}
+}
+
+func configuredViews(cfg Config, guard *cardinalityGuard) []sdkmetric.View {
+	if len(cfg.HistogramBuckets) == 0 && cfg.DefaultHistogramBuckets == nil && guard == nil {
+		return nil
+	}
+	return []sdkmetric.View{configuredView(cfg, guard)}
+}
+
+func validateBoundaries(bounds []float64) error {
+	for i, bound := range bounds {
+		if math.IsNaN(bound) || math.IsInf(bound, 0) {
+			return fmt.Errorf("boundary %v is not finite", bound)
+		}
+		if i > 0 && bound <= bounds[i-1] {
+			return fmt.Errorf("boundaries must be strictly increasing")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package meter
+
+import (
+	"context"
+	"math"
+	"slices"
+	"testing"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+func TestHistogramBucketsApplyByName(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider, err := Setup(context.Background(), Config{
+		Enabled:                 true,
+		Endpoint:                "localhost:4318",
+		HistogramBuckets:        map[string][]float64{"request.duration": {0.1, 1}},
+		DefaultHistogramBuckets: LatencyBuckets,
+	}, resource.Empty(), WithMetricReader(reader))
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	m := provider.Meter("buckets")
+	named, err := m.Float64Histogram("request.duration")
+	if err != nil {
+		t.Fatalf("Float64Histogram: %v", err)
+	}
+	other, err := m.Int64Histogram("queue.wait")
+	if err != nil {
+		t.Fatalf("Int64Histogram: %v", err)
+	}
+	named.Record(context.Background(), 0.5)
+	other.Record(context.Background(), 2)
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+
+	bounds := map[string][]float64{}
+	for _, scope := range rm.ScopeMetrics {
+		for _, metric := range scope.Metrics {
+			switch data := metric.Data.(type) {
+			case metricdata.Histogram[float64]:
+				bounds[metric.Name] = data.DataPoints[0].Bounds
+			case metricdata.Histogram[int64]:
+				bounds[metric.Name] = data.DataPoints[0].Bounds
+			}
+		}
+	}
+	if len(bounds) != 2 {
+		t.Fatalf("expected one stream per histogram, got %v", bounds)
+	}
+	if !slices.Equal(bounds["request.duration"], []float64{0.1, 1}) {
+		t.Fatalf("unexpected named bounds: %v", bounds["request.duration"])
+	}
+	if !slices.Equal(bounds["queue.wait"], LatencyBuckets) {
+		t.Fatalf("unexpected default bounds: %v", bounds["queue.wait"])
+	}
+}
+
+func TestValidateRejectsUnsortedBuckets(t *testing.T) {
+	cfg := Config{Endpoint: "localhost:4318", HistogramBuckets: map[string][]float64{"latency": {1, 0.5}}}.ApplyDefaults()
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error for decreasing boundaries")
+	}
+	cfg = Config{Endpoint: "localhost:4318", DefaultHistogramBuckets: []float64{0, math.Inf(1)}}.ApplyDefaults()
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected error for infinite boundary")
+	}
+}
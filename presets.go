@@ -0,0 +1,74 @@
+package goo11y
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/mfahmialkautsar/goo11y/auth"
+)
+
+// GrafanaCloudDefaultZone is the Grafana Cloud region PresetGrafanaCloud targets when none is given.
+const GrafanaCloudDefaultZone = "prod-us-central-0"
+
+// presetProductionSampleRatio is the share of root traces PresetProduction samples.
+const presetProductionSampleRatio = 0.1
+
+// PresetQueueDir is the directory under which PresetProduction keeps its log and metric spools and
+// its trace failover journal. Mount a volume there so they survive container restarts.
+const PresetQueueDir = "/var/lib/goo11y"
+
+// PresetDev returns a Config for local development: debug-level logs on the console in the
+// development environment, with tracing, metrics, and profiling off. Set Resource.ServiceName and
+// enable other components on the returned value as needed.
+func PresetDev() Config {
+	cfg := Config{}
+	cfg.Resource.Environment = "development"
+	cfg.Logger.Enabled = true
+	cfg.Logger.Level = "debug"
+	cfg.Logger.Console = true
+	return cfg
+}
+
+// PresetProduction returns a Config exporting logs, traces, and metrics over OTLP/HTTP to endpoint,
+// authenticated with creds, in the production environment. Logs and metrics go through the disk
+// spool and traces through the failover queue, all under PresetQueueDir, so a collector outage does
+// not lose telemetry, and 10% of root traces are sampled. Profiling stays off because it needs a
+// Pyroscope server URL.
+func PresetProduction(endpoint string, creds auth.Credentials) Config {
+	cfg := Config{}
+	cfg.Resource.Environment = "production"
+
+	cfg.Logger.Enabled = true
+	cfg.Logger.Level = "info"
+	cfg.Logger.OTLP.Enabled = true
+	cfg.Logger.OTLP.Endpoint = endpoint
+	cfg.Logger.OTLP.Credentials = creds
+	cfg.Logger.OTLP.UseSpool = true
+	cfg.Logger.OTLP.QueueDir = filepath.Join(PresetQueueDir, "logs")
+
+	cfg.Tracer.Enabled = true
+	cfg.Tracer.SampleRatio = presetProductionSampleRatio
+	cfg.Tracer.Export.Backend.Enabled = true
+	cfg.Tracer.Export.Backend.Endpoint = endpoint
+	cfg.Tracer.Export.Backend.Credentials = creds
+	cfg.Tracer.Export.Backend.Failover.Directory = filepath.Join(PresetQueueDir, "trace-failover")
+
+	cfg.Meter.Enabled = true
+	cfg.Meter.Endpoint = endpoint
+	cfg.Meter.Credentials = creds
+	cfg.Meter.UseSpool = true
+	cfg.Meter.QueueDir = filepath.Join(PresetQueueDir, "metrics")
+	return cfg
+}
+
+// PresetGrafanaCloud returns PresetProduction aimed at the Grafana Cloud OTLP gateway of zone, such
+// as "prod-eu-west-2", or GrafanaCloudDefaultZone when zone is empty. stackID is the stack's
+// instance ID and apiKey an access policy token with write scopes for logs, traces, and metrics;
+// both are sent as basic auth, as the gateway expects.
+func PresetGrafanaCloud(stackID, apiKey, zone string) Config {
+	if zone == "" {
+		zone = GrafanaCloudDefaultZone
+	}
+	endpoint := "https://otlp-gateway-" + strings.TrimSpace(zone) + ".grafana.net/otlp"
+	return PresetProduction(endpoint, auth.Credentials{BasicUsername: stackID, BasicPassword: apiKey})
+}
@@ -0,0 +1,45 @@
+package goo11y
+
+import (
+	"testing"
+
+	"github.com/mfahmialkautsar/goo11y/auth"
+)
+
+func TestPresetsValidate(t *testing.T) {
+	t.Parallel()
+
+	presets := map[string]Config{
+		"dev":          PresetDev(),
+		"production":   PresetProduction("https://otlp.example.com", auth.Credentials{BearerToken: "token"}),
+		"grafanacloud": PresetGrafanaCloud("123456", "glc_key", ""),
+	}
+	for name, cfg := range presets {
+		cfg.Resource.ServiceName = "checkout"
+		report := ValidateConfig(cfg)
+		if !report.OK() || len(report.Warnings) != 0 {
+			t.Fatalf("%s preset does not validate cleanly: %+v", name, report)
+		}
+	}
+}
+
+func TestPresetGrafanaCloud(t *testing.T) {
+	t.Parallel()
+
+	cfg := PresetGrafanaCloud("123456", "glc_key", "prod-eu-west-2")
+	want := "https://otlp-gateway-prod-eu-west-2.grafana.net/otlp"
+	if cfg.Logger.OTLP.Endpoint != want || cfg.Tracer.Export.Backend.Endpoint != want || cfg.Meter.Endpoint != want {
+		t.Fatalf("unexpected endpoints %q %q %q", cfg.Logger.OTLP.Endpoint, cfg.Tracer.Export.Backend.Endpoint, cfg.Meter.Endpoint)
+	}
+	headers := cfg.Meter.Credentials.HeaderMap()
+	if headers["Authorization"] != "Basic MTIzNDU2OmdsY19rZXk=" {
+		t.Fatalf("unexpected authorization header %q", headers["Authorization"])
+	}
+	if cfg.Tracer.SampleRatio != presetProductionSampleRatio || !cfg.Logger.OTLP.UseSpool {
+		t.Fatalf("expected production sampling and spooling, got %v %v", cfg.Tracer.SampleRatio, cfg.Logger.OTLP.UseSpool)
+	}
+
+	if got := PresetGrafanaCloud("1", "k", "").Meter.Endpoint; got != "https://otlp-gateway-"+GrafanaCloudDefaultZone+".grafana.net/otlp" {
+		t.Fatalf("default zone endpoint = %q", got)
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/creasty/defaults"
 	"github.com/go-playground/validator/v10"
+	"github.com/grafana/pyroscope-go"
 	"github.com/mfahmialkautsar/goo11y/auth"
 )
 
@@ -24,6 +25,18 @@ type Config struct {
 	UseGlobal            bool
 	Async                bool          `default:"true"`
 	UploadRate           time.Duration `validate:"gte=0"`
+	// SpanLabels names span attributes, such as endpoint or tenant, pushed as Pyroscope labels while
+	// the span runs, so profiles can be filtered by them. It applies when the tracer is enabled too.
+	SpanLabels []string
+	// ProfileTypes selects the Pyroscope profile types to collect: cpu, alloc_objects, alloc_space,
+	// inuse_objects, inuse_space, goroutines, goroutine_leak, mutex_count, mutex_duration,
+	// block_count and block_duration. Empty collects every type except goroutine_leak. Mutex and
+	// block sampling are only switched on when a matching type is selected.
+	ProfileTypes []string `validate:"dive,oneof=cpu alloc_objects alloc_space inuse_objects inuse_space goroutines goroutine_leak mutex_count mutex_duration block_count block_duration"`
+	// TraceProfile configures the span processor linking spans to profiles when the tracer is enabled.
+	TraceProfile TraceProfileConfig
+	// Overhead pauses profiling or thins its sampling while the process is busy.
+	Overhead OverheadConfig
 }
 
 func (c Config) withDefaults() Config {
@@ -39,6 +52,45 @@ func (c Config) ApplyDefaults() Config {
 	return c.withDefaults()
 }
 
+// defaultProfileTypes is collected when Config.ProfileTypes is empty.
+var defaultProfileTypes = []pyroscope.ProfileType{
+	pyroscope.ProfileCPU,
+	pyroscope.ProfileAllocObjects,
+	pyroscope.ProfileAllocSpace,
+	pyroscope.ProfileInuseObjects,
+	pyroscope.ProfileInuseSpace,
+	pyroscope.ProfileGoroutines,
+	pyroscope.ProfileMutexCount,
+	pyroscope.ProfileMutexDuration,
+	pyroscope.ProfileBlockCount,
+	pyroscope.ProfileBlockDuration,
+}
+
+func (c Config) profileTypes() []pyroscope.ProfileType {
+	if len(c.ProfileTypes) == 0 {
+		return append([]pyroscope.ProfileType(nil), defaultProfileTypes...)
+	}
+	types := make([]pyroscope.ProfileType, len(c.ProfileTypes))
+	for i, name := range c.ProfileTypes {
+		types[i] = pyroscope.ProfileType(name)
+	}
+	return types
+}
+
+// samplingRates returns the mutex and block rates to set for the collected types; a type family
+// that is not collected gets zero so the runtime does not pay for it.
+func (c Config) samplingRates(types []pyroscope.ProfileType) (mutexFraction, blockRate int) {
+	for _, t := range types {
+		switch t {
+		case pyroscope.ProfileMutexCount, pyroscope.ProfileMutexDuration:
+			mutexFraction = c.MutexProfileFraction
+		case pyroscope.ProfileBlockCount, pyroscope.ProfileBlockDuration:
+			blockRate = c.BlockProfileRate
+		}
+	}
+	return mutexFraction, blockRate
+}
+
 func (c Config) preparedCredentials() (map[string]string, string, string, bool) {
 	headers := c.Credentials.HeaderMap()
 	user, pass, hasBasic := c.Credentials.BasicAuth()
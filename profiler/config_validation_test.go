@@ -3,6 +3,7 @@ package profiler
 import (
 	"testing"
 
+	"github.com/grafana/pyroscope-go"
 	"github.com/mfahmialkautsar/goo11y/constant"
 )
 
@@ -80,6 +81,24 @@ func TestConfigValidate(t *testing.T) {
 			}.ApplyDefaults(),
 			wantErr: false,
 		},
+		{
+			name: "valid profile types",
+			config: Config{
+				Enabled:      true,
+				ServerURL:    "http://localhost:4040",
+				ProfileTypes: []string{"cpu", "inuse_space", "goroutines"},
+			}.ApplyDefaults(),
+			wantErr: false,
+		},
+		{
+			name: "invalid profile type",
+			config: Config{
+				Enabled:      true,
+				ServerURL:    "http://localhost:4040",
+				ProfileTypes: []string{"cpu", "off_cpu"},
+			}.ApplyDefaults(),
+			wantErr: true,
+		},
 		{
 			name: "invalid missing server url",
 			config: Config{
@@ -99,3 +118,22 @@ func TestConfigValidate(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigProfileTypesDriveSamplingRates(t *testing.T) {
+	cfg := Config{}.ApplyDefaults()
+	if got := cfg.profileTypes(); len(got) != len(defaultProfileTypes) {
+		t.Fatalf("expected default profile types, got %v", got)
+	}
+	if mutex, block := cfg.samplingRates(cfg.profileTypes()); mutex != 5 || block != 5 {
+		t.Fatalf("expected default sampling rates, got mutex=%d block=%d", mutex, block)
+	}
+
+	cfg.ProfileTypes = []string{"alloc_objects", "inuse_space", "block_count"}
+	types := cfg.profileTypes()
+	if len(types) != 3 || types[0] != pyroscope.ProfileAllocObjects {
+		t.Fatalf("unexpected profile types %v", types)
+	}
+	if mutex, block := cfg.samplingRates(types); mutex != 0 || block != 5 {
+		t.Fatalf("expected only block sampling, got mutex=%d block=%d", mutex, block)
+	}
+}
@@ -7,17 +7,14 @@ import (
 )
 
 var globalController atomic.Value
-var disabledController = &Controller{}
+var disabledController = Nop()
 
 // Init configures the profiler controller and exposes it globally.
-func Init(cfg Config, log *logger.Logger) error {
-	controller, err := Setup(cfg, log)
+func Init(cfg Config, log *logger.Logger, opts ...Option) error {
+	controller, err := Setup(cfg, log, opts...)
 	if err != nil {
 		return err
 	}
-	if controller == nil {
-		controller = disabledController
-	}
 	Use(controller)
 	return nil
 }
@@ -0,0 +1,171 @@
+package profiler
+
+import (
+	"runtime"
+	"sync"
+	"time"
+)
+
+// maxSampleScale bounds how far the governor thins mutex and block sampling below the configured
+// rates. CPU profiling is switched off while sampling is thinned this far.
+const maxSampleScale = 64
+
+// OverheadConfig caps the profiler's cost on busy processes. Load is the CPU time this process used
+// over the last CheckInterval as a percentage of GOMAXPROCS, read with getrusage; where that is
+// unavailable the governor stays idle. The profiler's own work counts toward it.
+type OverheadConfig struct {
+	// TargetCPUPercent is the load above which mutex and block sampling are halved every
+	// CheckInterval, down to 1/64 of the configured rates, and restored step by step once load falls
+	// below it again. Pyroscope fixes the CPU profile at 100 Hz, so CPU profiling is the last to go:
+	// it is stopped once the other rates reach 1/64 and started again on the first step back.
+	// Zero disables adaptation.
+	TargetCPUPercent float64 `validate:"gte=0"`
+	// PauseAbovePercent stops profiling while load exceeds it. Zero never pauses.
+	PauseAbovePercent float64 `validate:"gte=0"`
+	// ResumeBelowPercent restarts a paused profiler once load drops under it; zero means 90% of
+	// PauseAbovePercent.
+	ResumeBelowPercent float64       `validate:"gte=0"`
+	CheckInterval      time.Duration `default:"15s" validate:"gte=0"`
+}
+
+func (c OverheadConfig) enabled() bool {
+	return c.CheckInterval > 0 && (c.TargetCPUPercent > 0 || c.PauseAbovePercent > 0)
+}
+
+func (c OverheadConfig) resumeBelow() float64 {
+	if c.ResumeBelowPercent > 0 {
+		return c.ResumeBelowPercent
+	}
+	return c.PauseAbovePercent * 0.9
+}
+
+// processCPUTime reports the user and system CPU time the process has used; tests replace it.
+var processCPUTime = readProcessCPUTime
+
+// overheadGovernor samples process CPU and adjusts the profiler to the overhead budget.
+type overheadGovernor struct {
+	cfg           OverheadConfig
+	mutexFraction int
+	blockRate     int
+	pause         func()
+	resume        func() error
+	// profileCPU restarts the profiler with or without the CPU profile; nil when it is not collected.
+	profileCPU func(enabled bool) error
+
+	mu     sync.Mutex
+	scale  int
+	paused bool
+	cpuOff bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func newOverheadGovernor(cfg OverheadConfig, mutexFraction, blockRate int, pause func(), resume func() error,
+	profileCPU func(enabled bool) error,
+) *overheadGovernor {
+	return &overheadGovernor{
+		cfg:           cfg,
+		mutexFraction: mutexFraction,
+		blockRate:     blockRate,
+		pause:         pause,
+		resume:        resume,
+		profileCPU:    profileCPU,
+		scale:         1,
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+}
+
+func (g *overheadGovernor) start() {
+	go func() {
+		defer close(g.done)
+		ticker := time.NewTicker(g.cfg.CheckInterval)
+		defer ticker.Stop()
+		lastCPU, measured := processCPUTime()
+		lastWall := time.Now()
+		for {
+			select {
+			case <-g.stop:
+				return
+			case now := <-ticker.C:
+				used, ok := processCPUTime()
+				if ok && measured {
+					g.observe(cpuPercent(used-lastCPU, now.Sub(lastWall)))
+				}
+				lastCPU, lastWall, measured = used, now, ok
+			}
+		}
+	}()
+}
+
+// close stops sampling and restores the configured sampling rates.
+func (g *overheadGovernor) close() {
+	close(g.stop)
+	<-g.done
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.scale = 1
+	g.applyRates()
+}
+
+// cpuPercent returns used CPU time as a percentage of what GOMAXPROCS allows over wall.
+func cpuPercent(used, wall time.Duration) float64 {
+	if wall <= 0 {
+		return 0
+	}
+	return used.Seconds() / (wall.Seconds() * float64(runtime.GOMAXPROCS(0))) * 100
+}
+
+// observe applies one load sample. It is only called from the sampling goroutine, so the state is
+// read and written back around the profiler restarts instead of holding g.mu while Pyroscope
+// uploads and starts again.
+func (g *overheadGovernor) observe(load float64) {
+	g.mu.Lock()
+	scale, paused, cpuOff := g.scale, g.paused, g.cpuOff
+	g.mu.Unlock()
+
+	switch {
+	case paused && (g.cfg.PauseAbovePercent == 0 || load < g.cfg.resumeBelow()):
+		if err := g.resume(); err == nil {
+			paused = false
+		}
+	case !paused && g.cfg.PauseAbovePercent > 0 && load > g.cfg.PauseAbovePercent:
+		g.pause()
+		paused = true
+	}
+
+	if g.cfg.TargetCPUPercent > 0 {
+		if load > g.cfg.TargetCPUPercent {
+			scale = min(scale*2, maxSampleScale)
+		} else if scale > 1 {
+			scale /= 2
+		}
+	}
+	if off := scale == maxSampleScale; g.profileCPU != nil && off != cpuOff {
+		// A failed restart leaves the profiler stopped; resuming retries it on the next sample.
+		if err := g.profileCPU(!off); err != nil {
+			paused = true
+		}
+		cpuOff = off
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.paused, g.cpuOff = paused, cpuOff
+	if scale != g.scale {
+		g.scale = scale
+		g.applyRates()
+	}
+}
+
+func (g *overheadGovernor) applyRates() {
+	runtime.SetMutexProfileFraction(g.mutexFraction * g.scale)
+	runtime.SetBlockProfileRate(g.blockRate * g.scale)
+}
+
+func (g *overheadGovernor) state() (scale int, paused bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.scale, g.paused
+}
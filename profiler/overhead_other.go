@@ -0,0 +1,10 @@
+//go:build !unix
+
+package profiler
+
+import "time"
+
+// readProcessCPUTime reports no CPU time where getrusage is unavailable, leaving the governor idle.
+func readProcessCPUTime() (time.Duration, bool) {
+	return 0, false
+}
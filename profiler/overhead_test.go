@@ -0,0 +1,133 @@
+package profiler
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestOverheadGovernorPausesAndResumes(t *testing.T) {
+	var pauses, resumes int
+	g := newOverheadGovernor(OverheadConfig{PauseAbovePercent: 80, CheckInterval: time.Second}, 5, 5,
+		func() { pauses++ },
+		func() error { resumes++; return nil }, nil,
+	)
+
+	g.observe(50)
+	if _, paused := g.state(); paused || pauses != 0 {
+		t.Fatalf("expected profiling to continue at 50%%, paused=%v pauses=%d", paused, pauses)
+	}
+	g.observe(95)
+	g.observe(99)
+	if _, paused := g.state(); !paused || pauses != 1 {
+		t.Fatalf("expected a single pause above 80%%, paused=%v pauses=%d", paused, pauses)
+	}
+	g.observe(75)
+	if _, paused := g.state(); !paused || resumes != 0 {
+		t.Fatalf("expected to stay paused above the resume threshold, paused=%v resumes=%d", paused, resumes)
+	}
+	g.observe(60)
+	if _, paused := g.state(); paused || resumes != 1 {
+		t.Fatalf("expected resume below 72%%, paused=%v resumes=%d", paused, resumes)
+	}
+}
+
+func TestOverheadGovernorAdaptsSampling(t *testing.T) {
+	t.Cleanup(func() {
+		runtime.SetMutexProfileFraction(0)
+		runtime.SetBlockProfileRate(0)
+	})
+	g := newOverheadGovernor(OverheadConfig{TargetCPUPercent: 50, CheckInterval: time.Second}, 5, 5,
+		func() {}, func() error { return nil }, nil)
+
+	for range 10 {
+		g.observe(90)
+	}
+	if scale, _ := g.state(); scale != maxSampleScale {
+		t.Fatalf("expected scale capped at %d, got %d", maxSampleScale, scale)
+	}
+	if got := runtime.SetMutexProfileFraction(-1); got != 5*maxSampleScale {
+		t.Fatalf("expected mutex fraction %d, got %d", 5*maxSampleScale, got)
+	}
+
+	g.observe(10)
+	if scale, _ := g.state(); scale != maxSampleScale/2 {
+		t.Fatalf("expected scale to halve below target, got %d", scale)
+	}
+	for range 10 {
+		g.observe(10)
+	}
+	if scale, _ := g.state(); scale != 1 {
+		t.Fatalf("expected configured rates restored, got scale %d", scale)
+	}
+}
+
+func TestOverheadGovernorSwitchesCPUProfilingAtMaximumThinning(t *testing.T) {
+	t.Cleanup(func() {
+		runtime.SetMutexProfileFraction(0)
+		runtime.SetBlockProfileRate(0)
+	})
+	var switches []bool
+	g := newOverheadGovernor(OverheadConfig{TargetCPUPercent: 50, CheckInterval: time.Second}, 5, 5,
+		func() {}, func() error { return nil },
+		func(enabled bool) error { switches = append(switches, enabled); return nil })
+
+	for range 5 {
+		g.observe(90)
+	}
+	if len(switches) != 0 {
+		t.Fatalf("expected CPU profiling to continue before maximum thinning, got %v", switches)
+	}
+	g.observe(90)
+	g.observe(90)
+	if len(switches) != 1 || switches[0] {
+		t.Fatalf("expected CPU profiling switched off once, got %v", switches)
+	}
+	g.observe(10)
+	if len(switches) != 2 || !switches[1] {
+		t.Fatalf("expected CPU profiling switched back on, got %v", switches)
+	}
+}
+
+func TestOverheadGovernorSamplesProcessCPU(t *testing.T) {
+	original := processCPUTime
+	t.Cleanup(func() { processCPUTime = original })
+	var used time.Duration
+	processCPUTime = func() (time.Duration, bool) {
+		// Every sample reports far more CPU time than the interval allows.
+		used += time.Hour
+		return used, true
+	}
+
+	paused := make(chan struct{}, 1)
+	g := newOverheadGovernor(OverheadConfig{PauseAbovePercent: 80, CheckInterval: 5 * time.Millisecond}, 0, 0,
+		func() { paused <- struct{}{} }, func() error { return nil }, nil)
+	g.start()
+	defer g.close()
+
+	select {
+	case <-paused:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the governor to pause profiling under high load")
+	}
+}
+
+func TestCPUPercentScalesByGOMAXPROCS(t *testing.T) {
+	procs := float64(runtime.GOMAXPROCS(0))
+	if got := cpuPercent(time.Second, time.Second); got != 100/procs {
+		t.Fatalf("expected %.2f%%, got %.2f%%", 100/procs, got)
+	}
+	if got := cpuPercent(time.Second, 0); got != 0 {
+		t.Fatalf("expected 0%% for an empty interval, got %.2f%%", got)
+	}
+}
+
+func TestOverheadConfigDisabledByDefault(t *testing.T) {
+	cfg := Config{}.ApplyDefaults()
+	if cfg.Overhead.CheckInterval != 15*time.Second {
+		t.Fatalf("expected default check interval, got %v", cfg.Overhead.CheckInterval)
+	}
+	if cfg.Overhead.enabled() {
+		t.Fatal("expected overhead governor disabled without thresholds")
+	}
+}
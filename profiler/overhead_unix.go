@@ -0,0 +1,17 @@
+//go:build unix
+
+package profiler
+
+import (
+	"syscall"
+	"time"
+)
+
+// readProcessCPUTime returns the user and system CPU time of the process from getrusage.
+func readProcessCPUTime() (time.Duration, bool) {
+	var usage syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err != nil {
+		return 0, false
+	}
+	return time.Duration(usage.Utime.Nano() + usage.Stime.Nano()), true
+}
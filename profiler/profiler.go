@@ -3,6 +3,8 @@ package profiler
 import (
 	"fmt"
 	"runtime"
+	"slices"
+	"sync"
 
 	"github.com/grafana/pyroscope-go"
 	"github.com/mfahmialkautsar/goo11y/logger"
@@ -10,15 +12,47 @@ import (
 
 // Controller manages the lifecycle of the Pyroscope profiler.
 type Controller struct {
+	mu       sync.Mutex
 	profiler *pyroscope.Profiler
+	config   pyroscope.Config
+	governor *overheadGovernor
+	// withoutCPU leaves the CPU profile out of restarts while the overhead budget has switched it off.
+	withoutCPU bool
+}
+
+// Nop returns a controller that profiles nothing. Setup returns it when profiling is disabled.
+func Nop() *Controller {
+	return &Controller{}
+}
+
+// Option configures the pyroscope profiler.
+type Option func(*config)
+
+type config struct {
+	profileTypes []pyroscope.ProfileType
+	logger       pyroscope.Logger
+}
+
+// WithProfileTypes replaces the collected profile types, taking precedence over Config.ProfileTypes.
+func WithProfileTypes(types ...pyroscope.ProfileType) Option {
+	return func(c *config) {
+		c.profileTypes = append(c.profileTypes, types...)
+	}
+}
+
+// WithPyroscopeLogger routes the profiler's own diagnostics to l instead of the telemetry logger.
+func WithPyroscopeLogger(l pyroscope.Logger) Option {
+	return func(c *config) {
+		c.logger = l
+	}
 }
 
 // Setup initializes a pyroscope profiler and starts profiling if enabled.
-func Setup(cfg Config, log *logger.Logger) (*Controller, error) {
+func Setup(cfg Config, log *logger.Logger, opts ...Option) (*Controller, error) {
 	cfg = cfg.ApplyDefaults()
 
 	if !cfg.Enabled {
-		return nil, nil
+		return Nop(), nil
 	}
 
 	if err := cfg.Validate(); err != nil {
@@ -38,25 +72,25 @@ func Setup(cfg Config, log *logger.Logger) (*Controller, error) {
 		Logger:          pyroscope.StandardLogger,
 		Tags:            cfg.Tags,
 		TenantID:        cfg.TenantID,
-		ProfileTypes: []pyroscope.ProfileType{
-			pyroscope.ProfileCPU,
-			pyroscope.ProfileAllocObjects,
-			pyroscope.ProfileAllocSpace,
-			pyroscope.ProfileInuseObjects,
-			pyroscope.ProfileInuseSpace,
-			pyroscope.ProfileGoroutines,
-			pyroscope.ProfileMutexCount,
-			pyroscope.ProfileMutexDuration,
-			pyroscope.ProfileBlockCount,
-			pyroscope.ProfileBlockDuration,
-		},
-		HTTPHeaders: headers,
+		ProfileTypes:    cfg.profileTypes(),
+		HTTPHeaders:     headers,
 	}
 
 	if log != nil {
 		profilerCfg.Logger = newPyroscopeTelemetryLogger(log)
 	}
 
+	options := config{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if len(options.profileTypes) > 0 {
+		profilerCfg.ProfileTypes = options.profileTypes
+	}
+	if options.logger != nil {
+		profilerCfg.Logger = options.logger
+	}
+
 	if hasBasic {
 		profilerCfg.BasicAuthUser = user
 		profilerCfg.BasicAuthPassword = pass
@@ -71,28 +105,105 @@ func Setup(cfg Config, log *logger.Logger) (*Controller, error) {
 		return nil, fmt.Errorf("start profiler: %w", err)
 	}
 
-	runtime.SetMutexProfileFraction(cfg.MutexProfileFraction)
-	runtime.SetBlockProfileRate(cfg.BlockProfileRate)
-
-	return &Controller{profiler: controller}, nil
+	mutexFraction, blockRate := cfg.samplingRates(profilerCfg.ProfileTypes)
+	runtime.SetMutexProfileFraction(mutexFraction)
+	runtime.SetBlockProfileRate(blockRate)
+
+	c := &Controller{profiler: controller, config: profilerCfg}
+	if cfg.Overhead.enabled() {
+		var profileCPU func(bool) error
+		if slices.Contains(profilerCfg.ProfileTypes, pyroscope.ProfileCPU) {
+			profileCPU = c.profileCPU
+		}
+		c.governor = newOverheadGovernor(cfg.Overhead, mutexFraction, blockRate, c.pause, c.resume, profileCPU)
+		c.governor.start()
+	}
+	return c, nil
 }
 
 // Stop flushes and terminates the profiler if it has been started.
 func (c *Controller) Stop() error {
+	if c.governor != nil {
+		c.governor.close()
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	if c.profiler == nil {
 		return nil
 	}
-	return c.profiler.Stop()
+	err := c.profiler.Stop()
+	c.profiler = nil
+	return err
 }
 
 // Flush requests an immediate upload of collected profiles.
 func (c *Controller) Flush(wait bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	if c.profiler == nil {
 		return
 	}
 	c.profiler.Flush(wait)
 }
 
+// Paused reports whether the overhead budget has stopped profiling because the process is busy.
+func (c *Controller) Paused() bool {
+	if c.governor == nil {
+		return false
+	}
+	_, paused := c.governor.state()
+	return paused
+}
+
+// pause, resume, and profileCPU are called only by the governor, which Stop halts first. They stop
+// and start Pyroscope outside c.mu so Flush is not held up by the final upload or the restart.
+func (c *Controller) pause() {
+	c.mu.Lock()
+	running := c.profiler
+	c.profiler = nil
+	c.mu.Unlock()
+	if running != nil {
+		_ = running.Stop()
+	}
+}
+
+func (c *Controller) resume() error {
+	c.mu.Lock()
+	running := c.profiler != nil
+	cfg := c.config
+	if c.withoutCPU {
+		cfg.ProfileTypes = slices.DeleteFunc(slices.Clone(cfg.ProfileTypes), func(t pyroscope.ProfileType) bool {
+			return t == pyroscope.ProfileCPU
+		})
+	}
+	c.mu.Unlock()
+	if running {
+		return nil
+	}
+	profiler, err := pyroscope.Start(cfg)
+	if err != nil {
+		return fmt.Errorf("restart profiler: %w", err)
+	}
+	c.mu.Lock()
+	c.profiler = profiler
+	c.mu.Unlock()
+	return nil
+}
+
+// profileCPU restarts a running profiler with or without the CPU profile. A paused profiler only
+// records the choice for its next resume.
+func (c *Controller) profileCPU(enabled bool) error {
+	c.mu.Lock()
+	c.withoutCPU = !enabled
+	running := c.profiler != nil
+	c.mu.Unlock()
+	if !running {
+		return nil
+	}
+	c.pause()
+	return c.resume()
+}
+
 type pyroscopeTelemetryLogger struct {
 	log *logger.Logger
 }
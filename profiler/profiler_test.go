@@ -1,6 +1,12 @@
 package profiler
 
-import "testing"
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/grafana/pyroscope-go"
+)
 
 func TestSetupDisabledProfiler(t *testing.T) {
 	controller, err := Setup(Config{}, nil)
@@ -8,16 +14,14 @@ func TestSetupDisabledProfiler(t *testing.T) {
 		t.Fatalf("setup disabled profiler: %v", err)
 	}
 
-	if controller != nil {
-		t.Fatalf("expected nil controller when disabled, got %#v", controller)
+	if controller == nil {
+		t.Fatal("expected no-op controller when disabled, got nil")
 	}
 
-	defer func() {
-		if r := recover(); r == nil {
-			t.Fatal("expected panic when invoking method on nil controller")
-		}
-	}()
-	_ = controller.Stop()
+	controller.Flush(true)
+	if err := controller.Stop(); err != nil {
+		t.Fatalf("stop disabled controller: %v", err)
+	}
 }
 
 func TestSetupRequiresServerAndService(t *testing.T) {
@@ -35,3 +39,34 @@ func TestSetupRequiresServerAndService(t *testing.T) {
 	}
 	_ = controller.Stop()
 }
+
+type recordingPyroscopeLogger struct {
+	lines []string
+}
+
+func (l *recordingPyroscopeLogger) Infof(format string, args ...any) {
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+func (l *recordingPyroscopeLogger) Debugf(string, ...any) {}
+
+func (l *recordingPyroscopeLogger) Errorf(format string, args ...any) {
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+func TestSetupAppliesOptions(t *testing.T) {
+	rec := &recordingPyroscopeLogger{}
+	controller, err := Setup(Config{Enabled: true, ServerURL: "http://localhost:4040"}, nil,
+		WithPyroscopeLogger(rec),
+		WithProfileTypes(pyroscope.ProfileCPU),
+	)
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	_ = controller.Stop()
+
+	output := strings.Join(rec.lines, "\n")
+	if !strings.Contains(output, "ProfilingTypes: [cpu]") {
+		t.Fatalf("expected only the cpu profile type in startup log, got %q", output)
+	}
+}
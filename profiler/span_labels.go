@@ -0,0 +1,113 @@
+package profiler
+
+import (
+	"bytes"
+	"context"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// SpanLabelsSpanProcessor returns a span processor that pushes the span attributes named by keys as
+// Pyroscope labels onto the goroutine starting the span, the same labels pyroscope.TagWrapper applies,
+// and restores the previous labels when the span ends. Only attributes present when the span starts
+// are seen. The labels are restored only when the span ends on the goroutine that started it, as with
+// defer span.End(); a span ended elsewhere leaves the labels of both goroutines alone. Labels of
+// enclosing spans stay in effect for their children.
+func SpanLabelsSpanProcessor(keys ...string) sdktrace.SpanProcessor {
+	wanted := make([]attribute.Key, 0, len(keys))
+	for _, key := range keys {
+		if key != "" {
+			wanted = append(wanted, attribute.Key(key))
+		}
+	}
+	return &spanLabelsProcessor{keys: wanted}
+}
+
+type spanLabelsProcessor struct {
+	keys []attribute.Key
+	// active maps each tracked span to its labeled context and the context to restore when it ends.
+	active sync.Map
+}
+
+type spanLabels struct {
+	labeled context.Context
+	restore context.Context
+	// goroutine is the ID of the goroutine whose labels were set; only it restores them.
+	goroutine uint64
+}
+
+func (p *spanLabelsProcessor) OnStart(ctx context.Context, span sdktrace.ReadWriteSpan) {
+	if span == nil || len(p.keys) == 0 {
+		return
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	base := ctx
+	parent, tracked := p.active.Load(span.Parent().SpanID())
+	if tracked && span.Parent().IsValid() {
+		base = parent.(spanLabels).labeled
+	}
+
+	labels := p.labels(span)
+	if len(labels) == 0 {
+		if tracked {
+			p.active.Store(span.SpanContext().SpanID(), spanLabels{labeled: base, restore: base})
+		}
+		return
+	}
+
+	labeled := pprof.WithLabels(base, pprof.Labels(labels...))
+	p.active.Store(span.SpanContext().SpanID(), spanLabels{labeled: labeled, restore: base, goroutine: goroutineID()})
+	pprof.SetGoroutineLabels(labeled)
+}
+
+func (p *spanLabelsProcessor) labels(span sdktrace.ReadWriteSpan) []string {
+	var labels []string
+	attrs := span.Attributes()
+	for _, key := range p.keys {
+		for _, kv := range attrs {
+			if kv.Key == key {
+				labels = append(labels, string(key), kv.Value.Emit())
+				break
+			}
+		}
+	}
+	return labels
+}
+
+func (p *spanLabelsProcessor) OnEnd(span sdktrace.ReadOnlySpan) {
+	if span == nil {
+		return
+	}
+	entry, ok := p.active.LoadAndDelete(span.SpanContext().SpanID())
+	if !ok {
+		return
+	}
+	if labels := entry.(spanLabels); labels.labeled != labels.restore && labels.goroutine == goroutineID() {
+		pprof.SetGoroutineLabels(labels.restore)
+	}
+}
+
+// goroutineID returns the ID the runtime gives the calling goroutine, read from the header of its
+// stack trace, "goroutine 42 [running]:". The runtime exposes no cheaper way to tell goroutines apart.
+func goroutineID() uint64 {
+	var buf [64]byte
+	header := bytes.TrimPrefix(buf[:runtime.Stack(buf[:], false)], []byte("goroutine "))
+	end := bytes.IndexByte(header, ' ')
+	if end < 0 {
+		return 0
+	}
+	id, _ := strconv.ParseUint(string(header[:end]), 10, 64)
+	return id
+}
+
+func (p *spanLabelsProcessor) Shutdown(context.Context) error { return nil }
+
+func (p *spanLabelsProcessor) ForceFlush(context.Context) error { return nil }
@@ -0,0 +1,79 @@
+package profiler
+
+import (
+	"bytes"
+	"context"
+	"runtime/pprof"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// goroutineLabels returns the label sets the goroutine profile reports.
+func goroutineLabels(t *testing.T) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := pprof.Lookup("goroutine").WriteTo(&buf, 1); err != nil {
+		t.Fatalf("goroutine profile: %v", err)
+	}
+	return buf.String()
+}
+
+func TestSpanLabelsSpanProcessorLabelsGoroutine(t *testing.T) {
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(SpanLabelsSpanProcessor("endpoint", "tenant")))
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+	tracer := tp.Tracer("span-labels")
+
+	ctx, parent := tracer.Start(context.Background(), "parent", trace.WithAttributes(
+		attribute.String("endpoint", "/checkout"),
+		attribute.String("ignored", "value"),
+	))
+	if labels := goroutineLabels(t); !strings.Contains(labels, `"endpoint":"/checkout"`) || strings.Contains(labels, `"ignored"`) {
+		t.Fatalf("expected only the endpoint label, got:\n%s", labels)
+	}
+
+	_, child := tracer.Start(ctx, "child", trace.WithAttributes(attribute.String("tenant", "acme")))
+	if labels := goroutineLabels(t); !strings.Contains(labels, `"endpoint":"/checkout", "tenant":"acme"`) {
+		t.Fatalf("expected parent and child labels, got:\n%s", labels)
+	}
+	child.End()
+	if labels := goroutineLabels(t); strings.Contains(labels, `"tenant":"acme"`) || !strings.Contains(labels, `"endpoint":"/checkout"`) {
+		t.Fatalf("expected child label removed and parent label kept, got:\n%s", labels)
+	}
+
+	parent.End()
+	if labels := goroutineLabels(t); strings.Contains(labels, `"endpoint":"/checkout"`) {
+		t.Fatalf("expected labels restored after span end, got:\n%s", labels)
+	}
+}
+
+func TestSpanLabelsSpanProcessorRestoresOnlyOnStartingGoroutine(t *testing.T) {
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(SpanLabelsSpanProcessor("endpoint")))
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+	t.Cleanup(func() { pprof.SetGoroutineLabels(context.Background()) })
+
+	_, span := tp.Tracer("span-labels").Start(context.Background(), "handoff", trace.WithAttributes(
+		attribute.String("endpoint", "/async"),
+	))
+
+	ended := make(chan struct{})
+	release := make(chan struct{})
+	go func() {
+		pprof.SetGoroutineLabels(pprof.WithLabels(context.Background(), pprof.Labels("worker", "consumer")))
+		span.End()
+		close(ended)
+		<-release
+	}()
+	<-ended
+	labels := goroutineLabels(t)
+	close(release)
+	if !strings.Contains(labels, `"worker":"consumer"`) {
+		t.Fatalf("expected the goroutine ending the span to keep its labels, got:\n%s", labels)
+	}
+	if !strings.Contains(labels, `"endpoint":"/async"`) {
+		t.Fatalf("expected the starting goroutine to keep the span labels, got:\n%s", labels)
+	}
+}
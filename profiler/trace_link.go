@@ -11,34 +11,85 @@ import (
 // TraceProfileAttributeKey matches the span attribute Grafana expects to bridge traces and Pyroscope profiles.
 const TraceProfileAttributeKey = "pyroscope.profile.id"
 
+// TraceProfileConfig shapes how spans are linked to Pyroscope profiles.
+type TraceProfileConfig struct {
+	// RootSpanOnly links only local root spans, those without a parent or with a remote one, which
+	// is enough for Grafana's span-to-profile view and keeps child spans smaller.
+	RootSpanOnly bool
+	// AttributeKey names the span attribute carrying the profile ID; it defaults to TraceProfileAttributeKey.
+	AttributeKey string `default:"pyroscope.profile.id"`
+	// LabelKey names the pprof label the profile ID is read from; it defaults to TraceProfileAttributeKey.
+	LabelKey string `default:"pyroscope.profile.id"`
+	// Labels lists further pprof labels copied onto linked spans as attributes of the same name.
+	Labels []string
+}
+
 // TraceProfileSpanProcessor returns a span processor that copies Pyroscope profile identifiers from context labels onto spans.
 func TraceProfileSpanProcessor() sdktrace.SpanProcessor {
-	return traceProfileLinkProcessor{}
+	return NewTraceProfileSpanProcessor(TraceProfileConfig{})
 }
 
-type traceProfileLinkProcessor struct{}
+// NewTraceProfileSpanProcessor returns a trace-profile link processor configured by cfg. goo11y.New
+// registers one from Config.Profiler.TraceProfile; callers building their own TracerProvider pass
+// it to sdktrace.WithSpanProcessor.
+func NewTraceProfileSpanProcessor(cfg TraceProfileConfig) sdktrace.SpanProcessor {
+	if cfg.AttributeKey == "" {
+		cfg.AttributeKey = TraceProfileAttributeKey
+	}
+	if cfg.LabelKey == "" {
+		cfg.LabelKey = TraceProfileAttributeKey
+	}
+	labels := make(map[string]struct{}, len(cfg.Labels))
+	for _, key := range cfg.Labels {
+		if key != "" && key != cfg.LabelKey {
+			labels[key] = struct{}{}
+		}
+	}
+	return &traceProfileLinkProcessor{
+		rootOnly:  cfg.RootSpanOnly,
+		attribute: attribute.Key(cfg.AttributeKey),
+		label:     cfg.LabelKey,
+		labels:    labels,
+	}
+}
+
+type traceProfileLinkProcessor struct {
+	rootOnly  bool
+	attribute attribute.Key
+	label     string
+	labels    map[string]struct{}
+}
 
-func (traceProfileLinkProcessor) OnStart(ctx context.Context, span sdktrace.ReadWriteSpan) {
+func (p *traceProfileLinkProcessor) OnStart(ctx context.Context, span sdktrace.ReadWriteSpan) {
 	if span == nil || ctx == nil {
 		return
 	}
+	if p.rootOnly {
+		if parent := span.Parent(); parent.IsValid() && !parent.IsRemote() {
+			return
+		}
+	}
 
 	var profileID string
+	var extra []attribute.KeyValue
 	pprof.ForLabels(ctx, func(key, value string) bool {
-		if key == TraceProfileAttributeKey {
+		if key == p.label {
 			profileID = value
-			return false
+		} else if _, ok := p.labels[key]; ok {
+			extra = append(extra, attribute.String(key, value))
 		}
 		return true
 	})
 
-	if profileID != "" {
-		span.SetAttributes(attribute.String(TraceProfileAttributeKey, profileID))
+	if profileID == "" {
+		return
 	}
+	span.SetAttributes(p.attribute.String(profileID))
+	span.SetAttributes(extra...)
 }
 
-func (traceProfileLinkProcessor) OnEnd(s sdktrace.ReadOnlySpan) {}
+func (*traceProfileLinkProcessor) OnEnd(s sdktrace.ReadOnlySpan) {}
 
-func (traceProfileLinkProcessor) Shutdown(context.Context) error { return nil }
+func (*traceProfileLinkProcessor) Shutdown(context.Context) error { return nil }
 
-func (traceProfileLinkProcessor) ForceFlush(context.Context) error { return nil }
+func (*traceProfileLinkProcessor) ForceFlush(context.Context) error { return nil }
@@ -46,3 +46,45 @@ func TestTraceProfileSpanProcessorAddsAttribute(t *testing.T) {
 
 	t.Fatalf("attribute %s not found on span", TraceProfileAttributeKey)
 }
+
+func TestNewTraceProfileSpanProcessorAppliesConfig(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	processor := NewTraceProfileSpanProcessor(TraceProfileConfig{
+		RootSpanOnly: true,
+		AttributeKey: "profile.id",
+		LabelKey:     "profile_id",
+		Labels:       []string{"tenant"},
+	})
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder), sdktrace.WithSpanProcessor(processor))
+	defer func() {
+		_ = tp.Shutdown(context.Background())
+	}()
+
+	tracer := tp.Tracer("trace-link")
+	pprof.Do(context.Background(), pprof.Labels("profile_id", "p-1", "tenant", "acme", "ignored", "x"), func(ctx context.Context) {
+		ctx, root := tracer.Start(ctx, "root")
+		_, child := tracer.Start(ctx, "child")
+		child.End()
+		root.End()
+	})
+
+	for _, span := range recorder.Ended() {
+		attrs := make(map[string]string)
+		for _, attr := range span.Attributes() {
+			attrs[string(attr.Key)] = attr.Value.AsString()
+		}
+		switch span.Name() {
+		case "root":
+			if attrs["profile.id"] != "p-1" || attrs["tenant"] != "acme" {
+				t.Fatalf("expected profile id and tenant on root span, got %v", attrs)
+			}
+			if _, ok := attrs["ignored"]; ok {
+				t.Fatalf("unexpected unlisted label on root span: %v", attrs)
+			}
+		case "child":
+			if len(attrs) != 0 {
+				t.Fatalf("expected child span untouched with RootSpanOnly, got %v", attrs)
+			}
+		}
+	}
+}
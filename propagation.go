@@ -0,0 +1,51 @@
+package goo11y
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// ContextFromRequest returns the request context enriched with the trace context and baggage
+// carried by its headers, using the globally configured propagator.
+func ContextFromRequest(r *http.Request) context.Context {
+	if r == nil {
+		return context.Background()
+	}
+	return otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+}
+
+// InjectIntoRequest writes the trace context and baggage from ctx into the outgoing request headers.
+func InjectIntoRequest(ctx context.Context, r *http.Request) {
+	if r == nil {
+		return
+	}
+	if r.Header == nil {
+		r.Header = make(http.Header)
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(r.Header))
+}
+
+// ContextFromMap returns ctx enriched with the trace context and baggage stored in headers,
+// such as message queue metadata.
+func ContextFromMap(ctx context.Context, headers map[string]string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if len(headers) == 0 {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(headers))
+}
+
+// InjectIntoMap writes the trace context and baggage from ctx into headers.
+// Returns headers, allocating a new map when it is nil.
+func InjectIntoMap(ctx context.Context, headers map[string]string) map[string]string {
+	if headers == nil {
+		headers = make(map[string]string)
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.MapCarrier(headers))
+	return headers
+}
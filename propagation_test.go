@@ -0,0 +1,63 @@
+package goo11y
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func withTraceContextPropagator(t *testing.T) {
+	t.Helper()
+	previous := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	t.Cleanup(func() { otel.SetTextMapPropagator(previous) })
+}
+
+func TestRequestPropagationRoundTrip(t *testing.T) {
+	withTraceContextPropagator(t)
+	tp := sdktrace.NewTracerProvider()
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	ctx, span := tp.Tracer("propagation").Start(context.Background(), "client")
+	defer span.End()
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	InjectIntoRequest(ctx, req)
+	if req.Header.Get("traceparent") == "" {
+		t.Fatal("expected traceparent header")
+	}
+
+	got := trace.SpanContextFromContext(ContextFromRequest(req))
+	if got.TraceID() != span.SpanContext().TraceID() || !got.IsRemote() {
+		t.Fatalf("unexpected extracted span context %+v", got)
+	}
+}
+
+func TestMapPropagationRoundTrip(t *testing.T) {
+	withTraceContextPropagator(t)
+	tp := sdktrace.NewTracerProvider()
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	ctx, span := tp.Tracer("propagation").Start(context.Background(), "producer")
+	defer span.End()
+
+	headers := InjectIntoMap(ctx, nil)
+	if headers["traceparent"] == "" {
+		t.Fatalf("expected traceparent in %v", headers)
+	}
+
+	got := trace.SpanContextFromContext(ContextFromMap(context.Background(), headers))
+	if got.SpanID() != span.SpanContext().SpanID() {
+		t.Fatalf("unexpected extracted span context %+v", got)
+	}
+
+	if ctx := ContextFromMap(context.Background(), nil); trace.SpanContextFromContext(ctx).IsValid() {
+		t.Fatal("expected empty headers to leave context unchanged")
+	}
+}
@@ -0,0 +1,164 @@
+package goo11y
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/mfahmialkautsar/goo11y/logger"
+	"github.com/mfahmialkautsar/goo11y/meter"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	panicInstrumentation = "github.com/mfahmialkautsar/goo11y"
+	panicCounterName     = "runtime.go.panics"
+)
+
+// RecoverOption configures panic recovery behavior.
+type RecoverOption func(*recoverConfig)
+
+type recoverConfig struct {
+	repanic bool
+	source  string
+}
+
+// WithRepanic re-raises the recovered panic after it has been recorded.
+func WithRepanic() RecoverOption {
+	return func(c *recoverConfig) {
+		c.repanic = true
+	}
+}
+
+// WithPanicSource tags the panic counter with the supplied source label.
+func WithPanicSource(source string) RecoverOption {
+	return func(c *recoverConfig) {
+		c.source = source
+	}
+}
+
+func newRecoverConfig(opts []RecoverOption) recoverConfig {
+	c := recoverConfig{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&c)
+		}
+	}
+	return c
+}
+
+// Recover recovers a panic using the global logger and meter.
+// It must be invoked directly via defer.
+func Recover(ctx context.Context, opts ...RecoverOption) {
+	if recovered := recover(); recovered != nil {
+		handlePanic(ctx, recovered, logger.Global(), meter.Global(), newRecoverConfig(opts))
+	}
+}
+
+// Recover recovers a panic, logs it with its stack, marks the active span as failed,
+// and increments the panic counter. It must be invoked directly via defer.
+// Falls back to the global components if receiver is nil.
+func (t *Telemetry) Recover(ctx context.Context, opts ...RecoverOption) {
+	if recovered := recover(); recovered != nil {
+		log, provider := t.panicTargets()
+		handlePanic(ctx, recovered, log, provider, newRecoverConfig(opts))
+	}
+}
+
+// RecoverHandler wraps next so that panics are recorded through the global components
+// and answered with 500 Internal Server Error.
+func RecoverHandler(next http.Handler, opts ...RecoverOption) http.Handler {
+	return recoverHandler(nil, next, opts)
+}
+
+// RecoverHandler wraps next so that panics are recorded and answered with 500 Internal Server Error.
+func (t *Telemetry) RecoverHandler(next http.Handler, opts ...RecoverOption) http.Handler {
+	return recoverHandler(t, next, opts)
+}
+
+// Go runs fn in a new goroutine, recording any panic through the global components.
+func Go(ctx context.Context, fn func(context.Context), opts ...RecoverOption) {
+	goRecover(nil, ctx, fn, opts)
+}
+
+// Go runs fn in a new goroutine, recording any panic it raises.
+func (t *Telemetry) Go(ctx context.Context, fn func(context.Context), opts ...RecoverOption) {
+	goRecover(t, ctx, fn, opts)
+}
+
+func recoverHandler(t *Telemetry, next http.Handler, opts []RecoverOption) http.Handler {
+	cfg := newRecoverConfig(opts)
+	if cfg.source == "" {
+		cfg.source = "http"
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+			log, provider := t.panicTargets()
+			handlePanic(r.Context(), recovered, log, provider, cfg)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+func goRecover(t *Telemetry, ctx context.Context, fn func(context.Context), opts []RecoverOption) {
+	if fn == nil {
+		return
+	}
+	cfg := newRecoverConfig(opts)
+	if cfg.source == "" {
+		cfg.source = "goroutine"
+	}
+	go func() {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				log, provider := t.panicTargets()
+				handlePanic(ctx, recovered, log, provider, cfg)
+			}
+		}()
+		fn(ctx)
+	}()
+}
+
+func (t *Telemetry) panicTargets() (*logger.Logger, *meter.Provider) {
+	if t == nil {
+		return logger.Global(), meter.Global()
+	}
+	return t.Logger(), t.Meter()
+}
+
+func handlePanic(ctx context.Context, recovered any, log *logger.Logger, provider *meter.Provider, cfg recoverConfig) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	_ = log.LogPanic(ctx, recovered)
+	recordPanic(ctx, provider, cfg.source)
+
+	if cfg.repanic {
+		panic(recovered)
+	}
+}
+
+func recordPanic(ctx context.Context, provider *meter.Provider, source string) {
+	counter, err := provider.Meter(panicInstrumentation).Int64Counter(
+		panicCounterName,
+		metric.WithDescription("Number of recovered panics"),
+		metric.WithUnit("{panic}"),
+	)
+	if err != nil {
+		return
+	}
+	var opts []metric.AddOption
+	if source != "" {
+		opts = append(opts, metric.WithAttributes(attribute.String("panic.source", source)))
+	}
+	counter.Add(ctx, 1, opts...)
+}
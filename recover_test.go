@@ -0,0 +1,165 @@
+package goo11y
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mfahmialkautsar/goo11y/internal/testutil/inmemory"
+	"github.com/mfahmialkautsar/goo11y/logger"
+	"github.com/mfahmialkautsar/goo11y/meter"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func newRecoverTelemetry(t *testing.T) (*Telemetry, *bytes.Buffer, *sdkmetric.ManualReader) {
+	t.Helper()
+	var buf bytes.Buffer
+	log, err := logger.New(context.Background(), logger.Config{
+		Enabled:     true,
+		ServiceName: "recover-test",
+		Console:     false,
+		Writers:     []io.Writer{&buf},
+	})
+	if err != nil {
+		t.Fatalf("logger.New: %v", err)
+	}
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	t.Cleanup(func() {
+		_ = mp.Shutdown(context.Background())
+		_ = log.Close()
+	})
+	return FromComponents(log, nil, meter.NewProvider(mp), nil), &buf, reader
+}
+
+func panicCount(t *testing.T, reader *sdkmetric.ManualReader) int64 {
+	t.Helper()
+	rm, err := inmemory.GetMetrics(context.Background(), reader)
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	m, ok := inmemory.FindMetricByName(rm, panicCounterName)
+	if !ok {
+		return 0
+	}
+	sum, ok := m.Data.(metricdata.Sum[int64])
+	if !ok {
+		t.Fatalf("unexpected data type %T", m.Data)
+	}
+	var total int64
+	for _, dp := range sum.DataPoints {
+		total += dp.Value
+	}
+	return total
+}
+
+func TestTelemetryRecoverSwallowsPanic(t *testing.T) {
+	tele, buf, reader := newRecoverTelemetry(t)
+
+	func() {
+		defer tele.Recover(context.Background())
+		panic("swallowed")
+	}()
+
+	if !strings.Contains(buf.String(), "swallowed") {
+		t.Fatalf("expected panic to be logged, got %q", buf.String())
+	}
+	if got := panicCount(t, reader); got != 1 {
+		t.Fatalf("expected panic counter 1, got %d", got)
+	}
+}
+
+func TestTelemetryRecoverRepanics(t *testing.T) {
+	tele, _, reader := newRecoverTelemetry(t)
+
+	defer func() {
+		if recovered := recover(); recovered != "again" {
+			t.Fatalf("expected repanic value, got %v", recovered)
+		}
+		if got := panicCount(t, reader); got != 1 {
+			t.Fatalf("expected panic counter 1, got %d", got)
+		}
+	}()
+
+	func() {
+		defer tele.Recover(context.Background(), WithRepanic())
+		panic("again")
+	}()
+}
+
+func TestTelemetryRecoverHandlerResponds500(t *testing.T) {
+	tele, buf, reader := newRecoverTelemetry(t)
+
+	handler := tele.RecoverHandler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("handler exploded")
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d", rec.Code)
+	}
+	if !strings.Contains(buf.String(), "handler exploded") {
+		t.Fatalf("expected panic to be logged, got %q", buf.String())
+	}
+	if got := panicCount(t, reader); got != 1 {
+		t.Fatalf("expected panic counter 1, got %d", got)
+	}
+}
+
+func TestTelemetryGoRecoversGoroutinePanic(t *testing.T) {
+	tele, _, reader := newRecoverTelemetry(t)
+
+	done := make(chan struct{})
+	tele.Go(context.Background(), func(context.Context) {
+		defer close(done)
+		panic("goroutine")
+	})
+	<-done
+
+	deadline := time.Now().Add(2 * time.Second)
+	for panicCount(t, reader) != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected goroutine panic to be counted")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRecoverHandlerRepanicWritesNoCrashReport(t *testing.T) {
+	dir := t.TempDir()
+	log, err := logger.New(context.Background(), logger.Config{
+		Enabled:     true,
+		ServiceName: "recover-test",
+		Console:     false,
+		Writers:     []io.Writer{io.Discard},
+		Crash:       logger.CrashConfig{Directory: dir},
+	})
+	if err != nil {
+		t.Fatalf("logger.New: %v", err)
+	}
+	t.Cleanup(func() { _ = log.Close() })
+	tele := FromComponents(log, nil, nil, nil)
+
+	handler := tele.RecoverHandler(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		panic("handler exploded")
+	}), WithRepanic())
+	// net/http recovers the re-raised panic and keeps serving.
+	func() {
+		defer func() { _ = recover() }()
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	}()
+
+	// The logger keeps its runtime crash output open in dir, so only reports are counted.
+	if reports, _ := filepath.Glob(filepath.Join(dir, "crash-*.json")); len(reports) != 0 {
+		t.Fatalf("expected no crash report for a recovered panic, got %v", reports)
+	}
+}
@@ -0,0 +1,306 @@
+package goo11y
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/mfahmialkautsar/goo11y/logger"
+	"github.com/mfahmialkautsar/goo11y/meter"
+	"github.com/mfahmialkautsar/goo11y/tracer"
+	"go.opentelemetry.io/otel"
+)
+
+// component indexes the lifecycle slots of a Telemetry, in setup order.
+type component int
+
+const (
+	componentLogger component = iota
+	componentTracer
+	componentMeter
+	componentProfiler
+	componentCount
+)
+
+// setCloser installs fn as the shutdown function of c. The first installation registers a shutdown
+// hook that dispatches to the current closer, so Reload can swap closers without reordering hooks.
+func (t *Telemetry) setCloser(c component, fn func(context.Context) error) {
+	t.closers[c] = fn
+	if fn == nil || t.hooked[c] {
+		return
+	}
+	t.hooked[c] = true
+	t.shutdownHooks = append(t.shutdownHooks, func(ctx context.Context) error {
+		if closer := t.closers[c]; closer != nil {
+			return closer(ctx)
+		}
+		return nil
+	})
+}
+
+// Reload applies cfg to a running Telemetry, rebuilding only the components whose configuration
+// changed. The Options passed to New are reused unless opts is non-empty. Exporters and readers
+// supplied through Options are shut down with the component that owns them, so pass fresh ones in
+// opts whenever the tracer or meter they belong to is rebuilt. A replacement is started before its
+// predecessor is shut down, so telemetry buffered by the old component is flushed rather than
+// dropped. The profiler is the exception: it is stopped first because the runtime allows a single
+// CPU profile at a time.
+//
+// A resource change rebuilds the tracer and meter, and a logger change also rebuilds the profiler,
+// which logs through it. Pointers such as writers and detectors compare by identity.
+// Function-valued settings such as Resource.Override never compare equal, so they rebuild their
+// components on every reload. Components obtained from the accessors before Reload are shut down
+// when replaced; call the accessors again afterwards or use the globals (UseGlobal). When a
+// component fails to build, the ones already replaced are rolled back and the previous
+// configuration stays in effect.
+// No-op if receiver is nil.
+func (t *Telemetry) Reload(ctx context.Context, cfg Config, opts ...Option) error {
+	if t == nil {
+		return nil
+	}
+	cfg.applyDefaults()
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+
+	t.reloadMu.Lock()
+	defer t.reloadMu.Unlock()
+	applyExportFailureInterval(cfg)
+
+	c := t.opts
+	if len(opts) > 0 {
+		c = config{}
+		for _, opt := range opts {
+			opt(&c)
+		}
+	}
+
+	prev := t.cfg
+	resourceChanged := !sameConfig(prev.Resource, cfg.Resource) || !sameConfig(prev.Customizers, cfg.Customizers)
+	var changed [componentCount]bool
+	changed[componentLogger] = !sameConfig(prev.Logger, cfg.Logger)
+	changed[componentTracer] = resourceChanged || !sameConfig(prev.Tracer, cfg.Tracer)
+	changed[componentMeter] = resourceChanged || !sameConfig(prev.Meter, cfg.Meter)
+	changed[componentProfiler] = !sameConfig(prev.Profiler, cfg.Profiler) ||
+		(changed[componentLogger] && (prev.Profiler.Enabled || cfg.Profiler.Enabled))
+	if changed == [componentCount]bool{} {
+		t.cfg = cfg
+		t.opts = c
+		return nil
+	}
+
+	hadLoggerMetrics := t.Logger() != nil && t.Meter() != nil && prev.Logger.File.Enabled
+
+	stage := &Telemetry{}
+	stage.logger.Store(t.Logger())
+	restore := snapshotGlobals()
+	rollback := func() {
+		for c := componentProfiler; c >= componentLogger; c-- {
+			if changed[c] && stage.closers[c] != nil {
+				_ = stage.closers[c](ctx)
+			}
+		}
+		restore()
+	}
+
+	if changed[componentLogger] {
+		stage.logger.Store(nil)
+		if err := setupLogger(ctx, &cfg, &c, stage); err != nil {
+			return err
+		}
+	}
+	if changed[componentTracer] || changed[componentMeter] {
+		res, err := buildResource(ctx, cfg, c.resources...)
+		if err != nil {
+			rollback()
+			return fmt.Errorf("build resource: %w", err)
+		}
+		if changed[componentTracer] {
+			if err := setupTracer(ctx, &cfg, &c, stage, res); err != nil {
+				rollback()
+				return err
+			}
+		}
+		if changed[componentMeter] {
+			if err := setupMeter(ctx, &cfg, &c, stage, res); err != nil {
+				rollback()
+				return err
+			}
+		}
+	}
+	if changed[componentProfiler] {
+		if err := t.replaceProfiler(ctx, prev, cfg, &c, stage); err != nil {
+			rollback()
+			return err
+		}
+	}
+
+	var old [componentCount]func(context.Context) error
+	for c := componentLogger; c < componentCount; c++ {
+		if !changed[c] {
+			continue
+		}
+		if c != componentProfiler {
+			old[c] = t.closers[c]
+		}
+		t.setCloser(c, stage.closers[c])
+	}
+	if changed[componentLogger] {
+		t.logger.Store(stage.Logger())
+	}
+	if changed[componentTracer] {
+		t.tracer.Store(stage.Tracer())
+	}
+	if changed[componentMeter] {
+		t.meter.Store(stage.Meter())
+	}
+	if changed[componentProfiler] {
+		t.profiler.Store(stage.Profiler())
+	}
+	t.cfg = cfg
+	t.opts = c
+	if Global() == t {
+		Use(t)
+	}
+
+	if changed[componentTracer] || changed[componentProfiler] {
+		t.registerTraceProfile(cfg)
+	}
+	if changed[componentTracer] || changed[componentMeter] {
+		t.registerSpanMetrics(ctx, cfg)
+	}
+	if changed[componentTracer] || changed[componentProfiler] {
+		t.registerSpanLabels(cfg)
+	}
+	if changed[componentTracer] {
+		t.registerContextProcessors()
+	}
+	if t.Logger() != nil && t.Meter() != nil && cfg.Logger.File.Enabled && (changed[componentMeter] || !hadLoggerMetrics) {
+		if err := t.registerLoggerMetrics(); err != nil {
+			t.emitWarn(ctx, "register logger metrics", err)
+		}
+	}
+	if t.Logger() != nil && t.Meter() != nil && (cfg.Logger.MessageMetrics.Enabled || cfg.Logger.File.Enabled) && (changed[componentLogger] || changed[componentMeter]) {
+		if err := t.Logger().BindMeter(t.Meter().Meter(loggerMetricsInstrumentation)); err != nil {
+			t.emitWarn(ctx, "bind logger metrics", err)
+		}
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownGracePeriod)
+	defer cancel()
+	var errs error
+	for c := componentProfiler; c >= componentLogger; c-- {
+		if old[c] != nil {
+			errs = errors.Join(errs, old[c](shutdownCtx))
+		}
+	}
+	t.emitWarn(ctx, "shut down replaced components", errs)
+	return nil
+}
+
+// replaceProfiler stops the running profiler and starts one from cfg on stage. If the new profiler
+// cannot start, the previous configuration is restarted in its place.
+func (t *Telemetry) replaceProfiler(ctx context.Context, prev, cfg Config, c *config, stage *Telemetry) error {
+	if closer := t.closers[componentProfiler]; closer != nil {
+		t.emitWarn(ctx, "stop profiler", closer(ctx))
+	}
+	t.closers[componentProfiler] = nil
+	t.profiler.Store(nil)
+
+	err := setupProfiler(&cfg, c, stage)
+	if err == nil {
+		return nil
+	}
+	restored := &Telemetry{}
+	restored.logger.Store(t.Logger())
+	if restoreErr := setupProfiler(&prev, &t.opts, restored); restoreErr != nil {
+		t.emitWarn(ctx, "restart previous profiler", restoreErr)
+	} else {
+		t.profiler.Store(restored.Profiler())
+		t.setCloser(componentProfiler, restored.closers[componentProfiler])
+	}
+	return err
+}
+
+// snapshotGlobals captures the package and OpenTelemetry globals that logger, tracer and meter setup
+// may replace and returns a function restoring them. The profiler restores its own, see replaceProfiler.
+func snapshotGlobals() func() {
+	log := logger.Global()
+	tp := tracer.Global()
+	mp := meter.Global()
+	otelTracer := otel.GetTracerProvider()
+	otelPropagator := otel.GetTextMapPropagator()
+	otelMeter := otel.GetMeterProvider()
+	return func() {
+		logger.Use(log)
+		tracer.Use(tp)
+		meter.Use(mp)
+		otel.SetTracerProvider(otelTracer)
+		otel.SetTextMapPropagator(otelPropagator)
+		otel.SetMeterProvider(otelMeter)
+	}
+}
+
+// sameConfig reports whether a and b hold the same settings. Unlike reflect.DeepEqual, pointers and
+// channels compare by identity, so a shared writer counts as unchanged however its state evolves.
+// Functions are equal only when both are nil.
+func sameConfig(a, b any) bool {
+	return sameValue(reflect.ValueOf(a), reflect.ValueOf(b))
+}
+
+func sameValue(a, b reflect.Value) bool {
+	if !a.IsValid() || !b.IsValid() {
+		return a.IsValid() == b.IsValid()
+	}
+	if a.Type() != b.Type() {
+		return false
+	}
+	switch a.Kind() {
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		return a.Pointer() == b.Pointer()
+	case reflect.Func:
+		return a.IsNil() && b.IsNil()
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return sameValue(a.Elem(), b.Elem())
+	case reflect.Slice:
+		if a.IsNil() != b.IsNil() {
+			return false
+		}
+		fallthrough
+	case reflect.Array:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for i := 0; i < a.Len(); i++ {
+			if !sameValue(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		if a.IsNil() != b.IsNil() || a.Len() != b.Len() {
+			return false
+		}
+		iter := a.MapRange()
+		for iter.Next() {
+			other := b.MapIndex(iter.Key())
+			if !other.IsValid() || !sameValue(iter.Value(), other) {
+				return false
+			}
+		}
+		return true
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if !sameValue(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a.Equal(b)
+	}
+}
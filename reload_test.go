@@ -0,0 +1,171 @@
+package goo11y
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/mfahmialkautsar/goo11y/logger"
+	"github.com/mfahmialkautsar/goo11y/meter"
+	"github.com/mfahmialkautsar/goo11y/tracer"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func reloadTestConfig(buf *bytes.Buffer) Config {
+	return Config{
+		Resource: ResourceConfig{ServiceName: "reload-svc"},
+		Logger: logger.Config{
+			Enabled: true,
+			Level:   "info",
+			Console: false,
+			Writers: []io.Writer{buf},
+		},
+		Tracer: tracer.Config{Enabled: true},
+		Meter: meter.Config{
+			Enabled:  true,
+			Endpoint: "localhost:4318",
+		},
+	}
+}
+
+func TestReloadRebuildsOnlyChangedComponents(t *testing.T) {
+	var buf bytes.Buffer
+	exporter := tracetest.NewInMemoryExporter()
+	tele, err := New(context.Background(), reloadTestConfig(&buf),
+		WithTracerOption(tracer.WithSpanExporter(exporter)),
+		WithMeterOption(meter.WithMetricReader(sdkmetric.NewManualReader())),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = tele.Shutdown(context.Background()) })
+
+	oldLogger, oldTracer, oldMeter := tele.Logger(), tele.Tracer(), tele.Meter()
+	hooks := len(tele.shutdownHooks)
+
+	tele.Logger().Info().Msg("before reload")
+	cfg := reloadTestConfig(&buf)
+	cfg.Logger.Level = "debug"
+	if err := tele.Reload(context.Background(), cfg); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+
+	if tele.Logger() == oldLogger {
+		t.Fatal("expected logger to be rebuilt")
+	}
+	if tele.Tracer() != oldTracer || tele.Meter() != oldMeter {
+		t.Fatal("tracer and meter should be kept when their config is unchanged")
+	}
+	if len(tele.shutdownHooks) != hooks {
+		t.Fatalf("reload should not add shutdown hooks, got %d want %d", len(tele.shutdownHooks), hooks)
+	}
+
+	tele.Logger().Debug().Msg("after reload")
+	if !strings.Contains(buf.String(), "after reload") {
+		t.Fatalf("expected debug entry after reload, got %q", buf.String())
+	}
+}
+
+func TestReloadReplacesTracerOnResourceChange(t *testing.T) {
+	var buf bytes.Buffer
+	first := tracetest.NewInMemoryExporter()
+	tele, err := New(context.Background(), reloadTestConfig(&buf),
+		WithTracerOption(tracer.WithSpanExporter(first)),
+		WithMeterOption(meter.WithMetricReader(sdkmetric.NewManualReader())),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = tele.Shutdown(context.Background()) })
+
+	oldTracer, oldLogger := tele.Tracer(), tele.Logger()
+	_, span := tele.Tracer().Tracer("reload").Start(context.Background(), "in-flight")
+	span.End()
+
+	second := tracetest.NewInMemoryExporter()
+	cfg := reloadTestConfig(&buf)
+	cfg.Resource.ServiceVersion = "2.0.0"
+	err = tele.Reload(context.Background(), cfg,
+		WithTracerOption(tracer.WithSpanExporter(second)),
+		WithMeterOption(meter.WithMetricReader(sdkmetric.NewManualReader())),
+	)
+	if err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if tele.Tracer() == oldTracer {
+		t.Fatal("expected tracer to be rebuilt after resource change")
+	}
+	if tele.Logger() != oldLogger {
+		t.Fatal("logger should be kept when its config is unchanged")
+	}
+
+	_, span = tele.Tracer().Tracer("reload").Start(context.Background(), "after")
+	span.End()
+	if err := tele.Tracer().ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+	spans := second.GetSpans()
+	if len(spans) != 1 || spans[0].Name != "after" {
+		t.Fatalf("expected new exporter to receive span, got %v", spans)
+	}
+	if got := spans[0].Resource.String(); !strings.Contains(got, "2.0.0") {
+		t.Fatalf("expected new resource, got %s", got)
+	}
+}
+
+func TestReloadRejectsInvalidConfig(t *testing.T) {
+	var buf bytes.Buffer
+	tele, err := New(context.Background(), Config{
+		Logger: logger.Config{Enabled: true, Console: false, Writers: []io.Writer{&buf}},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = tele.Shutdown(context.Background()) })
+	oldLogger := tele.Logger()
+
+	err = tele.Reload(context.Background(), Config{
+		Logger: logger.Config{Enabled: true, Console: false, Level: "info", Format: "xml"},
+	})
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+	if tele.Logger() != oldLogger {
+		t.Fatal("logger should be untouched after a failed reload")
+	}
+}
+
+func TestReloadNil(t *testing.T) {
+	var tele *Telemetry
+	if err := tele.Reload(context.Background(), Config{}); err != nil {
+		t.Fatalf("expected nil error reloading nil telemetry: %v", err)
+	}
+}
+
+func TestSameConfigComparesPointersByIdentity(t *testing.T) {
+	var buf bytes.Buffer
+	a := logger.Config{Level: "info", Writers: []io.Writer{&buf}}
+	b := logger.Config{Level: "info", Writers: []io.Writer{&buf}}
+	if !sameConfig(a, b) {
+		t.Fatal("expected configs sharing a writer to compare equal")
+	}
+	buf.WriteString("state changes do not matter")
+	if !sameConfig(a, b) {
+		t.Fatal("writer state should not affect equality")
+	}
+
+	var other bytes.Buffer
+	b.Writers = []io.Writer{&other}
+	if sameConfig(a, b) {
+		t.Fatal("expected different writers to compare unequal")
+	}
+
+	withFunc := ResourceConfig{Override: func(context.Context) (*sdkresource.Resource, error) { return nil, nil }}
+	if sameConfig(withFunc, withFunc) {
+		t.Fatal("function-valued settings should never compare equal")
+	}
+}
@@ -0,0 +1,99 @@
+package goo11y
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/mfahmialkautsar/goo11y/logger"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RequestIDHeader is the header RequestIDHandler adopts the request ID from and echoes it in.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDKey is the span attribute carrying the request ID.
+const RequestIDKey = attribute.Key("request.id")
+
+const maxRequestIDLength = 128
+
+// NewRequestID returns a random 32-character hex request ID.
+func NewRequestID() string {
+	var id [16]byte
+	_, _ = rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
+
+// ContextWithRequestID returns ctx carrying id for correlation that works even when tracing is off
+// or the trace is not sampled: entries logged with the context record it as request_id, it travels
+// to downstream services as the request.id baggage member, and the active span and spans started
+// from ctx under a Telemetry tracer get the request.id attribute. Empty ids leave ctx unchanged.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	ctx = logger.ContextWithRequestID(ctx, id)
+	if member, err := baggage.NewMemberRaw(logger.RequestIDBaggageKey, id); err == nil {
+		if bag, err := baggage.FromContext(ctx).SetMember(member); err == nil {
+			ctx = baggage.ContextWithBaggage(ctx, bag)
+		}
+	}
+	trace.SpanFromContext(ctx).SetAttributes(RequestIDKey.String(id))
+	return ctx
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, from ContextWithRequestID or from
+// baggage received from an upstream service, or "" when there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	return logger.RequestIDFromContext(ctx)
+}
+
+// RequestIDHandler wraps next so every request carries a request ID: the incoming X-Request-ID
+// header when it is present and at most 128 printable ASCII characters, a new ID otherwise. The ID
+// is stored with ContextWithRequestID and echoed in the X-Request-ID response header.
+func RequestIDHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = NewRequestID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(ContextWithRequestID(r.Context(), id)))
+	})
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// RequestIDSpanProcessor returns a span processor adding the request.id attribute to spans whose
+// parent context carries a request ID. Telemetry registers it on its tracer provider.
+func RequestIDSpanProcessor() sdktrace.SpanProcessor {
+	return requestIDSpanProcessor{}
+}
+
+type requestIDSpanProcessor struct{}
+
+func (requestIDSpanProcessor) OnStart(ctx context.Context, span sdktrace.ReadWriteSpan) {
+	if id := RequestIDFromContext(ctx); id != "" {
+		span.SetAttributes(RequestIDKey.String(id))
+	}
+}
+
+func (requestIDSpanProcessor) OnEnd(sdktrace.ReadOnlySpan) {}
+
+func (requestIDSpanProcessor) Shutdown(context.Context) error { return nil }
+
+func (requestIDSpanProcessor) ForceFlush(context.Context) error { return nil }
@@ -0,0 +1,99 @@
+package goo11y
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mfahmialkautsar/goo11y/logger"
+	"go.opentelemetry.io/otel/baggage"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestRequestIDHandlerCorrelatesLogsAndSpans(t *testing.T) {
+	var buf bytes.Buffer
+	log, err := logger.New(context.Background(), logger.Config{
+		Enabled: true,
+		Console: false,
+		Writers: []io.Writer{&buf},
+	})
+	if err != nil {
+		t.Fatalf("logger.New: %v", err)
+	}
+	t.Cleanup(func() { _ = log.Close() })
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.NeverSample()),
+		sdktrace.WithSpanProcessor(RequestIDSpanProcessor()),
+		sdktrace.WithSpanProcessor(recorder),
+	)
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	sampledTP := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(RequestIDSpanProcessor()),
+		sdktrace.WithSpanProcessor(recorder),
+	)
+	t.Cleanup(func() { _ = sampledTP.Shutdown(context.Background()) })
+
+	var seen string
+	handler := RequestIDHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		seen = RequestIDFromContext(ctx)
+		// Unsampled: no span exists in the backend, the log still correlates.
+		_, unsampled := tp.Tracer("test").Start(ctx, "unsampled")
+		unsampled.End()
+		log.Info().Ctx(ctx).Msg("handled")
+		if got := baggage.FromContext(ctx).Member(logger.RequestIDBaggageKey).Value(); got != seen {
+			t.Errorf("expected the request ID in baggage, got %q", got)
+		}
+		_, child := sampledTP.Tracer("test").Start(ctx, "child")
+		child.End()
+	}))
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDHeader, "req-42")
+	handler.ServeHTTP(rec, req)
+
+	if seen != "req-42" || rec.Header().Get(RequestIDHeader) != "req-42" {
+		t.Fatalf("expected the incoming request ID adopted and echoed, got %q and %q", seen, rec.Header().Get(RequestIDHeader))
+	}
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if entry[logger.RequestIDField] != "req-42" {
+		t.Fatalf("expected request_id in the log entry, got %v", entry[logger.RequestIDField])
+	}
+	ended := recorder.Ended()
+	if len(ended) != 1 || ended[0].Name() != "child" {
+		t.Fatalf("expected only the sampled child span, got %d spans", len(ended))
+	}
+	var tagged bool
+	for _, attr := range ended[0].Attributes() {
+		tagged = tagged || (attr.Key == RequestIDKey && attr.Value.AsString() == "req-42")
+	}
+	if !tagged {
+		t.Fatalf("expected the child span tagged with request.id, got %v", ended[0].Attributes())
+	}
+}
+
+func TestRequestIDHandlerReplacesInvalidIDs(t *testing.T) {
+	for _, incoming := range []string{"", "has space", string(bytes.Repeat([]byte("a"), 129))} {
+		var seen string
+		handler := RequestIDHandler(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			seen = RequestIDFromContext(r.Context())
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(RequestIDHeader, incoming)
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+		if len(seen) != 32 || seen == incoming {
+			t.Fatalf("expected a generated ID for %q, got %q", incoming, seen)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package goo11y
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ScrubbedValue replaces the credentials, query values, and header values left out by HTTPScrubber.
+const ScrubbedValue = "REDACTED"
+
+// sensitiveHeaders are never recorded, even when allowed.
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// HTTPScrubber decides which parts of an HTTP request or response may be recorded on spans and log
+// entries. The zero value is the safe default: URL credentials and every query value are redacted,
+// and no header is recorded. AllowQueryParams names query parameters whose values are kept, and
+// AllowHeaders names headers that are recorded, matched case-insensitively. Authorization,
+// Proxy-Authorization, Cookie, and Set-Cookie are redacted even when allowed.
+type HTTPScrubber struct {
+	AllowQueryParams []string
+	AllowHeaders     []string
+}
+
+// URL returns u as a string with the user info and the values of query parameters outside
+// AllowQueryParams replaced by REDACTED. The fragment is dropped.
+func (s HTTPScrubber) URL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	scrubbed := *u
+	scrubbed.Fragment = ""
+	scrubbed.RawFragment = ""
+	if scrubbed.User != nil {
+		if _, hasPassword := scrubbed.User.Password(); hasPassword {
+			scrubbed.User = url.UserPassword(ScrubbedValue, ScrubbedValue)
+		} else {
+			scrubbed.User = url.User(ScrubbedValue)
+		}
+	}
+	if scrubbed.RawQuery != "" {
+		scrubbed.RawQuery = s.query(scrubbed.RawQuery)
+	}
+	return scrubbed.String()
+}
+
+// query redacts the values of raw, keeping the parameter order and the allowed values.
+func (s HTTPScrubber) query(raw string) string {
+	pairs := strings.Split(raw, "&")
+	for idx, pair := range pairs {
+		key, _, hasValue := strings.Cut(pair, "=")
+		if !hasValue {
+			continue
+		}
+		name, err := url.QueryUnescape(key)
+		if err != nil || !containsFold(s.AllowQueryParams, name) {
+			pairs[idx] = key + "=" + ScrubbedValue
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// HeaderAttributes returns the allowed headers of h as attributes named prefix followed by the
+// lower-cased header name, such as http.request.header.x-request-id, in name order. Pass
+// "http.request.header." or "http.response.header.".
+func (s HTTPScrubber) HeaderAttributes(prefix string, h http.Header) []attribute.KeyValue {
+	if len(s.AllowHeaders) == 0 || len(h) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(s.AllowHeaders))
+	for name := range h {
+		if containsFold(s.AllowHeaders, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	attrs := make([]attribute.KeyValue, 0, len(names))
+	for _, name := range names {
+		values := h.Values(name)
+		if containsFold(sensitiveHeaders, name) {
+			values = slices.Repeat([]string{ScrubbedValue}, len(values))
+		}
+		attrs = append(attrs, attribute.StringSlice(prefix+strings.ToLower(name), values))
+	}
+	return attrs
+}
+
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
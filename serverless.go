@@ -0,0 +1,32 @@
+package goo11y
+
+import (
+	"context"
+)
+
+// FlushAfter wraps a serverless invocation handler so that spans, metrics, and log records are
+// exported before it returns, even when the handler panics. The flush is bounded by the shutdown
+// grace period and does not inherit the invocation's cancellation; a failed flush is logged rather
+// than returned so it never fails the invocation. Falls back to running handler if receiver is nil.
+func (t *Telemetry) FlushAfter(handler func(context.Context) error) func(context.Context) error {
+	return func(ctx context.Context) error {
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		if t != nil {
+			defer t.flushInvocation(ctx)
+		}
+		if handler == nil {
+			return nil
+		}
+		return handler(ctx)
+	}
+}
+
+func (t *Telemetry) flushInvocation(ctx context.Context) {
+	flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownGracePeriod)
+	defer cancel()
+	if err := t.ForceFlush(flushCtx); err != nil {
+		t.emitWarn(ctx, "flush invocation", err)
+	}
+}
@@ -0,0 +1,95 @@
+package goo11y
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/mfahmialkautsar/goo11y/auth"
+	"github.com/mfahmialkautsar/goo11y/tracer"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestServerlessEnablesSynchronousExport(t *testing.T) {
+	cfg := Config{Serverless: true}
+	cfg.applyDefaults()
+	if !cfg.Tracer.SyncExport || !cfg.Meter.ManualExport || !cfg.Logger.OTLP.SyncExport || !cfg.Logger.Audit.OTLP.SyncExport {
+		t.Fatalf("expected serverless to enable synchronous export, got tracer=%v meter=%v logger=%v audit=%v",
+			cfg.Tracer.SyncExport, cfg.Meter.ManualExport, cfg.Logger.OTLP.SyncExport, cfg.Logger.Audit.OTLP.SyncExport)
+	}
+}
+
+func TestServerlessBypassesSpools(t *testing.T) {
+	cfg := PresetProduction("https://otlp.example.com", auth.Credentials{})
+	cfg.Resource.ServiceName = "checkout"
+	cfg.Serverless = true
+
+	report := ValidateConfig(cfg)
+	var fields []string
+	for _, warning := range report.Warnings {
+		fields = append(fields, warning.Field)
+	}
+	if !slices.Contains(fields, "Logger.OTLP.UseSpool") || !slices.Contains(fields, "Meter.UseSpool") {
+		t.Fatalf("expected warnings about the bypassed spools, got %v", report.Warnings)
+	}
+
+	cfg.applyDefaults()
+	if cfg.Logger.OTLP.UseSpool || cfg.Logger.Audit.OTLP.UseSpool || cfg.Meter.UseSpool {
+		t.Fatalf("expected serverless to bypass the spools, got logger=%v audit=%v meter=%v",
+			cfg.Logger.OTLP.UseSpool, cfg.Logger.Audit.OTLP.UseSpool, cfg.Meter.UseSpool)
+	}
+}
+
+func TestFlushAfterFlushesOnReturnAndPanic(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tele, err := New(context.Background(), Config{
+		Tracer: tracer.Config{Enabled: true},
+	}, WithTracerOption(tracer.WithSpanExporter(exporter)))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = tele.Shutdown(context.Background()) })
+
+	failure := errors.New("handler failed")
+	handler := tele.FlushAfter(func(ctx context.Context) error {
+		_, span := tele.Tracer().Tracer("serverless").Start(ctx, "invocation")
+		span.End()
+		return failure
+	})
+	if err := handler(context.Background()); !errors.Is(err, failure) {
+		t.Fatalf("expected handler error, got %v", err)
+	}
+	if got := len(exporter.GetSpans()); got != 1 {
+		t.Fatalf("expected span flushed after invocation, got %d", got)
+	}
+
+	panicking := tele.FlushAfter(func(ctx context.Context) error {
+		_, span := tele.Tracer().Tracer("serverless").Start(ctx, "panicking")
+		span.End()
+		panic("boom")
+	})
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatal("expected panic to propagate")
+			}
+		}()
+		_ = panicking(context.Background())
+	}()
+	if got := len(exporter.GetSpans()); got != 2 {
+		t.Fatalf("expected span flushed despite panic, got %d", got)
+	}
+}
+
+func TestFlushAfterNilTelemetry(t *testing.T) {
+	var tele *Telemetry
+	called := false
+	err := tele.FlushAfter(func(context.Context) error {
+		called = true
+		return nil
+	})(context.Background())
+	if err != nil || !called {
+		t.Fatalf("expected handler to run without telemetry, called=%v err=%v", called, err)
+	}
+}
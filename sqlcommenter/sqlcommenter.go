@@ -0,0 +1,130 @@
+// Package sqlcommenter appends sqlcommenter-style comments carrying the trace context to SQL
+// statements, so database-side tools such as Cloud SQL Insights or pg_stat_statements samples can
+// be correlated with the trace that issued the query. Call Comment on each statement before handing
+// it to the driver, or wrap the query methods of a client with it.
+package sqlcommenter
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Well-known tag keys from the sqlcommenter specification.
+const (
+	TagApplication = "application"
+	TagController  = "controller"
+	TagAction      = "action"
+	TagRoute       = "route"
+	TagDBDriver    = "db_driver"
+	TagFramework   = "framework"
+)
+
+// Option configures a Commenter.
+type Option func(*Commenter)
+
+// WithTags adds static tags, such as TagApplication or TagDBDriver, to every comment.
+func WithTags(tags map[string]string) Option {
+	return func(c *Commenter) {
+		for key, value := range tags {
+			c.tags[key] = value
+		}
+	}
+}
+
+// WithPropagator writes the trace context fields through propagator instead of W3C trace context.
+// Every field it injects, such as traceparent, tracestate, or baggage, becomes a tag.
+func WithPropagator(propagator propagation.TextMapPropagator) Option {
+	return func(c *Commenter) {
+		c.propagator = propagator
+	}
+}
+
+// Commenter renders sqlcommenter comments.
+type Commenter struct {
+	tags       map[string]string
+	propagator propagation.TextMapPropagator
+}
+
+// New creates a Commenter. Without WithPropagator it writes traceparent and tracestate only, so
+// baggage does not leak into query logs.
+func New(opts ...Option) *Commenter {
+	c := &Commenter{tags: make(map[string]string), propagator: propagation.TraceContext{}}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(c)
+		}
+	}
+	if c.propagator == nil {
+		c.propagator = propagation.TraceContext{}
+	}
+	return c
+}
+
+var defaultCommenter = New()
+
+// Comment appends the trace context of ctx to query with the default Commenter.
+func Comment(ctx context.Context, query string) string {
+	return defaultCommenter.Comment(ctx, query)
+}
+
+// Comment appends a comment holding the static tags and the trace context of ctx to query, before a
+// trailing semicolon. Queries that already contain a comment are returned unchanged, as the
+// specification requires, and so is query when there is nothing to add.
+func (c *Commenter) Comment(ctx context.Context, query string) string {
+	if strings.Contains(query, "/*") || strings.Contains(query, "--") {
+		return query
+	}
+	comment := c.Tags(ctx)
+	if comment == "" {
+		return query
+	}
+	trimmed := strings.TrimRight(query, " \t\r\n")
+	if body, ok := strings.CutSuffix(trimmed, ";"); ok {
+		return body + " " + comment + ";"
+	}
+	return trimmed + " " + comment
+}
+
+// Tags returns the comment Comment appends, such as /*traceparent='00-…-01'*/, or "" when there are
+// no tags and ctx carries no trace context. It suits clients that attach comments themselves.
+func (c *Commenter) Tags(ctx context.Context) string {
+	fields := make(map[string]string, len(c.tags)+2)
+	for key, value := range c.tags {
+		fields[key] = value
+	}
+	if ctx != nil {
+		c.propagator.Inject(ctx, propagation.MapCarrier(fields))
+	}
+	if len(fields) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	b.WriteString("/*")
+	for idx, key := range keys {
+		if idx > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(escape(key))
+		b.WriteString("='")
+		b.WriteString(escape(fields[key]))
+		b.WriteByte('\'')
+	}
+	b.WriteString("*/")
+	return b.String()
+}
+
+// escape URL-encodes s with spaces as %20, as the specification requires. Quotes are encoded too,
+// so values need no further escaping inside their quotes.
+func escape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
@@ -0,0 +1,60 @@
+package sqlcommenter
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func spanContext(t *testing.T) context.Context {
+	t.Helper()
+	traceID, _ := trace.TraceIDFromHex("0af7651916cd43dd8448eb211c80319c")
+	spanID, _ := trace.SpanIDFromHex("b7ad6b7169203331")
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	})
+	return trace.ContextWithSpanContext(context.Background(), sc)
+}
+
+func TestCommentAppendsTraceContext(t *testing.T) {
+	ctx := spanContext(t)
+
+	got := Comment(ctx, "SELECT * FROM users WHERE id = $1;")
+	want := "SELECT * FROM users WHERE id = $1 /*traceparent='00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01'*/;"
+	if got != want {
+		t.Fatalf("Comment:\n got %s\nwant %s", got, want)
+	}
+
+	if got := Comment(context.Background(), "SELECT 1"); got != "SELECT 1" {
+		t.Fatalf("query without trace context changed: %s", got)
+	}
+	commented := "SELECT 1 /* existing */"
+	if got := Comment(ctx, commented); got != commented {
+		t.Fatalf("query with a comment changed: %s", got)
+	}
+}
+
+func TestCommenterEscapesAndSortsTags(t *testing.T) {
+	c := New(
+		WithTags(map[string]string{
+			TagRoute:       "/users/{id}",
+			TagApplication: "billing api",
+			TagController:  "it's",
+		}),
+		WithPropagator(propagation.NewCompositeTextMapPropagator()),
+	)
+
+	got := c.Tags(spanContext(t))
+	want := `/*application='billing%20api',controller='it%27s',route='%2Fusers%2F%7Bid%7D'*/`
+	if got != want {
+		t.Fatalf("Tags:\n got %s\nwant %s", got, want)
+	}
+	if !strings.HasSuffix(c.Comment(context.Background(), "SELECT 1"), want) {
+		t.Fatal("expected static tags without trace context")
+	}
+}
@@ -0,0 +1,42 @@
+package goo11y
+
+import (
+	"github.com/mfahmialkautsar/goo11y/internal/otlputil"
+	"github.com/mfahmialkautsar/goo11y/logger"
+	"github.com/mfahmialkautsar/goo11y/meter"
+	"github.com/mfahmialkautsar/goo11y/tracer"
+)
+
+// Stats aggregates runtime counters from every configured component.
+type Stats struct {
+	Logger logger.Stats
+	Tracer tracer.Stats
+	Meter  meter.Stats
+	// ExportFailures counts export failures per "component/transport", such as "tracer/http",
+	// including those kept out of the logs by Config.ExportFailureLogInterval.
+	ExportFailures map[string]ExportFailureStats
+}
+
+// ExportFailureStats counts the export failures of one component and transport.
+type ExportFailureStats struct {
+	Reported   uint64
+	Suppressed uint64
+}
+
+// Stats returns a snapshot of the components' runtime counters.
+// Disabled components report zero values.
+func (t *Telemetry) Stats() Stats {
+	var stats Stats
+	if t == nil {
+		return stats
+	}
+	stats.Logger = t.Logger().Stats()
+	stats.Tracer = t.Tracer().Stats()
+	stats.Meter = t.Meter().Stats()
+	failures := otlputil.ExportFailures()
+	stats.ExportFailures = make(map[string]ExportFailureStats, len(failures))
+	for key, failure := range failures {
+		stats.ExportFailures[key] = ExportFailureStats(failure)
+	}
+	return stats
+}
@@ -0,0 +1,46 @@
+package goo11y
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mfahmialkautsar/goo11y/internal/otlputil"
+)
+
+func TestTelemetryStatsAggregatesComponents(t *testing.T) {
+	tele, _, _ := newRecoverTelemetry(t)
+
+	tele.Logger().Info().Msg("first")
+	tele.Logger().Error().Msg("second")
+
+	stats := tele.Stats()
+	if got := stats.Logger.Lines["info"]; got != 1 {
+		t.Fatalf("expected 1 info line, got %d", got)
+	}
+	if got := stats.Logger.Lines["error"]; got != 1 {
+		t.Fatalf("expected 1 error line, got %d", got)
+	}
+	if stats.Tracer.SpansExported != 0 || stats.Meter.ExportBatches != 0 {
+		t.Fatalf("expected zero counters for unconfigured components, got %+v", stats)
+	}
+}
+
+func TestTelemetryStatsNilReceiver(t *testing.T) {
+	var tele *Telemetry
+	stats := tele.Stats()
+	if stats.Logger.Lines != nil || stats.Tracer.SpansExported != 0 || stats.Meter.ExportBatches != 0 {
+		t.Fatalf("expected zero stats, got %+v", stats)
+	}
+}
+
+func TestTelemetryStatsReportsExportFailures(t *testing.T) {
+	tele, _, _ := newRecoverTelemetry(t)
+
+	otlputil.LogExportFailure("stats-test", "http", errors.New("down"))
+	otlputil.LogExportFailure("stats-test", "http", errors.New("down"))
+
+	got := tele.Stats().ExportFailures["stats-test/http"]
+	if got.Reported != 2 || got.Suppressed != 1 {
+		t.Fatalf("unexpected export failure stats %+v", got)
+	}
+}
@@ -4,36 +4,102 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"log"
+	"math"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/mfahmialkautsar/goo11y/clock"
+	"github.com/mfahmialkautsar/goo11y/internal/cloudresource"
+	"github.com/mfahmialkautsar/goo11y/internal/otlputil"
+	"github.com/mfahmialkautsar/goo11y/internal/resourceutil"
 	"github.com/mfahmialkautsar/goo11y/logger"
 	"github.com/mfahmialkautsar/goo11y/meter"
 	"github.com/mfahmialkautsar/goo11y/profiler"
 	"github.com/mfahmialkautsar/goo11y/tracer"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.28.0"
 )
 
-const shutdownGracePeriod = 5 * time.Second
+const (
+	shutdownGracePeriod          = 5 * time.Second
+	loggerMetricsInstrumentation = "github.com/mfahmialkautsar/goo11y/logger"
+)
 
-// Telemetry owns the lifecycle of the configured observability components.
+// Telemetry owns the lifecycle of the configured observability components. Reload swaps them
+// atomically, so the accessors are safe to call while it runs.
 type Telemetry struct {
-	Logger   *logger.Logger
-	Tracer   *tracer.Provider
-	Meter    *meter.Provider
-	Profiler *profiler.Controller
+	logger   atomic.Pointer[logger.Logger]
+	tracer   atomic.Pointer[tracer.Provider]
+	meter    atomic.Pointer[meter.Provider]
+	profiler atomic.Pointer[profiler.Controller]
 
 	shutdownHooks []func(context.Context) error
+	spanMetrics   sdktrace.SpanProcessor
+	spanLabels    sdktrace.SpanProcessor
+	traceProfile  sdktrace.SpanProcessor
+	// contextTracer is the tracer provider the context span processors are registered on.
+	contextTracer *tracer.Provider
+	closers       [componentCount]func(context.Context) error
+	hooked        [componentCount]bool
+
+	cfg      Config
+	opts     config
+	reloadMu sync.Mutex
+}
+
+// Logger returns the current logger, or nil when t is nil.
+func (t *Telemetry) Logger() *logger.Logger {
+	if t == nil {
+		return nil
+	}
+	return t.logger.Load()
+}
+
+// Tracer returns the current tracer provider, or nil when t is nil.
+func (t *Telemetry) Tracer() *tracer.Provider {
+	if t == nil {
+		return nil
+	}
+	return t.tracer.Load()
+}
+
+// Meter returns the current meter provider, or nil when t is nil.
+func (t *Telemetry) Meter() *meter.Provider {
+	if t == nil {
+		return nil
+	}
+	return t.meter.Load()
+}
+
+// Profiler returns the current profiler controller, or nil when t is nil.
+func (t *Telemetry) Profiler() *profiler.Controller {
+	if t == nil {
+		return nil
+	}
+	return t.profiler.Load()
 }
 
 // Option configures the telemetry provider.
 type Option func(*config)
 
 type config struct {
-	tracerOptions []tracer.Option
-	meterOptions  []meter.Option
+	tracerOptions   []tracer.Option
+	meterOptions    []meter.Option
+	profilerOptions []profiler.Option
+	loggerWriters   []io.Writer
+	resources       []*resource.Resource
+	shutdownHooks   []func(context.Context) error
+	clock           clock.Clock
 }
 
 // WithTracerOption adds options for the tracer provider.
@@ -50,6 +116,59 @@ func WithMeterOption(opts ...meter.Option) Option {
 	}
 }
 
+// WithProfilerOption adds options for the profiler.
+func WithProfilerOption(opts ...profiler.Option) Option {
+	return func(c *config) {
+		c.profilerOptions = append(c.profilerOptions, opts...)
+	}
+}
+
+// WithLoggerWriter adds writers that receive every log entry alongside logger.Config.Writers.
+func WithLoggerWriter(writers ...io.Writer) Option {
+	return func(c *config) {
+		c.loggerWriters = append(c.loggerWriters, writers...)
+	}
+}
+
+// WithResource merges res over the resource built from ResourceConfig, before Customizers run.
+func WithResource(res *resource.Resource) Option {
+	return func(c *config) {
+		if res != nil {
+			c.resources = append(c.resources, res)
+		}
+	}
+}
+
+// WithShutdownHook registers fn to run when Telemetry shuts down. Hooks run in reverse order of
+// registration, before the components are shut down, so they can still emit telemetry. Reload ignores
+// hooks passed in its options.
+func WithShutdownHook(fn func(context.Context) error) Option {
+	return func(c *config) {
+		if fn != nil {
+			c.shutdownHooks = append(c.shutdownHooks, fn)
+		}
+	}
+}
+
+// WithClock drives the timings Telemetry measures itself, such as job durations, and becomes the
+// Clock of the logger, tracer, and meter configs that leave it nil.
+func WithClock(clk clock.Clock) Option {
+	return func(c *config) {
+		c.clock = clk
+	}
+}
+
+// FromComponents returns a Telemetry over components built elsewhere, such as test doubles. Any of
+// them may be nil. Shutdown does not close them; their owner does.
+func FromComponents(log *logger.Logger, tp *tracer.Provider, mp *meter.Provider, prof *profiler.Controller) *Telemetry {
+	tele := &Telemetry{}
+	tele.logger.Store(log)
+	tele.tracer.Store(tp)
+	tele.meter.Store(mp)
+	tele.profiler.Store(prof)
+	return tele
+}
+
 // New wires the requested observability components based on the provided configuration.
 func New(ctx context.Context, cfg Config, opts ...Option) (*Telemetry, error) {
 	cfg.applyDefaults()
@@ -61,57 +180,77 @@ func New(ctx context.Context, cfg Config, opts ...Option) (*Telemetry, error) {
 	for _, opt := range opts {
 		opt(&c)
 	}
+	applyExportFailureInterval(cfg)
+	installErrorHandler(cfg)
 
-	res, err := buildResource(ctx, cfg)
-	if err != nil {
-		return nil, fmt.Errorf("build resource: %w", err)
-	}
+	tele := &Telemetry{cfg: cfg, opts: c}
+	rec := newInitRecorder(tele.now)
 
-	tele := &Telemetry{}
+	var res *resource.Resource
+	if err := rec.step("resource", func() (err error) {
+		res, err = buildResource(ctx, cfg, c.resources...)
+		return err
+	}); err != nil {
+		return nil, tele.finishInit(ctx, rec, fmt.Errorf("build resource: %w", err))
+	}
 
-	if err := setupLogger(ctx, &cfg, tele); err != nil {
-		return nil, err
+	if err := rec.step("logger", func() error { return setupLogger(ctx, &cfg, &c, tele) }); err != nil {
+		return nil, tele.finishInit(ctx, rec, err)
 	}
 
-	if err := setupTracer(ctx, &cfg, &c, tele, res); err != nil {
-		return nil, err
+	if err := rec.step("tracer", func() error { return setupTracer(ctx, &cfg, &c, tele, res) }); err != nil {
+		return nil, tele.finishInit(ctx, rec, err)
 	}
 
-	if err := setupMeter(ctx, &cfg, &c, tele, res); err != nil {
-		return nil, err
+	if err := rec.step("meter", func() error { return setupMeter(ctx, &cfg, &c, tele, res) }); err != nil {
+		return nil, tele.finishInit(ctx, rec, err)
 	}
 
-	if err := setupProfiler(&cfg, tele); err != nil {
-		return nil, err
+	if err := rec.step("profiler", func() error { return setupProfiler(&cfg, &c, tele) }); err != nil {
+		return nil, tele.finishInit(ctx, rec, err)
 	}
 
-	tele.configureIntegrations(cfg)
+	_ = rec.step("integrations", func() error {
+		tele.configureIntegrations(ctx, cfg)
+		return nil
+	})
+	for _, fn := range c.shutdownHooks {
+		tele.OnShutdown(fn)
+	}
 
+	_ = tele.finishInit(ctx, rec, nil)
 	return tele, nil
 }
 
-func setupLogger(ctx context.Context, cfg *Config, tele *Telemetry) error {
+func setupLogger(ctx context.Context, cfg *Config, c *config, tele *Telemetry) error {
 	if !cfg.Logger.Enabled {
 		return nil
 	}
+	logCfg := cfg.Logger
+	if logCfg.Clock == nil {
+		logCfg.Clock = c.clock
+	}
+	if len(c.loggerWriters) > 0 {
+		logCfg.Writers = append(slices.Clip(logCfg.Writers), c.loggerWriters...)
+	}
 	var log *logger.Logger
 	var err error
 	if cfg.Logger.UseGlobal {
-		err = logger.Init(ctx, cfg.Logger)
+		err = logger.Init(ctx, logCfg)
 		if err != nil {
 			return fmt.Errorf("setup logger: %w", err)
 		}
 		log = logger.Global()
 	} else {
-		log, err = logger.New(ctx, cfg.Logger)
+		log, err = logger.New(ctx, logCfg)
 		if err != nil {
 			return fmt.Errorf("setup logger: %w", err)
 		}
-		tele.shutdownHooks = append(tele.shutdownHooks, func(ctx context.Context) error {
+		tele.setCloser(componentLogger, func(context.Context) error {
 			return log.Close()
 		})
 	}
-	tele.Logger = log
+	tele.logger.Store(log)
 	return nil
 }
 
@@ -119,24 +258,26 @@ func setupTracer(ctx context.Context, cfg *Config, c *config, tele *Telemetry, r
 	if !cfg.Tracer.Enabled {
 		return nil
 	}
+	tracerCfg := cfg.Tracer
+	if tracerCfg.Clock == nil {
+		tracerCfg.Clock = c.clock
+	}
 	var provider *tracer.Provider
 	var err error
 	if cfg.Tracer.UseGlobal {
-		err = tracer.Init(ctx, cfg.Tracer, res, c.tracerOptions...)
+		err = tracer.Init(ctx, tracerCfg, res, c.tracerOptions...)
 		if err != nil {
 			return fmt.Errorf("setup tracer: %w", err)
 		}
 		provider = tracer.Global()
 	} else {
-		provider, err = tracer.Setup(ctx, cfg.Tracer, res, c.tracerOptions...)
+		provider, err = tracer.Setup(ctx, tracerCfg, res, c.tracerOptions...)
 		if err != nil {
 			return fmt.Errorf("setup tracer: %w", err)
 		}
 	}
-	tele.Tracer = provider
-	tele.shutdownHooks = append(tele.shutdownHooks, func(ctx context.Context) error {
-		return provider.Shutdown(ctx)
-	})
+	tele.tracer.Store(provider)
+	tele.setCloser(componentTracer, provider.Shutdown)
 	return nil
 }
 
@@ -144,24 +285,29 @@ func setupMeter(ctx context.Context, cfg *Config, c *config, tele *Telemetry, re
 	if !cfg.Meter.Enabled {
 		return nil
 	}
+	meterCfg := cfg.Meter
+	if meterCfg.Clock == nil {
+		meterCfg.Clock = c.clock
+	}
+	if meterCfg.OnInstrumentConflict == nil && tele.Logger() != nil {
+		meterCfg.OnInstrumentConflict = tele.logInstrumentConflict
+	}
 	var provider *meter.Provider
 	var err error
 	if cfg.Meter.UseGlobal {
-		err = meter.Init(ctx, cfg.Meter, res, c.meterOptions...)
+		err = meter.Init(ctx, meterCfg, res, c.meterOptions...)
 		if err != nil {
 			return fmt.Errorf("setup meter: %w", err)
 		}
 		provider = meter.Global()
 	} else {
-		provider, err = meter.Setup(ctx, cfg.Meter, res, c.meterOptions...)
+		provider, err = meter.Setup(ctx, meterCfg, res, c.meterOptions...)
 		if err != nil {
 			return fmt.Errorf("setup meter: %w", err)
 		}
 	}
-	tele.Meter = provider
-	tele.shutdownHooks = append(tele.shutdownHooks, func(ctx context.Context) error {
-		return provider.Shutdown(ctx)
-	})
+	tele.meter.Store(provider)
+	tele.setCloser(componentMeter, provider.Shutdown)
 
 	if cfg.Meter.Runtime.Enabled {
 		var regErr error
@@ -174,29 +320,32 @@ func setupMeter(ctx context.Context, cfg *Config, c *config, tele *Telemetry, re
 			tele.emitWarn(ctx, "register runtime metrics", regErr)
 		}
 	}
+	if err := provider.RegisterInfoMetrics(ctx, cfg.Meter.Info); err != nil {
+		tele.emitWarn(ctx, "register info metrics", err)
+	}
 	return nil
 }
 
-func setupProfiler(cfg *Config, tele *Telemetry) error {
+func setupProfiler(cfg *Config, c *config, tele *Telemetry) error {
 	if !cfg.Profiler.Enabled {
 		return nil
 	}
 	var controller *profiler.Controller
 	var err error
 	if cfg.Profiler.UseGlobal {
-		err = profiler.Init(cfg.Profiler, tele.Logger)
+		err = profiler.Init(cfg.Profiler, tele.Logger(), c.profilerOptions...)
 		if err != nil {
 			return fmt.Errorf("setup profiler: %w", err)
 		}
 		controller = profiler.Global()
 	} else {
-		controller, err = profiler.Setup(cfg.Profiler, tele.Logger)
+		controller, err = profiler.Setup(cfg.Profiler, tele.Logger(), c.profilerOptions...)
 		if err != nil {
 			return fmt.Errorf("setup profiler: %w", err)
 		}
 	}
-	tele.Profiler = controller
-	tele.shutdownHooks = append(tele.shutdownHooks, func(context.Context) error {
+	tele.profiler.Store(controller)
+	tele.setCloser(componentProfiler, func(context.Context) error {
 		return controller.Stop()
 	})
 	return nil
@@ -212,9 +361,13 @@ func (t *Telemetry) Shutdown(ctx context.Context) error {
 	ctx, cancel := context.WithTimeout(ctx, shutdownGracePeriod)
 	defer cancel()
 
+	t.reloadMu.Lock()
+	hooks := slices.Clone(t.shutdownHooks)
+	t.reloadMu.Unlock()
+
 	var errs error
-	for i := len(t.shutdownHooks) - 1; i >= 0; i-- {
-		if err := t.shutdownHooks[i](ctx); err != nil {
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := hooks[i](ctx); err != nil {
 			errs = errors.Join(errs, err)
 		}
 	}
@@ -222,7 +375,20 @@ func (t *Telemetry) Shutdown(ctx context.Context) error {
 	return errs
 }
 
-// ForceFlush triggers immediate delivery of spans and metrics.
+// OnShutdown registers fn to run during Shutdown. Hooks run in reverse order of registration
+// under the same deadline as the built-in component hooks, so a hook registered after New runs
+// before any component shuts down. Errors are joined into the Shutdown result.
+// No-op if receiver is nil.
+func (t *Telemetry) OnShutdown(fn func(context.Context) error) {
+	if t == nil || fn == nil {
+		return
+	}
+	t.reloadMu.Lock()
+	defer t.reloadMu.Unlock()
+	t.shutdownHooks = append(t.shutdownHooks, fn)
+}
+
+// ForceFlush triggers immediate delivery of spans, metrics, and OTLP log records.
 // No-op if receiver is nil.
 func (t *Telemetry) ForceFlush(ctx context.Context) error {
 	if t == nil {
@@ -230,42 +396,177 @@ func (t *Telemetry) ForceFlush(ctx context.Context) error {
 	}
 
 	var errs error
-	if t.Tracer != nil {
-		if err := t.Tracer.ForceFlush(ctx); err != nil {
+	if t.Tracer() != nil {
+		if err := t.Tracer().ForceFlush(ctx); err != nil {
 			errs = errors.Join(errs, err)
 		}
 	}
-	if t.Meter != nil {
-		if err := t.Meter.ForceFlush(ctx); err != nil {
+	if t.Meter() != nil {
+		if err := t.Meter().ForceFlush(ctx); err != nil {
 			errs = errors.Join(errs, err)
 		}
 	}
-	if t.Profiler != nil {
-		t.Profiler.Flush(true)
+	if t.Logger() != nil {
+		if err := t.Logger().Flush(ctx); err != nil {
+			errs = errors.Join(errs, err)
+		}
+	}
+	if t.Profiler() != nil {
+		t.Profiler().Flush(true)
 	}
 	return errs
 }
 
-func (t *Telemetry) configureIntegrations(cfg Config) {
-	if t.Tracer != nil && t.Profiler != nil {
-		if processor := profiler.TraceProfileSpanProcessor(); processor != nil {
-			t.Tracer.RegisterSpanProcessor(processor)
+func (t *Telemetry) configureIntegrations(ctx context.Context, cfg Config) {
+	t.registerTraceProfile(cfg)
+	t.registerSpanMetrics(ctx, cfg)
+	t.registerSpanLabels(cfg)
+	t.registerContextProcessors()
+	if t.Logger() != nil && t.Meter() != nil && cfg.Logger.File.Enabled {
+		if err := t.registerLoggerMetrics(); err != nil {
+			t.emitWarn(ctx, "register logger metrics", err)
+		}
+	}
+	if t.Logger() != nil && t.Meter() != nil && (cfg.Logger.MessageMetrics.Enabled || cfg.Logger.File.Enabled) {
+		if err := t.Logger().BindMeter(t.Meter().Meter(loggerMetricsInstrumentation)); err != nil {
+			t.emitWarn(ctx, "bind logger metrics", err)
 		}
 	}
 }
 
+// registerSpanMetrics replaces the span metrics processor so it records through the current meter.
+func (t *Telemetry) registerSpanMetrics(ctx context.Context, cfg Config) {
+	if t.spanMetrics != nil {
+		t.Tracer().UnregisterSpanProcessor(t.spanMetrics)
+		t.spanMetrics = nil
+	}
+	if t.Tracer() == nil || t.Meter() == nil || !cfg.Tracer.SpanMetrics.Enabled {
+		return
+	}
+	processor, err := tracer.NewSpanMetricsProcessor(t.Meter().Meter(tracer.SpanMetricsInstrumentation), cfg.Tracer.SpanMetrics)
+	if err != nil {
+		t.emitWarn(ctx, "register span metrics", err)
+		return
+	}
+	t.Tracer().RegisterSpanProcessor(processor)
+	t.spanMetrics = processor
+}
+
+// registerTraceProfile replaces the processor linking spans to the profiles recorded during them.
+func (t *Telemetry) registerTraceProfile(cfg Config) {
+	if t.traceProfile != nil {
+		t.Tracer().UnregisterSpanProcessor(t.traceProfile)
+		t.traceProfile = nil
+	}
+	if t.Tracer() == nil || t.Profiler() == nil {
+		return
+	}
+	t.traceProfile = profiler.NewTraceProfileSpanProcessor(cfg.Profiler.TraceProfile)
+	t.Tracer().RegisterSpanProcessor(t.traceProfile)
+}
+
+// registerSpanLabels replaces the processor pushing span attributes as profile labels.
+func (t *Telemetry) registerSpanLabels(cfg Config) {
+	if t.spanLabels != nil {
+		t.Tracer().UnregisterSpanProcessor(t.spanLabels)
+		t.spanLabels = nil
+	}
+	if t.Tracer() == nil || t.Profiler() == nil || !cfg.Profiler.Enabled || len(cfg.Profiler.SpanLabels) == 0 {
+		return
+	}
+	t.spanLabels = profiler.SpanLabelsSpanProcessor(cfg.Profiler.SpanLabels...)
+	t.Tracer().RegisterSpanProcessor(t.spanLabels)
+}
+
+// registerContextProcessors registers the processors that carry state from the parent context onto
+// new spans, the request ID and the span events buffered by logger.ContextWithSpanEventBuffer, on
+// the current tracer provider. The processors hold no state, so a replaced provider simply takes
+// its own.
+func (t *Telemetry) registerContextProcessors() {
+	if t.Tracer() == nil || t.contextTracer == t.Tracer() {
+		return
+	}
+	t.Tracer().RegisterSpanProcessor(RequestIDSpanProcessor())
+	t.Tracer().RegisterSpanProcessor(logger.SpanEventBufferProcessor())
+	t.contextTracer = t.Tracer()
+}
+
+func (t *Telemetry) registerLoggerMetrics() error {
+	m := t.Meter().Meter(loggerMetricsInstrumentation)
+	_, err := m.Int64ObservableCounter(
+		"goo11y.logger.file.dropped",
+		metric.WithDescription("Log entries discarded because the file writer queue was full"),
+		metric.WithUnit("{entry}"),
+		metric.WithInt64Callback(func(_ context.Context, observer metric.Int64Observer) error {
+			dropped := t.Logger().Stats().FileDropped
+			if dropped > math.MaxInt64 {
+				dropped = math.MaxInt64
+			}
+			observer.Observe(int64(dropped))
+			return nil
+		}),
+	)
+	return err
+}
+
+// applyExportFailureInterval installs cfg.ExportFailureLogInterval for the exporters' failure logs.
+func applyExportFailureInterval(cfg Config) {
+	interval := cfg.ExportFailureLogInterval
+	if interval == 0 {
+		interval = otlputil.DefaultExportFailureInterval
+	}
+	otlputil.SetExportFailureInterval(interval)
+}
+
+// installErrorHandler sends SDK errors through the rate-limited handler the Logger reports to,
+// or to stderr until a Logger is set up.
+func installErrorHandler(cfg Config) {
+	if cfg.OTelErrors.Disabled {
+		return
+	}
+	interval := cfg.OTelErrors.LogInterval
+	if interval == 0 {
+		interval = otlputil.DefaultExportFailureInterval
+	}
+	otel.SetErrorHandler(otlputil.NewSDKErrorHandler(interval))
+}
+
+// logInstrumentConflict warns about an instrument redefined with a different kind, unit, or
+// description, naming both call sites.
+func (t *Telemetry) logInstrumentConflict(conflict meter.InstrumentConflict) {
+	t.Logger().Warn().
+		Str("meter", conflict.Scope).
+		Str("instrument", conflict.Name).
+		Dict("first", instrumentSignatureDict(conflict.First)).
+		Dict("conflict", instrumentSignatureDict(conflict.Conflict)).
+		Msg("conflicting instrument definition")
+}
+
+func instrumentSignatureDict(sig meter.InstrumentSignature) *zerolog.Event {
+	return zerolog.Dict().
+		Str("kind", sig.Kind).
+		Str("unit", sig.Unit).
+		Str("description", sig.Description).
+		Str("call_site", sig.CallSite)
+}
+
 func (t *Telemetry) emitWarn(ctx context.Context, msg string, err error) {
+	warnTo(t.Logger(), ctx, msg, err)
+}
+
+// warnTo logs err through lg, or the standard logger when lg is nil.
+func warnTo(lg *logger.Logger, ctx context.Context, msg string, err error) {
 	if err == nil {
 		return
 	}
-	if t.Logger != nil {
-		t.Logger.Warn().Ctx(ctx).Err(err).Msg(msg)
+	if lg != nil {
+		lg.Warn().Ctx(ctx).Err(err).Msg(msg)
 	} else {
 		log.Printf("goo11y WARN: %s: %v", msg, err)
 	}
 }
 
-func buildResource(ctx context.Context, cfg Config) (*resource.Resource, error) {
+func buildResource(ctx context.Context, cfg Config, extra ...*resource.Resource) (*resource.Resource, error) {
 	attrs := []attribute.KeyValue{
 		semconv.ServiceNameKey.String(cfg.Resource.ServiceName),
 	}
@@ -276,9 +577,20 @@ func buildResource(ctx context.Context, cfg Config) (*resource.Resource, error)
 	if cfg.Resource.Environment != "" {
 		attrs = append(attrs, semconv.DeploymentEnvironmentNameKey.String(cfg.Resource.Environment))
 	}
+	if cfg.Resource.InstanceID != "" {
+		attrs = append(attrs, semconv.ServiceInstanceID(cfg.Resource.InstanceID))
+	}
+	if cfg.Resource.RunID != "" {
+		attrs = append(attrs, ProcessRunIDKey.String(cfg.Resource.RunID))
+	}
 	for key, value := range cfg.Resource.Attributes {
 		attrs = append(attrs, attribute.String(key, value))
 	}
+	for key, env := range cfg.Resource.EnvAttributes {
+		if value := os.Getenv(env); value != "" {
+			attrs = append(attrs, attribute.String(key, value))
+		}
+	}
 
 	options := []resource.Option{
 		resource.WithAttributes(attrs...),
@@ -289,18 +601,44 @@ func buildResource(ctx context.Context, cfg Config) (*resource.Resource, error)
 		resource.WithHost(),
 		resource.WithContainer(),
 	}
-	if len(cfg.Resource.Detectors) > 0 {
-		options = append(options, resource.WithDetectors(cfg.Resource.Detectors...))
-	}
-	if len(cfg.Resource.Options) > 0 {
-		options = append(options, cfg.Resource.Options...)
-	}
-
 	defaults, err := resource.New(ctx, options...)
 	if err != nil {
 		return nil, fmt.Errorf("resource defaults: %w", err)
 	}
 
+	// Detectors and options are merged one at a time so a schema URL conflict between them and
+	// the SDK's semconv version is resolved by Resource.SchemaConflict instead of failing outright.
+	merger := &resourceutil.Merger{Strategy: cfg.Resource.SchemaConflict}
+	detectors := append([]resource.Detector(nil), cfg.Resource.Detectors...)
+	for _, preset := range cfg.Resource.Presets {
+		if detector, ok := cloudresource.Detector(preset); ok {
+			detectors = append(detectors, detector)
+		}
+	}
+	for _, detector := range detectors {
+		if detector == nil {
+			continue
+		}
+		detected, err := detector.Detect(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("resource defaults: %w", err)
+		}
+		defaults, err = merger.Merge(defaults, detected)
+		if err != nil {
+			return nil, fmt.Errorf("resource defaults: %w", err)
+		}
+	}
+	if len(cfg.Resource.Options) > 0 {
+		fromOptions, err := resource.New(ctx, cfg.Resource.Options...)
+		if err != nil {
+			return nil, fmt.Errorf("resource defaults: %w", err)
+		}
+		defaults, err = merger.Merge(defaults, fromOptions)
+		if err != nil {
+			return nil, fmt.Errorf("resource defaults: %w", err)
+		}
+	}
+
 	base := resource.Empty()
 	if cfg.Resource.Override != nil {
 		base, err = cfg.Resource.Override(ctx)
@@ -309,10 +647,16 @@ func buildResource(ctx context.Context, cfg Config) (*resource.Resource, error)
 		}
 	}
 
-	res, err := resource.Merge(defaults, base)
+	res, err := merger.Merge(defaults, base)
 	if err != nil {
 		return nil, fmt.Errorf("resource merge override: %w", err)
 	}
+	for _, r := range extra {
+		res, err = merger.Merge(res, r)
+		if err != nil {
+			return nil, fmt.Errorf("resource merge option: %w", err)
+		}
+	}
 
 	for idx, customizer := range cfg.Customizers {
 		if customizer == nil {
@@ -326,3 +670,11 @@ func buildResource(ctx context.Context, cfg Config) (*resource.Resource, error)
 
 	return res, nil
 }
+
+// now returns the current time from the clock set by WithClock, or time.Now.
+func (t *Telemetry) now() time.Time {
+	if t == nil {
+		return time.Now()
+	}
+	return clock.OrReal(t.opts.clock).Now()
+}
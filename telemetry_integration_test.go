@@ -118,10 +118,10 @@ func TestTelemetryTracePropagationIntegration(t *testing.T) {
 	profileID := fmt.Sprintf("profile-%s", traceID)
 	pyroscope.TagWrapper(spanCtx, pyroscope.Labels(profiler.TraceProfileAttributeKey, profileID), func(ctx context.Context) {
 		// Log something
-		if tele.Logger == nil {
+		if tele.Logger() == nil {
 			t.Fatal("expected logger to be initialized")
 		}
-		tele.Logger.Info().Ctx(ctx).Str("test_case", testCase).Msg(logMessage)
+		tele.Logger().Info().Ctx(ctx).Str("test_case", testCase).Msg(logMessage)
 
 		// Record metric
 		m := otel.Meter("goo11y/integration")
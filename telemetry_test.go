@@ -7,6 +7,8 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
@@ -14,6 +16,7 @@ import (
 
 	"github.com/grafana/pyroscope-go"
 	"github.com/mfahmialkautsar/goo11y/internal/testutil"
+	"github.com/mfahmialkautsar/goo11y/internal/testutil/inmemory"
 	"github.com/mfahmialkautsar/goo11y/logger"
 	"github.com/mfahmialkautsar/goo11y/meter"
 	"github.com/mfahmialkautsar/goo11y/profiler"
@@ -21,6 +24,8 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	sdkresource "go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/sdk/trace/tracetest"
@@ -46,7 +51,7 @@ func TestTelemetryEmitWarnAddsSpanEvents(t *testing.T) {
 		t.Fatal("expected logger instance")
 	}
 
-	tele := &Telemetry{Logger: log}
+	tele := FromComponents(log, nil, nil, nil)
 
 	recorder := tracetest.NewSpanRecorder()
 	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
@@ -103,7 +108,7 @@ func TestTelemetryEmitWarnSkipsNilError(t *testing.T) {
 	if log == nil {
 		t.Fatal("expected logger instance")
 	}
-	tele := &Telemetry{Logger: log}
+	tele := FromComponents(log, nil, nil, nil)
 	tele.emitWarn(context.Background(), "msg", nil)
 	if buf.Len() != 0 {
 		t.Fatalf("expected no log output for nil error, got %s", buf.String())
@@ -162,6 +167,76 @@ func TestBuildResourceComposes(t *testing.T) {
 	}
 }
 
+type schemaDetector struct {
+	url string
+}
+
+func (d schemaDetector) Detect(context.Context) (*sdkresource.Resource, error) {
+	return sdkresource.NewWithAttributes(d.url, attribute.String("detector.schema", d.url)), nil
+}
+
+func TestBuildResourceSchemaConflict(t *testing.T) {
+	const newer = "https://opentelemetry.io/schemas/1.99.0"
+	cfg := Config{Resource: ResourceConfig{
+		ServiceName: "svc",
+		Detectors:   []sdkresource.Detector{schemaDetector{url: "https://opentelemetry.io/schemas/1.4.0"}, schemaDetector{url: newer}},
+	}}
+
+	if _, err := buildResource(context.Background(), cfg); !errors.Is(err, sdkresource.ErrSchemaURLConflict) {
+		t.Fatalf("expected schema conflict error, got %v", err)
+	}
+
+	cfg.Resource.SchemaConflict = SchemaConflictPreferLatest
+	res, err := buildResource(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("buildResource prefer-latest: %v", err)
+	}
+	if res.SchemaURL() != newer {
+		t.Fatalf("expected newest schema URL, got %q", res.SchemaURL())
+	}
+	if got := testutil.AttrsToMap(res.Attributes())[string(semconv.ServiceNameKey)]; got != "svc" {
+		t.Fatalf("service.name = %v", got)
+	}
+
+	cfg.Resource.SchemaConflict = SchemaConflictStrip
+	res, err = buildResource(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("buildResource strip: %v", err)
+	}
+	if res.SchemaURL() != "" {
+		t.Fatalf("expected schema URL stripped, got %q", res.SchemaURL())
+	}
+	if got := testutil.AttrsToMap(res.Attributes())["detector.schema"]; got != newer {
+		t.Fatalf("detector.schema = %v", got)
+	}
+}
+
+func TestBuildResourceEnvAttributes(t *testing.T) {
+	t.Setenv("GOO11Y_TEST_POD_IP", "10.0.0.7")
+	cfg := Config{
+		Resource: ResourceConfig{
+			ServiceName: "svc",
+			EnvAttributes: map[string]string{
+				"k8s.pod.ip":  "GOO11Y_TEST_POD_IP",
+				"k8s.missing": "GOO11Y_TEST_UNSET",
+			},
+			Presets: []string{"k8s"},
+		},
+	}
+
+	res, err := buildResource(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("buildResource: %v", err)
+	}
+	attrs := testutil.AttrsToMap(res.Attributes())
+	if got := attrs["k8s.pod.ip"]; got != "10.0.0.7" {
+		t.Fatalf("k8s.pod.ip = %v", got)
+	}
+	if _, ok := attrs["k8s.missing"]; ok {
+		t.Fatal("unset environment variable produced an attribute")
+	}
+}
+
 func TestBuildResourceOverrideError(t *testing.T) {
 	cfg := Config{Resource: ResourceConfig{ServiceName: "svc"}}
 	cfg.Resource.Override = func(context.Context) (*sdkresource.Resource, error) {
@@ -239,10 +314,10 @@ func TestNewInitializesLoggerOnly(t *testing.T) {
 	if err != nil {
 		t.Fatalf("New: %v", err)
 	}
-	if tele.Logger == nil {
+	if tele.Logger() == nil {
 		t.Fatal("expected logger to be initialized")
 	}
-	if tele.Tracer != nil || tele.Meter != nil || tele.Profiler != nil {
+	if tele.Tracer() != nil || tele.Meter() != nil || tele.Profiler() != nil {
 		t.Fatalf("expected other components nil, got %+v", tele)
 	}
 	if len(tele.shutdownHooks) != 1 {
@@ -306,15 +381,15 @@ func TestTelemetryLinksTracesToProfiles(t *testing.T) {
 			_ = tele.Shutdown(context.Background())
 		})
 	})
-	if tele.Tracer == nil {
+	if tele.Tracer() == nil {
 		t.Fatal("expected tracer provider")
 	}
-	if tele.Profiler == nil {
+	if tele.Profiler() == nil {
 		t.Fatal("expected profiler controller")
 	}
 
 	recorder := tracetest.NewSpanRecorder()
-	tele.Tracer.RegisterSpanProcessor(recorder)
+	tele.Tracer().RegisterSpanProcessor(recorder)
 
 	profileID := "profile-link-id"
 	pyroscope.TagWrapper(context.Background(), pyroscope.Labels(profiler.TraceProfileAttributeKey, profileID), func(ctx context.Context) {
@@ -399,3 +474,283 @@ func TestTelemetryForceFlush(t *testing.T) {
 		t.Fatalf("ForceFlush: %v", err)
 	}
 }
+
+func TestTelemetryRegistersLoggerDropMetric(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	tele, err := New(context.Background(), Config{
+		Logger: logger.Config{
+			Enabled: true,
+			File: logger.FileConfig{
+				Enabled:   true,
+				Directory: t.TempDir(),
+				Mode:      logger.FileModeDrop,
+			},
+		},
+		Meter: meter.Config{
+			Enabled:  true,
+			Endpoint: "localhost:4318",
+		},
+	}, WithMeterOption(meter.WithMetricReader(reader)))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = tele.Shutdown(context.Background()) })
+
+	rm, err := inmemory.GetMetrics(context.Background(), reader)
+	if err != nil {
+		t.Fatalf("GetMetrics: %v", err)
+	}
+	if _, ok := inmemory.FindMetricByName(rm, "goo11y.logger.file.dropped"); !ok {
+		t.Fatal("expected logger drop metric to be registered")
+	}
+}
+
+func TestWithClockDrivesLoggerFileRollover(t *testing.T) {
+	dir := t.TempDir()
+	tele, err := New(context.Background(), Config{
+		Logger: logger.Config{
+			Enabled: true,
+			Console: false,
+			File: logger.FileConfig{
+				Enabled:   true,
+				Directory: dir,
+				Timezone:  "UTC",
+			},
+		},
+	}, WithClock(&steppingClock{current: time.Date(2020, 1, 2, 12, 0, 0, 0, time.UTC)}))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	tele.Logger().Info().Msg("dated by the injected clock")
+	if err := tele.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "2020-01-02.log")); err != nil {
+		t.Fatalf("expected the log file named after the injected clock: %v", err)
+	}
+}
+
+func TestTelemetryCountsLogMessages(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	tele, err := New(context.Background(), Config{
+		Logger: logger.Config{
+			Enabled:        true,
+			Console:        false,
+			Writers:        []io.Writer{io.Discard},
+			MessageMetrics: logger.MessageMetricsConfig{Enabled: true, Component: "checkout"},
+		},
+		Meter: meter.Config{
+			Enabled:  true,
+			Endpoint: "localhost:4318",
+		},
+	}, WithMeterOption(meter.WithMetricReader(reader)))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = tele.Shutdown(context.Background()) })
+
+	tele.Logger().Error().Msg("failed")
+
+	rm, err := inmemory.GetMetrics(context.Background(), reader)
+	if err != nil {
+		t.Fatalf("GetMetrics: %v", err)
+	}
+	if _, ok := inmemory.FindMetricByName(rm, logger.MessageMetricsName); !ok {
+		t.Fatal("expected log message counter to be registered")
+	}
+}
+
+func TestTelemetryRecordsSpanMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	tele, err := New(context.Background(), Config{
+		Tracer: tracer.Config{
+			Enabled:     true,
+			SpanMetrics: tracer.SpanMetricsConfig{Enabled: true},
+		},
+		Meter: meter.Config{
+			Enabled:  true,
+			Endpoint: "localhost:4318",
+		},
+	},
+		WithTracerOption(tracer.WithSpanExporter(tracetest.NewInMemoryExporter())),
+		WithMeterOption(meter.WithMetricReader(reader)),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = tele.Shutdown(context.Background()) })
+
+	_, span := tele.Tracer().Tracer("spanmetrics").Start(context.Background(), "op")
+	span.End()
+
+	rm, err := inmemory.GetMetrics(context.Background(), reader)
+	if err != nil {
+		t.Fatalf("GetMetrics: %v", err)
+	}
+	if _, ok := inmemory.FindMetricByName(rm, "traces_spanmetrics_calls_total"); !ok {
+		t.Fatal("expected span calls counter to be recorded")
+	}
+}
+
+func TestNewAppliesComponentOptions(t *testing.T) {
+	var buf, extra bytes.Buffer
+	exporter := tracetest.NewInMemoryExporter()
+	var hookRan bool
+	tele, err := New(context.Background(), reloadTestConfig(&buf),
+		WithTracerOption(tracer.WithSpanExporter(exporter)),
+		WithMeterOption(meter.WithMetricReader(sdkmetric.NewManualReader())),
+		WithLoggerWriter(&extra),
+		WithResource(sdkresource.NewSchemaless(attribute.String("team", "core"))),
+		WithShutdownHook(func(context.Context) error {
+			hookRan = true
+			return nil
+		}),
+		WithShutdownHook(func(context.Context) error {
+			return errors.New("hook failed")
+		}),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	tele.Logger().Info().Msg("option-writer")
+	if !strings.Contains(buf.String(), "option-writer") || !strings.Contains(extra.String(), "option-writer") {
+		t.Fatalf("expected entry in config and option writers, got %q and %q", buf.String(), extra.String())
+	}
+
+	_, span := tele.Tracer().Tracer("options").Start(context.Background(), "op")
+	span.End()
+	if err := tele.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	team, ok := spans[0].Resource.Set().Value("team")
+	if !ok || team.AsString() != "core" {
+		t.Fatalf("expected resource option attribute, got %v", spans[0].Resource.Attributes())
+	}
+
+	err = tele.Shutdown(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "hook failed") {
+		t.Fatalf("expected shutdown hook error, got %v", err)
+	}
+	if !hookRan {
+		t.Fatal("expected shutdown hook to run")
+	}
+}
+
+func TestOnShutdownRunsHooksInReverseOrderBeforeComponents(t *testing.T) {
+	var buf bytes.Buffer
+	tele, err := New(context.Background(), Config{
+		Resource: ResourceConfig{ServiceName: "shutdown-hooks"},
+		Logger: logger.Config{
+			Enabled: true,
+			Console: false,
+			Writers: []io.Writer{&buf},
+		},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	var order []string
+	tele.OnShutdown(func(ctx context.Context) error {
+		order = append(order, "first")
+		return nil
+	})
+	tele.OnShutdown(func(ctx context.Context) error {
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("expected shutdown hook context to carry a deadline")
+		}
+		order = append(order, "second")
+		tele.Logger().Info().Msg("hook-flush")
+		return errors.New("hook failed")
+	})
+	tele.OnShutdown(nil)
+
+	err = tele.Shutdown(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "hook failed") {
+		t.Fatalf("expected joined hook error, got %v", err)
+	}
+	if strings.Join(order, ",") != "second,first" {
+		t.Fatalf("unexpected hook order: %v", order)
+	}
+	if !strings.Contains(buf.String(), "hook-flush") {
+		t.Fatalf("expected hook to log before the logger closed, got %q", buf.String())
+	}
+
+	var nilTele *Telemetry
+	nilTele.OnShutdown(func(context.Context) error { return nil })
+}
+
+func TestTelemetryRoutesOTelErrorsToLogger(t *testing.T) {
+	previous := otel.GetErrorHandler()
+	t.Cleanup(func() { otel.SetErrorHandler(previous) })
+
+	var buf syncBuffer
+	tele, err := New(context.Background(), Config{
+		Logger: logger.Config{
+			Enabled: true,
+			Console: false,
+			Writers: []io.Writer{&buf},
+		},
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = tele.Shutdown(context.Background()) })
+
+	for range 3 {
+		otel.Handle(errors.New("instrument conflict"))
+	}
+	if got := strings.Count(buf.String(), "instrument conflict"); got != 1 {
+		t.Fatalf("expected one rate-limited sdk error entry, got %d in %q", got, buf.String())
+	}
+	if !strings.Contains(buf.String(), "opentelemetry sdk error") {
+		t.Fatalf("expected sdk error message, got %q", buf.String())
+	}
+
+	marker := otelErrorFunc(func(error) {})
+	otel.SetErrorHandler(marker)
+	if _, err := New(context.Background(), Config{OTelErrors: OTelErrorConfig{Disabled: true}}); err != nil {
+		t.Fatalf("New disabled: %v", err)
+	}
+	if _, ok := otel.GetErrorHandler().(otelErrorFunc); !ok {
+		t.Fatalf("expected disabled config to keep the current handler, got %T", otel.GetErrorHandler())
+	}
+}
+
+type otelErrorFunc func(error)
+
+func (f otelErrorFunc) Handle(err error) { f(err) }
+
+func TestNewLogsConflictingInstruments(t *testing.T) {
+	var buf bytes.Buffer
+	cfg := reloadTestConfig(&buf)
+	cfg.Tracer.Enabled = false
+	tele, err := New(context.Background(), cfg,
+		WithMeterOption(meter.WithMetricReader(sdkmetric.NewManualReader())),
+	)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = tele.Shutdown(context.Background()) })
+
+	m := tele.Meter().Meter("checkout")
+	if _, err := m.Float64Histogram("latency", otelmetric.WithUnit("s")); err != nil {
+		t.Fatalf("Float64Histogram: %v", err)
+	}
+	if _, err := m.Float64Histogram("latency", otelmetric.WithUnit("ms")); err != nil {
+		t.Fatalf("Float64Histogram: %v", err)
+	}
+
+	entry := buf.String()
+	if !strings.Contains(entry, `"message":"conflicting instrument definition"`) ||
+		!strings.Contains(entry, `"first":{"kind":"Float64Histogram","unit":"s"`) ||
+		!strings.Contains(entry, `"conflict":{"kind":"Float64Histogram","unit":"ms"`) {
+		t.Fatalf("expected a conflict warning, got %s", entry)
+	}
+}
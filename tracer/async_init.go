@@ -0,0 +1,35 @@
+package tracer
+
+import (
+	"context"
+
+	"github.com/mfahmialkautsar/goo11y/internal/otlputil"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// lazySpanExporter forwards to an exporter built in the background for Config.AsyncInit. Exports
+// wait for it, within their own deadline, while the batch processor keeps queuing new spans.
+type lazySpanExporter struct {
+	exporter *otlputil.Lazy[sdktrace.SpanExporter]
+}
+
+func newLazySpanExporter(build func() (sdktrace.SpanExporter, error)) lazySpanExporter {
+	return lazySpanExporter{exporter: otlputil.NewLazy("tracer", build)}
+}
+
+func (e lazySpanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	exporter, err := e.exporter.Wait(ctx)
+	if err != nil {
+		return err
+	}
+	return exporter.ExportSpans(ctx, spans)
+}
+
+func (e lazySpanExporter) Shutdown(ctx context.Context) error {
+	exporter, err := e.exporter.Wait(ctx)
+	if err != nil {
+		// A failed build was already reported; there is nothing to shut down.
+		return ctx.Err()
+	}
+	return exporter.Shutdown(ctx)
+}
@@ -0,0 +1,62 @@
+package tracer
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestLazySpanExporterWaitsForBuild(t *testing.T) {
+	release := make(chan struct{})
+	exporter := &recordingSpanExporter{}
+	lazy := newLazySpanExporter(func() (sdktrace.SpanExporter, error) {
+		<-release
+		return exporter, nil
+	})
+
+	spans := tracetest.SpanStubs{{Name: "queued"}}.Snapshots()
+	done := make(chan error, 1)
+	go func() { done <- lazy.ExportSpans(context.Background(), spans) }()
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("export spans: %v", err)
+	}
+	if len(exporter.spans) != 1 || exporter.spans[0].Name() != "queued" {
+		t.Fatalf("expected the span to reach the built exporter, got %d spans", len(exporter.spans))
+	}
+	if err := lazy.Shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+}
+
+func TestSetupAsyncInitDefersExporterErrors(t *testing.T) {
+	ctx := context.Background()
+	cfg := Config{
+		Enabled:     true,
+		ServiceName: "async-init",
+		Export: ExportConfig{
+			Backend: BackendConfig{
+				Enabled:  true,
+				Endpoint: "collector 4318",
+			},
+		},
+	}
+	if _, err := Setup(ctx, cfg, resource.Empty()); err == nil {
+		t.Fatal("expected setup to fail on the endpoint without AsyncInit")
+	}
+
+	cfg.AsyncInit = true
+	provider, err := Setup(ctx, cfg, resource.Empty())
+	if err != nil {
+		t.Fatalf("setup tracer: %v", err)
+	}
+	_, span := provider.Tracer("async-init").Start(ctx, "op")
+	span.End()
+	if err := provider.ForceFlush(ctx); err == nil {
+		t.Fatal("expected the export to report the failed build")
+	}
+	_ = provider.Shutdown(ctx)
+}
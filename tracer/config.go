@@ -7,7 +7,9 @@ import (
 	"github.com/creasty/defaults"
 	"github.com/go-playground/validator/v10"
 	"github.com/mfahmialkautsar/goo11y/auth"
+	"github.com/mfahmialkautsar/goo11y/clock"
 	"github.com/mfahmialkautsar/goo11y/internal/fileutil"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
 const (
@@ -27,6 +29,31 @@ type Config struct {
 	SampleRatio float64 `default:"1.0" validate:"gte=0,lte=1"`
 	UseGlobal   bool
 	Export      ExportConfig `validate:"required_if=Enabled true"`
+	// IDGenerator overrides the SDK's random trace and span ID generator when set.
+	IDGenerator sdktrace.IDGenerator
+	// Interop adds the AWS X-Ray ("xray"), Google Cloud Trace ("gcp") or Datadog ("datadog") propagator
+	// in front of W3C trace context. The xray mode also generates X-Ray compatible trace IDs unless
+	// IDGenerator is set.
+	Interop string `validate:"omitempty,oneof=xray gcp datadog"`
+	// SyncExport exports every span as it ends instead of batching, overriding Async.
+	SyncExport bool
+	// SpanMetrics records RED metrics from ended spans through the meter when both are enabled.
+	SpanMetrics SpanMetricsConfig
+	// RemoteSampling replaces the SampleRatio sampler with strategies polled from a Jaeger remote
+	// sampling endpoint when URL is set.
+	RemoteSampling RemoteSamplingConfig
+	// Limits caps attributes, events, and links per span and the length of attribute values.
+	Limits LimitsConfig
+	// SpanNames strips IDs and other high-cardinality parts from span names before export.
+	SpanNames SpanNameConfig
+	// AsyncInit builds the backend and file exporters in the background, so Setup does not wait for
+	// dials, TLS handshakes, or failover journal replays. Spans queue in the batch processor until
+	// the exporter is ready, and build errors are logged as export failures instead of failing Setup.
+	// With SyncExport, the first exports wait for the exporter.
+	AsyncInit bool
+	// Clock drives the daily trace file rollover, the failover journal's file names, and replay
+	// backoff and pacing. Nil uses the real clock; tests can pass goo11ytest.NewFakeClock.
+	Clock clock.Clock
 }
 
 // ExportConfig selects the trace export destinations.
@@ -36,14 +63,46 @@ type ExportConfig struct {
 }
 
 // BackendConfig controls OTLP backend delivery.
+// Headers are sent with every export, below the Credentials headers. Endpoint, Insecure, Headers,
+// Protocol, Timeout, and Certificate fall back to the standard OTEL_EXPORTER_OTLP_TRACES_* and
+// OTEL_EXPORTER_OTLP_* variables when left unset.
 type BackendConfig struct {
-	Enabled     bool
-	Endpoint    string `validate:"required_if=Enabled true"`
-	Insecure    bool
-	Protocol    string        `default:"http" validate:"required_if=Enabled true,omitempty,oneof=http grpc"`
-	Timeout     time.Duration `default:"10s" validate:"required_if=Enabled true,omitempty,gt=0"`
-	Credentials auth.Credentials
-	Failover    FailoverConfig
+	Enabled  bool
+	Endpoint string `validate:"required_if=Enabled true"`
+	// EndpointIsURL sends HTTP exports to Endpoint's path as given instead of appending /v1/traces.
+	// It is set when Endpoint comes from OTEL_EXPORTER_OTLP_TRACES_ENDPOINT, which holds the full URL.
+	EndpointIsURL bool
+	Insecure      bool
+	Protocol      string        `default:"http" validate:"required_if=Enabled true,omitempty,oneof=http grpc"`
+	Timeout       time.Duration `default:"10s" validate:"required_if=Enabled true,omitempty,gt=0"`
+	Credentials   auth.Credentials
+	Headers       map[string]string
+	Failover      FailoverConfig
+	Secondary     SecondaryConfig
+	// Certificate is a PEM file of CA certificates trusted for the primary and secondary backends
+	// instead of the system roots.
+	Certificate string
+	// MaxPayloadBytes splits batches whose OTLP/JSON encoding exceeds it into smaller batches, each
+	// sent and journaled on its own, so a collector's request size limit does not reject them. Zero
+	// sends batches as they are.
+	MaxPayloadBytes int `validate:"gte=0"`
+	// MaxRequestsPerSecond and MaxBytesPerSecond pace the replay of journaled batches, with jitter,
+	// so a backlog drained after an outage does not trip the backend's rate limits. Zero leaves a
+	// limit off.
+	MaxRequestsPerSecond float64 `validate:"gte=0"`
+	MaxBytesPerSecond    int     `validate:"gte=0"`
+}
+
+// SecondaryConfig names a fail-over backend using the same protocol and timeout as the primary.
+// Sends move to Endpoint after Threshold consecutive failures and move back once a probe of the
+// primary, sent at most every ProbeInterval, succeeds. Batches that fail on both stay in the
+// failover journal for replay.
+type SecondaryConfig struct {
+	Endpoint      string
+	Insecure      bool
+	Credentials   auth.Credentials
+	Threshold     int           `default:"3" validate:"gte=0"`
+	ProbeInterval time.Duration `default:"30s" validate:"gte=0"`
 }
 
 // FailoverConfig controls disk-backed backend failover.
@@ -62,6 +121,7 @@ type FileConfig struct {
 }
 
 func (c Config) withDefaults() Config {
+	c.Export.Backend.applyEnv()
 	_ = defaults.Set(&c)
 
 	if c.Export.File.Enabled {
@@ -120,5 +180,5 @@ func (c Config) Validate() error {
 }
 
 func (c Config) validateBase() error {
-	return validate.StructPartial(c, "ServiceName", "SampleRatio")
+	return validate.StructPartial(c, "ServiceName", "SampleRatio", "Interop", "RemoteSampling", "SpanNames")
 }
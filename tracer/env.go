@@ -0,0 +1,31 @@
+package tracer
+
+import "github.com/mfahmialkautsar/goo11y/internal/otlputil"
+
+// applyEnv fills the fields left unset from the OTEL_EXPORTER_OTLP_TRACES_* and OTEL_EXPORTER_OTLP_*
+// variables.
+func (c *BackendConfig) applyEnv() {
+	env := otlputil.ReadEnv(otlputil.SignalTraces)
+	if c.Endpoint == "" {
+		c.Endpoint = env.Endpoint
+		c.EndpointIsURL = c.EndpointIsURL || env.EndpointIsURL
+	}
+	if !c.Insecure {
+		c.Insecure = env.Insecure
+	}
+	c.Headers = otlputil.MergeHeaders(c.Headers, env.Headers)
+	if c.Timeout == 0 {
+		c.Timeout = env.Timeout
+	}
+	if c.Protocol == "" {
+		c.Protocol = env.Protocol
+	}
+	if c.Certificate == "" {
+		c.Certificate = env.Certificate
+	}
+}
+
+// headerMap returns the headers sent to the backend, letting Credentials override Headers.
+func (c BackendConfig) headerMap() map[string]string {
+	return otlputil.MergeHeaders(c.Credentials.HeaderMap(), c.Headers)
+}
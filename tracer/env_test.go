@@ -0,0 +1,110 @@
+package tracer
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+func TestBackendConfigFallsBackToEnv(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "https://collector:4318/v1/traces")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_HEADERS", "x-tenant=env,x-scope=env")
+	t.Setenv("OTEL_EXPORTER_OTLP_TIMEOUT", "3000")
+
+	cfg := Config{Export: ExportConfig{Backend: BackendConfig{
+		Enabled: true,
+		Headers: map[string]string{"X-Tenant": "config"},
+	}}}.ApplyDefaults()
+	backend := cfg.Export.Backend
+	if backend.Endpoint != "https://collector:4318/v1/traces" || !backend.EndpointIsURL {
+		t.Fatalf("unexpected endpoint %q", backend.Endpoint)
+	}
+	if backend.Timeout != 3*time.Second || backend.Protocol != "http" {
+		t.Fatalf("unexpected timeout %v or protocol %q", backend.Timeout, backend.Protocol)
+	}
+	headers := backend.headerMap()
+	if headers["X-Tenant"] != "config" || headers["x-scope"] != "env" || len(headers) != 2 {
+		t.Fatalf("expected config headers to win over env, got %v", headers)
+	}
+}
+
+func TestBackendTrustsConfiguredCertificate(t *testing.T) {
+	var received atomic.Int32
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+
+	certFile := filepath.Join(t.TempDir(), "ca.pem")
+	block := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(certFile, block, 0o600); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	provider, err := Setup(context.Background(), Config{
+		Enabled:     true,
+		ServiceName: "trace-tls",
+		SyncExport:  true,
+		Export: ExportConfig{Backend: BackendConfig{
+			Enabled:     true,
+			Endpoint:    srv.URL,
+			Certificate: certFile,
+			Failover:    FailoverConfig{Enabled: false, Owner: FailoverOwnerApp},
+		}},
+	}, resource.Empty())
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	_, span := provider.provider.Tracer("trace-tls").Start(context.Background(), "tls-span")
+	span.End()
+	if err := provider.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+	if received.Load() == 0 {
+		t.Fatal("expected the TLS backend to receive the span")
+	}
+}
+
+func TestBackendSendsToSignalEndpointAsIs(t *testing.T) {
+	var paths atomic.Value
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths.Store(r.URL.Path)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", srv.URL+"/ingest/spans")
+
+	provider, err := Setup(context.Background(), Config{
+		Enabled:     true,
+		ServiceName: "trace-env-url",
+		SyncExport:  true,
+		Export: ExportConfig{Backend: BackendConfig{
+			Enabled:  true,
+			Failover: FailoverConfig{Enabled: false, Owner: FailoverOwnerApp},
+		}},
+	}, resource.Empty())
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	_, span := provider.provider.Tracer("trace-env-url").Start(context.Background(), "span")
+	span.End()
+	if err := provider.ForceFlush(context.Background()); err != nil {
+		t.Fatalf("ForceFlush: %v", err)
+	}
+	if got, _ := paths.Load().(string); got != "/ingest/spans" {
+		t.Fatalf("expected the export at the configured path, got %q", got)
+	}
+}
@@ -10,8 +10,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/mfahmialkautsar/goo11y/clock"
 	"github.com/mfahmialkautsar/goo11y/constant"
 	"github.com/mfahmialkautsar/goo11y/internal/otlputil"
+	"github.com/mfahmialkautsar/goo11y/internal/spool"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	coltrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
 	"google.golang.org/grpc"
@@ -36,7 +38,7 @@ func newConfiguredExporter(ctx context.Context, cfg Config) (sdktrace.SpanExport
 	exporters := make([]sdktrace.SpanExporter, 0, 2)
 
 	if cfg.Export.File.Enabled {
-		fileExporter, err := newTraceFileExporter(cfg.Export.File)
+		fileExporter, err := newTraceFileExporter(cfg.Export.File, cfg.Clock)
 		if err != nil {
 			return nil, err
 		}
@@ -44,7 +46,7 @@ func newConfiguredExporter(ctx context.Context, cfg Config) (sdktrace.SpanExport
 	}
 
 	if cfg.Export.Backend.Enabled {
-		backendExporter, err := newBackendSpanExporter(ctx, cfg.Export.Backend)
+		backendExporter, err := newBackendSpanExporter(ctx, cfg.Export.Backend, cfg.Clock)
 		if err != nil {
 			for _, exporter := range exporters {
 				_ = exporter.Shutdown(context.Background())
@@ -96,23 +98,24 @@ func (f *fanoutSpanExporter) Shutdown(ctx context.Context) error {
 }
 
 type backendSpanExporter struct {
-	sender  traceBackendSender
-	journal *traceFailoverJournal
-	replay  *traceReplayManager
+	sender   traceBackendSender
+	journal  *traceFailoverJournal
+	replay   *traceReplayManager
+	maxBytes int
 }
 
-func newBackendSpanExporter(ctx context.Context, cfg BackendConfig) (sdktrace.SpanExporter, error) {
+func newBackendSpanExporter(ctx context.Context, cfg BackendConfig, clk clock.Clock) (sdktrace.SpanExporter, error) {
 	sender, err := newTraceBackendSender(ctx, cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	exporter := &backendSpanExporter{sender: sender}
+	exporter := &backendSpanExporter{sender: sender, maxBytes: cfg.MaxPayloadBytes}
 	if !cfg.Failover.Enabled {
 		return exporter, nil
 	}
 
-	journal, err := newTraceFailoverJournal(cfg.Failover)
+	journal, err := newTraceFailoverJournal(cfg.Failover, clk)
 	if err != nil {
 		_ = sender.Shutdown(context.Background())
 		return nil, err
@@ -124,7 +127,8 @@ func newBackendSpanExporter(ctx context.Context, cfg BackendConfig) (sdktrace.Sp
 	exporter.journal = journal
 
 	if cfg.Failover.Owner == FailoverOwnerApp {
-		exporter.replay = newTraceReplayManager(journal, sender)
+		pacer := spool.NewPacer(cfg.MaxRequestsPerSecond, cfg.MaxBytesPerSecond, clk)
+		exporter.replay = newTraceReplayManager(journal, sender, pacer, clk)
 	}
 
 	return exporter, nil
@@ -139,6 +143,10 @@ func (e *backendSpanExporter) ExportSpans(ctx context.Context, spans []sdktrace.
 	if batch == nil {
 		return nil
 	}
+	if e.maxBytes > 0 && len(spans) > 1 && len(batch.JSON()) > e.maxBytes {
+		half := len(spans) / 2
+		return errors.Join(e.ExportSpans(ctx, spans[:half]), e.ExportSpans(ctx, spans[half:]))
+	}
 
 	if e.journal == nil {
 		if err := e.sender.Send(ctx, batch); err != nil {
@@ -196,17 +204,22 @@ type httpTraceBackend struct {
 	transport string
 }
 
-func newHTTPTraceBackend(cfg BackendConfig, endpoint otlputil.Endpoint) traceBackendSender {
-	scheme := "https"
-	if endpoint.Insecure {
-		scheme = "http"
+func newHTTPTraceBackend(cfg BackendConfig, endpoint otlputil.Endpoint) (traceBackendSender, error) {
+	client := &http.Client{Timeout: cfg.Timeout}
+	tlsConfig, err := otlputil.TLSConfig(cfg.Certificate)
+	if err != nil {
+		return nil, fmt.Errorf("tracer: %w", err)
+	}
+	if tlsConfig != nil && !endpoint.Insecure {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		client.Transport = transport
 	}
-
 	return &httpTraceBackend{
-		client: &http.Client{Timeout: cfg.Timeout},
-		url:    scheme + "://" + endpoint.Host + endpoint.PathWithSuffix("/v1/traces"),
+		client: client,
+		url:    endpoint.ExportURL("/v1/traces", cfg.EndpointIsURL),
 		headers: func() map[string]string {
-			headers := cfg.Credentials.HeaderMap()
+			headers := cfg.headerMap()
 			if headers == nil {
 				return map[string]string{}
 			}
@@ -214,7 +227,7 @@ func newHTTPTraceBackend(cfg BackendConfig, endpoint otlputil.Endpoint) traceBac
 		}(),
 		timeout:   cfg.Timeout,
 		transport: constant.ProtocolHTTP,
-	}
+	}, nil
 }
 
 func (h *httpTraceBackend) Send(ctx context.Context, batch *encodedTraceBatch) error {
@@ -272,10 +285,17 @@ func newGRPCTraceBackend(ctx context.Context, cfg BackendConfig, endpoint otlput
 		return nil, fmt.Errorf("tracer: grpc endpoint %q must not include a path", cfg.Endpoint)
 	}
 
+	tlsConfig, err := otlputil.TLSConfig(cfg.Certificate)
+	if err != nil {
+		return nil, fmt.Errorf("tracer: %w", err)
+	}
 	opts := []grpc.DialOption{}
-	if endpoint.Insecure {
+	switch {
+	case endpoint.Insecure:
 		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	} else {
+	case tlsConfig != nil:
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)))
+	default:
 		opts = append(opts, grpc.WithTransportCredentials(credentials.NewClientTLSFromCert(nil, "")))
 	}
 
@@ -285,7 +305,7 @@ func newGRPCTraceBackend(ctx context.Context, cfg BackendConfig, endpoint otlput
 	}
 
 	headers := metadata.MD{}
-	for key, value := range cfg.Credentials.HeaderMap() {
+	for key, value := range cfg.headerMap() {
 		headers.Append(strings.ToLower(key), value)
 	}
 
@@ -329,7 +349,52 @@ func (g *grpcTraceBackend) Transport() string {
 	return g.transport
 }
 
+type failoverTraceBackend struct {
+	primary   traceBackendSender
+	secondary traceBackendSender
+	failover  *otlputil.Failover
+}
+
+func (f *failoverTraceBackend) Send(ctx context.Context, batch *encodedTraceBatch) error {
+	return f.failover.Do(
+		func() error { return f.primary.Send(ctx, batch) },
+		func() error { return f.secondary.Send(ctx, batch) },
+	)
+}
+
+func (f *failoverTraceBackend) Shutdown(ctx context.Context) error {
+	return errors.Join(f.primary.Shutdown(ctx), f.secondary.Shutdown(ctx))
+}
+
+func (f *failoverTraceBackend) Transport() string {
+	return f.primary.Transport()
+}
+
 func newTraceBackendSender(ctx context.Context, cfg BackendConfig) (traceBackendSender, error) {
+	primary, err := newTraceBackendTarget(ctx, cfg)
+	if err != nil || cfg.Secondary.Endpoint == "" {
+		return primary, err
+	}
+
+	secondaryCfg := cfg
+	secondaryCfg.Endpoint = cfg.Secondary.Endpoint
+	secondaryCfg.Insecure = cfg.Secondary.Insecure
+	secondaryCfg.Credentials = cfg.Secondary.Credentials
+	secondaryCfg.Headers = nil
+	secondary, err := newTraceBackendTarget(ctx, secondaryCfg)
+	if err != nil {
+		_ = primary.Shutdown(context.Background())
+		return nil, err
+	}
+
+	return &failoverTraceBackend{
+		primary:   primary,
+		secondary: secondary,
+		failover:  otlputil.NewFailover("tracer", cfg.Protocol, cfg.Secondary.Threshold, cfg.Secondary.ProbeInterval),
+	}, nil
+}
+
+func newTraceBackendTarget(ctx context.Context, cfg BackendConfig) (traceBackendSender, error) {
 	endpoint, err := otlputil.ParseEndpoint(cfg.Endpoint, cfg.Insecure)
 	if err != nil {
 		return nil, fmt.Errorf("tracer: %w", err)
@@ -337,7 +402,7 @@ func newTraceBackendSender(ctx context.Context, cfg BackendConfig) (traceBackend
 
 	switch cfg.Protocol {
 	case constant.ProtocolHTTP:
-		return newHTTPTraceBackend(cfg, endpoint), nil
+		return newHTTPTraceBackend(cfg, endpoint)
 	case constant.ProtocolGRPC:
 		return newGRPCTraceBackend(ctx, cfg, endpoint)
 	default:
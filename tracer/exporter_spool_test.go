@@ -10,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -89,7 +90,7 @@ func TestTraceFileExporterWritesDailyFile(t *testing.T) {
 		Enabled:   true,
 		Directory: dir,
 		Buffer:    64,
-	})
+	}, nil)
 	if err != nil {
 		t.Fatalf("newTraceFileExporter: %v", err)
 	}
@@ -125,7 +126,7 @@ func TestTraceFileExporterRecreatesDeletedFile(t *testing.T) {
 		Enabled:   true,
 		Directory: dir,
 		Buffer:    64,
-	})
+	}, nil)
 	if err != nil {
 		t.Fatalf("newTraceFileExporter: %v", err)
 	}
@@ -560,7 +561,7 @@ func TestAppFailoverRecoversPendingFilesOnStartup(t *testing.T) {
 		Owner:     FailoverOwnerApp,
 		Directory: failoverDir,
 		Buffer:    64,
-	})
+	}, nil)
 	if err != nil {
 		t.Fatalf("newTraceFailoverJournal: %v", err)
 	}
@@ -591,7 +592,7 @@ func TestAppFailoverRecoversPendingFilesOnStartup(t *testing.T) {
 			Directory: failoverDir,
 			Buffer:    64,
 		},
-	})
+	}, nil)
 	if err != nil {
 		t.Fatalf("newBackendSpanExporter: %v", err)
 	}
@@ -849,3 +850,116 @@ func (s *flakyTraceServer) Export(ctx context.Context, req *coltrace.ExportTrace
 	s.successCount.Add(1)
 	return &coltrace.ExportTraceServiceResponse{}, nil
 }
+
+func TestBackendFailsOverToSecondaryEndpoint(t *testing.T) {
+	var primaryHits atomic.Int32
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		_ = r.Body.Close()
+		primaryHits.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(primary.Close)
+
+	var secondaryHits atomic.Int32
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		_ = r.Body.Close()
+		secondaryHits.Add(1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(secondary.Close)
+
+	cfg := Config{
+		Enabled:     true,
+		ServiceName: "trace-secondary",
+		Export: ExportConfig{
+			Backend: BackendConfig{
+				Enabled:  true,
+				Endpoint: primary.URL,
+				Timeout:  time.Second,
+				Failover: FailoverConfig{Owner: FailoverOwnerApp},
+				Secondary: SecondaryConfig{
+					Endpoint:      secondary.URL,
+					Threshold:     1,
+					ProbeInterval: time.Hour,
+				},
+			},
+		},
+	}
+
+	provider, err := Setup(context.Background(), cfg, resource.Empty())
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = provider.Shutdown(context.Background())
+	})
+
+	tr := provider.provider.Tracer("trace-secondary")
+	for i := range 2 {
+		_, span := tr.Start(context.Background(), fmt.Sprintf("span-%d", i))
+		span.End()
+		if err := provider.ForceFlush(context.Background()); err != nil {
+			t.Fatalf("ForceFlush %d: %v", i, err)
+		}
+	}
+
+	if got := primaryHits.Load(); got != 1 {
+		t.Fatalf("expected primary to be skipped after failing over, hits=%d", got)
+	}
+	if got := secondaryHits.Load(); got != 2 {
+		t.Fatalf("expected both batches on the secondary, hits=%d", got)
+	}
+}
+
+type countingTraceSender struct {
+	mu      sync.Mutex
+	batches []int
+}
+
+func (s *countingTraceSender) Send(_ context.Context, batch *encodedTraceBatch) error {
+	req, err := batch.Request()
+	if err != nil {
+		return err
+	}
+	spans := 0
+	for _, rs := range req.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			spans += len(ss.Spans)
+		}
+	}
+	s.mu.Lock()
+	s.batches = append(s.batches, spans)
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *countingTraceSender) Shutdown(context.Context) error { return nil }
+
+func (s *countingTraceSender) Transport() string { return constant.ProtocolHTTP }
+
+func TestBackendExporterSplitsOversizedBatches(t *testing.T) {
+	sender := &countingTraceSender{}
+	exporter := &backendSpanExporter{sender: sender, maxBytes: 2048}
+
+	stubs := make(tracetest.SpanStubs, 20)
+	for i := range stubs {
+		stubs[i] = tracetest.SpanStub{
+			Name:        fmt.Sprintf("span-%d", i),
+			SpanContext: oteltrace.NewSpanContext(oteltrace.SpanContextConfig{TraceID: oteltrace.TraceID{1}, SpanID: oteltrace.SpanID{byte(i + 1)}}),
+			Attributes:  []attribute.KeyValue{attribute.String("payload", strings.Repeat("x", 200))},
+		}
+	}
+	if err := exporter.ExportSpans(context.Background(), stubs.Snapshots()); err != nil {
+		t.Fatalf("ExportSpans: %v", err)
+	}
+
+	total := 0
+	for _, n := range sender.batches {
+		total += n
+	}
+	if len(sender.batches) < 2 || total != 20 {
+		t.Fatalf("expected 20 spans over several batches, got %v", sender.batches)
+	}
+}
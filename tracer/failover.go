@@ -13,7 +13,9 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/mfahmialkautsar/goo11y/clock"
 	"github.com/mfahmialkautsar/goo11y/internal/otlputil"
+	"github.com/mfahmialkautsar/goo11y/internal/spool"
 )
 
 const (
@@ -28,7 +30,7 @@ type traceFailoverJournal struct {
 	seq       atomic.Uint64
 }
 
-func newTraceFailoverJournal(cfg FailoverConfig) (*traceFailoverJournal, error) {
+func newTraceFailoverJournal(cfg FailoverConfig, clk clock.Clock) (*traceFailoverJournal, error) {
 	if cfg.Directory == "" {
 		return nil, fmt.Errorf("missing trace failover directory")
 	}
@@ -38,7 +40,7 @@ func newTraceFailoverJournal(cfg FailoverConfig) (*traceFailoverJournal, error)
 	return &traceFailoverJournal{
 		directory: cfg.Directory,
 		buffer:    cfg.Buffer,
-		now:       time.Now,
+		now:       clock.OrReal(clk).Now,
 	}, nil
 }
 
@@ -167,6 +169,21 @@ func (j *traceFailoverJournal) OldestReady() (string, bool, error) {
 	return names[0], true, nil
 }
 
+// countJournalEntries returns the number of journaled batches awaiting replay in directory.
+func countJournalEntries(directory string) int {
+	entries, err := os.ReadDir(directory)
+	if err != nil {
+		return 0
+	}
+	count := 0
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), traceJournalExt) {
+			count++
+		}
+	}
+	return count
+}
+
 func (j *traceFailoverJournal) Read(name string) ([]byte, error) {
 	data, err := os.ReadFile(filepath.Join(j.directory, filepath.Base(name)))
 	if err != nil {
@@ -178,17 +195,21 @@ func (j *traceFailoverJournal) Read(name string) ([]byte, error) {
 type traceReplayManager struct {
 	journal *traceFailoverJournal
 	sender  traceBackendSender
+	pacer   *spool.Pacer
+	clock   clock.Clock
 	notify  chan struct{}
 	cancel  context.CancelFunc
 	done    chan struct{}
 	once    sync.Once
 }
 
-func newTraceReplayManager(journal *traceFailoverJournal, sender traceBackendSender) *traceReplayManager {
+func newTraceReplayManager(journal *traceFailoverJournal, sender traceBackendSender, pacer *spool.Pacer, clk clock.Clock) *traceReplayManager {
 	ctx, cancel := context.WithCancel(context.Background())
 	manager := &traceReplayManager{
 		journal: journal,
 		sender:  sender,
+		pacer:   pacer,
+		clock:   clock.OrReal(clk),
 		notify:  make(chan struct{}, 1),
 		cancel:  cancel,
 		done:    make(chan struct{}),
@@ -264,6 +285,9 @@ func (m *traceReplayManager) run(ctx context.Context) {
 			continue
 		}
 
+		if !m.pacer.Wait(ctx, len(payload)) {
+			return
+		}
 		batch := &encodedTraceBatch{json: payload}
 		if err := m.sender.Send(ctx, batch); err != nil {
 			otlputil.LogExportFailure("tracer", m.sender.Transport(), err)
@@ -295,7 +319,7 @@ func (m *traceReplayManager) run(ctx context.Context) {
 }
 
 func (m *traceReplayManager) wait(ctx context.Context, delay time.Duration) bool {
-	timer := time.NewTimer(delay)
+	timer := m.clock.NewTimer(delay)
 	defer timer.Stop()
 
 	select {
@@ -303,7 +327,7 @@ func (m *traceReplayManager) wait(ctx context.Context, delay time.Duration) bool
 		return false
 	case <-m.notify:
 		return true
-	case <-timer.C:
+	case <-timer.C():
 		return true
 	}
 }
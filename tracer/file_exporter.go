@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/mfahmialkautsar/goo11y/clock"
 	"github.com/mfahmialkautsar/goo11y/internal/otlputil"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
@@ -24,8 +25,8 @@ type traceFileExporter struct {
 	sink *dailyTraceFileSink
 }
 
-func newTraceFileExporter(cfg FileConfig) (sdktrace.SpanExporter, error) {
-	sink, err := newDailyTraceFileSink(cfg)
+func newTraceFileExporter(cfg FileConfig, clk clock.Clock) (sdktrace.SpanExporter, error) {
+	sink, err := newDailyTraceFileSink(cfg, clk)
 	if err != nil {
 		return nil, err
 	}
@@ -73,7 +74,7 @@ type dailyTraceFileSink struct {
 	file        *os.File
 }
 
-func newDailyTraceFileSink(cfg FileConfig) (*dailyTraceFileSink, error) {
+func newDailyTraceFileSink(cfg FileConfig, clk clock.Clock) (*dailyTraceFileSink, error) {
 	if cfg.Directory == "" {
 		return nil, fmt.Errorf("missing trace export directory")
 	}
@@ -84,7 +85,7 @@ func newDailyTraceFileSink(cfg FileConfig) (*dailyTraceFileSink, error) {
 	return &dailyTraceFileSink{
 		directory: cfg.Directory,
 		buffer:    cfg.Buffer,
-		now:       time.Now,
+		now:       clock.OrReal(clk).Now,
 	}, nil
 }
 
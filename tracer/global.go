@@ -4,13 +4,12 @@ import (
 	"context"
 	"sync/atomic"
 
-	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/sdk/resource"
 	"go.opentelemetry.io/otel/trace"
 )
 
 var globalProvider atomic.Value
-var disabledProvider = &Provider{}
+var disabledProvider = Nop()
 
 // Init configures the tracer provider and exposes it globally.
 func Init(ctx context.Context, cfg Config, res *resource.Resource, opts ...Option) error {
@@ -18,9 +17,6 @@ func Init(ctx context.Context, cfg Config, res *resource.Resource, opts ...Optio
 	if err != nil {
 		return err
 	}
-	if provider == nil {
-		provider = disabledProvider
-	}
 	Use(provider)
 	return nil
 }
@@ -47,10 +43,7 @@ func Global() *Provider {
 
 // Tracer produces a tracer backed by the global provider.
 func Tracer(name string, opts ...trace.TracerOption) trace.Tracer {
-	if provider := Global(); provider != nil && provider.provider != nil {
-		return provider.provider.Tracer(name, opts...)
-	}
-	return otel.Tracer(name, opts...)
+	return Global().Tracer(name, opts...)
 }
 
 // SpanContext extracts the span context using the global provider.
@@ -0,0 +1,49 @@
+package tracer
+
+import (
+	"context"
+	"encoding/binary"
+	"math/rand"
+	"sync"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type deterministicIDGenerator struct {
+	mu     sync.Mutex
+	random *rand.Rand
+}
+
+var _ sdktrace.IDGenerator = (*deterministicIDGenerator)(nil)
+
+// NewDeterministicIDGenerator returns an IDGenerator producing the same ID sequence for a given seed.
+// It is intended for tests and reproducible fixtures, not for production traffic.
+func NewDeterministicIDGenerator(seed int64) sdktrace.IDGenerator {
+	return &deterministicIDGenerator{random: rand.New(rand.NewSource(seed))}
+}
+
+func (g *deterministicIDGenerator) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var tid trace.TraceID
+	var sid trace.SpanID
+	for !tid.IsValid() {
+		binary.BigEndian.PutUint64(tid[:8], g.random.Uint64())
+		binary.BigEndian.PutUint64(tid[8:], g.random.Uint64())
+	}
+	for !sid.IsValid() {
+		binary.BigEndian.PutUint64(sid[:], g.random.Uint64())
+	}
+	return tid, sid
+}
+
+func (g *deterministicIDGenerator) NewSpanID(ctx context.Context, traceID trace.TraceID) trace.SpanID {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	var sid trace.SpanID
+	for !sid.IsValid() {
+		binary.BigEndian.PutUint64(sid[:], g.random.Uint64())
+	}
+	return sid
+}
@@ -0,0 +1,46 @@
+package tracer
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+func TestDeterministicIDGeneratorRepeatsSequence(t *testing.T) {
+	ctx := context.Background()
+	a := NewDeterministicIDGenerator(42)
+	b := NewDeterministicIDGenerator(42)
+
+	for range 3 {
+		tidA, sidA := a.NewIDs(ctx)
+		tidB, sidB := b.NewIDs(ctx)
+		if tidA != tidB || sidA != sidB {
+			t.Fatalf("sequences diverged: %s/%s vs %s/%s", tidA, sidA, tidB, sidB)
+		}
+		if !tidA.IsValid() || !sidA.IsValid() {
+			t.Fatal("expected valid IDs")
+		}
+	}
+}
+
+func TestSetupUsesConfiguredIDGenerator(t *testing.T) {
+	ctx := context.Background()
+	provider, err := Setup(ctx, Config{
+		Enabled:     true,
+		IDGenerator: NewDeterministicIDGenerator(7),
+	}, resource.Empty(), WithSpanExporter(&stubSpanExporter{}))
+	if err != nil {
+		t.Fatalf("setup tracer: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = provider.Shutdown(ctx)
+	})
+
+	wantTrace, _ := NewDeterministicIDGenerator(7).NewIDs(ctx)
+	_, span := provider.Tracer("idgen").Start(ctx, "op")
+	span.End()
+	if got := span.SpanContext().TraceID(); got != wantTrace {
+		t.Fatalf("unexpected trace ID: got %s want %s", got, wantTrace)
+	}
+}
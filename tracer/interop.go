@@ -0,0 +1,273 @@
+package tracer
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Interop modes accepted by Config.Interop.
+const (
+	InteropXRay       = "xray"
+	InteropCloudTrace = "gcp"
+	InteropDatadog    = "datadog"
+)
+
+const (
+	xrayTraceHeader       = "X-Amzn-Trace-Id"
+	cloudTraceHeader      = "X-Cloud-Trace-Context"
+	xrayTraceIDVersion    = "1"
+	xrayEpochHexLength    = 8
+	xrayRandomHexLength   = 24
+	cloudTraceSampledFlag = "o=1"
+
+	datadogTraceIDHeader  = "x-datadog-trace-id"
+	datadogParentIDHeader = "x-datadog-parent-id"
+	datadogPriorityHeader = "x-datadog-sampling-priority"
+	datadogTagsHeader     = "x-datadog-tags"
+	datadogTraceIDTag     = "_dd.p.tid"
+)
+
+// interopPropagator returns the propagator installed for the given interop mode.
+// W3C trace context and baggage stay enabled so mixed fleets keep correlating.
+func interopPropagator(mode string) propagation.TextMapPropagator {
+	propagators := []propagation.TextMapPropagator{}
+	switch mode {
+	case InteropXRay:
+		propagators = append(propagators, XRayPropagator{})
+	case InteropCloudTrace:
+		propagators = append(propagators, CloudTracePropagator{})
+	case InteropDatadog:
+		propagators = append(propagators, DatadogPropagator{})
+	}
+	propagators = append(propagators, propagation.TraceContext{}, propagation.Baggage{})
+	return propagation.NewCompositeTextMapPropagator(propagators...)
+}
+
+type xrayIDGenerator struct{}
+
+var _ sdktrace.IDGenerator = (*xrayIDGenerator)(nil)
+
+// NewXRayIDGenerator returns an IDGenerator whose trace IDs start with the current epoch seconds,
+// as AWS X-Ray requires.
+func NewXRayIDGenerator() sdktrace.IDGenerator {
+	return &xrayIDGenerator{}
+}
+
+func (g *xrayIDGenerator) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	var tid trace.TraceID
+	binary.BigEndian.PutUint32(tid[:4], uint32(time.Now().Unix()))
+	_, _ = rand.Read(tid[4:])
+	return tid, g.NewSpanID(ctx, tid)
+}
+
+func (g *xrayIDGenerator) NewSpanID(context.Context, trace.TraceID) trace.SpanID {
+	var sid trace.SpanID
+	for !sid.IsValid() {
+		_, _ = rand.Read(sid[:])
+	}
+	return sid
+}
+
+// XRayPropagator reads and writes the AWS X-Ray X-Amzn-Trace-Id header.
+type XRayPropagator struct{}
+
+var _ propagation.TextMapPropagator = XRayPropagator{}
+
+// Inject writes the span context from ctx into carrier.
+func (XRayPropagator) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+	tid := sc.TraceID().String()
+	sampled := "0"
+	if sc.IsSampled() {
+		sampled = "1"
+	}
+	carrier.Set(xrayTraceHeader, fmt.Sprintf("Root=%s-%s-%s;Parent=%s;Sampled=%s",
+		xrayTraceIDVersion, tid[:xrayEpochHexLength], tid[xrayEpochHexLength:], sc.SpanID().String(), sampled))
+}
+
+// Extract reads the X-Ray header from carrier into a remote span context.
+func (XRayPropagator) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	header := carrier.Get(xrayTraceHeader)
+	if header == "" {
+		return ctx
+	}
+	var cfg trace.SpanContextConfig
+	for part := range strings.SplitSeq(header, ";") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "Root":
+			segments := strings.Split(value, "-")
+			if len(segments) != 3 || segments[0] != xrayTraceIDVersion ||
+				len(segments[1]) != xrayEpochHexLength || len(segments[2]) != xrayRandomHexLength {
+				return ctx
+			}
+			tid, err := trace.TraceIDFromHex(segments[1] + segments[2])
+			if err != nil {
+				return ctx
+			}
+			cfg.TraceID = tid
+		case "Parent":
+			sid, err := trace.SpanIDFromHex(value)
+			if err != nil {
+				return ctx
+			}
+			cfg.SpanID = sid
+		case "Sampled":
+			if value == "1" {
+				cfg.TraceFlags = trace.FlagsSampled
+			}
+		}
+	}
+	cfg.Remote = true
+	sc := trace.NewSpanContext(cfg)
+	if !sc.IsValid() {
+		return ctx
+	}
+	return trace.ContextWithRemoteSpanContext(ctx, sc)
+}
+
+// Fields returns the header names written by Inject.
+func (XRayPropagator) Fields() []string {
+	return []string{xrayTraceHeader}
+}
+
+// CloudTracePropagator reads and writes the Google Cloud Trace X-Cloud-Trace-Context header.
+type CloudTracePropagator struct{}
+
+var _ propagation.TextMapPropagator = CloudTracePropagator{}
+
+// Inject writes the span context from ctx into carrier.
+func (CloudTracePropagator) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+	sid := sc.SpanID()
+	value := sc.TraceID().String() + "/" + strconv.FormatUint(binary.BigEndian.Uint64(sid[:]), 10)
+	if sc.IsSampled() {
+		value += ";" + cloudTraceSampledFlag
+	} else {
+		value += ";o=0"
+	}
+	carrier.Set(cloudTraceHeader, value)
+}
+
+// Extract reads the Cloud Trace header from carrier into a remote span context.
+func (CloudTracePropagator) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	header := carrier.Get(cloudTraceHeader)
+	if header == "" {
+		return ctx
+	}
+	ids, options, _ := strings.Cut(header, ";")
+	traceHex, spanDec, ok := strings.Cut(ids, "/")
+	if !ok {
+		return ctx
+	}
+	tid, err := trace.TraceIDFromHex(traceHex)
+	if err != nil {
+		return ctx
+	}
+	spanValue, err := strconv.ParseUint(spanDec, 10, 64)
+	if err != nil {
+		return ctx
+	}
+	var sid trace.SpanID
+	binary.BigEndian.PutUint64(sid[:], spanValue)
+
+	cfg := trace.SpanContextConfig{TraceID: tid, SpanID: sid, Remote: true}
+	if strings.TrimSpace(options) == cloudTraceSampledFlag {
+		cfg.TraceFlags = trace.FlagsSampled
+	}
+	sc := trace.NewSpanContext(cfg)
+	if !sc.IsValid() {
+		return ctx
+	}
+	return trace.ContextWithRemoteSpanContext(ctx, sc)
+}
+
+// Fields returns the header names written by Inject.
+func (CloudTracePropagator) Fields() []string {
+	return []string{cloudTraceHeader}
+}
+
+// DatadogPropagator reads and writes the Datadog x-datadog-* headers. Datadog carries the low 64 bits
+// of the trace ID in decimal and the high 64 bits, in hex, as the _dd.p.tid tag of x-datadog-tags.
+type DatadogPropagator struct{}
+
+var _ propagation.TextMapPropagator = DatadogPropagator{}
+
+// Inject writes the span context from ctx into carrier.
+func (DatadogPropagator) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+	tid := sc.TraceID()
+	sid := sc.SpanID()
+	carrier.Set(datadogTraceIDHeader, strconv.FormatUint(binary.BigEndian.Uint64(tid[8:]), 10))
+	carrier.Set(datadogParentIDHeader, strconv.FormatUint(binary.BigEndian.Uint64(sid[:]), 10))
+	priority := "0"
+	if sc.IsSampled() {
+		priority = "1"
+	}
+	carrier.Set(datadogPriorityHeader, priority)
+	if high := binary.BigEndian.Uint64(tid[:8]); high != 0 {
+		carrier.Set(datadogTagsHeader, fmt.Sprintf("%s=%016x", datadogTraceIDTag, high))
+	}
+}
+
+// Extract reads the Datadog headers from carrier into a remote span context.
+func (DatadogPropagator) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	low, err := strconv.ParseUint(carrier.Get(datadogTraceIDHeader), 10, 64)
+	if err != nil {
+		return ctx
+	}
+	parent, err := strconv.ParseUint(carrier.Get(datadogParentIDHeader), 10, 64)
+	if err != nil {
+		return ctx
+	}
+
+	var tid trace.TraceID
+	binary.BigEndian.PutUint64(tid[8:], low)
+	for tag := range strings.SplitSeq(carrier.Get(datadogTagsHeader), ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(tag), "=")
+		if !ok || key != datadogTraceIDTag {
+			continue
+		}
+		if high, err := strconv.ParseUint(value, 16, 64); err == nil && len(value) == 16 {
+			binary.BigEndian.PutUint64(tid[:8], high)
+		}
+	}
+	var sid trace.SpanID
+	binary.BigEndian.PutUint64(sid[:], parent)
+
+	cfg := trace.SpanContextConfig{TraceID: tid, SpanID: sid, Remote: true}
+	if priority, err := strconv.Atoi(carrier.Get(datadogPriorityHeader)); err == nil && priority > 0 {
+		cfg.TraceFlags = trace.FlagsSampled
+	}
+	sc := trace.NewSpanContext(cfg)
+	if !sc.IsValid() {
+		return ctx
+	}
+	return trace.ContextWithRemoteSpanContext(ctx, sc)
+}
+
+// Fields returns the header names written by Inject.
+func (DatadogPropagator) Fields() []string {
+	return []string{datadogTraceIDHeader, datadogParentIDHeader, datadogPriorityHeader, datadogTagsHeader}
+}
@@ -0,0 +1,130 @@
+package tracer
+
+import (
+	"context"
+	"encoding/binary"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func remoteContext(t *testing.T, sampled bool) context.Context {
+	t.Helper()
+	tid, _ := trace.TraceIDFromHex("5759e988bd862e3fe1be46a994272793")
+	sid, _ := trace.SpanIDFromHex("53995c3f42cd8ad8")
+	cfg := trace.SpanContextConfig{TraceID: tid, SpanID: sid}
+	if sampled {
+		cfg.TraceFlags = trace.FlagsSampled
+	}
+	return trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(cfg))
+}
+
+func TestXRayPropagatorRoundTrip(t *testing.T) {
+	carrier := propagation.MapCarrier{}
+	XRayPropagator{}.Inject(remoteContext(t, true), carrier)
+
+	want := "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1"
+	if got := carrier.Get(xrayTraceHeader); got != want {
+		t.Fatalf("unexpected header: got %q want %q", got, want)
+	}
+
+	sc := trace.SpanContextFromContext(XRayPropagator{}.Extract(context.Background(), carrier))
+	if sc.TraceID().String() != "5759e988bd862e3fe1be46a994272793" || sc.SpanID().String() != "53995c3f42cd8ad8" || !sc.IsSampled() || !sc.IsRemote() {
+		t.Fatalf("unexpected extracted span context %+v", sc)
+	}
+
+	bad := propagation.MapCarrier{xrayTraceHeader: "Root=2-abc;Parent=zz"}
+	if trace.SpanContextFromContext(XRayPropagator{}.Extract(context.Background(), bad)).IsValid() {
+		t.Fatal("expected malformed header to be ignored")
+	}
+}
+
+func TestCloudTracePropagatorRoundTrip(t *testing.T) {
+	carrier := propagation.MapCarrier{}
+	CloudTracePropagator{}.Inject(remoteContext(t, false), carrier)
+
+	sid, _ := trace.SpanIDFromHex("53995c3f42cd8ad8")
+	decimal := binary.BigEndian.Uint64(sid[:])
+	header := carrier.Get(cloudTraceHeader)
+	if header != "5759e988bd862e3fe1be46a994272793/"+strconv.FormatUint(decimal, 10)+";o=0" {
+		t.Fatalf("unexpected header %q", header)
+	}
+
+	sc := trace.SpanContextFromContext(CloudTracePropagator{}.Extract(context.Background(), propagation.MapCarrier{
+		cloudTraceHeader: "5759e988bd862e3fe1be46a994272793/" + strconv.FormatUint(decimal, 10) + ";o=1",
+	}))
+	if sc.SpanID() != sid || !sc.IsSampled() {
+		t.Fatalf("unexpected extracted span context %+v", sc)
+	}
+}
+
+func TestXRayIDGeneratorEmbedsEpoch(t *testing.T) {
+	before := uint32(time.Now().Unix())
+	tid, sid := NewXRayIDGenerator().NewIDs(context.Background())
+	epoch := binary.BigEndian.Uint32(tid[:4])
+	if epoch < before || epoch > uint32(time.Now().Unix()) {
+		t.Fatalf("trace ID epoch %d outside expected window", epoch)
+	}
+	if !tid.IsValid() || !sid.IsValid() {
+		t.Fatal("expected valid IDs")
+	}
+}
+
+func TestSetupInstallsInteropPropagator(t *testing.T) {
+	previous := otel.GetTextMapPropagator()
+	t.Cleanup(func() { otel.SetTextMapPropagator(previous) })
+
+	ctx := context.Background()
+	provider, err := Setup(ctx, Config{Enabled: true, Interop: InteropXRay}, resource.Empty(), WithSpanExporter(&stubSpanExporter{}))
+	if err != nil {
+		t.Fatalf("setup tracer: %v", err)
+	}
+	t.Cleanup(func() { _ = provider.Shutdown(ctx) })
+
+	fields := otel.GetTextMapPropagator().Fields()
+	if !slices.Contains(fields, xrayTraceHeader) || !slices.Contains(fields, "traceparent") {
+		t.Fatalf("expected X-Ray and W3C headers, got %v", fields)
+	}
+
+	if _, err := Setup(ctx, Config{Enabled: true, Interop: "zipkin"}, resource.Empty(), WithSpanExporter(&stubSpanExporter{})); err == nil {
+		t.Fatal("expected unknown interop mode to be rejected")
+	}
+}
+
+func TestDatadogPropagatorRoundTrip(t *testing.T) {
+	carrier := propagation.MapCarrier{}
+	DatadogPropagator{}.Inject(remoteContext(t, true), carrier)
+
+	tid, _ := trace.TraceIDFromHex("5759e988bd862e3fe1be46a994272793")
+	sid, _ := trace.SpanIDFromHex("53995c3f42cd8ad8")
+	wantTrace := strconv.FormatUint(binary.BigEndian.Uint64(tid[8:]), 10)
+	wantParent := strconv.FormatUint(binary.BigEndian.Uint64(sid[:]), 10)
+	if carrier.Get(datadogTraceIDHeader) != wantTrace || carrier.Get(datadogParentIDHeader) != wantParent {
+		t.Fatalf("unexpected headers %v", carrier)
+	}
+	if carrier.Get(datadogPriorityHeader) != "1" || carrier.Get(datadogTagsHeader) != "_dd.p.tid=5759e988bd862e3f" {
+		t.Fatalf("unexpected priority or tags %v", carrier)
+	}
+
+	sc := trace.SpanContextFromContext(DatadogPropagator{}.Extract(context.Background(), carrier))
+	if sc.TraceID() != tid || sc.SpanID() != sid || !sc.IsSampled() || !sc.IsRemote() {
+		t.Fatalf("unexpected extracted span context %+v", sc)
+	}
+
+	short := propagation.MapCarrier{datadogTraceIDHeader: "42", datadogParentIDHeader: "7", datadogPriorityHeader: "-1"}
+	sc = trace.SpanContextFromContext(DatadogPropagator{}.Extract(context.Background(), short))
+	if sc.TraceID().String() != "0000000000000000000000000000002a" || sc.IsSampled() {
+		t.Fatalf("unexpected 64-bit span context %+v", sc)
+	}
+
+	bad := propagation.MapCarrier{datadogTraceIDHeader: "abc", datadogParentIDHeader: "7"}
+	if trace.SpanContextFromContext(DatadogPropagator{}.Extract(context.Background(), bad)).IsValid() {
+		t.Fatal("expected malformed header to be ignored")
+	}
+}
@@ -0,0 +1,33 @@
+package tracer
+
+import sdktrace "go.opentelemetry.io/otel/sdk/trace"
+
+// LimitsConfig bounds what a single span may hold. A zero field keeps the SDK default, which the
+// OTEL_SPAN_*_LIMIT and OTEL_ATTRIBUTE_*_LIMIT variables can change (128 for counts, unlimited
+// value length); a negative field removes the limit. Attributes, events, and links past a limit
+// are dropped, and longer string values are truncated.
+type LimitsConfig struct {
+	AttributeValueLength   int
+	AttributeCount         int
+	EventCount             int
+	LinkCount              int
+	AttributePerEventCount int
+	AttributePerLinkCount  int
+}
+
+// spanLimits overlays the configured limits on the SDK defaults.
+func (c LimitsConfig) spanLimits() sdktrace.SpanLimits {
+	limits := sdktrace.NewSpanLimits()
+	set := func(dst *int, value int) {
+		if value != 0 {
+			*dst = value
+		}
+	}
+	set(&limits.AttributeValueLengthLimit, c.AttributeValueLength)
+	set(&limits.AttributeCountLimit, c.AttributeCount)
+	set(&limits.EventCountLimit, c.EventCount)
+	set(&limits.LinkCountLimit, c.LinkCount)
+	set(&limits.AttributePerEventCountLimit, c.AttributePerEventCount)
+	set(&limits.AttributePerLinkCountLimit, c.AttributePerLinkCount)
+	return limits
+}
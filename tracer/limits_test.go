@@ -0,0 +1,58 @@
+package tracer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestLimitsConfigOverlaysSDKDefaults(t *testing.T) {
+	t.Setenv("OTEL_SPAN_EVENT_COUNT_LIMIT", "7")
+
+	limits := LimitsConfig{AttributeCount: 3, AttributeValueLength: 8, LinkCount: -1}.spanLimits()
+	if limits.AttributeCountLimit != 3 || limits.AttributeValueLengthLimit != 8 {
+		t.Fatalf("expected configured limits, got %+v", limits)
+	}
+	if limits.LinkCountLimit != -1 {
+		t.Fatalf("expected unlimited links, got %d", limits.LinkCountLimit)
+	}
+	if limits.EventCountLimit != 7 {
+		t.Fatalf("expected env event limit to remain, got %d", limits.EventCountLimit)
+	}
+	if limits.AttributePerEventCountLimit != sdktrace.DefaultAttributePerEventCountLimit {
+		t.Fatalf("expected SDK default per-event limit, got %d", limits.AttributePerEventCountLimit)
+	}
+}
+
+func TestLimitsApplyToSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(recorder),
+		sdktrace.WithSpanLimits(LimitsConfig{AttributeCount: 2, AttributeValueLength: 4}.spanLimits()),
+	)
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+
+	_, span := tp.Tracer("limits").Start(context.Background(), "bounded")
+	span.SetAttributes(
+		attribute.String("a", strings.Repeat("x", 100)),
+		attribute.String("b", "y"),
+		attribute.String("c", "z"),
+	)
+	span.End()
+
+	ended := recorder.Ended()
+	if len(ended) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(ended))
+	}
+	attrs := ended[0].Attributes()
+	if len(attrs) != 2 || ended[0].DroppedAttributes() != 1 {
+		t.Fatalf("expected 2 attributes and 1 dropped, got %v (%d dropped)", attrs, ended[0].DroppedAttributes())
+	}
+	if got := attrs[0].Value.AsString(); got != "xxxx" {
+		t.Fatalf("expected truncated value, got %q", got)
+	}
+}
@@ -0,0 +1,97 @@
+package tracer
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.28.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const linkInstrumentation = "github.com/mfahmialkautsar/goo11y/tracer"
+
+// LinkFromContext returns a link to the span context carried by ctx.
+// Reports false if ctx carries no valid span context.
+func LinkFromContext(ctx context.Context, attrs ...attribute.KeyValue) (trace.Link, bool) {
+	if ctx == nil {
+		return trace.Link{}, false
+	}
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if !spanCtx.IsValid() {
+		return trace.Link{}, false
+	}
+	return trace.Link{SpanContext: spanCtx, Attributes: attrs}, true
+}
+
+// LinkFromCarrier extracts the remote span context stored in carrier with the global propagator and
+// returns a link to it. Reports false if the carrier holds no valid trace context.
+func LinkFromCarrier(carrier propagation.TextMapCarrier, attrs ...attribute.KeyValue) (trace.Link, bool) {
+	if carrier == nil {
+		return trace.Link{}, false
+	}
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), carrier)
+	return LinkFromContext(ctx, attrs...)
+}
+
+// LinksFromContexts returns one link per distinct valid span context in ctxs, in order.
+func LinksFromContexts(ctxs ...context.Context) []trace.Link {
+	links := make([]trace.Link, 0, len(ctxs))
+	for _, ctx := range ctxs {
+		if link, ok := LinkFromContext(ctx); ok {
+			links = appendLink(links, link)
+		}
+	}
+	return links
+}
+
+// LinksFromHeaders returns one link per distinct valid trace context found in the headers of a message
+// batch, such as Kafka or SQS message attributes. Messages without trace context are skipped.
+func LinksFromHeaders(batch []map[string]string) []trace.Link {
+	links := make([]trace.Link, 0, len(batch))
+	for _, headers := range batch {
+		if len(headers) == 0 {
+			continue
+		}
+		if link, ok := LinkFromCarrier(propagation.MapCarrier(headers)); ok {
+			links = appendLink(links, link)
+		}
+	}
+	return links
+}
+
+func appendLink(links []trace.Link, link trace.Link) []trace.Link {
+	for _, existing := range links {
+		if existing.SpanContext.Equal(link.SpanContext) {
+			return links
+		}
+	}
+	return append(links, link)
+}
+
+// StartLinkedSpan starts a span as a child of ctx that links to the spans carried by linkCtxs.
+// Contexts without a valid span context are ignored.
+func (p *Provider) StartLinkedSpan(ctx context.Context, name string, linkCtxs ...context.Context) (context.Context, trace.Span) {
+	return p.Tracer(linkInstrumentation).Start(ctx, name, trace.WithLinks(LinksFromContexts(linkCtxs...)...))
+}
+
+// StartBatchSpan starts a consumer span for processing a message batch. It links to every message
+// that carries trace context in its headers and records the batch size as messaging.batch.message_count.
+func (p *Provider) StartBatchSpan(ctx context.Context, name string, batch []map[string]string) (context.Context, trace.Span) {
+	return p.Tracer(linkInstrumentation).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithLinks(LinksFromHeaders(batch)...),
+		trace.WithAttributes(semconv.MessagingBatchMessageCount(len(batch))),
+	)
+}
+
+// StartLinkedSpan starts a linked span using the global provider.
+func StartLinkedSpan(ctx context.Context, name string, linkCtxs ...context.Context) (context.Context, trace.Span) {
+	return Global().StartLinkedSpan(ctx, name, linkCtxs...)
+}
+
+// StartBatchSpan starts a batch consumer span using the global provider.
+func StartBatchSpan(ctx context.Context, name string, batch []map[string]string) (context.Context, trace.Span) {
+	return Global().StartBatchSpan(ctx, name, batch)
+}
@@ -0,0 +1,88 @@
+package tracer
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func newLinkTestProvider(t *testing.T) (*Provider, *tracetest.SpanRecorder) {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	sdk := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() { _ = sdk.Shutdown(context.Background()) })
+	return &Provider{provider: sdk}, recorder
+}
+
+func TestStartLinkedSpanLinksValidContexts(t *testing.T) {
+	provider, recorder := newLinkTestProvider(t)
+	tr := provider.Tracer("links")
+
+	first, a := tr.Start(context.Background(), "producer-a")
+	second, b := tr.Start(context.Background(), "producer-b")
+	a.End()
+	b.End()
+
+	_, span := provider.StartLinkedSpan(context.Background(), "consume", first, second, first, context.Background())
+	span.End()
+
+	ended := recorder.Ended()
+	links := ended[len(ended)-1].Links()
+	if len(links) != 2 {
+		t.Fatalf("expected 2 distinct links, got %d", len(links))
+	}
+	if !links[0].SpanContext.Equal(a.SpanContext()) || !links[1].SpanContext.Equal(b.SpanContext()) {
+		t.Fatalf("unexpected links %v", links)
+	}
+}
+
+func TestStartBatchSpanLinksMessageHeaders(t *testing.T) {
+	prev := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(prev) })
+
+	provider, recorder := newLinkTestProvider(t)
+	producerCtx, producer := provider.Tracer("links").Start(context.Background(), "produce")
+	producer.End()
+
+	headers := map[string]string{}
+	otel.GetTextMapPropagator().Inject(producerCtx, propagation.MapCarrier(headers))
+	batch := []map[string]string{headers, nil, {"other": "value"}, headers}
+
+	_, span := provider.StartBatchSpan(context.Background(), "process batch", batch)
+	span.End()
+
+	ended := recorder.Ended()
+	consumer := ended[len(ended)-1]
+	if consumer.SpanKind() != trace.SpanKindConsumer {
+		t.Fatalf("expected consumer span, got %v", consumer.SpanKind())
+	}
+	links := consumer.Links()
+	if len(links) != 1 || links[0].SpanContext.SpanID() != producer.SpanContext().SpanID() {
+		t.Fatalf("expected one link to the producer, got %v", links)
+	}
+	var count int64
+	for _, attr := range consumer.Attributes() {
+		if attr.Key == "messaging.batch.message_count" {
+			count = attr.Value.AsInt64()
+		}
+	}
+	if count != 4 {
+		t.Fatalf("expected batch size 4, got %d", count)
+	}
+}
+
+func TestLinkFromCarrierWithoutContext(t *testing.T) {
+	if _, ok := LinkFromCarrier(propagation.MapCarrier{}); ok {
+		t.Fatal("expected no link from an empty carrier")
+	}
+	var missing context.Context
+	if _, ok := LinkFromContext(missing); ok {
+		t.Fatal("expected no link from a nil context")
+	}
+}
@@ -0,0 +1,243 @@
+package tracer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mfahmialkautsar/goo11y/internal/otlputil"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const remoteSamplingResponseLimit = 1 << 20
+
+// RemoteSamplingConfig polls a Jaeger remote sampling endpoint, such as the collector's
+// jaegerremotesampling extension at http://collector:5778/sampling, for the service's strategy.
+// SampleRatio applies until the first strategy arrives and whenever the endpoint cannot be read.
+type RemoteSamplingConfig struct {
+	URL          string        `validate:"omitempty,url"`
+	PollInterval time.Duration `default:"1m" validate:"gte=0"`
+	Timeout      time.Duration `default:"5s" validate:"gte=0"`
+}
+
+// samplingStrategy mirrors the Jaeger sampling strategy response. Jaeger encodes strategyType either
+// as its name or as its enum number, so it is decoded leniently.
+type samplingStrategy struct {
+	StrategyType          json.RawMessage `json:"strategyType"`
+	ProbabilisticSampling *struct {
+		SamplingRate float64 `json:"samplingRate"`
+	} `json:"probabilisticSampling"`
+	RateLimitingSampling *struct {
+		MaxTracesPerSecond float64 `json:"maxTracesPerSecond"`
+	} `json:"rateLimitingSampling"`
+	OperationSampling *struct {
+		DefaultSamplingProbability float64 `json:"defaultSamplingProbability"`
+		PerOperationStrategies     []struct {
+			Operation             string `json:"operation"`
+			ProbabilisticSampling struct {
+				SamplingRate float64 `json:"samplingRate"`
+			} `json:"probabilisticSampling"`
+		} `json:"perOperationStrategies"`
+	} `json:"operationSampling"`
+}
+
+// sampler converts the strategy to an SDK sampler.
+func (s samplingStrategy) sampler() (sdktrace.Sampler, error) {
+	if op := s.OperationSampling; op != nil {
+		perOperation := make(map[string]sdktrace.Sampler, len(op.PerOperationStrategies))
+		for _, strategy := range op.PerOperationStrategies {
+			perOperation[strategy.Operation] = sdktrace.TraceIDRatioBased(strategy.ProbabilisticSampling.SamplingRate)
+		}
+		return perOperationSampler{
+			fallback:     sdktrace.TraceIDRatioBased(op.DefaultSamplingProbability),
+			perOperation: perOperation,
+		}, nil
+	}
+	kind := strings.Trim(string(s.StrategyType), `"`)
+	switch {
+	case (kind == "RATE_LIMITING" || kind == "1") && s.RateLimitingSampling != nil:
+		return newRateLimitingSampler(s.RateLimitingSampling.MaxTracesPerSecond), nil
+	case s.ProbabilisticSampling != nil:
+		return sdktrace.TraceIDRatioBased(s.ProbabilisticSampling.SamplingRate), nil
+	default:
+		return nil, fmt.Errorf("unsupported sampling strategy %q", kind)
+	}
+}
+
+// remoteSampler delegates to the most recently fetched strategy.
+type remoteSampler struct {
+	current atomic.Pointer[sdktrace.Sampler]
+	client  *http.Client
+	url     string
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+var _ sdktrace.Sampler = (*remoteSampler)(nil)
+
+func newRemoteSampler(cfg RemoteSamplingConfig, serviceName string, initial sdktrace.Sampler) (*remoteSampler, error) {
+	endpoint, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("remote sampling url: %w", err)
+	}
+	query := endpoint.Query()
+	query.Set("service", serviceName)
+	endpoint.RawQuery = query.Encode()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &remoteSampler{
+		client: &http.Client{Timeout: cfg.Timeout},
+		url:    endpoint.String(),
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	s.current.Store(&initial)
+	return s, nil
+}
+
+// start fetches the strategy right away and then every interval until close.
+func (s *remoteSampler) start(interval time.Duration) {
+	go func() {
+		defer close(s.done)
+		s.refresh()
+		if interval <= 0 {
+			return
+		}
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-ticker.C:
+				s.refresh()
+			}
+		}
+	}()
+}
+
+func (s *remoteSampler) refresh() {
+	sampler, err := s.fetch(s.ctx)
+	if err != nil {
+		if s.ctx.Err() != nil {
+			return
+		}
+		otlputil.LogExportFailure("tracer", "remote-sampling", err)
+		return
+	}
+	s.current.Store(&sampler)
+}
+
+func (s *remoteSampler) fetch(ctx context.Context) (sdktrace.Sampler, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetch sampling strategy: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch sampling strategy: unexpected status %s", resp.Status)
+	}
+
+	var strategy samplingStrategy
+	if err := json.NewDecoder(io.LimitReader(resp.Body, remoteSamplingResponseLimit)).Decode(&strategy); err != nil {
+		return nil, fmt.Errorf("decode sampling strategy: %w", err)
+	}
+	sampler, err := strategy.sampler()
+	if err != nil {
+		return nil, err
+	}
+	// The strategy decides for root spans only; children follow their parent's decision, so a
+	// trace sampled upstream is not cut in half here.
+	return sdktrace.ParentBased(sampler), nil
+}
+
+// close stops polling and waits for an in-flight fetch to return.
+func (s *remoteSampler) close() {
+	s.cancel()
+	<-s.done
+}
+
+func (s *remoteSampler) ShouldSample(params sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	return (*s.current.Load()).ShouldSample(params)
+}
+
+func (s *remoteSampler) Description() string {
+	return "JaegerRemoteSampler{" + (*s.current.Load()).Description() + "}"
+}
+
+// perOperationSampler applies a ratio per span name, falling back to the default probability.
+type perOperationSampler struct {
+	fallback     sdktrace.Sampler
+	perOperation map[string]sdktrace.Sampler
+}
+
+func (s perOperationSampler) ShouldSample(params sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	if sampler, ok := s.perOperation[params.Name]; ok {
+		return sampler.ShouldSample(params)
+	}
+	return s.fallback.ShouldSample(params)
+}
+
+func (s perOperationSampler) Description() string {
+	return fmt.Sprintf("PerOperationSampler{operations:%d,default:%s}", len(s.perOperation), s.fallback.Description())
+}
+
+// rateLimitingSampler samples up to maxPerSecond traces per second with a token bucket.
+type rateLimitingSampler struct {
+	maxPerSecond float64
+
+	mu      sync.Mutex
+	tokens  float64
+	updated time.Time
+}
+
+func newRateLimitingSampler(maxPerSecond float64) *rateLimitingSampler {
+	s := &rateLimitingSampler{maxPerSecond: maxPerSecond, updated: time.Now()}
+	if maxPerSecond > 0 {
+		s.tokens = math.Max(maxPerSecond, 1)
+	}
+	return s
+}
+
+func (s *rateLimitingSampler) ShouldSample(params sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	psc := trace.SpanContextFromContext(params.ParentContext)
+	result := sdktrace.SamplingResult{Decision: sdktrace.Drop, Tracestate: psc.TraceState()}
+	if s.take() {
+		result.Decision = sdktrace.RecordAndSample
+	}
+	return result
+}
+
+func (s *rateLimitingSampler) take() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	capacity := math.Max(s.maxPerSecond, 1)
+	s.tokens = math.Min(capacity, s.tokens+now.Sub(s.updated).Seconds()*s.maxPerSecond)
+	s.updated = now
+	if s.tokens < 1 {
+		return false
+	}
+	s.tokens--
+	return true
+}
+
+func (s *rateLimitingSampler) Description() string {
+	return fmt.Sprintf("RateLimitingSampler{%g}", s.maxPerSecond)
+}
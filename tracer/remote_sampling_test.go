@@ -0,0 +1,119 @@
+package tracer
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestRemoteSamplingAppliesFetchedStrategy(t *testing.T) {
+	var body atomic.Value
+	body.Store(`{"strategyType":"PROBABILISTIC","probabilisticSampling":{"samplingRate":0}}`)
+	var service atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		service.Store(r.URL.Query().Get("service"))
+		_, _ = w.Write([]byte(body.Load().(string)))
+	}))
+	t.Cleanup(server.Close)
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider, err := Setup(context.Background(), Config{
+		Enabled:     true,
+		ServiceName: "checkout",
+		SyncExport:  true,
+		RemoteSampling: RemoteSamplingConfig{
+			URL:          server.URL + "/sampling",
+			PollInterval: 20 * time.Millisecond,
+		},
+	}, resource.Empty(), WithSpanExporter(exporter))
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	waitForSampler(t, provider, "TraceIDRatioBased{0}")
+	if got, _ := service.Load().(string); got != "checkout" {
+		t.Fatalf("expected service query parameter, got %q", got)
+	}
+	_, span := provider.Tracer("remote").Start(context.Background(), "dropped")
+	span.End()
+	if len(exporter.GetSpans()) != 0 {
+		t.Fatal("expected span to be dropped by the remote strategy")
+	}
+	parent := trace.ContextWithRemoteSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{1},
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	}))
+	_, span = provider.Tracer("remote").Start(parent, "continued")
+	span.End()
+	if spans := exporter.GetSpans(); len(spans) != 1 || spans[0].Name != "continued" {
+		t.Fatalf("expected the child of a sampled remote parent to be kept, got %v", spans)
+	}
+	exporter.Reset()
+
+	body.Store(`{"strategyType":0,"operationSampling":{"defaultSamplingProbability":0,"perOperationStrategies":[{"operation":"kept","probabilisticSampling":{"samplingRate":1}}]}}`)
+	waitForSampler(t, provider, "PerOperationSampler")
+	for _, name := range []string{"kept", "other"} {
+		_, span := provider.Tracer("remote").Start(context.Background(), name)
+		span.End()
+	}
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || spans[0].Name != "kept" {
+		t.Fatalf("expected only the kept operation to be sampled, got %v", spans)
+	}
+}
+
+func TestRemoteSamplingKeepsRatioWhenEndpointFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	sampler, err := newRemoteSampler(RemoteSamplingConfig{URL: server.URL, Timeout: time.Second}, "checkout", sdktrace.AlwaysSample())
+	if err != nil {
+		t.Fatalf("newRemoteSampler: %v", err)
+	}
+	sampler.start(0)
+	sampler.close()
+	if got := sampler.Description(); got != "JaegerRemoteSampler{AlwaysOnSampler}" {
+		t.Fatalf("expected initial sampler to stay, got %q", got)
+	}
+}
+
+func TestRateLimitingSamplerCapsTracesPerSecond(t *testing.T) {
+	sampler := newRateLimitingSampler(2)
+	sampled := 0
+	for range 10 {
+		if sampler.ShouldSample(sdktrace.SamplingParameters{ParentContext: context.Background()}).Decision == sdktrace.RecordAndSample {
+			sampled++
+		}
+	}
+	if sampled != 2 {
+		t.Fatalf("expected 2 sampled traces, got %d", sampled)
+	}
+	if newRateLimitingSampler(0).take() {
+		t.Fatal("expected zero rate to sample nothing")
+	}
+}
+
+func waitForSampler(t *testing.T, provider *Provider, want string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(provider.sampler.Description(), want) {
+		if time.Now().After(deadline) {
+			t.Fatalf("sampler did not switch to %s, got %s", want, provider.sampler.Description())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
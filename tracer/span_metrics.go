@@ -0,0 +1,127 @@
+package tracer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.28.0"
+)
+
+// SpanMetricsInstrumentation names the meter the span metrics processor records through.
+const SpanMetricsInstrumentation = "github.com/mfahmialkautsar/goo11y/tracer/spanmetrics"
+
+const (
+	spanMetricsLatencyName = "traces_spanmetrics_latency"
+	spanMetricsCallsName   = "traces_spanmetrics_calls_total"
+
+	spanNameKey   = attribute.Key("span.name")
+	spanKindKey   = attribute.Key("span.kind")
+	statusCodeKey = attribute.Key("status.code")
+)
+
+// SpanMetricsConfig derives RED metrics from ended spans locally, like the collector's spanmetrics
+// connector: a traces_spanmetrics_latency histogram in milliseconds and a traces_spanmetrics_calls_total
+// counter, both keyed by service.name, span.name, span.kind and status.code.
+type SpanMetricsConfig struct {
+	Enabled bool
+	// Buckets sets the latency histogram boundaries in milliseconds; when nil the SDK defaults remain.
+	Buckets []float64
+	// Dimensions lists span attribute keys added to the metric attributes when a span carries them.
+	Dimensions []string
+}
+
+// NewSpanMetricsProcessor returns a span processor recording span latency and call counts through m.
+// Register it on a Provider with RegisterSpanProcessor.
+func NewSpanMetricsProcessor(m metric.Meter, cfg SpanMetricsConfig) (sdktrace.SpanProcessor, error) {
+	latencyOpts := []metric.Float64HistogramOption{
+		metric.WithDescription("Duration of ended spans"),
+		metric.WithUnit("ms"),
+	}
+	if len(cfg.Buckets) > 0 {
+		latencyOpts = append(latencyOpts, metric.WithExplicitBucketBoundaries(cfg.Buckets...))
+	}
+	latency, err := m.Float64Histogram(spanMetricsLatencyName, latencyOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("tracer: create span latency histogram: %w", err)
+	}
+	calls, err := m.Int64Counter(
+		spanMetricsCallsName,
+		metric.WithDescription("Number of ended spans"),
+		metric.WithUnit("{call}"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("tracer: create span calls counter: %w", err)
+	}
+
+	dimensions := make([]attribute.Key, 0, len(cfg.Dimensions))
+	for _, key := range cfg.Dimensions {
+		if key != "" {
+			dimensions = append(dimensions, attribute.Key(key))
+		}
+	}
+	return &spanMetricsProcessor{latency: latency, calls: calls, dimensions: dimensions}, nil
+}
+
+type spanMetricsProcessor struct {
+	latency    metric.Float64Histogram
+	calls      metric.Int64Counter
+	dimensions []attribute.Key
+}
+
+func (p *spanMetricsProcessor) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+func (p *spanMetricsProcessor) OnEnd(span sdktrace.ReadOnlySpan) {
+	if span == nil {
+		return
+	}
+
+	attrs := make([]attribute.KeyValue, 0, 4+len(p.dimensions))
+	if res := span.Resource(); res != nil {
+		if name, ok := res.Set().Value(semconv.ServiceNameKey); ok {
+			attrs = append(attrs, semconv.ServiceNameKey.String(name.AsString()))
+		}
+	}
+	attrs = append(attrs,
+		spanNameKey.String(span.Name()),
+		spanKindKey.String("SPAN_KIND_"+kindName(span)),
+		statusCodeKey.String("STATUS_CODE_"+statusName(span)),
+	)
+	if len(p.dimensions) > 0 {
+		spanAttrs := span.Attributes()
+		for _, key := range p.dimensions {
+			for _, kv := range spanAttrs {
+				if kv.Key == key {
+					attrs = append(attrs, kv)
+					break
+				}
+			}
+		}
+	}
+
+	set := metric.WithAttributeSet(attribute.NewSet(attrs...))
+	elapsed := span.EndTime().Sub(span.StartTime())
+	ctx := context.Background()
+	p.latency.Record(ctx, float64(elapsed.Nanoseconds())/1e6, set)
+	p.calls.Add(ctx, 1, set)
+}
+
+func (p *spanMetricsProcessor) Shutdown(context.Context) error { return nil }
+
+func (p *spanMetricsProcessor) ForceFlush(context.Context) error { return nil }
+
+func kindName(span sdktrace.ReadOnlySpan) string {
+	switch kind := span.SpanKind().String(); kind {
+	case "internal", "server", "client", "producer", "consumer":
+		return strings.ToUpper(kind)
+	default:
+		return "UNSPECIFIED"
+	}
+}
+
+func statusName(span sdktrace.ReadOnlySpan) string {
+	return strings.ToUpper(span.Status().Code.String())
+}
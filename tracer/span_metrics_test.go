@@ -0,0 +1,100 @@
+package tracer
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.28.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSpanMetricsProcessorRecordsLatencyAndCalls(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	t.Cleanup(func() { _ = mp.Shutdown(context.Background()) })
+
+	processor, err := NewSpanMetricsProcessor(mp.Meter(SpanMetricsInstrumentation), SpanMetricsConfig{
+		Buckets:    []float64{1, 10, 100},
+		Dimensions: []string{"http.route"},
+	})
+	if err != nil {
+		t.Fatalf("NewSpanMetricsProcessor: %v", err)
+	}
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(processor),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName("checkout"))),
+	)
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+
+	tr := tp.Tracer("spanmetrics")
+	for range 2 {
+		_, span := tr.Start(context.Background(), "GET /orders",
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(attribute.String("http.route", "/orders"), attribute.String("user.id", "42")),
+		)
+		span.End()
+	}
+	_, failed := tr.Start(context.Background(), "charge")
+	failed.SetStatus(codes.Error, "declined")
+	failed.End()
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+
+	calls := map[string]int64{}
+	var histogramCount uint64
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				if m.Name != spanMetricsCallsName {
+					continue
+				}
+				for _, dp := range data.DataPoints {
+					name, _ := dp.Attributes.Value(spanNameKey)
+					status, _ := dp.Attributes.Value(statusCodeKey)
+					service, _ := dp.Attributes.Value(semconv.ServiceNameKey)
+					if service.AsString() != "checkout" {
+						t.Fatalf("unexpected service.name %q", service.AsString())
+					}
+					if _, ok := dp.Attributes.Value("user.id"); ok {
+						t.Fatalf("unexpected undeclared dimension in %v", dp.Attributes)
+					}
+					if name.AsString() == "GET /orders" {
+						route, _ := dp.Attributes.Value("http.route")
+						kind, _ := dp.Attributes.Value(spanKindKey)
+						if route.AsString() != "/orders" || kind.AsString() != "SPAN_KIND_SERVER" {
+							t.Fatalf("unexpected attributes %v", dp.Attributes)
+						}
+					}
+					calls[name.AsString()+"|"+status.AsString()] = dp.Value
+				}
+			case metricdata.Histogram[float64]:
+				if m.Name != spanMetricsLatencyName {
+					continue
+				}
+				for _, dp := range data.DataPoints {
+					if len(dp.Bounds) != 3 {
+						t.Fatalf("expected configured bounds, got %v", dp.Bounds)
+					}
+					histogramCount += dp.Count
+				}
+			}
+		}
+	}
+
+	if calls["GET /orders|STATUS_CODE_UNSET"] != 2 || calls["charge|STATUS_CODE_ERROR"] != 1 {
+		t.Fatalf("unexpected call counts %v", calls)
+	}
+	if histogramCount != 3 {
+		t.Fatalf("expected 3 latency observations, got %d", histogramCount)
+	}
+}
@@ -0,0 +1,133 @@
+package tracer
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// spanNameIDPlaceholder replaces path segments recognized as IDs by SpanNameConfig.ReplaceIDs.
+const spanNameIDPlaceholder = "{id}"
+
+var (
+	spanNameNumericID = regexp.MustCompile(`^[0-9]+$`)
+	spanNameUUID      = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	spanNameHexID     = regexp.MustCompile(`^[0-9a-fA-F]{16,}$`)
+	spanNameQuery     = regexp.MustCompile(`\?[^\s]*`)
+)
+
+// SpanNameConfig normalizes span names before they reach processors and exporters, so names built
+// from raw URLs, such as "GET /users/8812/orders", do not create a series or index entry per ID.
+// ReplaceIDs drops query strings and replaces path segments that are numbers, UUIDs, or hex strings
+// of 16 or more characters with "{id}". Rules then run in order, each replacing every match of its
+// Pattern, a regular expression, with Replacement, which may use $1-style references.
+type SpanNameConfig struct {
+	ReplaceIDs bool
+	Rules      []SpanNameRule `validate:"dive"`
+}
+
+// SpanNameRule rewrites the parts of span names matching Pattern.
+type SpanNameRule struct {
+	Pattern     string `validate:"required"`
+	Replacement string
+}
+
+func (c SpanNameConfig) enabled() bool {
+	return c.ReplaceIDs || len(c.Rules) > 0
+}
+
+// spanNamer applies a compiled SpanNameConfig.
+type spanNamer struct {
+	replaceIDs bool
+	rules      []compiledSpanNameRule
+}
+
+type compiledSpanNameRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+func newSpanNamer(cfg SpanNameConfig) (*spanNamer, error) {
+	namer := &spanNamer{replaceIDs: cfg.ReplaceIDs}
+	for _, rule := range cfg.Rules {
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("tracer: span name rule %q: %w", rule.Pattern, err)
+		}
+		namer.rules = append(namer.rules, compiledSpanNameRule{pattern: pattern, replacement: rule.Replacement})
+	}
+	return namer, nil
+}
+
+func (n *spanNamer) normalize(name string) string {
+	if n.replaceIDs {
+		name = spanNameQuery.ReplaceAllString(name, "")
+		segments := strings.Split(name, "/")
+		for idx, segment := range segments {
+			if spanNameNumericID.MatchString(segment) || spanNameUUID.MatchString(segment) || spanNameHexID.MatchString(segment) {
+				segments[idx] = spanNameIDPlaceholder
+			}
+		}
+		name = strings.Join(segments, "/")
+	}
+	for _, rule := range n.rules {
+		name = rule.pattern.ReplaceAllString(name, rule.replacement)
+	}
+	return name
+}
+
+// NewSpanNameProcessor returns a span processor that normalizes span names as they start, per cfg.
+// Register it before other processors so they see the normalized names. A name set later with
+// SetName is not seen by processors; Setup also normalizes names at export to cover those.
+func NewSpanNameProcessor(cfg SpanNameConfig) (sdktrace.SpanProcessor, error) {
+	namer, err := newSpanNamer(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return spanNameProcessor{namer: namer}, nil
+}
+
+type spanNameProcessor struct {
+	namer *spanNamer
+}
+
+func (p spanNameProcessor) OnStart(_ context.Context, span sdktrace.ReadWriteSpan) {
+	if name := span.Name(); name != "" {
+		if normalized := p.namer.normalize(name); normalized != name {
+			span.SetName(normalized)
+		}
+	}
+}
+
+func (spanNameProcessor) OnEnd(sdktrace.ReadOnlySpan) {}
+
+func (spanNameProcessor) Shutdown(context.Context) error { return nil }
+
+func (spanNameProcessor) ForceFlush(context.Context) error { return nil }
+
+// spanNameExporter normalizes the names of exported spans, catching names changed after start.
+type spanNameExporter struct {
+	sdktrace.SpanExporter
+	namer *spanNamer
+}
+
+func (e spanNameExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	renamed := make([]sdktrace.ReadOnlySpan, len(spans))
+	for idx, span := range spans {
+		renamed[idx] = span
+		if name := e.namer.normalize(span.Name()); name != span.Name() {
+			renamed[idx] = namedSpan{ReadOnlySpan: span, name: name}
+		}
+	}
+	return e.SpanExporter.ExportSpans(ctx, renamed)
+}
+
+type namedSpan struct {
+	sdktrace.ReadOnlySpan
+	name string
+}
+
+func (s namedSpan) Name() string { return s.name }
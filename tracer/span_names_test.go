@@ -0,0 +1,83 @@
+package tracer
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestSpanNameNormalize(t *testing.T) {
+	namer, err := newSpanNamer(SpanNameConfig{
+		ReplaceIDs: true,
+		Rules:      []SpanNameRule{{Pattern: `^(\w+) /api/v[0-9]+/`, Replacement: "$1 /api/"}},
+	})
+	if err != nil {
+		t.Fatalf("newSpanNamer: %v", err)
+	}
+
+	cases := map[string]string{
+		"GET /users/8812/orders?page=2":                             "GET /users/{id}/orders",
+		"DELETE /api/v2/carts/550e8400-e29b-41d4-a716-446655440000": "DELETE /api/carts/{id}",
+		"GET /blobs/9f86d081884c7d659a2feaa0c55ad015":               "GET /blobs/{id}",
+		"GET /users/me/v2":                                          "GET /users/me/v2",
+		"db.query":                                                  "db.query",
+	}
+	for name, want := range cases {
+		if got := namer.normalize(name); got != want {
+			t.Fatalf("normalize(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestSetupNormalizesSpanNames(t *testing.T) {
+	ctx := context.Background()
+	exporter := tracetest.NewInMemoryExporter()
+	provider, err := Setup(ctx, Config{
+		Enabled:    true,
+		SyncExport: true,
+		SpanNames:  SpanNameConfig{ReplaceIDs: true},
+	}, resource.Empty(), WithSpanExporter(exporter))
+	if err != nil {
+		t.Fatalf("Setup: %v", err)
+	}
+	defer func() { _ = provider.Shutdown(ctx) }()
+
+	tracer := provider.Tracer("span-names")
+	_, started := tracer.Start(ctx, "GET /users/8812")
+	if got := started.(interface{ Name() string }).Name(); got != "GET /users/{id}" {
+		t.Fatalf("expected processors to see the normalized name, got %q", got)
+	}
+	started.End()
+
+	_, renamed := tracer.Start(ctx, "HTTP GET")
+	renamed.SetName("GET /orders/42/items")
+	renamed.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 || spans[0].Name != "GET /users/{id}" || spans[1].Name != "GET /orders/{id}/items" {
+		t.Fatalf("expected normalized exported names, got %v", spans)
+	}
+
+	rejected := &shutdownSpanExporter{}
+	if _, err := Setup(ctx, Config{
+		Enabled:   true,
+		SpanNames: SpanNameConfig{Rules: []SpanNameRule{{Pattern: "("}}},
+	}, resource.Empty(), WithSpanExporter(rejected)); err == nil {
+		t.Fatal("expected an invalid span name pattern to be rejected")
+	}
+	if !rejected.shutdown {
+		t.Fatal("expected the exporter to be shut down when the config is rejected")
+	}
+}
+
+type shutdownSpanExporter struct {
+	stubSpanExporter
+	shutdown bool
+}
+
+func (e *shutdownSpanExporter) Shutdown(context.Context) error {
+	e.shutdown = true
+	return nil
+}
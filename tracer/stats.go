@@ -0,0 +1,61 @@
+package tracer
+
+import (
+	"context"
+	"sync/atomic"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Stats reports runtime counters for the tracer's export pipeline.
+type Stats struct {
+	// SpansExported is the number of spans handed to the exporters successfully.
+	SpansExported uint64
+	// SpansFailed is the number of spans whose export returned an error.
+	SpansFailed uint64
+	// ExportBatches is the number of export calls made by the span processor.
+	ExportBatches uint64
+	// FailoverPending is the number of journaled batches awaiting replay.
+	FailoverPending int
+}
+
+type exportStats struct {
+	exported atomic.Uint64
+	failed   atomic.Uint64
+	batches  atomic.Uint64
+}
+
+// countingSpanExporter records export outcomes before delegating to the wrapped exporter.
+type countingSpanExporter struct {
+	sdktrace.SpanExporter
+	stats *exportStats
+}
+
+func (e *countingSpanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	e.stats.batches.Add(1)
+	if err != nil {
+		e.stats.failed.Add(uint64(len(spans)))
+	} else {
+		e.stats.exported.Add(uint64(len(spans)))
+	}
+	return err
+}
+
+// Stats returns a snapshot of the tracer's export counters.
+// Returns zero values if provider is disabled.
+func (p *Provider) Stats() Stats {
+	var stats Stats
+	if p == nil {
+		return stats
+	}
+	if p.stats != nil {
+		stats.SpansExported = p.stats.exported.Load()
+		stats.SpansFailed = p.stats.failed.Load()
+		stats.ExportBatches = p.stats.batches.Load()
+	}
+	if p.failoverDir != "" {
+		stats.FailoverPending = countJournalEntries(p.failoverDir)
+	}
+	return stats
+}
@@ -0,0 +1,84 @@
+package tracer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+type toggleSpanExporter struct {
+	fail bool
+}
+
+func (e *toggleSpanExporter) ExportSpans(context.Context, []sdktrace.ReadOnlySpan) error {
+	if e.fail {
+		return errors.New("export failed")
+	}
+	return nil
+}
+
+func (*toggleSpanExporter) Shutdown(context.Context) error { return nil }
+
+func TestProviderStatsCountsExportOutcomes(t *testing.T) {
+	ctx := context.Background()
+	exporter := &toggleSpanExporter{}
+
+	provider, err := Setup(ctx, Config{Enabled: true}, resource.Empty(), WithSpanExporter(exporter))
+	if err != nil {
+		t.Fatalf("setup tracer: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = provider.Shutdown(ctx)
+	})
+
+	tr := provider.Tracer("stats")
+	_, span := tr.Start(ctx, "ok")
+	span.End()
+	if err := provider.ForceFlush(ctx); err != nil {
+		t.Fatalf("force flush tracer: %v", err)
+	}
+
+	exporter.fail = true
+	_, span = tr.Start(ctx, "failed")
+	span.End()
+	if err := provider.ForceFlush(ctx); err == nil {
+		t.Fatal("expected force flush to surface the export failure")
+	}
+
+	stats := provider.Stats()
+	if stats.SpansExported != 1 || stats.SpansFailed != 1 {
+		t.Fatalf("unexpected span counters: %+v", stats)
+	}
+	if stats.ExportBatches != 2 {
+		t.Fatalf("expected 2 export batches, got %d", stats.ExportBatches)
+	}
+
+	var disabled *Provider
+	if got := disabled.Stats(); got != (Stats{}) {
+		t.Fatalf("expected zero stats for nil provider, got %+v", got)
+	}
+}
+
+func TestCountJournalEntries(t *testing.T) {
+	dir := t.TempDir()
+	journal, err := newTraceFailoverJournal(FailoverConfig{Directory: dir, Buffer: 4}, nil)
+	if err != nil {
+		t.Fatalf("newTraceFailoverJournal: %v", err)
+	}
+	name, err := journal.StorePending([]byte(`{"resourceSpans":[]}`))
+	if err != nil {
+		t.Fatalf("StorePending: %v", err)
+	}
+	if got := countJournalEntries(dir); got != 0 {
+		t.Fatalf("pending batch should not be counted, got %d", got)
+	}
+	if _, err := journal.PromotePending(name); err != nil {
+		t.Fatalf("PromotePending: %v", err)
+	}
+	if got := countJournalEntries(dir); got != 1 {
+		t.Fatalf("expected 1 journaled batch, got %d", got)
+	}
+}
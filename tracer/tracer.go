@@ -5,7 +5,6 @@ import (
 	"fmt"
 
 	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"go.opentelemetry.io/otel/trace"
@@ -13,7 +12,10 @@ import (
 
 // Provider wraps the SDK tracer provider to expose a narrow API.
 type Provider struct {
-	provider *sdktrace.TracerProvider
+	provider    *sdktrace.TracerProvider
+	stats       *exportStats
+	failoverDir string
+	sampler     *remoteSampler
 }
 
 // NewProvider creates a new Provider wrapping the given SDK provider.
@@ -22,11 +24,30 @@ func NewProvider(p *sdktrace.TracerProvider) *Provider {
 	return &Provider{provider: p}
 }
 
+// Nop returns a disabled provider that records nothing and whose Tracer falls back to the
+// OpenTelemetry global. Setup returns it when tracing is disabled.
+func Nop() *Provider {
+	return &Provider{}
+}
+
 // RegisterSpanProcessor attaches the supplied span processor to the underlying provider.
+// No-op if the provider is disabled.
 func (p *Provider) RegisterSpanProcessor(processor sdktrace.SpanProcessor) {
+	if p == nil || p.provider == nil {
+		return
+	}
 	p.provider.RegisterSpanProcessor(processor)
 }
 
+// UnregisterSpanProcessor detaches a span processor previously attached with RegisterSpanProcessor.
+// No-op if the provider is disabled.
+func (p *Provider) UnregisterSpanProcessor(processor sdktrace.SpanProcessor) {
+	if p == nil || p.provider == nil {
+		return
+	}
+	p.provider.UnregisterSpanProcessor(processor)
+}
+
 // Option configures the tracer provider.
 type Option func(*config)
 
@@ -48,7 +69,7 @@ func Setup(ctx context.Context, cfg Config, res *resource.Resource, opts ...Opti
 	cfg = cfg.ApplyDefaults()
 
 	if !cfg.Enabled {
-		return nil, nil
+		return Nop(), nil
 	}
 
 	c := config{}
@@ -72,25 +93,64 @@ func Setup(ctx context.Context, cfg Config, res *resource.Resource, opts ...Opti
 
 	exporters := make([]sdktrace.SpanExporter, 0, len(c.exporters)+1)
 	if hasConfiguredExporters {
-		configuredExporter, err := newConfiguredExporter(ctx, cfg)
-		if err != nil {
-			return nil, err
+		if cfg.AsyncInit {
+			buildCtx := context.WithoutCancel(ctx)
+			exporters = append(exporters, newLazySpanExporter(func() (sdktrace.SpanExporter, error) {
+				return newConfiguredExporter(buildCtx, cfg)
+			}))
+		} else {
+			configuredExporter, err := newConfiguredExporter(ctx, cfg)
+			if err != nil {
+				return nil, err
+			}
+			exporters = append(exporters, configuredExporter)
 		}
-		exporters = append(exporters, configuredExporter)
 	}
 	exporters = append(exporters, c.exporters...)
 
-	exporter, err := combineSpanExporters(exporters)
+	combined, err := combineSpanExporters(exporters)
 	if err != nil {
 		return nil, fmt.Errorf("tracer config: %w", err)
 	}
+	var namer *spanNamer
+	if cfg.SpanNames.enabled() {
+		if namer, err = newSpanNamer(cfg.SpanNames); err != nil {
+			_ = combined.Shutdown(context.Background())
+			return nil, fmt.Errorf("tracer config: %w", err)
+		}
+		combined = spanNameExporter{SpanExporter: combined, namer: namer}
+	}
+	stats := &exportStats{}
+	exporter := &countingSpanExporter{SpanExporter: combined, stats: stats}
+
+	sampler := sdktrace.TraceIDRatioBased(cfg.SampleRatio)
+	var remote *remoteSampler
+	if cfg.RemoteSampling.URL != "" {
+		remote, err = newRemoteSampler(cfg.RemoteSampling, cfg.ServiceName, sampler)
+		if err != nil {
+			_ = combined.Shutdown(context.Background())
+			return nil, fmt.Errorf("tracer config: %w", err)
+		}
+		sampler = remote
+	}
 
 	options := []sdktrace.TracerProviderOption{
-		sdktrace.WithSampler(sdktrace.TraceIDRatioBased(cfg.SampleRatio)),
+		sdktrace.WithSampler(sampler),
 		sdktrace.WithResource(res),
+		sdktrace.WithSpanLimits(cfg.Limits.spanLimits()),
+	}
+	idGenerator := cfg.IDGenerator
+	if idGenerator == nil && cfg.Interop == InteropXRay {
+		idGenerator = NewXRayIDGenerator()
+	}
+	if idGenerator != nil {
+		options = append(options, sdktrace.WithIDGenerator(idGenerator))
 	}
 
-	if !cfg.Async {
+	if namer != nil {
+		options = append(options, sdktrace.WithSpanProcessor(spanNameProcessor{namer: namer}))
+	}
+	if !cfg.Async || cfg.SyncExport {
 		options = append(options, sdktrace.WithSpanProcessor(sdktrace.NewSimpleSpanProcessor(exporter)))
 	} else {
 		options = append(options, sdktrace.WithBatcher(exporter))
@@ -99,14 +159,25 @@ func Setup(ctx context.Context, cfg Config, res *resource.Resource, opts ...Opti
 	tp := sdktrace.NewTracerProvider(options...)
 
 	otel.SetTracerProvider(tp)
-	otel.SetTextMapPropagator(
-		propagation.NewCompositeTextMapPropagator(
-			propagation.TraceContext{},
-			propagation.Baggage{},
-		),
-	)
-
-	return &Provider{provider: tp}, nil
+	otel.SetTextMapPropagator(interopPropagator(cfg.Interop))
+
+	provider := &Provider{provider: tp, stats: stats, sampler: remote}
+	if remote != nil {
+		remote.start(cfg.RemoteSampling.PollInterval)
+	}
+	if cfg.Export.Backend.Enabled && cfg.Export.Backend.Failover.Enabled {
+		provider.failoverDir = cfg.Export.Backend.Failover.Directory
+	}
+	return provider, nil
+}
+
+// Tracer produces a tracer backed by this provider.
+// Falls back to the OpenTelemetry global tracer if provider is disabled.
+func (p *Provider) Tracer(name string, opts ...trace.TracerOption) trace.Tracer {
+	if p == nil || p.provider == nil {
+		return otel.Tracer(name, opts...)
+	}
+	return p.provider.Tracer(name, opts...)
 }
 
 // SpanContext extracts the span context from the provided request context.
@@ -120,6 +191,9 @@ func (p *Provider) Shutdown(ctx context.Context) error {
 	if p.provider == nil {
 		return nil
 	}
+	if p.sampler != nil {
+		p.sampler.close()
+	}
 	return p.provider.Shutdown(ctx)
 }
 
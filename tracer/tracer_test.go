@@ -20,8 +20,18 @@ func TestSetupDisabledTracer(t *testing.T) {
 	if err != nil {
 		t.Fatalf("setup disabled tracer: %v", err)
 	}
-	if provider != nil {
-		t.Fatalf("expected nil provider when tracer is disabled, got %#v", provider)
+	if provider == nil {
+		t.Fatal("expected no-op provider when tracer is disabled, got nil")
+	}
+
+	provider.RegisterSpanProcessor(sdktrace.NewSimpleSpanProcessor(nil))
+	_, span := provider.Tracer("disabled").Start(ctx, "op")
+	span.End()
+	if err := provider.ForceFlush(ctx); err != nil {
+		t.Fatalf("force flush disabled tracer: %v", err)
+	}
+	if err := provider.Shutdown(ctx); err != nil {
+		t.Fatalf("shutdown disabled tracer: %v", err)
 	}
 }
 
@@ -195,6 +205,29 @@ func (*stubSpanExporter) ExportSpans(context.Context, []sdktrace.ReadOnlySpan) e
 
 func (*stubSpanExporter) Shutdown(context.Context) error { return nil }
 
+func TestSyncExportOverridesAsync(t *testing.T) {
+	ctx := context.Background()
+	exporter := &recordingSpanExporter{}
+	provider, err := Setup(ctx, Config{
+		Enabled:     true,
+		ServiceName: "sync-export",
+		SyncExport:  true,
+	}, resource.Empty(), WithSpanExporter(exporter))
+	if err != nil {
+		t.Fatalf("setup tracer: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = provider.Shutdown(ctx)
+	})
+
+	_, span := provider.provider.Tracer("sync-export").Start(ctx, "ended")
+	span.End()
+
+	if len(exporter.spans) != 1 {
+		t.Fatalf("expected span exported on end, got %d", len(exporter.spans))
+	}
+}
+
 type recordingSpanExporter struct {
 	spans []sdktrace.ReadOnlySpan
 }
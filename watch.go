@@ -0,0 +1,94 @@
+package goo11y
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const defaultWatchInterval = 2 * time.Second
+
+// ConfigLoader reads the complete configuration from the file at path.
+type ConfigLoader func(path string) (Config, error)
+
+// WatchConfig polls the file at path and calls Reload with the configuration returned by load
+// whenever the file content changes. Load and reload failures are logged and leave the running
+// configuration in place; the same content is not retried until the file changes again. Polling
+// stops when ctx is done or the returned function is called. An interval of zero or less polls
+// every two seconds.
+func (t *Telemetry) WatchConfig(ctx context.Context, path string, load ConfigLoader, interval time.Duration) (func(), error) {
+	if t == nil {
+		return nil, errors.New("goo11y: watch config on nil telemetry")
+	}
+	if load == nil {
+		return nil, errors.New("goo11y: watch config requires a loader")
+	}
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	digest, err := fileDigest(path)
+	if err != nil {
+		return nil, fmt.Errorf("watch config: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var lastErr string
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			current, err := fileDigest(path)
+			if err != nil {
+				if err.Error() != lastErr {
+					lastErr = err.Error()
+					t.emitWarn(ctx, "watch config", err)
+				}
+				continue
+			}
+			lastErr = ""
+			if current == digest {
+				continue
+			}
+			digest = current
+
+			cfg, err := load(path)
+			if err != nil {
+				t.emitWarn(ctx, "load config", err)
+				continue
+			}
+			if err := t.Reload(ctx, cfg); err != nil {
+				t.emitWarn(ctx, "reload config", err)
+			}
+		}
+	}()
+
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			cancel()
+			<-done
+		})
+	}
+	return stop, nil
+}
+
+func fileDigest(path string) ([sha256.Size]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	return sha256.Sum256(data), nil
+}
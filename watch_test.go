@@ -0,0 +1,165 @@
+package goo11y
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mfahmialkautsar/goo11y/logger"
+)
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestWatchConfigReloadsOnChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "goo11y.conf")
+	if err := os.WriteFile(path, []byte("info"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+
+	out := &syncBuffer{}
+	load := func(path string) (Config, error) {
+		level, err := os.ReadFile(path)
+		if err != nil {
+			return Config{}, err
+		}
+		return Config{Logger: logger.Config{
+			Enabled: true,
+			Console: false,
+			Level:   strings.TrimSpace(string(level)),
+			Writers: []io.Writer{out},
+		}}, nil
+	}
+	cfg, _ := load(path)
+	tele, err := New(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = tele.Shutdown(context.Background()) })
+	initial := tele.Logger()
+
+	stop, err := tele.WatchConfig(context.Background(), path, load, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("WatchConfig: %v", err)
+	}
+	defer stop()
+
+	if err := os.WriteFile(path, []byte("debug"), 0o600); err != nil {
+		t.Fatalf("rewrite config: %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		tele.reloadMu.Lock()
+		level := tele.cfg.Logger.Level
+		tele.reloadMu.Unlock()
+		if level == "debug" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("config change was not reloaded")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	stop()
+
+	if tele.Logger() == initial {
+		t.Fatal("expected logger to be replaced")
+	}
+}
+
+func TestWatchConfigRequiresReadableFile(t *testing.T) {
+	tele := &Telemetry{}
+	load := func(string) (Config, error) { return Config{}, errors.New("unused") }
+
+	if _, err := tele.WatchConfig(context.Background(), filepath.Join(t.TempDir(), "missing"), load, 0); err == nil {
+		t.Fatal("expected error for missing file")
+	}
+	if _, err := tele.WatchConfig(context.Background(), "", nil, 0); err == nil {
+		t.Fatal("expected error for nil loader")
+	}
+}
+
+func TestWatchConfigWarnsWhileReloading(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "goo11y.conf")
+	if err := os.WriteFile(path, []byte("0"), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	out := &syncBuffer{}
+	cfg := func(level string) Config {
+		return Config{Logger: logger.Config{Enabled: true, Console: false, Level: level, Writers: []io.Writer{out}}}
+	}
+	tele, err := New(context.Background(), cfg("info"))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	t.Cleanup(func() { _ = tele.Shutdown(context.Background()) })
+
+	load := func(string) (Config, error) { return Config{}, errors.New("bad config") }
+	stop, err := tele.WatchConfig(context.Background(), path, load, time.Millisecond)
+	if err != nil {
+		t.Fatalf("WatchConfig: %v", err)
+	}
+	defer stop()
+
+	done := make(chan struct{})
+	var logging sync.WaitGroup
+	logging.Add(1)
+	go func() {
+		defer logging.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			tele.Logger().Warn().Msg("serving")
+			_ = tele.Tracer()
+			_ = tele.Meter()
+			time.Sleep(100 * time.Microsecond)
+		}
+	}()
+
+	for idx := range 20 {
+		if err := os.WriteFile(path, []byte{byte('1' + idx%9)}, 0o600); err != nil {
+			t.Fatalf("rewrite config: %v", err)
+		}
+		next := cfg("info")
+		if idx%2 == 0 {
+			// The ECS profile renames reserved fields, which must not touch the loggers still in use.
+			next = cfg("warn")
+			next.Logger.Profile = logger.ProfileECS
+		}
+		if err := tele.Reload(context.Background(), next); err != nil {
+			t.Fatalf("Reload: %v", err)
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+	close(done)
+	logging.Wait()
+	stop()
+
+	if !strings.Contains(out.String(), "bad config") {
+		t.Fatalf("expected load failures to be logged, got %s", out.String())
+	}
+}